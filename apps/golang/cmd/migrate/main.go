@@ -3,17 +3,38 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"agrinovagraphql/server/pkg/config"
 	"agrinovagraphql/server/pkg/database"
+	"agrinovagraphql/server/pkg/database/migrator"
 )
 
+const usage = `Usage: go run ./cmd/migrate [flags] <command> [N|VERSION]
+
+Commands:
+  status              List migrations with applied state and checksum drift
+  up [N]              Apply N pending migrations (all when N is omitted)
+  down [N]            Roll back the last N applied migrations (default 1)
+  redo                Roll back the last applied migration and apply it again
+  verify              Exit non-zero when applied migrations drifted or are unknown
+  baseline [VERSION]  Mark pending migrations up to VERSION (all when omitted)
+                      as applied without running them
+
+Flags:
+`
+
 func main() {
-	dryRun := flag.Bool("dry-run", false, "Show what would be migrated without actually running migrations")
+	includeManual := flag.Bool("manual", false, "Include manual migrations in `up` and `baseline`")
 	verbose := flag.Bool("verbose", false, "Enable verbose logging")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
@@ -21,6 +42,22 @@ func main() {
 		log.Println("Verbose mode enabled")
 	}
 
+	command := "up"
+	if flag.NArg() > 0 {
+		command = strings.ToLower(flag.Arg(0))
+	}
+	count := 0
+	through := ""
+	if command == "baseline" && flag.NArg() > 1 {
+		through = flag.Arg(1)
+	} else if flag.NArg() > 1 {
+		value, err := strconv.Atoi(flag.Arg(1))
+		if err != nil || value < 0 {
+			log.Fatalf("Invalid migration count %q", flag.Arg(1))
+		}
+		count = value
+	}
+
 	log.Println("Agrinova Database Migration Tool")
 	log.Println("====================================")
 
@@ -47,48 +84,97 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
-	defer func() {
-		log.Println("Migration completed")
-	}()
-
 	log.Println("Database connection established")
 
-	if *dryRun {
-		log.Println("DRY RUN MODE - No changes will be made")
-		log.Println("The following migrations would be executed:")
-		log.Println("   1. Core/GORM migrations (base schema + indexes + constraints)")
-		log.Println("   2. 000023_add_block_tarif_metadata")
-		log.Println("   3. 000038_enforce_unique_harvest_worker_scope")
-		log.Println("   4. 000024_cutover_legacy_schema (only if legacy master columns exist)")
-		log.Println("   5. 000026_finalize_drop_legacy_schema (includes 000025 readiness validation)")
-		log.Println("   6. 000027_finalize_drop_legacy_user_schema")
-		log.Println("")
-		log.Println("Run without --dry-run flag to apply migrations")
-		return
-	}
-
-	log.Println("Running database initialization and migrations...")
-	log.Println("")
-
-	if err := dbService.Initialize(context.Background()); err != nil {
-		log.Fatalf("Migration failed: %v", err)
-	}
-
-	log.Println("")
-	log.Println("All migrations completed successfully")
-	log.Println("")
-
-	log.Println("Running post-migration validation...")
 	ctx := context.Background()
-
-	if err := dbService.Health(ctx); err != nil {
-		log.Printf("Warning: Post-migration health check failed: %v", err)
-		log.Println("The migrations were applied, but there may be issues with the database.")
-		os.Exit(1)
+	runner := database.NewMigrationRunner(database.GetDB())
+
+	switch command {
+	case "status":
+		statuses, err := runner.Status(ctx)
+		if err != nil {
+			log.Fatalf("Failed to load migration status: %v", err)
+		}
+		printStatus(statuses)
+
+	case "up":
+		applied, err := runner.Up(ctx, count, *includeManual)
+		if err != nil {
+			log.Fatalf("Migration failed after applying %d migration(s): %v", len(applied), err)
+		}
+		log.Printf("Applied %d migration(s)", len(applied))
+		if count == 0 {
+			log.Println("Running post-migration maintenance...")
+			dbService.RunMaintenance(ctx)
+			if err := dbService.Health(ctx); err != nil {
+				log.Printf("Warning: Post-migration health check failed: %v", err)
+				os.Exit(1)
+			}
+		}
+
+	case "down":
+		rolledBack, err := runner.Down(ctx, count)
+		if err != nil {
+			log.Fatalf("Rollback failed after rolling back %d migration(s): %v", len(rolledBack), err)
+		}
+		log.Printf("Rolled back %d migration(s): %s", len(rolledBack), strings.Join(rolledBack, ", "))
+
+	case "redo":
+		version, err := runner.Redo(ctx)
+		if err != nil {
+			log.Fatalf("Redo failed: %v", err)
+		}
+		log.Printf("Redid migration %s", version)
+
+	case "verify":
+		problems, err := runner.Verify(ctx)
+		if err != nil {
+			log.Fatalf("Verification failed: %v", err)
+		}
+		if len(problems) > 0 {
+			printStatus(problems)
+			log.Fatalf("%d migration(s) drifted or are unknown to this build", len(problems))
+		}
+		log.Println("All applied migrations match their sources")
+
+	case "baseline":
+		marked, err := runner.Baseline(ctx, through, *includeManual)
+		if err != nil {
+			log.Fatalf("Baseline failed after marking %d migration(s): %v", len(marked), err)
+		}
+		log.Printf("Marked %d migration(s) as applied", len(marked))
+
+	default:
+		flag.Usage()
+		os.Exit(2)
 	}
+}
 
-	log.Println("Post-migration validation passed")
-	log.Println("")
-	log.Println("Database is ready for use")
-	log.Println("You can now start the server with: go run cmd/server/main.go")
+func printStatus(statuses []migrator.Status) {
+	fmt.Printf("%-8s %-60s %-10s %-25s %s\n", "VERSION", "NAME", "STATE", "APPLIED AT", "NOTES")
+	for _, status := range statuses {
+		state := "pending"
+		if status.Applied {
+			state = "applied"
+		} else if status.Manual {
+			state = "manual"
+		}
+
+		appliedAt := "-"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05Z07:00")
+		}
+
+		notes := []string{}
+		if status.Drifted {
+			notes = append(notes, "checksum drift")
+		}
+		if status.Unknown {
+			notes = append(notes, "not in registry")
+		}
+		if !status.Reversible && !status.Unknown {
+			notes = append(notes, "irreversible")
+		}
+		fmt.Printf("%-8s %-60s %-10s %-25s %s\n", status.Version, status.Name, state, appliedAt, strings.Join(notes, ", "))
+	}
 }
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"agrinovagraphql/server/pkg/config"
+	"agrinovagraphql/server/pkg/storage"
+)
+
+// Copies files from the local uploads directory into the store configured
+// by STORAGE_PROVIDER (usually S3), so existing photos and documents keep
+// resolving after the switch. Re-running skips objects already copied.
+func main() {
+	source := flag.String("source", "", "Local uploads directory to copy from (defaults to the configured uploads dir)")
+	prefix := flag.String("prefix", "", "Only copy keys starting with this prefix, e.g. satpam_photos/")
+	dryRun := flag.Bool("dry-run", false, "List the files that would be copied without copying them")
+	deleteSource := flag.Bool("delete-source", false, "Delete each local file after it was copied")
+	flag.Parse()
+
+	log.Println("📦 Upload Storage Migration Tool")
+	log.Println("================================")
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("❌ Failed to load configuration: %v", err)
+	}
+
+	sourceDir := strings.TrimSpace(*source)
+	if sourceDir == "" {
+		sourceDir = cfg.UploadsDir
+	}
+	sourceStore, err := storage.NewLocalStore(sourceDir)
+	if err != nil {
+		log.Fatalf("❌ Failed to open source directory: %v", err)
+	}
+
+	targetStore, err := storage.NewStoreFromEnv(cfg.UploadsDir)
+	if err != nil {
+		log.Fatalf("❌ Failed to open target storage: %v", err)
+	}
+	if local, ok := targetStore.(*storage.LocalStore); ok && filepath.Clean(local.Root()) == filepath.Clean(sourceStore.Root()) {
+		log.Fatalf("❌ Target storage is the source directory; set STORAGE_PROVIDER=s3 and the S3_* variables first")
+	}
+	log.Printf("Copying %s → %T", sourceStore.Root(), targetStore)
+
+	ctx := context.Background()
+	copied, skipped, failed := 0, 0, 0
+	if *dryRun {
+		err = sourceStore.List(ctx, *prefix, func(info storage.ObjectInfo) error {
+			log.Printf("would copy %s (%d bytes)", info.Key, info.Size)
+			copied++
+			return nil
+		})
+	} else {
+		err = storage.Copy(ctx, sourceStore, targetStore, *prefix, func(info storage.ObjectInfo, didCopy bool, copyErr error) error {
+			switch {
+			case copyErr != nil:
+				failed++
+				log.Printf("⚠️  %s: %v", info.Key, copyErr)
+				return nil
+			case didCopy:
+				copied++
+			default:
+				skipped++
+			}
+			if *deleteSource {
+				if err := sourceStore.Delete(ctx, info.Key); err != nil {
+					log.Printf("⚠️  failed to delete local %s: %v", info.Key, err)
+				}
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		log.Fatalf("❌ Migration stopped: %v", err)
+	}
+
+	if *dryRun {
+		log.Printf("✅ Dry run: %d file(s) would be copied", copied)
+		return
+	}
+	log.Printf("✅ Copied %d, already present %d, failed %d", copied, skipped, failed)
+	if failed > 0 {
+		log.Fatalf("❌ %d file(s) failed to copy; re-run to retry", failed)
+	}
+}
@@ -2,8 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"io"
+	"mime"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -13,22 +14,31 @@ import (
 
 	// GraphQL and service imports
 	authModule "agrinovagraphql/server/internal/auth"
-	sharedAuthInfra "agrinovagraphql/server/internal/auth/features/shared/infrastructure/postgres"
 	authServices "agrinovagraphql/server/internal/auth/services"
 
 	// Clean architecture module
 	authMiddlewarePkg "agrinovagraphql/server/internal/auth/middleware"
+	"agrinovagraphql/server/internal/cache"
+	"agrinovagraphql/server/internal/graphql/dataloader"
 	"agrinovagraphql/server/internal/graphql/directives"
 	"agrinovagraphql/server/internal/graphql/generated"
+	"agrinovagraphql/server/internal/graphql/querylimit"
+	"agrinovagraphql/server/internal/graphql/telemetry"
 	"agrinovagraphql/server/internal/middleware"
 	rbacServices "agrinovagraphql/server/internal/rbac/services"
 
 	// Shared and pkg imports
 	"agrinovagraphql/server/internal/graphql/resolvers"
+	"agrinovagraphql/server/internal/photoupload"
+	"agrinovagraphql/server/internal/pubsub"
+	"agrinovagraphql/server/internal/ratelimit"
 	"agrinovagraphql/server/internal/routes"
+	"agrinovagraphql/server/internal/theme"
 	"agrinovagraphql/server/pkg/config"
 	"agrinovagraphql/server/pkg/database"
 	"agrinovagraphql/server/pkg/logger"
+	"agrinovagraphql/server/pkg/storage"
+	"agrinovagraphql/server/pkg/tracing"
 
 	syncServices "agrinovagraphql/server/internal/sync/services"
 
@@ -65,11 +75,34 @@ func main() {
 	if err != nil {
 		log.Fatal("Failed to load configuration: %v", err)
 	}
+
+	// Tracing is opt-in; trace context from callers is always propagated
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Enabled:        cfg.Observability.TracingEnabled,
+		Endpoint:       cfg.Observability.TracingEndpoint,
+		SampleRatio:    cfg.Observability.TracingSampleRatio,
+		ServiceName:    cfg.Observability.ServiceName,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		log.Fatal("Failed to initialize tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
 	uploadsDir, err := resolveUploadsDir(cfg.UploadsDir)
 	if err != nil {
 		log.Fatal("Failed to initialize uploads directory: %v", err)
 	}
-	if copied, copyErr := seedBundledThemeAssets(uploadsDir); copyErr != nil {
+	uploadStore, err := storage.NewStoreFromEnv(uploadsDir)
+	if err != nil {
+		log.Fatal("Failed to initialize upload storage: %v", err)
+	}
+	uploadURLSecret := strings.TrimSpace(os.Getenv("STORAGE_URL_SECRET"))
+	if uploadURLSecret == "" {
+		uploadURLSecret = cfg.Auth.JWTAccessSecret
+	}
+	uploadURLSigner := storage.NewURLSigner(uploadURLSecret)
+	if copied, copyErr := seedBundledThemeAssets(context.Background(), uploadStore); copyErr != nil {
 		log.Warn("Theme asset seed skipped: %v", copyErr)
 	} else if copied > 0 {
 		log.Info("Seeded %d bundled theme asset(s) into uploads directory", copied)
@@ -92,11 +125,32 @@ func main() {
 		log.Fatal("Failed to connect to database: %v", err)
 	}
 
-	// Service startup must not run migrations.
-	// Execute migrations explicitly via the dedicated migration entrypoint:
-	//   go run ./cmd/migrate/main.go
+	// Service startup refuses to run against a schema with pending migrations.
+	// Apply them via the dedicated migration entrypoint:
+	//   go run ./cmd/migrate up
+	// or set AGRINOVA_AUTO_MIGRATE=true to apply them here.
+	migrationRunner := database.NewMigrationRunner(database.GetDB())
+	pendingMigrations, err := migrationRunner.Pending(context.Background())
+	if err != nil {
+		log.Fatal("Failed to check pending migrations: %v", err)
+	}
+	if len(pendingMigrations) > 0 {
+		if !envFlagEnabled("AGRINOVA_AUTO_MIGRATE") {
+			log.Fatal("%d pending migration(s), starting with %s. Run `go run ./cmd/migrate up` or set AGRINOVA_AUTO_MIGRATE=true", len(pendingMigrations), pendingMigrations[0].Version)
+		}
+		applied, err := migrationRunner.Up(context.Background(), 0, false)
+		if err != nil {
+			log.Fatal("Auto-migrate failed after applying %d migration(s): %v", len(applied), err)
+		}
+		log.Info("Auto-migrate applied %d migration(s)", len(applied))
+	}
 	if err := dbService.Health(context.Background()); err != nil {
-		log.Fatal("Database health check failed. Run migrations first with `go run ./cmd/migrate/main.go`: %v", err)
+		log.Fatal("Database health check failed. Run migrations first with `go run ./cmd/migrate up`: %v", err)
+	}
+	if cfg.Observability.TracingEnabled {
+		if err := database.GetDB().Use(tracing.GormPlugin{}); err != nil {
+			log.Fatal("Failed to install SQL tracing: %v", err)
+		}
 	}
 
 	// GraphQL schema will be loaded automatically by gqlgen
@@ -110,15 +164,60 @@ func main() {
 		log.Fatal("Failed to initialize auth module: %v", err)
 	}
 
-	// Start periodic cleanup for expired/inactive web sessions.
-	sessionRepo := sharedAuthInfra.NewSessionRepository(database.GetDB())
-	startSessionCleanupWorker(context.Background(), log, sessionRepo)
+	// Real-time events fan out through one bus so subscribers on every
+	// instance see them; the postgres driver relays via LISTEN/NOTIFY.
+	eventBus, err := pubsub.New(cfg.PubSub.Driver, database.GetDB(), pubsub.PostgresConfig{
+		DSN:     dbConfig.DSN(),
+		Channel: cfg.PubSub.Channel,
+	})
+	if err != nil {
+		log.Fatal("Failed to initialize pub/sub: %v", err)
+	}
+	defer eventBus.Close()
+	resolvers.UseSubscriptionPubSub(eventBus)
+	log.Info("📡 Real-time pub/sub driver: %s", cfg.PubSub.Driver)
+
+	// Caches and rate-limit counters; the postgres driver shares them
+	// between instances so limits survive restarts and do not multiply.
+	sharedCache, err := cache.NewStore(cfg.SharedState.Driver, database.GetDB(), cache.MemoryConfig{
+		MaxEntries:      10000,
+		CleanupInterval: cfg.SharedState.GCInterval,
+	})
+	if err != nil {
+		log.Fatal("Failed to initialize shared cache: %v", err)
+	}
+	defer sharedCache.Close()
+	rateLimitCounter, err := ratelimit.New(cfg.SharedState.Driver, database.GetDB(), cfg.SharedState.GCInterval)
+	if err != nil {
+		log.Fatal("Failed to initialize rate limit counters: %v", err)
+	}
+	defer rateLimitCounter.Close()
+	middleware.SetRoleRateLimitStore(middleware.NewSharedRateLimitStore(middleware.DefaultRateLimitConfig(), rateLimitCounter))
+	services.auth.rateLimit.SetRateLimitCounter(rateLimitCounter)
+	log.Info("🗄️ Shared state driver: %s", cfg.SharedState.Driver)
+
+	// Reports, analytics and history lists read from replicas when any are
+	// configured; lagging or unhealthy replicas fall back to the primary.
+	var readRouter *database.ReadRouter
+	if len(cfg.Database.Replicas) > 0 {
+		replicaManager, err := startReadReplicas(cfg)
+		if err != nil {
+			log.Fatal("Failed to initialize read replicas: %v", err)
+		}
+		defer replicaManager.Close()
+		readRouter, err = database.NewReadRouter(database.GetDB(), replicaManager, middleware.RLSSessionSettings)
+		if err != nil {
+			log.Fatal("Failed to initialize read router: %v", err)
+		}
+		log.Info("📚 Read replicas: %d (max lag %s)", len(cfg.Database.Replicas), cfg.Database.ReplicaMaxLag)
+	}
 
 	// Initialize WebSocket services
 	connectionManager := websocketServices.NewConnectionManager()
 	wsHandler := websocketServices.NewWebSocketHandler(connectionManager, authModuleV2.TokenService, services.auth.user)
+	wsHandler.SetRateLimiter(websocketServices.NewSharedWebSocketRateLimiter(websocketServices.DefaultWebSocketRateLimitConfig(), rateLimitCounter))
 	subscriptionResolver := websocketResolvers.NewSubscriptionResolver(wsHandler)
-	eventBroadcaster := websocketServices.NewEventBroadcaster(database.GetDB(), wsHandler)
+	eventBroadcaster := websocketServices.NewEventBroadcaster(database.GetDB(), wsHandler, eventBus)
 
 	// Initialize FCM services for push notifications
 	hierarchyService := authServices.NewHierarchyService(database.GetDB())
@@ -143,6 +242,7 @@ func main() {
 		router.Use(gin.Logger())
 	}
 	router.Use(gin.Recovery())
+	router.Use(middleware.HTTPTelemetry())
 	router.Use(corsMiddleware(cfg.CORS.AllowedOrigins))
 	router.Use(securityHeadersMiddleware())
 
@@ -155,6 +255,11 @@ func main() {
 	// csrfMiddleware := middleware.NewCSRFMiddleware(csrfConfig)
 	// router.Use(csrfMiddleware.GraphQLMiddleware())
 
+	// Prometheus metrics endpoint
+	if cfg.Observability.MetricsEnabled {
+		router.GET(cfg.Observability.MetricsPath, middleware.MetricsHandler(cfg.Observability.MetricsToken))
+	}
+
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -175,9 +280,13 @@ func main() {
 		services.auth.rateLimit,
 		services.auth.securityLogging,
 		cfg.Auth.JWTAccessSecret, // JWT secret for gate check QR token generation
-		uploadsDir,
+		uploadStore,
+		uploadURLSigner,
+		rateLimitCounter,
 	)
 
+	resolver.ReadRouter = readRouter
+
 	// Add WebSocket subscription resolver to main resolver
 	resolver.WebSocketSubscriptionResolver = subscriptionResolver
 
@@ -187,6 +296,30 @@ func main() {
 	// Add FCM notification service to resolver (for harvest notifications)
 	resolver.FCMNotificationService = fcmNotificationService
 	resolver.HierarchyService = hierarchyService
+	// Vehicle tax reminders always go out in-app; push is added when FCM is configured.
+	resolver.VehicleTaxReminderService = notifServices.NewVehicleTaxReminderService(database.GetDB(), resolver.NotificationService, fcmProvider, hierarchyService)
+	if fcmProvider != nil {
+		resolver.ManagerNotificationService = notifServices.NewManagerNotificationService(database.GetDB(), fcmProvider, hierarchyService)
+		resolver.NotificationRoutingService = notifServices.NewNotificationRoutingService(database.GetDB(), resolver.NotificationService, fcmProvider, hierarchyService)
+		resolver.NotificationService.SetRoutingService(resolver.NotificationRoutingService)
+	}
+
+	// One theme service backs both theme route groups so they share the
+	// runtime cache; invalidations are pushed to themeRuntimeChanged.
+	themeService := theme.NewService(database.GetDB())
+	photoUploadService := photoupload.NewService(database.GetDB(), uploadStore)
+	resolver.PhotoUploadService = photoUploadService
+	themeService.OnRuntimeChange(resolver.PublishThemeRuntimeChange)
+	resolvers.ShareThemeRuntimeCache(themeService.DropRuntimeCache)
+	go themeService.WatchCampaignBoundaries(context.Background())
+
+	// Start the cron scheduler once optional services are wired. Every instance
+	// polls, but only the advisory-lock holder runs jobs.
+	if envFlagEnabled("AGRINOVA_SCHEDULER_DISABLED") {
+		log.Info("Scheduler disabled via AGRINOVA_SCHEDULER_DISABLED")
+	} else {
+		resolver.StartScheduler(context.Background())
+	}
 
 	// Initialize WebAuth middleware for GraphQL context
 	webAuthMiddleware := middleware.NewWebAuthMiddleware(
@@ -207,6 +340,11 @@ func main() {
 
 	log.Info("✅ RLS Context Middleware initialized - PostgreSQL Row-Level Security ACTIVE")
 
+	// Request-scoped dataloaders batch relation lookups (mandor, block, division, ...)
+	// per GraphQL request. They run inside the RLS middleware so batches are
+	// scoped to the caller's companies.
+	dataloaderMiddleware := dataloader.Middleware(database.GetDB())
+
 	// Initialize authentication directives
 	authDirectives := directives.NewAuthDirectives()
 
@@ -216,11 +354,52 @@ func main() {
 		Directives: authDirectives,
 	}))
 
-	// Enable introspection for development (playground)
-	srv.Use(extension.Introspection{})
+	// Operation and resolver metrics and spans; added first so the operation
+	// span is the parent of every resolver span
+	srv.Use(&telemetry.Tracer{})
+
+	// Introspection (and the playground that depends on it) is config-gated;
+	// disable it in production with AGRINOVA_GRAPHQL_INTROSPECTION_ENABLED=false.
+	if cfg.GraphQL.IntrospectionEnabled {
+		srv.Use(extension.Introspection{})
+	}
+
+	// Reject operations that are too deep or exceed the caller's role budget
+	roleQueryBudgets, err := querylimit.ParseRoleBudgets(cfg.GraphQL.RoleMaxQueryCost)
+	if err != nil {
+		log.Fatal("Invalid graphql.role_max_query_cost: %v", err)
+	}
+	srv.Use(&querylimit.Limits{
+		MaxDepth:       cfg.GraphQL.MaxQueryDepth,
+		DefaultMaxCost: cfg.GraphQL.MaxQueryCost,
+		RoleMaxCost:    roleQueryBudgets,
+	})
+
+	// Production mobile builds may only send operations from their manifest.
+	// The allow-list runs before APQ so hash-only requests resolve from it.
+	if mode := cfg.GraphQL.AllowlistMode; mode != "" && mode != querylimit.AllowListOff {
+		allowList, err := querylimit.LoadAllowList(mode, cfg.GraphQL.AllowlistManifest)
+		if err != nil {
+			log.Fatal("Failed to load persisted query allow-list: %v", err)
+		}
+		srv.Use(allowList)
+		log.Info("🔒 Persisted query allow-list active (mode: %s)", mode)
+	}
+
+	// Automatic persisted queries: clients send a SHA-256 instead of the full query
+	if cfg.GraphQL.PersistedQueriesEnabled {
+		srv.Use(extension.AutomaticPersistedQuery{
+			Cache: querylimit.NewPersistedQueryCache(sharedCache, cfg.GraphQL.PersistedQueryTTL),
+		})
+	}
+
+	// Suspended or trial-expired tenants are read-only
+	srv.AroundOperations(middleware.TenantReadOnlyOperationMiddleware(resolver.TenantPlanService))
 
 	// Add WebSocket transport for GraphQL subscriptions
 	srv.AddTransport(transport.Websocket{
+		InitFunc:  telemetry.WebsocketInit,
+		CloseFunc: telemetry.WebsocketClose,
 		Upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				// In production, implement proper origin checking
@@ -251,24 +430,29 @@ func main() {
 		rbacService,
 	)
 
+	// Enforce per-company IP allow-lists (company settings) for both token and cookie auth
+	authMiddleware.SetNetworkPolicy(resolver.CompanySettingsService)
+	webAuthMiddleware.SetNetworkPolicy(resolver.CompanySettingsService)
+
 	// GraphQL endpoints with authentication and HTTP context middleware
 	// Security layers (executed in order):
 	// 1. authMiddleware.GraphQLAuth() - Validates JWT token (Bearer) and adds user to context
 	// 2. webAuthMiddleware.WebSessionMiddleware() - Validates web session and adds user to context
 	// 3. webAuthMiddleware.GraphQLContextMiddleware() - Adds HTTP context
 	// 4. rlsMiddleware.Middleware(srv) - Sets PostgreSQL RLS context + user assignments
-	// 5. srv - GraphQL handler with RLS-enforced database queries
+	// 5. dataloaderMiddleware - Installs request-scoped, RLS-aware dataloaders
+	// 6. srv - GraphQL handler with RLS-enforced database queries
 	router.POST(cfg.Server.GraphQLEndpoint,
 		authMiddleware.GraphQLAuth(),
 		webAuthMiddleware.WebSessionMiddleware(),
 		webAuthMiddleware.GraphQLContextMiddleware(),
-		gin.WrapH(rlsMiddleware.Middleware(srv)),
+		gin.WrapH(rlsMiddleware.Middleware(dataloaderMiddleware(srv))),
 	)
 	router.GET(cfg.Server.GraphQLEndpoint,
 		authMiddleware.GraphQLAuth(),
 		webAuthMiddleware.WebSessionMiddleware(),
 		webAuthMiddleware.GraphQLContextMiddleware(),
-		gin.WrapH(rlsMiddleware.Middleware(srv)),
+		gin.WrapH(rlsMiddleware.Middleware(dataloaderMiddleware(srv))),
 	)
 
 	// WebSocket endpoint for direct WebSocket connections (non-GraphQL)
@@ -290,10 +474,57 @@ func main() {
 		resolver.HandleProfileAvatarUpload,
 	)
 
-	// Static file serving for uploads with tighter headers.
+	// Bulk user and assignment import (multipart form-data, CSV/XLSX)
+	router.POST("/users/import",
+		authMiddleware.GraphQLAuth(),
+		webAuthMiddleware.WebSessionMiddleware(),
+		webAuthMiddleware.GraphQLContextMiddleware(),
+		resolver.HandleUserImport,
+	)
+
+	// Master data import/export (estate hierarchy and employees, CSV/XLSX)
+	router.POST("/master-data/import",
+		authMiddleware.GraphQLAuth(),
+		webAuthMiddleware.WebSessionMiddleware(),
+		webAuthMiddleware.GraphQLContextMiddleware(),
+		resolver.HandleMasterDataImport,
+	)
+	router.GET("/master-data/export",
+		authMiddleware.GraphQLAuth(),
+		webAuthMiddleware.WebSessionMiddleware(),
+		webAuthMiddleware.GraphQLContextMiddleware(),
+		resolver.HandleMasterDataExport,
+	)
+
+	// Uploaded files are served from the object store. Theme assets are
+	// public; other files need a signed link or an authenticated caller with
+	// access to the file's company.
 	uploadsGroup := router.Group("/uploads")
-	uploadsGroup.Use(uploadStaticHeadersMiddleware())
-	uploadsGroup.StaticFS("/", gin.Dir(uploadsDir, false))
+	uploadsGroup.Use(
+		uploadStaticHeadersMiddleware(),
+		authMiddleware.GraphQLAuth(),
+		webAuthMiddleware.WebSessionMiddleware(),
+		webAuthMiddleware.GraphQLContextMiddleware(),
+	)
+	uploadsGroup.GET("/*key", resolver.HandleUploadDownload)
+	uploadsGroup.HEAD("/*key", resolver.HandleUploadDownload)
+
+	// Short-lived signed links for uploaded files
+	router.GET("/api/uploads/signed-url",
+		authMiddleware.GraphQLAuth(),
+		webAuthMiddleware.WebSessionMiddleware(),
+		webAuthMiddleware.GraphQLContextMiddleware(),
+		resolver.HandleUploadSignedURL,
+	)
+
+	// Resumable photo uploads (tus) for the mobile sync
+	photoUploadGroup := router.Group("/api/uploads/photos")
+	photoUploadGroup.Use(
+		authMiddleware.GraphQLAuth(),
+		webAuthMiddleware.WebSessionMiddleware(),
+		webAuthMiddleware.GraphQLContextMiddleware(),
+	)
+	routes.SetupPhotoUploadRoutes(photoUploadGroup, photoUploadService)
 
 	// ============================
 	// External Integration Routes (API Key auth)
@@ -316,14 +547,16 @@ func main() {
 		webAuthMiddleware.WebSessionMiddleware(),
 		webAuthMiddleware.GraphQLContextMiddleware(),
 	)
-	routes.SetupThemeCampaignRoutes(themeGroup, database.GetDB(), uploadsDir)
+	routes.SetupThemeCampaignRoutes(themeGroup, themeService, uploadStore)
 
 	publicThemeGroup := router.Group("/api/public")
-	routes.SetupPublicThemeCampaignRoutes(publicThemeGroup, database.GetDB())
+	routes.SetupPublicThemeCampaignRoutes(publicThemeGroup, themeService)
 	log.Info("🎨 Theme campaign routes registered at /api/theme/* and /api/public/theme-runtime")
 
 	// GraphQL playground endpoint
-	router.GET("/playground", gin.WrapH(playground.Handler("GraphQL playground", cfg.Server.GraphQLEndpoint)))
+	if cfg.GraphQL.PlaygroundEnabled && cfg.GraphQL.IntrospectionEnabled {
+		router.GET("/playground", gin.WrapH(playground.Handler("GraphQL playground", cfg.Server.GraphQLEndpoint)))
+	}
 
 	// Note: REST API endpoints have been removed in favor of pure GraphQL implementation
 	// All authentication and business logic is now handled through GraphQL mutations and queries
@@ -335,8 +568,11 @@ func main() {
 	log.Info("🔌 WebSocket endpoint available at ws://localhost%s/ws", port)
 	log.Info("⚡ GraphQL Subscriptions available at ws://localhost%s%s", port, cfg.Server.GraphQLEndpoint)
 	log.Info("🏥 Health check available at http://localhost%s/health", port)
+	if cfg.Observability.MetricsEnabled {
+		log.Info("📈 Prometheus metrics available at http://localhost%s%s", port, cfg.Observability.MetricsPath)
+	}
 
-	log.Info("Uploads directory mounted at %s", uploadsDir)
+	log.Info("Uploads served from %T (local root %s)", uploadStore, uploadsDir)
 
 	if err := router.Run(port); err != nil {
 		log.Fatal("Failed to start server: %v", err)
@@ -419,38 +655,52 @@ func resolveFCMCredentialCandidates() []string {
 	return candidates
 }
 
-func startSessionCleanupWorker(ctx context.Context, log *logger.Logger, sessionRepo *sharedAuthInfra.SessionRepository) {
-	const cleanupInterval = 30 * time.Minute
-	const inactiveRetention = 30 * 24 * time.Hour
+// startReadReplicas connects the configured read replicas and starts their
+// health and lag checks. Unset connection fields inherit the primary's.
+func startReadReplicas(cfg *config.Config) (*database.ReadReplicaManager, error) {
+	managerConfig := database.DefaultReadReplicaManagerConfig()
+	managerConfig.MaxReplicationLag = cfg.Database.ReplicaMaxLag
+	if cfg.Database.ReplicaHealthCheckInterval > 0 {
+		managerConfig.HealthCheckInterval = cfg.Database.ReplicaHealthCheckInterval
+	}
+	manager := database.NewReadReplicaManager(managerConfig)
 
-	cleanup := func() {
-		if err := sessionRepo.RevokeExpiredSessions(ctx); err != nil {
-			log.Warn("Session cleanup: failed to revoke expired sessions: %v", err)
+	for i, replica := range cfg.Database.Replicas {
+		id := replica.ID
+		if id == "" {
+			id = fmt.Sprintf("replica-%d", i+1)
 		}
-		if err := sessionRepo.CleanupOldSessions(ctx, inactiveRetention); err != nil {
-			log.Warn("Session cleanup: failed to delete old inactive sessions: %v", err)
+		replicaConfig := database.ReplicaConfig{
+			Host:     replica.Host,
+			Port:     replica.Port,
+			User:     replica.User,
+			Password: replica.Password,
+			DBName:   replica.Name,
+			SSLMode:  replica.SSLMode,
+			Weight:   replica.Weight,
+			Priority: replica.Priority,
 		}
-	}
-
-	// Run once at startup so stale rows don't linger until the first tick.
-	cleanup()
-
-	go func() {
-		ticker := time.NewTicker(cleanupInterval)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ctx.Done():
-				log.Info("Session cleanup worker stopped")
-				return
-			case <-ticker.C:
-				cleanup()
-			}
+		if replicaConfig.Port == 0 {
+			replicaConfig.Port = 5432
+		}
+		if replicaConfig.User == "" {
+			replicaConfig.User = cfg.Database.User
+			replicaConfig.Password = cfg.Database.Password
+		}
+		if replicaConfig.DBName == "" {
+			replicaConfig.DBName = cfg.Database.Name
 		}
-	}()
+		if replicaConfig.SSLMode == "" {
+			replicaConfig.SSLMode = "disable"
+		}
+		if err := manager.AddReplica(id, replicaConfig); err != nil {
+			manager.Close()
+			return nil, err
+		}
+	}
 
-	log.Info("Session cleanup worker started (interval: %s, retention: %s)", cleanupInterval, inactiveRetention)
+	manager.StartHealthChecking()
+	return manager, nil
 }
 
 func envFlagEnabled(key string) bool {
@@ -579,9 +829,9 @@ func corsMiddleware(allowedOrigins []string) gin.HandlerFunc {
 
 		// CRITICAL CORS headers for cookie authentication
 		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Cookie")
-		c.Header("Access-Control-Expose-Headers", "Set-Cookie, X-CSRF-Token, Content-Type")
+		c.Header("Access-Control-Allow-Methods", "GET, HEAD, POST, PUT, DELETE, OPTIONS, PATCH")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Cookie, Tus-Resumable, Upload-Length, Upload-Offset, Upload-Metadata")
+		c.Header("Access-Control-Expose-Headers", "Set-Cookie, X-CSRF-Token, Content-Type, Location, Tus-Resumable, Upload-Offset, Upload-Length, Upload-Expires")
 		c.Header("Access-Control-Max-Age", "86400") // Cache preflight for 24 hours
 
 		// Handle preflight requests
@@ -638,57 +888,52 @@ func uploadStaticHeadersMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("X-Content-Type-Options", "nosniff")
 		c.Header("X-Frame-Options", "DENY")
-		c.Header("Cache-Control", "public, max-age=31536000, immutable")
 		c.Header("Content-Security-Policy", "default-src 'none'; img-src 'self' data:; script-src 'none'; style-src 'none'; object-src 'none'; frame-ancestors 'none'")
 		c.Header("Cross-Origin-Resource-Policy", "same-origin")
 		c.Next()
 	}
 }
 
-func seedBundledThemeAssets(uploadsDir string) (int, error) {
+// seedBundledThemeAssets copies the web app's bundled theme assets into the
+// upload store, leaving objects that already exist untouched.
+func seedBundledThemeAssets(ctx context.Context, uploadStore storage.Store) (int, error) {
 	sourceDir, err := findBundledThemeAssetSourceDir()
 	if err != nil {
 		return 0, err
 	}
 
-	targetRoot := filepath.Join(uploadsDir, "theme-assets")
-	if err := os.MkdirAll(targetRoot, 0755); err != nil {
-		return 0, fmt.Errorf("create theme asset target directory: %w", err)
-	}
-
 	copied := 0
 	err = filepath.Walk(sourceDir, func(currentPath string, info os.FileInfo, walkErr error) error {
 		if walkErr != nil {
 			return walkErr
 		}
+		if info.IsDir() || !info.Mode().IsRegular() {
+			return nil
+		}
 
 		relativePath, err := filepath.Rel(sourceDir, currentPath)
 		if err != nil {
 			return err
 		}
-		if relativePath == "." {
-			return nil
-		}
 		if strings.HasPrefix(relativePath, "..") {
 			return fmt.Errorf("invalid relative path in theme assets: %s", relativePath)
 		}
 
-		destinationPath := filepath.Join(targetRoot, relativePath)
-		if info.IsDir() {
-			return os.MkdirAll(destinationPath, 0755)
-		}
-		if !info.Mode().IsRegular() {
+		key := storage.JoinKey("theme-assets", filepath.ToSlash(relativePath))
+		if _, err := uploadStore.Stat(ctx, key); err == nil {
 			return nil
+		} else if !errors.Is(err, storage.ErrNotFound) {
+			return err
 		}
 
-		if _, err := os.Stat(destinationPath); err == nil {
-			return nil
-		} else if !os.IsNotExist(err) {
-			return err
+		sourceFile, err := os.Open(currentPath)
+		if err != nil {
+			return fmt.Errorf("open source file: %w", err)
 		}
+		defer sourceFile.Close()
 
-		if err := copyFile(currentPath, destinationPath, info.Mode().Perm()); err != nil {
-			return err
+		if err := uploadStore.Put(ctx, key, sourceFile, info.Size(), mime.TypeByExtension(filepath.Ext(currentPath))); err != nil {
+			return fmt.Errorf("store %s: %w", key, err)
 		}
 		copied++
 		return nil
@@ -736,31 +981,3 @@ func findBundledThemeAssetSourceDir() (string, error) {
 
 	return "", fmt.Errorf("bundled theme asset source directory not found")
 }
-
-func copyFile(sourcePath string, destinationPath string, mode os.FileMode) error {
-	sourceFile, err := os.Open(sourcePath)
-	if err != nil {
-		return fmt.Errorf("open source file: %w", err)
-	}
-	defer sourceFile.Close()
-
-	destinationDir := filepath.Dir(destinationPath)
-	if err := os.MkdirAll(destinationDir, 0755); err != nil {
-		return fmt.Errorf("create destination directory: %w", err)
-	}
-
-	destinationFile, err := os.OpenFile(destinationPath, os.O_CREATE|os.O_WRONLY|os.O_EXCL, mode)
-	if err != nil {
-		if os.IsExist(err) {
-			return nil
-		}
-		return fmt.Errorf("open destination file: %w", err)
-	}
-	defer destinationFile.Close()
-
-	if _, err := io.Copy(destinationFile, sourceFile); err != nil {
-		return fmt.Errorf("copy file content: %w", err)
-	}
-
-	return nil
-}
@@ -17,6 +17,7 @@ type Service struct {
 	tokenService   mobileDomain.TokenService
 	passwordSvc    sharedDomain.PasswordService
 	securityLogger sharedDomain.SecurityEventLogger
+	loginPolicy    sharedDomain.LoginPolicy
 	config         MobileConfig
 }
 
@@ -41,6 +42,12 @@ func NewService(
 	}
 }
 
+// SetLoginPolicy injects the company login policy (lockout, password expiry).
+// A nil policy disables enforcement.
+func (s *Service) SetLoginPolicy(policy sharedDomain.LoginPolicy) {
+	s.loginPolicy = policy
+}
+
 // Login handles mobile authentication with JWT tokens
 func (s *Service) Login(ctx context.Context, input mobileDomain.MobileLoginInput) (*mobileDomain.MobileLoginResult, error) {
 	// 1. Find user
@@ -71,8 +78,22 @@ func (s *Service) Login(ctx context.Context, input mobileDomain.MobileLoginInput
 
 	// 2. Verify password (or biometric if provided)
 	if err := s.verifyAuthentication(ctx, user, input); err != nil {
+		if s.loginPolicy != nil && errors.Is(err, ErrInvalidCredentials) {
+			_ = s.loginPolicy.RecordFailedLogin(ctx, user.ID)
+		}
+		return nil, err
+	}
+
+	// 2b. Enforce company login policy. This runs only after the credentials
+	// are verified, so a caller without them cannot tell a locked or expired
+	// account from a wrong password.
+	sessionTimeout, err := s.checkLoginPolicy(ctx, user, string(input.Platform))
+	if err != nil {
 		return nil, err
 	}
+	if s.loginPolicy != nil {
+		_ = s.loginPolicy.RecordSuccessfulLogin(ctx, user.ID)
+	}
 
 	// 3. Check or create device binding
 	device, err := s.ensureDeviceBinding(ctx, user.ID, input)
@@ -115,12 +136,12 @@ func (s *Service) Login(ctx context.Context, input mobileDomain.MobileLoginInput
 	}
 
 	// 6. Generate fresh tokens for the current device binding.
-	tokenPair, err := s.tokenService.GenerateTokenPair(ctx, user.ID, device.DeviceID, user.Role, companyID)
+	tokenPair, err := s.tokenService.GenerateTokenPair(ctx, user.ID, device.DeviceID, user.Role, companyID, sessionTimeout)
 	if err != nil {
 		return nil, err
 	}
 
-	offlineToken, err := s.tokenService.GenerateOfflineToken(ctx, user.ID, device.DeviceID)
+	offlineToken, err := s.tokenService.GenerateOfflineToken(ctx, user.ID, device.DeviceID, sessionTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -147,7 +168,7 @@ func (s *Service) Login(ctx context.Context, input mobileDomain.MobileLoginInput
 		OfflineToken:     offlineToken,
 		ExpiresAt:        tokenPair.AccessExpiresAt,
 		RefreshExpiresAt: tokenPair.RefreshExpiresAt,
-		OfflineExpiresAt: time.Now().Add(s.config.OfflineTokenDuration),
+		OfflineExpiresAt: time.Now().Add(mobileDomain.CapLifetime(s.config.OfflineTokenDuration, sessionTimeout)),
 		User:             sharedDomain.ToUserDTO(user),
 		Assignments:      s.convertAssignments(assignments),
 		Device:           *device,
@@ -240,6 +261,12 @@ func (s *Service) RefreshToken(ctx context.Context, input mobileDomain.RefreshTo
 		}
 	}
 
+	// The company policy also bounds renewed tokens.
+	sessionTimeout, err := s.checkLoginPolicy(ctx, user, "mobile")
+	if err != nil {
+		return nil, err
+	}
+
 	// Generate new token pair
 	// Revoke old token ID first so refresh tokens are one-time use (rotation).
 	if err := s.tokenService.RevokeToken(ctx, claims.TokenID); err != nil {
@@ -247,7 +274,7 @@ func (s *Service) RefreshToken(ctx context.Context, input mobileDomain.RefreshTo
 	}
 
 	// Generate new token pair
-	tokenPair, err := s.tokenService.GenerateTokenPair(ctx, user.ID, device.DeviceID, user.Role, companyID)
+	tokenPair, err := s.tokenService.GenerateTokenPair(ctx, user.ID, device.DeviceID, user.Role, companyID, sessionTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -324,13 +351,19 @@ func (s *Service) DeviceRenew(ctx context.Context, input mobileDomain.DeviceRene
 		}
 	}
 
-	// 7. Revoke old token (the offline token's record, identified by claims.TokenID)
+	// 7. The company policy also bounds renewed tokens.
+	sessionTimeout, err := s.checkLoginPolicy(ctx, user, "mobile")
+	if err != nil {
+		return nil, err
+	}
+
+	// 8. Revoke old token (the offline token's record, identified by claims.TokenID)
 	if err := s.tokenService.RevokeToken(ctx, claims.TokenID); err != nil {
 		return nil, err
 	}
 
-	// 8. Issue new access+refresh pair (no new offline token — client keeps existing one)
-	tokenPair, err := s.tokenService.GenerateTokenPair(ctx, user.ID, device.DeviceID, user.Role, companyID)
+	// 9. Issue new access+refresh pair (no new offline token — client keeps existing one)
+	tokenPair, err := s.tokenService.GenerateTokenPair(ctx, user.ID, device.DeviceID, user.Role, companyID, sessionTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -473,7 +506,30 @@ func (s *Service) verifyAuthentication(ctx context.Context, user *sharedDomain.U
 	}
 
 	// Fall back to password verification
-	return s.passwordSvc.VerifyPassword(user.Password, input.Password)
+	if err := s.passwordSvc.VerifyPassword(user.Password, input.Password); err != nil {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+// checkLoginPolicy enforces the company login policy and returns the session
+// timeout that caps the issued tokens (zero when the company sets none).
+func (s *Service) checkLoginPolicy(ctx context.Context, user *sharedDomain.User, platform string) (time.Duration, error) {
+	if s.loginPolicy == nil {
+		return 0, nil
+	}
+	decision, err := s.loginPolicy.CheckLoginAllowed(ctx, user)
+	if err != nil {
+		s.securityLogger.LogSecurityEvent(ctx, &sharedDomain.SecurityEvent{
+			UserID:    &user.ID,
+			Event:     sharedDomain.EventLoginFailure,
+			Severity:  sharedDomain.SeverityWarning,
+			IPAddress: "0.0.0.0",
+			Details:   map[string]interface{}{"reason": err.Error(), "platform": platform},
+		})
+		return 0, err
+	}
+	return decision.SessionTimeout, nil
 }
 
 func (s *Service) verifyBiometricToken(ctx context.Context, userID, token string) error {
@@ -84,11 +84,13 @@ type DeviceRenewInput struct {
 
 // TokenService defines interface for JWT token management
 type TokenService interface {
-	// GenerateTokenPair generates access and refresh tokens
-	GenerateTokenPair(ctx context.Context, userID, deviceID string, role domain.Role, companyID string) (*TokenPair, error)
+	// GenerateTokenPair generates access and refresh tokens. A non-zero
+	// maxLifetime caps both expiries (company session timeout).
+	GenerateTokenPair(ctx context.Context, userID, deviceID string, role domain.Role, companyID string, maxLifetime time.Duration) (*TokenPair, error)
 
-	// GenerateOfflineToken generates offline-capable token
-	GenerateOfflineToken(ctx context.Context, userID, deviceID string) (string, error)
+	// GenerateOfflineToken generates offline-capable token. A non-zero
+	// maxLifetime caps its expiry.
+	GenerateOfflineToken(ctx context.Context, userID, deviceID string, maxLifetime time.Duration) (string, error)
 
 	// ValidateAccessToken validates JWT access token
 	ValidateAccessToken(ctx context.Context, token string) (*TokenClaims, error)
@@ -118,6 +120,14 @@ type TokenPair struct {
 	TokenID          string    `json:"tokenId"`
 }
 
+// CapLifetime returns d limited to maxLifetime; zero means no limit.
+func CapLifetime(d, maxLifetime time.Duration) time.Duration {
+	if maxLifetime > 0 && maxLifetime < d {
+		return maxLifetime
+	}
+	return d
+}
+
 // TokenClaims represents JWT token claims
 type TokenClaims struct {
 	UserID    string            `json:"userId"`
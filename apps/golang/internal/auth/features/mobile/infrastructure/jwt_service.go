@@ -31,9 +31,11 @@ func NewJWTService(db *gorm.DB, config JWTConfig) *JWTService {
 }
 
 // GenerateTokenPair generates access and refresh tokens
-func (s *JWTService) GenerateTokenPair(ctx context.Context, userID, deviceID string, role sharedDomain.Role, companyID string) (*mobileDomain.TokenPair, error) {
+func (s *JWTService) GenerateTokenPair(ctx context.Context, userID, deviceID string, role sharedDomain.Role, companyID string, maxLifetime time.Duration) (*mobileDomain.TokenPair, error) {
 	now := time.Now()
 	tokenID := s.generateTokenID()
+	accessDuration := mobileDomain.CapLifetime(s.config.AccessTokenDuration, maxLifetime)
+	refreshDuration := mobileDomain.CapLifetime(s.config.RefreshTokenDuration, maxLifetime)
 
 	// Access token claims
 	accessClaims := jwt.MapClaims{
@@ -45,7 +47,7 @@ func (s *JWTService) GenerateTokenPair(ctx context.Context, userID, deviceID str
 		"iss":        s.config.Issuer,
 		"type":       "access",
 		"iat":        now.Unix(),
-		"exp":        now.Add(s.config.AccessTokenDuration).Unix(),
+		"exp":        now.Add(accessDuration).Unix(),
 	}
 
 	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
@@ -62,7 +64,7 @@ func (s *JWTService) GenerateTokenPair(ctx context.Context, userID, deviceID str
 		"iss":       s.config.Issuer,
 		"type":      "refresh",
 		"iat":       now.Unix(),
-		"exp":       now.Add(s.config.RefreshTokenDuration).Unix(),
+		"exp":       now.Add(refreshDuration).Unix(),
 	}
 
 	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
@@ -79,8 +81,8 @@ func (s *JWTService) GenerateTokenPair(ctx context.Context, userID, deviceID str
 		TokenType:        "JWT",
 		TokenHash:        s.hashToken(accessTokenString),
 		RefreshHash:      &[]string{s.hashToken(refreshTokenString)}[0],
-		ExpiresAt:        now.Add(s.config.AccessTokenDuration),
-		RefreshExpiresAt: &[]time.Time{now.Add(s.config.RefreshTokenDuration)}[0],
+		ExpiresAt:        now.Add(accessDuration),
+		RefreshExpiresAt: &[]time.Time{now.Add(refreshDuration)}[0],
 		CreatedAt:        now,
 		UpdatedAt:        now,
 	}
@@ -100,9 +102,10 @@ func (s *JWTService) GenerateTokenPair(ctx context.Context, userID, deviceID str
 
 // GenerateOfflineToken generates an opaque offline/session token (32 random bytes, base64url-encoded).
 // The raw token is returned to the client; only its SHA256 hash is stored server-side.
-func (s *JWTService) GenerateOfflineToken(ctx context.Context, userID, deviceID string) (string, error) {
+func (s *JWTService) GenerateOfflineToken(ctx context.Context, userID, deviceID string, maxLifetime time.Duration) (string, error) {
 	now := time.Now()
 	tokenID := s.generateTokenID()
+	offlineDuration := mobileDomain.CapLifetime(s.config.OfflineTokenDuration, maxLifetime)
 
 	// Generate 32 cryptographically-random bytes → base64url (no padding)
 	rawBytes := make([]byte, 32)
@@ -120,8 +123,8 @@ func (s *JWTService) GenerateOfflineToken(ctx context.Context, userID, deviceID
 		TokenType:        "OFFLINE",
 		TokenHash:        hash, // reuse token_hash column as primary lookup key
 		OfflineHash:      &hash,
-		ExpiresAt:        now.Add(s.config.OfflineTokenDuration),
-		OfflineExpiresAt: &[]time.Time{now.Add(s.config.OfflineTokenDuration)}[0],
+		ExpiresAt:        now.Add(offlineDuration),
+		OfflineExpiresAt: &[]time.Time{now.Add(offlineDuration)}[0],
 		CreatedAt:        now,
 		UpdatedAt:        now,
 	}
@@ -1,6 +1,10 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+)
 
 // PasswordService defines interface for password operations
 type PasswordService interface {
@@ -12,3 +16,35 @@ type PasswordService interface {
 type SecurityEventLogger interface {
 	LogSecurityEvent(ctx context.Context, event *SecurityEvent) error
 }
+
+// LoginPolicy enforces company security settings (lockout, password expiry,
+// session timeout) during authentication.
+type LoginPolicy interface {
+	// CheckLoginAllowed is called after the password is verified, so its
+	// errors never reveal account state to a caller without the password.
+	CheckLoginAllowed(ctx context.Context, user *User) (*LoginPolicyDecision, error)
+	// RecordFailedLogin increments the failure counter and locks the account
+	// once the company limit is reached.
+	RecordFailedLogin(ctx context.Context, userID string) error
+	// RecordSuccessfulLogin clears the failure counter.
+	RecordSuccessfulLogin(ctx context.Context, userID string) error
+}
+
+// LoginPolicyDecision carries policy values that shape the issued session.
+type LoginPolicyDecision struct {
+	// SessionTimeout caps the session lifetime. Zero means no company override.
+	SessionTimeout time.Duration
+}
+
+// Login policy errors
+var (
+	ErrAccountLocked   = errors.New("account is locked due to too many failed login attempts, please contact your administrator")
+	ErrPasswordExpired = errors.New("password has expired, please reset your password")
+)
+
+// TenantQuota enforces tenant plan limits when users are provisioned.
+type TenantQuota interface {
+	// WithUserQuota runs create while the plans of companyIDs are locked, and
+	// fails without calling it when additional users would exceed a plan.
+	WithUserQuota(ctx context.Context, companyIDs []string, additional int, create func(ctx context.Context) error) error
+}
@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"agrinovagraphql/server/internal/auth/features/shared/domain"
+
+	"gorm.io/gorm"
+)
+
+// defaultLockoutDuration is how long an account stays locked once the
+// company's maxFailedLogins limit is reached.
+const defaultLockoutDuration = 30 * time.Minute
+
+// LoginPolicyRepository implements domain.LoginPolicy backed by company_settings.
+// When a user belongs to several companies the strictest non-zero value wins.
+type LoginPolicyRepository struct {
+	db              *gorm.DB
+	lockoutDuration time.Duration
+	now             func() time.Time
+}
+
+// NewLoginPolicyRepository creates new PostgreSQL login policy repository
+func NewLoginPolicyRepository(db *gorm.DB) *LoginPolicyRepository {
+	return &LoginPolicyRepository{
+		db:              db,
+		lockoutDuration: defaultLockoutDuration,
+		now:             time.Now,
+	}
+}
+
+type effectiveLoginPolicy struct {
+	SessionTimeoutMinutes sql.NullInt64
+	MaxFailedLogins       sql.NullInt64
+	PasswordExpiryDays    sql.NullInt64
+}
+
+type userLoginState struct {
+	FailedLoginAttempts int
+	LockedUntil         *time.Time
+	PasswordChangedAt   *time.Time
+}
+
+// CheckLoginAllowed rejects locked accounts and expired passwords, and returns
+// the session timeout to apply to the new session.
+func (r *LoginPolicyRepository) CheckLoginAllowed(ctx context.Context, user *domain.User) (*domain.LoginPolicyDecision, error) {
+	decision := &domain.LoginPolicyDecision{}
+	if user == nil || user.ID == "" {
+		return decision, nil
+	}
+
+	var state userLoginState
+	if err := r.db.WithContext(ctx).
+		Table("users").
+		Select("failed_login_attempts, locked_until, password_changed_at").
+		Where("id = ?", user.ID).
+		Scan(&state).Error; err != nil {
+		return nil, fmt.Errorf("failed to load login state: %w", err)
+	}
+
+	now := r.now()
+	if state.LockedUntil != nil && state.LockedUntil.After(now) {
+		return nil, domain.ErrAccountLocked
+	}
+
+	policy, err := r.loadEffectivePolicy(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if policy.PasswordExpiryDays.Valid && state.PasswordChangedAt != nil {
+		expiresAt := state.PasswordChangedAt.AddDate(0, 0, int(policy.PasswordExpiryDays.Int64))
+		if now.After(expiresAt) {
+			return nil, domain.ErrPasswordExpired
+		}
+	}
+
+	if policy.SessionTimeoutMinutes.Valid {
+		decision.SessionTimeout = time.Duration(policy.SessionTimeoutMinutes.Int64) * time.Minute
+	}
+
+	return decision, nil
+}
+
+// RecordFailedLogin increments the failure counter and locks the account when
+// the strictest company limit is reached.
+func (r *LoginPolicyRepository) RecordFailedLogin(ctx context.Context, userID string) error {
+	if userID == "" {
+		return nil
+	}
+
+	policy, err := r.loadEffectivePolicy(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{
+		"failed_login_attempts": gorm.Expr("COALESCE(failed_login_attempts, 0) + 1"),
+	}
+	if policy.MaxFailedLogins.Valid {
+		updates["locked_until"] = gorm.Expr(
+			"CASE WHEN COALESCE(failed_login_attempts, 0) + 1 >= ? THEN ? ELSE locked_until END",
+			policy.MaxFailedLogins.Int64,
+			r.now().Add(r.lockoutDuration),
+		)
+	}
+
+	return r.db.WithContext(ctx).
+		Table("users").
+		Where("id = ?", userID).
+		UpdateColumns(updates).Error
+}
+
+// RecordSuccessfulLogin clears the failure counter and any expired lock.
+func (r *LoginPolicyRepository) RecordSuccessfulLogin(ctx context.Context, userID string) error {
+	if userID == "" {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).
+		Table("users").
+		Where("id = ? AND (failed_login_attempts <> 0 OR locked_until IS NOT NULL)", userID).
+		UpdateColumns(map[string]interface{}{
+			"failed_login_attempts": 0,
+			"locked_until":          nil,
+		}).Error
+}
+
+// loadEffectivePolicy merges company_settings for every company the user is
+// assigned to, directly or through an estate/division assignment.
+func (r *LoginPolicyRepository) loadEffectivePolicy(ctx context.Context, userID string) (*effectiveLoginPolicy, error) {
+	var policy effectiveLoginPolicy
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT
+			MIN(NULLIF(cs.session_timeout_minutes, 0)) AS session_timeout_minutes,
+			MIN(NULLIF(cs.max_failed_logins, 0)) AS max_failed_logins,
+			MIN(NULLIF(cs.password_expiry_days, 0)) AS password_expiry_days
+		FROM company_settings cs
+		WHERE cs.company_id IN (
+			SELECT uca.company_id FROM user_company_assignments uca
+			WHERE uca.user_id = ? AND uca.is_active = true
+			UNION
+			SELECT e.company_id FROM user_estate_assignments uea
+			JOIN estates e ON e.id = uea.estate_id
+			WHERE uea.user_id = ? AND uea.is_active = true
+			UNION
+			SELECT e.company_id FROM user_division_assignments uda
+			JOIN divisions d ON d.id = uda.division_id
+			JOIN estates e ON e.id = d.estate_id
+			WHERE uda.user_id = ? AND uda.is_active = true
+		)
+	`, userID, userID, userID).Scan(&policy).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load company login policy: %w", err)
+	}
+	return &policy, nil
+}
@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"agrinovagraphql/server/internal/auth/features/shared/domain"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupLoginPolicyTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	for _, stmt := range []string{
+		`CREATE TABLE users (
+			id TEXT PRIMARY KEY,
+			failed_login_attempts INTEGER DEFAULT 0,
+			locked_until DATETIME,
+			password_changed_at DATETIME
+		)`,
+		`CREATE TABLE company_settings (
+			company_id TEXT PRIMARY KEY,
+			session_timeout_minutes INTEGER,
+			max_failed_logins INTEGER,
+			password_expiry_days INTEGER
+		)`,
+		`CREATE TABLE user_company_assignments (user_id TEXT, company_id TEXT, is_active BOOLEAN)`,
+		`CREATE TABLE estates (id TEXT PRIMARY KEY, company_id TEXT)`,
+		`CREATE TABLE divisions (id TEXT PRIMARY KEY, estate_id TEXT)`,
+		`CREATE TABLE user_estate_assignments (user_id TEXT, estate_id TEXT, is_active BOOLEAN)`,
+		`CREATE TABLE user_division_assignments (user_id TEXT, division_id TEXT, is_active BOOLEAN)`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+	return db
+}
+
+func newTestLoginPolicyRepository(db *gorm.DB, now time.Time) *LoginPolicyRepository {
+	repo := NewLoginPolicyRepository(db)
+	repo.now = func() time.Time { return now }
+	return repo
+}
+
+func loadFailedLoginState(t *testing.T, db *gorm.DB, userID string) userLoginState {
+	t.Helper()
+	var state userLoginState
+	require.NoError(t, db.Table("users").
+		Select("failed_login_attempts, locked_until, password_changed_at").
+		Where("id = ?", userID).
+		Scan(&state).Error)
+	return state
+}
+
+func TestLoginPolicyRepository_CheckLoginAllowed_StrictestCompanyWins(t *testing.T) {
+	db := setupLoginPolicyTestDB(t)
+	now := time.Now()
+	repo := newTestLoginPolicyRepository(db, now)
+
+	require.NoError(t, db.Exec(`INSERT INTO users (id, password_changed_at) VALUES (?, ?)`, "user-1", now.AddDate(0, 0, -10)).Error)
+	require.NoError(t, db.Exec(`INSERT INTO company_settings VALUES ('company-a', 120, 5, 90), ('company-b', 30, 0, 0), ('company-c', 5, 3, 1)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO user_company_assignments VALUES ('user-1', 'company-a', true), ('user-1', 'company-c', false)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO estates VALUES ('estate-b', 'company-b')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO divisions VALUES ('division-b', 'estate-b')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO user_division_assignments VALUES ('user-1', 'division-b', true)`).Error)
+
+	decision, err := repo.CheckLoginAllowed(context.Background(), &domain.User{ID: "user-1"})
+	require.NoError(t, err)
+	// company-b is reached through the division; the inactive company-c is ignored.
+	assert.Equal(t, 30*time.Minute, decision.SessionTimeout)
+}
+
+func TestLoginPolicyRepository_CheckLoginAllowed_NoPolicy(t *testing.T) {
+	db := setupLoginPolicyTestDB(t)
+	repo := newTestLoginPolicyRepository(db, time.Now())
+	require.NoError(t, db.Exec(`INSERT INTO users (id) VALUES ('user-1')`).Error)
+
+	decision, err := repo.CheckLoginAllowed(context.Background(), &domain.User{ID: "user-1"})
+	require.NoError(t, err)
+	assert.Zero(t, decision.SessionTimeout)
+}
+
+func TestLoginPolicyRepository_CheckLoginAllowed_Locked(t *testing.T) {
+	db := setupLoginPolicyTestDB(t)
+	now := time.Now()
+	repo := newTestLoginPolicyRepository(db, now)
+	require.NoError(t, db.Exec(`INSERT INTO users (id, locked_until) VALUES (?, ?)`, "user-1", now.Add(10*time.Minute)).Error)
+	require.NoError(t, db.Exec(`INSERT INTO users (id, locked_until) VALUES (?, ?)`, "user-2", now.Add(-time.Minute)).Error)
+
+	_, err := repo.CheckLoginAllowed(context.Background(), &domain.User{ID: "user-1"})
+	assert.ErrorIs(t, err, domain.ErrAccountLocked)
+
+	_, err = repo.CheckLoginAllowed(context.Background(), &domain.User{ID: "user-2"})
+	assert.NoError(t, err, "an expired lock no longer blocks login")
+}
+
+func TestLoginPolicyRepository_CheckLoginAllowed_PasswordExpired(t *testing.T) {
+	db := setupLoginPolicyTestDB(t)
+	now := time.Now()
+	repo := newTestLoginPolicyRepository(db, now)
+	require.NoError(t, db.Exec(`INSERT INTO users (id, password_changed_at) VALUES (?, ?)`, "user-1", now.AddDate(0, 0, -31)).Error)
+	require.NoError(t, db.Exec(`INSERT INTO company_settings VALUES ('company-a', 0, 0, 30)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO user_company_assignments VALUES ('user-1', 'company-a', true)`).Error)
+
+	_, err := repo.CheckLoginAllowed(context.Background(), &domain.User{ID: "user-1"})
+	assert.ErrorIs(t, err, domain.ErrPasswordExpired)
+}
+
+func TestLoginPolicyRepository_RecordFailedLogin_LocksAtLimit(t *testing.T) {
+	db := setupLoginPolicyTestDB(t)
+	now := time.Now()
+	repo := newTestLoginPolicyRepository(db, now)
+	require.NoError(t, db.Exec(`INSERT INTO users (id) VALUES ('user-1')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO company_settings VALUES ('company-a', 0, 3, 0)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO estates VALUES ('estate-a', 'company-a')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO user_estate_assignments VALUES ('user-1', 'estate-a', true)`).Error)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		require.NoError(t, repo.RecordFailedLogin(ctx, "user-1"))
+	}
+	state := loadFailedLoginState(t, db, "user-1")
+	assert.Equal(t, 2, state.FailedLoginAttempts)
+	assert.Nil(t, state.LockedUntil)
+
+	require.NoError(t, repo.RecordFailedLogin(ctx, "user-1"))
+	state = loadFailedLoginState(t, db, "user-1")
+	assert.Equal(t, 3, state.FailedLoginAttempts)
+	require.NotNil(t, state.LockedUntil)
+	assert.WithinDuration(t, now.Add(defaultLockoutDuration), *state.LockedUntil, time.Second)
+
+	_, err := repo.CheckLoginAllowed(ctx, &domain.User{ID: "user-1"})
+	assert.ErrorIs(t, err, domain.ErrAccountLocked)
+}
+
+func TestLoginPolicyRepository_RecordFailedLogin_NoLimitNeverLocks(t *testing.T) {
+	db := setupLoginPolicyTestDB(t)
+	repo := newTestLoginPolicyRepository(db, time.Now())
+	require.NoError(t, db.Exec(`INSERT INTO users (id) VALUES ('user-1')`).Error)
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, repo.RecordFailedLogin(context.Background(), "user-1"))
+	}
+	state := loadFailedLoginState(t, db, "user-1")
+	assert.Equal(t, 10, state.FailedLoginAttempts)
+	assert.Nil(t, state.LockedUntil)
+}
+
+func TestLoginPolicyRepository_RecordSuccessfulLogin_ClearsFailures(t *testing.T) {
+	db := setupLoginPolicyTestDB(t)
+	repo := newTestLoginPolicyRepository(db, time.Now())
+	require.NoError(t, db.Exec(`INSERT INTO users (id, failed_login_attempts, locked_until) VALUES (?, ?, ?)`, "user-1", 4, time.Now().Add(-time.Minute)).Error)
+
+	require.NoError(t, repo.RecordSuccessfulLogin(context.Background(), "user-1"))
+	state := loadFailedLoginState(t, db, "user-1")
+	assert.Zero(t, state.FailedLoginAttempts)
+	assert.Nil(t, state.LockedUntil)
+}
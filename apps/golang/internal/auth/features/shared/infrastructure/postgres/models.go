@@ -20,6 +20,11 @@ type UserModel struct {
 	UpdatedAt time.Time  `gorm:"column:updated_at"`
 	DeletedAt *time.Time `gorm:"column:deleted_at;index"`
 
+	// Login policy state (company security settings)
+	FailedLoginAttempts int        `gorm:"column:failed_login_attempts;default:0"`
+	LockedUntil         *time.Time `gorm:"column:locked_until"`
+	PasswordChangedAt   *time.Time `gorm:"column:password_changed_at"`
+
 	// Relations
 	Manager             *UserModel                    `gorm:"foreignKey:ManagerID;references:ID"`
 	CompanyAssignments  []UserCompanyAssignmentModel  `gorm:"foreignKey:UserID"`
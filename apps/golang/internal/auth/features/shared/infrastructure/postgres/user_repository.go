@@ -362,6 +362,11 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 			CreatedAt: userModel.CreatedAt,
 			UpdatedAt: userModel.UpdatedAt,
 		}
+		passwordChangedAt := userModel.CreatedAt
+		if passwordChangedAt.IsZero() {
+			passwordChangedAt = time.Now()
+		}
+		baseUser.PasswordChangedAt = &passwordChangedAt
 
 		if err := tx.Create(&baseUser).Error; err != nil {
 			return err
@@ -407,6 +412,12 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 				"is_active":  userModel.IsActive,
 				"manager_id": userModel.ManagerID,
 				"updated_at": userModel.UpdatedAt,
+				// Reset password age only when the stored hash actually changes.
+				"password_changed_at": gorm.Expr(
+					"CASE WHEN password <> ? THEN ? ELSE password_changed_at END",
+					userModel.Password,
+					time.Now(),
+				),
 			}).Error; err != nil {
 			return err
 		}
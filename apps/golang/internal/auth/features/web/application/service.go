@@ -24,6 +24,7 @@ type Service struct {
 	passwordSvc    sharedDomain.PasswordService
 	securityLogger sharedDomain.SecurityEventLogger
 	rateLimiter    webDomain.RateLimiter // Injected interface
+	loginPolicy    sharedDomain.LoginPolicy
 	config         WebConfig
 }
 
@@ -52,6 +53,12 @@ func NewService(
 	}
 }
 
+// SetLoginPolicy injects the company login policy (lockout, password expiry,
+// session timeout). A nil policy disables enforcement.
+func (s *Service) SetLoginPolicy(policy sharedDomain.LoginPolicy) {
+	s.loginPolicy = policy
+}
+
 // Login handles web authentication with cookies
 func (s *Service) Login(ctx context.Context, input webDomain.WebLoginInput) (*webDomain.WebLoginResult, error) {
 	// 0. Check Rate Limiter
@@ -121,6 +128,9 @@ func (s *Service) Login(ctx context.Context, input webDomain.WebLoginInput) (*we
 
 	// 2. Verify password
 	if err := s.passwordSvc.VerifyPassword(user.Password, input.Password); err != nil {
+		if s.loginPolicy != nil {
+			_ = s.loginPolicy.RecordFailedLogin(ctx, user.ID)
+		}
 		if err := consumeFailureBudget(&user.ID); err != nil {
 			return nil, err
 		}
@@ -134,19 +144,46 @@ func (s *Service) Login(ctx context.Context, input webDomain.WebLoginInput) (*we
 		return nil, ErrInvalidCredentials
 	}
 
+	// 3. Enforce company login policy. This runs only after the password is
+	// verified, so a caller without it cannot tell a locked or expired account
+	// from a wrong password.
+	var policyDecision *sharedDomain.LoginPolicyDecision
+	if s.loginPolicy != nil {
+		policyDecision, err = s.loginPolicy.CheckLoginAllowed(ctx, user)
+		if err != nil {
+			if errors.Is(err, sharedDomain.ErrAccountLocked) || errors.Is(err, sharedDomain.ErrPasswordExpired) {
+				s.securityLogger.LogSecurityEvent(ctx, &sharedDomain.SecurityEvent{
+					UserID:    &user.ID,
+					Event:     sharedDomain.EventLoginFailure,
+					IPAddress: input.IPAddress,
+					UserAgent: input.UserAgent,
+					Details:   map[string]interface{}{"error": err.Error()},
+				})
+			}
+			return nil, err
+		}
+	}
+
+	if s.loginPolicy != nil {
+		_ = s.loginPolicy.RecordSuccessfulLogin(ctx, user.ID)
+	}
+
 	userDTO := sharedDomain.ToUserDTO(user)
 
-	// 3. Create session
+	// 4. Create session
 	sessionDuration := s.config.SessionDuration
 	if input.RememberMe {
 		sessionDuration = s.config.RememberMeDuration
 	}
+	if policyDecision != nil && policyDecision.SessionTimeout > 0 && policyDecision.SessionTimeout < sessionDuration {
+		sessionDuration = policyDecision.SessionTimeout
+	}
 	session, err := s.issueWebSession(ctx, user.ID, input.IPAddress, input.UserAgent, sessionDuration, "PASSWORD")
 	if err != nil {
 		return nil, err
 	}
 
-	// 4. Set auth cookies
+	// 5. Set auth cookies
 	csrfToken, err := s.cookieService.GenerateCSRFToken()
 	if err != nil {
 		return nil, err
@@ -158,7 +195,7 @@ func (s *Service) Login(ctx context.Context, input webDomain.WebLoginInput) (*we
 
 	s.rateLimiter.Reset(clientKey)
 
-	// 5. Log successful login
+	// 6. Log successful login
 	s.securityLogger.LogSecurityEvent(ctx, &sharedDomain.SecurityEvent{
 		UserID:    &user.ID,
 		Event:     sharedDomain.EventLoginSuccess,
@@ -167,7 +204,7 @@ func (s *Service) Login(ctx context.Context, input webDomain.WebLoginInput) (*we
 		Details:   map[string]interface{}{"platform": "web"},
 	})
 
-	// 6. Return result
+	// 7. Return result
 	return &webDomain.WebLoginResult{
 		SessionID: session.ID,
 		User:      userDTO,
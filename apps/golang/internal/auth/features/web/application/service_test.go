@@ -2,6 +2,7 @@ package web
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -565,3 +566,52 @@ func testWebLoginInput(identifier, password string) webDomain.WebLoginInput {
 		UserAgent:  "test-agent",
 	}
 }
+
+type stubTenantQuota struct {
+	err        error
+	companyIDs []string
+	calls      int
+}
+
+func (q *stubTenantQuota) WithUserQuota(ctx context.Context, companyIDs []string, _ int, create func(ctx context.Context) error) error {
+	q.calls++
+	q.companyIDs = companyIDs
+	if q.err != nil {
+		return q.err
+	}
+	return create(ctx)
+}
+
+func TestToggleStatusChecksTenantQuotaOnReactivation(t *testing.T) {
+	user := &sharedDomain.User{
+		ID:       "user-1",
+		Role:     sharedDomain.RoleTimbangan,
+		IsActive: false,
+		Assignments: []sharedDomain.Assignment{
+			{ID: "assignment-1", UserID: "user-1", CompanyID: "company-1", IsActive: true},
+		},
+	}
+	quota := &stubTenantQuota{err: errors.New("User quota exceeded")}
+	service := NewUserManagementService(&stubUserRepo{byIDUser: user}, &stubPasswordService{}, &stubSecurityLogger{})
+	service.SetTenantQuota(quota)
+
+	if _, err := service.ToggleStatus(context.Background(), "user-1"); err == nil {
+		t.Fatal("expected quota error on reactivation")
+	}
+	if quota.calls != 1 || len(quota.companyIDs) != 1 || quota.companyIDs[0] != "company-1" {
+		t.Fatalf("expected one quota check for company-1, got %d calls for %v", quota.calls, quota.companyIDs)
+	}
+
+	quota.err = nil
+	user.IsActive = true
+	toggled, err := service.ToggleStatus(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("expected deactivation to succeed, got %v", err)
+	}
+	if toggled.IsActive {
+		t.Fatal("expected user to be deactivated")
+	}
+	if quota.calls != 1 {
+		t.Fatalf("deactivation must not check the quota, got %d calls", quota.calls)
+	}
+}
@@ -70,6 +70,7 @@ type UserManagementService struct {
 	userRepo       domain.UserRepository
 	passwordSvc    domain.PasswordService
 	securityLogger domain.SecurityEventLogger
+	tenantQuota    domain.TenantQuota
 }
 
 // NewUserManagementService creates new user management service
@@ -85,6 +86,11 @@ func NewUserManagementService(
 	}
 }
 
+// SetTenantQuota injects tenant plan limit enforcement. A nil quota disables it.
+func (s *UserManagementService) SetTenantQuota(quota domain.TenantQuota) {
+	s.tenantQuota = quota
+}
+
 // GetUsers returns users with filters
 func (s *UserManagementService) GetUsers(ctx context.Context, filters domain.UserFilters) ([]*domain.User, int64, error) {
 	if s.isCompanyAdminRequester(ctx) {
@@ -254,7 +260,14 @@ func (s *UserManagementService) CreateUser(ctx context.Context, input domain.Use
 
 	syncMandorTypeAcrossCompanyAssignments(user.Assignments, input.Role, effectiveMandorType)
 
-	if err := s.userRepo.Create(ctx, user); err != nil {
+	create := func(ctx context.Context) error {
+		return s.userRepo.Create(ctx, user)
+	}
+	if user.IsActive {
+		if err := s.withUserQuota(ctx, companyIDs, create); err != nil {
+			return nil, err
+		}
+	} else if err := create(ctx); err != nil {
 		return nil, err
 	}
 
@@ -548,7 +561,14 @@ func (s *UserManagementService) UpdateUser(ctx context.Context, input domain.Use
 	user.Name = input.Name
 	user.Phone = input.Phone
 
-	if err := s.userRepo.Update(ctx, user); err != nil {
+	update := func(ctx context.Context) error {
+		return s.userRepo.Update(ctx, user)
+	}
+	if becameActive {
+		if err := s.withUserQuota(ctx, effectiveCompanyIDs, update); err != nil {
+			return nil, err
+		}
+	} else if err := update(ctx); err != nil {
 		return nil, err
 	}
 
@@ -632,13 +652,32 @@ func (s *UserManagementService) ToggleStatus(ctx context.Context, id string) (*d
 	user.IsActive = nextStatus
 	user.UpdatedAt = time.Now()
 
-	if err := s.userRepo.Update(ctx, user); err != nil {
+	update := func(ctx context.Context) error {
+		return s.userRepo.Update(ctx, user)
+	}
+	if nextStatus {
+		// A reactivated user counts against the plans again.
+		companyIDs, _, _ := extractActiveAssignmentIDs(user.Assignments)
+		if err := s.withUserQuota(ctx, companyIDs, update); err != nil {
+			return nil, err
+		}
+	} else if err := update(ctx); err != nil {
 		return nil, err
 	}
 
 	return user, nil
 }
 
+// withUserQuota runs write while the plans of companyIDs are locked, after
+// checking they have room for one more active user, so concurrent writes
+// cannot both pass the limit.
+func (s *UserManagementService) withUserQuota(ctx context.Context, companyIDs []string, write func(ctx context.Context) error) error {
+	if s.tenantQuota == nil {
+		return write(ctx)
+	}
+	return s.tenantQuota.WithUserQuota(ctx, companyIDs, 1, write)
+}
+
 func (s *UserManagementService) requesterRole(ctx context.Context) domain.Role {
 	roleVal := ctx.Value("user_role")
 	if roleVal == nil {
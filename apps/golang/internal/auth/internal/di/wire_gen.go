@@ -18,6 +18,7 @@ import (
 	webApp "agrinovagraphql/server/internal/auth/features/web/application"
 	webInfra "agrinovagraphql/server/internal/auth/features/web/infrastructure"
 	webGraphQL "agrinovagraphql/server/internal/auth/features/web/interfaces/graphql"
+	companyServices "agrinovagraphql/server/internal/company/services"
 
 	"time"
 
@@ -39,6 +40,7 @@ func NewAuthModuleV2(db *gorm.DB) (*AuthModule, error) {
 	deviceRepo := sharedInfra.NewDeviceRepository(db)
 	assignmentRepo := sharedInfra.NewAssignmentRepository(db)
 	companyRepo := sharedInfra.NewCompanyRepository(db)
+	loginPolicy := sharedInfra.NewLoginPolicyRepository(db)
 
 	// Security services
 	passwordService := securityInfra.NewPasswordService()
@@ -75,6 +77,7 @@ func NewAuthModuleV2(db *gorm.DB) (*AuthModule, error) {
 		rateLimiter,
 		webConfig,
 	)
+	webAuthService.SetLoginPolicy(loginPolicy)
 
 	webResolver := webGraphQL.NewResolver(webAuthService)
 
@@ -107,6 +110,7 @@ func NewAuthModuleV2(db *gorm.DB) (*AuthModule, error) {
 		securityLogger,
 		mobileConfig,
 	)
+	mobileAuthService.SetLoginPolicy(loginPolicy)
 
 	mobileResolver := mobileGraphQL.NewResolver(mobileAuthService)
 
@@ -126,6 +130,7 @@ func NewAuthModuleV2(db *gorm.DB) (*AuthModule, error) {
 		passwordService,
 		securityLogger,
 	)
+	userManagementService.SetTenantQuota(companyServices.NewTenantPlanService(db))
 
 	// Create module
 	authModule := &AuthModule{
@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"agrinovagraphql/server/pkg/email"
 )
 
 // EmailService abstracts email provider integration.
@@ -15,6 +17,33 @@ type EmailService interface {
 	SendResetPassword(to string, link string) error
 }
 
+// SenderEmailService sends auth emails through a configured email.Sender
+// (SMTP or SendGrid).
+type SenderEmailService struct {
+	sender email.Sender
+}
+
+// NewSenderEmailService creates an email service backed by sender.
+func NewSenderEmailService(sender email.Sender) *SenderEmailService {
+	return &SenderEmailService{sender: sender}
+}
+
+// SendResetPassword sends reset-password email to a user.
+func (s *SenderEmailService) SendResetPassword(to string, link string) error {
+	if strings.TrimSpace(link) == "" {
+		return fmt.Errorf("reset link is empty")
+	}
+
+	return s.sender.Send(context.Background(), &email.Message{
+		To:       []string{strings.TrimSpace(to)},
+		Subject:  resetPasswordSubject,
+		TextBody: fmt.Sprintf("Gunakan link berikut untuk reset password Anda: %s", link),
+		HTMLBody: fmt.Sprintf("<p>Gunakan link berikut untuk reset password Anda:</p><p><a href=\"%s\">Reset Password</a></p>", link),
+	})
+}
+
+const resetPasswordSubject = "Reset password Agrinova"
+
 // SendGridEmailService sends emails using SendGrid API.
 type SendGridEmailService struct {
 	apiKey     string
@@ -59,7 +88,7 @@ func (s *SendGridEmailService) SendResetPassword(to string, link string) error {
 		"from": map[string]string{
 			"email": s.from,
 		},
-		"subject": "Reset password Agrinova",
+		"subject": resetPasswordSubject,
 		"content": []map[string]string{
 			{
 				"type":  "text/plain",
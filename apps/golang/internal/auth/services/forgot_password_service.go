@@ -14,10 +14,11 @@ import (
 	"net/url"
 	"os"
 	"strings"
-	"sync"
 	"time"
 
 	"agrinovagraphql/server/internal/auth/models"
+	"agrinovagraphql/server/internal/ratelimit"
+	"agrinovagraphql/server/pkg/email"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -59,59 +60,35 @@ type SessionTokenRevoker interface {
 	RevokeAllByUserID(ctx context.Context, tx *gorm.DB, userID string, now time.Time) error
 }
 
-type forgotPasswordRateLimitEntry struct {
-	Count       int
-	WindowStart time.Time
-}
-
+// forgotPasswordRateLimiter caps reset requests per IP and email. Its
+// counter is in-memory by default; SetRateLimitCounter shares it across
+// instances.
 type forgotPasswordRateLimiter struct {
-	mu          sync.Mutex
-	entries     map[string]forgotPasswordRateLimitEntry
+	counter     ratelimit.Counter
+	ownCounter  bool // counter was created here and is closed when replaced
 	maxAttempts int
 	window      time.Duration
 }
 
 func newForgotPasswordRateLimiter(maxAttempts int, window time.Duration) *forgotPasswordRateLimiter {
 	return &forgotPasswordRateLimiter{
-		entries:     make(map[string]forgotPasswordRateLimitEntry),
+		counter:     ratelimit.NewMemory(window),
+		ownCounter:  true,
 		maxAttempts: maxAttempts,
 		window:      window,
 	}
 }
 
-func (l *forgotPasswordRateLimiter) Allow(ip string, normalizedEmail string, now time.Time) bool {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
-	key := fmt.Sprintf("%s|%s", strings.TrimSpace(ip), normalizedEmail)
-	entry, exists := l.entries[key]
-	if !exists || now.Sub(entry.WindowStart) >= l.window {
-		l.entries[key] = forgotPasswordRateLimitEntry{
-			Count:       1,
-			WindowStart: now,
-		}
-		l.cleanup(now)
+// Allow fails open when the counter is unavailable, so a database hiccup
+// does not block password recovery; the outage is logged.
+func (l *forgotPasswordRateLimiter) Allow(ctx context.Context, ip string, normalizedEmail string) bool {
+	key := fmt.Sprintf("forgot_password:%s|%s", strings.TrimSpace(ip), normalizedEmail)
+	allowed, _, err := ratelimit.Allow(ctx, l.counter, key, l.maxAttempts, l.window)
+	if err != nil {
+		log.Printf("forgot password rate limit unavailable: %v", err)
 		return true
 	}
-
-	if entry.Count >= l.maxAttempts {
-		l.entries[key] = entry
-		l.cleanup(now)
-		return false
-	}
-
-	entry.Count++
-	l.entries[key] = entry
-	l.cleanup(now)
-	return true
-}
-
-func (l *forgotPasswordRateLimiter) cleanup(now time.Time) {
-	for key, entry := range l.entries {
-		if now.Sub(entry.WindowStart) >= l.window {
-			delete(l.entries, key)
-		}
-	}
+	return allowed
 }
 
 // ForgotPasswordService implements forgot/reset password use cases.
@@ -137,10 +114,14 @@ func NewForgotPasswordService(
 	emailService EmailService,
 ) *ForgotPasswordService {
 	if emailService == nil {
-		emailService = NewSendGridEmailService(
-			os.Getenv("SENDGRID_API_KEY"),
-			os.Getenv("EMAIL_FROM"),
-		)
+		if sender, err := email.NewSenderFromEnv(); err == nil && sender != nil {
+			emailService = NewSenderEmailService(sender)
+		} else {
+			emailService = NewSendGridEmailService(
+				os.Getenv("SENDGRID_API_KEY"),
+				os.Getenv("EMAIL_FROM"),
+			)
+		}
 	}
 
 	resetURL := strings.TrimSpace(os.Getenv("APP_RESET_PASSWORD_URL"))
@@ -152,7 +133,7 @@ func NewForgotPasswordService(
 		db:                     db,
 		userRepo:               &gormForgotPasswordUserRepository{db: db},
 		passwordResetRepo:      &gormPasswordResetRepository{db: db},
-		sessionTokenRevoker:    &gormSessionTokenRevoker{db: db},
+		sessionTokenRevoker:    NewSessionTokenRevoker(db, "password_reset"),
 		emailService:           emailService,
 		passwordService:        passwordService,
 		securityLoggingService: securityLoggingService,
@@ -163,6 +144,19 @@ func NewForgotPasswordService(
 	}
 }
 
+// SetRateLimitCounter moves the forgot-password limit onto counter, e.g. a
+// Postgres counter shared by all instances.
+func (s *ForgotPasswordService) SetRateLimitCounter(counter ratelimit.Counter) {
+	if counter == nil {
+		return
+	}
+	if s.rateLimiter.ownCounter {
+		s.rateLimiter.counter.Close()
+	}
+	s.rateLimiter.counter = counter
+	s.rateLimiter.ownCounter = false
+}
+
 // ForgotPassword handles password reset request with anti-enumeration response.
 func (s *ForgotPasswordService) ForgotPassword(ctx context.Context, email string) (bool, string) {
 	normalizedEmail := normalizeEmail(email)
@@ -173,7 +167,7 @@ func (s *ForgotPasswordService) ForgotPassword(ctx context.Context, email string
 		return true, forgotPasswordGenericMessage
 	}
 
-	if !s.rateLimiter.Allow(clientIP, normalizedEmail, now) {
+	if !s.rateLimiter.Allow(ctx, clientIP, normalizedEmail) {
 		s.logAnonymousSecurity("forgot_password_rate_limited", map[string]interface{}{
 			"email": normalizedEmail,
 			"ip":    clientIP,
@@ -499,8 +493,12 @@ func (r *gormForgotPasswordUserRepository) UpdatePasswordHash(
 	db := r.withDB(tx).WithContext(ctx).Table("users").
 		Where("id = ? AND is_active = true", userID).
 		Updates(map[string]interface{}{
-			"password":   passwordHash,
-			"updated_at": updatedAt,
+			"password":            passwordHash,
+			"updated_at":          updatedAt,
+			"password_changed_at": updatedAt,
+			// A completed reset proves ownership, so lift any login lockout.
+			"failed_login_attempts": 0,
+			"locked_until":          nil,
 		})
 	if db.Error != nil {
 		return db.Error
@@ -581,7 +579,14 @@ func (r *gormPasswordResetRepository) MarkUsed(
 }
 
 type gormSessionTokenRevoker struct {
-	db *gorm.DB
+	db     *gorm.DB
+	reason string
+}
+
+// NewSessionTokenRevoker returns a SessionTokenRevoker that revokes a user's
+// sessions and JWT tokens, recording reason on the sessions.
+func NewSessionTokenRevoker(db *gorm.DB, reason string) SessionTokenRevoker {
+	return &gormSessionTokenRevoker{db: db, reason: reason}
 }
 
 func (r *gormSessionTokenRevoker) withDB(tx *gorm.DB) *gorm.DB {
@@ -605,7 +610,7 @@ func (r *gormSessionTokenRevoker) RevokeAllByUserID(
 			Updates(map[string]interface{}{
 				"is_active":      false,
 				"revoked":        true,
-				"revoked_reason": "password_reset",
+				"revoked_reason": r.reason,
 				"updated_at":     now,
 			}).Error; err != nil {
 			return err
@@ -276,6 +276,9 @@ func mustOpenForgotPasswordTestDB(t *testing.T) *gorm.DB {
 			password TEXT NOT NULL,
 			is_active BOOLEAN NOT NULL DEFAULT 1,
 			email_verified BOOLEAN NOT NULL DEFAULT 1,
+			failed_login_attempts INTEGER NOT NULL DEFAULT 0,
+			locked_until DATETIME NULL,
+			password_changed_at DATETIME NULL,
 			updated_at DATETIME
 		);
 	`)
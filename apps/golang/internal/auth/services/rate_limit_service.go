@@ -1,9 +1,14 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"math"
 	"sync"
 	"time"
+
+	"agrinovagraphql/server/internal/ratelimit"
 )
 
 // RateLimitService handles rate limiting for authentication operations
@@ -12,6 +17,9 @@ type RateLimitService struct {
 	refreshAttempts map[string]*AttemptTracker
 	mu              sync.RWMutex
 
+	// Shared attempt and lockout counters; nil keeps them in the maps above.
+	counter ratelimit.Counter
+
 	// Configuration
 	maxLoginAttempts      int
 	maxRefreshAttempts    int
@@ -72,8 +80,36 @@ func NewRateLimitServiceWithConfig(config RateLimitConfig) *RateLimitService {
 	return service
 }
 
+// SetRateLimitCounter moves attempts and lockouts onto counter, e.g. a
+// Postgres counter shared by all instances. Attempts are then limited over a
+// sliding window, and a lockout lasts until the end of the lockout window
+// after the one it started in.
+func (r *RateLimitService) SetRateLimitCounter(counter ratelimit.Counter) {
+	if counter == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.counter = counter
+	r.loginAttempts = make(map[string]*AttemptTracker)
+	r.refreshAttempts = make(map[string]*AttemptTracker)
+}
+
+// sharedCounter returns the shared counter, or nil when attempts are kept in
+// memory.
+func (r *RateLimitService) sharedCounter() ratelimit.Counter {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.counter
+}
+
 // AllowLogin checks if a login attempt is allowed for the given client/user combination
 func (r *RateLimitService) AllowLogin(clientIP, identifier string) bool {
+	if counter := r.sharedCounter(); counter != nil {
+		key := fmt.Sprintf("login:%s:%s", clientIP, identifier)
+		return allowShared(counter, key, r.maxLoginAttempts, r.loginWindowDuration, r.lockoutDuration)
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -120,6 +156,11 @@ func (r *RateLimitService) AllowLogin(clientIP, identifier string) bool {
 
 // AllowRefresh checks if a refresh token attempt is allowed for the given client
 func (r *RateLimitService) AllowRefresh(clientIP string) bool {
+	if counter := r.sharedCounter(); counter != nil {
+		key := fmt.Sprintf("refresh:%s", clientIP)
+		return allowShared(counter, key, r.maxRefreshAttempts, r.refreshWindowDuration, r.refreshWindowDuration)
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -166,6 +207,18 @@ func (r *RateLimitService) AllowRefresh(clientIP string) bool {
 
 // CheckLoginAttempt checks if login attempt is allowed and returns retry duration if blocked
 func (r *RateLimitService) CheckLoginAttempt(clientIP, identifier string) (bool, time.Duration) {
+	if counter := r.sharedCounter(); counter != nil {
+		key := fmt.Sprintf("login:%s:%s", clientIP, identifier)
+		if locked, remaining := sharedLockout(counter, key, r.lockoutDuration); locked {
+			return false, remaining
+		}
+		w, ok := peekShared(counter, key, r.loginWindowDuration)
+		if ok && w.Estimate(time.Now()) >= float64(r.maxLoginAttempts) {
+			return false, time.Until(w.End())
+		}
+		return true, 0
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -200,6 +253,12 @@ func (r *RateLimitService) CheckLoginAttempt(clientIP, identifier string) (bool,
 
 // ResetLoginAttempts resets login attempts for successful authentication
 func (r *RateLimitService) ResetLoginAttempts(clientIP, identifier string) {
+	if counter := r.sharedCounter(); counter != nil {
+		key := fmt.Sprintf("login:%s:%s", clientIP, identifier)
+		resetShared(counter, key, lockoutKey(key))
+		return
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -220,6 +279,10 @@ func (r *RateLimitService) RecordSuccessfulLogin(clientIP, identifier string) {
 
 // IsLocked checks if a client/identifier combination is currently locked
 func (r *RateLimitService) IsLocked(clientIP, identifier string) (bool, time.Duration) {
+	if counter := r.sharedCounter(); counter != nil {
+		return sharedLockout(counter, fmt.Sprintf("login:%s:%s", clientIP, identifier), r.lockoutDuration)
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -241,6 +304,22 @@ func (r *RateLimitService) IsLocked(clientIP, identifier string) (bool, time.Dur
 
 // GetRemainingAttempts returns the number of remaining login attempts
 func (r *RateLimitService) GetRemainingAttempts(clientIP, identifier string) int {
+	if counter := r.sharedCounter(); counter != nil {
+		key := fmt.Sprintf("login:%s:%s", clientIP, identifier)
+		if locked, _ := sharedLockout(counter, key, r.lockoutDuration); locked {
+			return 0
+		}
+		w, ok := peekShared(counter, key, r.loginWindowDuration)
+		if !ok {
+			return r.maxLoginAttempts
+		}
+		remaining := r.maxLoginAttempts - int(math.Ceil(w.Estimate(time.Now())))
+		if remaining < 0 {
+			remaining = 0
+		}
+		return remaining
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -271,12 +350,17 @@ func (r *RateLimitService) GetRemainingAttempts(clientIP, identifier string) int
 
 // ClearAttempts manually clears attempts for a client/identifier (admin function)
 func (r *RateLimitService) ClearAttempts(clientIP, identifier string) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
 	loginKey := fmt.Sprintf("login:%s:%s", clientIP, identifier)
 	refreshKey := fmt.Sprintf("refresh:%s", clientIP)
 
+	if counter := r.sharedCounter(); counter != nil {
+		resetShared(counter, loginKey, lockoutKey(loginKey), refreshKey, lockoutKey(refreshKey))
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	delete(r.loginAttempts, loginKey)
 	delete(r.refreshAttempts, refreshKey)
 }
@@ -297,7 +381,10 @@ func (r *RateLimitService) GetStats() map[string]interface{} {
 		}
 	}
 
+	// With a shared counter the attempts live outside this instance, so only
+	// the configuration is reported.
 	return map[string]interface{}{
+		"shared_counter":           r.counter != nil,
 		"total_tracked_ips":        len(r.loginAttempts),
 		"currently_locked":         lockedCount,
 		"total_attempts":           totalAttempts,
@@ -341,3 +428,77 @@ func (r *RateLimitService) cleanupExpired() {
 		r.mu.Unlock()
 	}
 }
+
+// sharedCounterTimeout bounds each shared counter call so a slow database
+// cannot stall authentication.
+const sharedCounterTimeout = 2 * time.Second
+
+// lockoutKey is the shared counter key that marks key as locked out.
+func lockoutKey(key string) string {
+	return "lockout:" + key
+}
+
+// allowShared counts one attempt for key and locks it out once the sliding
+// window estimate exceeds limit. Counter failures allow the attempt and are
+// logged.
+func allowShared(counter ratelimit.Counter, key string, limit int, window, lockout time.Duration) bool {
+	if locked, _ := sharedLockout(counter, key, lockout); locked {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sharedCounterTimeout)
+	defer cancel()
+
+	allowed, _, err := ratelimit.Allow(ctx, counter, key, limit, window)
+	if err != nil {
+		log.Printf("auth rate limit unavailable: %v", err)
+		return true
+	}
+	if !allowed {
+		if _, err := counter.Hit(ctx, lockoutKey(key), 1, lockout); err != nil {
+			log.Printf("auth rate limit lockout failed for %s: %v", key, err)
+		}
+	}
+	return allowed
+}
+
+// sharedLockout reports whether key is locked out and for how long. A lockout
+// set in one lockout window holds until the end of the next one.
+func sharedLockout(counter ratelimit.Counter, key string, lockout time.Duration) (bool, time.Duration) {
+	w, ok := peekShared(counter, lockoutKey(key), lockout)
+	if !ok {
+		return false, 0
+	}
+	switch {
+	case w.Count > 0:
+		return true, time.Until(w.End().Add(w.Length))
+	case w.Previous > 0:
+		return true, time.Until(w.End())
+	default:
+		return false, 0
+	}
+}
+
+// peekShared reads key's window. ok is false when the counter failed.
+func peekShared(counter ratelimit.Counter, key string, window time.Duration) (ratelimit.Window, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), sharedCounterTimeout)
+	defer cancel()
+
+	w, err := counter.Peek(ctx, key, window)
+	if err != nil {
+		log.Printf("auth rate limit unavailable: %v", err)
+		return ratelimit.Window{}, false
+	}
+	return w, true
+}
+
+func resetShared(counter ratelimit.Counter, keys ...string) {
+	ctx, cancel := context.WithTimeout(context.Background(), sharedCounterTimeout)
+	defer cancel()
+
+	for _, key := range keys {
+		if err := counter.Reset(ctx, key); err != nil {
+			log.Printf("auth rate limit reset failed for %s: %v", key, err)
+		}
+	}
+}
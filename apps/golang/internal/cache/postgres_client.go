@@ -0,0 +1,212 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Drivers accepted by NewStore.
+const (
+	DriverMemory   = "memory"
+	DriverPostgres = "postgres"
+)
+
+// Store is a CacheClient that owns background cleanup and must be closed.
+type Store interface {
+	CacheClient
+	Close() error
+}
+
+// NewStore creates the cache selected by driver. db is only used by the
+// postgres driver; an empty driver selects memory. MaxEntries only applies
+// to the memory driver.
+func NewStore(driver string, db *gorm.DB, config MemoryConfig) (Store, error) {
+	switch strings.ToLower(strings.TrimSpace(driver)) {
+	case "", DriverMemory:
+		return NewMemoryClient(config)
+	case DriverPostgres:
+		return NewPostgresClient(db, config)
+	default:
+		return nil, fmt.Errorf("cache: unknown driver %q", driver)
+	}
+}
+
+// PostgresClient stores entries in the UNLOGGED cache_entries table so all
+// instances share one cache. Entries are lost if Postgres crashes, which
+// only costs cache misses.
+type PostgresClient struct {
+	db               *gorm.DB
+	config           MemoryConfig
+	metricsCollector *MetricsCollector
+
+	stopCleanup chan struct{}
+	cleanupDone chan struct{}
+	closeOnce   sync.Once
+}
+
+var _ Store = (*PostgresClient)(nil)
+
+// NewPostgresClient creates a cache client backed by db and starts removing
+// expired entries every CleanupInterval.
+func NewPostgresClient(db *gorm.DB, config MemoryConfig) (*PostgresClient, error) {
+	if db == nil {
+		return nil, errors.New("cache: postgres driver requires a database")
+	}
+	if config.CleanupInterval == 0 {
+		config.CleanupInterval = 1 * time.Minute
+	}
+
+	client := &PostgresClient{
+		db:               db,
+		config:           config,
+		metricsCollector: NewMetricsCollector(),
+		stopCleanup:      make(chan struct{}),
+		cleanupDone:      make(chan struct{}),
+	}
+	go client.cleanupLoop()
+
+	return client, nil
+}
+
+func (p *PostgresClient) cleanupLoop() {
+	defer close(p.cleanupDone)
+
+	ticker := time.NewTicker(p.config.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.db.Exec(`DELETE FROM cache_entries WHERE expires_at < NOW()`).Error; err != nil {
+				log.Printf("cache: failed to remove expired entries: %v", err)
+			}
+		case <-p.stopCleanup:
+			return
+		}
+	}
+}
+
+// prefixKey adds the configured prefix to a key
+func (p *PostgresClient) prefixKey(key string) string {
+	if p.config.KeyPrefix == "" {
+		return key
+	}
+	return p.config.KeyPrefix + ":" + key
+}
+
+// Get retrieves a value from cache
+func (p *PostgresClient) Get(ctx context.Context, key string) (string, error) {
+	startTime := time.Now()
+	defer func() {
+		p.metricsCollector.RecordOperation("GET", time.Since(startTime))
+	}()
+
+	var values []string
+	err := p.db.WithContext(ctx).
+		Raw(`SELECT value FROM cache_entries WHERE key = ? AND (expires_at IS NULL OR expires_at > NOW())`, p.prefixKey(key)).
+		Scan(&values).Error
+	if err != nil {
+		return "", fmt.Errorf("cache: get %s: %w", key, err)
+	}
+	if len(values) == 0 {
+		p.metricsCollector.RecordCacheMiss()
+		return "", ErrCacheMiss
+	}
+
+	p.metricsCollector.RecordCacheHit()
+	return values[0], nil
+}
+
+// Set stores a value in cache
+func (p *PostgresClient) Set(ctx context.Context, key string, value string, expiration time.Duration) error {
+	startTime := time.Now()
+	defer func() {
+		p.metricsCollector.RecordOperation("SET", time.Since(startTime))
+	}()
+
+	var expiresAt *time.Time
+	if expiration > 0 {
+		t := time.Now().Add(expiration)
+		expiresAt = &t
+	}
+
+	err := p.db.WithContext(ctx).Exec(`
+		INSERT INTO cache_entries (key, value, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at`,
+		p.prefixKey(key), value, expiresAt).Error
+	if err != nil {
+		return fmt.Errorf("cache: set %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes a value from cache
+func (p *PostgresClient) Delete(ctx context.Context, key string) error {
+	startTime := time.Now()
+	defer func() {
+		p.metricsCollector.RecordOperation("DELETE", time.Since(startTime))
+	}()
+
+	if err := p.db.WithContext(ctx).Exec(`DELETE FROM cache_entries WHERE key = ?`, p.prefixKey(key)).Error; err != nil {
+		return fmt.Errorf("cache: delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// DeletePattern deletes all keys matching a pattern (supports * wildcard)
+func (p *PostgresClient) DeletePattern(ctx context.Context, pattern string) error {
+	startTime := time.Now()
+	defer func() {
+		p.metricsCollector.RecordOperation("DELETE_PATTERN", time.Since(startTime))
+	}()
+
+	err := p.db.WithContext(ctx).
+		Exec(`DELETE FROM cache_entries WHERE key LIKE ? ESCAPE '\'`, likePattern(p.prefixKey(pattern))).Error
+	if err != nil {
+		return fmt.Errorf("cache: delete pattern %s: %w", pattern, err)
+	}
+	return nil
+}
+
+// FlushDB clears all entries under the configured prefix
+func (p *PostgresClient) FlushDB(ctx context.Context) error {
+	db := p.db.WithContext(ctx)
+	var err error
+	if p.config.KeyPrefix == "" {
+		err = db.Exec(`DELETE FROM cache_entries`).Error
+	} else {
+		err = db.Exec(`DELETE FROM cache_entries WHERE key LIKE ? ESCAPE '\'`, likePattern(p.prefixKey("*"))).Error
+	}
+	if err != nil {
+		return fmt.Errorf("cache: flush: %w", err)
+	}
+	return nil
+}
+
+// GetMetrics returns cache metrics
+func (p *PostgresClient) GetMetrics() CacheMetrics {
+	return p.metricsCollector.GetMetrics()
+}
+
+// Close stops the cleanup goroutine
+func (p *PostgresClient) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.stopCleanup)
+		<-p.cleanupDone
+	})
+	return nil
+}
+
+// likePattern converts a * wildcard pattern into a LIKE pattern, escaping
+// LIKE's own wildcards so they match literally.
+func likePattern(pattern string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(pattern)
+	return strings.ReplaceAll(escaped, "*", "%")
+}
@@ -0,0 +1,31 @@
+package cache
+
+import "testing"
+
+func TestLikePattern(t *testing.T) {
+	cases := map[string]string{
+		"resolver:*":          "resolver:%",
+		"user:*:harvest_list": "user:%:harvest\\_list",
+		"100%":                "100\\%",
+		`a\b`:                 `a\\b`,
+	}
+	for pattern, want := range cases {
+		if got := likePattern(pattern); got != want {
+			t.Errorf("likePattern(%q) = %q, want %q", pattern, got, want)
+		}
+	}
+}
+
+func TestNewStore_Drivers(t *testing.T) {
+	store, err := NewStore("", nil, MemoryConfig{})
+	if err != nil {
+		t.Fatalf("NewStore(\"\"): %v", err)
+	}
+	store.Close()
+	if _, err := NewStore(DriverPostgres, nil, MemoryConfig{}); err == nil {
+		t.Error("postgres driver without a database should fail")
+	}
+	if _, err := NewStore("redis", nil, MemoryConfig{}); err == nil {
+		t.Error("unknown driver should fail")
+	}
+}
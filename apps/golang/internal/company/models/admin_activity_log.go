@@ -0,0 +1,70 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Activity types mirror the GraphQL AdminActivityType enum.
+const (
+	ActivityUserCreated     = "USER_CREATED"
+	ActivityUserUpdated     = "USER_UPDATED"
+	ActivityUserDeleted     = "USER_DELETED"
+	ActivityUserActivated   = "USER_ACTIVATED"
+	ActivityUserDeactivated = "USER_DEACTIVATED"
+	ActivityPasswordReset   = "PASSWORD_RESET"
+	ActivityRoleChanged     = "ROLE_CHANGED"
+	ActivityEstateCreated   = "ESTATE_CREATED"
+	ActivityDivisionCreated = "DIVISION_CREATED"
+	ActivitySettingsChanged = "SETTINGS_CHANGED"
+	ActivityLoginAttempt    = "LOGIN_ATTEMPT"
+)
+
+// Entity types recorded on activity log entries.
+const (
+	ActivityEntityUser    = "USER"
+	ActivityEntityCompany = "COMPANY"
+)
+
+// AdminActivityLog is a company-scoped audit entry for administrative actions.
+type AdminActivityLog struct {
+	ID           string    `gorm:"column:id;type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CompanyID    string    `gorm:"column:company_id;type:uuid" json:"companyId"`
+	Type         string    `gorm:"column:activity_type" json:"type"`
+	ActorID      string    `gorm:"column:actor_id;type:uuid" json:"actorId"`
+	ActorName    string    `gorm:"column:actor_name" json:"actorName"`
+	Description  string    `gorm:"column:description" json:"description"`
+	EntityType   *string   `gorm:"column:entity_type" json:"entityType,omitempty"`
+	EntityID     *string   `gorm:"column:entity_id" json:"entityId,omitempty"`
+	IPAddress    *string   `gorm:"column:ip_address" json:"ipAddress,omitempty"`
+	MetadataJSON *string   `gorm:"column:metadata_json" json:"-"`
+	CreatedAt    time.Time `gorm:"column:created_at" json:"createdAt"`
+}
+
+// TableName returns the table name for AdminActivityLog
+func (AdminActivityLog) TableName() string {
+	return "admin_activity_logs"
+}
+
+// SetMetadata encodes additional details for storage. Empty metadata is dropped.
+func (l *AdminActivityLog) SetMetadata(metadata map[string]interface{}) {
+	if len(metadata) == 0 {
+		l.MetadataJSON = nil
+		return
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return
+	}
+	value := string(encoded)
+	l.MetadataJSON = &value
+}
+
+// AdminActivityFilter narrows activity log queries.
+type AdminActivityFilter struct {
+	Type     *string
+	UserID   *string
+	DateFrom *time.Time
+	DateTo   *time.Time
+	Limit    int
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Plan types mirror the GraphQL PlanType enum.
+const (
+	PlanTrial      = "TRIAL"
+	PlanBasic      = "BASIC"
+	PlanStandard   = "STANDARD"
+	PlanPremium    = "PREMIUM"
+	PlanEnterprise = "ENTERPRISE"
+	PlanCustom     = "CUSTOM"
+)
+
+const bytesPerGB = 1024 * 1024 * 1024
+
+// CompanyPlan stores a tenant's subscription plan and quota limits. A zero
+// limit means "unlimited" so companies created before plans existed keep
+// working without a row.
+type CompanyPlan struct {
+	CompanyID string `gorm:"column:company_id;type:uuid;primaryKey" json:"companyId"`
+
+	PlanType    string     `gorm:"column:plan_type" json:"planType"`
+	PlanName    string     `gorm:"column:plan_name" json:"planName"`
+	StartDate   time.Time  `gorm:"column:start_date" json:"startDate"`
+	EndDate     *time.Time `gorm:"column:end_date" json:"endDate,omitempty"`
+	TrialEndsAt *time.Time `gorm:"column:trial_ends_at" json:"trialEndsAt,omitempty"`
+
+	MaxUsers            int     `gorm:"column:max_users" json:"maxUsers"`
+	MaxEstates          int     `gorm:"column:max_estates" json:"maxEstates"`
+	MaxStorageGB        float64 `gorm:"column:max_storage_gb" json:"maxStorageGb"`
+	FeaturesEnabledJSON string  `gorm:"column:features_enabled_json" json:"-"`
+	StorageUsedBytes    int64   `gorm:"column:storage_used_bytes" json:"storageUsedBytes"`
+
+	SuspendedAt     *time.Time `gorm:"column:suspended_at" json:"suspendedAt,omitempty"`
+	SuspendedReason *string    `gorm:"column:suspended_reason" json:"suspendedReason,omitempty"`
+	SuspendedBy     *string    `gorm:"column:suspended_by;type:uuid" json:"suspendedBy,omitempty"`
+
+	UpdatedBy *string   `gorm:"column:updated_by;type:uuid" json:"updatedBy,omitempty"`
+	CreatedAt time.Time `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName returns the table name for CompanyPlan
+func (CompanyPlan) TableName() string {
+	return "company_plans"
+}
+
+// DefaultCompanyPlan returns the unlimited plan used for companies without a row.
+func DefaultCompanyPlan(companyID string, since time.Time) *CompanyPlan {
+	return &CompanyPlan{
+		CompanyID:           companyID,
+		PlanType:            PlanEnterprise,
+		PlanName:            "Enterprise",
+		StartDate:           since,
+		FeaturesEnabledJSON: "[]",
+	}
+}
+
+// FeaturesEnabled decodes the enabled feature keys.
+func (p *CompanyPlan) FeaturesEnabled() []string {
+	if p == nil || strings.TrimSpace(p.FeaturesEnabledJSON) == "" {
+		return []string{}
+	}
+	var features []string
+	if err := json.Unmarshal([]byte(p.FeaturesEnabledJSON), &features); err != nil {
+		return []string{}
+	}
+	return features
+}
+
+// SetFeaturesEnabled encodes the enabled feature keys for storage.
+func (p *CompanyPlan) SetFeaturesEnabled(features []string) {
+	if features == nil {
+		features = []string{}
+	}
+	encoded, _ := json.Marshal(features)
+	p.FeaturesEnabledJSON = string(encoded)
+}
+
+// IsTrial reports whether the company is on a trial plan.
+func (p *CompanyPlan) IsTrial() bool {
+	return p != nil && p.PlanType == PlanTrial
+}
+
+// TrialExpired reports whether a trial plan has passed its expiry.
+func (p *CompanyPlan) TrialExpired(now time.Time) bool {
+	return p.IsTrial() && p.TrialEndsAt != nil && now.After(*p.TrialEndsAt)
+}
+
+// MaxStorageBytes converts the GB limit to bytes. Zero means unlimited.
+func (p *CompanyPlan) MaxStorageBytes() int64 {
+	if p == nil || p.MaxStorageGB <= 0 {
+		return 0
+	}
+	return int64(p.MaxStorageGB * bytesPerGB)
+}
+
+// StorageUsedGB returns the tracked storage usage in GB.
+func (p *CompanyPlan) StorageUsedGB() float64 {
+	if p == nil {
+		return 0
+	}
+	return float64(p.StorageUsedBytes) / bytesPerGB
+}
+
+// CompanyUsage reports current consumption against plan limits.
+type CompanyUsage struct {
+	CurrentUsers   int
+	CurrentEstates int
+	StorageUsedGB  float64
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CompanySettings stores per-company configuration that is enforced by the
+// auth, middleware and harvest layers. A zero value on numeric limits means
+// "disabled" so companies without a row keep the legacy behaviour.
+type CompanySettings struct {
+	CompanyID string `gorm:"column:company_id;type:uuid;primaryKey" json:"companyId"`
+
+	// General
+	Timezone   string `gorm:"column:timezone" json:"timezone"`
+	DateFormat string `gorm:"column:date_format" json:"dateFormat"`
+	Currency   string `gorm:"column:currency" json:"currency"`
+	Language   string `gorm:"column:language" json:"language"`
+
+	// Notifications
+	EmailEnabled          bool    `gorm:"column:email_enabled" json:"emailEnabled"`
+	PushEnabled           bool    `gorm:"column:push_enabled" json:"pushEnabled"`
+	SmsEnabled            bool    `gorm:"column:sms_enabled" json:"smsEnabled"`
+	DailyReportEnabled    bool    `gorm:"column:daily_report_enabled" json:"dailyReportEnabled"`
+	ProductionBelowTarget float64 `gorm:"column:production_below_target" json:"productionBelowTarget"`
+	QualityBelowThreshold float64 `gorm:"column:quality_below_threshold" json:"qualityBelowThreshold"`
+	PendingApprovalHours  int     `gorm:"column:pending_approval_hours" json:"pendingApprovalHours"`
+
+	// Security
+	SessionTimeoutMinutes int    `gorm:"column:session_timeout_minutes" json:"sessionTimeout"`
+	MaxFailedLogins       int    `gorm:"column:max_failed_logins" json:"maxFailedLogins"`
+	PasswordExpiryDays    int    `gorm:"column:password_expiry_days" json:"passwordExpiryDays"`
+	TwoFactorRequired     bool   `gorm:"column:two_factor_required" json:"twoFactorRequired"`
+	AllowedIPRangesJSON   string `gorm:"column:allowed_ip_ranges_json" json:"-"`
+
+	// Operational
+	DefaultShiftStart      string   `gorm:"column:default_shift_start" json:"defaultShiftStart"`
+	DefaultShiftEnd        string   `gorm:"column:default_shift_end" json:"defaultShiftEnd"`
+	AutoApproveThreshold   *float64 `gorm:"column:auto_approve_threshold" json:"autoApproveThreshold,omitempty"`
+	RequireGpsForHarvest   bool     `gorm:"column:require_gps_for_harvest" json:"requireGpsForHarvest"`
+	RequirePhotoForHarvest bool     `gorm:"column:require_photo_for_harvest" json:"requirePhotoForHarvest"`
+
+	UpdatedBy *string   `gorm:"column:updated_by;type:uuid" json:"updatedBy,omitempty"`
+	CreatedAt time.Time `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName returns the table name for CompanySettings
+func (CompanySettings) TableName() string {
+	return "company_settings"
+}
+
+// DefaultCompanySettings returns the settings used when a company has not
+// saved any configuration yet. Values mirror the column defaults.
+func DefaultCompanySettings(companyID string) *CompanySettings {
+	return &CompanySettings{
+		CompanyID:             companyID,
+		Timezone:              "Asia/Jakarta",
+		DateFormat:            "DD/MM/YYYY",
+		Currency:              "IDR",
+		Language:              "id",
+		PushEnabled:           true,
+		DailyReportEnabled:    true,
+		ProductionBelowTarget: 80,
+		QualityBelowThreshold: 90,
+		AllowedIPRangesJSON:   "[]",
+		DefaultShiftStart:     "06:00",
+		DefaultShiftEnd:       "14:00",
+	}
+}
+
+// AllowedIPRanges decodes the stored CIDR/IP allow-list. An undecodable
+// list is reported as empty; use DecodeAllowedIPRanges when enforcing it.
+func (s *CompanySettings) AllowedIPRanges() []string {
+	ranges, err := s.DecodeAllowedIPRanges()
+	if err != nil {
+		return []string{}
+	}
+	return ranges
+}
+
+// DecodeAllowedIPRanges decodes the stored CIDR/IP allow-list and fails when
+// the stored JSON is corrupt.
+func (s *CompanySettings) DecodeAllowedIPRanges() ([]string, error) {
+	if s == nil || strings.TrimSpace(s.AllowedIPRangesJSON) == "" {
+		return []string{}, nil
+	}
+	var ranges []string
+	if err := json.Unmarshal([]byte(s.AllowedIPRangesJSON), &ranges); err != nil {
+		return nil, fmt.Errorf("invalid allowed IP ranges: %w", err)
+	}
+	return ranges, nil
+}
+
+// SetAllowedIPRanges encodes the CIDR/IP allow-list for storage.
+func (s *CompanySettings) SetAllowedIPRanges(ranges []string) {
+	if ranges == nil {
+		ranges = []string{}
+	}
+	encoded, _ := json.Marshal(ranges)
+	s.AllowedIPRangesJSON = string(encoded)
+}
+
+// SessionTimeout returns the configured session timeout, or zero when unset.
+func (s *CompanySettings) SessionTimeout() time.Duration {
+	if s == nil || s.SessionTimeoutMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(s.SessionTimeoutMinutes) * time.Minute
+}
@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// CompanyUser is the company admin read model of a user, including the
+// names of the estates and divisions they are assigned to.
+type CompanyUser struct {
+	ID            string
+	Username      string
+	Name          string
+	Email         *string
+	Phone         *string
+	Role          string
+	IsActive      bool
+	IsOnline      bool
+	LastLogin     *time.Time
+	LockedUntil   *time.Time
+	EstateNames   []string
+	DivisionNames []string
+	CreatedAt     time.Time
+}
+
+// IsLocked reports whether the account is locked by failed logins at now.
+func (u *CompanyUser) IsLocked(now time.Time) bool {
+	return u != nil && u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
+// CompanyUserFilter narrows company user listings. Page is 1-based.
+type CompanyUserFilter struct {
+	Role       *string
+	EstateID   *string
+	DivisionID *string
+	ActiveOnly bool
+	Search     *string
+	Page       int
+	PageSize   int
+}
+
+// RoleUserCount is the per-role breakdown of company users.
+type RoleUserCount struct {
+	Role   string
+	Count  int
+	Active int
+}
+
+// CompanyUserOverview summarizes the users of a company.
+type CompanyUserOverview struct {
+	Total            int
+	ByRole           []RoleUserCount
+	ActiveToday      int
+	NewThisMonth     int
+	PendingApprovals int
+	LockedAccounts   int
+}
+
+// CompanyAdminStats aggregates structure and production figures for the
+// company admin dashboard. Production is in kilograms.
+type CompanyAdminStats struct {
+	TotalUsers        int
+	ActiveUsers       int
+	UsersOnlineNow    int
+	TotalEstates      int
+	TotalDivisions    int
+	TotalBlocks       int
+	TotalEmployees    int
+	TodayProduction   float64
+	MonthlyProduction float64
+}
+
+// EstateOverview is the per-estate row of the company admin dashboard.
+type EstateOverview struct {
+	EstateID        string
+	EstateName      string
+	ManagerName     *string
+	DivisionsCount  int
+	UsersCount      int
+	TodayProduction float64
+}
@@ -0,0 +1,389 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"agrinovagraphql/server/internal/company/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// companySettingsCacheTTL bounds how long a settings row is served from memory.
+// Settings are read on every authenticated request (IP allow-list) and every
+// harvest write, so a short cache avoids a DB round-trip per call.
+const companySettingsCacheTTL = 30 * time.Second
+
+var shiftTimePattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+type cachedCompanySettings struct {
+	settings  *models.CompanySettings
+	expiresAt time.Time
+}
+
+// settingsCache is shared by every CompanySettingsService in the process so
+// that an update made through the GraphQL resolver is visible to the auth
+// middleware and harvest services immediately.
+var settingsCache = struct {
+	sync.RWMutex
+	entries map[string]cachedCompanySettings
+}{entries: make(map[string]cachedCompanySettings)}
+
+// CompanySettingsService manages per-company configuration.
+type CompanySettingsService struct {
+	db *gorm.DB
+
+	tableCheck  sync.Once
+	tableExists bool
+}
+
+// CompanySettingsUpdate carries a partial settings update. Nil fields are left unchanged.
+type CompanySettingsUpdate struct {
+	Timezone   *string
+	DateFormat *string
+	Currency   *string
+	Language   *string
+
+	EmailEnabled          *bool
+	PushEnabled           *bool
+	SmsEnabled            *bool
+	DailyReportEnabled    *bool
+	ProductionBelowTarget *float64
+	QualityBelowThreshold *float64
+	PendingApprovalHours  *int
+
+	SessionTimeoutMinutes *int
+	MaxFailedLogins       *int
+	PasswordExpiryDays    *int
+	TwoFactorRequired     *bool
+	AllowedIPRanges       []string
+
+	DefaultShiftStart      *string
+	DefaultShiftEnd        *string
+	AutoApproveThreshold   *float64
+	RequireGpsForHarvest   *bool
+	RequirePhotoForHarvest *bool
+}
+
+// NewCompanySettingsService creates a new company settings service
+func NewCompanySettingsService(db *gorm.DB) *CompanySettingsService {
+	return &CompanySettingsService{db: db}
+}
+
+// GetSettings returns the stored settings for a company, falling back to defaults
+// when the company has not saved any configuration.
+func (s *CompanySettingsService) GetSettings(ctx context.Context, companyID string) (*models.CompanySettings, error) {
+	companyID = strings.TrimSpace(companyID)
+	if companyID == "" {
+		return nil, fmt.Errorf("company ID is required")
+	}
+
+	if cached := getCachedSettings(companyID); cached != nil {
+		return cached, nil
+	}
+
+	// Databases that predate migration 000076 behave as if every company used defaults.
+	if !s.hasSettingsTable() {
+		return models.DefaultCompanySettings(companyID), nil
+	}
+
+	var rows []models.CompanySettings
+	if err := s.db.WithContext(ctx).
+		Where("company_id = ?", companyID).
+		Limit(1).
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load company settings: %w", err)
+	}
+
+	settings := models.DefaultCompanySettings(companyID)
+	if len(rows) > 0 {
+		settings = &rows[0]
+	}
+
+	putCachedSettings(settings)
+	return cloneSettings(settings), nil
+}
+
+func (s *CompanySettingsService) hasSettingsTable() bool {
+	s.tableCheck.Do(func() {
+		s.tableExists = s.db.Migrator().HasTable(&models.CompanySettings{})
+	})
+	return s.tableExists
+}
+
+// UpdateSettings validates and persists a partial settings update.
+func (s *CompanySettingsService) UpdateSettings(
+	ctx context.Context,
+	companyID string,
+	actorID string,
+	update *CompanySettingsUpdate,
+) (*models.CompanySettings, error) {
+	if update == nil {
+		return s.GetSettings(ctx, companyID)
+	}
+
+	settings, err := s.GetSettings(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applySettingsUpdate(settings, update); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	settings.UpdatedAt = now
+	if settings.CreatedAt.IsZero() {
+		settings.CreatedAt = now
+	}
+	if actor := strings.TrimSpace(actorID); actor != "" {
+		settings.UpdatedBy = &actor
+	}
+
+	if err := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "company_id"}},
+			UpdateAll: true,
+		}).
+		Create(settings).Error; err != nil {
+		return nil, fmt.Errorf("failed to save company settings: %w", err)
+	}
+
+	InvalidateCompanySettingsCache(settings.CompanyID)
+	return settings, nil
+}
+
+// IsIPAllowed reports whether ipAddress falls inside the company allow-list.
+// An empty allow-list permits every address; a corrupt one is an error.
+func (s *CompanySettingsService) IsIPAllowed(ctx context.Context, companyID string, ipAddress string) (bool, error) {
+	if strings.TrimSpace(companyID) == "" {
+		return true, nil
+	}
+
+	settings, err := s.GetSettings(ctx, companyID)
+	if err != nil {
+		return false, err
+	}
+
+	ranges, err := settings.DecodeAllowedIPRanges()
+	if err != nil {
+		return false, fmt.Errorf("company %s: %w", companyID, err)
+	}
+	return IPInRanges(ipAddress, ranges), nil
+}
+
+// ListPendingApprovalEscalations returns companies that enabled pending approval escalation.
+func (s *CompanySettingsService) ListPendingApprovalEscalations(ctx context.Context) ([]*models.CompanySettings, error) {
+	var rows []*models.CompanySettings
+	if err := s.db.WithContext(ctx).
+		Where("pending_approval_hours > 0").
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list pending approval escalation settings: %w", err)
+	}
+	return rows, nil
+}
+
+// InvalidateCompanySettingsCache drops any cached settings for a company.
+func InvalidateCompanySettingsCache(companyID string) {
+	settingsCache.Lock()
+	delete(settingsCache.entries, companyID)
+	settingsCache.Unlock()
+}
+
+// IPInRanges reports whether ipAddress matches any CIDR or single IP in ranges.
+// An empty range list matches everything.
+func IPInRanges(ipAddress string, ranges []string) bool {
+	if len(ranges) == 0 {
+		return true
+	}
+
+	host := strings.TrimSpace(ipAddress)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, entry := range ranges {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			if _, network, err := net.ParseCIDR(entry); err == nil && network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if allowed := net.ParseIP(entry); allowed != nil && allowed.Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func applySettingsUpdate(settings *models.CompanySettings, update *CompanySettingsUpdate) error {
+	if update.Timezone != nil {
+		timezone := strings.TrimSpace(*update.Timezone)
+		if _, err := time.LoadLocation(timezone); err != nil || timezone == "" {
+			return fmt.Errorf("invalid timezone: %s", *update.Timezone)
+		}
+		settings.Timezone = timezone
+	}
+	if update.DateFormat != nil && strings.TrimSpace(*update.DateFormat) != "" {
+		settings.DateFormat = strings.TrimSpace(*update.DateFormat)
+	}
+	if update.Currency != nil && strings.TrimSpace(*update.Currency) != "" {
+		settings.Currency = strings.ToUpper(strings.TrimSpace(*update.Currency))
+	}
+	if update.Language != nil && strings.TrimSpace(*update.Language) != "" {
+		settings.Language = strings.ToLower(strings.TrimSpace(*update.Language))
+	}
+
+	if update.EmailEnabled != nil {
+		settings.EmailEnabled = *update.EmailEnabled
+	}
+	if update.PushEnabled != nil {
+		settings.PushEnabled = *update.PushEnabled
+	}
+	if update.SmsEnabled != nil {
+		settings.SmsEnabled = *update.SmsEnabled
+	}
+	if update.DailyReportEnabled != nil {
+		settings.DailyReportEnabled = *update.DailyReportEnabled
+	}
+	if update.ProductionBelowTarget != nil {
+		if *update.ProductionBelowTarget < 0 || *update.ProductionBelowTarget > 100 {
+			return fmt.Errorf("productionBelowTarget must be between 0 and 100")
+		}
+		settings.ProductionBelowTarget = *update.ProductionBelowTarget
+	}
+	if update.QualityBelowThreshold != nil {
+		if *update.QualityBelowThreshold < 0 || *update.QualityBelowThreshold > 100 {
+			return fmt.Errorf("qualityBelowThreshold must be between 0 and 100")
+		}
+		settings.QualityBelowThreshold = *update.QualityBelowThreshold
+	}
+	if update.PendingApprovalHours != nil {
+		if *update.PendingApprovalHours < 0 {
+			return fmt.Errorf("pendingApprovalHours cannot be negative")
+		}
+		settings.PendingApprovalHours = *update.PendingApprovalHours
+	}
+
+	if update.SessionTimeoutMinutes != nil {
+		if *update.SessionTimeoutMinutes < 0 {
+			return fmt.Errorf("sessionTimeout cannot be negative")
+		}
+		settings.SessionTimeoutMinutes = *update.SessionTimeoutMinutes
+	}
+	if update.MaxFailedLogins != nil {
+		if *update.MaxFailedLogins < 0 {
+			return fmt.Errorf("maxFailedLogins cannot be negative")
+		}
+		settings.MaxFailedLogins = *update.MaxFailedLogins
+	}
+	if update.PasswordExpiryDays != nil {
+		if *update.PasswordExpiryDays < 0 {
+			return fmt.Errorf("passwordExpiryDays cannot be negative")
+		}
+		settings.PasswordExpiryDays = *update.PasswordExpiryDays
+	}
+	if update.TwoFactorRequired != nil {
+		settings.TwoFactorRequired = *update.TwoFactorRequired
+	}
+	if update.AllowedIPRanges != nil {
+		ranges := make([]string, 0, len(update.AllowedIPRanges))
+		for _, entry := range update.AllowedIPRanges {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+			if strings.Contains(entry, "/") {
+				if _, _, err := net.ParseCIDR(entry); err != nil {
+					return fmt.Errorf("invalid IP range: %s", entry)
+				}
+			} else if net.ParseIP(entry) == nil {
+				return fmt.Errorf("invalid IP address: %s", entry)
+			}
+			ranges = append(ranges, entry)
+		}
+		settings.SetAllowedIPRanges(ranges)
+	}
+
+	if update.DefaultShiftStart != nil {
+		if !shiftTimePattern.MatchString(strings.TrimSpace(*update.DefaultShiftStart)) {
+			return fmt.Errorf("defaultShiftStart must use HH:MM format")
+		}
+		settings.DefaultShiftStart = strings.TrimSpace(*update.DefaultShiftStart)
+	}
+	if update.DefaultShiftEnd != nil {
+		if !shiftTimePattern.MatchString(strings.TrimSpace(*update.DefaultShiftEnd)) {
+			return fmt.Errorf("defaultShiftEnd must use HH:MM format")
+		}
+		settings.DefaultShiftEnd = strings.TrimSpace(*update.DefaultShiftEnd)
+	}
+	if update.AutoApproveThreshold != nil {
+		if *update.AutoApproveThreshold < 0 {
+			return fmt.Errorf("autoApproveThreshold cannot be negative")
+		}
+		if *update.AutoApproveThreshold == 0 {
+			settings.AutoApproveThreshold = nil
+		} else {
+			threshold := *update.AutoApproveThreshold
+			settings.AutoApproveThreshold = &threshold
+		}
+	}
+	if update.RequireGpsForHarvest != nil {
+		settings.RequireGpsForHarvest = *update.RequireGpsForHarvest
+	}
+	if update.RequirePhotoForHarvest != nil {
+		settings.RequirePhotoForHarvest = *update.RequirePhotoForHarvest
+	}
+
+	return nil
+}
+
+func getCachedSettings(companyID string) *models.CompanySettings {
+	settingsCache.RLock()
+	entry, ok := settingsCache.entries[companyID]
+	settingsCache.RUnlock()
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil
+	}
+	return cloneSettings(entry.settings)
+}
+
+func putCachedSettings(settings *models.CompanySettings) {
+	settingsCache.Lock()
+	settingsCache.entries[settings.CompanyID] = cachedCompanySettings{
+		settings:  cloneSettings(settings),
+		expiresAt: time.Now().Add(companySettingsCacheTTL),
+	}
+	settingsCache.Unlock()
+}
+
+func cloneSettings(settings *models.CompanySettings) *models.CompanySettings {
+	if settings == nil {
+		return nil
+	}
+	clone := *settings
+	if settings.AutoApproveThreshold != nil {
+		threshold := *settings.AutoApproveThreshold
+		clone.AutoApproveThreshold = &threshold
+	}
+	if settings.UpdatedBy != nil {
+		updatedBy := *settings.UpdatedBy
+		clone.UpdatedBy = &updatedBy
+	}
+	return &clone
+}
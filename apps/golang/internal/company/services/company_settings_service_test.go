@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"agrinovagraphql/server/internal/company/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPInRanges(t *testing.T) {
+	ranges := []string{"10.0.0.0/8", "203.0.113.7"}
+
+	assert.True(t, IPInRanges("10.1.2.3", ranges))
+	assert.True(t, IPInRanges("203.0.113.7:51234", ranges))
+	assert.False(t, IPInRanges("192.168.1.1", ranges))
+	assert.False(t, IPInRanges("not-an-ip", ranges))
+	assert.True(t, IPInRanges("192.168.1.1", nil), "empty allow-list permits everything")
+}
+
+func TestIsIPAllowed_RejectsCorruptAllowList(t *testing.T) {
+	settings := models.DefaultCompanySettings("company-corrupt-ip-ranges")
+	settings.AllowedIPRangesJSON = "{not json"
+	putCachedSettings(settings)
+	t.Cleanup(func() { InvalidateCompanySettingsCache(settings.CompanyID) })
+
+	allowed, err := (&CompanySettingsService{}).IsIPAllowed(context.Background(), settings.CompanyID, "10.1.2.3")
+	assert.Error(t, err)
+	assert.False(t, allowed)
+}
+
+func TestApplySettingsUpdate(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	strPtr := func(v string) *string { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+
+	t.Run("applies valid values", func(t *testing.T) {
+		settings := models.DefaultCompanySettings("company-1")
+		err := applySettingsUpdate(settings, &CompanySettingsUpdate{
+			Timezone:              strPtr("Asia/Makassar"),
+			MaxFailedLogins:       intPtr(5),
+			AllowedIPRanges:       []string{" 10.0.0.0/8 ", ""},
+			DefaultShiftStart:     strPtr("07:30"),
+			AutoApproveThreshold:  floatPtr(0),
+			SessionTimeoutMinutes: intPtr(45),
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "Asia/Makassar", settings.Timezone)
+		assert.Equal(t, 5, settings.MaxFailedLogins)
+		assert.Equal(t, []string{"10.0.0.0/8"}, settings.AllowedIPRanges())
+		assert.Equal(t, "07:30", settings.DefaultShiftStart)
+		assert.Nil(t, settings.AutoApproveThreshold)
+		assert.Equal(t, int64(45*60), int64(settings.SessionTimeout().Seconds()))
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		cases := map[string]*CompanySettingsUpdate{
+			"timezone":   {Timezone: strPtr("Mars/Olympus")},
+			"ip range":   {AllowedIPRanges: []string{"10.0.0.0/99"}},
+			"shift time": {DefaultShiftEnd: strPtr("25:00")},
+			"negative":   {PasswordExpiryDays: intPtr(-1)},
+		}
+		for name, update := range cases {
+			t.Run(name, func(t *testing.T) {
+				err := applySettingsUpdate(models.DefaultCompanySettings("company-1"), update)
+				assert.Error(t, err)
+			})
+		}
+	})
+}
@@ -0,0 +1,712 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"agrinovagraphql/server/internal/company/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// defaultTrialDays applies when a company is moved to TRIAL without an expiry.
+	defaultTrialDays      = 14
+	maxTrialExtensionDays = 365
+
+	companyAccessCacheTTL = 30 * time.Second
+)
+
+// Tenant plan errors
+var (
+	ErrCompanySuspended   = errors.New("company is suspended, data is read-only. Please contact your administrator")
+	ErrTrialExpired       = errors.New("company trial has expired, data is read-only. Please upgrade the plan")
+	ErrNotOnTrial         = errors.New("company is not on a trial plan")
+	ErrInvalidPlanType    = errors.New("invalid plan type")
+	ErrSuspendReasonEmpty = errors.New("suspension reason is required")
+)
+
+// QuotaExceededError is returned when an operation would exceed a plan limit.
+type QuotaExceededError struct {
+	Resource string
+	Limit    string
+	Current  string
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf(
+		"%s quota exceeded: plan allows %s, currently using %s. Please upgrade the plan",
+		e.Resource,
+		e.Limit,
+		e.Current,
+	)
+}
+
+var validPlanTypes = map[string]struct{}{
+	models.PlanTrial:      {},
+	models.PlanBasic:      {},
+	models.PlanStandard:   {},
+	models.PlanPremium:    {},
+	models.PlanEnterprise: {},
+	models.PlanCustom:     {},
+}
+
+type companyAccess int
+
+const (
+	companyAccessWritable companyAccess = iota
+	companyAccessSuspended
+	companyAccessTrialExpired
+)
+
+func (a companyAccess) err() error {
+	switch a {
+	case companyAccessSuspended:
+		return ErrCompanySuspended
+	case companyAccessTrialExpired:
+		return ErrTrialExpired
+	default:
+		return nil
+	}
+}
+
+type cachedCompanyAccess struct {
+	access    companyAccess
+	expiresAt time.Time
+}
+
+// companyAccessCache memoizes CheckWritable results; it is consulted on every
+// mutation so suspended tenants are rejected without a DB round-trip.
+var companyAccessCache = struct {
+	sync.RWMutex
+	entries map[string]cachedCompanyAccess
+}{entries: make(map[string]cachedCompanyAccess)}
+
+// TenantPlanService manages tenant subscription plans and quota enforcement.
+type TenantPlanService struct {
+	db *gorm.DB
+
+	tableCheck  sync.Once
+	tableExists bool
+}
+
+// PlanUpdate carries a plan change. Nil limits are left unchanged.
+type PlanUpdate struct {
+	PlanType        string
+	MaxUsers        *int
+	MaxEstates      *int
+	MaxStorageGB    *float64
+	TrialDays       *int
+	FeaturesEnabled []string
+}
+
+// NewTenantPlanService creates a new tenant plan service
+func NewTenantPlanService(db *gorm.DB) *TenantPlanService {
+	return &TenantPlanService{db: db}
+}
+
+func (s *TenantPlanService) hasPlanTable() bool {
+	s.tableCheck.Do(func() {
+		s.tableExists = s.db.Migrator().HasTable(&models.CompanyPlan{})
+	})
+	return s.tableExists
+}
+
+// GetPlan returns the company plan, falling back to an unlimited default.
+func (s *TenantPlanService) GetPlan(ctx context.Context, companyID string) (*models.CompanyPlan, error) {
+	companyID = strings.TrimSpace(companyID)
+	if companyID == "" {
+		return nil, fmt.Errorf("company ID is required")
+	}
+
+	db := s.db.WithContext(ctx)
+	plan, err := s.loadPlan(db, companyID, false)
+	if err != nil || plan != nil {
+		return plan, err
+	}
+	return s.defaultPlan(db, companyID)
+}
+
+// loadPlan returns the stored plan row, or nil when the company has none.
+// forUpdate locks the row until the surrounding transaction ends.
+func (s *TenantPlanService) loadPlan(db *gorm.DB, companyID string, forUpdate bool) (*models.CompanyPlan, error) {
+	if !s.hasPlanTable() {
+		return nil, nil
+	}
+
+	query := db.Where("company_id = ?", companyID)
+	if forUpdate {
+		query = query.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	var rows []models.CompanyPlan
+	if err := query.Limit(1).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load company plan: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &rows[0], nil
+}
+
+func (s *TenantPlanService) defaultPlan(db *gorm.DB, companyID string) (*models.CompanyPlan, error) {
+	var createdAt []time.Time
+	if err := db.
+		Table("companies").
+		Where("id = ?", companyID).
+		Limit(1).
+		Pluck("created_at", &createdAt).Error; err != nil {
+		return nil, fmt.Errorf("failed to load company: %w", err)
+	}
+	since := time.Now()
+	if len(createdAt) > 0 {
+		since = createdAt[0]
+	}
+	return models.DefaultCompanyPlan(companyID, since), nil
+}
+
+// GetUsage counts users, estates and tracked storage for a company.
+func (s *TenantPlanService) GetUsage(ctx context.Context, companyID string) (*models.CompanyUsage, error) {
+	usage := &models.CompanyUsage{}
+
+	db := s.db.WithContext(ctx)
+	users, err := countCompanyUsers(db, companyID)
+	if err != nil {
+		return nil, err
+	}
+	usage.CurrentUsers = int(users)
+
+	estates, err := countCompanyEstates(db, companyID)
+	if err != nil {
+		return nil, err
+	}
+	usage.CurrentEstates = int(estates)
+
+	plan, err := s.GetPlan(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	usage.StorageUsedGB = plan.StorageUsedGB()
+
+	return usage, nil
+}
+
+// countCompanyUsers counts active users assigned to the company directly or via
+// an estate/division assignment.
+func countCompanyUsers(db *gorm.DB, companyID string) (int64, error) {
+	var count int64
+	err := db.Raw(`
+		SELECT COUNT(DISTINCT u.id) FROM users u
+		WHERE u.is_active = true AND u.deleted_at IS NULL AND u.id IN (`+companyUserIDsSQL+`)
+	`, companyUserIDsArgs(companyID)...).Scan(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count company users: %w", err)
+	}
+	return count, nil
+}
+
+func countCompanyEstates(db *gorm.DB, companyID string) (int64, error) {
+	var count int64
+	if err := db.Table("estates").Where("company_id = ?", companyID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count estates: %w", err)
+	}
+	return count, nil
+}
+
+// UpdatePlan changes a company's plan and limits.
+func (s *TenantPlanService) UpdatePlan(ctx context.Context, companyID, actorID string, update *PlanUpdate) (*models.CompanyPlan, error) {
+	if update == nil {
+		return s.GetPlan(ctx, companyID)
+	}
+
+	planType := strings.ToUpper(strings.TrimSpace(update.PlanType))
+	if _, ok := validPlanTypes[planType]; !ok {
+		return nil, ErrInvalidPlanType
+	}
+
+	plan, err := s.GetPlan(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if plan.PlanType != planType {
+		plan.StartDate = now
+	}
+	plan.PlanType = planType
+	plan.PlanName = planDisplayName(planType)
+
+	if update.MaxUsers != nil {
+		if *update.MaxUsers < 0 {
+			return nil, fmt.Errorf("maxUsers cannot be negative")
+		}
+		plan.MaxUsers = *update.MaxUsers
+	}
+	if update.MaxEstates != nil {
+		if *update.MaxEstates < 0 {
+			return nil, fmt.Errorf("maxEstates cannot be negative")
+		}
+		plan.MaxEstates = *update.MaxEstates
+	}
+	if update.MaxStorageGB != nil {
+		if *update.MaxStorageGB < 0 {
+			return nil, fmt.Errorf("maxStorageGb cannot be negative")
+		}
+		plan.MaxStorageGB = *update.MaxStorageGB
+	}
+	if update.FeaturesEnabled != nil {
+		plan.SetFeaturesEnabled(update.FeaturesEnabled)
+	}
+
+	if planType == models.PlanTrial {
+		if update.TrialDays != nil && *update.TrialDays > 0 {
+			trialEndsAt := now.AddDate(0, 0, *update.TrialDays)
+			plan.TrialEndsAt = &trialEndsAt
+		} else if plan.TrialEndsAt == nil {
+			trialEndsAt := now.AddDate(0, 0, defaultTrialDays)
+			plan.TrialEndsAt = &trialEndsAt
+		}
+	} else {
+		plan.TrialEndsAt = nil
+	}
+
+	if err := s.savePlan(ctx, plan, actorID); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// ExtendTrial pushes a trial's expiry by the given number of days, counted from
+// now when the trial has already lapsed.
+func (s *TenantPlanService) ExtendTrial(ctx context.Context, companyID, actorID string, days int) (*models.CompanyPlan, error) {
+	if days <= 0 || days > maxTrialExtensionDays {
+		return nil, fmt.Errorf("days must be between 1 and %d", maxTrialExtensionDays)
+	}
+
+	plan, err := s.GetPlan(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if !plan.IsTrial() {
+		return nil, ErrNotOnTrial
+	}
+
+	base := time.Now()
+	if plan.TrialEndsAt != nil && plan.TrialEndsAt.After(base) {
+		base = *plan.TrialEndsAt
+	}
+	trialEndsAt := base.AddDate(0, 0, days)
+	plan.TrialEndsAt = &trialEndsAt
+
+	if err := s.savePlan(ctx, plan, actorID); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// SuspendCompany marks the company SUSPENDED, making its data read-only.
+func (s *TenantPlanService) SuspendCompany(ctx context.Context, companyID, actorID, reason string) (*models.CompanyPlan, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrSuspendReasonEmpty
+	}
+
+	plan, err := s.GetPlan(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	plan.SuspendedAt = &now
+	plan.SuspendedReason = &reason
+	if actor := strings.TrimSpace(actorID); actor != "" {
+		plan.SuspendedBy = &actor
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.setCompanyStatus(tx, companyID, "SUSPENDED", false); err != nil {
+			return err
+		}
+		return s.savePlanTx(tx, plan, actorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invalidateCompanyAccess(companyID)
+	return plan, nil
+}
+
+// ActivateCompany lifts a suspension.
+func (s *TenantPlanService) ActivateCompany(ctx context.Context, companyID, actorID string) (*models.CompanyPlan, error) {
+	plan, err := s.GetPlan(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.SuspendedAt = nil
+	plan.SuspendedReason = nil
+	plan.SuspendedBy = nil
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.setCompanyStatus(tx, companyID, "ACTIVE", true); err != nil {
+			return err
+		}
+		return s.savePlanTx(tx, plan, actorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invalidateCompanyAccess(companyID)
+	return plan, nil
+}
+
+func (s *TenantPlanService) setCompanyStatus(tx *gorm.DB, companyID, status string, isActive bool) error {
+	result := tx.Table("companies").
+		Where("id = ?", companyID).
+		Updates(map[string]interface{}{
+			"status":     status,
+			"is_active":  isActive,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update company status: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("company not found")
+	}
+	return nil
+}
+
+// CheckWritable returns ErrCompanySuspended or ErrTrialExpired when the company
+// may no longer modify data.
+func (s *TenantPlanService) CheckWritable(ctx context.Context, companyID string) error {
+	companyID = strings.TrimSpace(companyID)
+	if companyID == "" {
+		return nil
+	}
+
+	companyAccessCache.RLock()
+	entry, ok := companyAccessCache.entries[companyID]
+	companyAccessCache.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.access.err()
+	}
+
+	access, err := s.loadCompanyAccess(ctx, companyID)
+	if err != nil {
+		return err
+	}
+
+	companyAccessCache.Lock()
+	companyAccessCache.entries[companyID] = cachedCompanyAccess{
+		access:    access,
+		expiresAt: time.Now().Add(companyAccessCacheTTL),
+	}
+	companyAccessCache.Unlock()
+
+	return access.err()
+}
+
+func (s *TenantPlanService) loadCompanyAccess(ctx context.Context, companyID string) (companyAccess, error) {
+	var statuses []string
+	if err := s.db.WithContext(ctx).
+		Table("companies").
+		Where("id = ?", companyID).
+		Limit(1).
+		Pluck("status", &statuses).Error; err != nil {
+		return companyAccessWritable, fmt.Errorf("failed to load company status: %w", err)
+	}
+	if len(statuses) > 0 && strings.EqualFold(strings.TrimSpace(statuses[0]), "SUSPENDED") {
+		return companyAccessSuspended, nil
+	}
+
+	plan, err := s.GetPlan(ctx, companyID)
+	if err != nil {
+		return companyAccessWritable, err
+	}
+	if plan.SuspendedAt != nil {
+		return companyAccessSuspended, nil
+	}
+	if plan.TrialExpired(time.Now()) {
+		return companyAccessTrialExpired, nil
+	}
+	return companyAccessWritable, nil
+}
+
+// CheckUserQuota fails when adding users would exceed the plan's maxUsers. It
+// takes no lock, so it only suits early validation; the write itself must go
+// through CheckUserQuotaTx or WithUserQuota.
+func (s *TenantPlanService) CheckUserQuota(ctx context.Context, companyID string, additional int) error {
+	if err := s.CheckWritable(ctx, companyID); err != nil {
+		return err
+	}
+	return s.checkUserLimit(s.db.WithContext(ctx), companyID, additional, false)
+}
+
+// CheckUserQuotaTx checks the user limit inside tx, the transaction that
+// creates the users. The plan row stays locked until tx ends, so a concurrent
+// check for the same company waits and then counts the new users. Check
+// several companies in a fixed order to avoid deadlocks.
+func (s *TenantPlanService) CheckUserQuotaTx(tx *gorm.DB, companyID string, additional int) error {
+	if err := s.CheckWritable(tx.Statement.Context, companyID); err != nil {
+		return err
+	}
+	return s.checkUserLimit(tx, companyID, additional, true)
+}
+
+// WithUserQuota runs create while the plan rows of companyIDs are locked,
+// after checking that additional users fit each plan. Use it when the users
+// are written by code that manages its own transaction.
+func (s *TenantPlanService) WithUserQuota(ctx context.Context, companyIDs []string, additional int, create func(ctx context.Context) error) error {
+	companyIDs = sortedCompanyIDs(companyIDs)
+	for _, companyID := range companyIDs {
+		if err := s.CheckWritable(ctx, companyID); err != nil {
+			return err
+		}
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, companyID := range companyIDs {
+			if err := s.checkUserLimit(tx, companyID, additional, true); err != nil {
+				return err
+			}
+		}
+		return create(ctx)
+	})
+}
+
+func (s *TenantPlanService) checkUserLimit(db *gorm.DB, companyID string, additional int, lock bool) error {
+	plan, err := s.loadPlan(db, companyID, lock)
+	if err != nil || plan == nil || plan.MaxUsers <= 0 {
+		return err
+	}
+
+	current, err := countCompanyUsers(db, companyID)
+	if err != nil {
+		return err
+	}
+	if int(current)+additional > plan.MaxUsers {
+		return &QuotaExceededError{
+			Resource: "User",
+			Limit:    fmt.Sprintf("%d users", plan.MaxUsers),
+			Current:  fmt.Sprintf("%d users", current),
+		}
+	}
+	return nil
+}
+
+// CheckEstateQuotaTx fails when adding estates would exceed the plan's
+// maxEstates. Call it inside the transaction that creates the estates; the
+// plan row stays locked until tx ends.
+func (s *TenantPlanService) CheckEstateQuotaTx(tx *gorm.DB, companyID string, additional int) error {
+	if err := s.CheckWritable(tx.Statement.Context, companyID); err != nil {
+		return err
+	}
+
+	plan, err := s.loadPlan(tx, companyID, true)
+	if err != nil || plan == nil || plan.MaxEstates <= 0 {
+		return err
+	}
+
+	current, err := countCompanyEstates(tx, companyID)
+	if err != nil {
+		return err
+	}
+	if int(current)+additional > plan.MaxEstates {
+		return &QuotaExceededError{
+			Resource: "Estate",
+			Limit:    fmt.Sprintf("%d estates", plan.MaxEstates),
+			Current:  fmt.Sprintf("%d estates", current),
+		}
+	}
+	return nil
+}
+
+// CheckStorageQuota fails when storing sizeBytes more would exceed
+// maxStorageGb. It reserves nothing; use it to reject oversized uploads before
+// reading them and ReserveStorage once the size is final.
+func (s *TenantPlanService) CheckStorageQuota(ctx context.Context, companyID string, sizeBytes int64) error {
+	if err := s.CheckWritable(ctx, companyID); err != nil {
+		return err
+	}
+
+	plan, err := s.GetPlan(ctx, companyID)
+	if err != nil {
+		return err
+	}
+	return checkStorageLimit(plan, sizeBytes)
+}
+
+// ReserveStorage adds sizeBytes to the tracked storage when it fits
+// maxStorageGb. The check and the increment run in one transaction on the
+// locked plan row, so concurrent uploads cannot overshoot the limit together.
+// Release the reservation with RecordStorageUsage(-sizeBytes) when the upload
+// is not kept.
+func (s *TenantPlanService) ReserveStorage(ctx context.Context, companyID string, sizeBytes int64) error {
+	companyID = strings.TrimSpace(companyID)
+	if companyID == "" {
+		return nil
+	}
+	if err := s.CheckWritable(ctx, companyID); err != nil {
+		return err
+	}
+	if sizeBytes <= 0 || !s.hasPlanTable() {
+		return nil
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		plan, err := s.loadPlan(tx, companyID, true)
+		if err != nil {
+			return err
+		}
+		if plan == nil {
+			// Materialize the default plan so the counter has a row to live on.
+			if plan, err = s.defaultPlan(tx, companyID); err != nil {
+				return err
+			}
+			plan.CreatedAt = time.Now()
+			plan.UpdatedAt = plan.CreatedAt
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(plan).Error; err != nil {
+				return fmt.Errorf("failed to save company plan: %w", err)
+			}
+			if plan, err = s.loadPlan(tx, companyID, true); err != nil {
+				return err
+			}
+		}
+
+		if err := checkStorageLimit(plan, sizeBytes); err != nil {
+			return err
+		}
+		return tx.Model(&models.CompanyPlan{}).
+			Where("company_id = ?", companyID).
+			UpdateColumn("storage_used_bytes", gorm.Expr("storage_used_bytes + ?", sizeBytes)).Error
+	})
+}
+
+func checkStorageLimit(plan *models.CompanyPlan, sizeBytes int64) error {
+	maxBytes := plan.MaxStorageBytes()
+	if maxBytes <= 0 || plan.StorageUsedBytes+sizeBytes <= maxBytes {
+		return nil
+	}
+	return &QuotaExceededError{
+		Resource: "Storage",
+		Limit:    fmt.Sprintf("%.2f GB", plan.MaxStorageGB),
+		Current:  fmt.Sprintf("%.2f GB", plan.StorageUsedGB()),
+	}
+}
+
+// RecordStorageUsage adjusts the tracked storage counter by deltaBytes. Pass a
+// negative delta when stored files are deleted or a reservation is released;
+// the counter never drops below zero.
+func (s *TenantPlanService) RecordStorageUsage(ctx context.Context, companyID string, deltaBytes int64) error {
+	companyID = strings.TrimSpace(companyID)
+	if companyID == "" || deltaBytes == 0 || !s.hasPlanTable() {
+		return nil
+	}
+
+	plan, err := s.GetPlan(ctx, companyID)
+	if err != nil {
+		return err
+	}
+	if plan.CreatedAt.IsZero() {
+		if deltaBytes < 0 {
+			return nil
+		}
+		// Materialize the default plan so the counter has a row to live on.
+		if err := s.savePlan(ctx, plan, ""); err != nil {
+			return err
+		}
+	}
+
+	return s.db.WithContext(ctx).
+		Model(&models.CompanyPlan{}).
+		Where("company_id = ?", companyID).
+		UpdateColumn("storage_used_bytes", gorm.Expr(
+			"CASE WHEN storage_used_bytes + ? < 0 THEN 0 ELSE storage_used_bytes + ? END",
+			deltaBytes, deltaBytes,
+		)).Error
+}
+
+// sortedCompanyIDs trims, dedupes and sorts company IDs so plan rows are
+// always locked in the same order.
+func sortedCompanyIDs(companyIDs []string) []string {
+	seen := make(map[string]struct{}, len(companyIDs))
+	result := make([]string, 0, len(companyIDs))
+	for _, companyID := range companyIDs {
+		companyID = strings.TrimSpace(companyID)
+		if companyID == "" {
+			continue
+		}
+		if _, ok := seen[companyID]; ok {
+			continue
+		}
+		seen[companyID] = struct{}{}
+		result = append(result, companyID)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (s *TenantPlanService) savePlan(ctx context.Context, plan *models.CompanyPlan, actorID string) error {
+	if err := s.savePlanTx(s.db.WithContext(ctx), plan, actorID); err != nil {
+		return err
+	}
+	invalidateCompanyAccess(plan.CompanyID)
+	return nil
+}
+
+func (s *TenantPlanService) savePlanTx(tx *gorm.DB, plan *models.CompanyPlan, actorID string) error {
+	now := time.Now()
+	plan.UpdatedAt = now
+	if plan.CreatedAt.IsZero() {
+		plan.CreatedAt = now
+	}
+	if actor := strings.TrimSpace(actorID); actor != "" {
+		plan.UpdatedBy = &actor
+	}
+
+	// storage_used_bytes is maintained by RecordStorageUsage; never overwrite it here.
+	if err := tx.
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "company_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"plan_type", "plan_name", "start_date", "end_date", "trial_ends_at",
+				"max_users", "max_estates", "max_storage_gb", "features_enabled_json",
+				"suspended_at", "suspended_reason", "suspended_by", "updated_by", "updated_at",
+			}),
+		}).
+		Create(plan).Error; err != nil {
+		return fmt.Errorf("failed to save company plan: %w", err)
+	}
+	return nil
+}
+
+func invalidateCompanyAccess(companyID string) {
+	companyAccessCache.Lock()
+	delete(companyAccessCache.entries, companyID)
+	companyAccessCache.Unlock()
+}
+
+func planDisplayName(planType string) string {
+	switch planType {
+	case models.PlanTrial:
+		return "Trial"
+	case models.PlanBasic:
+		return "Basic"
+	case models.PlanStandard:
+		return "Standard"
+	case models.PlanPremium:
+		return "Premium"
+	case models.PlanCustom:
+		return "Custom"
+	default:
+		return "Enterprise"
+	}
+}
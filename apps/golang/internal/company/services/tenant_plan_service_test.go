@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"agrinovagraphql/server/internal/company/models"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// openCompanyTestDB opens a named shared-cache database so queries issued on
+// a second connection while a transaction is open see the same tables.
+func openCompanyTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	return db
+}
+
+func setupCompanyTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db := openCompanyTestDB(t)
+	for _, stmt := range []string{
+		`CREATE TABLE companies (id TEXT PRIMARY KEY, status TEXT DEFAULT 'ACTIVE', is_active BOOLEAN DEFAULT true, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE users (id TEXT PRIMARY KEY, is_active BOOLEAN DEFAULT true, deleted_at DATETIME)`,
+		`CREATE TABLE user_company_assignments (user_id TEXT, company_id TEXT, is_active BOOLEAN)`,
+		`CREATE TABLE estates (id TEXT PRIMARY KEY, company_id TEXT)`,
+		`CREATE TABLE divisions (id TEXT PRIMARY KEY, estate_id TEXT)`,
+		`CREATE TABLE user_estate_assignments (user_id TEXT, estate_id TEXT, is_active BOOLEAN)`,
+		`CREATE TABLE user_division_assignments (user_id TEXT, division_id TEXT, is_active BOOLEAN)`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+	require.NoError(t, db.AutoMigrate(&models.CompanyPlan{}))
+	return db
+}
+
+func seedCompany(t *testing.T, db *gorm.DB, companyID string) {
+	t.Helper()
+	require.NoError(t, db.Exec(`INSERT INTO companies (id, created_at) VALUES (?, ?)`, companyID, time.Now()).Error)
+	t.Cleanup(func() { invalidateCompanyAccess(companyID) })
+}
+
+func seedCompanyUser(t *testing.T, db *gorm.DB, companyID, userID string) {
+	t.Helper()
+	require.NoError(t, db.Exec(`INSERT INTO users (id) VALUES (?)`, userID).Error)
+	require.NoError(t, db.Exec(`INSERT INTO user_company_assignments VALUES (?, ?, true)`, userID, companyID).Error)
+}
+
+func loadStorageUsed(t *testing.T, db *gorm.DB, companyID string) int64 {
+	t.Helper()
+	var used int64
+	require.NoError(t, db.Model(&models.CompanyPlan{}).
+		Where("company_id = ?", companyID).
+		Pluck("storage_used_bytes", &used).Error)
+	return used
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestTenantPlanService_CheckUserQuota(t *testing.T) {
+	db := setupCompanyTestDB(t)
+	service := NewTenantPlanService(db)
+	ctx := context.Background()
+
+	seedCompany(t, db, "company-1")
+	seedCompanyUser(t, db, "company-1", "user-1")
+	seedCompanyUser(t, db, "company-1", "user-2")
+
+	assert.NoError(t, service.CheckUserQuota(ctx, "company-1", 5), "no plan row means unlimited")
+
+	_, err := service.UpdatePlan(ctx, "company-1", "", &PlanUpdate{PlanType: models.PlanBasic, MaxUsers: intPtr(3)})
+	require.NoError(t, err)
+
+	assert.NoError(t, service.CheckUserQuota(ctx, "company-1", 1))
+	var quotaErr *QuotaExceededError
+	require.ErrorAs(t, service.CheckUserQuota(ctx, "company-1", 2), &quotaErr)
+	assert.Equal(t, "User", quotaErr.Resource)
+}
+
+func TestTenantPlanService_WithUserQuota(t *testing.T) {
+	db := setupCompanyTestDB(t)
+	service := NewTenantPlanService(db)
+	ctx := context.Background()
+
+	seedCompany(t, db, "company-1")
+	seedCompany(t, db, "company-2")
+	seedCompanyUser(t, db, "company-2", "user-1")
+	_, err := service.UpdatePlan(ctx, "company-2", "", &PlanUpdate{PlanType: models.PlanBasic, MaxUsers: intPtr(1)})
+	require.NoError(t, err)
+
+	created := false
+	create := func(context.Context) error {
+		created = true
+		return nil
+	}
+
+	err = service.WithUserQuota(ctx, []string{"company-2", "company-1", "company-2"}, 1, create)
+	var quotaErr *QuotaExceededError
+	require.ErrorAs(t, err, &quotaErr)
+	assert.False(t, created, "create must not run when a company is over quota")
+
+	require.NoError(t, service.WithUserQuota(ctx, []string{"company-1"}, 1, create))
+	assert.True(t, created)
+}
+
+func TestTenantPlanService_CheckEstateQuotaTx(t *testing.T) {
+	db := setupCompanyTestDB(t)
+	service := NewTenantPlanService(db)
+	ctx := context.Background()
+
+	seedCompany(t, db, "company-1")
+	require.NoError(t, db.Exec(`INSERT INTO estates VALUES ('estate-1', 'company-1')`).Error)
+	_, err := service.UpdatePlan(ctx, "company-1", "", &PlanUpdate{PlanType: models.PlanBasic, MaxEstates: intPtr(2)})
+	require.NoError(t, err)
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := service.CheckEstateQuotaTx(tx, "company-1", 1); err != nil {
+			return err
+		}
+		return tx.Exec(`INSERT INTO estates VALUES ('estate-2', 'company-1')`).Error
+	})
+	require.NoError(t, err)
+
+	err = db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return service.CheckEstateQuotaTx(tx, "company-1", 1)
+	})
+	var quotaErr *QuotaExceededError
+	require.ErrorAs(t, err, &quotaErr)
+	assert.Equal(t, "Estate", quotaErr.Resource)
+}
+
+func TestTenantPlanService_ReserveStorage(t *testing.T) {
+	db := setupCompanyTestDB(t)
+	service := NewTenantPlanService(db)
+	ctx := context.Background()
+
+	seedCompany(t, db, "company-1")
+	maxGB := 1.0
+	_, err := service.UpdatePlan(ctx, "company-1", "", &PlanUpdate{PlanType: models.PlanBasic, MaxStorageGB: &maxGB})
+	require.NoError(t, err)
+
+	limit := int64(1024 * 1024 * 1024) // 1 GB
+	require.NoError(t, service.ReserveStorage(ctx, "company-1", limit-100))
+	assert.Equal(t, limit-100, loadStorageUsed(t, db, "company-1"))
+
+	var quotaErr *QuotaExceededError
+	require.ErrorAs(t, service.ReserveStorage(ctx, "company-1", 101), &quotaErr)
+	assert.Equal(t, "Storage", quotaErr.Resource)
+	assert.Equal(t, limit-100, loadStorageUsed(t, db, "company-1"), "a rejected reservation leaves the counter alone")
+
+	require.NoError(t, service.ReserveStorage(ctx, "company-1", 100))
+	assert.Equal(t, limit, loadStorageUsed(t, db, "company-1"))
+}
+
+func TestTenantPlanService_ReserveStorage_MaterializesDefaultPlan(t *testing.T) {
+	db := setupCompanyTestDB(t)
+	service := NewTenantPlanService(db)
+	ctx := context.Background()
+
+	seedCompany(t, db, "company-1")
+	require.NoError(t, service.ReserveStorage(ctx, "company-1", 2048))
+
+	plan, err := service.GetPlan(ctx, "company-1")
+	require.NoError(t, err)
+	assert.Equal(t, models.PlanEnterprise, plan.PlanType)
+	assert.Equal(t, int64(2048), plan.StorageUsedBytes)
+}
+
+func TestTenantPlanService_RecordStorageUsage_ReleaseClampsAtZero(t *testing.T) {
+	db := setupCompanyTestDB(t)
+	service := NewTenantPlanService(db)
+	ctx := context.Background()
+
+	seedCompany(t, db, "company-1")
+	seedCompany(t, db, "company-2")
+
+	require.NoError(t, service.RecordStorageUsage(ctx, "company-1", 500))
+	require.NoError(t, service.RecordStorageUsage(ctx, "company-1", -200))
+	assert.Equal(t, int64(300), loadStorageUsed(t, db, "company-1"))
+
+	require.NoError(t, service.RecordStorageUsage(ctx, "company-1", -1000))
+	assert.Zero(t, loadStorageUsed(t, db, "company-1"))
+
+	require.NoError(t, service.RecordStorageUsage(ctx, "company-2", -100))
+	var plans int64
+	require.NoError(t, db.Model(&models.CompanyPlan{}).Where("company_id = ?", "company-2").Count(&plans).Error)
+	assert.Zero(t, plans, "releasing storage never creates a plan row")
+}
+
+func TestTenantPlanService_UpdatePlanKeepsStorageUsage(t *testing.T) {
+	db := setupCompanyTestDB(t)
+	service := NewTenantPlanService(db)
+	ctx := context.Background()
+
+	seedCompany(t, db, "company-1")
+	require.NoError(t, service.RecordStorageUsage(ctx, "company-1", 4096))
+
+	plan, err := service.UpdatePlan(ctx, "company-1", "", &PlanUpdate{PlanType: models.PlanTrial, TrialDays: intPtr(7)})
+	require.NoError(t, err)
+	assert.Equal(t, "Trial", plan.PlanName)
+	require.NotNil(t, plan.TrialEndsAt)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 7), *plan.TrialEndsAt, time.Minute)
+	assert.Equal(t, int64(4096), loadStorageUsed(t, db, "company-1"))
+
+	_, err = service.UpdatePlan(ctx, "company-1", "", &PlanUpdate{PlanType: "GOLD"})
+	assert.ErrorIs(t, err, ErrInvalidPlanType)
+}
+
+func TestTenantPlanService_CheckWritable(t *testing.T) {
+	db := setupCompanyTestDB(t)
+	service := NewTenantPlanService(db)
+	ctx := context.Background()
+
+	seedCompany(t, db, "company-1")
+	seedCompany(t, db, "company-2")
+
+	_, err := service.SuspendCompany(ctx, "company-1", "", "unpaid invoice")
+	require.NoError(t, err)
+	assert.ErrorIs(t, service.CheckWritable(ctx, "company-1"), ErrCompanySuspended)
+	assert.ErrorIs(t, service.ReserveStorage(ctx, "company-1", 1), ErrCompanySuspended)
+
+	_, err = service.ActivateCompany(ctx, "company-1", "")
+	require.NoError(t, err)
+	assert.NoError(t, service.CheckWritable(ctx, "company-1"))
+
+	expired := time.Now().Add(-time.Hour)
+	require.NoError(t, db.Create(&models.CompanyPlan{
+		CompanyID:   "company-2",
+		PlanType:    models.PlanTrial,
+		TrialEndsAt: &expired,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}).Error)
+	assert.ErrorIs(t, service.CheckWritable(ctx, "company-2"), ErrTrialExpired)
+}
+
+func TestTenantPlanService_ExtendTrial(t *testing.T) {
+	db := setupCompanyTestDB(t)
+	service := NewTenantPlanService(db)
+	ctx := context.Background()
+
+	seedCompany(t, db, "company-1")
+	_, err := service.ExtendTrial(ctx, "company-1", "", 7)
+	assert.ErrorIs(t, err, ErrNotOnTrial)
+
+	_, err = service.UpdatePlan(ctx, "company-1", "", &PlanUpdate{PlanType: models.PlanTrial, TrialDays: intPtr(3)})
+	require.NoError(t, err)
+
+	plan, err := service.ExtendTrial(ctx, "company-1", "", 10)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 13), *plan.TrialEndsAt, time.Minute)
+
+	_, err = service.ExtendTrial(ctx, "company-1", "", 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,1040 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	sharedDomain "agrinovagraphql/server/internal/auth/features/shared/domain"
+	authServices "agrinovagraphql/server/internal/auth/services"
+	"agrinovagraphql/server/internal/company/models"
+	"agrinovagraphql/server/internal/graphql/domain/auth"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultCompanyUserPageSize = 20
+	maxCompanyUserPageSize     = 100
+	defaultActivityLogLimit    = 50
+	maxActivityLogLimit        = 500
+	minAdminPasswordLength     = 8
+
+	// onlineActivityWindow is how recent session activity must be for a user
+	// to count as online.
+	onlineActivityWindow = 5 * time.Minute
+)
+
+// Company user administration errors
+var (
+	ErrUserNotInCompany     = errors.New("user not found in your company")
+	ErrRoleNotManageable    = errors.New("access denied: role hierarchy does not allow this action")
+	ErrCannotManageSelf     = errors.New("you cannot perform this action on your own account")
+	ErrEstateNotInCompany   = errors.New("estate not found in your company")
+	ErrDivisionNotInCompany = errors.New("division not found in your company")
+	ErrAssignmentNotAllowed = errors.New("assignment is not allowed for this role")
+	ErrAdminPasswordTooWeak = fmt.Errorf("password must be at least %d characters", minAdminPasswordLength)
+	ErrUserLifecycleMissing = errors.New("user service unavailable")
+)
+
+// companyUserIDsSQL selects the users that belong to a company through any
+// active company, estate or division assignment. It takes the company ID
+// three times.
+const companyUserIDsSQL = `
+	SELECT uca.user_id FROM user_company_assignments uca
+	WHERE uca.company_id = ? AND uca.is_active = true
+	UNION
+	SELECT uea.user_id FROM user_estate_assignments uea
+	JOIN estates e ON e.id = uea.estate_id
+	WHERE e.company_id = ? AND uea.is_active = true
+	UNION
+	SELECT uda.user_id FROM user_division_assignments uda
+	JOIN divisions d ON d.id = uda.division_id
+	JOIN estates e ON e.id = d.estate_id
+	WHERE e.company_id = ? AND uda.is_active = true
+`
+
+func companyUserIDsArgs(companyID string) []interface{} {
+	return []interface{}{companyID, companyID, companyID}
+}
+
+// estateAssignableRoles may hold estate assignments; ASISTEN and MANDOR are
+// limited to a single estate.
+var estateAssignableRoles = map[string]bool{
+	string(auth.UserRoleManager): true,
+	string(auth.UserRoleAsisten): true,
+	string(auth.UserRoleMandor):  true,
+}
+
+var divisionAssignableRoles = map[string]bool{
+	string(auth.UserRoleAsisten): true,
+	string(auth.UserRoleMandor):  true,
+}
+
+// UserAdminActor identifies the company admin performing an action.
+type UserAdminActor struct {
+	UserID    string
+	Name      string
+	Role      auth.UserRole
+	CompanyID string
+	IPAddress string
+}
+
+// UserLifecycle performs the user writes that carry auth module validation
+// (duplicate assignment checks, delete blocking checks, password hashing).
+// Implemented by the web UserManagementService.
+type UserLifecycle interface {
+	ToggleStatus(ctx context.Context, id string) (*sharedDomain.User, error)
+	DeleteUser(ctx context.Context, id string) error
+	ResetPassword(ctx context.Context, id string, newPassword string) error
+}
+
+// UserAdminEventPublisher receives user status changes and new activity entries
+// so they can be pushed to company admin subscriptions.
+type UserAdminEventPublisher interface {
+	PublishUserStatusChange(companyID string, user *models.CompanyUser)
+	PublishAdminActivity(entry *models.AdminActivityLog)
+}
+
+// CompanyUserAdminService implements company-scoped user administration for
+// COMPANY_ADMIN: listing, status changes, password resets, unlocks and
+// estate/division assignments, with an activity audit trail.
+type CompanyUserAdminService struct {
+	db        *gorm.DB
+	roles     *authServices.RoleHierarchyService
+	lifecycle UserLifecycle
+	events    UserAdminEventPublisher
+
+	tableCheck  sync.Once
+	tableExists bool
+}
+
+// NewCompanyUserAdminService creates a new company user administration service
+func NewCompanyUserAdminService(
+	db *gorm.DB,
+	roles *authServices.RoleHierarchyService,
+	lifecycle UserLifecycle,
+) *CompanyUserAdminService {
+	if roles == nil {
+		roles = authServices.NewRoleHierarchyService()
+	}
+	return &CompanyUserAdminService{
+		db:        db,
+		roles:     roles,
+		lifecycle: lifecycle,
+	}
+}
+
+// SetEventPublisher injects the subscription publisher.
+func (s *CompanyUserAdminService) SetEventPublisher(events UserAdminEventPublisher) {
+	s.events = events
+}
+
+func (s *CompanyUserAdminService) hasActivityTable() bool {
+	s.tableCheck.Do(func() {
+		s.tableExists = s.db.Migrator().HasTable(&models.AdminActivityLog{})
+	})
+	return s.tableExists
+}
+
+// AuthorizeRole checks that the actor may assign role to a user.
+func (s *CompanyUserAdminService) AuthorizeRole(actor UserAdminActor, role string) error {
+	if err := s.roles.ValidateRoleAssignment(actor.Role, auth.UserRole(strings.ToUpper(strings.TrimSpace(role)))); err != nil {
+		return fmt.Errorf("%w: %v", ErrRoleNotManageable, err)
+	}
+	return nil
+}
+
+// AuthorizeUser loads a user of the actor's company and checks the actor
+// outranks them.
+func (s *CompanyUserAdminService) AuthorizeUser(ctx context.Context, actor UserAdminActor, userID string) (*models.CompanyUser, error) {
+	if strings.TrimSpace(userID) == actor.UserID {
+		return nil, ErrCannotManageSelf
+	}
+
+	user, err := s.GetUser(ctx, actor.CompanyID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.roles.CanManage(actor.Role, auth.UserRole(user.Role)) {
+		return nil, fmt.Errorf("%w: %s cannot manage %s", ErrRoleNotManageable, actor.Role, user.Role)
+	}
+	return user, nil
+}
+
+// ListUsers returns a page of company users and the total match count.
+func (s *CompanyUserAdminService) ListUsers(ctx context.Context, companyID string, filter models.CompanyUserFilter) ([]*models.CompanyUser, int64, error) {
+	page, pageSize := NormalizeCompanyUserPage(filter.Page, filter.PageSize)
+
+	query := s.companyUsersQuery(ctx, companyID)
+	if filter.Role != nil && strings.TrimSpace(*filter.Role) != "" {
+		query = query.Where("u.role = ?", strings.ToUpper(strings.TrimSpace(*filter.Role)))
+	}
+	if filter.ActiveOnly {
+		query = query.Where("u.is_active = ?", true)
+	}
+	if filter.EstateID != nil && strings.TrimSpace(*filter.EstateID) != "" {
+		estateID := strings.TrimSpace(*filter.EstateID)
+		query = query.Where(`u.id IN (
+			SELECT uea.user_id FROM user_estate_assignments uea
+			WHERE uea.estate_id = ? AND uea.is_active = true
+			UNION
+			SELECT uda.user_id FROM user_division_assignments uda
+			JOIN divisions d ON d.id = uda.division_id
+			WHERE d.estate_id = ? AND uda.is_active = true
+		)`, estateID, estateID)
+	}
+	if filter.DivisionID != nil && strings.TrimSpace(*filter.DivisionID) != "" {
+		query = query.Where(`u.id IN (
+			SELECT uda.user_id FROM user_division_assignments uda
+			WHERE uda.division_id = ? AND uda.is_active = true
+		)`, strings.TrimSpace(*filter.DivisionID))
+	}
+	if filter.Search != nil && strings.TrimSpace(*filter.Search) != "" {
+		pattern := "%" + strings.TrimSpace(*filter.Search) + "%"
+		query = query.Where("(u.username ILIKE ? OR u.name ILIKE ? OR u.email ILIKE ?)", pattern, pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count company users: %w", err)
+	}
+
+	users, err := s.loadUsers(ctx, query.Order("u.name ASC").Offset((page-1)*pageSize).Limit(pageSize))
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// GetUser returns a single user of the company.
+func (s *CompanyUserAdminService) GetUser(ctx context.Context, companyID, userID string) (*models.CompanyUser, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, ErrUserNotInCompany
+	}
+
+	users, err := s.loadUsers(ctx, s.companyUsersQuery(ctx, companyID).Where("u.id = ?", userID).Limit(1))
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, ErrUserNotInCompany
+	}
+	return users[0], nil
+}
+
+// SetUserActive activates or deactivates a user. Deactivation revokes the
+// user's sessions. Returns the refreshed user.
+func (s *CompanyUserAdminService) SetUserActive(ctx context.Context, actor UserAdminActor, userID string, active bool, reason *string) (*models.CompanyUser, error) {
+	if s.lifecycle == nil {
+		return nil, ErrUserLifecycleMissing
+	}
+
+	user, err := s.AuthorizeUser(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsActive != active {
+		if _, err := s.lifecycle.ToggleStatus(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+	if !active {
+		if err := s.revokeSessions(ctx, user.ID, "user_deactivated"); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.GetUser(ctx, actor.CompanyID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	activityType := models.ActivityUserActivated
+	description := fmt.Sprintf("Activated user %s", user.Username)
+	metadata := map[string]interface{}{}
+	if !active {
+		activityType = models.ActivityUserDeactivated
+		description = fmt.Sprintf("Deactivated user %s", user.Username)
+		if reason != nil && strings.TrimSpace(*reason) != "" {
+			metadata["reason"] = strings.TrimSpace(*reason)
+			description = fmt.Sprintf("%s: %s", description, strings.TrimSpace(*reason))
+		}
+	}
+
+	s.recordUserActivity(ctx, actor, activityType, updated, description, metadata)
+	s.PublishUserStatus(actor.CompanyID, updated)
+	return updated, nil
+}
+
+// DeleteUser removes a user of the company. Returns the user as it was before
+// deletion.
+func (s *CompanyUserAdminService) DeleteUser(ctx context.Context, actor UserAdminActor, userID string) (*models.CompanyUser, error) {
+	if s.lifecycle == nil {
+		return nil, ErrUserLifecycleMissing
+	}
+
+	user, err := s.AuthorizeUser(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.lifecycle.DeleteUser(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	user.IsActive = false
+	user.IsOnline = false
+	s.recordUserActivity(ctx, actor, models.ActivityUserDeleted, user, fmt.Sprintf("Deleted user %s", user.Username), nil)
+	s.PublishUserStatus(actor.CompanyID, user)
+	return user, nil
+}
+
+// ResetPassword sets a new password, clears any lockout and signs the user
+// out everywhere.
+func (s *CompanyUserAdminService) ResetPassword(ctx context.Context, actor UserAdminActor, userID, newPassword string) (*models.CompanyUser, error) {
+	if s.lifecycle == nil {
+		return nil, ErrUserLifecycleMissing
+	}
+	if len(newPassword) < minAdminPasswordLength {
+		return nil, ErrAdminPasswordTooWeak
+	}
+
+	user, err := s.AuthorizeUser(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.lifecycle.ResetPassword(ctx, user.ID, newPassword); err != nil {
+		return nil, err
+	}
+	if err := s.clearLockout(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	if err := s.revokeSessions(ctx, user.ID, "admin_password_reset"); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.GetUser(ctx, actor.CompanyID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordUserActivity(ctx, actor, models.ActivityPasswordReset, updated, fmt.Sprintf("Reset password for %s", user.Username), nil)
+	return updated, nil
+}
+
+// UnlockUser clears a failed-login lockout.
+func (s *CompanyUserAdminService) UnlockUser(ctx context.Context, actor UserAdminActor, userID string) (*models.CompanyUser, error) {
+	user, err := s.AuthorizeUser(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.clearLockout(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.GetUser(ctx, actor.CompanyID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordUserActivity(ctx, actor, models.ActivityUserUpdated, updated, fmt.Sprintf("Unlocked account %s", user.Username), map[string]interface{}{
+		"action": "unlock",
+	})
+	s.PublishUserStatus(actor.CompanyID, updated)
+	return updated, nil
+}
+
+// AssignEstate gives a user an active estate assignment in the actor's company.
+func (s *CompanyUserAdminService) AssignEstate(ctx context.Context, actor UserAdminActor, userID, estateID string) (*models.CompanyUser, error) {
+	user, err := s.AuthorizeUser(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !estateAssignableRoles[user.Role] {
+		return nil, fmt.Errorf("%w: %s cannot be assigned to an estate", ErrAssignmentNotAllowed, user.Role)
+	}
+
+	estateName, err := s.companyEstateName(ctx, actor.CompanyID, estateID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if user.Role == string(auth.UserRoleManager) {
+			var holder []string
+			if err := tx.Table("user_estate_assignments AS uea").
+				Joins("JOIN users u ON u.id = uea.user_id").
+				Where("uea.estate_id = ? AND uea.is_active = ?", estateID, true).
+				Where("u.role = ? AND u.is_active = ? AND u.deleted_at IS NULL", user.Role, true).
+				Where("u.id <> ?", user.ID).
+				Limit(1).
+				Pluck("u.username", &holder).Error; err != nil {
+				return fmt.Errorf("failed to check estate manager: %w", err)
+			}
+			if len(holder) > 0 {
+				return fmt.Errorf("%w: estate %s already has active manager %s", ErrAssignmentNotAllowed, estateName, holder[0])
+			}
+		} else {
+			var otherEstates int64
+			if err := tx.Table("user_estate_assignments").
+				Where("user_id = ? AND is_active = ? AND estate_id <> ?", user.ID, true, estateID).
+				Count(&otherEstates).Error; err != nil {
+				return fmt.Errorf("failed to check estate assignments: %w", err)
+			}
+			if otherEstates > 0 {
+				return fmt.Errorf("%w: %s must be assigned to exactly one estate", ErrAssignmentNotAllowed, user.Role)
+			}
+		}
+
+		return upsertAssignment(tx, "user_estate_assignments", "estate_id", user.ID, estateID, actor.UserID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.afterAssignmentChange(ctx, actor, user.ID, fmt.Sprintf("Assigned %s to estate %s", user.Username, estateName), map[string]interface{}{
+		"action":   "assign_estate",
+		"estateId": estateID,
+	})
+}
+
+// RemoveEstate deactivates a user's estate assignment.
+func (s *CompanyUserAdminService) RemoveEstate(ctx context.Context, actor UserAdminActor, userID, estateID string) (*models.CompanyUser, error) {
+	user, err := s.AuthorizeUser(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	estateName, err := s.companyEstateName(ctx, actor.CompanyID, estateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := deactivateAssignment(s.db.WithContext(ctx), "user_estate_assignments", "estate_id", user.ID, estateID); err != nil {
+		return nil, err
+	}
+
+	return s.afterAssignmentChange(ctx, actor, user.ID, fmt.Sprintf("Removed %s from estate %s", user.Username, estateName), map[string]interface{}{
+		"action":   "remove_estate",
+		"estateId": estateID,
+	})
+}
+
+// AssignDivision gives an ASISTEN or MANDOR an active division assignment.
+// When the user already has an estate, the division must belong to it.
+func (s *CompanyUserAdminService) AssignDivision(ctx context.Context, actor UserAdminActor, userID, divisionID string) (*models.CompanyUser, error) {
+	user, err := s.AuthorizeUser(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !divisionAssignableRoles[user.Role] {
+		return nil, fmt.Errorf("%w: %s cannot be assigned to a division", ErrAssignmentNotAllowed, user.Role)
+	}
+
+	division, err := s.companyDivision(ctx, actor.CompanyID, divisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var estateIDs []string
+		if err := tx.Table("user_estate_assignments").
+			Where("user_id = ? AND is_active = ?", user.ID, true).
+			Pluck("estate_id", &estateIDs).Error; err != nil {
+			return fmt.Errorf("failed to load estate assignments: %w", err)
+		}
+		if len(estateIDs) > 0 && !containsString(estateIDs, division.EstateID) {
+			return fmt.Errorf("%w: division %s is outside the user's estate", ErrAssignmentNotAllowed, division.Name)
+		}
+
+		return upsertAssignment(tx, "user_division_assignments", "division_id", user.ID, division.ID, actor.UserID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.afterAssignmentChange(ctx, actor, user.ID, fmt.Sprintf("Assigned %s to division %s", user.Username, division.Name), map[string]interface{}{
+		"action":     "assign_division",
+		"divisionId": division.ID,
+	})
+}
+
+// RemoveDivision deactivates a user's division assignment.
+func (s *CompanyUserAdminService) RemoveDivision(ctx context.Context, actor UserAdminActor, userID, divisionID string) (*models.CompanyUser, error) {
+	user, err := s.AuthorizeUser(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	division, err := s.companyDivision(ctx, actor.CompanyID, divisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := deactivateAssignment(s.db.WithContext(ctx), "user_division_assignments", "division_id", user.ID, division.ID); err != nil {
+		return nil, err
+	}
+
+	return s.afterAssignmentChange(ctx, actor, user.ID, fmt.Sprintf("Removed %s from division %s", user.Username, division.Name), map[string]interface{}{
+		"action":     "remove_division",
+		"divisionId": division.ID,
+	})
+}
+
+// RecordActivity writes an activity log entry for the actor's company and
+// publishes it to subscribers.
+func (s *CompanyUserAdminService) RecordActivity(
+	ctx context.Context,
+	actor UserAdminActor,
+	activityType, entityType, entityID, description string,
+	metadata map[string]interface{},
+) (*models.AdminActivityLog, error) {
+	entry := &models.AdminActivityLog{
+		CompanyID:   actor.CompanyID,
+		Type:        activityType,
+		ActorID:     actor.UserID,
+		ActorName:   actor.Name,
+		Description: description,
+		CreatedAt:   time.Now(),
+	}
+	if entityType != "" {
+		entry.EntityType = &entityType
+	}
+	if entityID != "" {
+		entry.EntityID = &entityID
+	}
+	if actor.IPAddress != "" {
+		ipAddress := actor.IPAddress
+		entry.IPAddress = &ipAddress
+	}
+	entry.SetMetadata(metadata)
+
+	if s.hasActivityTable() {
+		if err := s.db.WithContext(ctx).Create(entry).Error; err != nil {
+			return nil, fmt.Errorf("failed to write admin activity log: %w", err)
+		}
+	}
+
+	if s.events != nil {
+		s.events.PublishAdminActivity(entry)
+	}
+	return entry, nil
+}
+
+// ListActivities returns the newest activity entries of a company. The user
+// filter matches both the actor and the affected user.
+func (s *CompanyUserAdminService) ListActivities(ctx context.Context, companyID string, filter models.AdminActivityFilter) ([]*models.AdminActivityLog, error) {
+	if !s.hasActivityTable() {
+		return []*models.AdminActivityLog{}, nil
+	}
+
+	query := s.db.WithContext(ctx).
+		Where("company_id = ?", companyID).
+		Order("created_at DESC").
+		Limit(clampActivityLimit(filter.Limit))
+
+	if filter.Type != nil && strings.TrimSpace(*filter.Type) != "" {
+		query = query.Where("activity_type = ?", strings.TrimSpace(*filter.Type))
+	}
+	if filter.UserID != nil && strings.TrimSpace(*filter.UserID) != "" {
+		userID := strings.TrimSpace(*filter.UserID)
+		query = query.Where("(actor_id = ? OR (entity_type = ? AND entity_id = ?))", userID, models.ActivityEntityUser, userID)
+	}
+	if filter.DateFrom != nil {
+		query = query.Where("created_at >= ?", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		query = query.Where("created_at <= ?", *filter.DateTo)
+	}
+
+	var entries []*models.AdminActivityLog
+	if err := query.Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to load admin activity logs: %w", err)
+	}
+	return entries, nil
+}
+
+// UserOverview summarizes the users of a company.
+func (s *CompanyUserAdminService) UserOverview(ctx context.Context, companyID string) (*models.CompanyUserOverview, error) {
+	now := time.Now()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	var roleRows []struct {
+		Role   string `gorm:"column:role"`
+		Count  int    `gorm:"column:count"`
+		Active int    `gorm:"column:active"`
+	}
+	if err := s.companyUsersQuery(ctx, companyID).
+		Select("u.role AS role, COUNT(*) AS count, SUM(CASE WHEN u.is_active THEN 1 ELSE 0 END) AS active").
+		Group("u.role").
+		Order("u.role ASC").
+		Scan(&roleRows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count users by role: %w", err)
+	}
+
+	overview := &models.CompanyUserOverview{ByRole: make([]models.RoleUserCount, 0, len(roleRows))}
+	for _, row := range roleRows {
+		overview.Total += row.Count
+		overview.ByRole = append(overview.ByRole, models.RoleUserCount{Role: row.Role, Count: row.Count, Active: row.Active})
+	}
+
+	var counts struct {
+		ActiveToday      int `gorm:"column:active_today"`
+		NewThisMonth     int `gorm:"column:new_this_month"`
+		PendingApprovals int `gorm:"column:pending_approvals"`
+		LockedAccounts   int `gorm:"column:locked_accounts"`
+	}
+	args := append([]interface{}{dayStart, monthStart, now}, companyUserIDsArgs(companyID)...)
+	if err := s.db.WithContext(ctx).Raw(`
+		SELECT
+			COUNT(DISTINCT CASE WHEN EXISTS (
+				SELECT 1 FROM user_sessions s WHERE s.user_id = u.id AND s.last_activity >= ?
+			) THEN u.id END) AS active_today,
+			COUNT(DISTINCT CASE WHEN u.created_at >= ? THEN u.id END) AS new_this_month,
+			COUNT(DISTINCT CASE WHEN u.is_active = false AND NOT EXISTS (
+				SELECT 1 FROM user_sessions s WHERE s.user_id = u.id
+			) THEN u.id END) AS pending_approvals,
+			COUNT(DISTINCT CASE WHEN u.locked_until > ? THEN u.id END) AS locked_accounts
+		FROM users u
+		WHERE u.deleted_at IS NULL AND u.id IN (`+companyUserIDsSQL+`)
+	`, args...).Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("failed to summarize company users: %w", err)
+	}
+
+	overview.ActiveToday = counts.ActiveToday
+	overview.NewThisMonth = counts.NewThisMonth
+	overview.PendingApprovals = counts.PendingApprovals
+	overview.LockedAccounts = counts.LockedAccounts
+	return overview, nil
+}
+
+// DashboardStats aggregates structure, user and production figures.
+func (s *CompanyUserAdminService) DashboardStats(ctx context.Context, companyID string) (*models.CompanyAdminStats, error) {
+	now := time.Now()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	onlineSince := now.Add(-onlineActivityWindow)
+
+	var row struct {
+		TotalUsers        int     `gorm:"column:total_users"`
+		ActiveUsers       int     `gorm:"column:active_users"`
+		UsersOnlineNow    int     `gorm:"column:users_online_now"`
+		TotalEstates      int     `gorm:"column:total_estates"`
+		TotalDivisions    int     `gorm:"column:total_divisions"`
+		TotalBlocks       int     `gorm:"column:total_blocks"`
+		TotalEmployees    int     `gorm:"column:total_employees"`
+		TodayProduction   float64 `gorm:"column:today_production"`
+		MonthlyProduction float64 `gorm:"column:monthly_production"`
+	}
+
+	args := []interface{}{}
+	args = append(args, companyUserIDsArgs(companyID)...)
+	args = append(args, companyUserIDsArgs(companyID)...)
+	args = append(args, now, onlineSince)
+	args = append(args, companyUserIDsArgs(companyID)...)
+	args = append(args, companyID, companyID, companyID, companyID)
+	args = append(args, companyID, dayStart, now)
+	args = append(args, companyID, monthStart, now)
+
+	if err := s.db.WithContext(ctx).Raw(`
+		SELECT
+			(SELECT COUNT(*) FROM users u
+			 WHERE u.deleted_at IS NULL AND u.id IN (`+companyUserIDsSQL+`)) AS total_users,
+			(SELECT COUNT(*) FROM users u
+			 WHERE u.deleted_at IS NULL AND u.is_active = true AND u.id IN (`+companyUserIDsSQL+`)) AS active_users,
+			(SELECT COUNT(DISTINCT s.user_id) FROM user_sessions s
+			 WHERE s.is_active = true AND s.expires_at > ? AND s.last_activity >= ?
+			   AND s.user_id IN (`+companyUserIDsSQL+`)) AS users_online_now,
+			(SELECT COUNT(*) FROM estates e WHERE e.company_id = ?) AS total_estates,
+			(SELECT COUNT(*) FROM divisions d
+			 JOIN estates e ON e.id = d.estate_id
+			 WHERE e.company_id = ?) AS total_divisions,
+			(SELECT COUNT(*) FROM blocks b
+			 JOIN divisions d ON d.id = b.division_id
+			 JOIN estates e ON e.id = d.estate_id
+			 WHERE e.company_id = ?) AS total_blocks,
+			(SELECT COUNT(*) FROM employees emp
+			 WHERE emp.company_id = ? AND emp.is_active = true) AS total_employees,
+			(SELECT COALESCE(SUM(hr.berat_tbs), 0) FROM harvest_records hr
+			 WHERE hr.company_id = ? AND hr.status IN ('APPROVED', 'PENDING')
+			   AND hr.tanggal >= ? AND hr.tanggal <= ?) AS today_production,
+			(SELECT COALESCE(SUM(hr.berat_tbs), 0) FROM harvest_records hr
+			 WHERE hr.company_id = ? AND hr.status IN ('APPROVED', 'PENDING')
+			   AND hr.tanggal >= ? AND hr.tanggal <= ?) AS monthly_production
+	`, args...).Scan(&row).Error; err != nil {
+		return nil, fmt.Errorf("failed to aggregate company admin stats: %w", err)
+	}
+
+	return &models.CompanyAdminStats{
+		TotalUsers:        row.TotalUsers,
+		ActiveUsers:       row.ActiveUsers,
+		UsersOnlineNow:    row.UsersOnlineNow,
+		TotalEstates:      row.TotalEstates,
+		TotalDivisions:    row.TotalDivisions,
+		TotalBlocks:       row.TotalBlocks,
+		TotalEmployees:    row.TotalEmployees,
+		TodayProduction:   row.TodayProduction,
+		MonthlyProduction: row.MonthlyProduction,
+	}, nil
+}
+
+// EstateOverviews returns per-estate divisions, users, manager and today's
+// production for the company admin dashboard.
+func (s *CompanyUserAdminService) EstateOverviews(ctx context.Context, companyID string) ([]models.EstateOverview, error) {
+	now := time.Now()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	var rows []struct {
+		EstateID        string  `gorm:"column:estate_id"`
+		EstateName      string  `gorm:"column:estate_name"`
+		ManagerName     *string `gorm:"column:manager_name"`
+		DivisionsCount  int     `gorm:"column:divisions_count"`
+		UsersCount      int     `gorm:"column:users_count"`
+		TodayProduction float64 `gorm:"column:today_production"`
+	}
+	if err := s.db.WithContext(ctx).Raw(`
+		SELECT
+			e.id AS estate_id,
+			e.name AS estate_name,
+			(SELECT u.name FROM user_estate_assignments uea
+			 JOIN users u ON u.id = uea.user_id
+			 WHERE uea.estate_id = e.id AND uea.is_active = true
+			   AND u.role = ? AND u.is_active = true AND u.deleted_at IS NULL
+			 ORDER BY uea.created_at ASC
+			 LIMIT 1) AS manager_name,
+			(SELECT COUNT(*) FROM divisions d WHERE d.estate_id = e.id) AS divisions_count,
+			(SELECT COUNT(DISTINCT scoped.user_id) FROM (
+				SELECT uea.user_id FROM user_estate_assignments uea
+				WHERE uea.estate_id = e.id AND uea.is_active = true
+				UNION
+				SELECT uda.user_id FROM user_division_assignments uda
+				JOIN divisions d ON d.id = uda.division_id
+				WHERE d.estate_id = e.id AND uda.is_active = true
+			) scoped) AS users_count,
+			(SELECT COALESCE(SUM(hr.berat_tbs), 0) FROM harvest_records hr
+			 WHERE hr.estate_id = e.id AND hr.status IN ('APPROVED', 'PENDING')
+			   AND hr.tanggal >= ? AND hr.tanggal <= ?) AS today_production
+		FROM estates e
+		WHERE e.company_id = ?
+		ORDER BY e.name ASC
+	`, string(auth.UserRoleManager), dayStart, now, companyID).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load estate overview: %w", err)
+	}
+
+	overviews := make([]models.EstateOverview, 0, len(rows))
+	for _, row := range rows {
+		overviews = append(overviews, models.EstateOverview{
+			EstateID:        row.EstateID,
+			EstateName:      row.EstateName,
+			ManagerName:     row.ManagerName,
+			DivisionsCount:  row.DivisionsCount,
+			UsersCount:      row.UsersCount,
+			TodayProduction: row.TodayProduction,
+		})
+	}
+	return overviews, nil
+}
+
+func (s *CompanyUserAdminService) companyUsersQuery(ctx context.Context, companyID string) *gorm.DB {
+	return s.db.WithContext(ctx).
+		Table("users AS u").
+		Where("u.deleted_at IS NULL").
+		Where("u.id IN ("+companyUserIDsSQL+")", companyUserIDsArgs(companyID)...)
+}
+
+type companyUserRow struct {
+	ID          string     `gorm:"column:id"`
+	Username    string     `gorm:"column:username"`
+	Name        string     `gorm:"column:name"`
+	Email       *string    `gorm:"column:email"`
+	Phone       *string    `gorm:"column:phone"`
+	Role        string     `gorm:"column:role"`
+	IsActive    bool       `gorm:"column:is_active"`
+	LockedUntil *time.Time `gorm:"column:locked_until"`
+	CreatedAt   time.Time  `gorm:"column:created_at"`
+}
+
+// loadUsers scans users selected by query and attaches assignment names and
+// session state in batch.
+func (s *CompanyUserAdminService) loadUsers(ctx context.Context, query *gorm.DB) ([]*models.CompanyUser, error) {
+	var rows []companyUserRow
+	if err := query.
+		Select("u.id, u.username, u.name, u.email, u.phone, u.role, u.is_active, u.locked_until, u.created_at").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load company users: %w", err)
+	}
+
+	users := make([]*models.CompanyUser, 0, len(rows))
+	byID := make(map[string]*models.CompanyUser, len(rows))
+	userIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		user := &models.CompanyUser{
+			ID:            row.ID,
+			Username:      row.Username,
+			Name:          row.Name,
+			Email:         row.Email,
+			Phone:         row.Phone,
+			Role:          row.Role,
+			IsActive:      row.IsActive,
+			LockedUntil:   row.LockedUntil,
+			EstateNames:   []string{},
+			DivisionNames: []string{},
+			CreatedAt:     row.CreatedAt,
+		}
+		users = append(users, user)
+		byID[row.ID] = user
+		userIDs = append(userIDs, row.ID)
+	}
+	if len(userIDs) == 0 {
+		return users, nil
+	}
+
+	var assignmentRows []struct {
+		UserID string `gorm:"column:user_id"`
+		Name   string `gorm:"column:name"`
+		Kind   string `gorm:"column:kind"`
+	}
+	if err := s.db.WithContext(ctx).Raw(`
+		SELECT uea.user_id, e.name, 'ESTATE' AS kind FROM user_estate_assignments uea
+		JOIN estates e ON e.id = uea.estate_id
+		WHERE uea.user_id IN ? AND uea.is_active = true
+		UNION ALL
+		SELECT uda.user_id, d.name, 'DIVISION' AS kind FROM user_division_assignments uda
+		JOIN divisions d ON d.id = uda.division_id
+		WHERE uda.user_id IN ? AND uda.is_active = true
+	`, userIDs, userIDs).Scan(&assignmentRows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load user assignments: %w", err)
+	}
+	for _, row := range assignmentRows {
+		user := byID[row.UserID]
+		if user == nil {
+			continue
+		}
+		if row.Kind == "ESTATE" {
+			user.EstateNames = append(user.EstateNames, row.Name)
+		} else {
+			user.DivisionNames = append(user.DivisionNames, row.Name)
+		}
+	}
+
+	now := time.Now()
+	var sessionRows []struct {
+		UserID    string     `gorm:"column:user_id"`
+		LastLogin *time.Time `gorm:"column:last_login"`
+		Online    int        `gorm:"column:online"`
+	}
+	if err := s.db.WithContext(ctx).Raw(`
+		SELECT
+			s.user_id,
+			MAX(s.created_at) AS last_login,
+			MAX(CASE WHEN s.is_active = true AND s.expires_at > ? AND s.last_activity >= ? THEN 1 ELSE 0 END) AS online
+		FROM user_sessions s
+		WHERE s.user_id IN ?
+		GROUP BY s.user_id
+	`, now, now.Add(-onlineActivityWindow), userIDs).Scan(&sessionRows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load user sessions: %w", err)
+	}
+	for _, row := range sessionRows {
+		if user := byID[row.UserID]; user != nil {
+			user.LastLogin = row.LastLogin
+			user.IsOnline = row.Online > 0
+		}
+	}
+
+	for _, user := range users {
+		sort.Strings(user.EstateNames)
+		sort.Strings(user.DivisionNames)
+	}
+	return users, nil
+}
+
+func (s *CompanyUserAdminService) afterAssignmentChange(
+	ctx context.Context,
+	actor UserAdminActor,
+	userID, description string,
+	metadata map[string]interface{},
+) (*models.CompanyUser, error) {
+	updated, err := s.GetUser(ctx, actor.CompanyID, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.recordUserActivity(ctx, actor, models.ActivityUserUpdated, updated, description, metadata)
+	return updated, nil
+}
+
+// recordUserActivity logs an activity about user. Audit failures are logged
+// and do not undo the change that was already committed.
+func (s *CompanyUserAdminService) recordUserActivity(
+	ctx context.Context,
+	actor UserAdminActor,
+	activityType string,
+	user *models.CompanyUser,
+	description string,
+	metadata map[string]interface{},
+) {
+	if _, err := s.RecordActivity(ctx, actor, activityType, models.ActivityEntityUser, user.ID, description, metadata); err != nil {
+		fmt.Printf("⚠️ [CompanyUserAdmin] failed to record %s for user %s: %v\n", activityType, user.ID, err)
+	}
+}
+
+// PublishUserStatus pushes the user to userStatusChange subscribers.
+func (s *CompanyUserAdminService) PublishUserStatus(companyID string, user *models.CompanyUser) {
+	if s.events != nil && user != nil {
+		s.events.PublishUserStatusChange(companyID, user)
+	}
+}
+
+func (s *CompanyUserAdminService) clearLockout(ctx context.Context, userID string) error {
+	if err := s.db.WithContext(ctx).
+		Table("users").
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"failed_login_attempts": 0,
+			"locked_until":          nil,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to unlock user: %w", err)
+	}
+	return nil
+}
+
+// revokeSessions ends the user's web sessions and revokes their access and
+// refresh tokens, so a deactivated or reset user is signed out everywhere.
+func (s *CompanyUserAdminService) revokeSessions(ctx context.Context, userID, reason string) error {
+	revoker := authServices.NewSessionTokenRevoker(s.db, reason)
+	if err := revoker.RevokeAllByUserID(ctx, nil, userID, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke user sessions: %w", err)
+	}
+	return nil
+}
+
+func (s *CompanyUserAdminService) companyEstateName(ctx context.Context, companyID, estateID string) (string, error) {
+	var names []string
+	if err := s.db.WithContext(ctx).
+		Table("estates").
+		Where("id = ? AND company_id = ?", strings.TrimSpace(estateID), companyID).
+		Limit(1).
+		Pluck("name", &names).Error; err != nil {
+		return "", fmt.Errorf("failed to load estate: %w", err)
+	}
+	if len(names) == 0 {
+		return "", ErrEstateNotInCompany
+	}
+	return names[0], nil
+}
+
+type companyDivision struct {
+	ID       string `gorm:"column:id"`
+	Name     string `gorm:"column:name"`
+	EstateID string `gorm:"column:estate_id"`
+}
+
+func (s *CompanyUserAdminService) companyDivision(ctx context.Context, companyID, divisionID string) (*companyDivision, error) {
+	var rows []companyDivision
+	if err := s.db.WithContext(ctx).
+		Table("divisions AS d").
+		Select("d.id, d.name, d.estate_id").
+		Joins("JOIN estates e ON e.id = d.estate_id").
+		Where("d.id = ? AND e.company_id = ?", strings.TrimSpace(divisionID), companyID).
+		Limit(1).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load division: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, ErrDivisionNotInCompany
+	}
+	return &rows[0], nil
+}
+
+// upsertAssignment reactivates an existing assignment row or inserts a new one.
+func upsertAssignment(tx *gorm.DB, table, targetColumn, userID, targetID, assignedBy string) error {
+	now := time.Now()
+	result := tx.Table(table).
+		Where("user_id = ? AND "+targetColumn+" = ?", userID, targetID).
+		Updates(map[string]interface{}{
+			"is_active":   true,
+			"assigned_by": assignedBy,
+			"assigned_at": now,
+			"updated_at":  now,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update %s: %w", table, result.Error)
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	if err := tx.Table(table).Create(map[string]interface{}{
+		"id":          gorm.Expr("gen_random_uuid()"),
+		"user_id":     userID,
+		targetColumn:  targetID,
+		"is_active":   true,
+		"assigned_by": assignedBy,
+		"assigned_at": now,
+		"created_at":  now,
+		"updated_at":  now,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to create %s: %w", table, err)
+	}
+	return nil
+}
+
+func deactivateAssignment(db *gorm.DB, table, targetColumn, userID, targetID string) error {
+	result := db.Table(table).
+		Where("user_id = ? AND "+targetColumn+" = ? AND is_active = ?", userID, targetID, true).
+		Updates(map[string]interface{}{
+			"is_active":  false,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update %s: %w", table, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: user has no active assignment to remove", ErrAssignmentNotAllowed)
+	}
+	return nil
+}
+
+// NormalizeCompanyUserPage applies the default and maximum page size used by
+// ListUsers.
+func NormalizeCompanyUserPage(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultCompanyUserPageSize
+	}
+	if pageSize > maxCompanyUserPageSize {
+		pageSize = maxCompanyUserPageSize
+	}
+	return page, pageSize
+}
+
+func clampActivityLimit(limit int) int {
+	if limit < 1 {
+		return defaultActivityLogLimit
+	}
+	if limit > maxActivityLogLimit {
+		return maxActivityLogLimit
+	}
+	return limit
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
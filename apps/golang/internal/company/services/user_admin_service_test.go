@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sharedDomain "agrinovagraphql/server/internal/auth/features/shared/domain"
+	"agrinovagraphql/server/internal/company/models"
+	"agrinovagraphql/server/internal/graphql/domain/auth"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeUserLifecycle writes the status flip directly and records resets.
+type fakeUserLifecycle struct {
+	db             *gorm.DB
+	resetPasswords map[string]string
+}
+
+func (f *fakeUserLifecycle) ToggleStatus(ctx context.Context, id string) (*sharedDomain.User, error) {
+	if err := f.db.WithContext(ctx).Exec(`UPDATE users SET is_active = NOT is_active WHERE id = ?`, id).Error; err != nil {
+		return nil, err
+	}
+	return &sharedDomain.User{ID: id}, nil
+}
+
+func (f *fakeUserLifecycle) DeleteUser(ctx context.Context, id string) error {
+	return f.db.WithContext(ctx).Exec(`UPDATE users SET deleted_at = ? WHERE id = ?`, time.Now(), id).Error
+}
+
+func (f *fakeUserLifecycle) ResetPassword(ctx context.Context, id string, newPassword string) error {
+	f.resetPasswords[id] = newPassword
+	return nil
+}
+
+type recordingUserAdminEvents struct {
+	statusChanges []*models.CompanyUser
+	activities    []*models.AdminActivityLog
+}
+
+func (r *recordingUserAdminEvents) PublishUserStatusChange(companyID string, user *models.CompanyUser) {
+	r.statusChanges = append(r.statusChanges, user)
+}
+
+func (r *recordingUserAdminEvents) PublishAdminActivity(entry *models.AdminActivityLog) {
+	r.activities = append(r.activities, entry)
+}
+
+func setupUserAdminTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db := openCompanyTestDB(t)
+	for _, stmt := range []string{
+		`CREATE TABLE users (
+			id TEXT PRIMARY KEY,
+			username TEXT,
+			name TEXT,
+			email TEXT,
+			phone TEXT,
+			role TEXT,
+			is_active BOOLEAN DEFAULT true,
+			failed_login_attempts INTEGER DEFAULT 0,
+			locked_until DATETIME,
+			created_at DATETIME,
+			deleted_at DATETIME
+		)`,
+		`CREATE TABLE estates (id TEXT PRIMARY KEY, company_id TEXT, name TEXT)`,
+		`CREATE TABLE divisions (id TEXT PRIMARY KEY, estate_id TEXT, name TEXT)`,
+		`CREATE TABLE user_company_assignments (user_id TEXT, company_id TEXT, is_active BOOLEAN)`,
+		`CREATE TABLE user_estate_assignments (
+			id TEXT, user_id TEXT, estate_id TEXT, is_active BOOLEAN,
+			assigned_by TEXT, assigned_at DATETIME, created_at DATETIME, updated_at DATETIME
+		)`,
+		`CREATE TABLE user_division_assignments (
+			id TEXT, user_id TEXT, division_id TEXT, is_active BOOLEAN,
+			assigned_by TEXT, assigned_at DATETIME, created_at DATETIME, updated_at DATETIME
+		)`,
+		`CREATE TABLE user_sessions (
+			user_id TEXT, is_active BOOLEAN, expires_at DATETIME,
+			last_activity DATETIME, created_at DATETIME, updated_at DATETIME,
+			revoked BOOLEAN DEFAULT false, revoked_reason TEXT
+		)`,
+		`CREATE TABLE jwt_tokens (
+			user_id TEXT, is_revoked BOOLEAN DEFAULT false, revoked_at DATETIME, updated_at DATETIME
+		)`,
+		`CREATE TABLE admin_activity_logs (
+			id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+			company_id TEXT, activity_type TEXT, actor_id TEXT, actor_name TEXT,
+			description TEXT, entity_type TEXT, entity_id TEXT, ip_address TEXT,
+			metadata_json TEXT, created_at DATETIME
+		)`,
+		`INSERT INTO estates VALUES ('estate-1', 'company-1', 'Estate Satu'), ('estate-2', 'company-1', 'Estate Dua'), ('estate-x', 'company-2', 'Other Estate')`,
+		`INSERT INTO divisions VALUES ('division-1', 'estate-1', 'Divisi A'), ('division-2', 'estate-2', 'Divisi B')`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+	return db
+}
+
+func seedAdminTestUser(t *testing.T, db *gorm.DB, id, name, role string, active bool) {
+	t.Helper()
+	require.NoError(t, db.Exec(
+		`INSERT INTO users (id, username, name, role, is_active, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		id, id, name, role, active, time.Now(),
+	).Error)
+}
+
+func newTestUserAdminService(db *gorm.DB) (*CompanyUserAdminService, *fakeUserLifecycle, *recordingUserAdminEvents) {
+	lifecycle := &fakeUserLifecycle{db: db, resetPasswords: map[string]string{}}
+	events := &recordingUserAdminEvents{}
+	service := NewCompanyUserAdminService(db, nil, lifecycle)
+	service.SetEventPublisher(events)
+	return service, lifecycle, events
+}
+
+var testCompanyAdmin = UserAdminActor{UserID: "admin-1", Name: "Admin", Role: auth.UserRoleCompanyAdmin, CompanyID: "company-1"}
+
+func TestAuthorizeRole(t *testing.T) {
+	service := NewCompanyUserAdminService(nil, nil, nil)
+	actor := UserAdminActor{UserID: "admin-1", Role: auth.UserRoleCompanyAdmin, CompanyID: "company-1"}
+
+	assert.NoError(t, service.AuthorizeRole(actor, "MANAGER"))
+	assert.NoError(t, service.AuthorizeRole(actor, " mandor "))
+	assert.ErrorIs(t, service.AuthorizeRole(actor, "SUPER_ADMIN"), ErrRoleNotManageable)
+	assert.ErrorIs(t, service.AuthorizeRole(actor, "COMPANY_ADMIN"), ErrRoleNotManageable)
+}
+
+func TestNormalizeCompanyUserPage(t *testing.T) {
+	page, size := NormalizeCompanyUserPage(0, 0)
+	assert.Equal(t, 1, page)
+	assert.Equal(t, defaultCompanyUserPageSize, size)
+
+	page, size = NormalizeCompanyUserPage(3, 1000)
+	assert.Equal(t, 3, page)
+	assert.Equal(t, maxCompanyUserPageSize, size)
+
+	assert.Equal(t, defaultActivityLogLimit, clampActivityLimit(0))
+	assert.Equal(t, maxActivityLogLimit, clampActivityLimit(10000))
+}
+
+func TestCompanyUserAdminService_ListUsers(t *testing.T) {
+	db := setupUserAdminTestDB(t)
+	service, _, _ := newTestUserAdminService(db)
+	ctx := context.Background()
+
+	seedAdminTestUser(t, db, "manager-1", "Budi", "MANAGER", true)
+	seedAdminTestUser(t, db, "mandor-1", "Citra", "MANDOR", false)
+	seedAdminTestUser(t, db, "asisten-1", "Andi", "ASISTEN", true)
+	seedAdminTestUser(t, db, "outsider", "Zed", "MANDOR", true)
+	require.NoError(t, db.Exec(`INSERT INTO user_company_assignments VALUES ('manager-1', 'company-1', true), ('outsider', 'company-2', true)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO user_estate_assignments (user_id, estate_id, is_active) VALUES ('manager-1', 'estate-1', true)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO user_division_assignments (user_id, division_id, is_active) VALUES ('mandor-1', 'division-1', true), ('asisten-1', 'division-2', true)`).Error)
+
+	users, total, err := service.ListUsers(ctx, "company-1", models.CompanyUserFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total, "the user of another company is excluded")
+	require.Len(t, users, 3)
+	assert.Equal(t, []string{"Andi", "Budi", "Citra"}, []string{users[0].Name, users[1].Name, users[2].Name})
+	assert.Equal(t, []string{"Estate Satu"}, users[1].EstateNames)
+	assert.Equal(t, []string{"Divisi A"}, users[2].DivisionNames)
+
+	users, total, err = service.ListUsers(ctx, "company-1", models.CompanyUserFilter{ActiveOnly: true, Page: 2, PageSize: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, users, 1)
+	assert.Equal(t, "Budi", users[0].Name)
+
+	estateID := "estate-1"
+	users, _, err = service.ListUsers(ctx, "company-1", models.CompanyUserFilter{EstateID: &estateID})
+	require.NoError(t, err)
+	assert.Len(t, users, 2, "estate filter includes users assigned through a division")
+
+	role := "asisten"
+	users, _, err = service.ListUsers(ctx, "company-1", models.CompanyUserFilter{Role: &role})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	assert.Equal(t, "asisten-1", users[0].ID)
+}
+
+func TestCompanyUserAdminService_AuthorizeUser(t *testing.T) {
+	db := setupUserAdminTestDB(t)
+	service, _, _ := newTestUserAdminService(db)
+	ctx := context.Background()
+
+	seedAdminTestUser(t, db, "manager-1", "Budi", "MANAGER", true)
+	seedAdminTestUser(t, db, "admin-2", "Other Admin", "COMPANY_ADMIN", true)
+	seedAdminTestUser(t, db, "outsider", "Zed", "MANDOR", true)
+	require.NoError(t, db.Exec(`INSERT INTO user_company_assignments VALUES ('manager-1', 'company-1', true), ('admin-2', 'company-1', true), ('outsider', 'company-2', true)`).Error)
+
+	user, err := service.AuthorizeUser(ctx, testCompanyAdmin, "manager-1")
+	require.NoError(t, err)
+	assert.Equal(t, "MANAGER", user.Role)
+
+	_, err = service.AuthorizeUser(ctx, testCompanyAdmin, "admin-1")
+	assert.ErrorIs(t, err, ErrCannotManageSelf)
+	_, err = service.AuthorizeUser(ctx, testCompanyAdmin, "admin-2")
+	assert.ErrorIs(t, err, ErrRoleNotManageable)
+	_, err = service.AuthorizeUser(ctx, testCompanyAdmin, "outsider")
+	assert.ErrorIs(t, err, ErrUserNotInCompany)
+}
+
+func TestCompanyUserAdminService_SetUserActive(t *testing.T) {
+	db := setupUserAdminTestDB(t)
+	service, _, events := newTestUserAdminService(db)
+	ctx := context.Background()
+
+	seedAdminTestUser(t, db, "mandor-1", "Citra", "MANDOR", true)
+	require.NoError(t, db.Exec(`INSERT INTO user_company_assignments VALUES ('mandor-1', 'company-1', true)`).Error)
+	// created_at stays NULL: SQLite returns MAX(datetime) as text.
+	require.NoError(t, db.Exec(`INSERT INTO user_sessions (user_id, is_active, expires_at, last_activity) VALUES ('mandor-1', true, ?, ?)`,
+		time.Now().Add(time.Hour), time.Now()).Error)
+	require.NoError(t, db.Exec(`INSERT INTO jwt_tokens (user_id) VALUES ('mandor-1')`).Error)
+
+	reason := "left the company"
+	user, err := service.SetUserActive(ctx, testCompanyAdmin, "mandor-1", false, &reason)
+	require.NoError(t, err)
+	assert.False(t, user.IsActive)
+	assert.False(t, user.IsOnline)
+
+	var activeSessions int64
+	require.NoError(t, db.Table("user_sessions").Where("user_id = ? AND is_active = ?", "mandor-1", true).Count(&activeSessions).Error)
+	assert.Zero(t, activeSessions, "deactivation revokes sessions")
+	assertTokensRevoked(t, db, "mandor-1")
+
+	require.Len(t, events.statusChanges, 1)
+	require.Len(t, events.activities, 1)
+	assert.Equal(t, models.ActivityUserDeactivated, events.activities[0].Type)
+	assert.Contains(t, events.activities[0].Description, reason)
+
+	user, err = service.SetUserActive(ctx, testCompanyAdmin, "mandor-1", true, nil)
+	require.NoError(t, err)
+	assert.True(t, user.IsActive)
+	assert.Equal(t, models.ActivityUserActivated, events.activities[1].Type)
+
+	entries, err := service.ListActivities(ctx, "company-1", models.AdminActivityFilter{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestCompanyUserAdminService_ResetPassword(t *testing.T) {
+	db := setupUserAdminTestDB(t)
+	service, lifecycle, _ := newTestUserAdminService(db)
+	ctx := context.Background()
+
+	seedAdminTestUser(t, db, "mandor-1", "Citra", "MANDOR", true)
+	require.NoError(t, db.Exec(`INSERT INTO user_company_assignments VALUES ('mandor-1', 'company-1', true)`).Error)
+	require.NoError(t, db.Exec(`UPDATE users SET failed_login_attempts = 5, locked_until = ? WHERE id = 'mandor-1'`, time.Now().Add(time.Hour)).Error)
+	require.NoError(t, db.Exec(`INSERT INTO jwt_tokens (user_id) VALUES ('mandor-1'), ('mandor-1')`).Error)
+
+	_, err := service.ResetPassword(ctx, testCompanyAdmin, "mandor-1", "short")
+	assert.ErrorIs(t, err, ErrAdminPasswordTooWeak)
+	assert.Empty(t, lifecycle.resetPasswords)
+
+	user, err := service.ResetPassword(ctx, testCompanyAdmin, "mandor-1", "n3w-Passw0rd")
+	require.NoError(t, err)
+	assert.Equal(t, "n3w-Passw0rd", lifecycle.resetPasswords["mandor-1"])
+	assert.False(t, user.IsLocked(time.Now()), "a reset also clears the lockout")
+	assertTokensRevoked(t, db, "mandor-1")
+}
+
+// assertTokensRevoked checks that none of the user's JWT tokens stay usable.
+func assertTokensRevoked(t *testing.T, db *gorm.DB, userID string) {
+	t.Helper()
+	var usable int64
+	require.NoError(t, db.Table("jwt_tokens").Where("user_id = ? AND is_revoked = ?", userID, false).Count(&usable).Error)
+	assert.Zero(t, usable, "access and refresh tokens are revoked")
+}
+
+func TestCompanyUserAdminService_UnlockUser(t *testing.T) {
+	db := setupUserAdminTestDB(t)
+	service, _, _ := newTestUserAdminService(db)
+	ctx := context.Background()
+
+	seedAdminTestUser(t, db, "mandor-1", "Citra", "MANDOR", true)
+	require.NoError(t, db.Exec(`INSERT INTO user_company_assignments VALUES ('mandor-1', 'company-1', true)`).Error)
+	require.NoError(t, db.Exec(`UPDATE users SET failed_login_attempts = 5, locked_until = ? WHERE id = 'mandor-1'`, time.Now().Add(time.Hour)).Error)
+
+	user, err := service.GetUser(ctx, "company-1", "mandor-1")
+	require.NoError(t, err)
+	assert.True(t, user.IsLocked(time.Now()))
+
+	user, err = service.UnlockUser(ctx, testCompanyAdmin, "mandor-1")
+	require.NoError(t, err)
+	assert.False(t, user.IsLocked(time.Now()))
+}
+
+func TestCompanyUserAdminService_AssignEstate(t *testing.T) {
+	db := setupUserAdminTestDB(t)
+	service, _, _ := newTestUserAdminService(db)
+	ctx := context.Background()
+
+	seedAdminTestUser(t, db, "manager-1", "Budi", "MANAGER", true)
+	seedAdminTestUser(t, db, "manager-2", "Dewi", "MANAGER", true)
+	seedAdminTestUser(t, db, "mandor-1", "Citra", "MANDOR", true)
+	seedAdminTestUser(t, db, "timbangan-1", "Eko", "TIMBANGAN", true)
+	require.NoError(t, db.Exec(`INSERT INTO user_company_assignments VALUES
+		('manager-1', 'company-1', true), ('manager-2', 'company-1', true),
+		('mandor-1', 'company-1', true), ('timbangan-1', 'company-1', true)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO user_estate_assignments (user_id, estate_id, is_active) VALUES
+		('manager-1', 'estate-1', true), ('mandor-1', 'estate-1', true), ('manager-2', 'estate-2', false)`).Error)
+
+	_, err := service.AssignEstate(ctx, testCompanyAdmin, "manager-2", "estate-1")
+	assert.ErrorIs(t, err, ErrAssignmentNotAllowed, "estate-1 already has an active manager")
+
+	_, err = service.AssignEstate(ctx, testCompanyAdmin, "mandor-1", "estate-2")
+	assert.ErrorIs(t, err, ErrAssignmentNotAllowed, "a mandor holds exactly one estate")
+
+	_, err = service.AssignEstate(ctx, testCompanyAdmin, "timbangan-1", "estate-2")
+	assert.ErrorIs(t, err, ErrAssignmentNotAllowed)
+
+	_, err = service.AssignEstate(ctx, testCompanyAdmin, "manager-2", "estate-x")
+	assert.ErrorIs(t, err, ErrEstateNotInCompany)
+
+	user, err := service.AssignEstate(ctx, testCompanyAdmin, "manager-2", "estate-2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Estate Dua"}, user.EstateNames, "the inactive assignment is reactivated")
+
+	user, err = service.RemoveEstate(ctx, testCompanyAdmin, "manager-2", "estate-2")
+	require.NoError(t, err)
+	assert.Empty(t, user.EstateNames)
+
+	_, err = service.RemoveEstate(ctx, testCompanyAdmin, "manager-2", "estate-2")
+	assert.ErrorIs(t, err, ErrAssignmentNotAllowed)
+}
+
+func TestCompanyUserAdminService_AssignDivision(t *testing.T) {
+	db := setupUserAdminTestDB(t)
+	service, _, _ := newTestUserAdminService(db)
+	ctx := context.Background()
+
+	seedAdminTestUser(t, db, "mandor-1", "Citra", "MANDOR", true)
+	seedAdminTestUser(t, db, "manager-1", "Budi", "MANAGER", true)
+	require.NoError(t, db.Exec(`INSERT INTO user_company_assignments VALUES ('mandor-1', 'company-1', true), ('manager-1', 'company-1', true)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO user_estate_assignments (user_id, estate_id, is_active) VALUES ('mandor-1', 'estate-1', true)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO user_division_assignments (user_id, division_id, is_active) VALUES ('mandor-1', 'division-1', false)`).Error)
+
+	_, err := service.AssignDivision(ctx, testCompanyAdmin, "mandor-1", "division-2")
+	assert.ErrorIs(t, err, ErrAssignmentNotAllowed, "division-2 is outside the mandor's estate")
+
+	_, err = service.AssignDivision(ctx, testCompanyAdmin, "manager-1", "division-1")
+	assert.ErrorIs(t, err, ErrAssignmentNotAllowed)
+
+	_, err = service.AssignDivision(ctx, testCompanyAdmin, "mandor-1", "division-missing")
+	assert.ErrorIs(t, err, ErrDivisionNotInCompany)
+
+	user, err := service.AssignDivision(ctx, testCompanyAdmin, "mandor-1", "division-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Divisi A"}, user.DivisionNames)
+}
@@ -339,6 +339,7 @@ type GateCheckPhoto struct {
 	DeviceID           string       `json:"device_id" gorm:"not null"`
 	LocalPath          string       `json:"local_path" gorm:"not null"`
 	CloudPath          *string      `json:"cloud_path"`
+	ThumbnailPath      *string      `json:"thumbnail_path"`
 	FileHash           string       `json:"file_hash" gorm:"not null"`
 	SyncStatus         SyncStatus   `json:"sync_status" gorm:"type:varchar(20);default:'PENDING'"`
 	CreatedAt          time.Time    `json:"created_at"`
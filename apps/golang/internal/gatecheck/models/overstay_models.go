@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	satpam "agrinovagraphql/server/internal/graphql/domain/satpam"
+)
+
+// GateOverstayRule is the longest a guest vehicle may stay inside a company
+// estate. Rules without a vehicle type or destination apply to all of them;
+// the company-wide default has neither.
+type GateOverstayRule struct {
+	ID             string              `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CompanyID      string              `json:"company_id" gorm:"type:uuid;not null;index"`
+	VehicleType    *satpam.VehicleType `json:"vehicle_type" gorm:"type:varchar(20)"`
+	Destination    *string             `json:"destination" gorm:"type:varchar(255)"`
+	MaxStayMinutes int32               `json:"max_stay_minutes" gorm:"not null"`
+	IsActive       bool                `json:"is_active" gorm:"not null;default:true"`
+	CreatedBy      string              `json:"created_by" gorm:"type:uuid;not null"`
+	UpdatedBy      *string             `json:"updated_by" gorm:"type:uuid"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+// TableName returns the table name for GateOverstayRule
+func (GateOverstayRule) TableName() string {
+	return "gate_overstay_rules"
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	satpam "agrinovagraphql/server/internal/graphql/domain/satpam"
+)
+
+// GateWatchlistEntry is a company-scoped plate, ID card number or name that
+// satpam must not admit without checks
+type GateWatchlistEntry struct {
+	ID              string                        `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CompanyID       string                        `json:"company_id" gorm:"type:uuid;not null;index"`
+	MatchType       satpam.GateWatchlistMatchType `json:"match_type" gorm:"type:varchar(20);not null"`
+	Value           string                        `json:"value" gorm:"type:varchar(150);not null"`
+	NormalizedValue string                        `json:"normalized_value" gorm:"type:varchar(150);not null"`
+	Reason          string                        `json:"reason" gorm:"type:text;not null"`
+	Severity        satpam.GateWatchlistSeverity  `json:"severity" gorm:"type:varchar(20);not null"`
+	ExpiresAt       *time.Time                    `json:"expires_at"`
+	IsActive        bool                          `json:"is_active" gorm:"not null;default:true"`
+	CreatedBy       string                        `json:"created_by" gorm:"type:uuid;not null"`
+	UpdatedBy       *string                       `json:"updated_by" gorm:"type:uuid"`
+	CreatedAt       time.Time                     `json:"created_at"`
+	UpdatedAt       time.Time                     `json:"updated_at"`
+}
+
+// TableName returns the table name for GateWatchlistEntry
+func (GateWatchlistEntry) TableName() string {
+	return "gate_watchlist_entries"
+}
+
+// GateWatchlistHit records a guest that matched a watchlist entry at the gate
+// and, for REQUIRE_OVERRIDE entries, the supervisor decision
+type GateWatchlistHit struct {
+	ID           string                        `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CompanyID    string                        `json:"company_id" gorm:"type:uuid;not null;index"`
+	EntryID      string                        `json:"entry_id" gorm:"type:uuid;not null;index"`
+	Source       satpam.GateWatchlistHitSource `json:"source" gorm:"type:varchar(20);not null"`
+	Status       satpam.GateWatchlistHitStatus `json:"status" gorm:"type:varchar(20);not null;index"`
+	Severity     satpam.GateWatchlistSeverity  `json:"severity" gorm:"type:varchar(20);not null"`
+	MatchType    satpam.GateWatchlistMatchType `json:"match_type" gorm:"type:varchar(20);not null"`
+	MatchedValue string                        `json:"matched_value" gorm:"type:varchar(150);not null"`
+	Reason       string                        `json:"reason" gorm:"type:text;not null"`
+	VehiclePlate string                        `json:"vehicle_plate" gorm:"type:varchar(20)"`
+	DriverName   string                        `json:"driver_name" gorm:"type:varchar(100)"`
+	IDCardNumber *string                       `json:"id_card_number" gorm:"type:varchar(50)"`
+	GuestLogID   *string                       `json:"guest_log_id" gorm:"type:uuid;index"`
+	DeviceID     string                        `json:"device_id" gorm:"type:varchar(255)"`
+	ScannedBy    string                        `json:"scanned_by" gorm:"type:uuid;not null"`
+	ReviewedBy   *string                       `json:"reviewed_by" gorm:"type:uuid"`
+	ReviewedAt   *time.Time                    `json:"reviewed_at"`
+	ReviewNotes  *string                       `json:"review_notes" gorm:"type:text"`
+	CreatedAt    time.Time                     `json:"created_at"`
+	UpdatedAt    time.Time                     `json:"updated_at"`
+}
+
+// TableName returns the table name for GateWatchlistHit
+func (GateWatchlistHit) TableName() string {
+	return "gate_watchlist_hits"
+}
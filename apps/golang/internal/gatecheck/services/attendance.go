@@ -0,0 +1,351 @@
+package services
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Attendance statuses
+const (
+	AttendanceStatusPresent    = "PRESENT"
+	AttendanceStatusIncomplete = "INCOMPLETE"
+	AttendanceStatusAbsent     = "ABSENT"
+)
+
+// Gate scan actions recorded by syncEmployeeLog
+const (
+	AttendanceActionEntry = "ENTRY"
+	AttendanceActionExit  = "EXIT"
+)
+
+const (
+	// attendanceDuplicateWindow ignores repeated scans of the same action
+	// (double taps, two satpam devices) within this window.
+	attendanceDuplicateWindow = 5 * time.Minute
+	// attendanceMaxSession is the longest ENTRY→EXIT span still paired as one
+	// session; anything longer is treated as a missing EXIT plus a missing ENTRY.
+	attendanceMaxSession = 18 * time.Hour
+	// attendanceDateLayout is the YYYY-MM-DD layout used for work dates.
+	attendanceDateLayout = "2006-01-02"
+)
+
+// AttendanceScan is a single employee gate scan.
+type AttendanceScan struct {
+	NIK       string
+	Name      string
+	Action    string
+	Gate      string
+	ScannedAt time.Time
+}
+
+// AttendanceShift is the company's default working shift in local time.
+// A shift whose end is not after its start runs overnight.
+type AttendanceShift struct {
+	StartMinute int
+	EndMinute   int
+	Location    *time.Location
+}
+
+// AttendanceSession is one ENTRY→EXIT pair. Either side may be missing; the
+// missing side is then estimated from the shift bounds.
+type AttendanceSession struct {
+	EntryAt   *time.Time
+	ExitAt    *time.Time
+	EntryGate string
+	ExitGate  string
+	Minutes   int
+	Estimated bool
+}
+
+// AttendanceDay is the attendance of one employee on one work date.
+type AttendanceDay struct {
+	NIK               string
+	Name              string
+	WorkDate          string
+	Sessions          []AttendanceSession
+	FirstEntryAt      *time.Time
+	LastExitAt        *time.Time
+	WorkedMinutes     int
+	EstimatedMinutes  int
+	ScheduledMinutes  int
+	LateMinutes       int
+	EarlyLeaveMinutes int
+	OvertimeMinutes   int
+	MissingEntry      bool
+	MissingExit       bool
+	Status            string
+}
+
+// NewAttendanceShift builds a shift from "HH:MM" bounds and an IANA timezone,
+// falling back to the company defaults (06:00–14:00 WIB) for invalid values.
+func NewAttendanceShift(start, end, timezone string) AttendanceShift {
+	startMinute, ok := parseShiftClock(start)
+	if !ok {
+		startMinute = 6 * 60
+	}
+	endMinute, ok := parseShiftClock(end)
+	if !ok {
+		endMinute = 14 * 60
+	}
+	loc, err := time.LoadLocation(strings.TrimSpace(timezone))
+	if err != nil || strings.TrimSpace(timezone) == "" {
+		loc = getWIBLocation()
+	}
+	return AttendanceShift{StartMinute: startMinute, EndMinute: endMinute, Location: loc}
+}
+
+// Overnight reports whether the shift crosses midnight.
+func (s AttendanceShift) Overnight() bool {
+	return s.EndMinute <= s.StartMinute
+}
+
+// Minutes returns the scheduled length of the shift.
+func (s AttendanceShift) Minutes() int {
+	if s.Overnight() {
+		return 24*60 - s.StartMinute + s.EndMinute
+	}
+	return s.EndMinute - s.StartMinute
+}
+
+// Bounds returns the shift start and end for a YYYY-MM-DD work date.
+func (s AttendanceShift) Bounds(workDate string) (time.Time, time.Time) {
+	day, err := time.ParseInLocation(attendanceDateLayout, workDate, s.location())
+	if err != nil {
+		return time.Time{}, time.Time{}
+	}
+	start := day.Add(time.Duration(s.StartMinute) * time.Minute)
+	return start, start.Add(time.Duration(s.Minutes()) * time.Minute)
+}
+
+// WorkDate returns the work date a scan belongs to. Day shifts use the local
+// calendar date; for overnight shifts, scans before the middle of the off-duty
+// period belong to the shift that started the previous evening.
+func (s AttendanceShift) WorkDate(t time.Time) string {
+	local := t.In(s.location())
+	if s.Overnight() {
+		offDuty := s.StartMinute - s.EndMinute
+		cutoff := s.EndMinute + offDuty/2
+		if local.Hour()*60+local.Minute() < cutoff {
+			local = local.AddDate(0, 0, -1)
+		}
+	}
+	return local.Format(attendanceDateLayout)
+}
+
+func (s AttendanceShift) location() *time.Location {
+	if s.Location == nil {
+		return getWIBLocation()
+	}
+	return s.Location
+}
+
+// PairAttendance pairs ENTRY and EXIT scans per NIK into work-date attendance
+// records. Duplicate scans are ignored, a second ENTRY without an EXIT closes
+// the previous session as missing its EXIT, and an EXIT without an open ENTRY
+// becomes a session missing its ENTRY. Results are ordered by NIK and date.
+func PairAttendance(scans []AttendanceScan, shift AttendanceShift) []*AttendanceDay {
+	byNIK := make(map[string][]AttendanceScan)
+	for _, scan := range scans {
+		nik := strings.TrimSpace(scan.NIK)
+		action := strings.ToUpper(strings.TrimSpace(scan.Action))
+		if nik == "" || (action != AttendanceActionEntry && action != AttendanceActionExit) {
+			continue
+		}
+		scan.NIK = nik
+		scan.Action = action
+		byNIK[nik] = append(byNIK[nik], scan)
+	}
+
+	days := make(map[string]*AttendanceDay)
+	for nik, employeeScans := range byNIK {
+		sort.SliceStable(employeeScans, func(i, j int) bool {
+			return employeeScans[i].ScannedAt.Before(employeeScans[j].ScannedAt)
+		})
+
+		var open *AttendanceScan
+		var last *AttendanceScan
+		addSession := func(entry, exit *AttendanceScan) {
+			anchor := entry
+			if anchor == nil {
+				anchor = exit
+			}
+			workDate := shift.WorkDate(anchor.ScannedAt)
+			key := nik + "|" + workDate
+			day, ok := days[key]
+			if !ok {
+				day = &AttendanceDay{NIK: nik, WorkDate: workDate}
+				days[key] = day
+			}
+			if day.Name == "" {
+				day.Name = strings.TrimSpace(anchor.Name)
+			}
+			day.Sessions = append(day.Sessions, buildAttendanceSession(entry, exit, shift, workDate))
+		}
+
+		for i := range employeeScans {
+			scan := &employeeScans[i]
+			if last != nil && last.Action == scan.Action && scan.ScannedAt.Sub(last.ScannedAt) < attendanceDuplicateWindow {
+				continue
+			}
+			last = scan
+
+			switch scan.Action {
+			case AttendanceActionEntry:
+				if open != nil {
+					addSession(open, nil)
+				}
+				open = scan
+			case AttendanceActionExit:
+				if open != nil && scan.ScannedAt.Sub(open.ScannedAt) <= attendanceMaxSession {
+					addSession(open, scan)
+					open = nil
+					continue
+				}
+				if open != nil {
+					addSession(open, nil)
+					open = nil
+				}
+				addSession(nil, scan)
+			}
+		}
+		if open != nil {
+			addSession(open, nil)
+		}
+	}
+
+	result := make([]*AttendanceDay, 0, len(days))
+	for _, day := range days {
+		summarizeAttendanceDay(day, shift)
+		result = append(result, day)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].NIK != result[j].NIK {
+			return result[i].NIK < result[j].NIK
+		}
+		return result[i].WorkDate < result[j].WorkDate
+	})
+	return result
+}
+
+func buildAttendanceSession(entry, exit *AttendanceScan, shift AttendanceShift, workDate string) AttendanceSession {
+	session := AttendanceSession{}
+	shiftStart, shiftEnd := shift.Bounds(workDate)
+
+	var start, end time.Time
+	if entry != nil {
+		entryAt := entry.ScannedAt
+		session.EntryAt = &entryAt
+		session.EntryGate = entry.Gate
+		start = entryAt
+	}
+	if exit != nil {
+		exitAt := exit.ScannedAt
+		session.ExitAt = &exitAt
+		session.ExitGate = exit.Gate
+		end = exitAt
+	}
+
+	switch {
+	case entry == nil:
+		start = shiftStart
+		session.Estimated = true
+	case exit == nil:
+		end = shiftEnd
+		session.Estimated = true
+	}
+
+	if end.After(start) {
+		session.Minutes = int(end.Sub(start) / time.Minute)
+	}
+	return session
+}
+
+func summarizeAttendanceDay(day *AttendanceDay, shift AttendanceShift) {
+	sort.SliceStable(day.Sessions, func(i, j int) bool {
+		return sessionAnchor(day.Sessions[i]).Before(sessionAnchor(day.Sessions[j]))
+	})
+
+	day.ScheduledMinutes = shift.Minutes()
+	for _, session := range day.Sessions {
+		day.WorkedMinutes += session.Minutes
+		if session.Estimated {
+			day.EstimatedMinutes += session.Minutes
+		}
+		if session.EntryAt == nil {
+			day.MissingEntry = true
+		} else if day.FirstEntryAt == nil {
+			day.FirstEntryAt = session.EntryAt
+		}
+		if session.ExitAt == nil {
+			day.MissingExit = true
+		} else {
+			day.LastExitAt = session.ExitAt
+		}
+	}
+
+	shiftStart, shiftEnd := shift.Bounds(day.WorkDate)
+	first := day.Sessions[0]
+	if first.EntryAt != nil && first.EntryAt.After(shiftStart) {
+		day.LateMinutes = int(first.EntryAt.Sub(shiftStart) / time.Minute)
+	}
+	lastSession := day.Sessions[len(day.Sessions)-1]
+	if lastSession.ExitAt != nil && lastSession.ExitAt.Before(shiftEnd) {
+		day.EarlyLeaveMinutes = int(shiftEnd.Sub(*lastSession.ExitAt) / time.Minute)
+	}
+	if day.WorkedMinutes > day.ScheduledMinutes {
+		day.OvertimeMinutes = day.WorkedMinutes - day.ScheduledMinutes
+	}
+
+	day.Status = AttendanceStatusPresent
+	if day.MissingEntry || day.MissingExit {
+		day.Status = AttendanceStatusIncomplete
+	}
+}
+
+func sessionAnchor(session AttendanceSession) time.Time {
+	if session.EntryAt != nil {
+		return *session.EntryAt
+	}
+	if session.ExitAt != nil {
+		return *session.ExitAt
+	}
+	return time.Time{}
+}
+
+// AttendanceWorkingDays counts the working days (Monday–Saturday) between two
+// YYYY-MM-DD dates, inclusive.
+func AttendanceWorkingDays(from, to string) int {
+	start, err := time.Parse(attendanceDateLayout, from)
+	if err != nil {
+		return 0
+	}
+	end, err := time.Parse(attendanceDateLayout, to)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() != time.Sunday {
+			count++
+		}
+	}
+	return count
+}
+
+func parseShiftClock(value string) (int, bool) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 2 {
+		return 0, false
+	}
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, false
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, false
+	}
+	return hour*60 + minute, true
+}
@@ -0,0 +1,657 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	companyModels "agrinovagraphql/server/internal/company/models"
+	companyServices "agrinovagraphql/server/internal/company/services"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// unassignedDivisionName labels employees without a division in reports.
+	unassignedDivisionName = "Tanpa Divisi"
+	// maxAttendanceRangeDays bounds the per-employee attendance query.
+	maxAttendanceRangeDays = 92
+)
+
+// AttendanceService builds attendance reports from employee gate scans.
+type AttendanceService struct {
+	db       *gorm.DB
+	settings *companyServices.CompanySettingsService
+}
+
+// NewAttendanceService creates a new attendance service
+func NewAttendanceService(db *gorm.DB) *AttendanceService {
+	return &AttendanceService{
+		db:       db,
+		settings: companyServices.NewCompanySettingsService(db),
+	}
+}
+
+// AttendanceEmployee is an employee on the company roster.
+type AttendanceEmployee struct {
+	ID           string  `gorm:"column:id"`
+	NIK          string  `gorm:"column:nik"`
+	Name         string  `gorm:"column:name"`
+	Role         string  `gorm:"column:role"`
+	DivisionID   *string `gorm:"column:division_id"`
+	DivisionName string  `gorm:"column:division_name"`
+}
+
+// EmployeeAttendanceDay is an attendance day joined with the employee roster.
+// Day.Status is ABSENT and Day.Sessions empty when the employee has no scans.
+type EmployeeAttendanceDay struct {
+	EmployeeID   *string
+	DivisionID   *string
+	DivisionName string
+	Day          *AttendanceDay
+}
+
+// DivisionAttendanceSummary aggregates attendance per division. For monthly
+// reports the counts are employee-days.
+type DivisionAttendanceSummary struct {
+	DivisionID      *string
+	DivisionName    string
+	EmployeeCount   int
+	PresentCount    int
+	IncompleteCount int
+	AbsentCount     int
+	LateCount       int
+	WorkedMinutes   int
+	OvertimeMinutes int
+}
+
+// HarvestGateDiscrepancy is a harvester with harvest records on a day
+// without an ENTRY scan at the gate.
+type HarvestGateDiscrepancy struct {
+	WorkDate         string
+	NIK              string
+	EmployeeID       *string
+	EmployeeName     string
+	DivisionID       *string
+	HarvestRecordIDs []string
+}
+
+// DailyAttendanceReport is the attendance of a company on one work date.
+type DailyAttendanceReport struct {
+	CompanyID              string
+	Date                   string
+	ShiftStart             string
+	ShiftEnd               string
+	Timezone               string
+	Divisions              []*DivisionAttendanceSummary
+	Employees              []*EmployeeAttendanceDay
+	HarvestersWithoutEntry []*HarvestGateDiscrepancy
+}
+
+// EmployeeAttendanceSummary totals one employee's attendance over a period.
+type EmployeeAttendanceSummary struct {
+	EmployeeID      *string
+	NIK             string
+	Name            string
+	DivisionID      *string
+	DivisionName    string
+	WorkingDays     int
+	PresentDays     int
+	IncompleteDays  int
+	AbsentDays      int
+	LateDays        int
+	WorkedMinutes   int
+	OvertimeMinutes int
+}
+
+// MonthlyAttendanceReport is the attendance of a company over a month.
+type MonthlyAttendanceReport struct {
+	CompanyID              string
+	Year                   int
+	Month                  int
+	DateFrom               string
+	DateTo                 string
+	WorkingDays            int
+	ShiftStart             string
+	ShiftEnd               string
+	Timezone               string
+	Divisions              []*DivisionAttendanceSummary
+	Employees              []*EmployeeAttendanceSummary
+	HarvestersWithoutEntry []*HarvestGateDiscrepancy
+}
+
+type attendanceScanRow struct {
+	NIK          string     `gorm:"column:nik"`
+	Nama         string     `gorm:"column:nama"`
+	Action       string     `gorm:"column:action"`
+	GatePosition string     `gorm:"column:gate_position"`
+	ScannedAt    *time.Time `gorm:"column:scanned_at"`
+}
+
+type attendanceHarvestRow struct {
+	ID                 string    `gorm:"column:id"`
+	Tanggal            time.Time `gorm:"column:tanggal"`
+	NIK                *string   `gorm:"column:nik"`
+	EmployeeNIK        *string   `gorm:"column:employee_nik"`
+	KaryawanID         *string   `gorm:"column:karyawan_id"`
+	Karyawan           string    `gorm:"column:karyawan"`
+	DivisionID         *string   `gorm:"column:division_id"`
+	EmployeeDivisionID *string   `gorm:"column:employee_division_id"`
+	RosterDivisionID   *string   `gorm:"column:roster_division_id"`
+}
+
+// attendancePeriod holds everything needed to report a date range.
+type attendancePeriod struct {
+	shift      AttendanceShift
+	shiftStart string
+	shiftEnd   string
+	timezone   string
+	roster     []*AttendanceEmployee
+	rosterNIK  map[string]*AttendanceEmployee
+	days       map[string]*AttendanceDay
+	unmatched  []*AttendanceDay
+	harvesters []*HarvestGateDiscrepancy
+}
+
+// DailyReport returns the attendance of every active employee on a work date
+// (YYYY-MM-DD), optionally limited to one division.
+func (s *AttendanceService) DailyReport(ctx context.Context, companyID, date string, divisionID *string) (*DailyAttendanceReport, error) {
+	if _, err := time.Parse(attendanceDateLayout, date); err != nil {
+		return nil, fmt.Errorf("tanggal tidak valid, gunakan format YYYY-MM-DD")
+	}
+
+	period, err := s.loadPeriod(ctx, companyID, date, date, divisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	employees := make([]*EmployeeAttendanceDay, 0, len(period.roster)+len(period.unmatched))
+	for _, employee := range period.roster {
+		employeeID := employee.ID
+		employees = append(employees, &EmployeeAttendanceDay{
+			EmployeeID:   &employeeID,
+			DivisionID:   employee.DivisionID,
+			DivisionName: divisionLabel(employee.DivisionName),
+			Day:          period.dayFor(employee, date),
+		})
+	}
+	for _, day := range period.unmatched {
+		employees = append(employees, &EmployeeAttendanceDay{
+			DivisionName: unassignedDivisionName,
+			Day:          day,
+		})
+	}
+
+	return &DailyAttendanceReport{
+		CompanyID:              companyID,
+		Date:                   date,
+		ShiftStart:             period.shiftStart,
+		ShiftEnd:               period.shiftEnd,
+		Timezone:               period.timezone,
+		Divisions:              SummarizeAttendanceByDivision(employees),
+		Employees:              employees,
+		HarvestersWithoutEntry: period.harvesters,
+	}, nil
+}
+
+// MonthlyReport totals attendance per employee and division for a calendar
+// month. Days after today are not counted as working days.
+func (s *AttendanceService) MonthlyReport(ctx context.Context, companyID string, year, month int, divisionID *string) (*MonthlyAttendanceReport, error) {
+	if month < 1 || month > 12 || year < 2000 {
+		return nil, fmt.Errorf("periode bulan tidak valid")
+	}
+
+	first := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1)
+	from := first.Format(attendanceDateLayout)
+	to := last.Format(attendanceDateLayout)
+
+	period, err := s.loadPeriod(ctx, companyID, from, to, divisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Now().In(period.shift.location()).Format(attendanceDateLayout)
+	countTo := to
+	if today < countTo {
+		countTo = today
+	}
+	workingDays := 0
+	if countTo >= from {
+		workingDays = AttendanceWorkingDays(from, countTo)
+	}
+
+	dates := make([]string, 0, 31)
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		dates = append(dates, day.Format(attendanceDateLayout))
+	}
+
+	rows := make([]*EmployeeAttendanceDay, 0, len(period.roster)*len(dates))
+	summaries := make([]*EmployeeAttendanceSummary, 0, len(period.roster)+len(period.unmatched))
+	for _, employee := range period.roster {
+		employeeID := employee.ID
+		summary := &EmployeeAttendanceSummary{
+			EmployeeID:   &employeeID,
+			NIK:          employee.NIK,
+			Name:         employee.Name,
+			DivisionID:   employee.DivisionID,
+			DivisionName: divisionLabel(employee.DivisionName),
+			WorkingDays:  workingDays,
+		}
+		for _, date := range dates {
+			if date > countTo {
+				break
+			}
+			day := period.dayFor(employee, date)
+			if day.Status == AttendanceStatusAbsent {
+				if AttendanceWorkingDays(date, date) == 0 {
+					continue
+				}
+				summary.AbsentDays++
+			} else {
+				addAttendanceDay(summary, day)
+			}
+			rows = append(rows, &EmployeeAttendanceDay{
+				EmployeeID:   &employeeID,
+				DivisionID:   employee.DivisionID,
+				DivisionName: summary.DivisionName,
+				Day:          day,
+			})
+		}
+		summaries = append(summaries, summary)
+	}
+
+	unmatched := make(map[string]*EmployeeAttendanceSummary)
+	for _, day := range period.unmatched {
+		summary, ok := unmatched[day.NIK]
+		if !ok {
+			summary = &EmployeeAttendanceSummary{
+				NIK:          day.NIK,
+				Name:         day.Name,
+				DivisionName: unassignedDivisionName,
+				WorkingDays:  workingDays,
+			}
+			unmatched[day.NIK] = summary
+			summaries = append(summaries, summary)
+		}
+		addAttendanceDay(summary, day)
+		rows = append(rows, &EmployeeAttendanceDay{DivisionName: unassignedDivisionName, Day: day})
+	}
+
+	divisions := SummarizeAttendanceByDivision(rows)
+	employeeCounts := make(map[string]int)
+	for _, summary := range summaries {
+		employeeCounts[divisionKey(summary.DivisionID)]++
+	}
+	for _, division := range divisions {
+		division.EmployeeCount = employeeCounts[divisionKey(division.DivisionID)]
+	}
+
+	return &MonthlyAttendanceReport{
+		CompanyID:              companyID,
+		Year:                   year,
+		Month:                  month,
+		DateFrom:               from,
+		DateTo:                 to,
+		WorkingDays:            workingDays,
+		ShiftStart:             period.shiftStart,
+		ShiftEnd:               period.shiftEnd,
+		Timezone:               period.timezone,
+		Divisions:              divisions,
+		Employees:              summaries,
+		HarvestersWithoutEntry: period.harvesters,
+	}, nil
+}
+
+// EmployeeAttendance returns the attendance days of one NIK between two work
+// dates (YYYY-MM-DD, inclusive). Days without scans are omitted.
+func (s *AttendanceService) EmployeeAttendance(ctx context.Context, companyID, nik, from, to string) ([]*EmployeeAttendanceDay, error) {
+	nik = strings.TrimSpace(nik)
+	if nik == "" {
+		return nil, fmt.Errorf("NIK wajib diisi")
+	}
+	start, err := time.Parse(attendanceDateLayout, from)
+	if err != nil {
+		return nil, fmt.Errorf("tanggal awal tidak valid, gunakan format YYYY-MM-DD")
+	}
+	end, err := time.Parse(attendanceDateLayout, to)
+	if err != nil {
+		return nil, fmt.Errorf("tanggal akhir tidak valid, gunakan format YYYY-MM-DD")
+	}
+	if end.Before(start) || end.Sub(start) > maxAttendanceRangeDays*24*time.Hour {
+		return nil, fmt.Errorf("rentang tanggal maksimal %d hari", maxAttendanceRangeDays)
+	}
+
+	var employees []*AttendanceEmployee
+	if err := s.rosterQuery(ctx, companyID).Where("e.nik = ?", nik).Limit(1).Scan(&employees).Error; err != nil {
+		return nil, fmt.Errorf("failed to load employee: %w", err)
+	}
+
+	shift, _, _, _ := s.companyShift(ctx, companyID)
+	scans, err := s.loadScans(ctx, companyID, shift, from, to, []string{nik})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*EmployeeAttendanceDay, 0)
+	for _, day := range PairAttendance(scans, shift) {
+		if day.WorkDate < from || day.WorkDate > to {
+			continue
+		}
+		row := &EmployeeAttendanceDay{DivisionName: unassignedDivisionName, Day: day}
+		if len(employees) > 0 {
+			employee := employees[0]
+			row.EmployeeID = &employee.ID
+			row.DivisionID = employee.DivisionID
+			row.DivisionName = divisionLabel(employee.DivisionName)
+			day.Name = employee.Name
+		}
+		result = append(result, row)
+	}
+	return result, nil
+}
+
+// SummarizeAttendanceByDivision aggregates attendance rows per division,
+// ordered by division name.
+func SummarizeAttendanceByDivision(rows []*EmployeeAttendanceDay) []*DivisionAttendanceSummary {
+	byDivision := make(map[string]*DivisionAttendanceSummary)
+	employees := make(map[string]map[string]bool)
+	for _, row := range rows {
+		key := divisionKey(row.DivisionID)
+		summary, ok := byDivision[key]
+		if !ok {
+			summary = &DivisionAttendanceSummary{DivisionID: row.DivisionID, DivisionName: divisionLabel(row.DivisionName)}
+			byDivision[key] = summary
+			employees[key] = make(map[string]bool)
+		}
+		if !employees[key][row.Day.NIK] {
+			employees[key][row.Day.NIK] = true
+			summary.EmployeeCount++
+		}
+
+		switch row.Day.Status {
+		case AttendanceStatusPresent:
+			summary.PresentCount++
+		case AttendanceStatusIncomplete:
+			summary.IncompleteCount++
+		default:
+			summary.AbsentCount++
+		}
+		if row.Day.LateMinutes > 0 {
+			summary.LateCount++
+		}
+		summary.WorkedMinutes += row.Day.WorkedMinutes
+		summary.OvertimeMinutes += row.Day.OvertimeMinutes
+	}
+
+	result := make([]*DivisionAttendanceSummary, 0, len(byDivision))
+	for _, summary := range byDivision {
+		result = append(result, summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].DivisionName < result[j].DivisionName
+	})
+	return result
+}
+
+// findHarvestersWithoutEntry returns harvesters with harvest records on a
+// work date who have no ENTRY scan that day. days is keyed by NIK|date.
+func findHarvestersWithoutEntry(records []attendanceHarvestRow, days map[string]*AttendanceDay, loc *time.Location) []*HarvestGateDiscrepancy {
+	byKey := make(map[string]*HarvestGateDiscrepancy)
+	result := make([]*HarvestGateDiscrepancy, 0)
+	for _, record := range records {
+		nik := ""
+		if record.EmployeeNIK != nil {
+			nik = strings.TrimSpace(*record.EmployeeNIK)
+		}
+		if nik == "" && record.NIK != nil {
+			nik = strings.TrimSpace(*record.NIK)
+		}
+		if nik == "" {
+			continue
+		}
+
+		workDate := record.Tanggal.In(loc).Format(attendanceDateLayout)
+		key := nik + "|" + workDate
+		if day, ok := days[key]; ok && day.FirstEntryAt != nil {
+			continue
+		}
+
+		discrepancy, ok := byKey[key]
+		if !ok {
+			divisionID := record.RosterDivisionID
+			if divisionID == nil {
+				divisionID = record.EmployeeDivisionID
+			}
+			if divisionID == nil {
+				divisionID = record.DivisionID
+			}
+			discrepancy = &HarvestGateDiscrepancy{
+				WorkDate:     workDate,
+				NIK:          nik,
+				EmployeeID:   record.KaryawanID,
+				EmployeeName: strings.TrimSpace(record.Karyawan),
+				DivisionID:   divisionID,
+			}
+			byKey[key] = discrepancy
+			result = append(result, discrepancy)
+		}
+		discrepancy.HarvestRecordIDs = append(discrepancy.HarvestRecordIDs, record.ID)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].WorkDate != result[j].WorkDate {
+			return result[i].WorkDate < result[j].WorkDate
+		}
+		return result[i].NIK < result[j].NIK
+	})
+	return result
+}
+
+func (s *AttendanceService) loadPeriod(ctx context.Context, companyID, from, to string, divisionID *string) (*attendancePeriod, error) {
+	shift, shiftStart, shiftEnd, timezone := s.companyShift(ctx, companyID)
+	period := &attendancePeriod{
+		shift:      shift,
+		shiftStart: shiftStart,
+		shiftEnd:   shiftEnd,
+		timezone:   timezone,
+		rosterNIK:  make(map[string]*AttendanceEmployee),
+		days:       make(map[string]*AttendanceDay),
+	}
+
+	rosterQuery := s.rosterQuery(ctx, companyID)
+	if divisionID != nil && strings.TrimSpace(*divisionID) != "" {
+		rosterQuery = rosterQuery.Where("e.division_id = ?", strings.TrimSpace(*divisionID))
+	}
+	if err := rosterQuery.Order("d.name, e.name").Scan(&period.roster).Error; err != nil {
+		return nil, fmt.Errorf("failed to load employees: %w", err)
+	}
+	niks := make([]string, 0, len(period.roster))
+	for _, employee := range period.roster {
+		period.rosterNIK[employee.NIK] = employee
+		niks = append(niks, employee.NIK)
+	}
+
+	// A division filter restricts scans to that division's roster; otherwise
+	// scans of NIKs missing from the roster are reported as unassigned.
+	filtered := divisionID != nil && strings.TrimSpace(*divisionID) != ""
+	if filtered && len(niks) == 0 {
+		return period, nil
+	}
+	var scanNIKs []string
+	if filtered {
+		scanNIKs = niks
+	}
+	scans, err := s.loadScans(ctx, companyID, shift, from, to, scanNIKs)
+	if err != nil {
+		return nil, err
+	}
+	for _, day := range PairAttendance(scans, shift) {
+		if day.WorkDate < from || day.WorkDate > to {
+			continue
+		}
+		period.days[day.NIK+"|"+day.WorkDate] = day
+		if _, ok := period.rosterNIK[day.NIK]; !ok {
+			period.unmatched = append(period.unmatched, day)
+		}
+	}
+
+	harvests, err := s.loadHarvests(ctx, companyID, shift, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, discrepancy := range findHarvestersWithoutEntry(harvests, period.days, shift.location()) {
+		if filtered && (discrepancy.DivisionID == nil || *discrepancy.DivisionID != strings.TrimSpace(*divisionID)) {
+			continue
+		}
+		period.harvesters = append(period.harvesters, discrepancy)
+	}
+
+	return period, nil
+}
+
+// rosterQuery selects the active employees of a company with their division.
+func (s *AttendanceService) rosterQuery(ctx context.Context, companyID string) *gorm.DB {
+	return s.db.WithContext(ctx).
+		Table("employees e").
+		Select("e.id, e.nik, e.name, e.role, e.division_id, COALESCE(d.name, '') AS division_name").
+		Joins("LEFT JOIN divisions d ON d.id = e.division_id").
+		Where("e.company_id = ? AND e.is_active = ?", companyID, true)
+}
+
+// dayFor returns the employee's attendance on a work date, or an ABSENT day.
+func (p *attendancePeriod) dayFor(employee *AttendanceEmployee, date string) *AttendanceDay {
+	if day, ok := p.days[employee.NIK+"|"+date]; ok {
+		if day.Name == "" {
+			day.Name = employee.Name
+		}
+		return day
+	}
+	return &AttendanceDay{
+		NIK:              employee.NIK,
+		Name:             employee.Name,
+		WorkDate:         date,
+		Sessions:         []AttendanceSession{},
+		ScheduledMinutes: p.shift.Minutes(),
+		Status:           AttendanceStatusAbsent,
+	}
+}
+
+func (s *AttendanceService) companyShift(ctx context.Context, companyID string) (AttendanceShift, string, string, string) {
+	settings, err := s.settings.GetSettings(ctx, companyID)
+	if err != nil || settings == nil {
+		settings = companyModels.DefaultCompanySettings(companyID)
+	}
+	shift := NewAttendanceShift(settings.DefaultShiftStart, settings.DefaultShiftEnd, settings.Timezone)
+	return shift, settings.DefaultShiftStart, settings.DefaultShiftEnd, shift.location().String()
+}
+
+// loadScans reads ENTRY/EXIT scans around a work-date range. scanned_at is
+// stored without a zone and read as UTC; a day of margin on each side covers
+// overnight shifts and late-synced scans.
+func (s *AttendanceService) loadScans(ctx context.Context, companyID string, shift AttendanceShift, from, to string, niks []string) ([]AttendanceScan, error) {
+	start, _ := shift.Bounds(from)
+	_, end := shift.Bounds(to)
+	start = start.Add(-24 * time.Hour).UTC()
+	end = end.Add(24 * time.Hour).UTC()
+
+	var rows []attendanceScanRow
+	query := s.db.WithContext(ctx).
+		Table("gate_employee_logs").
+		Select("nik, nama, action, gate_position, scanned_at").
+		Where("company_id = ? AND deleted_at IS NULL AND scanned_at >= ? AND scanned_at < ?", companyID, start, end)
+	if len(niks) > 0 {
+		query = query.Where("nik IN ?", niks)
+	}
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load employee gate logs: %w", err)
+	}
+
+	// Scans synced through the legacy sync pipeline live in employee_logs.
+	if s.db.Migrator().HasTable("employee_logs") {
+		var legacy []attendanceScanRow
+		legacyQuery := s.db.WithContext(ctx).
+			Table("employee_logs").
+			Select("nik, nama, action, gate_position, scanned_at").
+			Where("company_id = ? AND scanned_at >= ? AND scanned_at < ?", companyID, start, end)
+		if len(niks) > 0 {
+			legacyQuery = legacyQuery.Where("nik IN ?", niks)
+		}
+		if err := legacyQuery.Scan(&legacy).Error; err != nil {
+			return nil, fmt.Errorf("failed to load employee logs: %w", err)
+		}
+		rows = append(rows, legacy...)
+	}
+
+	scans := make([]AttendanceScan, 0, len(rows))
+	for _, row := range rows {
+		if row.ScannedAt == nil {
+			continue
+		}
+		scannedAt := time.Date(
+			row.ScannedAt.Year(), row.ScannedAt.Month(), row.ScannedAt.Day(),
+			row.ScannedAt.Hour(), row.ScannedAt.Minute(), row.ScannedAt.Second(), 0, time.UTC,
+		)
+		scans = append(scans, AttendanceScan{
+			NIK:       row.NIK,
+			Name:      row.Nama,
+			Action:    row.Action,
+			Gate:      row.GatePosition,
+			ScannedAt: scannedAt,
+		})
+	}
+	return scans, nil
+}
+
+func (s *AttendanceService) loadHarvests(ctx context.Context, companyID string, shift AttendanceShift, from, to string) ([]attendanceHarvestRow, error) {
+	start, err := time.ParseInLocation(attendanceDateLayout, from, shift.location())
+	if err != nil {
+		return nil, err
+	}
+	end, err := time.ParseInLocation(attendanceDateLayout, to, shift.location())
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []attendanceHarvestRow
+	err = s.db.WithContext(ctx).
+		Table("harvest_records hr").
+		Select("hr.id, hr.tanggal, hr.nik, e.nik AS employee_nik, hr.karyawan_id, hr.karyawan, hr.division_id, hr.employee_division_id, e.division_id AS roster_division_id").
+		Joins("LEFT JOIN employees e ON e.id = hr.karyawan_id").
+		Where("hr.company_id = ? AND hr.deleted_at IS NULL AND hr.status <> ?", companyID, "REJECTED").
+		Where("hr.tanggal >= ? AND hr.tanggal < ?", start, end.AddDate(0, 0, 1)).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load harvest records: %w", err)
+	}
+	return rows, nil
+}
+
+func addAttendanceDay(summary *EmployeeAttendanceSummary, day *AttendanceDay) {
+	if day.Status == AttendanceStatusIncomplete {
+		summary.IncompleteDays++
+	} else {
+		summary.PresentDays++
+	}
+	if day.LateMinutes > 0 {
+		summary.LateDays++
+	}
+	summary.WorkedMinutes += day.WorkedMinutes
+	summary.OvertimeMinutes += day.OvertimeMinutes
+}
+
+func divisionKey(divisionID *string) string {
+	if divisionID == nil {
+		return ""
+	}
+	return *divisionID
+}
+
+func divisionLabel(name string) string {
+	if strings.TrimSpace(name) == "" {
+		return unassignedDivisionName
+	}
+	return name
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func wib(day, hour, minute int) time.Time {
+	return time.Date(2026, 3, day, hour, minute, 0, 0, getWIBLocation()).UTC()
+}
+
+func TestPairAttendanceDayShift(t *testing.T) {
+	shift := NewAttendanceShift("06:00", "14:00", "Asia/Jakarta")
+	scans := []AttendanceScan{
+		{NIK: "1001", Name: "Budi", Action: "ENTRY", Gate: "POS-1", ScannedAt: wib(2, 6, 10)},
+		{NIK: "1001", Action: "ENTRY", Gate: "POS-1", ScannedAt: wib(2, 6, 12)}, // duplicate tap
+		{NIK: "1001", Action: "EXIT", Gate: "POS-2", ScannedAt: wib(2, 15, 10)},
+		{NIK: "1002", Name: "Sari", Action: "ENTRY", ScannedAt: wib(2, 5, 55)},
+		{NIK: "1002", Action: "EXIT", ScannedAt: wib(2, 13, 0)},
+	}
+
+	days := PairAttendance(scans, shift)
+	require.Len(t, days, 2)
+
+	budi := days[0]
+	assert.Equal(t, "1001", budi.NIK)
+	assert.Equal(t, "Budi", budi.Name)
+	assert.Equal(t, "2026-03-02", budi.WorkDate)
+	require.Len(t, budi.Sessions, 1)
+	assert.Equal(t, "POS-1", budi.Sessions[0].EntryGate)
+	assert.Equal(t, "POS-2", budi.Sessions[0].ExitGate)
+	assert.Equal(t, 540, budi.WorkedMinutes)
+	assert.Equal(t, 480, budi.ScheduledMinutes)
+	assert.Equal(t, 10, budi.LateMinutes)
+	assert.Equal(t, 60, budi.OvertimeMinutes)
+	assert.Equal(t, AttendanceStatusPresent, budi.Status)
+
+	sari := days[1]
+	assert.Equal(t, 0, sari.LateMinutes)
+	assert.Equal(t, 60, sari.EarlyLeaveMinutes)
+	assert.Equal(t, 0, sari.OvertimeMinutes)
+}
+
+func TestPairAttendanceOvernightShift(t *testing.T) {
+	shift := NewAttendanceShift("22:00", "06:00", "Asia/Jakarta")
+	assert.True(t, shift.Overnight())
+	assert.Equal(t, 480, shift.Minutes())
+
+	scans := []AttendanceScan{
+		{NIK: "2001", Action: "ENTRY", ScannedAt: wib(2, 21, 50)},
+		{NIK: "2001", Action: "EXIT", ScannedAt: wib(3, 6, 5)},
+		// Exit only the next night: attributed to the shift of the 3rd
+		{NIK: "2001", Action: "EXIT", ScannedAt: wib(4, 5, 30)},
+	}
+
+	days := PairAttendance(scans, shift)
+	require.Len(t, days, 2)
+
+	assert.Equal(t, "2026-03-02", days[0].WorkDate)
+	assert.Equal(t, 495, days[0].WorkedMinutes)
+	assert.Equal(t, 0, days[0].LateMinutes)
+	assert.Equal(t, AttendanceStatusPresent, days[0].Status)
+
+	assert.Equal(t, "2026-03-03", days[1].WorkDate)
+	assert.True(t, days[1].MissingEntry)
+	assert.Equal(t, AttendanceStatusIncomplete, days[1].Status)
+	assert.Equal(t, 450, days[1].WorkedMinutes)
+	assert.Equal(t, 450, days[1].EstimatedMinutes)
+}
+
+func TestPairAttendanceMissingScans(t *testing.T) {
+	shift := NewAttendanceShift("06:00", "14:00", "Asia/Jakarta")
+	scans := []AttendanceScan{
+		{NIK: "3001", Action: "ENTRY", ScannedAt: wib(2, 6, 0)},
+		{NIK: "3001", Action: "ENTRY", ScannedAt: wib(2, 12, 0)},
+		{NIK: "3001", Action: "EXIT", ScannedAt: wib(2, 13, 0)},
+		{NIK: "3001", Action: "ENTRY", ScannedAt: wib(3, 6, 30)},
+		{NIK: "", Action: "ENTRY", ScannedAt: wib(3, 6, 30)},
+		{NIK: "3001", Action: "UNKNOWN", ScannedAt: wib(3, 7, 0)},
+	}
+
+	days := PairAttendance(scans, shift)
+	require.Len(t, days, 2)
+
+	first := days[0]
+	require.Len(t, first.Sessions, 2)
+	assert.True(t, first.Sessions[0].Estimated)
+	assert.Nil(t, first.Sessions[0].ExitAt)
+	assert.True(t, first.MissingExit)
+	assert.False(t, first.MissingEntry)
+	// 06:00–14:00 estimated plus 12:00–13:00 scanned
+	assert.Equal(t, 540, first.WorkedMinutes)
+	assert.Equal(t, 480, first.EstimatedMinutes)
+
+	second := days[1]
+	assert.Equal(t, "2026-03-03", second.WorkDate)
+	assert.True(t, second.MissingExit)
+	assert.Equal(t, 30, second.LateMinutes)
+	assert.Equal(t, AttendanceStatusIncomplete, second.Status)
+}
+
+func TestNewAttendanceShiftDefaults(t *testing.T) {
+	shift := NewAttendanceShift("bad", "", "Mars/Olympus")
+	assert.Equal(t, 6*60, shift.StartMinute)
+	assert.Equal(t, 14*60, shift.EndMinute)
+	assert.Equal(t, "Asia/Jakarta", shift.location().String())
+}
+
+func TestAttendanceWorkingDays(t *testing.T) {
+	// March 2026 has 5 Sundays
+	assert.Equal(t, 26, AttendanceWorkingDays("2026-03-01", "2026-03-31"))
+	assert.Equal(t, 0, AttendanceWorkingDays("2026-03-01", "2026-03-01"))
+	assert.Equal(t, 1, AttendanceWorkingDays("2026-03-02", "2026-03-02"))
+	assert.Equal(t, 0, AttendanceWorkingDays("2026-03-05", "2026-03-02"))
+}
+
+func TestFindHarvestersWithoutEntry(t *testing.T) {
+	loc := getWIBLocation()
+	nik := "1001"
+	rosterNIK := "1002"
+	karyawanID := "emp-1002"
+	division := "div-a"
+	tanggal := time.Date(2026, 3, 2, 0, 0, 0, 0, loc)
+
+	days := map[string]*AttendanceDay{
+		"1001|2026-03-02": {NIK: "1001", WorkDate: "2026-03-02", FirstEntryAt: &tanggal},
+		"1002|2026-03-02": {NIK: "1002", WorkDate: "2026-03-02", MissingEntry: true},
+	}
+	records := []attendanceHarvestRow{
+		{ID: "hr-1", Tanggal: tanggal, NIK: &nik, Karyawan: "Budi"},
+		{ID: "hr-2", Tanggal: tanggal, EmployeeNIK: &rosterNIK, KaryawanID: &karyawanID, Karyawan: "Sari", RosterDivisionID: &division},
+		{ID: "hr-3", Tanggal: tanggal, EmployeeNIK: &rosterNIK, KaryawanID: &karyawanID, Karyawan: "Sari"},
+		{ID: "hr-4", Tanggal: tanggal, Karyawan: "Kelompok A"},
+	}
+
+	result := findHarvestersWithoutEntry(records, days, loc)
+	require.Len(t, result, 1)
+	assert.Equal(t, "1002", result[0].NIK)
+	assert.Equal(t, "2026-03-02", result[0].WorkDate)
+	assert.Equal(t, []string{"hr-2", "hr-3"}, result[0].HarvestRecordIDs)
+	require.NotNil(t, result[0].DivisionID)
+	assert.Equal(t, "div-a", *result[0].DivisionID)
+}
+
+func TestSummarizeAttendanceByDivision(t *testing.T) {
+	divA := "div-a"
+	rows := []*EmployeeAttendanceDay{
+		{DivisionID: &divA, DivisionName: "Divisi A", Day: &AttendanceDay{NIK: "1", Status: AttendanceStatusPresent, WorkedMinutes: 500, OvertimeMinutes: 20, LateMinutes: 5}},
+		{DivisionID: &divA, DivisionName: "Divisi A", Day: &AttendanceDay{NIK: "2", Status: AttendanceStatusAbsent}},
+		{DivisionName: "", Day: &AttendanceDay{NIK: "3", Status: AttendanceStatusIncomplete, WorkedMinutes: 480}},
+	}
+
+	summaries := SummarizeAttendanceByDivision(rows)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, "Divisi A", summaries[0].DivisionName)
+	assert.Equal(t, 2, summaries[0].EmployeeCount)
+	assert.Equal(t, 1, summaries[0].PresentCount)
+	assert.Equal(t, 1, summaries[0].AbsentCount)
+	assert.Equal(t, 1, summaries[0].LateCount)
+	assert.Equal(t, 500, summaries[0].WorkedMinutes)
+	assert.Equal(t, unassignedDivisionName, summaries[1].DivisionName)
+	assert.Equal(t, 1, summaries[1].IncompleteCount)
+}
@@ -9,16 +9,18 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"path"
-	"path/filepath"
 	"strings"
 	"time"
 
+	companyServices "agrinovagraphql/server/internal/company/services"
 	"agrinovagraphql/server/internal/gatecheck/models"
 	"agrinovagraphql/server/internal/graphql/domain/common"
 	satpam "agrinovagraphql/server/internal/graphql/domain/satpam"
 	"agrinovagraphql/server/internal/graphql/generated"
 	"agrinovagraphql/server/internal/middleware"
+	"agrinovagraphql/server/internal/photoverify"
+	"agrinovagraphql/server/pkg/storage"
+	"agrinovagraphql/server/pkg/tracing"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -28,40 +30,31 @@ import (
 
 // GateCheckService handles all gate check operations
 type GateCheckService struct {
-	db         *gorm.DB
-	jwtSecret  string
-	uploadsDir string
+	db          *gorm.DB
+	jwtSecret   string
+	uploads     storage.Store
+	watchlist   *GateWatchlistService
+	overstay    *GateOverstayService
+	verifier    *photoverify.Service
+	tenantPlans *companyServices.TenantPlanService
 }
 
 // NewGateCheckService creates a new gate check service
-func NewGateCheckService(db *gorm.DB, jwtSecret string, uploadsDir string) *GateCheckService {
+func NewGateCheckService(db *gorm.DB, jwtSecret string, uploads storage.Store) *GateCheckService {
 	return &GateCheckService{
-		db:         db,
-		jwtSecret:  jwtSecret,
-		uploadsDir: normalizeUploadsDir(uploadsDir),
+		db:          db,
+		jwtSecret:   jwtSecret,
+		uploads:     uploads,
+		watchlist:   NewGateWatchlistService(db),
+		overstay:    NewGateOverstayService(db),
+		verifier:    photoverify.NewService(db, uploads),
+		tenantPlans: companyServices.NewTenantPlanService(db),
 	}
 }
 
-func normalizeUploadsDir(uploadDir string) string {
-	trimmed := strings.TrimSpace(uploadDir)
-	if trimmed == "" {
-		return "./uploads"
-	}
-
-	cleaned := filepath.Clean(trimmed)
-	if cleaned == "." {
-		return "./uploads"
-	}
-
-	return cleaned
-}
-
-func (s *GateCheckService) satpamPhotoUploadDir() string {
-	return filepath.Join(s.uploadsDir, "satpam_photos")
-}
-
-func satpamPhotoURLPath(filename string) string {
-	return "/" + path.Join("uploads", "satpam_photos", filename)
+// satpamPhotoKey stores photos per company so downloads can be scoped.
+func satpamPhotoKey(companyID string, filename string) string {
+	return storage.JoinKey("satpam_photos", sanitizePhotoID(companyID), filename)
 }
 
 // getWIBLocation returns the Asia/Jakarta timezone (WIB, UTC+7) for same-day date comparison
@@ -107,9 +100,13 @@ type GuestLog struct {
 	Longitude           *float64
 	RegistrationSource  *satpam.RegistrationSource `gorm:"type:varchar(20)"`
 	SyncStatus          common.SyncStatus          `gorm:"type:varchar(20);default:'PENDING'"`
-	CreatedAt           time.Time                  `gorm:"autoCreateTime"`
-	UpdatedAt           time.Time                  `gorm:"autoUpdateTime"`
-	DeletedAt           gorm.DeletedAt             `gorm:"index"`
+	// Set by the overstay detector; resolved when the vehicle exits.
+	OverstayDetectedAt   *time.Time
+	OverstayLimitMinutes *int
+	OverstayResolvedAt   *time.Time
+	CreatedAt            time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt            time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt            gorm.DeletedAt `gorm:"index"`
 }
 
 // TableName returns the table name for GuestLog
@@ -290,6 +287,38 @@ func (s *GateCheckService) RegisterGuest(ctx context.Context, input satpam.Creat
 		}, nil
 	}
 
+	// Screen the guest against the company watchlist before admitting it
+	screening, err := s.watchlist.Screen(ctx, WatchlistScreenInput{
+		CompanyID: user.CompanyID,
+		Candidate: WatchlistCandidate{
+			VehiclePlate: input.VehiclePlate,
+			IDCardNumber: input.IDCardNumber,
+			DriverName:   input.DriverName,
+		},
+		Source:        satpam.GateWatchlistHitSourceRegistration,
+		DeviceID:      input.DeviceID,
+		ScannedBy:     user.ID,
+		OverrideHitID: input.WatchlistOverrideID,
+	})
+	if err != nil {
+		return &satpam.GuestRegistrationResult{
+			Success: false,
+			Message: "Gagal memeriksa watchlist",
+		}, nil
+	}
+	var watchlistAlert *satpam.GateWatchlistAlert
+	if screening != nil {
+		watchlistAlert = ToGateWatchlistAlert(screening.Hit)
+		watchlistAlert.IsNew = screening.Created
+		if !screening.Allowed {
+			return &satpam.GuestRegistrationResult{
+				Success:        false,
+				Message:        screening.Message,
+				WatchlistAlert: watchlistAlert,
+			}, nil
+		}
+	}
+
 	// Create entry time
 	now := time.Now()
 
@@ -332,14 +361,23 @@ func (s *GateCheckService) RegisterGuest(ctx context.Context, input satpam.Creat
 		}, nil
 	}
 
+	// Link the watchlist hit to the admitted guest so its QR validation passes
+	if screening != nil {
+		if err := s.watchlist.AttachGuestLog(ctx, screening.Hit, guestLog.ID); err == nil {
+			watchlistAlert = ToGateWatchlistAlert(screening.Hit)
+			watchlistAlert.IsNew = screening.Created
+		}
+	}
+
 	// Generate QR token
 	qrToken, err := s.generateQRToken(ctx, guestLog.ID, satpam.GateIntentEntry, input.DeviceID, 60, user.ID, user.CompanyID)
 	if err != nil {
 		// Guest log created but QR failed - still return success but with warning
 		return &satpam.GuestRegistrationResult{
-			Success:  true,
-			Message:  "Tamu terdaftar, tetapi gagal membuat QR code",
-			GuestLog: s.convertToSatpamGuestLog(guestLog),
+			Success:        true,
+			Message:        "Tamu terdaftar, tetapi gagal membuat QR code",
+			GuestLog:       s.convertToSatpamGuestLog(guestLog),
+			WatchlistAlert: watchlistAlert,
 		}, nil
 	}
 
@@ -347,11 +385,17 @@ func (s *GateCheckService) RegisterGuest(ctx context.Context, input satpam.Creat
 	guestLog.QRCodeData = &qrToken.Token
 	s.db.Save(guestLog)
 
+	message := "Tamu berhasil didaftarkan"
+	if screening != nil {
+		message = screening.Message
+	}
+
 	return &satpam.GuestRegistrationResult{
-		Success:  true,
-		Message:  "Tamu berhasil didaftarkan",
-		GuestLog: s.convertToSatpamGuestLog(guestLog),
-		QRToken:  qrToken,
+		Success:        true,
+		Message:        message,
+		GuestLog:       s.convertToSatpamGuestLog(guestLog),
+		QRToken:        qrToken,
+		WatchlistAlert: watchlistAlert,
 	}, nil
 }
 
@@ -541,12 +585,26 @@ func (s *GateCheckService) ProcessGuestExit(ctx context.Context, input satpam.Pr
 							}, nil
 						}
 
-						return &satpam.ProcessExitResult{
+						result := &satpam.ProcessExitResult{
 							Success:     true,
 							Message:     "Keluar berhasil diproses (Stateless)",
 							GuestLog:    s.convertToSatpamGuestLog(newGuestLog),
 							WasOverstay: false, // Calc specific logic if needed
-						}, nil
+						}
+
+						// The entry record stays open on this path, so close its overstay here.
+						if guestLogID != "" {
+							resolvedEntry, err := s.overstay.ResolveByID(ctx, user.CompanyID, guestLogID, now)
+							if err != nil {
+								log.Printf("Failed to resolve overstay for entry %s: %v", guestLogID, err)
+							} else if resolvedEntry != nil {
+								result.WasOverstay = true
+								result.OverstayResolvedAt = resolvedEntry.OverstayResolvedAt
+								result.ResolvedOverstayLog = s.convertToSatpamGuestLog(resolvedEntry)
+							}
+						}
+
+						return result, nil
 					}
 				}
 			}
@@ -601,14 +659,12 @@ func (s *GateCheckService) ProcessGuestExit(ctx context.Context, input satpam.Pr
 		guestLog.SecondCargo = input.SecondCargo
 	}
 
-	// Calculate duration
-	var wasOverstay bool
-	if guestLog.EntryTime != nil {
-		durationMinutes := int32(now.Sub(*guestLog.EntryTime).Minutes())
-
-		// Check overstay (8 hours = 480 minutes)
-		wasOverstay = durationMinutes > 480
+	// Check overstay against the detector mark or the company stay limit
+	wasOverstay := guestLog.OverstayDetectedAt != nil
+	if !wasOverstay && guestLog.EntryTime != nil {
+		wasOverstay = IsOverstayed(*guestLog.EntryTime, s.overstay.MaxStayMinutes(ctx, &guestLog), now)
 	}
+	overstayResolved := s.overstay.ResolveOnExit(&guestLog, now)
 
 	if input.Notes != nil {
 		guestLog.Notes = input.Notes
@@ -624,12 +680,17 @@ func (s *GateCheckService) ProcessGuestExit(ctx context.Context, input satpam.Pr
 		}, nil
 	}
 
-	return &satpam.ProcessExitResult{
+	result := &satpam.ProcessExitResult{
 		Success:     true,
 		Message:     "Keluar berhasil diproses",
 		GuestLog:    s.convertToSatpamGuestLog(&guestLog),
 		WasOverstay: wasOverstay,
-	}, nil
+	}
+	if overstayResolved {
+		result.OverstayResolvedAt = guestLog.OverstayResolvedAt
+		result.ResolvedOverstayLog = result.GuestLog
+	}
+	return result, nil
 }
 
 // ValidateQR validates a QR token
@@ -666,11 +727,64 @@ func (s *GateCheckService) ValidateQR(ctx context.Context, qrData string, expect
 
 	// Get associated guest log if exists
 	var guestLog *satpam.SatpamGuestLog
+	var screening *WatchlistScreening
 	if qrToken.GuestLogID != nil {
 		var gl GuestLog
 		if err := s.db.Where("id = ?", *qrToken.GuestLogID).First(&gl).Error; err == nil {
 			guestLog = s.convertToSatpamGuestLog(&gl)
+
+			// Only entries are screened; a watchlisted guest must still be let out
+			if qrToken.AllowedScan == satpam.GateIntentEntry {
+				scannedBy := qrToken.GeneratedBy
+				if user, err := getUserContext(ctx); err == nil {
+					scannedBy = user.ID
+				}
+				screening, err = s.watchlist.Screen(ctx, WatchlistScreenInput{
+					CompanyID: gl.CompanyID,
+					Candidate: WatchlistCandidate{
+						VehiclePlate: gl.VehiclePlate,
+						IDCardNumber: gl.IDCardNumber,
+						DriverName:   gl.DriverName,
+					},
+					Source:     satpam.GateWatchlistHitSourceQRValidation,
+					GuestLogID: &gl.ID,
+					DeviceID:   deviceID,
+					ScannedBy:  scannedBy,
+				})
+				if err != nil {
+					return &satpam.QRValidationResult{
+						IsValid:           false,
+						Message:           "Gagal memeriksa watchlist",
+						AllowedOperations: []satpam.GateIntent{},
+						TokenInfo:         s.convertToSatpamQRToken(&qrToken),
+						GuestLog:          guestLog,
+					}, nil
+				}
+			}
+		}
+	}
+
+	if screening != nil {
+		alert := ToGateWatchlistAlert(screening.Hit)
+		alert.IsNew = screening.Created
+		if !screening.Allowed {
+			return &satpam.QRValidationResult{
+				IsValid:           false,
+				Message:           screening.Message,
+				AllowedOperations: []satpam.GateIntent{},
+				TokenInfo:         s.convertToSatpamQRToken(&qrToken),
+				GuestLog:          guestLog,
+				WatchlistAlert:    alert,
+			}, nil
 		}
+		return &satpam.QRValidationResult{
+			IsValid:           true,
+			Message:           screening.Message,
+			TokenInfo:         s.convertToSatpamQRToken(&qrToken),
+			GuestLog:          guestLog,
+			AllowedOperations: []satpam.GateIntent{qrToken.AllowedScan},
+			WatchlistAlert:    alert,
+		}, nil
 	}
 
 	return &satpam.QRValidationResult{
@@ -736,13 +850,28 @@ func (s *GateCheckService) GetVehiclesInside(ctx context.Context, search *string
 	}
 
 	result := make([]*satpam.VehicleInsideInfo, len(filteredLogs))
+	overstayRules := make(map[string][]*models.GateOverstayRule)
 	for i, gl := range filteredLogs {
 		duration := int32(0)
-		isOverstay := false
+		maxStay := DefaultOverstayMinutes
+		if gl.OverstayLimitMinutes != nil {
+			maxStay = *gl.OverstayLimitMinutes
+		} else {
+			rules, ok := overstayRules[gl.CompanyID]
+			if !ok {
+				if rules, err = s.overstay.activeRules(ctx, gl.CompanyID); err != nil {
+					log.Printf("Failed to load overstay rules for company %s: %v", gl.CompanyID, err)
+				}
+				overstayRules[gl.CompanyID] = rules
+			}
+			maxStay = ResolveMaxStayMinutes(rules, gl.VehicleType, gl.Destination)
+		}
+		isOverstay := gl.OverstayDetectedAt != nil
 		if gl.EntryTime != nil {
 			duration = int32(time.Since(*gl.EntryTime).Minutes())
-			isOverstay = duration > 480 // 8 hours
+			isOverstay = isOverstay || IsOverstayed(*gl.EntryTime, maxStay, time.Now())
 		}
+		maxStayMinutes := int32(maxStay)
 		entryGate := gl.EntryGate
 		if entryGate == nil || strings.TrimSpace(*entryGate) == "" {
 			trimmedGatePosition := strings.TrimSpace(gl.GatePosition)
@@ -760,6 +889,8 @@ func (s *GateCheckService) GetVehiclesInside(ctx context.Context, search *string
 			EntryTime:           *gl.EntryTime,
 			Duration:            duration,
 			IsOverstay:          isOverstay,
+			MaxStayMinutes:      &maxStayMinutes,
+			OverstayDetectedAt:  gl.OverstayDetectedAt,
 			Destination:         gl.Destination,
 			QRCode:              gl.QRCodeData,
 			LoadType:            gl.LoadType,
@@ -1153,7 +1284,10 @@ func (s *GateCheckService) ConvertToVehicleCompletedInfo(gl *GuestLog) *satpam.V
 }
 
 // SyncSatpamRecords syncs satpam records from mobile device
-func (s *GateCheckService) SyncSatpamRecords(ctx context.Context, input satpam.SatpamSyncInput) (*satpam.SatpamSyncResult, error) {
+func (s *GateCheckService) SyncSatpamRecords(ctx context.Context, input satpam.SatpamSyncInput) (_ *satpam.SatpamSyncResult, err error) {
+	ctx, span := tracing.Start(ctx, "GateCheckService.SyncSatpamRecords")
+	defer func() { tracing.End(span, err) }()
+
 	// Validate input constraints (batch size limit)
 	if err := input.Validate(); err != nil {
 		return &satpam.SatpamSyncResult{
@@ -1415,6 +1549,46 @@ func (s *GateCheckService) SyncSatpamRecords(ctx context.Context, input satpam.S
 		syncedCount++
 	}
 
+	// Offline records were already admitted, so watchlist matches are only flagged
+	watchlistInputs := make([]WatchlistScreenInput, 0, len(uniqueRows))
+	for _, row := range uniqueRows {
+		if _, failed := writeErrorsByRowID[row.ID]; failed || row.GenerationIntent == string(satpam.GateIntentExit) {
+			continue
+		}
+		rowID := row.ID
+		watchlistInputs = append(watchlistInputs, WatchlistScreenInput{
+			Candidate: WatchlistCandidate{
+				VehiclePlate: row.VehiclePlate,
+				IDCardNumber: row.IDCardNumber,
+				DriverName:   row.DriverName,
+			},
+			GuestLogID: &rowID,
+			DeviceID:   input.DeviceID,
+			ScannedBy:  user.ID,
+		})
+	}
+	var watchlistAlerts []*satpam.GateWatchlistAlert
+	screenings, watchlistErr := s.watchlist.ScreenSynced(ctx, user.CompanyID, watchlistInputs)
+	if watchlistErr != nil {
+		log.Printf("SyncSatpamRecords watchlist screening failed: %v", watchlistErr)
+	}
+	alertedRows := make(map[string]struct{}, len(screenings))
+	for _, index := range validIndexes {
+		rowID := rowIDByResultIndex[index]
+		screening, exists := screenings[rowID]
+		if !exists || !results[index].Success {
+			continue
+		}
+		results[index].Reason = &screening.Message
+		if _, alerted := alertedRows[rowID]; alerted {
+			continue
+		}
+		alertedRows[rowID] = struct{}{}
+		alert := ToGateWatchlistAlert(screening.Hit)
+		alert.IsNew = screening.Created
+		watchlistAlerts = append(watchlistAlerts, alert)
+	}
+
 	if profileEnabled {
 		totalDuration := time.Since(requestStartedAt)
 		log.Printf(
@@ -1438,6 +1612,7 @@ func (s *GateCheckService) SyncSatpamRecords(ctx context.Context, input satpam.S
 		Results:           results,
 		ServerTimestamp:   time.Now(),
 		Message:           fmt.Sprintf("Synced %d records, %d failed", syncedCount, failedCount),
+		WatchlistAlerts:   watchlistAlerts,
 	}, nil
 }
 
@@ -1638,6 +1813,10 @@ func (s *GateCheckService) MarkOverstay(ctx context.Context, guestLogID string,
 	if notes != nil {
 		guestLog.Notes = notes
 	}
+	if guestLog.OverstayDetectedAt == nil && guestLog.ExitTime == nil {
+		now := time.Now()
+		guestLog.OverstayDetectedAt = &now
+	}
 
 	if err := s.db.Save(&guestLog).Error; err != nil {
 		return nil, fmt.Errorf("gagal menyimpan status overstay: %w", err)
@@ -1652,23 +1831,21 @@ func (s *GateCheckService) ConvertToSatpamGuestLog(gl *GuestLog) *satpam.SatpamG
 }
 
 // findExistingPhoto checks if a photo file already exists for a localID (offline UUID)
-// in the uploads directory. This handles cases where photos are synced before the record.
-func (s *GateCheckService) findExistingPhoto(localID string) *string {
-	uploadDir := s.satpamPhotoUploadDir()
-	files, err := os.ReadDir(uploadDir)
-	if err != nil {
-		fmt.Printf("findExistingPhoto: error reading dir %s: %v\n", uploadDir, err)
-		return nil
-	}
-
-	for _, f := range files {
+// in the company's photo storage. This handles cases where photos are synced before the record.
+func (s *GateCheckService) findExistingPhoto(ctx context.Context, companyID string, localID string) *string {
+	var found *string
+	errFound := errors.New("found")
+	err := s.uploads.List(ctx, satpamPhotoKey(companyID, localID+"_"), func(info storage.ObjectInfo) error {
 		// Photos are named like LOCAL_ID_TIMESTAMP.jpg
-		if !f.IsDir() && strings.HasPrefix(f.Name(), localID+"_") {
-			urlPath := satpamPhotoURLPath(f.Name())
-			return &urlPath
-		}
+		urlPath := storage.URLPath(info.Key)
+		found = &urlPath
+		return errFound
+	})
+	if err != nil && !errors.Is(err, errFound) {
+		fmt.Printf("findExistingPhoto: error listing photos for %s: %v\n", localID, err)
+		return nil
 	}
-	return nil
+	return found
 }
 
 // normalizePhotoURL ensures photo paths have a leading slash for frontend proxy compatibility.
@@ -2325,7 +2502,10 @@ func (s *GateCheckService) generateUpdatedQRWrapper(token *models.QRToken) (stri
 var _ = models.SyncCompleted
 
 // SyncEmployeeLog syncs employee access logs from mobile
-func (s *GateCheckService) SyncEmployeeLog(ctx context.Context, input generated.EmployeeLogSyncInput) (*generated.EmployeeLogSyncResult, error) {
+func (s *GateCheckService) SyncEmployeeLog(ctx context.Context, input generated.EmployeeLogSyncInput) (_ *generated.EmployeeLogSyncResult, err error) {
+	ctx, span := tracing.Start(ctx, "GateCheckService.SyncEmployeeLog")
+	defer func() { tracing.End(span, err) }()
+
 	// Parse timestamp
 	scanTime := time.Now()
 	if input.Record.ScannedAt != nil {
@@ -2417,7 +2597,10 @@ func sanitizePhotoID(id string) string {
 }
 
 // SyncSatpamPhotos syncs photos from mobile
-func (s *GateCheckService) SyncSatpamPhotos(ctx context.Context, input generated.SatpamPhotoSyncInput) (*generated.SatpamPhotoSyncResult, error) {
+func (s *GateCheckService) SyncSatpamPhotos(ctx context.Context, input generated.SatpamPhotoSyncInput) (_ *generated.SatpamPhotoSyncResult, err error) {
+	ctx, span := tracing.Start(ctx, "GateCheckService.SyncSatpamPhotos")
+	defer func() { tracing.End(span, err) }()
+
 	log.Printf("SyncSatpamPhotos: Received %d photos", len(input.Photos))
 
 	// Enforce batch size limit
@@ -2431,12 +2614,6 @@ func (s *GateCheckService) SyncSatpamPhotos(ctx context.Context, input generated
 		return nil, fmt.Errorf("authentication required")
 	}
 
-	uploadDir := s.satpamPhotoUploadDir()
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		log.Printf("SyncSatpamPhotos: failed to create upload directory: %v", err)
-		return nil, fmt.Errorf("failed to prepare storage")
-	}
-
 	successful := 0
 	failed := 0
 	totalBytes := 0
@@ -2530,11 +2707,24 @@ func (s *GateCheckService) SyncSatpamPhotos(ctx context.Context, input generated
 
 		// Generate safe file name using sanitized ID
 		filename := fmt.Sprintf("%s_%d%s", safePhotoID, photo.TakenAt.Unix(), detectedExt)
-		savePath := filepath.Join(uploadDir, filename)
+		photoKey := satpamPhotoKey(user.CompanyID, filename)
 
-		// Save to file
-		if err := os.WriteFile(savePath, data, 0644); err != nil {
+		// Reserve the company's storage quota before writing the file
+		if err := s.tenantPlans.ReserveStorage(ctx, user.CompanyID, int64(len(data))); err != nil {
+			code := "QUOTA_EXCEEDED"
+			errorsList = append(errorsList, &common.PhotoUploadError{
+				PhotoID: photo.PhotoID,
+				Error:   err.Error(),
+				Code:    &code,
+			})
+			failed++
+			continue
+		}
+
+		// Save to storage
+		if err := storage.PutBytes(ctx, s.uploads, photoKey, data, detectedMime); err != nil {
 			log.Printf("SyncSatpamPhotos: failed to save file %s: %v", filename, err)
+			s.releasePhotoStorage(ctx, user.CompanyID, int64(len(data)))
 			code := "SAVE_ERROR"
 			errorsList = append(errorsList, &common.PhotoUploadError{
 				PhotoID: photo.PhotoID,
@@ -2547,7 +2737,7 @@ func (s *GateCheckService) SyncSatpamPhotos(ctx context.Context, input generated
 
 		successful++
 		totalBytes += len(data)
-		relativePath := satpamPhotoURLPath(filename)
+		relativePath := storage.URLPath(photoKey)
 
 		// Insert into gate_check_photos table
 		photoRecord := models.GateCheckPhoto{
@@ -2576,7 +2766,8 @@ func (s *GateCheckService) SyncSatpamPhotos(ctx context.Context, input generated
 		if err := s.db.Create(&photoRecord).Error; err != nil {
 			log.Printf("SyncSatpamPhotos: failed to insert photo record for %s: %v", safePhotoID, err)
 			// Clean up the saved file since DB insert failed
-			_ = os.Remove(savePath)
+			_ = s.uploads.Delete(ctx, photoKey)
+			s.releasePhotoStorage(ctx, user.CompanyID, int64(len(data)))
 			successful--
 			failed++
 			code := "DB_INSERT_ERROR"
@@ -2585,6 +2776,11 @@ func (s *GateCheckService) SyncSatpamPhotos(ctx context.Context, input generated
 				Error:   "Failed to save photo record",
 				Code:    &code,
 			})
+		} else if relatedID != "" {
+			subject := photoverify.Subject{RecordType: photoverify.RecordGuestLog, RecordID: relatedID, PhotoURL: relativePath}
+			if _, err := s.verifier.Verify(ctx, subject, data); err != nil {
+				log.Printf("SyncSatpamPhotos: failed to verify photo %s: %v", safePhotoID, err)
+			}
 		}
 	}
 
@@ -2598,6 +2794,13 @@ func (s *GateCheckService) SyncSatpamPhotos(ctx context.Context, input generated
 	}, nil
 }
 
+// releasePhotoStorage gives back storage reserved for a photo that was not kept.
+func (s *GateCheckService) releasePhotoStorage(ctx context.Context, companyID string, sizeBytes int64) {
+	if err := s.tenantPlans.RecordStorageUsage(ctx, companyID, -sizeBytes); err != nil {
+		log.Printf("SyncSatpamPhotos: failed to release storage for company %s: %v", companyID, err)
+	}
+}
+
 // sanitizeEntryTime ensures entry time is not significantly in the future relative to creation time.
 // This handles cases where sync might have incorrectly updated EntryTime to "Now".
 func sanitizeEntryTime(entryTime time.Time, createdAt time.Time) time.Time {
@@ -0,0 +1,351 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"agrinovagraphql/server/internal/gatecheck/models"
+	satpam "agrinovagraphql/server/internal/graphql/domain/satpam"
+
+	"gorm.io/gorm"
+)
+
+// DefaultOverstayMinutes applies when a company has no matching rule.
+const DefaultOverstayMinutes = 480
+
+// maxOverstayRuleMinutes bounds rules to one week.
+const maxOverstayRuleMinutes = 7 * 24 * 60
+
+// ErrOverstayRuleNotFound is returned when an overstay rule does not exist
+var ErrOverstayRuleNotFound = errors.New("overstay rule not found")
+
+// GateOverstayService marks guest vehicles that stay inside longer than the
+// company allows and resolves them when the vehicle leaves.
+type GateOverstayService struct {
+	db *gorm.DB
+}
+
+// NewGateOverstayService creates a new gate overstay service
+func NewGateOverstayService(db *gorm.DB) *GateOverstayService {
+	return &GateOverstayService{db: db}
+}
+
+// OverstayDetectionResult lists the guest logs changed by one detector run.
+type OverstayDetectionResult struct {
+	// Detected were marked overstayed in this run.
+	Detected []*GuestLog
+	// Resolved had an overstay and left through a separate EXIT record or sync.
+	Resolved []*GuestLog
+}
+
+// ResolveMaxStayMinutes returns the stay limit for a vehicle type and
+// destination. A rule naming both wins over one naming the destination, which
+// wins over one naming the vehicle type, which wins over the company default.
+func ResolveMaxStayMinutes(rules []*models.GateOverstayRule, vehicleType satpam.VehicleType, destination *string) int {
+	normalizedDestination := ""
+	if destination != nil {
+		normalizedDestination = normalizeOverstayDestination(*destination)
+	}
+
+	best, bestRank := DefaultOverstayMinutes, -1
+	for _, rule := range rules {
+		if rule == nil || !rule.IsActive {
+			continue
+		}
+
+		rank := 0
+		if rule.VehicleType != nil {
+			if *rule.VehicleType != vehicleType {
+				continue
+			}
+			rank++
+		}
+		if rule.Destination != nil {
+			if normalizeOverstayDestination(*rule.Destination) != normalizedDestination {
+				continue
+			}
+			rank += 2
+		}
+
+		if rank > bestRank {
+			best, bestRank = int(rule.MaxStayMinutes), rank
+		}
+	}
+	return best
+}
+
+// IsOverstayed reports whether a vehicle that entered at entryTime has passed
+// its stay limit at now.
+func IsOverstayed(entryTime time.Time, limitMinutes int, now time.Time) bool {
+	return now.Sub(entryTime) > time.Duration(limitMinutes)*time.Minute
+}
+
+// ValidateOverstayRule checks and normalizes a rule before it is saved.
+func ValidateOverstayRule(rule *models.GateOverstayRule) error {
+	if rule.MaxStayMinutes < 1 || rule.MaxStayMinutes > maxOverstayRuleMinutes {
+		return fmt.Errorf("batas waktu harus 1-%d menit", maxOverstayRuleMinutes)
+	}
+	if rule.VehicleType != nil && !rule.VehicleType.IsValid() {
+		return fmt.Errorf("tipe kendaraan tidak valid: %s", *rule.VehicleType)
+	}
+	if rule.Destination != nil {
+		destination := strings.TrimSpace(*rule.Destination)
+		if destination == "" {
+			rule.Destination = nil
+		} else {
+			rule.Destination = &destination
+		}
+	}
+	return nil
+}
+
+// Detect marks the open guest logs of a company that passed their stay limit
+// and resolves earlier overstays whose vehicle has since left.
+func (s *GateOverstayService) Detect(ctx context.Context, companyID string, now time.Time) (*OverstayDetectionResult, error) {
+	rules, err := s.activeRules(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &OverstayDetectionResult{}
+
+	// The shortest limit bounds which entries can possibly be overstayed.
+	shortest := DefaultOverstayMinutes
+	for _, rule := range rules {
+		if int(rule.MaxStayMinutes) < shortest {
+			shortest = int(rule.MaxStayMinutes)
+		}
+	}
+
+	var openLogs []*GuestLog
+	if err := s.db.WithContext(ctx).
+		Where("company_id = ? AND entry_time IS NOT NULL AND exit_time IS NULL", companyID).
+		Where("overstay_detected_at IS NULL").
+		Where("COALESCE(generation_intent, '') <> ?", string(satpam.GateIntentExit)).
+		Where("entry_time < ?", now.Add(-time.Duration(shortest)*time.Minute)).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM gate_guest_logs AS exit_rec
+			WHERE exit_rec.vehicle_plate = gate_guest_logs.vehicle_plate
+			AND exit_rec.vehicle_type = gate_guest_logs.vehicle_type
+			AND exit_rec.company_id = gate_guest_logs.company_id
+			AND exit_rec.generation_intent = 'EXIT'
+			AND exit_rec.exit_time IS NOT NULL
+			AND exit_rec.exit_time >= gate_guest_logs.entry_time
+			AND exit_rec.deleted_at IS NULL
+		)`).
+		Order("entry_time ASC").
+		Find(&openLogs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load open guest logs: %w", err)
+	}
+
+	for _, guestLog := range openLogs {
+		limit := ResolveMaxStayMinutes(rules, guestLog.VehicleType, guestLog.Destination)
+		if !IsOverstayed(*guestLog.EntryTime, limit, now) {
+			continue
+		}
+
+		update := s.db.WithContext(ctx).
+			Model(&GuestLog{}).
+			Where("id = ? AND overstay_detected_at IS NULL", guestLog.ID).
+			Updates(map[string]interface{}{
+				"overstay_detected_at":   now,
+				"overstay_limit_minutes": limit,
+			})
+		if update.Error != nil {
+			return result, fmt.Errorf("failed to mark overstay for %s: %w", guestLog.ID, update.Error)
+		}
+		if update.RowsAffected == 0 {
+			continue
+		}
+
+		detectedAt := now
+		guestLog.OverstayDetectedAt = &detectedAt
+		guestLog.OverstayLimitMinutes = &limit
+		result.Detected = append(result.Detected, guestLog)
+	}
+
+	resolved, err := s.resolveExited(ctx, companyID, now)
+	if err != nil {
+		return result, err
+	}
+	result.Resolved = resolved
+	return result, nil
+}
+
+// ResolveOnExit closes the overstay of a guest log that is exiting. It
+// reports whether an overstay was resolved.
+func (s *GateOverstayService) ResolveOnExit(guestLog *GuestLog, exitTime time.Time) bool {
+	if guestLog == nil || guestLog.OverstayDetectedAt == nil || guestLog.OverstayResolvedAt != nil {
+		return false
+	}
+	guestLog.OverstayResolvedAt = &exitTime
+	return true
+}
+
+// ResolveByID closes the overstay of an entry that left through a separate
+// EXIT record, such as a stateless QR exit.
+func (s *GateOverstayService) ResolveByID(ctx context.Context, companyID, guestLogID string, exitTime time.Time) (*GuestLog, error) {
+	update := s.db.WithContext(ctx).
+		Model(&GuestLog{}).
+		Where("id = ? AND company_id = ?", guestLogID, companyID).
+		Where("overstay_detected_at IS NOT NULL AND overstay_resolved_at IS NULL").
+		Update("overstay_resolved_at", exitTime)
+	if update.Error != nil {
+		return nil, fmt.Errorf("failed to resolve overstay: %w", update.Error)
+	}
+	if update.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	var guestLog GuestLog
+	if err := s.db.WithContext(ctx).Where("id = ?", guestLogID).First(&guestLog).Error; err != nil {
+		return nil, fmt.Errorf("failed to load resolved guest log: %w", err)
+	}
+	return &guestLog, nil
+}
+
+// MaxStayMinutes returns the stay limit of a guest log.
+func (s *GateOverstayService) MaxStayMinutes(ctx context.Context, guestLog *GuestLog) int {
+	if guestLog.OverstayLimitMinutes != nil {
+		return *guestLog.OverstayLimitMinutes
+	}
+	rules, err := s.activeRules(ctx, guestLog.CompanyID)
+	if err != nil {
+		return DefaultOverstayMinutes
+	}
+	return ResolveMaxStayMinutes(rules, guestLog.VehicleType, guestLog.Destination)
+}
+
+// ListRules returns the overstay rules of a company.
+func (s *GateOverstayService) ListRules(ctx context.Context, companyID string) ([]*models.GateOverstayRule, error) {
+	var rules []*models.GateOverstayRule
+	if err := s.db.WithContext(ctx).
+		Where("company_id = ?", companyID).
+		Order("vehicle_type NULLS FIRST, destination NULLS FIRST").
+		Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list overstay rules: %w", err)
+	}
+	return rules, nil
+}
+
+// GetRule returns an overstay rule of the company.
+func (s *GateOverstayService) GetRule(ctx context.Context, companyID, ruleID string) (*models.GateOverstayRule, error) {
+	var rule models.GateOverstayRule
+	if err := s.db.WithContext(ctx).Where("id = ? AND company_id = ?", ruleID, companyID).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOverstayRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to load overstay rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// SaveRule validates and creates or updates an overstay rule.
+func (s *GateOverstayService) SaveRule(ctx context.Context, rule *models.GateOverstayRule) error {
+	if err := ValidateOverstayRule(rule); err != nil {
+		return err
+	}
+
+	query := s.db.WithContext(ctx).
+		Model(&models.GateOverstayRule{}).
+		Where("company_id = ?", rule.CompanyID)
+	if rule.VehicleType != nil {
+		query = query.Where("vehicle_type = ?", *rule.VehicleType)
+	} else {
+		query = query.Where("vehicle_type IS NULL")
+	}
+	if rule.Destination != nil {
+		query = query.Where("LOWER(destination) = LOWER(?)", *rule.Destination)
+	} else {
+		query = query.Where("destination IS NULL")
+	}
+	if rule.ID != "" {
+		query = query.Where("id <> ?", rule.ID)
+	}
+
+	var duplicate int64
+	if err := query.Count(&duplicate).Error; err != nil {
+		return fmt.Errorf("failed to check overstay rule duplicates: %w", err)
+	}
+	if duplicate > 0 {
+		return fmt.Errorf("aturan overstay untuk tipe kendaraan dan tujuan ini sudah ada")
+	}
+
+	if rule.ID == "" {
+		if err := s.db.WithContext(ctx).Create(rule).Error; err != nil {
+			return fmt.Errorf("failed to create overstay rule: %w", err)
+		}
+		return nil
+	}
+	if err := s.db.WithContext(ctx).Save(rule).Error; err != nil {
+		return fmt.Errorf("failed to update overstay rule: %w", err)
+	}
+	return nil
+}
+
+// DeleteRule removes an overstay rule.
+func (s *GateOverstayService) DeleteRule(ctx context.Context, companyID, ruleID string) error {
+	result := s.db.WithContext(ctx).Where("id = ? AND company_id = ?", ruleID, companyID).Delete(&models.GateOverstayRule{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete overstay rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrOverstayRuleNotFound
+	}
+	return nil
+}
+
+// resolveExited closes overstays whose vehicle left without processGuestExit,
+// through a synced or stateless EXIT record.
+func (s *GateOverstayService) resolveExited(ctx context.Context, companyID string, now time.Time) ([]*GuestLog, error) {
+	var logs []*GuestLog
+	if err := s.db.WithContext(ctx).
+		Where("company_id = ? AND overstay_detected_at IS NOT NULL AND overstay_resolved_at IS NULL", companyID).
+		Where(`(exit_time IS NOT NULL OR EXISTS (
+			SELECT 1 FROM gate_guest_logs AS exit_rec
+			WHERE exit_rec.vehicle_plate = gate_guest_logs.vehicle_plate
+			AND exit_rec.vehicle_type = gate_guest_logs.vehicle_type
+			AND exit_rec.company_id = gate_guest_logs.company_id
+			AND exit_rec.generation_intent = 'EXIT'
+			AND exit_rec.exit_time IS NOT NULL
+			AND exit_rec.exit_time >= gate_guest_logs.entry_time
+			AND exit_rec.deleted_at IS NULL
+		))`).
+		Find(&logs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load exited overstays: %w", err)
+	}
+
+	resolved := make([]*GuestLog, 0, len(logs))
+	for _, guestLog := range logs {
+		resolvedAt := now
+		if guestLog.ExitTime != nil {
+			resolvedAt = *guestLog.ExitTime
+		}
+		if err := s.db.WithContext(ctx).
+			Model(&GuestLog{}).
+			Where("id = ? AND overstay_resolved_at IS NULL", guestLog.ID).
+			Update("overstay_resolved_at", resolvedAt).Error; err != nil {
+			return resolved, fmt.Errorf("failed to resolve overstay for %s: %w", guestLog.ID, err)
+		}
+		guestLog.OverstayResolvedAt = &resolvedAt
+		resolved = append(resolved, guestLog)
+	}
+	return resolved, nil
+}
+
+func (s *GateOverstayService) activeRules(ctx context.Context, companyID string) ([]*models.GateOverstayRule, error) {
+	var rules []*models.GateOverstayRule
+	if err := s.db.WithContext(ctx).
+		Where("company_id = ? AND is_active = ?", companyID, true).
+		Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to load overstay rules: %w", err)
+	}
+	return rules, nil
+}
+
+func normalizeOverstayDestination(destination string) string {
+	return strings.ToLower(strings.Join(strings.Fields(destination), " "))
+}
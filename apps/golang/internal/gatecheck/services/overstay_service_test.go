@@ -0,0 +1,67 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"agrinovagraphql/server/internal/gatecheck/models"
+	satpam "agrinovagraphql/server/internal/graphql/domain/satpam"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveMaxStayMinutes(t *testing.T) {
+	truck := satpam.VehicleTypeTruck
+	mill := "Pabrik  Kelapa Sawit"
+	office := "Kantor"
+
+	rules := []*models.GateOverstayRule{
+		{ID: "default", MaxStayMinutes: 600, IsActive: true},
+		{ID: "truck", VehicleType: &truck, MaxStayMinutes: 240, IsActive: true},
+		{ID: "office", Destination: &office, MaxStayMinutes: 120, IsActive: true},
+		{ID: "truck-mill", VehicleType: &truck, Destination: &mill, MaxStayMinutes: 90, IsActive: true},
+		{ID: "inactive", Destination: &office, MaxStayMinutes: 30, IsActive: false},
+	}
+
+	destination := " pabrik kelapa SAWIT "
+	assert.Equal(t, 90, ResolveMaxStayMinutes(rules, truck, &destination))
+
+	destination = "kantor"
+	assert.Equal(t, 120, ResolveMaxStayMinutes(rules, truck, &destination))
+	assert.Equal(t, 240, ResolveMaxStayMinutes(rules, truck, nil))
+	assert.Equal(t, 600, ResolveMaxStayMinutes(rules, satpam.VehicleTypeCar, nil))
+	assert.Equal(t, DefaultOverstayMinutes, ResolveMaxStayMinutes(nil, truck, nil))
+}
+
+func TestIsOverstayed(t *testing.T) {
+	entry := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+
+	assert.False(t, IsOverstayed(entry, 60, entry.Add(60*time.Minute)))
+	assert.True(t, IsOverstayed(entry, 60, entry.Add(61*time.Minute)))
+}
+
+func TestValidateOverstayRule(t *testing.T) {
+	blank := "   "
+	rule := &models.GateOverstayRule{MaxStayMinutes: 30, Destination: &blank}
+	require.NoError(t, ValidateOverstayRule(rule))
+	assert.Nil(t, rule.Destination)
+
+	invalidType := satpam.VehicleType("BOAT")
+	assert.Error(t, ValidateOverstayRule(&models.GateOverstayRule{MaxStayMinutes: 0}))
+	assert.Error(t, ValidateOverstayRule(&models.GateOverstayRule{MaxStayMinutes: maxOverstayRuleMinutes + 1}))
+	assert.Error(t, ValidateOverstayRule(&models.GateOverstayRule{MaxStayMinutes: 30, VehicleType: &invalidType}))
+}
+
+func TestResolveOnExit(t *testing.T) {
+	svc := &GateOverstayService{}
+	exit := time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC)
+	detected := exit.Add(-time.Hour)
+
+	assert.False(t, svc.ResolveOnExit(&GuestLog{}, exit))
+
+	guestLog := &GuestLog{OverstayDetectedAt: &detected}
+	require.True(t, svc.ResolveOnExit(guestLog, exit))
+	assert.Equal(t, exit, *guestLog.OverstayResolvedAt)
+	assert.False(t, svc.ResolveOnExit(guestLog, exit.Add(time.Minute)))
+}
@@ -0,0 +1,505 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"agrinovagraphql/server/internal/gatecheck/models"
+	satpam "agrinovagraphql/server/internal/graphql/domain/satpam"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrWatchlistEntryNotFound is returned when a watchlist entry does not exist
+	ErrWatchlistEntryNotFound = errors.New("watchlist entry not found")
+	// ErrWatchlistHitNotFound is returned when a watchlist hit does not exist
+	ErrWatchlistHitNotFound = errors.New("watchlist hit not found")
+)
+
+// defaultWatchlistHitLimit bounds ListHits when no limit is given.
+const defaultWatchlistHitLimit = 100
+
+// GateWatchlistService screens guests against the company watchlist and
+// records the hits and supervisor overrides.
+type GateWatchlistService struct {
+	db *gorm.DB
+}
+
+// NewGateWatchlistService creates a new gate watchlist service
+func NewGateWatchlistService(db *gorm.DB) *GateWatchlistService {
+	return &GateWatchlistService{db: db}
+}
+
+// WatchlistCandidate is the guest identity screened at the gate.
+type WatchlistCandidate struct {
+	VehiclePlate string
+	IDCardNumber *string
+	DriverName   string
+}
+
+// WatchlistScreenInput describes one gate operation to screen.
+type WatchlistScreenInput struct {
+	CompanyID  string
+	Candidate  WatchlistCandidate
+	Source     satpam.GateWatchlistHitSource
+	GuestLogID *string
+	DeviceID   string
+	ScannedBy  string
+	// OverrideHitID is an approved REQUIRE_OVERRIDE hit presented by satpam
+	// when registering the guest again.
+	OverrideHitID *string
+}
+
+// WatchlistScreening is the outcome of screening a guest.
+type WatchlistScreening struct {
+	Allowed bool
+	Message string
+	Hit     *models.GateWatchlistHit
+	// Created is false when an earlier hit of the same guest log was reused,
+	// so callers alert managers only once per hit.
+	Created bool
+}
+
+// NormalizeWatchlistValue canonicalizes a value for matching: plates and ID
+// card numbers keep only upper-case letters and digits, names are lower-cased
+// with single spaces.
+func NormalizeWatchlistValue(matchType satpam.GateWatchlistMatchType, value string) string {
+	switch matchType {
+	case satpam.GateWatchlistMatchTypePlate, satpam.GateWatchlistMatchTypeIDCard:
+		var builder strings.Builder
+		for _, r := range strings.ToUpper(value) {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				builder.WriteRune(r)
+			}
+		}
+		return builder.String()
+	case satpam.GateWatchlistMatchTypeName:
+		return strings.Join(strings.Fields(strings.ToLower(value)), " ")
+	default:
+		return strings.TrimSpace(value)
+	}
+}
+
+// MatchWatchlist returns the active, unexpired entries matching the candidate,
+// most severe first.
+func MatchWatchlist(entries []*models.GateWatchlistEntry, candidate WatchlistCandidate, now time.Time) []*models.GateWatchlistEntry {
+	values := map[satpam.GateWatchlistMatchType]string{
+		satpam.GateWatchlistMatchTypePlate: NormalizeWatchlistValue(satpam.GateWatchlistMatchTypePlate, candidate.VehiclePlate),
+		satpam.GateWatchlistMatchTypeName:  NormalizeWatchlistValue(satpam.GateWatchlistMatchTypeName, candidate.DriverName),
+	}
+	if candidate.IDCardNumber != nil {
+		values[satpam.GateWatchlistMatchTypeIDCard] = NormalizeWatchlistValue(satpam.GateWatchlistMatchTypeIDCard, *candidate.IDCardNumber)
+	}
+
+	matches := make([]*models.GateWatchlistEntry, 0)
+	for _, entry := range entries {
+		if entry == nil || !entry.IsActive {
+			continue
+		}
+		if entry.ExpiresAt != nil && !entry.ExpiresAt.After(now) {
+			continue
+		}
+		value := values[entry.MatchType]
+		if value == "" || value != entry.NormalizedValue {
+			continue
+		}
+		matches = append(matches, entry)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return watchlistSeverityRank(matches[i].Severity) > watchlistSeverityRank(matches[j].Severity)
+	})
+	return matches
+}
+
+// ValidateWatchlistEntry checks and normalizes an entry before it is saved.
+func ValidateWatchlistEntry(entry *models.GateWatchlistEntry, now time.Time) error {
+	switch entry.MatchType {
+	case satpam.GateWatchlistMatchTypePlate, satpam.GateWatchlistMatchTypeIDCard, satpam.GateWatchlistMatchTypeName:
+	default:
+		return fmt.Errorf("tipe watchlist tidak valid: %s", entry.MatchType)
+	}
+	if watchlistSeverityRank(entry.Severity) == 0 {
+		return fmt.Errorf("tingkat watchlist tidak valid: %s", entry.Severity)
+	}
+
+	entry.Value = strings.TrimSpace(entry.Value)
+	entry.NormalizedValue = NormalizeWatchlistValue(entry.MatchType, entry.Value)
+	if entry.NormalizedValue == "" || len(entry.Value) > 150 {
+		return fmt.Errorf("nilai watchlist harus 1-150 karakter")
+	}
+	entry.Reason = strings.TrimSpace(entry.Reason)
+	if entry.Reason == "" {
+		return fmt.Errorf("alasan watchlist wajib diisi")
+	}
+	if entry.ExpiresAt != nil && !entry.ExpiresAt.After(now) {
+		return fmt.Errorf("tanggal kedaluwarsa harus di masa depan")
+	}
+	return nil
+}
+
+// Screen checks a guest against the company watchlist and records a hit.
+// WARN entries admit the guest, BLOCK entries refuse it and REQUIRE_OVERRIDE
+// entries refuse it until a supervisor approves the hit. Synced records were
+// already admitted offline, so they are only flagged. A nil result means the
+// guest is not on the watchlist.
+func (s *GateWatchlistService) Screen(ctx context.Context, input WatchlistScreenInput) (*WatchlistScreening, error) {
+	if strings.TrimSpace(input.CompanyID) == "" {
+		return nil, nil
+	}
+
+	entries, err := s.activeEntries(ctx, input.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	return s.screenAgainst(ctx, entries, input)
+}
+
+// ScreenSynced flags synced guest logs of one company that match the
+// watchlist. Results are keyed by guest log ID.
+func (s *GateWatchlistService) ScreenSynced(ctx context.Context, companyID string, inputs []WatchlistScreenInput) (map[string]*WatchlistScreening, error) {
+	screenings := make(map[string]*WatchlistScreening)
+	if strings.TrimSpace(companyID) == "" || len(inputs) == 0 {
+		return screenings, nil
+	}
+
+	entries, err := s.activeEntries(ctx, companyID)
+	if err != nil || len(entries) == 0 {
+		return screenings, err
+	}
+
+	for _, input := range inputs {
+		if input.GuestLogID == nil {
+			continue
+		}
+		input.CompanyID = companyID
+		input.Source = satpam.GateWatchlistHitSourceSync
+		screening, err := s.screenAgainst(ctx, entries, input)
+		if err != nil {
+			return screenings, err
+		}
+		if screening != nil {
+			screenings[*input.GuestLogID] = screening
+		}
+	}
+	return screenings, nil
+}
+
+func (s *GateWatchlistService) activeEntries(ctx context.Context, companyID string) ([]*models.GateWatchlistEntry, error) {
+	var entries []*models.GateWatchlistEntry
+	if err := s.db.WithContext(ctx).
+		Where("company_id = ? AND is_active = ?", companyID, true).
+		Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to load watchlist: %w", err)
+	}
+	return entries, nil
+}
+
+func (s *GateWatchlistService) screenAgainst(ctx context.Context, entries []*models.GateWatchlistEntry, input WatchlistScreenInput) (*WatchlistScreening, error) {
+	now := time.Now()
+	matches := MatchWatchlist(entries, input.Candidate, now)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	entry := matches[0]
+
+	if entry.Severity == satpam.GateWatchlistSeverityRequireOverride {
+		approved, err := s.findApprovedOverride(ctx, input, entry)
+		if err != nil {
+			return nil, err
+		}
+		if approved != nil {
+			return &WatchlistScreening{Allowed: true, Message: "Disetujui supervisor", Hit: approved}, nil
+		}
+	}
+
+	// Repeated QR scans and re-syncs of the same guest log reuse their hit.
+	if input.GuestLogID != nil {
+		var existing models.GateWatchlistHit
+		err := s.db.WithContext(ctx).
+			Where("guest_log_id = ? AND entry_id = ?", *input.GuestLogID, entry.ID).
+			Order("created_at DESC").
+			First(&existing).Error
+		if err == nil && existing.Status != satpam.GateWatchlistHitStatusOverrideRejected {
+			return screeningFor(&existing), nil
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to load watchlist hit: %w", err)
+		}
+	}
+
+	hit := &models.GateWatchlistHit{
+		CompanyID:    input.CompanyID,
+		EntryID:      entry.ID,
+		Source:       input.Source,
+		Status:       WatchlistHitStatus(entry.Severity, input.Source),
+		Severity:     entry.Severity,
+		MatchType:    entry.MatchType,
+		MatchedValue: entry.Value,
+		Reason:       entry.Reason,
+		VehiclePlate: strings.TrimSpace(input.Candidate.VehiclePlate),
+		DriverName:   strings.TrimSpace(input.Candidate.DriverName),
+		IDCardNumber: input.Candidate.IDCardNumber,
+		GuestLogID:   input.GuestLogID,
+		DeviceID:     input.DeviceID,
+		ScannedBy:    input.ScannedBy,
+	}
+	if err := s.db.WithContext(ctx).Create(hit).Error; err != nil {
+		return nil, fmt.Errorf("failed to record watchlist hit: %w", err)
+	}
+	screening := screeningFor(hit)
+	screening.Created = true
+	return screening, nil
+}
+
+// WatchlistHitStatus returns the status of a new hit for an entry severity.
+func WatchlistHitStatus(severity satpam.GateWatchlistSeverity, source satpam.GateWatchlistHitSource) satpam.GateWatchlistHitStatus {
+	if source == satpam.GateWatchlistHitSourceSync {
+		return satpam.GateWatchlistHitStatusFlagged
+	}
+	switch severity {
+	case satpam.GateWatchlistSeverityBlock:
+		return satpam.GateWatchlistHitStatusBlocked
+	case satpam.GateWatchlistSeverityRequireOverride:
+		return satpam.GateWatchlistHitStatusPendingOverride
+	default:
+		return satpam.GateWatchlistHitStatusFlagged
+	}
+}
+
+// AttachGuestLog links a registration hit to the guest log it admitted, so
+// later QR validations of that guest find the approved override. An approved
+// override is consumed.
+func (s *GateWatchlistService) AttachGuestLog(ctx context.Context, hit *models.GateWatchlistHit, guestLogID string) error {
+	status := hit.Status
+	if status == satpam.GateWatchlistHitStatusOverrideApproved {
+		status = satpam.GateWatchlistHitStatusOverrideUsed
+	}
+	if err := s.db.WithContext(ctx).
+		Model(&models.GateWatchlistHit{}).
+		Where("id = ?", hit.ID).
+		Updates(map[string]interface{}{
+			"guest_log_id": guestLogID,
+			"status":       status,
+			"updated_at":   time.Now(),
+		}).Error; err != nil {
+		return fmt.Errorf("failed to link watchlist hit: %w", err)
+	}
+	hit.GuestLogID = &guestLogID
+	hit.Status = status
+	return nil
+}
+
+// ReviewOverride approves or rejects a hit waiting for a supervisor override.
+func (s *GateWatchlistService) ReviewOverride(ctx context.Context, companyID, hitID, reviewerID string, approve bool, notes *string) (*models.GateWatchlistHit, error) {
+	hit, err := s.GetHit(ctx, companyID, hitID)
+	if err != nil {
+		return nil, err
+	}
+	if hit.Status != satpam.GateWatchlistHitStatusPendingOverride {
+		return nil, fmt.Errorf("hit watchlist tidak menunggu persetujuan (status %s)", hit.Status)
+	}
+
+	now := time.Now()
+	hit.Status = satpam.GateWatchlistHitStatusOverrideRejected
+	if approve {
+		hit.Status = satpam.GateWatchlistHitStatusOverrideApproved
+	}
+	hit.ReviewedBy = &reviewerID
+	hit.ReviewedAt = &now
+	hit.ReviewNotes = notes
+	if err := s.db.WithContext(ctx).Save(hit).Error; err != nil {
+		return nil, fmt.Errorf("failed to review watchlist hit: %w", err)
+	}
+	return hit, nil
+}
+
+// GetHit returns a hit of the company.
+func (s *GateWatchlistService) GetHit(ctx context.Context, companyID, hitID string) (*models.GateWatchlistHit, error) {
+	var hit models.GateWatchlistHit
+	if err := s.db.WithContext(ctx).Where("id = ? AND company_id = ?", hitID, companyID).First(&hit).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWatchlistHitNotFound
+		}
+		return nil, fmt.Errorf("failed to load watchlist hit: %w", err)
+	}
+	return &hit, nil
+}
+
+// ListHits returns the most recent hits of a company, optionally by status.
+func (s *GateWatchlistService) ListHits(ctx context.Context, companyID string, status *satpam.GateWatchlistHitStatus, limit int) ([]*models.GateWatchlistHit, error) {
+	if limit <= 0 || limit > defaultWatchlistHitLimit {
+		limit = defaultWatchlistHitLimit
+	}
+	query := s.db.WithContext(ctx).Where("company_id = ?", companyID)
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+
+	var hits []*models.GateWatchlistHit
+	if err := query.Order("created_at DESC").Limit(limit).Find(&hits).Error; err != nil {
+		return nil, fmt.Errorf("failed to list watchlist hits: %w", err)
+	}
+	return hits, nil
+}
+
+// ListEntries returns the watchlist of a company, newest first.
+func (s *GateWatchlistService) ListEntries(ctx context.Context, companyID string, includeInactive bool) ([]*models.GateWatchlistEntry, error) {
+	query := s.db.WithContext(ctx).Where("company_id = ?", companyID)
+	if !includeInactive {
+		query = query.Where("is_active = ? AND (expires_at IS NULL OR expires_at > ?)", true, time.Now())
+	}
+
+	var entries []*models.GateWatchlistEntry
+	if err := query.Order("created_at DESC").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list watchlist: %w", err)
+	}
+	return entries, nil
+}
+
+// GetEntry returns a watchlist entry of the company.
+func (s *GateWatchlistService) GetEntry(ctx context.Context, companyID, entryID string) (*models.GateWatchlistEntry, error) {
+	var entry models.GateWatchlistEntry
+	if err := s.db.WithContext(ctx).Where("id = ? AND company_id = ?", entryID, companyID).First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWatchlistEntryNotFound
+		}
+		return nil, fmt.Errorf("failed to load watchlist entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// SaveEntry validates and creates or updates a watchlist entry.
+func (s *GateWatchlistService) SaveEntry(ctx context.Context, entry *models.GateWatchlistEntry) error {
+	if err := ValidateWatchlistEntry(entry, time.Now()); err != nil {
+		return err
+	}
+
+	var duplicate int64
+	query := s.db.WithContext(ctx).
+		Model(&models.GateWatchlistEntry{}).
+		Where("company_id = ? AND match_type = ? AND normalized_value = ?", entry.CompanyID, entry.MatchType, entry.NormalizedValue)
+	if entry.ID != "" {
+		query = query.Where("id <> ?", entry.ID)
+	}
+	if err := query.Count(&duplicate).Error; err != nil {
+		return fmt.Errorf("failed to check watchlist duplicates: %w", err)
+	}
+	if duplicate > 0 {
+		return fmt.Errorf("%s %s sudah ada di watchlist", entry.MatchType, entry.Value)
+	}
+
+	if entry.ID == "" {
+		if err := s.db.WithContext(ctx).Create(entry).Error; err != nil {
+			return fmt.Errorf("failed to create watchlist entry: %w", err)
+		}
+		return nil
+	}
+	if err := s.db.WithContext(ctx).Save(entry).Error; err != nil {
+		return fmt.Errorf("failed to update watchlist entry: %w", err)
+	}
+	return nil
+}
+
+// DeleteEntry removes a watchlist entry. Recorded hits keep their reason.
+func (s *GateWatchlistService) DeleteEntry(ctx context.Context, companyID, entryID string) error {
+	result := s.db.WithContext(ctx).Where("id = ? AND company_id = ?", entryID, companyID).Delete(&models.GateWatchlistEntry{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete watchlist entry: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrWatchlistEntryNotFound
+	}
+	return nil
+}
+
+// findApprovedOverride returns an approved or used override for the entry:
+// either the hit satpam presented with the registration, or one already
+// linked to the guest log being validated.
+func (s *GateWatchlistService) findApprovedOverride(ctx context.Context, input WatchlistScreenInput, entry *models.GateWatchlistEntry) (*models.GateWatchlistHit, error) {
+	query := s.db.WithContext(ctx).
+		Where("company_id = ? AND entry_id = ? AND status IN ?", input.CompanyID, entry.ID, []satpam.GateWatchlistHitStatus{
+			satpam.GateWatchlistHitStatusOverrideApproved,
+			satpam.GateWatchlistHitStatusOverrideUsed,
+		})
+	switch {
+	case input.GuestLogID != nil:
+		query = query.Where("guest_log_id = ?", *input.GuestLogID)
+	case input.OverrideHitID != nil && strings.TrimSpace(*input.OverrideHitID) != "":
+		query = query.Where("id = ? AND status = ?", strings.TrimSpace(*input.OverrideHitID), satpam.GateWatchlistHitStatusOverrideApproved)
+	default:
+		return nil, nil
+	}
+
+	var hit models.GateWatchlistHit
+	if err := query.Order("reviewed_at DESC").First(&hit).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load watchlist override: %w", err)
+	}
+	return &hit, nil
+}
+
+func screeningFor(hit *models.GateWatchlistHit) *WatchlistScreening {
+	screening := &WatchlistScreening{Hit: hit}
+	switch hit.Status {
+	case satpam.GateWatchlistHitStatusBlocked:
+		screening.Message = fmt.Sprintf("Masuk ditolak: %s %s ada di daftar hitam (%s)", hit.MatchType, hit.MatchedValue, hit.Reason)
+	case satpam.GateWatchlistHitStatusPendingOverride:
+		screening.Message = fmt.Sprintf("%s %s ada di watchlist (%s). Menunggu persetujuan supervisor", hit.MatchType, hit.MatchedValue, hit.Reason)
+	case satpam.GateWatchlistHitStatusOverrideRejected:
+		screening.Message = fmt.Sprintf("Masuk ditolak supervisor: %s %s (%s)", hit.MatchType, hit.MatchedValue, hit.Reason)
+	default:
+		screening.Allowed = true
+		screening.Message = fmt.Sprintf("Perhatian: %s %s ada di watchlist (%s)", hit.MatchType, hit.MatchedValue, hit.Reason)
+	}
+	return screening
+}
+
+func watchlistSeverityRank(severity satpam.GateWatchlistSeverity) int {
+	switch severity {
+	case satpam.GateWatchlistSeverityBlock:
+		return 3
+	case satpam.GateWatchlistSeverityRequireOverride:
+		return 2
+	case satpam.GateWatchlistSeverityWarn:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ToGateWatchlistAlert converts a hit to its GraphQL alert.
+func ToGateWatchlistAlert(hit *models.GateWatchlistHit) *satpam.GateWatchlistAlert {
+	if hit == nil {
+		return nil
+	}
+	return &satpam.GateWatchlistAlert{
+		HitID:            hit.ID,
+		CompanyID:        hit.CompanyID,
+		Source:           hit.Source,
+		Status:           hit.Status,
+		Severity:         hit.Severity,
+		MatchType:        hit.MatchType,
+		MatchedValue:     hit.MatchedValue,
+		Reason:           hit.Reason,
+		VehiclePlate:     hit.VehiclePlate,
+		DriverName:       hit.DriverName,
+		IDCardNumber:     hit.IDCardNumber,
+		GuestLogID:       hit.GuestLogID,
+		DeviceID:         hit.DeviceID,
+		Blocked:          hit.Status == satpam.GateWatchlistHitStatusBlocked || hit.Status == satpam.GateWatchlistHitStatusOverrideRejected,
+		RequiresOverride: hit.Status == satpam.GateWatchlistHitStatusPendingOverride,
+		ReviewedBy:       hit.ReviewedBy,
+		ReviewedAt:       hit.ReviewedAt,
+		ReviewNotes:      hit.ReviewNotes,
+		CreatedAt:        hit.CreatedAt,
+	}
+}
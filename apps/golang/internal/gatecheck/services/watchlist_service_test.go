@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"agrinovagraphql/server/internal/gatecheck/models"
+	satpam "agrinovagraphql/server/internal/graphql/domain/satpam"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeWatchlistValue(t *testing.T) {
+	assert.Equal(t, "KH1234AB", NormalizeWatchlistValue(satpam.GateWatchlistMatchTypePlate, " kh 1234-ab "))
+	assert.Equal(t, "6301010101010001", NormalizeWatchlistValue(satpam.GateWatchlistMatchTypeIDCard, "6301.0101.0101.0001"))
+	assert.Equal(t, "budi santoso", NormalizeWatchlistValue(satpam.GateWatchlistMatchTypeName, "  Budi   SANTOSO "))
+}
+
+func TestMatchWatchlist(t *testing.T) {
+	now := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+	idCard := "6301-0101"
+
+	entries := []*models.GateWatchlistEntry{
+		{ID: "warn-name", MatchType: satpam.GateWatchlistMatchTypeName, NormalizedValue: "budi santoso", Severity: satpam.GateWatchlistSeverityWarn, IsActive: true},
+		{ID: "block-plate", MatchType: satpam.GateWatchlistMatchTypePlate, NormalizedValue: "KH1234AB", Severity: satpam.GateWatchlistSeverityBlock, IsActive: true, ExpiresAt: &future},
+		{ID: "expired", MatchType: satpam.GateWatchlistMatchTypeIDCard, NormalizedValue: "63010101", Severity: satpam.GateWatchlistSeverityBlock, IsActive: true, ExpiresAt: &past},
+		{ID: "inactive", MatchType: satpam.GateWatchlistMatchTypePlate, NormalizedValue: "KH1234AB", Severity: satpam.GateWatchlistSeverityRequireOverride, IsActive: false},
+		{ID: "other", MatchType: satpam.GateWatchlistMatchTypePlate, NormalizedValue: "DA9999XX", Severity: satpam.GateWatchlistSeverityBlock, IsActive: true},
+	}
+
+	matches := MatchWatchlist(entries, WatchlistCandidate{
+		VehiclePlate: "KH 1234 AB",
+		IDCardNumber: &idCard,
+		DriverName:   "Budi Santoso",
+	}, now)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "block-plate", matches[0].ID)
+	assert.Equal(t, "warn-name", matches[1].ID)
+
+	assert.Empty(t, MatchWatchlist(entries, WatchlistCandidate{VehiclePlate: "B 1 A", DriverName: ""}, now))
+}
+
+func TestValidateWatchlistEntry(t *testing.T) {
+	now := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+
+	entry := &models.GateWatchlistEntry{MatchType: satpam.GateWatchlistMatchTypePlate, Value: " kh 1234 ab ", Reason: " Pencurian TBS ", Severity: satpam.GateWatchlistSeverityBlock}
+	require.NoError(t, ValidateWatchlistEntry(entry, now))
+	assert.Equal(t, "kh 1234 ab", entry.Value)
+	assert.Equal(t, "KH1234AB", entry.NormalizedValue)
+	assert.Equal(t, "Pencurian TBS", entry.Reason)
+
+	assert.Error(t, ValidateWatchlistEntry(&models.GateWatchlistEntry{MatchType: "EMAIL", Value: "x", Reason: "x", Severity: satpam.GateWatchlistSeverityWarn}, now))
+	assert.Error(t, ValidateWatchlistEntry(&models.GateWatchlistEntry{MatchType: satpam.GateWatchlistMatchTypeName, Value: "x", Reason: "x", Severity: "HIGH"}, now))
+	assert.Error(t, ValidateWatchlistEntry(&models.GateWatchlistEntry{MatchType: satpam.GateWatchlistMatchTypePlate, Value: " - ", Reason: "x", Severity: satpam.GateWatchlistSeverityWarn}, now))
+	assert.Error(t, ValidateWatchlistEntry(&models.GateWatchlistEntry{MatchType: satpam.GateWatchlistMatchTypeName, Value: "x", Reason: " ", Severity: satpam.GateWatchlistSeverityWarn}, now))
+	assert.Error(t, ValidateWatchlistEntry(&models.GateWatchlistEntry{MatchType: satpam.GateWatchlistMatchTypeName, Value: "x", Reason: "x", Severity: satpam.GateWatchlistSeverityWarn, ExpiresAt: &past}, now))
+}
+
+func TestWatchlistHitStatusAndAlert(t *testing.T) {
+	assert.Equal(t, satpam.GateWatchlistHitStatusFlagged, WatchlistHitStatus(satpam.GateWatchlistSeverityWarn, satpam.GateWatchlistHitSourceRegistration))
+	assert.Equal(t, satpam.GateWatchlistHitStatusBlocked, WatchlistHitStatus(satpam.GateWatchlistSeverityBlock, satpam.GateWatchlistHitSourceQRValidation))
+	assert.Equal(t, satpam.GateWatchlistHitStatusPendingOverride, WatchlistHitStatus(satpam.GateWatchlistSeverityRequireOverride, satpam.GateWatchlistHitSourceRegistration))
+	assert.Equal(t, satpam.GateWatchlistHitStatusFlagged, WatchlistHitStatus(satpam.GateWatchlistSeverityBlock, satpam.GateWatchlistHitSourceSync))
+
+	pending := screeningFor(&models.GateWatchlistHit{ID: "hit-1", Status: satpam.GateWatchlistHitStatusPendingOverride})
+	assert.False(t, pending.Allowed)
+	alert := ToGateWatchlistAlert(pending.Hit)
+	assert.True(t, alert.RequiresOverride)
+	assert.False(t, alert.Blocked)
+
+	assert.False(t, screeningFor(&models.GateWatchlistHit{Status: satpam.GateWatchlistHitStatusBlocked}).Allowed)
+	assert.True(t, screeningFor(&models.GateWatchlistHit{Status: satpam.GateWatchlistHitStatusFlagged}).Allowed)
+	assert.True(t, screeningFor(&models.GateWatchlistHit{Status: satpam.GateWatchlistHitStatusOverrideUsed}).Allowed)
+	assert.Nil(t, ToGateWatchlistAlert(nil))
+}
@@ -0,0 +1,172 @@
+package dataloader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultWait is how long a loader collects keys before it hits the
+	// database. Sibling field resolvers run concurrently, so a couple of
+	// milliseconds is enough to gather a whole list level.
+	defaultWait = 2 * time.Millisecond
+	// defaultMaxBatch caps the IN (...) list of a single query.
+	defaultMaxBatch = 500
+)
+
+// FetchFunc loads the values for a batch of keys. Keys missing from the
+// returned map resolve to the zero value (nil for pointer types).
+type FetchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader batches and caches lookups by key for the lifetime of one request.
+type Loader[K comparable, V any] struct {
+	name     string
+	ctx      context.Context
+	fetch    FetchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	cache map[K]*result[V]
+	batch *batch[K, V]
+}
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+type batch[K comparable, V any] struct {
+	keys    []K
+	results []*result[V]
+}
+
+// NewLoader creates a loader whose fetches run with ctx, the request context.
+func NewLoader[K comparable, V any](ctx context.Context, name string, fetch FetchFunc[K, V]) *Loader[K, V] {
+	return &Loader[K, V]{
+		name:     name,
+		ctx:      ctx,
+		fetch:    fetch,
+		wait:     defaultWait,
+		maxBatch: defaultMaxBatch,
+		cache:    make(map[K]*result[V]),
+	}
+}
+
+// Load returns the value for key, waiting for the batch that contains it.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	return l.await(ctx, l.enqueue(key))
+}
+
+// LoadMany resolves all keys, usually in a single batch, and returns the
+// values in key order.
+func (l *Loader[K, V]) LoadMany(ctx context.Context, keys []K) ([]V, error) {
+	pending := make([]*result[V], len(keys))
+	for i, key := range keys {
+		pending[i] = l.enqueue(key)
+	}
+
+	values := make([]V, len(keys))
+	for i, res := range pending {
+		value, err := l.await(ctx, res)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// Prime stores a value that was already loaded elsewhere, such as a gorm
+// preload, so later Loads for the key do not hit the database.
+func (l *Loader[K, V]) Prime(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.cache[key]; ok {
+		return
+	}
+	res := &result[V]{done: make(chan struct{}), value: value}
+	close(res.done)
+	l.cache[key] = res
+}
+
+func (l *Loader[K, V]) enqueue(key K) *result[V] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if res, ok := l.cache[key]; ok {
+		recordLoad(l.name, true)
+		return res
+	}
+	recordLoad(l.name, false)
+
+	res := &result[V]{done: make(chan struct{})}
+	l.cache[key] = res
+
+	if l.batch == nil {
+		b := &batch[K, V]{}
+		l.batch = b
+		time.AfterFunc(l.wait, func() { l.dispatch(b) })
+	}
+	l.batch.keys = append(l.batch.keys, key)
+	l.batch.results = append(l.batch.results, res)
+
+	if len(l.batch.keys) >= l.maxBatch {
+		full := l.batch
+		l.batch = nil
+		go l.run(full)
+	}
+	return res
+}
+
+func (l *Loader[K, V]) dispatch(b *batch[K, V]) {
+	l.mu.Lock()
+	if l.batch != b {
+		// Already sent because it filled up.
+		l.mu.Unlock()
+		return
+	}
+	l.batch = nil
+	l.mu.Unlock()
+
+	l.run(b)
+}
+
+func (l *Loader[K, V]) run(b *batch[K, V]) {
+	values, err := l.fetch(l.ctx, b.keys)
+	recordBatch(l.name, len(b.keys), err)
+
+	for i, key := range b.keys {
+		res := b.results[i]
+		if err != nil {
+			res.err = err
+		} else {
+			res.value = values[key]
+		}
+		close(res.done)
+	}
+
+	if err != nil {
+		// Do not cache failures; a later Load in the same request retries.
+		l.mu.Lock()
+		for i, key := range b.keys {
+			if l.cache[key] == b.results[i] {
+				delete(l.cache, key)
+			}
+		}
+		l.mu.Unlock()
+	}
+}
+
+func (l *Loader[K, V]) await(ctx context.Context, res *result[V]) (V, error) {
+	select {
+	case <-res.done:
+		return res.value, res.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
@@ -0,0 +1,136 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+)
+
+type fetchRecorder struct {
+	mu      sync.Mutex
+	batches [][]string
+	fail    bool
+}
+
+func (f *fetchRecorder) fetch(_ context.Context, keys []string) (map[string]*string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	batch := append([]string(nil), keys...)
+	sort.Strings(batch)
+	f.batches = append(f.batches, batch)
+	if f.fail {
+		return nil, errors.New("boom")
+	}
+
+	result := make(map[string]*string, len(keys))
+	for _, key := range keys {
+		if key == "missing" {
+			continue
+		}
+		value := "value-" + key
+		result[key] = &value
+	}
+	return result, nil
+}
+
+func TestLoader_BatchesConcurrentLoads(t *testing.T) {
+	recorder := &fetchRecorder{}
+	loader := NewLoader(context.Background(), "test", recorder.fetch)
+
+	keys := []string{"a", "b", "c", "a", "missing"}
+	values := make([]*string, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func(i int, key string) {
+			defer wg.Done()
+			value, err := loader.Load(context.Background(), key)
+			if err != nil {
+				t.Errorf("Load(%q) error: %v", key, err)
+			}
+			values[i] = value
+		}(i, key)
+	}
+	wg.Wait()
+
+	if len(recorder.batches) != 1 {
+		t.Fatalf("batches = %v, want a single batch", recorder.batches)
+	}
+	if got := recorder.batches[0]; len(got) != 4 {
+		t.Fatalf("batch keys = %v, want 4 distinct keys", got)
+	}
+	if values[0] == nil || *values[0] != "value-a" || values[3] != values[0] {
+		t.Errorf("values for a = %v, %v", values[0], values[3])
+	}
+	if values[4] != nil {
+		t.Errorf("missing key resolved to %v, want nil", *values[4])
+	}
+
+	// Cached keys do not trigger another fetch.
+	if _, err := loader.Load(context.Background(), "b"); err != nil {
+		t.Fatalf("cached Load error: %v", err)
+	}
+	if len(recorder.batches) != 1 {
+		t.Errorf("cached Load fetched again: %v", recorder.batches)
+	}
+}
+
+func TestLoader_LoadManySplitsAtMaxBatch(t *testing.T) {
+	recorder := &fetchRecorder{}
+	loader := NewLoader(context.Background(), "test", recorder.fetch)
+	loader.maxBatch = 2
+
+	values, err := loader.LoadMany(context.Background(), []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("LoadMany error: %v", err)
+	}
+	if len(values) != 3 || *values[2] != "value-c" {
+		t.Fatalf("values = %v", values)
+	}
+	if len(recorder.batches) != 2 {
+		t.Errorf("batches = %v, want 2", recorder.batches)
+	}
+}
+
+func TestLoader_PrimeAndErrors(t *testing.T) {
+	recorder := &fetchRecorder{fail: true}
+	loader := NewLoader(context.Background(), "test", recorder.fetch)
+
+	primed := "primed"
+	loader.Prime("p", &primed)
+	if value, err := loader.Load(context.Background(), "p"); err != nil || value != &primed {
+		t.Fatalf("primed Load = %v, %v", value, err)
+	}
+
+	if _, err := loader.Load(context.Background(), "x"); err == nil {
+		t.Fatal("expected fetch error")
+	}
+
+	// Failures are not cached, so the next Load retries.
+	recorder.fail = false
+	value, err := loader.Load(context.Background(), "x")
+	if err != nil || value == nil || *value != "value-x" {
+		t.Fatalf("retry Load = %v, %v", value, err)
+	}
+	if len(recorder.batches) != 2 {
+		t.Errorf("batches = %v, want 2", recorder.batches)
+	}
+}
+
+func TestLoader_RespectsCallerContext(t *testing.T) {
+	block := make(chan struct{})
+	loader := NewLoader(context.Background(), "test", func(context.Context, []string) (map[string]*string, error) {
+		<-block
+		return nil, nil
+	})
+	defer close(block)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := loader.Load(ctx, "a"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Load error = %v, want context.Canceled", err)
+	}
+}
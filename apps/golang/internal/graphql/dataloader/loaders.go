@@ -0,0 +1,330 @@
+// Package dataloader provides request-scoped, batched loaders for the
+// relations GraphQL resolvers walk most often (harvest -> mandor, block ->
+// division -> estate -> company, employees). Field resolvers that used to run
+// one query per row now share a single IN (...) query per list level.
+package dataloader
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	employeeModels "agrinovagraphql/server/internal/employee/models"
+	"agrinovagraphql/server/internal/graphql/domain/auth"
+	"agrinovagraphql/server/internal/graphql/domain/mandor"
+	"agrinovagraphql/server/internal/graphql/domain/master"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type contextKey struct{}
+
+// EmployeeNIKKey identifies an employee by NIK within a company. An empty
+// CompanyID matches the NIK in any company the caller can see.
+type EmployeeNIKKey struct {
+	CompanyID string
+	NIK       string
+}
+
+// Loaders holds one loader per relation for a single request.
+type Loaders struct {
+	Users          *Loader[string, *auth.User]
+	Companies      *Loader[string, *master.Company]
+	Estates        *Loader[string, *master.Estate]
+	Divisions      *Loader[string, *master.Division]
+	Blocks         *Loader[string, *master.Block]
+	Employees      *Loader[string, *employeeModels.Employee]
+	EmployeesByNIK *Loader[EmployeeNIKKey, *employeeModels.Employee]
+	HarvestRecords *Loader[string, *mandor.HarvestRecord]
+
+	db        *gorm.DB
+	scopeOnce sync.Once
+	scope     accessScope
+	scopeErr  error
+}
+
+// New builds the loaders for one request. Fetches run with ctx so they see
+// the request's RLS context and are cancelled with it.
+func New(ctx context.Context, db *gorm.DB) *Loaders {
+	l := &Loaders{db: db}
+
+	l.Users = NewLoader(ctx, "users", l.fetchUsers)
+	l.Companies = NewLoader(ctx, "companies", l.fetchCompanies)
+	l.Estates = NewLoader(ctx, "estates", l.fetchEstates)
+	l.Divisions = NewLoader(ctx, "divisions", l.fetchDivisions)
+	l.Blocks = NewLoader(ctx, "blocks", l.fetchBlocks)
+	l.Employees = NewLoader(ctx, "employees", l.fetchEmployees)
+	l.EmployeesByNIK = NewLoader(ctx, "employees_by_nik", l.fetchEmployeesByNIK)
+	l.HarvestRecords = NewLoader(ctx, "harvest_records", l.fetchHarvestRecords)
+
+	return l
+}
+
+// Middleware installs fresh loaders on every request. It must run inside the
+// RLS context middleware so the loaders can scope their queries to the
+// caller's companies.
+func Middleware(db *gorm.DB) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// A websocket connection lives for many operations; caching
+			// rows for its whole lifetime would serve stale data, so
+			// subscriptions fall back to per-call loaders (see For).
+			if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := WithLoaders(r.Context(), New(r.Context(), db))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// WithLoaders attaches loaders to ctx.
+func WithLoaders(ctx context.Context, loaders *Loaders) context.Context {
+	return context.WithValue(ctx, contextKey{}, loaders)
+}
+
+// FromContext returns the request's loaders, if the middleware installed them.
+func FromContext(ctx context.Context) (*Loaders, bool) {
+	loaders, ok := ctx.Value(contextKey{}).(*Loaders)
+	return loaders, ok
+}
+
+// For returns the request's loaders or, outside an HTTP request (websocket
+// operations, workers, tests), a fresh set bound to ctx. The fallback still
+// batches concurrent siblings; it just does not share a cache across calls.
+func For(ctx context.Context, db *gorm.DB) *Loaders {
+	if loaders, ok := FromContext(ctx); ok {
+		return loaders
+	}
+	return New(ctx, db)
+}
+
+// PrimeBlock caches a preloaded block and whatever part of its
+// division/estate/company chain came with it.
+func (l *Loaders) PrimeBlock(block *master.Block) {
+	if block == nil || block.ID == "" {
+		return
+	}
+	l.Blocks.Prime(block.ID, block)
+	l.PrimeDivision(block.Division)
+}
+
+// PrimeDivision caches a preloaded division and its estate chain.
+func (l *Loaders) PrimeDivision(division *master.Division) {
+	if division == nil || division.ID == "" {
+		return
+	}
+	l.Divisions.Prime(division.ID, division)
+	l.PrimeEstate(division.Estate)
+}
+
+// PrimeEstate caches a preloaded estate and its company.
+func (l *Loaders) PrimeEstate(estate *master.Estate) {
+	if estate == nil || estate.ID == "" {
+		return
+	}
+	l.Estates.Prime(estate.ID, estate)
+	if estate.Company != nil && estate.Company.ID != "" {
+		l.Companies.Prime(estate.Company.ID, estate.Company)
+	}
+}
+
+func (l *Loaders) fetchUsers(ctx context.Context, ids []string) (map[string]*auth.User, error) {
+	ids = uuidKeys(ids)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := l.db.WithContext(ctx).Where("users.id IN ?", ids)
+	query, err := l.scopeUsers(ctx, query)
+	if err != nil || query == nil {
+		return nil, err
+	}
+
+	var rows []*auth.User
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return indexByID(rows, func(u *auth.User) string { return u.ID }), nil
+}
+
+func (l *Loaders) fetchCompanies(ctx context.Context, ids []string) (map[string]*master.Company, error) {
+	ids = uuidKeys(ids)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := l.db.WithContext(ctx).Where("companies.id IN ?", ids)
+	query, err := l.scopeCompanies(ctx, query, "companies.id")
+	if err != nil || query == nil {
+		return nil, err
+	}
+
+	var rows []*master.Company
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return indexByID(rows, func(c *master.Company) string { return c.ID }), nil
+}
+
+func (l *Loaders) fetchEstates(ctx context.Context, ids []string) (map[string]*master.Estate, error) {
+	ids = uuidKeys(ids)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := l.db.WithContext(ctx).Where("estates.id IN ?", ids)
+	query, err := l.scopeCompanies(ctx, query, "estates.company_id")
+	if err != nil || query == nil {
+		return nil, err
+	}
+
+	var rows []*master.Estate
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return indexByID(rows, func(e *master.Estate) string { return e.ID }), nil
+}
+
+func (l *Loaders) fetchDivisions(ctx context.Context, ids []string) (map[string]*master.Division, error) {
+	ids = uuidKeys(ids)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := l.db.WithContext(ctx).
+		Select("divisions.*").
+		Joins("JOIN estates ON estates.id = divisions.estate_id").
+		Where("divisions.id IN ?", ids)
+	query, err := l.scopeCompanies(ctx, query, "estates.company_id")
+	if err != nil || query == nil {
+		return nil, err
+	}
+
+	var rows []*master.Division
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return indexByID(rows, func(d *master.Division) string { return d.ID }), nil
+}
+
+func (l *Loaders) fetchBlocks(ctx context.Context, ids []string) (map[string]*master.Block, error) {
+	ids = uuidKeys(ids)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := l.db.WithContext(ctx).
+		Select("blocks.*").
+		Joins("JOIN divisions ON divisions.id = blocks.division_id").
+		Joins("JOIN estates ON estates.id = divisions.estate_id").
+		Where("blocks.id IN ?", ids)
+	query, err := l.scopeCompanies(ctx, query, "estates.company_id")
+	if err != nil || query == nil {
+		return nil, err
+	}
+
+	var rows []*master.Block
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return indexByID(rows, func(b *master.Block) string { return b.ID }), nil
+}
+
+func (l *Loaders) fetchEmployees(ctx context.Context, ids []string) (map[string]*employeeModels.Employee, error) {
+	ids = uuidKeys(ids)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := l.db.WithContext(ctx).Where("employees.id IN ?", ids)
+	query, err := l.scopeCompanies(ctx, query, "employees.company_id")
+	if err != nil || query == nil {
+		return nil, err
+	}
+
+	var rows []*employeeModels.Employee
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return indexByID(rows, func(e *employeeModels.Employee) string { return e.ID }), nil
+}
+
+func (l *Loaders) fetchEmployeesByNIK(ctx context.Context, keys []EmployeeNIKKey) (map[EmployeeNIKKey]*employeeModels.Employee, error) {
+	niks := make([]string, 0, len(keys))
+	for _, key := range keys {
+		niks = append(niks, key.NIK)
+	}
+
+	query := l.db.WithContext(ctx).
+		Where("employees.nik IN ?", niks).
+		Order("employees.is_active DESC, employees.created_at")
+	query, err := l.scopeCompanies(ctx, query, "employees.company_id")
+	if err != nil || query == nil {
+		return nil, err
+	}
+
+	var rows []*employeeModels.Employee
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[EmployeeNIKKey]*employeeModels.Employee, len(keys))
+	for _, key := range keys {
+		for _, row := range rows {
+			if row.NIK == key.NIK && (key.CompanyID == "" || row.CompanyID == key.CompanyID) {
+				result[key] = row
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func (l *Loaders) fetchHarvestRecords(ctx context.Context, ids []string) (map[string]*mandor.HarvestRecord, error) {
+	ids = uuidKeys(ids)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := l.db.WithContext(ctx).
+		Select("harvest_records.*").
+		Joins("JOIN blocks ON blocks.id = harvest_records.block_id").
+		Joins("JOIN divisions ON divisions.id = blocks.division_id").
+		Joins("JOIN estates ON estates.id = divisions.estate_id").
+		Where("harvest_records.id IN ?", ids)
+	query, err := l.scopeCompanies(ctx, query, "estates.company_id")
+	if err != nil || query == nil {
+		return nil, err
+	}
+
+	var rows []*mandor.HarvestRecord
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return indexByID(rows, func(h *mandor.HarvestRecord) string { return h.ID }), nil
+}
+
+// uuidKeys drops keys that are not UUIDs; one malformed id would otherwise
+// fail the whole batch with a cast error.
+func uuidKeys(ids []string) []string {
+	valid := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err == nil {
+			valid = append(valid, id)
+		}
+	}
+	return valid
+}
+
+func indexByID[V any](rows []V, id func(V) string) map[string]V {
+	result := make(map[string]V, len(rows))
+	for _, row := range rows {
+		result[id(row)] = row
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package dataloader
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	batchSize = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "agrinova",
+			Subsystem: "graphql_dataloader",
+			Name:      "batch_size",
+			Help:      "Number of keys fetched per dataloader batch",
+			Buckets:   []float64{1, 2, 5, 10, 25, 50, 100, 250, 500},
+		},
+		[]string{"loader"},
+	)
+
+	batchErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "agrinova",
+			Subsystem: "graphql_dataloader",
+			Name:      "batch_errors_total",
+			Help:      "Total number of dataloader batches that failed",
+		},
+		[]string{"loader"},
+	)
+
+	loads = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "agrinova",
+			Subsystem: "graphql_dataloader",
+			Name:      "loads_total",
+			Help:      "Total number of dataloader key lookups, by cache result",
+		},
+		[]string{"loader", "cache"},
+	)
+)
+
+func recordBatch(loader string, size int, err error) {
+	batchSize.WithLabelValues(loader).Observe(float64(size))
+	if err != nil {
+		batchErrors.WithLabelValues(loader).Inc()
+	}
+}
+
+func recordLoad(loader string, hit bool) {
+	cache := "miss"
+	if hit {
+		cache = "hit"
+	}
+	loads.WithLabelValues(loader, cache).Inc()
+}
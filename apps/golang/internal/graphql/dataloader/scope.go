@@ -0,0 +1,109 @@
+package dataloader
+
+import (
+	"context"
+	"fmt"
+
+	"agrinovagraphql/server/internal/middleware"
+
+	"gorm.io/gorm"
+)
+
+// accessScope is the application-level mirror of the PostgreSQL RLS policy:
+// batched queries only return rows from companies the caller is assigned to.
+// Database RLS still applies on top when it is active.
+type accessScope struct {
+	restricted bool
+	userID     string
+	companyIDs []string
+}
+
+// accessScope resolves the caller's companies once per request. Estate and
+// division assignments are folded into their companies so managers and
+// asisten without a direct company assignment still see their own rows.
+func (l *Loaders) accessScope(ctx context.Context) (accessScope, error) {
+	l.scopeOnce.Do(func() {
+		rlsCtx, ok := middleware.GetRLSContextFromRequest(ctx)
+		if !ok || rlsCtx.Role == "SUPER_ADMIN" {
+			// Without an RLS context the parent resolver already applied
+			// its own authorization, and the database policies remain.
+			return
+		}
+
+		scope := accessScope{restricted: true, userID: rlsCtx.UserID.String()}
+		seen := make(map[string]bool)
+		add := func(id string) {
+			if id != "" && !seen[id] {
+				seen[id] = true
+				scope.companyIDs = append(scope.companyIDs, id)
+			}
+		}
+		for _, id := range rlsCtx.CompanyIDs {
+			add(id.String())
+		}
+
+		if len(rlsCtx.EstateIDs) > 0 || len(rlsCtx.DivisionIDs) > 0 {
+			estateIDs := make([]string, 0, len(rlsCtx.EstateIDs))
+			for _, id := range rlsCtx.EstateIDs {
+				estateIDs = append(estateIDs, id.String())
+			}
+			divisionIDs := make([]string, 0, len(rlsCtx.DivisionIDs))
+			for _, id := range rlsCtx.DivisionIDs {
+				divisionIDs = append(divisionIDs, id.String())
+			}
+
+			var companyIDs []string
+			if err := l.db.WithContext(ctx).Raw(`
+				SELECT DISTINCT e.company_id::text
+				FROM estates e
+				LEFT JOIN divisions d ON d.estate_id = e.id
+				WHERE e.id::text IN (?) OR d.id::text IN (?)
+			`, append(estateIDs, ""), append(divisionIDs, "")).Scan(&companyIDs).Error; err != nil {
+				l.scopeErr = fmt.Errorf("failed to resolve dataloader scope: %w", err)
+				return
+			}
+			for _, id := range companyIDs {
+				add(id)
+			}
+		}
+
+		l.scope = scope
+	})
+
+	return l.scope, l.scopeErr
+}
+
+// scopeCompanies restricts query to rows whose company column is visible to
+// the caller. A nil query with a nil error means nothing is visible.
+func (l *Loaders) scopeCompanies(ctx context.Context, query *gorm.DB, column string) (*gorm.DB, error) {
+	scope, err := l.accessScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.restricted {
+		return query, nil
+	}
+	if len(scope.companyIDs) == 0 {
+		return nil, nil
+	}
+	return query.Where(column+" IN ?", scope.companyIDs), nil
+}
+
+// scopeUsers restricts a users query to the caller and users assigned to one
+// of the caller's companies.
+func (l *Loaders) scopeUsers(ctx context.Context, query *gorm.DB) (*gorm.DB, error) {
+	scope, err := l.accessScope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !scope.restricted {
+		return query, nil
+	}
+	if len(scope.companyIDs) == 0 {
+		return query.Where("users.id = ?", scope.userID), nil
+	}
+	return query.Where(
+		"users.id = ? OR EXISTS (SELECT 1 FROM user_company_assignments uca WHERE uca.user_id = users.id AND uca.is_active = true AND uca.company_id IN ?)",
+		scope.userID, scope.companyIDs,
+	), nil
+}
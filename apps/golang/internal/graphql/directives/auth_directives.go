@@ -58,9 +58,15 @@ func requireAuthDirective(ctx context.Context, obj interface{}, next graphql.Res
 		// Check if middleware stored a more specific error code (e.g., ACCESS_EXPIRED)
 		if errCode, ok := ctx.Value("auth_error_code").(string); ok && errCode != "" {
 			code = errCode
-			if code == "ACCESS_EXPIRED" {
+			switch code {
+			case "ACCESS_EXPIRED":
 				message = "access token expired"
 				retryable = true
+			case "IP_NOT_ALLOWED":
+				message = "access from this network is not allowed for your company"
+			case "NETWORK_POLICY_UNAVAILABLE":
+				message = "your company's network policy could not be checked, please try again"
+				retryable = true
 			}
 		}
 		return nil, &gqlerror.Error{
@@ -86,9 +92,15 @@ func hasRoleDirective(ctx context.Context, obj interface{}, next graphql.Resolve
 		retryable := false
 		if errCode, ok := ctx.Value("auth_error_code").(string); ok && errCode != "" {
 			code = errCode
-			if code == "ACCESS_EXPIRED" {
+			switch code {
+			case "ACCESS_EXPIRED":
 				message = "access token expired"
 				retryable = true
+			case "IP_NOT_ALLOWED":
+				message = "access from this network is not allowed for your company"
+			case "NETWORK_POLICY_UNAVAILABLE":
+				message = "your company's network policy could not be checked, please try again"
+				retryable = true
 			}
 		}
 		return nil, &gqlerror.Error{
@@ -501,6 +501,9 @@ type VehicleTax struct {
 	AdminAmount      float64    `json:"adminAmount" gorm:"column:admin_amount;type:numeric(18,2);not null;default:0"`
 	PenaltyAmount    float64    `json:"penaltyAmount" gorm:"column:penalty_amount;type:numeric(18,2);not null;default:0"`
 	TotalAmount      float64    `json:"totalAmount" gorm:"column:total_amount;type:numeric(18,2);not null;default:0"`
+	// PenaltyEstimate is the late penalty last assessed by the reminder job;
+	// PenaltyAmount is what was entered from the tax notice.
+	PenaltyEstimate  float64    `json:"penaltyEstimate" gorm:"column:penalty_estimate;type:numeric(18,2);not null;default:0"`
 	PaymentDate      *time.Time `json:"paymentDate,omitempty" gorm:"column:payment_date;type:date"`
 	PaymentMethod    *string    `json:"paymentMethod,omitempty" gorm:"column:payment_method;type:varchar(50)"`
 	PaymentReference *string    `json:"paymentReference,omitempty" gorm:"column:payment_reference;type:varchar(100)"`
@@ -0,0 +1,113 @@
+package master
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// Reminder stages recorded in vehicle_tax_notifications.reminder_type.
+const (
+	VehicleTaxReminderH30     = "H-30"
+	VehicleTaxReminderH7      = "H-7"
+	VehicleTaxReminderH1      = "H-1"
+	VehicleTaxReminderOverdue = "OVERDUE"
+)
+
+// PKB late penalty: 2% of the PKB amount for every started month past the
+// due date, capped at 24 months.
+const (
+	VehicleTaxPenaltyRatePerMonth = 0.02
+	VehicleTaxPenaltyMaxMonths    = 24
+)
+
+// IsOutstanding reports whether the tax still has to be paid.
+func (t *VehicleTax) IsOutstanding() bool {
+	status := strings.ToUpper(strings.TrimSpace(t.TaxStatus))
+	return t.PaymentDate == nil && (status == "OPEN" || status == "OVERDUE")
+}
+
+// DaysUntilDue returns the calendar days from asOf to the due date; negative
+// once the tax is overdue.
+func (t *VehicleTax) DaysUntilDue(asOf time.Time) int {
+	return calendarDaysBetween(asOf, t.DueDate)
+}
+
+// DaysOverdue returns how many days an outstanding tax is past its due date.
+func (t *VehicleTax) DaysOverdue(asOf time.Time) int {
+	if !t.IsOutstanding() {
+		return 0
+	}
+	if days := -t.DaysUntilDue(asOf); days > 0 {
+		return days
+	}
+	return 0
+}
+
+// BlocksGateEntry reports whether the vehicle must be refused at the gate
+// because this tax is overdue.
+func (t *VehicleTax) BlocksGateEntry(asOf time.Time) bool {
+	return t.DaysOverdue(asOf) > 0
+}
+
+// ReminderStage returns the reminder stage the tax is in on asOf, or "" when
+// no reminder is due.
+func (t *VehicleTax) ReminderStage(asOf time.Time) string {
+	if !t.IsOutstanding() {
+		return ""
+	}
+
+	days := t.DaysUntilDue(asOf)
+	switch {
+	case days < 0:
+		return VehicleTaxReminderOverdue
+	case days <= 1:
+		return VehicleTaxReminderH1
+	case days <= 7:
+		return VehicleTaxReminderH7
+	case days <= 30:
+		return VehicleTaxReminderH30
+	default:
+		return ""
+	}
+}
+
+// EstimatedPenalty returns the PKB late penalty owed on asOf.
+func (t *VehicleTax) EstimatedPenalty(asOf time.Time) float64 {
+	if t.DaysOverdue(asOf) == 0 || t.PKBAmount <= 0 {
+		return 0
+	}
+
+	months := vehicleTaxPenaltyMonths(t.DueDate, asOf)
+	penalty := t.PKBAmount * VehicleTaxPenaltyRatePerMonth * float64(months)
+	return math.Round(penalty*100) / 100
+}
+
+// vehicleTaxPenaltyMonths counts started months between the due date and
+// asOf; a partial month counts as a full one.
+func vehicleTaxPenaltyMonths(dueDate, asOf time.Time) int {
+	due := calendarDate(dueDate)
+	now := calendarDate(asOf)
+
+	months := (now.Year()-due.Year())*12 + int(now.Month()-due.Month())
+	if now.Day() > due.Day() {
+		months++
+	}
+	if months < 1 {
+		months = 1
+	}
+	if months > VehicleTaxPenaltyMaxMonths {
+		months = VehicleTaxPenaltyMaxMonths
+	}
+	return months
+}
+
+// calendarDaysBetween compares dates only, so DATE columns read back as UTC
+// midnight line up with a local asOf.
+func calendarDaysBetween(from, to time.Time) int {
+	return int(calendarDate(to).Sub(calendarDate(from)).Hours() / 24)
+}
+
+func calendarDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,55 @@
+package master
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVehicleTaxReminderStage(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	tax := &VehicleTax{
+		DueDate:   time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC),
+		TaxStatus: "OPEN",
+	}
+
+	cases := map[string]string{
+		"2026-04-15": "",
+		"2026-05-01": VehicleTaxReminderH30,
+		"2026-05-24": VehicleTaxReminderH7,
+		"2026-05-30": VehicleTaxReminderH1,
+		"2026-05-31": VehicleTaxReminderH1,
+		"2026-06-01": VehicleTaxReminderOverdue,
+	}
+	for day, expected := range cases {
+		asOf, err := time.ParseInLocation("2006-01-02", day, jakarta)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, tax.ReminderStage(asOf.Add(23*time.Hour)), day)
+	}
+
+	tax.TaxStatus = "PAID"
+	assert.Equal(t, "", tax.ReminderStage(time.Date(2026, 6, 10, 0, 0, 0, 0, jakarta)))
+}
+
+func TestVehicleTaxEstimatedPenalty(t *testing.T) {
+	tax := &VehicleTax{
+		DueDate:   time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC),
+		PKBAmount: 1_000_000,
+		TaxStatus: "OVERDUE",
+	}
+
+	assert.Equal(t, 0.0, tax.EstimatedPenalty(time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 20_000.0, tax.EstimatedPenalty(time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 20_000.0, tax.EstimatedPenalty(time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 40_000.0, tax.EstimatedPenalty(time.Date(2026, 2, 16, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 480_000.0, tax.EstimatedPenalty(time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	assert.True(t, tax.BlocksGateEntry(time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 1, tax.DaysOverdue(time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)))
+
+	paidAt := time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC)
+	tax.PaymentDate = &paidAt
+	assert.False(t, tax.BlocksGateEntry(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 0.0, tax.EstimatedPenalty(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)))
+}
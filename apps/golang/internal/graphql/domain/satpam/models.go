@@ -64,6 +64,9 @@ type VehicleInsideInfo struct {
 	EntryTime           time.Time      `json:"entryTime"`
 	Duration            int32          `json:"duration"`
 	IsOverstay          bool           `json:"isOverstay"`
+	MaxStayMinutes      *int32         `json:"maxStayMinutes,omitempty"`
+	OverstayDetectedAt  *time.Time     `json:"overstayDetectedAt,omitempty"`
+	OverstayResolvedAt  *time.Time     `json:"overstayResolvedAt,omitempty"`
 	Destination         *string        `json:"destination,omitempty"`
 	QRCode              *string        `json:"qrCode,omitempty"`
 	LoadType            *string        `json:"loadType,omitempty"`
@@ -246,6 +249,7 @@ type CreateGuestRegistrationInput struct {
 	DeliveryOrderNumber *string             `json:"deliveryOrderNumber,omitempty"`
 	SecondCargo         *string             `json:"secondCargo,omitempty"`
 	RegistrationSource  *RegistrationSource `json:"registrationSource,omitempty"`
+	WatchlistOverrideID *string             `json:"watchlistOverrideId,omitempty"`
 }
 
 // Validate checks required fields and enforces length/range constraints.
@@ -322,11 +326,38 @@ func (i *SatpamSyncInput) Validate() error {
 
 // GuestRegistrationResult for registration result.
 type GuestRegistrationResult struct {
-	Success  bool            `json:"success"`
-	Message  string          `json:"message"`
-	GuestLog *SatpamGuestLog `json:"guestLog,omitempty"`
-	QRToken  *SatpamQRToken  `json:"qrToken,omitempty"`
-	Errors   []string        `json:"errors,omitempty"`
+	Success        bool                `json:"success"`
+	Message        string              `json:"message"`
+	GuestLog       *SatpamGuestLog     `json:"guestLog,omitempty"`
+	QRToken        *SatpamQRToken      `json:"qrToken,omitempty"`
+	WatchlistAlert *GateWatchlistAlert `json:"watchlistAlert,omitempty"`
+	Errors         []string            `json:"errors,omitempty"`
+}
+
+// GateWatchlistAlert for a guest that matched the company watchlist.
+type GateWatchlistAlert struct {
+	HitID            string                 `json:"hitId"`
+	CompanyID        string                 `json:"companyId"`
+	Source           GateWatchlistHitSource `json:"source"`
+	Status           GateWatchlistHitStatus `json:"status"`
+	Severity         GateWatchlistSeverity  `json:"severity"`
+	MatchType        GateWatchlistMatchType `json:"matchType"`
+	MatchedValue     string                 `json:"matchedValue"`
+	Reason           string                 `json:"reason"`
+	VehiclePlate     string                 `json:"vehiclePlate"`
+	DriverName       string                 `json:"driverName"`
+	IDCardNumber     *string                `json:"idCardNumber,omitempty"`
+	GuestLogID       *string                `json:"guestLogId,omitempty"`
+	DeviceID         string                 `json:"deviceId"`
+	Blocked          bool                   `json:"blocked"`
+	RequiresOverride bool                   `json:"requiresOverride"`
+	ReviewedBy       *string                `json:"reviewedBy,omitempty"`
+	ReviewedAt       *time.Time             `json:"reviewedAt,omitempty"`
+	ReviewNotes      *string                `json:"reviewNotes,omitempty"`
+	CreatedAt        time.Time              `json:"createdAt"`
+	// IsNew is set when the hit was recorded by this request; reused hits of
+	// the same guest log are not alerted again. Not exposed in the schema.
+	IsNew bool `json:"-"`
 }
 
 // ============================================================================
@@ -348,12 +379,13 @@ type SatpamQRToken struct {
 
 // QRValidationResult for QR validation.
 type QRValidationResult struct {
-	IsValid           bool            `json:"isValid"`
-	Message           string          `json:"message"`
-	TokenInfo         *SatpamQRToken  `json:"tokenInfo,omitempty"`
-	GuestLog          *SatpamGuestLog `json:"guestLog,omitempty"`
-	AllowedOperations []GateIntent    `json:"allowedOperations"`
-	Errors            []string        `json:"errors,omitempty"`
+	IsValid           bool                `json:"isValid"`
+	Message           string              `json:"message"`
+	TokenInfo         *SatpamQRToken      `json:"tokenInfo,omitempty"`
+	GuestLog          *SatpamGuestLog     `json:"guestLog,omitempty"`
+	AllowedOperations []GateIntent        `json:"allowedOperations"`
+	WatchlistAlert    *GateWatchlistAlert `json:"watchlistAlert,omitempty"`
+	Errors            []string            `json:"errors,omitempty"`
 }
 
 // ============================================================================
@@ -377,7 +409,11 @@ type ProcessExitResult struct {
 	Message     string          `json:"message"`
 	GuestLog    *SatpamGuestLog `json:"guestLog,omitempty"`
 	WasOverstay bool            `json:"wasOverstay"`
-	Errors      []string        `json:"errors,omitempty"`
+	// OverstayResolvedAt is set when this exit closed a detected overstay.
+	OverstayResolvedAt *time.Time `json:"overstayResolvedAt,omitempty"`
+	Errors             []string   `json:"errors,omitempty"`
+	// ResolvedOverstayLog is the entry whose overstay this exit closed.
+	ResolvedOverstayLog *SatpamGuestLog `json:"-"`
 }
 
 // ============================================================================
@@ -441,6 +477,7 @@ type SatpamSyncResult struct {
 	RecordsFailed     int32                   `json:"recordsFailed"`
 	ConflictsDetected int32                   `json:"conflictsDetected"`
 	Results           []*SatpamSyncItemResult `json:"results"`
+	WatchlistAlerts   []*GateWatchlistAlert   `json:"watchlistAlerts"`
 	ServerTimestamp   time.Time               `json:"serverTimestamp"`
 	Message           string                  `json:"message"`
 }
@@ -1072,3 +1109,237 @@ func (e PhotoType) MarshalJSON() ([]byte, error) {
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
+
+// GateWatchlistMatchType enum.
+type GateWatchlistMatchType string
+
+const (
+	GateWatchlistMatchTypePlate  GateWatchlistMatchType = "PLATE"
+	GateWatchlistMatchTypeIDCard GateWatchlistMatchType = "ID_CARD"
+	GateWatchlistMatchTypeName   GateWatchlistMatchType = "NAME"
+)
+
+var AllGateWatchlistMatchType = []GateWatchlistMatchType{
+	GateWatchlistMatchTypePlate,
+	GateWatchlistMatchTypeIDCard,
+	GateWatchlistMatchTypeName,
+}
+
+func (e GateWatchlistMatchType) IsValid() bool {
+	switch e {
+	case GateWatchlistMatchTypePlate, GateWatchlistMatchTypeIDCard, GateWatchlistMatchTypeName:
+		return true
+	}
+	return false
+}
+
+func (e GateWatchlistMatchType) String() string {
+	return string(e)
+}
+
+func (e *GateWatchlistMatchType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+	*e = GateWatchlistMatchType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GateWatchlistMatchType", str)
+	}
+	return nil
+}
+
+func (e GateWatchlistMatchType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *GateWatchlistMatchType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e GateWatchlistMatchType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// GateWatchlistSeverity enum.
+type GateWatchlistSeverity string
+
+const (
+	GateWatchlistSeverityWarn            GateWatchlistSeverity = "WARN"
+	GateWatchlistSeverityRequireOverride GateWatchlistSeverity = "REQUIRE_OVERRIDE"
+	GateWatchlistSeverityBlock           GateWatchlistSeverity = "BLOCK"
+)
+
+var AllGateWatchlistSeverity = []GateWatchlistSeverity{
+	GateWatchlistSeverityWarn,
+	GateWatchlistSeverityRequireOverride,
+	GateWatchlistSeverityBlock,
+}
+
+func (e GateWatchlistSeverity) IsValid() bool {
+	switch e {
+	case GateWatchlistSeverityWarn, GateWatchlistSeverityRequireOverride, GateWatchlistSeverityBlock:
+		return true
+	}
+	return false
+}
+
+func (e GateWatchlistSeverity) String() string {
+	return string(e)
+}
+
+func (e *GateWatchlistSeverity) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+	*e = GateWatchlistSeverity(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GateWatchlistSeverity", str)
+	}
+	return nil
+}
+
+func (e GateWatchlistSeverity) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *GateWatchlistSeverity) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e GateWatchlistSeverity) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// GateWatchlistHitSource enum.
+type GateWatchlistHitSource string
+
+const (
+	GateWatchlistHitSourceRegistration GateWatchlistHitSource = "REGISTRATION"
+	GateWatchlistHitSourceQRValidation GateWatchlistHitSource = "QR_VALIDATION"
+	GateWatchlistHitSourceSync         GateWatchlistHitSource = "SYNC"
+)
+
+var AllGateWatchlistHitSource = []GateWatchlistHitSource{
+	GateWatchlistHitSourceRegistration,
+	GateWatchlistHitSourceQRValidation,
+	GateWatchlistHitSourceSync,
+}
+
+func (e GateWatchlistHitSource) IsValid() bool {
+	switch e {
+	case GateWatchlistHitSourceRegistration, GateWatchlistHitSourceQRValidation, GateWatchlistHitSourceSync:
+		return true
+	}
+	return false
+}
+
+func (e GateWatchlistHitSource) String() string {
+	return string(e)
+}
+
+func (e *GateWatchlistHitSource) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+	*e = GateWatchlistHitSource(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GateWatchlistHitSource", str)
+	}
+	return nil
+}
+
+func (e GateWatchlistHitSource) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *GateWatchlistHitSource) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e GateWatchlistHitSource) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// GateWatchlistHitStatus enum.
+type GateWatchlistHitStatus string
+
+const (
+	GateWatchlistHitStatusFlagged          GateWatchlistHitStatus = "FLAGGED"
+	GateWatchlistHitStatusBlocked          GateWatchlistHitStatus = "BLOCKED"
+	GateWatchlistHitStatusPendingOverride  GateWatchlistHitStatus = "PENDING_OVERRIDE"
+	GateWatchlistHitStatusOverrideApproved GateWatchlistHitStatus = "OVERRIDE_APPROVED"
+	GateWatchlistHitStatusOverrideRejected GateWatchlistHitStatus = "OVERRIDE_REJECTED"
+	GateWatchlistHitStatusOverrideUsed     GateWatchlistHitStatus = "OVERRIDE_USED"
+)
+
+var AllGateWatchlistHitStatus = []GateWatchlistHitStatus{
+	GateWatchlistHitStatusFlagged,
+	GateWatchlistHitStatusBlocked,
+	GateWatchlistHitStatusPendingOverride,
+	GateWatchlistHitStatusOverrideApproved,
+	GateWatchlistHitStatusOverrideRejected,
+	GateWatchlistHitStatusOverrideUsed,
+}
+
+func (e GateWatchlistHitStatus) IsValid() bool {
+	switch e {
+	case GateWatchlistHitStatusFlagged, GateWatchlistHitStatusBlocked, GateWatchlistHitStatusPendingOverride, GateWatchlistHitStatusOverrideApproved, GateWatchlistHitStatusOverrideRejected, GateWatchlistHitStatusOverrideUsed:
+		return true
+	}
+	return false
+}
+
+func (e GateWatchlistHitStatus) String() string {
+	return string(e)
+}
+
+func (e *GateWatchlistHitStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+	*e = GateWatchlistHitStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GateWatchlistHitStatus", str)
+	}
+	return nil
+}
+
+func (e GateWatchlistHitStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *GateWatchlistHitStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e GateWatchlistHitStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
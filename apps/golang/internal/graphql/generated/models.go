@@ -147,6 +147,16 @@ type AlertThresholds struct {
 	PendingApprovalHours int32 `json:"pendingApprovalHours"`
 }
 
+// AlertThresholdsInput for alert configuration.
+type AlertThresholdsInput struct {
+	// Production below target percentage
+	ProductionBelowTarget *float64 `json:"productionBelowTarget,omitempty"`
+	// Quality below threshold
+	QualityBelowThreshold *float64 `json:"qualityBelowThreshold,omitempty"`
+	// Escalate harvest records pending longer than this many hours (0 disables)
+	PendingApprovalHours *int32 `json:"pendingApprovalHours,omitempty"`
+}
+
 // ApiSettings for API configuration.
 type APISettings struct {
 	// Version
@@ -275,6 +285,17 @@ type AreaStats struct {
 	CrossCompanyMetrics *CrossCompanyMetrics `json:"crossCompanyMetrics,omitempty"`
 }
 
+// One ENTRY→EXIT pair. A missing side is estimated from the shift bounds.
+type AttendanceSession struct {
+	EntryAt       *time.Time `json:"entryAt,omitempty"`
+	ExitAt        *time.Time `json:"exitAt,omitempty"`
+	EntryGate     *string    `json:"entryGate,omitempty"`
+	ExitGate      *string    `json:"exitGate,omitempty"`
+	WorkedMinutes int32      `json:"workedMinutes"`
+	// True when entryAt or exitAt is missing and the shift bound was used
+	Estimated bool `json:"estimated"`
+}
+
 // BJR (Brondolan Janjang Rasio) calculation result.
 type BJRCalculation struct {
 	ID             string     `json:"id"`
@@ -480,8 +501,6 @@ type CompanyAdminStats struct {
 	TodayProduction float64 `json:"todayProduction"`
 	// Monthly production
 	MonthlyProduction float64 `json:"monthlyProduction"`
-	// System uptime
-	SystemUptime float64 `json:"systemUptime"`
 }
 
 // CompanyDetailAdmin for admin company view.
@@ -891,6 +910,16 @@ type CreateCompanyUserInput struct {
 	SendWelcomeEmail *bool `json:"sendWelcomeEmail,omitempty"`
 }
 
+type CreateGateWatchlistEntryInput struct {
+	// Required for super admins
+	CompanyID *string                       `json:"companyId,omitempty"`
+	MatchType satpam.GateWatchlistMatchType `json:"matchType"`
+	Value     string                        `json:"value"`
+	Reason    string                        `json:"reason"`
+	Severity  satpam.GateWatchlistSeverity  `json:"severity"`
+	ExpiresAt *time.Time                    `json:"expiresAt,omitempty"`
+}
+
 type CreateGradingRecordInput struct {
 	HarvestRecordID      string    `json:"harvestRecordId"`
 	QualityScore         int32     `json:"qualityScore"`
@@ -973,6 +1002,12 @@ type CreateNotificationTemplateInput struct {
 	ActionLabel *string `json:"actionLabel,omitempty"`
 	// Default metadata
 	DefaultMetadata *string `json:"defaultMetadata,omitempty"`
+	// Email subject template
+	EmailSubjectTemplate *string `json:"emailSubjectTemplate,omitempty"`
+	// Plain-text email body template
+	EmailTextTemplate *string `json:"emailTextTemplate,omitempty"`
+	// HTML email body template
+	EmailHTMLTemplate *string `json:"emailHtmlTemplate,omitempty"`
 }
 
 type CreatePKSRecordInput struct {
@@ -1004,6 +1039,20 @@ type CreatePerawatanRecordInput struct {
 	Catatan            *string        `json:"catatan,omitempty"`
 }
 
+// CreateScheduledJobInput adds a global or company-specific schedule for a registered job.
+type CreateScheduledJobInput struct {
+	// Registered handler name
+	Name string `json:"name"`
+	// Company override; omit for a global schedule
+	CompanyID *string `json:"companyId,omitempty"`
+	// Cron expression
+	CronExpression string `json:"cronExpression"`
+	// IANA timezone; defaults to the company timezone
+	Timezone *string `json:"timezone,omitempty"`
+	// Description
+	Description *string `json:"description,omitempty"`
+}
+
 type CreateTariffRuleOverrideInput struct {
 	RuleID        string             `json:"ruleId"`
 	OverrideType  TariffOverrideType `json:"overrideType"`
@@ -1049,6 +1098,18 @@ type CrossCompanyMetrics struct {
 	TotalProduction float64 `json:"totalProduction"`
 }
 
+// Daily attendance report of a company.
+type DailyAttendanceReport struct {
+	CompanyID              string                       `json:"companyId"`
+	Date                   string                       `json:"date"`
+	ShiftStart             string                       `json:"shiftStart"`
+	ShiftEnd               string                       `json:"shiftEnd"`
+	Timezone               string                       `json:"timezone"`
+	Divisions              []*DivisionAttendanceSummary `json:"divisions"`
+	Employees              []*EmployeeAttendanceDay     `json:"employees"`
+	HarvestersWithoutEntry []*HarvestGateDiscrepancy    `json:"harvestersWithoutEntry"`
+}
+
 // DatabaseHealthInfo shows database connection and RLS policy status.
 type DatabaseHealthInfo struct {
 	// Whether database connection is healthy
@@ -1084,6 +1145,19 @@ type DeviceRenewInput struct {
 	DeviceFingerprint *string `json:"deviceFingerprint,omitempty"`
 }
 
+// Attendance totals of a division. Monthly reports count employee-days.
+type DivisionAttendanceSummary struct {
+	DivisionID      *string `json:"divisionId,omitempty"`
+	DivisionName    string  `json:"divisionName"`
+	EmployeeCount   int32   `json:"employeeCount"`
+	PresentCount    int32   `json:"presentCount"`
+	IncompleteCount int32   `json:"incompleteCount"`
+	AbsentCount     int32   `json:"absentCount"`
+	LateCount       int32   `json:"lateCount"`
+	WorkedMinutes   int32   `json:"workedMinutes"`
+	OvertimeMinutes int32   `json:"overtimeMinutes"`
+}
+
 // EmailSettings for email configuration.
 type EmailSettings struct {
 	// SMTP enabled
@@ -1112,6 +1186,48 @@ type EmailSettingsInput struct {
 	FromName *string `json:"fromName,omitempty"`
 }
 
+// Attendance of one employee on one work date.
+type EmployeeAttendanceDay struct {
+	Nik string `json:"nik"`
+	// Null for NIKs scanned at the gate but missing from the employee roster
+	EmployeeID   *string `json:"employeeId,omitempty"`
+	EmployeeName string  `json:"employeeName"`
+	DivisionID   *string `json:"divisionId,omitempty"`
+	DivisionName string  `json:"divisionName"`
+	// Work date (YYYY-MM-DD); overnight shifts belong to the date they started
+	Date          string           `json:"date"`
+	Status        AttendanceStatus `json:"status"`
+	FirstEntryAt  *time.Time       `json:"firstEntryAt,omitempty"`
+	LastExitAt    *time.Time       `json:"lastExitAt,omitempty"`
+	WorkedMinutes int32            `json:"workedMinutes"`
+	// Part of workedMinutes estimated from the shift bounds
+	EstimatedMinutes  int32                `json:"estimatedMinutes"`
+	ScheduledMinutes  int32                `json:"scheduledMinutes"`
+	LateMinutes       int32                `json:"lateMinutes"`
+	EarlyLeaveMinutes int32                `json:"earlyLeaveMinutes"`
+	OvertimeMinutes   int32                `json:"overtimeMinutes"`
+	MissingEntry      bool                 `json:"missingEntry"`
+	MissingExit       bool                 `json:"missingExit"`
+	Sessions          []*AttendanceSession `json:"sessions"`
+}
+
+// Attendance totals of one employee over a month.
+type EmployeeAttendanceSummary struct {
+	Nik          string  `json:"nik"`
+	EmployeeID   *string `json:"employeeId,omitempty"`
+	EmployeeName string  `json:"employeeName"`
+	DivisionID   *string `json:"divisionId,omitempty"`
+	DivisionName string  `json:"divisionName"`
+	// Monday–Saturday up to today
+	WorkingDays     int32 `json:"workingDays"`
+	PresentDays     int32 `json:"presentDays"`
+	IncompleteDays  int32 `json:"incompleteDays"`
+	AbsentDays      int32 `json:"absentDays"`
+	LateDays        int32 `json:"lateDays"`
+	WorkedMinutes   int32 `json:"workedMinutes"`
+	OvertimeMinutes int32 `json:"overtimeMinutes"`
+}
+
 // EmployeeLogSyncInput for employee access log sync.
 type EmployeeLogSyncInput struct {
 	// Device ID
@@ -1254,6 +1370,16 @@ type GradingApprovalInput struct {
 	RejectionReason *string `json:"rejectionReason,omitempty"`
 }
 
+// Harvester with harvest records on a work date but no gate ENTRY scan.
+type HarvestGateDiscrepancy struct {
+	Date             string   `json:"date"`
+	Nik              string   `json:"nik"`
+	EmployeeID       *string  `json:"employeeId,omitempty"`
+	EmployeeName     string   `json:"employeeName"`
+	DivisionID       *string  `json:"divisionId,omitempty"`
+	HarvestRecordIds []string `json:"harvestRecordIds"`
+}
+
 // Paginated response for harvest records.
 type HarvestRecordsPaginatedResponse struct {
 	// Harvest records for current page
@@ -1434,9 +1560,74 @@ type MandorPhotoSyncRecord struct {
 	TakenAt time.Time `json:"takenAt"`
 }
 
+// MasterDataImportJob tracks a spreadsheet import uploaded through /master-data/import.
+type MasterDataImportJob struct {
+	ID            string                      `json:"id"`
+	CompanyID     string                      `json:"companyId"`
+	ImportType    MasterDataImportType        `json:"importType"`
+	Status        MasterDataImportStatus      `json:"status"`
+	FileName      string                      `json:"fileName"`
+	TotalRows     int32                       `json:"totalRows"`
+	ProcessedRows int32                       `json:"processedRows"`
+	CreatedCount  int32                       `json:"createdCount"`
+	UpdatedCount  int32                       `json:"updatedCount"`
+	FailedRows    int32                       `json:"failedRows"`
+	Errors        []*MasterDataImportRowError `json:"errors"`
+	Message       *string                     `json:"message,omitempty"`
+	CreatedBy     string                      `json:"createdBy"`
+	StartedAt     *time.Time                  `json:"startedAt,omitempty"`
+	FinishedAt    *time.Time                  `json:"finishedAt,omitempty"`
+	CreatedAt     time.Time                   `json:"createdAt"`
+	UpdatedAt     time.Time                   `json:"updatedAt"`
+}
+
+// MasterDataImportRowError describes why one spreadsheet row was not imported.
+type MasterDataImportRowError struct {
+	Row     int32   `json:"row"`
+	Entity  *string `json:"entity,omitempty"`
+	Field   *string `json:"field,omitempty"`
+	Message string  `json:"message"`
+}
+
+// Monthly attendance report of a company.
+type MonthlyAttendanceReport struct {
+	CompanyID              string                       `json:"companyId"`
+	Year                   int32                        `json:"year"`
+	Month                  int32                        `json:"month"`
+	DateFrom               string                       `json:"dateFrom"`
+	DateTo                 string                       `json:"dateTo"`
+	WorkingDays            int32                        `json:"workingDays"`
+	ShiftStart             string                       `json:"shiftStart"`
+	ShiftEnd               string                       `json:"shiftEnd"`
+	Timezone               string                       `json:"timezone"`
+	Divisions              []*DivisionAttendanceSummary `json:"divisions"`
+	Employees              []*EmployeeAttendanceSummary `json:"employees"`
+	HarvestersWithoutEntry []*HarvestGateDiscrepancy    `json:"harvestersWithoutEntry"`
+}
+
 type Mutation struct {
 }
 
+// NotificationEscalationStep notifies more recipients when every notification of
+// the previous step is still unread afterMinutes after it was sent. A step whose
+// scope holds no recipients is skipped and the next step runs in its place.
+type NotificationEscalationStep struct {
+	AfterMinutes   int32    `json:"afterMinutes"`
+	RecipientRoles []string `json:"recipientRoles"`
+	Scope          string   `json:"scope"`
+	Channels       []string `json:"channels"`
+	// Priority of escalated notifications; defaults to the original priority
+	Priority *models.NotificationPriority `json:"priority,omitempty"`
+}
+
+type NotificationEscalationStepInput struct {
+	AfterMinutes   int32                        `json:"afterMinutes"`
+	RecipientRoles []string                     `json:"recipientRoles"`
+	Scope          string                       `json:"scope"`
+	Channels       []string                     `json:"channels"`
+	Priority       *models.NotificationPriority `json:"priority,omitempty"`
+}
+
 // NotificationFilterInput for filtering notifications
 type NotificationFilterInput struct {
 	// Filter by notification types
@@ -1457,6 +1648,43 @@ type NotificationFilterInput struct {
 	UnreadOnly *bool `json:"unreadOnly,omitempty"`
 }
 
+// NotificationRoutingRule maps an event type and priority to recipients and
+// channels. Company rules replace global rules for the same event type.
+type NotificationRoutingRule struct {
+	ID string `json:"id"`
+	// Owning company; null for global defaults
+	CompanyID *string `json:"companyId,omitempty"`
+	Name      string  `json:"name"`
+	// Event type the rule applies to
+	EventType models.NotificationType `json:"eventType"`
+	// Lowest event priority the rule applies to
+	MinPriority models.NotificationPriority `json:"minPriority"`
+	// Roles that receive the notification
+	RecipientRoles []string `json:"recipientRoles"`
+	// Recipient scope: HIERARCHY (supervisors of the actor, or the mandor and their supervisors for approval decisions), ESTATE or COMPANY
+	Scope string `json:"scope"`
+	// Delivery channels: WEB, MOBILE, EMAIL
+	Channels []string `json:"channels"`
+	// Escalation chain applied while the notifications stay unread
+	EscalationSteps []*NotificationEscalationStep `json:"escalationSteps"`
+	IsActive        bool                          `json:"isActive"`
+	CreatedAt       time.Time                     `json:"createdAt"`
+	UpdatedAt       time.Time                     `json:"updatedAt"`
+}
+
+type NotificationRoutingRuleInput struct {
+	// Owning company; company admins always use their own company
+	CompanyID       *string                            `json:"companyId,omitempty"`
+	Name            string                             `json:"name"`
+	EventType       models.NotificationType            `json:"eventType"`
+	MinPriority     *models.NotificationPriority       `json:"minPriority,omitempty"`
+	RecipientRoles  []string                           `json:"recipientRoles"`
+	Scope           string                             `json:"scope"`
+	Channels        []string                           `json:"channels"`
+	EscalationSteps []*NotificationEscalationStepInput `json:"escalationSteps,omitempty"`
+	IsActive        *bool                              `json:"isActive,omitempty"`
+}
+
 // NotificationSettings for notifications.
 type NotificationSettings struct {
 	// Email notifications enabled
@@ -1481,6 +1709,8 @@ type NotificationSettingsInput struct {
 	SmsEnabled *bool `json:"smsEnabled,omitempty"`
 	// Daily report enabled
 	DailyReportEnabled *bool `json:"dailyReportEnabled,omitempty"`
+	// Alert thresholds
+	AlertThresholds *AlertThresholdsInput `json:"alertThresholds,omitempty"`
 }
 
 // NotificationTypeCount represents count for a specific notification type
@@ -1513,6 +1743,8 @@ type OperationalSettingsInput struct {
 	DefaultShiftStart *string `json:"defaultShiftStart,omitempty"`
 	// Default shift end
 	DefaultShiftEnd *string `json:"defaultShiftEnd,omitempty"`
+	// Auto approve harvest records at or below this weight (kg). 0 disables.
+	AutoApproveThreshold *float64 `json:"autoApproveThreshold,omitempty"`
 	// Require GPS
 	RequireGpsForHarvest *bool `json:"requireGpsForHarvest,omitempty"`
 	// Require photo
@@ -1668,6 +1900,12 @@ type RegisterFCMTokenInput struct {
 	DeviceID string `json:"deviceId"`
 }
 
+type ReviewGateWatchlistOverrideInput struct {
+	HitID   string  `json:"hitId"`
+	Approve bool    `json:"approve"`
+	Notes   *string `json:"notes,omitempty"`
+}
+
 // RevokeJWTTokenResponse describes revoke operation result.
 type RevokeJWTTokenResponse struct {
 	Success bool   `json:"success"`
@@ -1879,6 +2117,80 @@ type SatpamQRTokenSyncRecord struct {
 	LocalVersion int32 `json:"localVersion"`
 }
 
+// ScheduledJob is a cron definition run by the scheduler leader.
+type ScheduledJob struct {
+	// Job ID
+	ID string `json:"id"`
+	// Registered handler name
+	Name string `json:"name"`
+	// Description
+	Description *string `json:"description,omitempty"`
+	// Five-field cron expression or descriptor such as @daily
+	CronExpression string `json:"cronExpression"`
+	// Timezone configured on the job, if any
+	Timezone *string `json:"timezone,omitempty"`
+	// Timezone the expression is evaluated in (job, company settings or default); COMPANY when a global job follows each company's timezone
+	EffectiveTimezone string `json:"effectiveTimezone"`
+	// Company override; null for the global schedule
+	CompanyID *string `json:"companyId,omitempty"`
+	// Paused
+	IsPaused bool `json:"isPaused"`
+	// Next scheduled run
+	NextRunAt *time.Time `json:"nextRunAt,omitempty"`
+	// Last run start
+	LastRunAt *time.Time `json:"lastRunAt,omitempty"`
+	// Status of the last run
+	LastStatus *ScheduledJobRunStatus `json:"lastStatus,omitempty"`
+	// Pending manual trigger
+	RunRequestedAt *time.Time `json:"runRequestedAt,omitempty"`
+	// Created at
+	CreatedAt time.Time `json:"createdAt"`
+	// Updated at
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ScheduledJobRun is one execution of a scheduled job.
+type ScheduledJobRun struct {
+	// Run ID
+	ID string `json:"id"`
+	// Job ID
+	JobID string `json:"jobId"`
+	// Job name
+	JobName string `json:"jobName"`
+	// Company override the run belonged to
+	CompanyID *string `json:"companyId,omitempty"`
+	// Trigger
+	Trigger ScheduledJobTrigger `json:"trigger"`
+	// User who triggered a manual run
+	TriggeredBy *string `json:"triggeredBy,omitempty"`
+	// Status
+	Status ScheduledJobRunStatus `json:"status"`
+	// Instance that ran the job
+	InstanceID string `json:"instanceId"`
+	// Occurrence the run was for
+	ScheduledFor *time.Time `json:"scheduledFor,omitempty"`
+	// Started at
+	StartedAt time.Time `json:"startedAt"`
+	// Finished at
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	// Duration in milliseconds
+	DurationMs *int32 `json:"durationMs,omitempty"`
+	// Summary returned by the job
+	Message *string `json:"message,omitempty"`
+	// Error
+	Error *string `json:"error,omitempty"`
+}
+
+// SchedulerStatus describes the instance answering the query.
+type SchedulerStatus struct {
+	// Instance ID
+	InstanceID string `json:"instanceId"`
+	// Whether this instance holds the scheduler lock
+	IsLeader bool `json:"isLeader"`
+	// Job names with a registered handler
+	RegisteredJobs []string `json:"registeredJobs"`
+}
+
 // SecuritySettings for security.
 type SecuritySettings struct {
 	// Session timeout (minutes)
@@ -1903,6 +2215,8 @@ type SecuritySettingsInput struct {
 	PasswordExpiryDays *int32 `json:"passwordExpiryDays,omitempty"`
 	// Two factor required
 	TwoFactorRequired *bool `json:"twoFactorRequired,omitempty"`
+	// Allowed IP ranges (CIDR or single IP). Empty list allows all.
+	AllowedIPRanges []string `json:"allowedIpRanges,omitempty"`
 }
 
 // SessionFilterInput allows filtering user sessions.
@@ -2267,6 +2581,16 @@ type TenantOverview struct {
 	CompaniesByStatus []*CompanyStatusCount `json:"companiesByStatus"`
 }
 
+// Signals that the runtime theme may have changed. Clients refetch the runtime
+// payload with If-None-Match and keep their copy on 304 Not Modified.
+type ThemeRuntimeChange struct {
+	// THEME_UPDATED, CAMPAIGN_UPDATED, SETTINGS_UPDATED or CAMPAIGN_WINDOW
+	Reason string `json:"reason"`
+	// Company whose branding changed; null when every company is affected
+	CompanyID *string   `json:"companyId,omitempty"`
+	ChangedAt time.Time `json:"changedAt"`
+}
+
 // TimbanganHistoryFilter for filtering.
 type TimbanganHistoryFilter struct {
 	// Date from
@@ -2400,6 +2724,17 @@ type UpdateEmployeeInput struct {
 	IsActive   *bool   `json:"isActive,omitempty"`
 }
 
+type UpdateGateWatchlistEntryInput struct {
+	ID        string                        `json:"id"`
+	Value     *string                       `json:"value,omitempty"`
+	Reason    *string                       `json:"reason,omitempty"`
+	Severity  *satpam.GateWatchlistSeverity `json:"severity,omitempty"`
+	ExpiresAt *time.Time                    `json:"expiresAt,omitempty"`
+	// Clears expiresAt
+	ClearExpiresAt *bool `json:"clearExpiresAt,omitempty"`
+	IsActive       *bool `json:"isActive,omitempty"`
+}
+
 type UpdateGradingRecordInput struct {
 	QualityScore         *int32   `json:"qualityScore,omitempty"`
 	MaturityLevel        *string  `json:"maturityLevel,omitempty"`
@@ -2466,6 +2801,8 @@ type UpdateNotificationPreferencesInput struct {
 	QuietHoursEnd *string `json:"quietHoursEnd,omitempty"`
 	// Quiet hours timezone
 	QuietHoursTimezone *string `json:"quietHoursTimezone,omitempty"`
+	// Email delivery mode: IMMEDIATE, HOURLY or DAILY
+	EmailDigestMode *string `json:"emailDigestMode,omitempty"`
 }
 
 type UpdatePKSRecordInput struct {
@@ -2496,6 +2833,18 @@ type UpdatePerawatanRecordInput struct {
 	Status             *StatusPerawatan `json:"status,omitempty"`
 }
 
+// UpdateScheduledJobInput changes a job schedule.
+type UpdateScheduledJobInput struct {
+	// Job ID
+	ID string `json:"id"`
+	// Cron expression
+	CronExpression *string `json:"cronExpression,omitempty"`
+	// IANA timezone; empty string clears it
+	Timezone *string `json:"timezone,omitempty"`
+	// Description
+	Description *string `json:"description,omitempty"`
+}
+
 // UpdateSystemSettingsInput for updating settings.
 type UpdateSystemSettingsInput struct {
 	// General settings
@@ -2537,6 +2886,18 @@ type UpdateVehicleTaxInput struct {
 	Notes            *string    `json:"notes,omitempty"`
 }
 
+type UpsertGateOverstayRuleInput struct {
+	// Updates the rule when set
+	ID *string `json:"id,omitempty"`
+	// Required for super admins creating a rule
+	CompanyID   *string             `json:"companyId,omitempty"`
+	VehicleType *satpam.VehicleType `json:"vehicleType,omitempty"`
+	Destination *string             `json:"destination,omitempty"`
+	// 1 to 10080 minutes (one week)
+	MaxStayMinutes int32 `json:"maxStayMinutes"`
+	IsActive       *bool `json:"isActive,omitempty"`
+}
+
 // UserCompanyAssignment represents Area Manager assignments to multiple companies.
 type UserCompanyAssignment struct {
 	ID                string                  `json:"id"`
@@ -2624,6 +2985,8 @@ type UserManagementResult struct {
 	User *CompanyUser `json:"user,omitempty"`
 	// Errors
 	Errors []string `json:"errors,omitempty"`
+	// One-time temporary password, set only when adminResetUserPassword generated one. It is not stored or shown again.
+	TemporaryPassword *string `json:"temporaryPassword,omitempty"`
 }
 
 // UserOverview for user statistics.
@@ -2733,22 +3096,26 @@ type VehiclePaginationResponse struct {
 }
 
 type VehicleTax struct {
-	ID               string     `json:"id"`
-	VehicleID        string     `json:"vehicleId"`
-	TaxYear          int32      `json:"taxYear"`
-	DueDate          time.Time  `json:"dueDate"`
-	PkbAmount        float64    `json:"pkbAmount"`
-	SwdklljAmount    float64    `json:"swdklljAmount"`
-	AdminAmount      float64    `json:"adminAmount"`
-	PenaltyAmount    float64    `json:"penaltyAmount"`
-	TotalAmount      float64    `json:"totalAmount"`
-	PaymentDate      *time.Time `json:"paymentDate,omitempty"`
-	PaymentMethod    *string    `json:"paymentMethod,omitempty"`
-	PaymentReference *string    `json:"paymentReference,omitempty"`
-	TaxStatus        string     `json:"taxStatus"`
-	Notes            *string    `json:"notes,omitempty"`
-	CreatedAt        time.Time  `json:"createdAt"`
-	UpdatedAt        time.Time  `json:"updatedAt"`
+	ID                     string     `json:"id"`
+	VehicleID              string     `json:"vehicleId"`
+	TaxYear                int32      `json:"taxYear"`
+	DueDate                time.Time  `json:"dueDate"`
+	PkbAmount              float64    `json:"pkbAmount"`
+	SwdklljAmount          float64    `json:"swdklljAmount"`
+	AdminAmount            float64    `json:"adminAmount"`
+	PenaltyAmount          float64    `json:"penaltyAmount"`
+	TotalAmount            float64    `json:"totalAmount"`
+	PaymentDate            *time.Time `json:"paymentDate,omitempty"`
+	PaymentMethod          *string    `json:"paymentMethod,omitempty"`
+	PaymentReference       *string    `json:"paymentReference,omitempty"`
+	TaxStatus              string     `json:"taxStatus"`
+	Notes                  *string    `json:"notes,omitempty"`
+	DaysOverdue            int32      `json:"daysOverdue"`
+	EstimatedPenaltyAmount float64    `json:"estimatedPenaltyAmount"`
+	ReminderStage          *string    `json:"reminderStage,omitempty"`
+	GateEntryBlocked       bool       `json:"gateEntryBlocked"`
+	CreatedAt              time.Time  `json:"createdAt"`
+	UpdatedAt              time.Time  `json:"updatedAt"`
 }
 
 type VehicleTaxDocument struct {
@@ -3097,6 +3464,66 @@ func (e AreaManagerActionType) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+type AttendanceStatus string
+
+const (
+	// Complete ENTRY/EXIT pairs
+	AttendanceStatusPresent AttendanceStatus = "PRESENT"
+	// At least one ENTRY or EXIT scan is missing
+	AttendanceStatusIncomplete AttendanceStatus = "INCOMPLETE"
+	// No gate scans on the work date
+	AttendanceStatusAbsent AttendanceStatus = "ABSENT"
+)
+
+var AllAttendanceStatus = []AttendanceStatus{
+	AttendanceStatusPresent,
+	AttendanceStatusIncomplete,
+	AttendanceStatusAbsent,
+}
+
+func (e AttendanceStatus) IsValid() bool {
+	switch e {
+	case AttendanceStatusPresent, AttendanceStatusIncomplete, AttendanceStatusAbsent:
+		return true
+	}
+	return false
+}
+
+func (e AttendanceStatus) String() string {
+	return string(e)
+}
+
+func (e *AttendanceStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AttendanceStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AttendanceStatus", str)
+	}
+	return nil
+}
+
+func (e AttendanceStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *AttendanceStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e AttendanceStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 // BlockTreatmentRequestStatus represents lifecycle status for semester treatment requests.
 type BlockTreatmentRequestStatus string
 
@@ -3480,6 +3907,122 @@ func (e ManagerBudgetWorkflowStatus) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// MasterDataImportStatus represents lifecycle status for background master data imports.
+type MasterDataImportStatus string
+
+const (
+	MasterDataImportStatusPending   MasterDataImportStatus = "PENDING"
+	MasterDataImportStatusRunning   MasterDataImportStatus = "RUNNING"
+	MasterDataImportStatusCompleted MasterDataImportStatus = "COMPLETED"
+	MasterDataImportStatusFailed    MasterDataImportStatus = "FAILED"
+)
+
+var AllMasterDataImportStatus = []MasterDataImportStatus{
+	MasterDataImportStatusPending,
+	MasterDataImportStatusRunning,
+	MasterDataImportStatusCompleted,
+	MasterDataImportStatusFailed,
+}
+
+func (e MasterDataImportStatus) IsValid() bool {
+	switch e {
+	case MasterDataImportStatusPending, MasterDataImportStatusRunning, MasterDataImportStatusCompleted, MasterDataImportStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e MasterDataImportStatus) String() string {
+	return string(e)
+}
+
+func (e *MasterDataImportStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MasterDataImportStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MasterDataImportStatus", str)
+	}
+	return nil
+}
+
+func (e MasterDataImportStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *MasterDataImportStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e MasterDataImportStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// MasterDataImportType selects the spreadsheet layout of a master data import or export.
+type MasterDataImportType string
+
+const (
+	MasterDataImportTypeHierarchy MasterDataImportType = "HIERARCHY"
+	MasterDataImportTypeEmployee  MasterDataImportType = "EMPLOYEE"
+)
+
+var AllMasterDataImportType = []MasterDataImportType{
+	MasterDataImportTypeHierarchy,
+	MasterDataImportTypeEmployee,
+}
+
+func (e MasterDataImportType) IsValid() bool {
+	switch e {
+	case MasterDataImportTypeHierarchy, MasterDataImportTypeEmployee:
+		return true
+	}
+	return false
+}
+
+func (e MasterDataImportType) String() string {
+	return string(e)
+}
+
+func (e *MasterDataImportType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MasterDataImportType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MasterDataImportType", str)
+	}
+	return nil
+}
+
+func (e MasterDataImportType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *MasterDataImportType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e MasterDataImportType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 // PKS quality classification.
 type PKSKualitas string
 
@@ -3844,6 +4387,120 @@ func (e RegistrationSource) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// ScheduledJobRunStatus for scheduled job runs.
+type ScheduledJobRunStatus string
+
+const (
+	ScheduledJobRunStatusRunning   ScheduledJobRunStatus = "RUNNING"
+	ScheduledJobRunStatusSucceeded ScheduledJobRunStatus = "SUCCEEDED"
+	ScheduledJobRunStatusFailed    ScheduledJobRunStatus = "FAILED"
+)
+
+var AllScheduledJobRunStatus = []ScheduledJobRunStatus{
+	ScheduledJobRunStatusRunning,
+	ScheduledJobRunStatusSucceeded,
+	ScheduledJobRunStatusFailed,
+}
+
+func (e ScheduledJobRunStatus) IsValid() bool {
+	switch e {
+	case ScheduledJobRunStatusRunning, ScheduledJobRunStatusSucceeded, ScheduledJobRunStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e ScheduledJobRunStatus) String() string {
+	return string(e)
+}
+
+func (e *ScheduledJobRunStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ScheduledJobRunStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ScheduledJobRunStatus", str)
+	}
+	return nil
+}
+
+func (e ScheduledJobRunStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ScheduledJobRunStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ScheduledJobRunStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+// ScheduledJobTrigger tells why a run started.
+type ScheduledJobTrigger string
+
+const (
+	ScheduledJobTriggerSchedule ScheduledJobTrigger = "SCHEDULE"
+	ScheduledJobTriggerManual   ScheduledJobTrigger = "MANUAL"
+)
+
+var AllScheduledJobTrigger = []ScheduledJobTrigger{
+	ScheduledJobTriggerSchedule,
+	ScheduledJobTriggerManual,
+}
+
+func (e ScheduledJobTrigger) IsValid() bool {
+	switch e {
+	case ScheduledJobTriggerSchedule, ScheduledJobTriggerManual:
+		return true
+	}
+	return false
+}
+
+func (e ScheduledJobTrigger) String() string {
+	return string(e)
+}
+
+func (e *ScheduledJobTrigger) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ScheduledJobTrigger(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ScheduledJobTrigger", str)
+	}
+	return nil
+}
+
+func (e ScheduledJobTrigger) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ScheduledJobTrigger) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ScheduledJobTrigger) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 // ServiceStatus enum.
 type ServiceStatus string
 
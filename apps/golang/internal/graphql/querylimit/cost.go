@@ -0,0 +1,165 @@
+package querylimit
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	// defaultListSize is the assumed length of a list field that has no
+	// page-size argument (companies -> estates -> divisions -> blocks).
+	defaultListSize = 10
+	// maxListSize caps page-size arguments so a huge limit cannot overflow.
+	maxListSize = 1000
+)
+
+// pageSizeArgs are the argument names this schema uses to bound lists.
+var pageSizeArgs = []string{"limit", "pageSize", "first", "last", "perPage", "take"}
+
+// costSchema overrides field complexity so list fields multiply the cost of
+// their selection. Every other field keeps gqlgen's default of 1 + children.
+type costSchema struct {
+	graphql.ExecutableSchema
+}
+
+func (s costSchema) Complexity(ctx context.Context, typeName, fieldName string, childComplexity int, args map[string]any) (int, bool) {
+	if strings.HasPrefix(fieldName, "__") {
+		// Introspection is gated by config, not by the cost budget.
+		return 1, true
+	}
+	if cost, ok := s.ExecutableSchema.Complexity(ctx, typeName, fieldName, childComplexity, args); ok {
+		return cost, true
+	}
+
+	def := s.Schema().Types[typeName]
+	if def == nil {
+		return 0, false
+	}
+	field := def.Fields.ForName(fieldName)
+	if field == nil {
+		return 0, false
+	}
+
+	pageSize, hasPageSize := pageSizeArg(args)
+	switch {
+	case field.Type.Elem != nil && hasPageSize:
+		return scaledCost(childComplexity, pageSize), true
+	case field.Type.Elem != nil:
+		return scaledCost(childComplexity, defaultListSize), true
+	case hasPageSize:
+		// Paginated wrapper ({ data: [...], totalCount }): the inner list
+		// is already counted at defaultListSize, scale it to the page.
+		return scaledCost(childComplexity, ceilDiv(pageSize, defaultListSize)), true
+	default:
+		return 0, false
+	}
+}
+
+// pageSizeArg finds a page-size argument on the field or one level down in
+// an input object such as ApprovalFilterInput.pageSize.
+func pageSizeArg(args map[string]any) (int, bool) {
+	if size, ok := findPageSize(args); ok {
+		return size, true
+	}
+	for _, value := range args {
+		if nested, ok := value.(map[string]any); ok {
+			if size, ok := findPageSize(nested); ok {
+				return size, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func findPageSize(args map[string]any) (int, bool) {
+	for _, name := range pageSizeArgs {
+		value, ok := args[name]
+		if !ok || value == nil {
+			continue
+		}
+		var size int64
+		switch v := value.(type) {
+		case int:
+			size = int64(v)
+		case int32:
+			size = int64(v)
+		case int64:
+			size = v
+		case float64:
+			size = int64(v)
+		case *int32:
+			if v == nil {
+				continue
+			}
+			size = int64(*v)
+		default:
+			continue
+		}
+		if size < 1 {
+			size = 1
+		}
+		if size > maxListSize {
+			size = maxListSize
+		}
+		return int(size), true
+	}
+	return 0, false
+}
+
+// scaledCost returns 1 + childComplexity*factor, saturating at math.MaxInt
+// so deeply nested lists cannot wrap around to a small or negative cost.
+func scaledCost(childComplexity, factor int) int {
+	if childComplexity > 0 && factor > 0 && childComplexity > (math.MaxInt-1)/factor {
+		return math.MaxInt
+	}
+	return 1 + childComplexity*factor
+}
+
+func ceilDiv(a, b int) int {
+	if a <= b {
+		return 1
+	}
+	return (a + b - 1) / b
+}
+
+// operationDepth returns the deepest field nesting of the operation.
+// Introspection fields are skipped; introspection is gated separately and
+// its ofType chains are deep by design.
+func operationDepth(op *ast.OperationDefinition) int {
+	if op == nil {
+		return 0
+	}
+	return selectionDepth(op.SelectionSet, map[string]bool{})
+}
+
+func selectionDepth(set ast.SelectionSet, visiting map[string]bool) int {
+	deepest := 0
+	for _, selection := range set {
+		depth := 0
+		switch sel := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(sel.Name, "__") {
+				continue
+			}
+			depth = 1 + selectionDepth(sel.SelectionSet, visiting)
+		case *ast.InlineFragment:
+			depth = selectionDepth(sel.SelectionSet, visiting)
+		case *ast.FragmentSpread:
+			// Validation rejects fragment cycles, but guard anyway.
+			if sel.Definition == nil || visiting[sel.Name] {
+				continue
+			}
+			visiting[sel.Name] = true
+			depth = selectionDepth(sel.Definition.SelectionSet, visiting)
+			delete(visiting, sel.Name)
+		}
+		if depth > deepest {
+			deepest = depth
+		}
+	}
+	return deepest
+}
@@ -0,0 +1,147 @@
+// Package querylimit guards the GraphQL endpoint against expensive
+// operations: per-role cost budgets, a depth limit, automatic persisted
+// queries and an allow-list mode for production mobile builds.
+package querylimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"agrinovagraphql/server/internal/graphql/domain/auth"
+	"agrinovagraphql/server/internal/middleware"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	errCodeTooDeep    = "QUERY_TOO_DEEP"
+	errCodeTooComplex = "QUERY_TOO_COMPLEX"
+
+	anonymousRole = "ANONYMOUS"
+)
+
+// Limits rejects operations that are nested deeper than MaxDepth or whose
+// cost exceeds the caller's role budget.
+type Limits struct {
+	// MaxDepth is the deepest allowed field nesting; 0 disables the check.
+	MaxDepth int
+	// DefaultMaxCost applies to roles without their own budget; 0 disables
+	// cost checks for those roles.
+	DefaultMaxCost int
+	// RoleMaxCost overrides DefaultMaxCost per role.
+	RoleMaxCost map[auth.UserRole]int
+
+	schema graphql.ExecutableSchema
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = &Limits{}
+
+// ExtensionName implements graphql.HandlerExtension.
+func (l *Limits) ExtensionName() string {
+	return "QueryLimits"
+}
+
+// Validate implements graphql.HandlerExtension.
+func (l *Limits) Validate(schema graphql.ExecutableSchema) error {
+	if schema == nil {
+		return errors.New("QueryLimits requires an executable schema")
+	}
+	l.schema = costSchema{ExecutableSchema: schema}
+	return nil
+}
+
+// MutateOperationContext implements graphql.OperationContextMutator.
+func (l *Limits) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	op := opCtx.Operation
+	if op == nil {
+		op = opCtx.Doc.Operations.ForName(opCtx.OperationName)
+	}
+	if op == nil {
+		return nil
+	}
+
+	role := roleLabel(ctx)
+
+	if l.MaxDepth > 0 {
+		if depth := operationDepth(op); depth > l.MaxDepth {
+			recordRejection(reasonDepth, role)
+			err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, l.MaxDepth)
+			errcode.Set(err, errCodeTooDeep)
+			err.Extensions["depth"] = depth
+			err.Extensions["limit"] = l.MaxDepth
+			return err
+		}
+	}
+
+	budget := l.budget(middleware.GetUserRoleFromContext(ctx))
+	if budget <= 0 {
+		return nil
+	}
+
+	cost := complexity.Calculate(ctx, l.schema, op, opCtx.Variables)
+	operationCost.WithLabelValues(role).Observe(float64(cost))
+	if cost > budget {
+		recordRejection(reasonComplexity, role)
+		err := gqlerror.Errorf("operation has cost %d, which exceeds the %s budget of %d", cost, role, budget)
+		errcode.Set(err, errCodeTooComplex)
+		err.Extensions["cost"] = cost
+		err.Extensions["limit"] = budget
+		return err
+	}
+
+	return nil
+}
+
+func (l *Limits) budget(role auth.UserRole) int {
+	if budget, ok := l.RoleMaxCost[role]; ok {
+		return budget
+	}
+	return l.DefaultMaxCost
+}
+
+func roleLabel(ctx context.Context) string {
+	if role := middleware.GetUserRoleFromContext(ctx); role != "" {
+		return string(role)
+	}
+	return anonymousRole
+}
+
+// ParseRoleBudgets parses "MANDOR=5000,SUPER_ADMIN=50000" into per-role
+// cost budgets. Role names are case-insensitive; blank input yields nil.
+func ParseRoleBudgets(raw string) (map[auth.UserRole]int, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+
+	budgets := make(map[auth.UserRole]int)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid role budget %q: expected ROLE=COST", entry)
+		}
+		role := auth.UserRole(strings.ToUpper(strings.TrimSpace(name)))
+		if !role.IsValid() {
+			return nil, fmt.Errorf("invalid role budget %q: unknown role", entry)
+		}
+		cost, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || cost < 0 {
+			return nil, fmt.Errorf("invalid role budget %q: cost must be a non-negative integer", entry)
+		}
+		budgets[role] = cost
+	}
+	return budgets, nil
+}
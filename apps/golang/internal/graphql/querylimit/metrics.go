@@ -0,0 +1,39 @@
+package querylimit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	reasonDepth      = "depth"
+	reasonComplexity = "complexity"
+	reasonAllowList  = "allowlist"
+)
+
+var (
+	rejectedOperations = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "agrinova",
+			Subsystem: "graphql",
+			Name:      "rejected_operations_total",
+			Help:      "Total number of GraphQL operations rejected before execution",
+		},
+		[]string{"reason", "role"},
+	)
+
+	operationCost = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "agrinova",
+			Subsystem: "graphql",
+			Name:      "operation_cost",
+			Help:      "Calculated cost of accepted and rejected GraphQL operations",
+			Buckets:   []float64{10, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 25000, 50000, 100000},
+		},
+		[]string{"role"},
+	)
+)
+
+func recordRejection(reason, role string) {
+	rejectedOperations.WithLabelValues(reason, role).Inc()
+}
@@ -0,0 +1,198 @@
+package querylimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"agrinovagraphql/server/internal/cache"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const errCodeNotAllowed = "PERSISTED_QUERY_NOT_ALLOWED"
+
+// Allow-list modes.
+const (
+	AllowListOff    = "off"
+	AllowListMobile = "mobile"
+	AllowListAll    = "all"
+)
+
+// PersistedQueryCache stores automatic persisted queries (APQ) in the
+// shared cache client, keyed by the query's SHA-256.
+type PersistedQueryCache struct {
+	client cache.CacheClient
+	ttl    time.Duration
+}
+
+var _ graphql.Cache[string] = (*PersistedQueryCache)(nil)
+
+// NewPersistedQueryCache creates an APQ cache; ttl 0 keeps entries until
+// the cache evicts them.
+func NewPersistedQueryCache(client cache.CacheClient, ttl time.Duration) *PersistedQueryCache {
+	return &PersistedQueryCache{client: client, ttl: ttl}
+}
+
+// Get implements graphql.Cache.
+func (c *PersistedQueryCache) Get(ctx context.Context, hash string) (string, bool) {
+	query, err := c.client.Get(ctx, persistedQueryKey(hash))
+	if err != nil || query == "" {
+		return "", false
+	}
+	return query, true
+}
+
+// Add implements graphql.Cache.
+func (c *PersistedQueryCache) Add(ctx context.Context, hash, query string) {
+	_ = c.client.Set(ctx, persistedQueryKey(hash), query, c.ttl)
+}
+
+func persistedQueryKey(hash string) string {
+	return "graphql:apq:" + hash
+}
+
+// AllowList only lets through operations whose SHA-256 is in a manifest
+// shipped with the client build. Hash-only requests are answered from the
+// manifest, so allow-listed clients never depend on the APQ cache.
+// It must be registered before the APQ extension.
+type AllowList struct {
+	// Mode is AllowListMobile (only mobile builds) or AllowListAll.
+	Mode    string
+	queries map[string]string
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationParameterMutator
+} = &AllowList{}
+
+// NewAllowList builds an allow-list from queries keyed by SHA-256.
+func NewAllowList(mode string, queries map[string]string) *AllowList {
+	return &AllowList{Mode: mode, queries: queries}
+}
+
+// LoadAllowList reads a manifest file. Two formats are accepted: a plain
+// {"<sha256>": "<query>"} object, or an Apollo persisted query manifest
+// ({"operations": [{"body": "..."}]}). Hashes are recomputed from the
+// query text, so a manifest with stale ids fails loudly instead of
+// silently rejecting the build.
+func LoadAllowList(mode, path string) (*AllowList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read persisted query manifest: %w", err)
+	}
+
+	queries, err := parseManifest(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid persisted query manifest %s: %w", path, err)
+	}
+	return NewAllowList(mode, queries), nil
+}
+
+func parseManifest(data []byte) (map[string]string, error) {
+	var apollo struct {
+		Operations []struct {
+			ID   string `json:"id"`
+			Body string `json:"body"`
+		} `json:"operations"`
+	}
+	if err := json.Unmarshal(data, &apollo); err == nil && apollo.Operations != nil {
+		queries := make(map[string]string, len(apollo.Operations))
+		for _, op := range apollo.Operations {
+			if strings.TrimSpace(op.Body) == "" {
+				return nil, fmt.Errorf("operation %q has no body", op.ID)
+			}
+			queries[queryHash(op.Body)] = op.Body
+		}
+		return queries, nil
+	}
+
+	var plain map[string]string
+	if err := json.Unmarshal(data, &plain); err != nil {
+		return nil, err
+	}
+	queries := make(map[string]string, len(plain))
+	for hash, query := range plain {
+		if queryHash(query) != strings.ToLower(hash) {
+			return nil, fmt.Errorf("hash %s does not match its query", hash)
+		}
+		queries[queryHash(query)] = query
+	}
+	return queries, nil
+}
+
+// ExtensionName implements graphql.HandlerExtension.
+func (a *AllowList) ExtensionName() string {
+	return "PersistedQueryAllowList"
+}
+
+// Validate implements graphql.HandlerExtension.
+func (a *AllowList) Validate(graphql.ExecutableSchema) error {
+	if a.Mode != AllowListMobile && a.Mode != AllowListAll {
+		return fmt.Errorf("unsupported allow-list mode %q", a.Mode)
+	}
+	if len(a.queries) == 0 {
+		return errors.New("persisted query allow-list is empty")
+	}
+	return nil
+}
+
+// MutateOperationParameters implements graphql.OperationParameterMutator.
+func (a *AllowList) MutateOperationParameters(ctx context.Context, params *graphql.RawParams) *gqlerror.Error {
+	if a.Mode == AllowListMobile && !IsMobileClient(params.Headers) {
+		return nil
+	}
+
+	hash := requestedHash(params.Extensions)
+	if hash == "" {
+		hash = queryHash(params.Query)
+	}
+
+	query, ok := a.queries[hash]
+	if !ok || (params.Query != "" && params.Query != query) {
+		recordRejection(reasonAllowList, roleLabel(ctx))
+		err := gqlerror.Errorf("operation is not in the persisted query allow-list")
+		errcode.Set(err, errCodeNotAllowed)
+		return err
+	}
+
+	params.Query = query
+	return nil
+}
+
+// IsMobileClient reports whether the request comes from the mobile app,
+// by X-Platform hint or the app's User-Agent.
+func IsMobileClient(headers http.Header) bool {
+	if headers == nil {
+		return false
+	}
+	switch strings.ToUpper(strings.TrimSpace(headers.Get("X-Platform"))) {
+	case "ANDROID", "MOBILE_ANDROID", "IOS", "MOBILE_IOS", "IPHONE", "IPAD", "MOBILE":
+		return true
+	}
+	return strings.HasPrefix(headers.Get("User-Agent"), "Agrinova-Mobile/")
+}
+
+func requestedHash(extensions map[string]any) string {
+	persisted, ok := extensions["persistedQuery"].(map[string]any)
+	if !ok {
+		return ""
+	}
+	hash, _ := persisted["sha256Hash"].(string)
+	return strings.ToLower(strings.TrimSpace(hash))
+}
+
+func queryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
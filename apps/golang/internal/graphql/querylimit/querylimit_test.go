@@ -0,0 +1,301 @@
+package querylimit
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"testing"
+
+	"agrinovagraphql/server/internal/graphql/domain/auth"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const testSchemaSDL = `
+type Query {
+	companies: [Company!]!
+	harvestRecords(limit: Int): [HarvestRecord!]!
+	approvals(filter: ApprovalFilter): ApprovalPage!
+}
+input ApprovalFilter { pageSize: Int }
+type ApprovalPage { data: [HarvestRecord!]! totalCount: Int! }
+type Company { id: ID! estates: [Estate!]! }
+type Estate { id: ID! name: String! }
+type HarvestRecord { id: ID! block: Block! }
+type Block { id: ID! name: String! }
+`
+
+// fakeSchema is an executable schema with no generated complexity, so every
+// cost comes from costSchema.
+type fakeSchema struct {
+	schema *ast.Schema
+}
+
+func (f fakeSchema) Schema() *ast.Schema { return f.schema }
+
+func (f fakeSchema) Complexity(context.Context, string, string, int, map[string]any) (int, bool) {
+	return 0, false
+}
+
+func (f fakeSchema) Exec(context.Context) graphql.ResponseHandler { return nil }
+
+func newTestSchema(t *testing.T) fakeSchema {
+	t.Helper()
+	schema, err := gqlparser.LoadSchema(&ast.Source{Input: testSchemaSDL})
+	if err != nil {
+		t.Fatalf("load schema: %v", err)
+	}
+	return fakeSchema{schema: schema}
+}
+
+func operationContext(t *testing.T, schema fakeSchema, query string) *graphql.OperationContext {
+	t.Helper()
+	doc, errs := gqlparser.LoadQuery(schema.schema, query)
+	if errs != nil {
+		t.Fatalf("load query: %v", errs)
+	}
+	return &graphql.OperationContext{
+		RawQuery:  query,
+		Doc:       doc,
+		Operation: doc.Operations[0],
+		Variables: map[string]any{},
+	}
+}
+
+func TestLimits_MutateOperationContext(t *testing.T) {
+	schema := newTestSchema(t)
+	limits := &Limits{
+		MaxDepth:       3,
+		DefaultMaxCost: 200,
+		RoleMaxCost:    map[auth.UserRole]int{auth.UserRoleSuperAdmin: 10000},
+	}
+	if err := limits.Validate(schema); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		role     string
+		query    string
+		wantCode string
+	}{
+		{
+			name:  "nested list within budget",
+			query: `{ companies { id estates { name } } }`,
+		},
+		{
+			name:     "page size multiplies cost",
+			query:    `{ harvestRecords(limit: 500) { id block { name } } }`,
+			wantCode: errCodeTooComplex,
+		},
+		{
+			name:     "page size in filter input",
+			query:    `{ approvals(filter: {pageSize: 200}) { data { id } totalCount } }`,
+			wantCode: errCodeTooComplex,
+		},
+		{
+			name:  "role budget overrides default",
+			role:  string(auth.UserRoleSuperAdmin),
+			query: `{ harvestRecords(limit: 500) { id block { name } } }`,
+		},
+		{
+			name:     "too deep",
+			role:     string(auth.UserRoleSuperAdmin),
+			query:    `{ approvals { data { block { name } } } }`,
+			wantCode: errCodeTooDeep,
+		},
+		{
+			name:  "introspection does not count towards depth",
+			query: `{ __schema { types { fields { type { ofType { name } } } } } }`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.role != "" {
+				ctx = context.WithValue(ctx, "user_role", tt.role)
+			}
+
+			err := limits.MutateOperationContext(ctx, operationContext(t, schema, tt.query))
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("unexpected rejection: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected %s rejection", tt.wantCode)
+			}
+			if code := err.Extensions["code"]; code != tt.wantCode {
+				t.Errorf("code = %v, want %s", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestCostSchema_SaturatesOnOverflow(t *testing.T) {
+	schema := costSchema{newTestSchema(t)}
+	ctx := context.Background()
+
+	cost, ok := schema.Complexity(ctx, "Query", "harvestRecords", math.MaxInt/2, map[string]any{"limit": 1000})
+	if !ok || cost != math.MaxInt {
+		t.Errorf("paged list cost = %d, %v, want math.MaxInt", cost, ok)
+	}
+	cost, ok = schema.Complexity(ctx, "Company", "estates", math.MaxInt/5, nil)
+	if !ok || cost != math.MaxInt {
+		t.Errorf("list cost = %d, %v, want math.MaxInt", cost, ok)
+	}
+	cost, ok = schema.Complexity(ctx, "Query", "harvestRecords", 3, map[string]any{"limit": 20})
+	if !ok || cost != 61 {
+		t.Errorf("small list cost = %d, %v, want 61", cost, ok)
+	}
+}
+
+func TestParseRoleBudgets(t *testing.T) {
+	budgets, err := ParseRoleBudgets(" mandor=5000, SUPER_ADMIN=50000 ,")
+	if err != nil {
+		t.Fatalf("ParseRoleBudgets: %v", err)
+	}
+	if budgets[auth.UserRoleMandor] != 5000 || budgets[auth.UserRoleSuperAdmin] != 50000 || len(budgets) != 2 {
+		t.Errorf("budgets = %v", budgets)
+	}
+
+	if budgets, err := ParseRoleBudgets(""); err != nil || budgets != nil {
+		t.Errorf("blank input = %v, %v", budgets, err)
+	}
+
+	for _, raw := range []string{"MANDOR", "GUEST=10", "MANDOR=-1", "MANDOR=lots"} {
+		if _, err := ParseRoleBudgets(raw); err == nil {
+			t.Errorf("ParseRoleBudgets(%q) expected error", raw)
+		}
+	}
+}
+
+func TestParseManifest(t *testing.T) {
+	query := `query Me { me { id } }`
+	hash := queryHash(query)
+
+	apollo, _ := json.Marshal(map[string]any{
+		"format":     "apollo-persisted-query-manifest",
+		"operations": []map[string]string{{"id": "stale", "body": query}},
+	})
+	plain, _ := json.Marshal(map[string]string{hash: query})
+
+	for name, data := range map[string][]byte{"apollo": apollo, "plain": plain} {
+		queries, err := parseManifest(data)
+		if err != nil {
+			t.Fatalf("%s: parseManifest: %v", name, err)
+		}
+		if queries[hash] != query {
+			t.Errorf("%s: queries = %v", name, queries)
+		}
+	}
+
+	mismatched, _ := json.Marshal(map[string]string{"deadbeef": query})
+	if _, err := parseManifest(mismatched); err == nil {
+		t.Error("expected error for hash that does not match its query")
+	}
+}
+
+func TestAllowList_MutateOperationParameters(t *testing.T) {
+	query := `query Me { me { id } }`
+	hash := queryHash(query)
+	mobile := http.Header{"User-Agent": []string{"Agrinova-Mobile/2.1.0 (Flutter)"}}
+	web := http.Header{"User-Agent": []string{"Mozilla/5.0"}}
+	persisted := map[string]any{"persistedQuery": map[string]any{"version": 1, "sha256Hash": hash}}
+
+	tests := []struct {
+		name      string
+		mode      string
+		params    graphql.RawParams
+		wantError bool
+		wantQuery string
+	}{
+		{
+			name:      "mobile hash-only request resolves from manifest",
+			mode:      AllowListMobile,
+			params:    graphql.RawParams{Headers: mobile, Extensions: persisted},
+			wantQuery: query,
+		},
+		{
+			name:      "mobile full query in manifest",
+			mode:      AllowListMobile,
+			params:    graphql.RawParams{Headers: mobile, Query: query},
+			wantQuery: query,
+		},
+		{
+			name:      "mobile ad-hoc query rejected",
+			mode:      AllowListMobile,
+			params:    graphql.RawParams{Headers: mobile, Query: `{ me { id name } }`},
+			wantError: true,
+		},
+		{
+			name:      "mobile query that does not match its hash rejected",
+			mode:      AllowListMobile,
+			params:    graphql.RawParams{Headers: mobile, Query: `{ me { id name } }`, Extensions: persisted},
+			wantError: true,
+		},
+		{
+			name:      "web bypasses mobile mode",
+			mode:      AllowListMobile,
+			params:    graphql.RawParams{Headers: web, Query: `{ me { id name } }`},
+			wantQuery: `{ me { id name } }`,
+		},
+		{
+			name:      "web rejected in all mode",
+			mode:      AllowListAll,
+			params:    graphql.RawParams{Headers: web, Query: `{ me { id name } }`},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowList := NewAllowList(tt.mode, map[string]string{hash: query})
+			if err := allowList.Validate(nil); err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+
+			params := tt.params
+			err := allowList.MutateOperationParameters(context.Background(), &params)
+			if tt.wantError {
+				if err == nil {
+					t.Fatal("expected rejection")
+				}
+				if code := err.Extensions["code"]; code != errCodeNotAllowed {
+					t.Errorf("code = %v, want %s", code, errCodeNotAllowed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected rejection: %v", err)
+			}
+			if params.Query != tt.wantQuery {
+				t.Errorf("query = %q, want %q", params.Query, tt.wantQuery)
+			}
+		})
+	}
+}
+
+func TestIsMobileClient(t *testing.T) {
+	tests := []struct {
+		headers http.Header
+		want    bool
+	}{
+		{http.Header{"User-Agent": []string{"Agrinova-Mobile/2.1.0 (Flutter)"}}, true},
+		{http.Header{"X-Platform": []string{"android"}}, true},
+		{http.Header{"X-Platform": []string{"MOBILE_IOS"}}, true},
+		{http.Header{"X-Platform": []string{"WEB"}}, false},
+		{http.Header{"User-Agent": []string{"Mozilla/5.0"}}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := IsMobileClient(tt.headers); got != tt.want {
+			t.Errorf("IsMobileClient(%v) = %v, want %v", tt.headers, got, tt.want)
+		}
+	}
+}
@@ -312,6 +312,9 @@ func (r *queryResolver) AreaManagerDashboard(
 	dateTo *time.Time,
 	companyID *string,
 ) (*generated.AreaManagerDashboardData, error) {
+	ctx, release := r.ReadRouter.UseReplica(ctx)
+	defer release()
+
 	userID := strings.TrimSpace(middleware.GetCurrentUserID(ctx))
 	if userID == "" {
 		return nil, fmt.Errorf("authentication required")
@@ -480,6 +483,9 @@ func (r *queryResolver) AreaManagerDashboard(
 
 // AreaManagerCompanyDetail is the resolver for the areaManagerCompanyDetail field.
 func (r *queryResolver) AreaManagerCompanyDetail(ctx context.Context, companyID string) (*generated.CompanyPerformanceData, error) {
+	ctx, release := r.ReadRouter.UseReplica(ctx)
+	defer release()
+
 	userID := strings.TrimSpace(middleware.GetCurrentUserID(ctx))
 	if userID == "" {
 		return nil, fmt.Errorf("authentication required")
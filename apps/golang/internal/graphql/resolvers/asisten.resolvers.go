@@ -550,7 +550,8 @@ func (r *queryResolver) PendingApprovals(ctx context.Context, filter *asisten.Ap
 		return nil, fmt.Errorf("failed to fetch pending approvals: %w", err)
 	}
 
-	// Convert to ApprovalItem with manual relationship loading
+	// Convert to ApprovalItem; relations for the whole page are batched first
+	r.preloadApprovalRelations(ctx, records)
 	items := make([]*asisten.ApprovalItem, 0, len(records))
 	for _, record := range records {
 		item := r.convertHarvestToApprovalItemWithLoading(ctx, record)
@@ -596,6 +597,9 @@ func (r *queryResolver) ApprovalItem(ctx context.Context, id string) (*asisten.A
 
 // ApprovalHistory is the resolver for the approvalHistory field.
 func (r *queryResolver) ApprovalHistory(ctx context.Context, filter *asisten.ApprovalFilterInput) (*asisten.ApprovalListResponse, error) {
+	ctx, release := r.ReadRouter.UseReplica(ctx)
+	defer release()
+
 	userID, userRole, err := r.requireApprovalUserContext(ctx)
 	if err != nil {
 		return nil, err
@@ -660,6 +664,7 @@ func (r *queryResolver) ApprovalHistory(ctx context.Context, filter *asisten.App
 		return nil, fmt.Errorf("failed to fetch approval history: %w", err)
 	}
 
+	r.preloadApprovalRelations(ctx, records)
 	items := make([]*asisten.ApprovalItem, 0, len(records))
 	for _, record := range records {
 		items = append(items, r.convertHarvestToApprovalItemWithLoading(ctx, record))
@@ -822,7 +827,8 @@ func (r *subscriptionResolver) BlockActivityUpdate(ctx context.Context, division
 
 // Helper functions
 
-// convertHarvestToApprovalItemWithLoading manually loads related data to avoid GORM deep preload issues
+// convertHarvestToApprovalItemWithLoading loads related data through the request dataloaders
+// to avoid GORM deep preload issues; list callers batch them with preloadApprovalRelations.
 func (r *queryResolver) convertHarvestToApprovalItemWithLoading(ctx context.Context, record *mandor.HarvestRecord) *asisten.ApprovalItem {
 	photoUrls := normalizeHarvestPhotoURLs(record.PhotoURL)
 
@@ -830,7 +836,7 @@ func (r *queryResolver) convertHarvestToApprovalItemWithLoading(ctx context.Cont
 		ID:                record.ID,
 		HarvestDate:       record.Tanggal,
 		EmployeeCount:     1, // Would need to parse karyawan field
-		Employees:         r.resolveHarvestEmployeeLabel(record),
+		Employees:         r.resolveHarvestEmployeeLabel(ctx, record),
 		TbsCount:          record.JumlahJanjang,
 		Weight:            record.BeratTbs,
 		SubmittedAt:       record.CreatedAt,
@@ -850,28 +856,30 @@ func (r *queryResolver) convertHarvestToApprovalItemWithLoading(ctx context.Cont
 		ValidationStatus:  asisten.ValidationStatusValid,
 		ValidationIssues:  nil,
 	}
+	r.applyPhotoVerificationIssues(ctx, item)
+
+	loaders := r.loaders(ctx)
 
-	// Manually load Mandor (User)
+	// Load Mandor (User)
 	if record.MandorID != "" {
-		var mandor auth.User
-		if err := r.db.Where("id = ?", record.MandorID).First(&mandor).Error; err == nil {
-			item.Mandor = &mandor
+		if mandor, err := loaders.Users.Load(ctx, record.MandorID); err == nil && mandor != nil {
+			item.Mandor = mandor
 		}
 	}
 
-	// Manually load Block and Division
+	// Load Block and Division
 	if record.BlockID != "" {
-		var block master.Block
-		if err := r.db.Where("id = ?", record.BlockID).First(&block).Error; err == nil {
-			item.Block = &block
+		if block, err := loaders.Blocks.Load(ctx, record.BlockID); err == nil && block != nil {
+			// Copy so the cached block is not mutated below
+			blockCopy := *block
+			item.Block = &blockCopy
 
 			// Load Division for the block
 			if block.DivisionID != "" {
-				var division master.Division
-				if err := r.db.Where("id = ?", block.DivisionID).First(&division).Error; err == nil {
-					item.Division = &division
+				if division, err := loaders.Divisions.Load(ctx, block.DivisionID); err == nil && division != nil {
+					item.Division = division
 					// Also set division on block for completeness
-					item.Block.Division = &division
+					item.Block.Division = division
 				}
 			}
 		}
@@ -880,16 +888,16 @@ func (r *queryResolver) convertHarvestToApprovalItemWithLoading(ctx context.Cont
 	return item
 }
 
-func (r *queryResolver) resolveHarvestEmployeeLabel(record *mandor.HarvestRecord) string {
+func (r *queryResolver) resolveHarvestEmployeeLabel(ctx context.Context, record *mandor.HarvestRecord) string {
 	if record == nil {
 		return ""
 	}
 
-	if name := r.lookupEmployeeNameByID(record.KaryawanID); name != "" {
+	if name := r.lookupEmployeeNameByID(ctx, record.KaryawanID); name != "" {
 		return name
 	}
 
-	if name := r.lookupEmployeeNameByNIK(record.Nik, record.CompanyID); name != "" {
+	if name := r.lookupEmployeeNameByNIK(ctx, record.Nik, record.CompanyID); name != "" {
 		return name
 	}
 
@@ -956,7 +964,7 @@ func normalizeHarvestPhotoURLs(photoURL *string) []string {
 	return []string{trimmed}
 }
 
-func (r *queryResolver) lookupEmployeeNameFromRaw(raw string, companyID *string) string {
+func (r *queryResolver) lookupEmployeeNameFromRaw(ctx context.Context, raw string, companyID *string) string {
 	candidate := strings.TrimSpace(raw)
 	if candidate == "" {
 		return ""
@@ -965,17 +973,17 @@ func (r *queryResolver) lookupEmployeeNameFromRaw(raw string, companyID *string)
 	// If value is UUID-like, try lookup by employee ID first.
 	if _, err := uuid.Parse(candidate); err == nil {
 		id := candidate
-		if name := r.lookupEmployeeNameByID(&id); name != "" {
+		if name := r.lookupEmployeeNameByID(ctx, &id); name != "" {
 			return name
 		}
 	}
 
 	// Try lookup by NIK/employee code.
 	nik := candidate
-	return r.lookupEmployeeNameByNIK(&nik, companyID)
+	return r.lookupEmployeeNameByNIK(ctx, &nik, companyID)
 }
 
-func (r *queryResolver) lookupEmployeeNameByID(employeeID *string) string {
+func (r *queryResolver) lookupEmployeeNameByID(ctx context.Context, employeeID *string) string {
 	if employeeID == nil {
 		return ""
 	}
@@ -985,48 +993,26 @@ func (r *queryResolver) lookupEmployeeNameByID(employeeID *string) string {
 		return ""
 	}
 
-	var name string
-	tx := r.db.Table("employees").
-		Select("name").
-		Where("id = ?", id).
-		Limit(1).
-		Scan(&name)
-
-	if tx.Error != nil || tx.RowsAffected == 0 {
+	employee, err := r.loaders(ctx).Employees.Load(ctx, id)
+	if err != nil || employee == nil {
 		return ""
 	}
 
-	return strings.TrimSpace(name)
+	return strings.TrimSpace(employee.Name)
 }
 
-func (r *queryResolver) lookupEmployeeNameByNIK(nik *string, companyID *string) string {
-	if nik == nil {
-		return ""
-	}
-
-	nikValue := strings.TrimSpace(*nik)
-	if nikValue == "" {
+func (r *queryResolver) lookupEmployeeNameByNIK(ctx context.Context, nik *string, companyID *string) string {
+	key, ok := employeeNIKKey(nik, companyID)
+	if !ok {
 		return ""
 	}
 
-	query := r.db.Table("employees").
-		Select("name").
-		Where("nik = ?", nikValue)
-
-	if companyID != nil {
-		companyValue := strings.TrimSpace(*companyID)
-		if companyValue != "" {
-			query = query.Where("company_id = ?", companyValue)
-		}
-	}
-
-	var name string
-	tx := query.Limit(1).Scan(&name)
-	if tx.Error != nil || tx.RowsAffected == 0 {
+	employee, err := r.loaders(ctx).EmployeesByNIK.Load(ctx, key)
+	if err != nil || employee == nil {
 		return ""
 	}
 
-	return strings.TrimSpace(name)
+	return strings.TrimSpace(employee.Name)
 }
 
 func calculateElapsedTime(submittedAt time.Time) string {
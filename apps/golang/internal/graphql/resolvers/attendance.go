@@ -0,0 +1,96 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	gateCheckServices "agrinovagraphql/server/internal/gatecheck/services"
+	"agrinovagraphql/server/internal/graphql/generated"
+	"agrinovagraphql/server/internal/middleware"
+)
+
+// requireAttendanceScope checks that the caller may read attendance of the
+// company.
+func (r *Resolver) requireAttendanceScope(ctx context.Context, companyID string) error {
+	if r.AttendanceService == nil {
+		return fmt.Errorf("attendance service not available")
+	}
+	userID := middleware.GetCurrentUserID(ctx)
+	if userID == "" {
+		return fmt.Errorf("authentication required")
+	}
+	return r.validateCompanyScope(ctx, userID, companyID)
+}
+
+func toGraphQLEmployeeAttendanceDays(rows []*gateCheckServices.EmployeeAttendanceDay) []*generated.EmployeeAttendanceDay {
+	result := make([]*generated.EmployeeAttendanceDay, 0, len(rows))
+	for _, row := range rows {
+		day := row.Day
+		sessions := make([]*generated.AttendanceSession, 0, len(day.Sessions))
+		for _, session := range day.Sessions {
+			sessions = append(sessions, &generated.AttendanceSession{
+				EntryAt:       session.EntryAt,
+				ExitAt:        session.ExitAt,
+				EntryGate:     stringPointerIfNotEmpty(session.EntryGate),
+				ExitGate:      stringPointerIfNotEmpty(session.ExitGate),
+				WorkedMinutes: int32(session.Minutes),
+				Estimated:     session.Estimated,
+			})
+		}
+
+		result = append(result, &generated.EmployeeAttendanceDay{
+			Nik:               day.NIK,
+			EmployeeID:        row.EmployeeID,
+			EmployeeName:      day.Name,
+			DivisionID:        row.DivisionID,
+			DivisionName:      row.DivisionName,
+			Date:              day.WorkDate,
+			Status:            generated.AttendanceStatus(day.Status),
+			FirstEntryAt:      day.FirstEntryAt,
+			LastExitAt:        day.LastExitAt,
+			WorkedMinutes:     int32(day.WorkedMinutes),
+			EstimatedMinutes:  int32(day.EstimatedMinutes),
+			ScheduledMinutes:  int32(day.ScheduledMinutes),
+			LateMinutes:       int32(day.LateMinutes),
+			EarlyLeaveMinutes: int32(day.EarlyLeaveMinutes),
+			OvertimeMinutes:   int32(day.OvertimeMinutes),
+			MissingEntry:      day.MissingEntry,
+			MissingExit:       day.MissingExit,
+			Sessions:          sessions,
+		})
+	}
+	return result
+}
+
+func toGraphQLDivisionAttendance(summaries []*gateCheckServices.DivisionAttendanceSummary) []*generated.DivisionAttendanceSummary {
+	result := make([]*generated.DivisionAttendanceSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, &generated.DivisionAttendanceSummary{
+			DivisionID:      summary.DivisionID,
+			DivisionName:    summary.DivisionName,
+			EmployeeCount:   int32(summary.EmployeeCount),
+			PresentCount:    int32(summary.PresentCount),
+			IncompleteCount: int32(summary.IncompleteCount),
+			AbsentCount:     int32(summary.AbsentCount),
+			LateCount:       int32(summary.LateCount),
+			WorkedMinutes:   int32(summary.WorkedMinutes),
+			OvertimeMinutes: int32(summary.OvertimeMinutes),
+		})
+	}
+	return result
+}
+
+func toGraphQLHarvestGateDiscrepancies(discrepancies []*gateCheckServices.HarvestGateDiscrepancy) []*generated.HarvestGateDiscrepancy {
+	result := make([]*generated.HarvestGateDiscrepancy, 0, len(discrepancies))
+	for _, discrepancy := range discrepancies {
+		result = append(result, &generated.HarvestGateDiscrepancy{
+			Date:             discrepancy.WorkDate,
+			Nik:              discrepancy.NIK,
+			EmployeeID:       discrepancy.EmployeeID,
+			EmployeeName:     discrepancy.EmployeeName,
+			DivisionID:       discrepancy.DivisionID,
+			HarvestRecordIds: discrepancy.HarvestRecordIDs,
+		})
+	}
+	return result
+}
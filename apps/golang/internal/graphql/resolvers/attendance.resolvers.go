@@ -0,0 +1,91 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.83
+
+import (
+	"agrinovagraphql/server/internal/graphql/generated"
+	"context"
+)
+
+// DailyAttendanceReport is the resolver for the dailyAttendanceReport field.
+func (r *queryResolver) DailyAttendanceReport(ctx context.Context, companyID string, date string, divisionID *string) (*generated.DailyAttendanceReport, error) {
+	if err := r.requireAttendanceScope(ctx, companyID); err != nil {
+		return nil, err
+	}
+
+	report, err := r.AttendanceService.DailyReport(ctx, companyID, date, divisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.DailyAttendanceReport{
+		CompanyID:              report.CompanyID,
+		Date:                   report.Date,
+		ShiftStart:             report.ShiftStart,
+		ShiftEnd:               report.ShiftEnd,
+		Timezone:               report.Timezone,
+		Divisions:              toGraphQLDivisionAttendance(report.Divisions),
+		Employees:              toGraphQLEmployeeAttendanceDays(report.Employees),
+		HarvestersWithoutEntry: toGraphQLHarvestGateDiscrepancies(report.HarvestersWithoutEntry),
+	}, nil
+}
+
+// MonthlyAttendanceReport is the resolver for the monthlyAttendanceReport field.
+func (r *queryResolver) MonthlyAttendanceReport(ctx context.Context, companyID string, year int32, month int32, divisionID *string) (*generated.MonthlyAttendanceReport, error) {
+	if err := r.requireAttendanceScope(ctx, companyID); err != nil {
+		return nil, err
+	}
+
+	report, err := r.AttendanceService.MonthlyReport(ctx, companyID, int(year), int(month), divisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	employees := make([]*generated.EmployeeAttendanceSummary, 0, len(report.Employees))
+	for _, summary := range report.Employees {
+		employees = append(employees, &generated.EmployeeAttendanceSummary{
+			Nik:             summary.NIK,
+			EmployeeID:      summary.EmployeeID,
+			EmployeeName:    summary.Name,
+			DivisionID:      summary.DivisionID,
+			DivisionName:    summary.DivisionName,
+			WorkingDays:     int32(summary.WorkingDays),
+			PresentDays:     int32(summary.PresentDays),
+			IncompleteDays:  int32(summary.IncompleteDays),
+			AbsentDays:      int32(summary.AbsentDays),
+			LateDays:        int32(summary.LateDays),
+			WorkedMinutes:   int32(summary.WorkedMinutes),
+			OvertimeMinutes: int32(summary.OvertimeMinutes),
+		})
+	}
+
+	return &generated.MonthlyAttendanceReport{
+		CompanyID:              report.CompanyID,
+		Year:                   int32(report.Year),
+		Month:                  int32(report.Month),
+		DateFrom:               report.DateFrom,
+		DateTo:                 report.DateTo,
+		WorkingDays:            int32(report.WorkingDays),
+		ShiftStart:             report.ShiftStart,
+		ShiftEnd:               report.ShiftEnd,
+		Timezone:               report.Timezone,
+		Divisions:              toGraphQLDivisionAttendance(report.Divisions),
+		Employees:              employees,
+		HarvestersWithoutEntry: toGraphQLHarvestGateDiscrepancies(report.HarvestersWithoutEntry),
+	}, nil
+}
+
+// EmployeeAttendance is the resolver for the employeeAttendance field.
+func (r *queryResolver) EmployeeAttendance(ctx context.Context, companyID string, nik string, dateFrom string, dateTo string) ([]*generated.EmployeeAttendanceDay, error) {
+	if err := r.requireAttendanceScope(ctx, companyID); err != nil {
+		return nil, err
+	}
+
+	days, err := r.AttendanceService.EmployeeAttendance(ctx, companyID, nik, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+	return toGraphQLEmployeeAttendanceDays(days), nil
+}
@@ -11,16 +11,25 @@ import (
 
 // BkmPotongBuahReport is the resolver for the bkmPotongBuahReport field.
 func (r *queryResolver) BkmPotongBuahReport(ctx context.Context, filter bkm.BkmPotongBuahFilter) (*bkm.BkmPotongBuahSummary, error) {
+	ctx, release := r.ReadRouter.UseReplica(ctx)
+	defer release()
+
 	return r.BkmReportService.GetPotongBuahReport(ctx, &filter)
 }
 
 // BkmPotongBuahFlat is the resolver for the bkmPotongBuahFlat field.
 func (r *queryResolver) BkmPotongBuahFlat(ctx context.Context, filter bkm.BkmPotongBuahFilter, page int32, limit int32) (*bkm.BkmPotongBuahFlatResponse, error) {
+	ctx, release := r.ReadRouter.UseReplica(ctx)
+	defer release()
+
 	return r.BkmReportService.GetPotongBuahFlat(ctx, &filter, page, limit)
 }
 
 // BkmPotongBuahAnalytics is the resolver for the bkmPotongBuahAnalytics field.
 func (r *queryResolver) BkmPotongBuahAnalytics(ctx context.Context, filter bkm.BkmPotongBuahFilter, topN *int32) (*bkm.BkmPotongBuahAnalytics, error) {
+	ctx, release := r.ReadRouter.UseReplica(ctx)
+	defer release()
+
 	resolvedTopN := int32(10)
 	if topN != nil {
 		resolvedTopN = *topN
@@ -38,6 +38,14 @@ func (r *blockResolver) LandType(ctx context.Context, obj *master.Block) (*gener
 	return mapLandTypeToGenerated(&landType), nil
 }
 
+func (r *blockResolver) Division(ctx context.Context, obj *master.Block) (*master.Division, error) {
+	if obj.Division != nil {
+		r.loaders(ctx).PrimeDivision(obj.Division)
+		return obj.Division, nil
+	}
+	return loadRequired(ctx, r.loaders(ctx).Divisions, "division", obj.DivisionID)
+}
+
 func (r *blockResolver) HarvestRecords(ctx context.Context, obj *master.Block) ([]*mandor.HarvestRecord, error) {
 	if obj == nil || strings.TrimSpace(obj.ID) == "" {
 		return []*mandor.HarvestRecord{}, nil
@@ -5,11 +5,15 @@ package resolvers
 // Code generated by github.com/99designs/gqlgen version v0.17.83
 
 import (
+	companyModels "agrinovagraphql/server/internal/company/models"
+	companyServices "agrinovagraphql/server/internal/company/services"
 	"agrinovagraphql/server/internal/graphql/domain/auth"
 	"agrinovagraphql/server/internal/graphql/generated"
 	"agrinovagraphql/server/internal/middleware"
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -41,6 +45,14 @@ func (r *mutationResolver) CreateCompanyUser(ctx context.Context, input generate
 		}, nil
 	}
 
+	actor, err := r.companyAdminActor(ctx)
+	if err != nil {
+		return userManagementResult(nil, "", err)
+	}
+	if err := r.CompanyUserAdminService.AuthorizeRole(actor, input.Role); err != nil {
+		return userManagementResult(nil, "", err)
+	}
+
 	// 3. Validate assignment rules based on role
 	switch input.Role {
 	case string(auth.UserRoleManager):
@@ -87,10 +99,13 @@ func (r *mutationResolver) CreateCompanyUser(ctx context.Context, input generate
 		}, nil
 	}
 
+	r.recordCompanyAdminActivity(ctx, actor, companyModels.ActivityUserCreated, companyModels.ActivityEntityUser, user.ID,
+		fmt.Sprintf("Created %s user %s", input.Role, input.Username), nil)
+
 	return &generated.UserManagementResult{
 		Success: true,
 		Message: "User created successfully",
-		User:    r.mapToCompanyUser(user),
+		User:    r.reloadCompanyUser(ctx, requesterCompanyID, user),
 	}, nil
 }
 
@@ -124,7 +139,22 @@ func (r *mutationResolver) UpdateCompanyUser(ctx context.Context, input generate
 		}
 	}
 
-	// 3. Map to Auth domain input
+	// 3. Enforce company scope and role hierarchy on the target user
+	actor, err := r.companyAdminActor(ctx)
+	if err != nil {
+		return userManagementResult(nil, "", err)
+	}
+	target, err := r.CompanyUserAdminService.AuthorizeUser(ctx, actor, input.UserID)
+	if err != nil {
+		return userManagementResult(nil, "", err)
+	}
+	if input.Role != nil {
+		if err := r.CompanyUserAdminService.AuthorizeRole(actor, *input.Role); err != nil {
+			return userManagementResult(nil, "", err)
+		}
+	}
+
+	// 4. Map to Auth domain input
 	var role *auth.UserRole
 	if input.Role != nil {
 		r := auth.UserRole(*input.Role)
@@ -143,7 +173,7 @@ func (r *mutationResolver) UpdateCompanyUser(ctx context.Context, input generate
 		CompanyIDs: []string{requesterCompanyID},
 	}
 
-	// 4. Call AuthResolver
+	// 5. Call AuthResolver
 	user, err := r.AuthResolver.UpdateUser(ctx, updateInput)
 	if err != nil {
 		return &generated.UserManagementResult{
@@ -152,10 +182,34 @@ func (r *mutationResolver) UpdateCompanyUser(ctx context.Context, input generate
 		}, nil
 	}
 
+	companyUser := r.reloadCompanyUser(ctx, requesterCompanyID, user)
+
+	// 6. Audit the change; status changes are also pushed to subscribers
+	activityType := companyModels.ActivityUserUpdated
+	description := fmt.Sprintf("Updated user %s", target.Username)
+	switch {
+	case input.Role != nil && *input.Role != target.Role:
+		activityType = companyModels.ActivityRoleChanged
+		description = fmt.Sprintf("Changed role of %s from %s to %s", target.Username, target.Role, *input.Role)
+	case input.IsActive != nil && *input.IsActive != target.IsActive && *input.IsActive:
+		activityType = companyModels.ActivityUserActivated
+		description = fmt.Sprintf("Activated user %s", target.Username)
+	case input.IsActive != nil && *input.IsActive != target.IsActive:
+		activityType = companyModels.ActivityUserDeactivated
+		description = fmt.Sprintf("Deactivated user %s", target.Username)
+	}
+	r.recordCompanyAdminActivity(ctx, actor, activityType, companyModels.ActivityEntityUser, target.ID, description, nil)
+
+	if input.IsActive != nil && *input.IsActive != target.IsActive {
+		if updated, err := r.CompanyUserAdminService.GetUser(ctx, requesterCompanyID, target.ID); err == nil {
+			r.CompanyUserAdminService.PublishUserStatus(requesterCompanyID, updated)
+		}
+	}
+
 	return &generated.UserManagementResult{
 		Success: true,
 		Message: "User updated successfully",
-		User:    r.mapToCompanyUser(user),
+		User:    companyUser,
 	}, nil
 }
 
@@ -185,92 +239,360 @@ func (r *Resolver) mapToCompanyUser(u *auth.User) *generated.CompanyUser {
 
 // DeleteCompanyUser is the resolver for the deleteCompanyUser field.
 func (r *mutationResolver) DeleteCompanyUser(ctx context.Context, userID string) (*generated.UserManagementResult, error) {
-	panic(fmt.Errorf("not implemented: DeleteCompanyUser - deleteCompanyUser"))
+	actor, err := r.companyAdminActor(ctx)
+	if err != nil {
+		return userManagementResult(nil, "", err)
+	}
+
+	user, err := r.CompanyUserAdminService.DeleteUser(ctx, actor, userID)
+	return userManagementResult(user, "User deleted successfully", err)
 }
 
 // ActivateUser is the resolver for the activateUser field.
 func (r *mutationResolver) ActivateUser(ctx context.Context, userID string) (*generated.UserManagementResult, error) {
-	panic(fmt.Errorf("not implemented: ActivateUser - activateUser"))
+	actor, err := r.companyAdminActor(ctx)
+	if err != nil {
+		return userManagementResult(nil, "", err)
+	}
+
+	user, err := r.CompanyUserAdminService.SetUserActive(ctx, actor, userID, true, nil)
+	return userManagementResult(user, "User activated successfully", err)
 }
 
 // DeactivateUser is the resolver for the deactivateUser field.
 func (r *mutationResolver) DeactivateUser(ctx context.Context, userID string, reason *string) (*generated.UserManagementResult, error) {
-	panic(fmt.Errorf("not implemented: DeactivateUser - deactivateUser"))
+	actor, err := r.companyAdminActor(ctx)
+	if err != nil {
+		return userManagementResult(nil, "", err)
+	}
+
+	user, err := r.CompanyUserAdminService.SetUserActive(ctx, actor, userID, false, reason)
+	return userManagementResult(user, "User deactivated successfully", err)
 }
 
 // AdminResetUserPassword is the resolver for the adminResetUserPassword field.
 func (r *mutationResolver) AdminResetUserPassword(ctx context.Context, userID string, newPassword *string, sendEmail *bool) (*generated.UserManagementResult, error) {
-	panic(fmt.Errorf("not implemented: AdminResetUserPassword - adminResetUserPassword"))
+	actor, err := r.companyAdminActor(ctx)
+	if err != nil {
+		return userManagementResult(nil, "", err)
+	}
+
+	// Without an explicit password, prefer emailing a reset link when the
+	// user has an address; otherwise issue a one-time temporary password.
+	if newPassword == nil || strings.TrimSpace(*newPassword) == "" {
+		target, err := r.CompanyUserAdminService.AuthorizeUser(ctx, actor, userID)
+		if err != nil {
+			return userManagementResult(nil, "", err)
+		}
+
+		if (sendEmail == nil || *sendEmail) && target.Email != nil && strings.TrimSpace(*target.Email) != "" {
+			if _, err := r.AuthResolver.ForgotPassword(ctx, *target.Email); err != nil {
+				return userManagementResult(nil, "", err)
+			}
+			r.recordCompanyAdminActivity(ctx, actor, companyModels.ActivityPasswordReset, companyModels.ActivityEntityUser, target.ID,
+				fmt.Sprintf("Sent password reset link to %s", target.Username), map[string]interface{}{"method": "email"})
+			return userManagementResult(target, "Password reset link sent to the user's email", nil)
+		}
+
+		temporaryPassword, err := generateTemporaryPassword()
+		if err != nil {
+			return nil, err
+		}
+		user, err := r.CompanyUserAdminService.ResetPassword(ctx, actor, userID, temporaryPassword)
+		result, resultErr := userManagementResult(user, "Password reset successfully. Share the temporary password with the user through a secure channel", err)
+		if err == nil && result != nil {
+			result.TemporaryPassword = &temporaryPassword
+		}
+		return result, resultErr
+	}
+
+	user, err := r.CompanyUserAdminService.ResetPassword(ctx, actor, userID, *newPassword)
+	return userManagementResult(user, "Password reset successfully", err)
 }
 
 // UnlockUserAccount is the resolver for the unlockUserAccount field.
 func (r *mutationResolver) UnlockUserAccount(ctx context.Context, userID string) (*generated.UserManagementResult, error) {
-	panic(fmt.Errorf("not implemented: UnlockUserAccount - unlockUserAccount"))
+	actor, err := r.companyAdminActor(ctx)
+	if err != nil {
+		return userManagementResult(nil, "", err)
+	}
+
+	user, err := r.CompanyUserAdminService.UnlockUser(ctx, actor, userID)
+	return userManagementResult(user, "User account unlocked successfully", err)
 }
 
 // AssignUserToEstateAdmin is the resolver for the assignUserToEstateAdmin field.
 func (r *mutationResolver) AssignUserToEstateAdmin(ctx context.Context, userID string, estateID string) (*generated.UserManagementResult, error) {
-	panic(fmt.Errorf("not implemented: AssignUserToEstateAdmin - assignUserToEstateAdmin"))
+	actor, err := r.companyAdminActor(ctx)
+	if err != nil {
+		return userManagementResult(nil, "", err)
+	}
+
+	user, err := r.CompanyUserAdminService.AssignEstate(ctx, actor, userID, estateID)
+	return userManagementResult(user, "User assigned to estate successfully", err)
 }
 
 // RemoveUserFromEstateAdmin is the resolver for the removeUserFromEstateAdmin field.
 func (r *mutationResolver) RemoveUserFromEstateAdmin(ctx context.Context, userID string, estateID string) (*generated.UserManagementResult, error) {
-	panic(fmt.Errorf("not implemented: RemoveUserFromEstateAdmin - removeUserFromEstateAdmin"))
+	actor, err := r.companyAdminActor(ctx)
+	if err != nil {
+		return userManagementResult(nil, "", err)
+	}
+
+	user, err := r.CompanyUserAdminService.RemoveEstate(ctx, actor, userID, estateID)
+	return userManagementResult(user, "User removed from estate successfully", err)
 }
 
 // AssignUserToDivisionAdmin is the resolver for the assignUserToDivisionAdmin field.
 func (r *mutationResolver) AssignUserToDivisionAdmin(ctx context.Context, userID string, divisionID string) (*generated.UserManagementResult, error) {
-	panic(fmt.Errorf("not implemented: AssignUserToDivisionAdmin - assignUserToDivisionAdmin"))
+	actor, err := r.companyAdminActor(ctx)
+	if err != nil {
+		return userManagementResult(nil, "", err)
+	}
+
+	user, err := r.CompanyUserAdminService.AssignDivision(ctx, actor, userID, divisionID)
+	return userManagementResult(user, "User assigned to division successfully", err)
 }
 
 // RemoveUserFromDivisionAdmin is the resolver for the removeUserFromDivisionAdmin field.
 func (r *mutationResolver) RemoveUserFromDivisionAdmin(ctx context.Context, userID string, divisionID string) (*generated.UserManagementResult, error) {
-	panic(fmt.Errorf("not implemented: RemoveUserFromDivisionAdmin - removeUserFromDivisionAdmin"))
+	actor, err := r.companyAdminActor(ctx)
+	if err != nil {
+		return userManagementResult(nil, "", err)
+	}
+
+	user, err := r.CompanyUserAdminService.RemoveDivision(ctx, actor, userID, divisionID)
+	return userManagementResult(user, "User removed from division successfully", err)
 }
 
 // UpdateCompanySettings is the resolver for the updateCompanySettings field.
 func (r *mutationResolver) UpdateCompanySettings(ctx context.Context, input generated.UpdateCompanySettingsInput) (*generated.CompanySettings, error) {
-	panic(fmt.Errorf("not implemented: UpdateCompanySettings - updateCompanySettings"))
+	companyID := middleware.GetCompanyFromContext(ctx)
+	if companyID == "" {
+		return nil, fmt.Errorf("company information missing from context")
+	}
+
+	companyName, err := r.lookupCompanyName(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := r.CompanySettingsService.UpdateSettings(
+		ctx,
+		companyID,
+		middleware.GetCurrentUserID(ctx),
+		mapCompanySettingsInput(input),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if actor, err := r.companyAdminActor(ctx); err == nil {
+		r.recordCompanyAdminActivity(ctx, actor, companyModels.ActivitySettingsChanged, companyModels.ActivityEntityCompany, companyID,
+			"Updated company settings", nil)
+	}
+
+	return mapCompanySettingsToGraphQL(settings, companyName), nil
 }
 
 // CompanyAdminDashboard is the resolver for the companyAdminDashboard field.
 func (r *queryResolver) CompanyAdminDashboard(ctx context.Context) (*generated.CompanyAdminDashboardData, error) {
-	panic(fmt.Errorf("not implemented: CompanyAdminDashboard - companyAdminDashboard"))
+	companyID := middleware.GetCompanyFromContext(ctx)
+	if companyID == "" {
+		return nil, fmt.Errorf("company information missing from context")
+	}
+
+	user, err := r.AuthResolver.Me(ctx)
+	if err != nil {
+		return nil, err
+	}
+	company, err := r.MasterResolver.GetCompany(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := r.CompanyUserAdminService.DashboardStats(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	overview, err := r.CompanyUserAdminService.UserOverview(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	estates, err := r.CompanyUserAdminService.EstateOverviews(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	activities, err := r.CompanyUserAdminService.ListActivities(ctx, companyID, companyModels.AdminActivityFilter{Limit: 10})
+	if err != nil {
+		return nil, err
+	}
+
+	estateOverview := make([]*generated.EstateOverviewData, 0, len(estates))
+	for _, estate := range estates {
+		estateOverview = append(estateOverview, mapEstateOverviewToGraphQL(estate))
+	}
+
+	systemHealth := r.companySystemHealth(ctx, stats.UsersOnlineNow)
+
+	return &generated.CompanyAdminDashboardData{
+		User:    user,
+		Company: company,
+		Stats: &generated.CompanyAdminStats{
+			TotalUsers:        int32(stats.TotalUsers),
+			ActiveUsers:       int32(stats.ActiveUsers),
+			UsersOnlineNow:    int32(stats.UsersOnlineNow),
+			TotalEstates:      int32(stats.TotalEstates),
+			TotalDivisions:    int32(stats.TotalDivisions),
+			TotalBlocks:       int32(stats.TotalBlocks),
+			TotalEmployees:    int32(stats.TotalEmployees),
+			TodayProduction:   stats.TodayProduction,
+			MonthlyProduction: stats.MonthlyProduction,
+		},
+		UserOverview:     mapUserOverviewToGraphQL(overview),
+		EstateOverview:   estateOverview,
+		SystemHealth:     systemHealth,
+		RecentActivities: mapAdminActivitiesToGraphQL(activities),
+	}, nil
 }
 
 // CompanyUsers is the resolver for the companyUsers field.
 func (r *queryResolver) CompanyUsers(ctx context.Context, filter *generated.UserFilterInput) (*generated.CompanyUserListResponse, error) {
-	panic(fmt.Errorf("not implemented: CompanyUsers - companyUsers"))
+	companyID := middleware.GetCompanyFromContext(ctx)
+	if companyID == "" {
+		return nil, fmt.Errorf("company information missing from context")
+	}
+
+	query := companyModels.CompanyUserFilter{}
+	if filter != nil {
+		query.Role = filter.Role
+		query.EstateID = filter.EstateID
+		query.DivisionID = filter.DivisionID
+		query.Search = filter.Search
+		if filter.ActiveOnly != nil {
+			query.ActiveOnly = *filter.ActiveOnly
+		}
+		if filter.Page != nil {
+			query.Page = int(*filter.Page)
+		}
+		if filter.PageSize != nil {
+			query.PageSize = int(*filter.PageSize)
+		}
+	}
+
+	users, total, err := r.CompanyUserAdminService.ListUsers(ctx, companyID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*generated.CompanyUser, 0, len(users))
+	for _, user := range users {
+		result = append(result, mapCompanyUserToGraphQL(user))
+	}
+
+	page, pageSize := companyServices.NormalizeCompanyUserPage(query.Page, query.PageSize)
+
+	return &generated.CompanyUserListResponse{
+		Users:      result,
+		TotalCount: int32(total),
+		HasMore:    int64(page*pageSize) < total,
+	}, nil
 }
 
 // CompanyUser is the resolver for the companyUser field.
 func (r *queryResolver) CompanyUser(ctx context.Context, userID string) (*generated.CompanyUser, error) {
-	panic(fmt.Errorf("not implemented: CompanyUser - companyUser"))
+	companyID := middleware.GetCompanyFromContext(ctx)
+	if companyID == "" {
+		return nil, fmt.Errorf("company information missing from context")
+	}
+
+	user, err := r.CompanyUserAdminService.GetUser(ctx, companyID, userID)
+	if errors.Is(err, companyServices.ErrUserNotInCompany) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mapCompanyUserToGraphQL(user), nil
 }
 
 // CompanySettings is the resolver for the companySettings field.
 func (r *queryResolver) CompanySettings(ctx context.Context) (*generated.CompanySettings, error) {
-	panic(fmt.Errorf("not implemented: CompanySettings - companySettings"))
+	companyID := middleware.GetCompanyFromContext(ctx)
+	if companyID == "" {
+		return nil, fmt.Errorf("company information missing from context")
+	}
+
+	companyName, err := r.lookupCompanyName(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := r.CompanySettingsService.GetSettings(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapCompanySettingsToGraphQL(settings, companyName), nil
 }
 
 // AdminActivityLogs is the resolver for the adminActivityLogs field.
 func (r *queryResolver) AdminActivityLogs(ctx context.Context, activityType *generated.AdminActivityType, userID *string, dateFrom *time.Time, dateTo *time.Time, limit *int32) ([]*generated.AdminActivityLog, error) {
-	panic(fmt.Errorf("not implemented: AdminActivityLogs - adminActivityLogs"))
+	companyID := middleware.GetCompanyFromContext(ctx)
+	if companyID == "" {
+		return nil, fmt.Errorf("company information missing from context")
+	}
+
+	filter := companyModels.AdminActivityFilter{
+		UserID:   userID,
+		DateFrom: dateFrom,
+		DateTo:   dateTo,
+	}
+	if activityType != nil {
+		value := activityType.String()
+		filter.Type = &value
+	}
+	if limit != nil {
+		filter.Limit = int(*limit)
+	}
+
+	entries, err := r.CompanyUserAdminService.ListActivities(ctx, companyID, filter)
+	if err != nil {
+		return nil, err
+	}
+	return mapAdminActivitiesToGraphQL(entries), nil
 }
 
 // UserStatistics is the resolver for the userStatistics field.
 func (r *queryResolver) UserStatistics(ctx context.Context) (*generated.UserOverview, error) {
-	panic(fmt.Errorf("not implemented: UserStatistics - userStatistics"))
+	companyID := middleware.GetCompanyFromContext(ctx)
+	if companyID == "" {
+		return nil, fmt.Errorf("company information missing from context")
+	}
+
+	overview, err := r.CompanyUserAdminService.UserOverview(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return mapUserOverviewToGraphQL(overview), nil
 }
 
 // UserStatusChange is the resolver for the userStatusChange field.
 func (r *subscriptionResolver) UserStatusChange(ctx context.Context) (<-chan *generated.CompanyUser, error) {
-	panic(fmt.Errorf("not implemented: UserStatusChange - userStatusChange"))
+	companyID := middleware.GetCompanyFromContext(ctx)
+	if companyID == "" {
+		return nil, fmt.Errorf("company information missing from context")
+	}
+	return globalCompanyAdminSubscriptionHub.subscribeUserStatus(ctx, companyID), nil
 }
 
 // NewAdminActivity is the resolver for the newAdminActivity field.
 func (r *subscriptionResolver) NewAdminActivity(ctx context.Context) (<-chan *generated.AdminActivityLog, error) {
-	panic(fmt.Errorf("not implemented: NewAdminActivity - newAdminActivity"))
+	companyID := middleware.GetCompanyFromContext(ctx)
+	if companyID == "" {
+		return nil, fmt.Errorf("company information missing from context")
+	}
+	return globalCompanyAdminSubscriptionHub.subscribeActivity(ctx, companyID), nil
 }
 
 // SystemHealthChange is the resolver for the systemHealthChange field.
@@ -0,0 +1,173 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	companyModels "agrinovagraphql/server/internal/company/models"
+	"agrinovagraphql/server/internal/graphql/generated"
+	"agrinovagraphql/server/internal/pubsub"
+)
+
+type companyUserSubscriberSet map[chan *generated.CompanyUser]struct{}
+type adminActivitySubscriberSet map[chan *generated.AdminActivityLog]struct{}
+
+// companyAdminSubscriptionHub fans out user status changes and admin activity
+// to COMPANY_ADMIN subscribers of the same company.
+type companyAdminSubscriptionHub struct {
+	mu sync.RWMutex
+
+	userStatusSubscribers map[string]companyUserSubscriberSet
+	activitySubscribers   map[string]adminActivitySubscriberSet
+
+	events hubBus
+}
+
+// companyUserStatusEvent and adminActivityEvent carry the already mapped
+// GraphQL payload with the company it is scoped to.
+type companyUserStatusEvent struct {
+	CompanyID string
+	User      *generated.CompanyUser
+}
+
+type adminActivityEvent struct {
+	CompanyID string
+	Entry     *generated.AdminActivityLog
+}
+
+func newCompanyAdminSubscriptionHub() *companyAdminSubscriptionHub {
+	h := &companyAdminSubscriptionHub{
+		userStatusSubscribers: make(map[string]companyUserSubscriberSet),
+		activitySubscribers:   make(map[string]adminActivitySubscriberSet),
+	}
+	h.attach(pubsub.NewMemory())
+	return h
+}
+
+func (h *companyAdminSubscriptionHub) attach(bus pubsub.PubSub) {
+	h.events.attach(bus, func(bus pubsub.PubSub) []func() {
+		return []func(){
+			subscribeHubEvent(&h.events, bus, topicCompanyUserStatus, h.deliverUserStatus),
+			subscribeHubEvent(&h.events, bus, topicCompanyAdminActivity, h.deliverActivity),
+		}
+	})
+}
+
+var globalCompanyAdminSubscriptionHub = newCompanyAdminSubscriptionHub()
+
+func (h *companyAdminSubscriptionHub) subscribeUserStatus(ctx context.Context, companyID string) <-chan *generated.CompanyUser {
+	ch := make(chan *generated.CompanyUser, 16)
+	companyID = strings.TrimSpace(companyID)
+	if companyID == "" {
+		close(ch)
+		return ch
+	}
+
+	h.mu.Lock()
+	if h.userStatusSubscribers[companyID] == nil {
+		h.userStatusSubscribers[companyID] = make(companyUserSubscriberSet)
+	}
+	h.userStatusSubscribers[companyID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		h.mu.Lock()
+		subscribers := h.userStatusSubscribers[companyID]
+		delete(subscribers, ch)
+		if len(subscribers) == 0 {
+			delete(h.userStatusSubscribers, companyID)
+		}
+		h.mu.Unlock()
+		close(ch)
+	}()
+
+	return ch
+}
+
+func (h *companyAdminSubscriptionHub) subscribeActivity(ctx context.Context, companyID string) <-chan *generated.AdminActivityLog {
+	ch := make(chan *generated.AdminActivityLog, 16)
+	companyID = strings.TrimSpace(companyID)
+	if companyID == "" {
+		close(ch)
+		return ch
+	}
+
+	h.mu.Lock()
+	if h.activitySubscribers[companyID] == nil {
+		h.activitySubscribers[companyID] = make(adminActivitySubscriberSet)
+	}
+	h.activitySubscribers[companyID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		h.mu.Lock()
+		subscribers := h.activitySubscribers[companyID]
+		delete(subscribers, ch)
+		if len(subscribers) == 0 {
+			delete(h.activitySubscribers, companyID)
+		}
+		h.mu.Unlock()
+		close(ch)
+	}()
+
+	return ch
+}
+
+// PublishUserStatusChange implements companyServices.UserAdminEventPublisher.
+func (h *companyAdminSubscriptionHub) PublishUserStatusChange(companyID string, user *companyModels.CompanyUser) {
+	if user == nil {
+		return
+	}
+	publishHubEvent(&h.events, topicCompanyUserStatus, companyUserStatusEvent{
+		CompanyID: strings.TrimSpace(companyID),
+		User:      mapCompanyUserToGraphQL(user),
+	}, h.deliverUserStatus)
+}
+
+// PublishAdminActivity implements companyServices.UserAdminEventPublisher.
+func (h *companyAdminSubscriptionHub) PublishAdminActivity(entry *companyModels.AdminActivityLog) {
+	if entry == nil {
+		return
+	}
+	publishHubEvent(&h.events, topicCompanyAdminActivity, adminActivityEvent{
+		CompanyID: strings.TrimSpace(entry.CompanyID),
+		Entry:     mapAdminActivityToGraphQL(entry),
+	}, h.deliverActivity)
+}
+
+func (h *companyAdminSubscriptionHub) deliverUserStatus(event companyUserStatusEvent) {
+	if event.User == nil {
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.userStatusSubscribers[event.CompanyID] {
+		select {
+		case ch <- event.User:
+		default:
+			// Keep mutation path non-blocking for slow subscribers.
+		}
+	}
+}
+
+func (h *companyAdminSubscriptionHub) deliverActivity(event adminActivityEvent) {
+	if event.Entry == nil {
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch := range h.activitySubscribers[event.CompanyID] {
+		select {
+		case ch <- event.Entry:
+		default:
+			// Keep mutation path non-blocking for slow subscribers.
+		}
+	}
+}
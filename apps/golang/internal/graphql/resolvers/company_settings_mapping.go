@@ -0,0 +1,112 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+
+	companyModels "agrinovagraphql/server/internal/company/models"
+	companyServices "agrinovagraphql/server/internal/company/services"
+	"agrinovagraphql/server/internal/graphql/generated"
+)
+
+func (r *Resolver) lookupCompanyName(ctx context.Context, companyID string) (string, error) {
+	var names []string
+	if err := r.db.WithContext(ctx).
+		Table("companies").
+		Where("id = ?", companyID).
+		Limit(1).
+		Pluck("name", &names).Error; err != nil {
+		return "", fmt.Errorf("failed to load company: %w", err)
+	}
+	if len(names) == 0 {
+		return "", fmt.Errorf("company not found")
+	}
+	return names[0], nil
+}
+
+func mapCompanySettingsToGraphQL(settings *companyModels.CompanySettings, companyName string) *generated.CompanySettings {
+	return &generated.CompanySettings{
+		CompanyID: settings.CompanyID,
+		General: &generated.GeneralSettings{
+			CompanyName: companyName,
+			Timezone:    settings.Timezone,
+			DateFormat:  settings.DateFormat,
+			Currency:    settings.Currency,
+			Language:    settings.Language,
+		},
+		Notifications: &generated.NotificationSettings{
+			EmailEnabled:       settings.EmailEnabled,
+			PushEnabled:        settings.PushEnabled,
+			SmsEnabled:         settings.SmsEnabled,
+			DailyReportEnabled: settings.DailyReportEnabled,
+			AlertThresholds: &generated.AlertThresholds{
+				ProductionBelowTarget: settings.ProductionBelowTarget,
+				QualityBelowThreshold: settings.QualityBelowThreshold,
+				PendingApprovalHours:  int32(settings.PendingApprovalHours),
+			},
+		},
+		Security: &generated.SecuritySettings{
+			SessionTimeout:     int32(settings.SessionTimeoutMinutes),
+			MaxFailedLogins:    int32(settings.MaxFailedLogins),
+			PasswordExpiryDays: int32(settings.PasswordExpiryDays),
+			TwoFactorRequired:  settings.TwoFactorRequired,
+			AllowedIPRanges:    settings.AllowedIPRanges(),
+		},
+		Operational: &generated.OperationalSettings{
+			DefaultShiftStart:      settings.DefaultShiftStart,
+			DefaultShiftEnd:        settings.DefaultShiftEnd,
+			AutoApproveThreshold:   settings.AutoApproveThreshold,
+			RequireGpsForHarvest:   settings.RequireGpsForHarvest,
+			RequirePhotoForHarvest: settings.RequirePhotoForHarvest,
+		},
+	}
+}
+
+func mapCompanySettingsInput(input generated.UpdateCompanySettingsInput) *companyServices.CompanySettingsUpdate {
+	update := &companyServices.CompanySettingsUpdate{}
+
+	if general := input.General; general != nil {
+		update.Timezone = general.Timezone
+		update.DateFormat = general.DateFormat
+		update.Currency = general.Currency
+		update.Language = general.Language
+	}
+
+	if notifications := input.Notifications; notifications != nil {
+		update.EmailEnabled = notifications.EmailEnabled
+		update.PushEnabled = notifications.PushEnabled
+		update.SmsEnabled = notifications.SmsEnabled
+		update.DailyReportEnabled = notifications.DailyReportEnabled
+		if thresholds := notifications.AlertThresholds; thresholds != nil {
+			update.ProductionBelowTarget = thresholds.ProductionBelowTarget
+			update.QualityBelowThreshold = thresholds.QualityBelowThreshold
+			update.PendingApprovalHours = int32PtrToIntPtr(thresholds.PendingApprovalHours)
+		}
+	}
+
+	if security := input.Security; security != nil {
+		update.SessionTimeoutMinutes = int32PtrToIntPtr(security.SessionTimeout)
+		update.MaxFailedLogins = int32PtrToIntPtr(security.MaxFailedLogins)
+		update.PasswordExpiryDays = int32PtrToIntPtr(security.PasswordExpiryDays)
+		update.TwoFactorRequired = security.TwoFactorRequired
+		update.AllowedIPRanges = security.AllowedIPRanges
+	}
+
+	if operational := input.Operational; operational != nil {
+		update.DefaultShiftStart = operational.DefaultShiftStart
+		update.DefaultShiftEnd = operational.DefaultShiftEnd
+		update.AutoApproveThreshold = operational.AutoApproveThreshold
+		update.RequireGpsForHarvest = operational.RequireGpsForHarvest
+		update.RequirePhotoForHarvest = operational.RequirePhotoForHarvest
+	}
+
+	return update
+}
+
+func int32PtrToIntPtr(value *int32) *int {
+	if value == nil {
+		return nil
+	}
+	converted := int(*value)
+	return &converted
+}
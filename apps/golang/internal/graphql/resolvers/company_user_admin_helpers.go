@@ -0,0 +1,250 @@
+package resolvers
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	companyModels "agrinovagraphql/server/internal/company/models"
+	companyServices "agrinovagraphql/server/internal/company/services"
+	"agrinovagraphql/server/internal/graphql/domain/auth"
+	"agrinovagraphql/server/internal/graphql/generated"
+	"agrinovagraphql/server/internal/middleware"
+)
+
+const temporaryPasswordLength = 12
+
+// temporaryPasswordAlphabet omits look-alike characters (0/O, 1/l/I).
+const temporaryPasswordAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnpqrstuvwxyz23456789"
+
+// companyAdminActor builds the acting admin from the request context.
+func (r *Resolver) companyAdminActor(ctx context.Context) (companyServices.UserAdminActor, error) {
+	actor := companyServices.UserAdminActor{
+		UserID:    middleware.GetCurrentUserID(ctx),
+		Role:      middleware.GetUserRoleFromContext(ctx),
+		CompanyID: middleware.GetCompanyFromContext(ctx),
+	}
+	if actor.UserID == "" {
+		return actor, fmt.Errorf("authentication required")
+	}
+	if actor.CompanyID == "" {
+		return actor, fmt.Errorf("Unauthorized: Company information missing from context")
+	}
+
+	var names []string
+	if err := r.db.WithContext(ctx).
+		Table("users").
+		Where("id = ?", actor.UserID).
+		Limit(1).
+		Pluck("name", &names).Error; err != nil {
+		return actor, fmt.Errorf("failed to load current user: %w", err)
+	}
+	if len(names) > 0 {
+		actor.Name = names[0]
+	}
+	if ip, ok := ctx.Value("client_ip").(string); ok {
+		actor.IPAddress = strings.TrimSpace(ip)
+	}
+	return actor, nil
+}
+
+func mapCompanyUserToGraphQL(u *companyModels.CompanyUser) *generated.CompanyUser {
+	if u == nil {
+		return nil
+	}
+
+	return &generated.CompanyUser{
+		ID:        u.ID,
+		Username:  u.Username,
+		Email:     u.Email,
+		Phone:     u.Phone,
+		FullName:  u.Name,
+		Role:      u.Role,
+		IsActive:  u.IsActive,
+		IsOnline:  u.IsOnline,
+		LastLogin: u.LastLogin,
+		Assignments: &auth.UserAssignmentSummary{
+			EstateNames:   u.EstateNames,
+			DivisionNames: u.DivisionNames,
+		},
+		CreatedAt: u.CreatedAt,
+	}
+}
+
+func mapAdminActivityToGraphQL(entry *companyModels.AdminActivityLog) *generated.AdminActivityLog {
+	if entry == nil {
+		return nil
+	}
+
+	return &generated.AdminActivityLog{
+		ID:          entry.ID,
+		Type:        generated.AdminActivityType(entry.Type),
+		ActorID:     entry.ActorID,
+		ActorName:   entry.ActorName,
+		Description: entry.Description,
+		EntityType:  entry.EntityType,
+		EntityID:    entry.EntityID,
+		IPAddress:   entry.IPAddress,
+		Timestamp:   entry.CreatedAt,
+	}
+}
+
+func mapAdminActivitiesToGraphQL(entries []*companyModels.AdminActivityLog) []*generated.AdminActivityLog {
+	result := make([]*generated.AdminActivityLog, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, mapAdminActivityToGraphQL(entry))
+	}
+	return result
+}
+
+func mapUserOverviewToGraphQL(overview *companyModels.CompanyUserOverview) *generated.UserOverview {
+	byRole := make([]*generated.RoleUserCount, 0, len(overview.ByRole))
+	for _, row := range overview.ByRole {
+		byRole = append(byRole, &generated.RoleUserCount{
+			Role:   row.Role,
+			Count:  int32(row.Count),
+			Active: int32(row.Active),
+		})
+	}
+
+	return &generated.UserOverview{
+		Total:            int32(overview.Total),
+		ByRole:           byRole,
+		ActiveToday:      int32(overview.ActiveToday),
+		NewThisMonth:     int32(overview.NewThisMonth),
+		PendingApprovals: int32(overview.PendingApprovals),
+		LockedAccounts:   int32(overview.LockedAccounts),
+	}
+}
+
+func mapEstateOverviewToGraphQL(estate companyModels.EstateOverview) *generated.EstateOverviewData {
+	status := generated.EstateStatusOperational
+	switch {
+	case estate.DivisionsCount == 0:
+		status = generated.EstateStatusOffline
+	case estate.TodayProduction <= 0:
+		status = generated.EstateStatusPartial
+	}
+
+	return &generated.EstateOverviewData{
+		EstateID:        estate.EstateID,
+		EstateName:      estate.EstateName,
+		ManagerName:     estate.ManagerName,
+		DivisionsCount:  int32(estate.DivisionsCount),
+		UsersCount:      int32(estate.UsersCount),
+		TodayProduction: estate.TodayProduction,
+		Status:          status,
+	}
+}
+
+// userManagementResult wraps a company admin user operation. Errors are
+// reported as a failed result, matching createCompanyUser/updateCompanyUser.
+func userManagementResult(user *companyModels.CompanyUser, message string, err error) (*generated.UserManagementResult, error) {
+	if err != nil {
+		return &generated.UserManagementResult{
+			Success: false,
+			Message: err.Error(),
+			Errors:  []string{err.Error()},
+		}, nil
+	}
+
+	return &generated.UserManagementResult{
+		Success: true,
+		Message: message,
+		User:    mapCompanyUserToGraphQL(user),
+	}, nil
+}
+
+// reloadCompanyUser returns the company admin view of a user created or
+// updated through the auth module, falling back to the auth payload.
+func (r *Resolver) reloadCompanyUser(ctx context.Context, companyID string, user *auth.User) *generated.CompanyUser {
+	if user == nil {
+		return nil
+	}
+	if r.CompanyUserAdminService != nil {
+		if companyUser, err := r.CompanyUserAdminService.GetUser(ctx, companyID, user.ID); err == nil {
+			return mapCompanyUserToGraphQL(companyUser)
+		}
+	}
+	return r.mapToCompanyUser(user)
+}
+
+func (r *Resolver) companySystemHealth(ctx context.Context, usersOnline int) *generated.SystemHealthData {
+	health := &generated.SystemHealthData{
+		Status:            generated.SystemStatusHealthy,
+		APIHealth:         true,
+		DatabaseHealth:    true,
+		SyncServiceHealth: true,
+		ActiveConnections: int32(usersOnline),
+	}
+
+	sqlDB, err := r.db.DB()
+	if err == nil {
+		pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		err = sqlDB.PingContext(pingCtx)
+		cancel()
+	}
+	if err != nil {
+		health.DatabaseHealth = false
+		health.SyncServiceHealth = false
+		health.Status = generated.SystemStatusCritical
+	}
+	return health
+}
+
+func generateTemporaryPassword() (string, error) {
+	var builder strings.Builder
+	builder.Grow(temporaryPasswordLength)
+	max := big.NewInt(int64(len(temporaryPasswordAlphabet)))
+	for i := 0; i < temporaryPasswordLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate temporary password: %w", err)
+		}
+		builder.WriteByte(temporaryPasswordAlphabet[n.Int64()])
+	}
+	return builder.String(), nil
+}
+
+// userAssignmentSummaryResolver implements generated.UserAssignmentSummaryResolver.
+type userAssignmentSummaryResolver struct{ *Resolver }
+
+func (r *userAssignmentSummaryResolver) Estates(ctx context.Context, obj *auth.UserAssignmentSummary) ([]string, error) {
+	if obj == nil || obj.EstateNames == nil {
+		return []string{}, nil
+	}
+	return obj.EstateNames, nil
+}
+
+func (r *userAssignmentSummaryResolver) Divisions(ctx context.Context, obj *auth.UserAssignmentSummary) ([]string, error) {
+	if obj == nil || obj.DivisionNames == nil {
+		return []string{}, nil
+	}
+	return obj.DivisionNames, nil
+}
+
+func (r *userAssignmentSummaryResolver) PksAssignment(ctx context.Context, obj *auth.UserAssignmentSummary) (*string, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	return obj.PksName, nil
+}
+
+// recordCompanyAdminActivity writes an activity entry for a change that was
+// already committed; failures are logged instead of failing the mutation.
+func (r *Resolver) recordCompanyAdminActivity(
+	ctx context.Context,
+	actor companyServices.UserAdminActor,
+	activityType, entityType, entityID, description string,
+	metadata map[string]interface{},
+) {
+	if r.CompanyUserAdminService == nil {
+		return
+	}
+	if _, err := r.CompanyUserAdminService.RecordActivity(ctx, actor, activityType, entityType, entityID, description, metadata); err != nil {
+		fmt.Printf("⚠️ failed to record admin activity %s for company %s: %v\n", activityType, actor.CompanyID, err)
+	}
+}
@@ -0,0 +1,104 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"agrinovagraphql/server/internal/graphql/dataloader"
+	"agrinovagraphql/server/internal/graphql/domain/mandor"
+)
+
+// loaders returns the request-scoped dataloaders installed by
+// dataloader.Middleware, or a fresh set outside an HTTP request.
+func (r *Resolver) loaders(ctx context.Context) *dataloader.Loaders {
+	return dataloader.For(ctx, r.db)
+}
+
+// loadRequired loads a non-null relation and turns a missing (or out of
+// scope) row into a field error instead of a nil for a non-null field.
+func loadRequired[V any](ctx context.Context, loader *dataloader.Loader[string, *V], kind, id string) (*V, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, fmt.Errorf("%s not set", kind)
+	}
+	value, err := loader.Load(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", kind, err)
+	}
+	if value == nil {
+		return nil, fmt.Errorf("%s not found: %s", kind, id)
+	}
+	return value, nil
+}
+
+// preloadApprovalRelations batches the mandor, block, division and employee
+// lookups for a page of approval items, so the per-item conversion only
+// reads the loader cache. Failures are left to the per-item lookups.
+func (r *queryResolver) preloadApprovalRelations(ctx context.Context, records []*mandor.HarvestRecord) {
+	if len(records) == 0 {
+		return
+	}
+
+	loaders := r.loaders(ctx)
+	var mandorIDs, blockIDs, employeeIDs []string
+	var employeeNIKs []dataloader.EmployeeNIKKey
+	seen := make(map[string]bool)
+	collect := func(list *[]string, kind, id string) {
+		id = strings.TrimSpace(id)
+		if id != "" && !seen[kind+id] {
+			seen[kind+id] = true
+			*list = append(*list, id)
+		}
+	}
+
+	for _, record := range records {
+		collect(&mandorIDs, "mandor", record.MandorID)
+		collect(&blockIDs, "block", record.BlockID)
+		if record.KaryawanID != nil {
+			collect(&employeeIDs, "employee", *record.KaryawanID)
+		}
+		if key, ok := employeeNIKKey(record.Nik, record.CompanyID); ok && !seen["nik"+key.CompanyID+"|"+key.NIK] {
+			seen["nik"+key.CompanyID+"|"+key.NIK] = true
+			employeeNIKs = append(employeeNIKs, key)
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		_, _ = loaders.Users.LoadMany(ctx, mandorIDs)
+	}()
+	go func() {
+		defer wg.Done()
+		blocks, err := loaders.Blocks.LoadMany(ctx, blockIDs)
+		if err != nil {
+			return
+		}
+		var divisionIDs []string
+		for _, block := range blocks {
+			if block != nil {
+				collect(&divisionIDs, "division", block.DivisionID)
+			}
+		}
+		_, _ = loaders.Divisions.LoadMany(ctx, divisionIDs)
+	}()
+	go func() {
+		defer wg.Done()
+		_, _ = loaders.Employees.LoadMany(ctx, employeeIDs)
+		_, _ = loaders.EmployeesByNIK.LoadMany(ctx, employeeNIKs)
+	}()
+	wg.Wait()
+}
+
+func employeeNIKKey(nik, companyID *string) (dataloader.EmployeeNIKKey, bool) {
+	if nik == nil || strings.TrimSpace(*nik) == "" {
+		return dataloader.EmployeeNIKKey{}, false
+	}
+	key := dataloader.EmployeeNIKKey{NIK: strings.TrimSpace(*nik)}
+	if companyID != nil {
+		key.CompanyID = strings.TrimSpace(*companyID)
+	}
+	return key, true
+}
@@ -0,0 +1,139 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"agrinovagraphql/server/internal/gatecheck/models"
+	gateCheckServices "agrinovagraphql/server/internal/gatecheck/services"
+	"agrinovagraphql/server/internal/graphql/domain/satpam"
+	notificationModels "agrinovagraphql/server/internal/notifications/models"
+	schedulerServices "agrinovagraphql/server/internal/scheduler/services"
+)
+
+// gateOverstayCompanyScope resolves the company an overstay rule request applies to.
+func (r *Resolver) gateOverstayCompanyScope(ctx context.Context, requested *string) (string, error) {
+	if r.GateOverstayService == nil {
+		return "", fmt.Errorf("gate overstay service not available")
+	}
+	return r.gateCompanyScope(ctx, requested)
+}
+
+// gateOverstayCompanyScopeForRule resolves the company of an existing rule
+// and checks the caller may manage it.
+func (r *Resolver) gateOverstayCompanyScopeForRule(ctx context.Context, ruleID string) (string, error) {
+	var companyIDs []string
+	if err := r.db.WithContext(ctx).Model(&models.GateOverstayRule{}).Where("id = ?", ruleID).Pluck("company_id", &companyIDs).Error; err != nil {
+		return "", err
+	}
+	if len(companyIDs) == 0 {
+		return "", gateCheckServices.ErrOverstayRuleNotFound
+	}
+	return r.gateOverstayCompanyScope(ctx, &companyIDs[0])
+}
+
+// raiseSatpamOverstayAlert publishes a newly overstayed vehicle and notifies
+// the company managers.
+func (r *Resolver) raiseSatpamOverstayAlert(ctx context.Context, record *satpam.SatpamGuestLog, guestLog *gateCheckServices.GuestLog) {
+	alert := buildSatpamOverstayAlert(record)
+	applySatpamOverstayState(alert, guestLog)
+	publishSatpamOverstayAlert(alert)
+
+	if err := (&mutationResolver{r}).persistSatpamOverstayNotification(ctx, record); err != nil {
+		log.Printf("failed to persist satpam overstay notification: %v", err)
+	}
+}
+
+// resolveSatpamOverstayAlert publishes the overstay as resolved and marks the
+// unread overstay notifications of the guest log as read.
+func (r *Resolver) resolveSatpamOverstayAlert(ctx context.Context, record *satpam.SatpamGuestLog, resolvedAt *time.Time) {
+	alert := buildSatpamOverstayAlert(record)
+	if alert == nil {
+		return
+	}
+	if resolvedAt == nil {
+		now := time.Now()
+		resolvedAt = &now
+	}
+	alert.OverstayResolvedAt = resolvedAt
+	publishSatpamOverstayAlert(alert)
+
+	if err := r.markSatpamOverstayNotificationsRead(ctx, getSatpamRecordEntityID(record)); err != nil {
+		log.Printf("failed to resolve satpam overstay notifications: %v", err)
+	}
+}
+
+func (r *Resolver) markSatpamOverstayNotificationsRead(ctx context.Context, entityID string) error {
+	if r.NotificationService == nil || strings.TrimSpace(entityID) == "" {
+		return nil
+	}
+
+	var notifications []notificationModels.Notification
+	if err := r.db.WithContext(ctx).
+		Where("type = ? AND idempotency_key = ? AND status = ?",
+			notificationModels.NotificationTypeGateCheckAlert,
+			fmt.Sprintf("satpam:overstay:%s", entityID),
+			notificationModels.NotificationStatusUnread).
+		Find(&notifications).Error; err != nil {
+		return fmt.Errorf("failed to load overstay notifications: %w", err)
+	}
+
+	for _, notification := range notifications {
+		if _, err := r.NotificationService.MarkAsRead(ctx, notification.ID, notification.RecipientID); err != nil {
+			return fmt.Errorf("failed to mark overstay notification %s as read: %w", notification.ID, err)
+		}
+	}
+	return nil
+}
+
+func applySatpamOverstayState(alert *satpam.VehicleInsideInfo, guestLog *gateCheckServices.GuestLog) {
+	if alert == nil || guestLog == nil {
+		return
+	}
+	if guestLog.OverstayLimitMinutes != nil {
+		limit := int32(*guestLog.OverstayLimitMinutes)
+		alert.MaxStayMinutes = &limit
+	}
+	alert.OverstayDetectedAt = guestLog.OverstayDetectedAt
+	alert.OverstayResolvedAt = guestLog.OverstayResolvedAt
+}
+
+// runGateOverstayDetectionJob marks guest vehicles that passed their company
+// stay limit and resolves overstays whose vehicle left through a synced or
+// stateless exit.
+func (r *Resolver) runGateOverstayDetectionJob(ctx context.Context, run schedulerServices.JobContext) (string, error) {
+	if r.GateOverstayService == nil || r.GateCheckService == nil {
+		return "skipped: gate check service is not configured", nil
+	}
+
+	now := time.Now()
+	detected, resolved := 0, 0
+	var failures []string
+	for _, companyID := range run.CompanyIDs {
+		result, err := r.GateOverstayService.Detect(ctx, companyID, now)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", companyID, err))
+		}
+		if result == nil {
+			continue
+		}
+
+		for _, guestLog := range result.Detected {
+			r.raiseSatpamOverstayAlert(ctx, r.GateCheckService.ConvertToSatpamGuestLog(guestLog), guestLog)
+		}
+		for _, guestLog := range result.Resolved {
+			r.resolveSatpamOverstayAlert(ctx, r.GateCheckService.ConvertToSatpamGuestLog(guestLog), guestLog.OverstayResolvedAt)
+		}
+		detected += len(result.Detected)
+		resolved += len(result.Resolved)
+	}
+
+	message := fmt.Sprintf("%d overstay(s) detected and %d resolved across %d company(ies)", detected, resolved, len(run.CompanyIDs))
+	if len(failures) > 0 {
+		return message, fmt.Errorf("%d company(ies) failed: %s", len(failures), strings.Join(failures, "; "))
+	}
+	return message, nil
+}
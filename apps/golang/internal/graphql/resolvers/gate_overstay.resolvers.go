@@ -0,0 +1,82 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.83
+
+import (
+	"agrinovagraphql/server/internal/gatecheck/models"
+	"agrinovagraphql/server/internal/graphql/generated"
+	"agrinovagraphql/server/internal/middleware"
+	"context"
+	"time"
+)
+
+// UpsertGateOverstayRule is the resolver for the upsertGateOverstayRule field.
+func (r *mutationResolver) UpsertGateOverstayRule(ctx context.Context, input generated.UpsertGateOverstayRuleInput) (*models.GateOverstayRule, error) {
+	userID := middleware.GetCurrentUserID(ctx)
+
+	if input.ID == nil || *input.ID == "" {
+		companyID, err := r.gateOverstayCompanyScope(ctx, input.CompanyID)
+		if err != nil {
+			return nil, err
+		}
+
+		rule := &models.GateOverstayRule{
+			CompanyID:      companyID,
+			VehicleType:    input.VehicleType,
+			Destination:    input.Destination,
+			MaxStayMinutes: input.MaxStayMinutes,
+			IsActive:       input.IsActive == nil || *input.IsActive,
+			CreatedBy:      userID,
+		}
+		if err := r.GateOverstayService.SaveRule(ctx, rule); err != nil {
+			return nil, err
+		}
+		return rule, nil
+	}
+
+	companyID, err := r.gateOverstayCompanyScopeForRule(ctx, *input.ID)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := r.GateOverstayService.GetRule(ctx, companyID, *input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.VehicleType = input.VehicleType
+	rule.Destination = input.Destination
+	rule.MaxStayMinutes = input.MaxStayMinutes
+	if input.IsActive != nil {
+		rule.IsActive = *input.IsActive
+	}
+	rule.UpdatedBy = &userID
+	rule.UpdatedAt = time.Now()
+
+	if err := r.GateOverstayService.SaveRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteGateOverstayRule is the resolver for the deleteGateOverstayRule field.
+func (r *mutationResolver) DeleteGateOverstayRule(ctx context.Context, id string) (bool, error) {
+	companyID, err := r.gateOverstayCompanyScopeForRule(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if err := r.GateOverstayService.DeleteRule(ctx, companyID, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GateOverstayRules is the resolver for the gateOverstayRules field.
+func (r *queryResolver) GateOverstayRules(ctx context.Context, companyID *string) ([]*models.GateOverstayRule, error) {
+	scopedCompanyID, err := r.gateOverstayCompanyScope(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return r.GateOverstayService.ListRules(ctx, scopedCompanyID)
+}
@@ -0,0 +1,191 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"agrinovagraphql/server/internal/gatecheck/models"
+	gateCheckServices "agrinovagraphql/server/internal/gatecheck/services"
+	"agrinovagraphql/server/internal/graphql/domain/auth"
+	"agrinovagraphql/server/internal/graphql/domain/satpam"
+	"agrinovagraphql/server/internal/middleware"
+	notificationModels "agrinovagraphql/server/internal/notifications/models"
+	notificationServices "agrinovagraphql/server/internal/notifications/services"
+)
+
+// gateWatchlistCompanyScope resolves the company a watchlist request applies to.
+func (r *Resolver) gateWatchlistCompanyScope(ctx context.Context, requested *string) (string, error) {
+	if r.GateWatchlistService == nil {
+		return "", fmt.Errorf("gate watchlist service not available")
+	}
+	return r.gateCompanyScope(ctx, requested)
+}
+
+// gateCompanyScope resolves the company a gate settings request applies to.
+// Super admins must name the company; everyone else defaults to the company
+// in context and may only target companies they are assigned to.
+func (r *Resolver) gateCompanyScope(ctx context.Context, requested *string) (string, error) {
+	requestedID := ""
+	if requested != nil {
+		requestedID = strings.TrimSpace(*requested)
+	}
+
+	if middleware.GetUserRoleFromContext(ctx) == auth.UserRoleSuperAdmin {
+		if requestedID == "" {
+			return "", fmt.Errorf("companyId is required")
+		}
+		return requestedID, nil
+	}
+
+	companyID := middleware.GetCompanyFromContext(ctx)
+	if requestedID == "" || requestedID == companyID {
+		if companyID == "" {
+			return "", fmt.Errorf("company tidak ditemukan")
+		}
+		return companyID, nil
+	}
+
+	userID := middleware.GetCurrentUserID(ctx)
+	if err := r.validateCompanyScope(ctx, userID, requestedID); err != nil {
+		return "", err
+	}
+	return requestedID, nil
+}
+
+// gateWatchlistCompanyScopeForEntry resolves the company of an existing
+// watchlist entry and checks the caller may manage it.
+func (r *Resolver) gateWatchlistCompanyScopeForEntry(ctx context.Context, entryID string) (string, error) {
+	return r.gateWatchlistCompanyScopeFor(ctx, &models.GateWatchlistEntry{}, entryID, gateCheckServices.ErrWatchlistEntryNotFound)
+}
+
+// gateWatchlistCompanyScopeForHit resolves the company of a watchlist hit and
+// checks the caller may see it.
+func (r *Resolver) gateWatchlistCompanyScopeForHit(ctx context.Context, hitID string) (string, error) {
+	return r.gateWatchlistCompanyScopeFor(ctx, &models.GateWatchlistHit{}, hitID, gateCheckServices.ErrWatchlistHitNotFound)
+}
+
+func (r *Resolver) gateWatchlistCompanyScopeFor(ctx context.Context, model interface{}, id string, notFound error) (string, error) {
+	var companyIDs []string
+	if err := r.db.WithContext(ctx).Model(model).Where("id = ?", id).Pluck("company_id", &companyIDs).Error; err != nil {
+		return "", err
+	}
+	if len(companyIDs) == 0 {
+		return "", notFound
+	}
+	return r.gateWatchlistCompanyScope(ctx, &companyIDs[0])
+}
+
+// gateWatchlistSubscriberCompanies returns the companies whose watchlist
+// alerts the subscriber may receive. Area managers see all assigned
+// companies; nil means every company.
+func (r *Resolver) gateWatchlistSubscriberCompanies(ctx context.Context) ([]string, error) {
+	switch middleware.GetUserRoleFromContext(ctx) {
+	case auth.UserRoleSuperAdmin:
+		return nil, nil
+	case auth.UserRoleAreaManager:
+		return r.areaManagerCompanyIDs(ctx, middleware.GetCurrentUserID(ctx))
+	}
+
+	companyID := middleware.GetCompanyFromContext(ctx)
+	if companyID == "" {
+		return nil, fmt.Errorf("company tidak ditemukan")
+	}
+	return []string{companyID}, nil
+}
+
+// raiseGateWatchlistAlert publishes a new watchlist hit to subscribers and
+// notifies the company managers. Reused hits were alerted when recorded.
+func (r *Resolver) raiseGateWatchlistAlert(ctx context.Context, alert *satpam.GateWatchlistAlert) {
+	if alert == nil || !alert.IsNew {
+		return
+	}
+
+	publishSatpamWatchlistAlert(alert)
+	if err := r.persistGateWatchlistNotification(ctx, alert); err != nil {
+		log.Printf("Failed to persist gate watchlist notification for hit %s: %v", alert.HitID, err)
+	}
+}
+
+func (r *Resolver) persistGateWatchlistNotification(ctx context.Context, alert *satpam.GateWatchlistAlert) error {
+	if r.NotificationService == nil {
+		return nil
+	}
+
+	title, priority := gateWatchlistNotificationTitle(alert)
+	message := fmt.Sprintf("%s %s cocok dengan watchlist (%s %s): %s.",
+		alert.VehiclePlate, alert.DriverName, alert.MatchType, alert.MatchedValue, alert.Reason)
+	if alert.RequiresOverride {
+		message += " Menunggu persetujuan supervisor."
+	}
+	metadata := map[string]interface{}{
+		"hitId":         alert.HitID,
+		"source":        string(alert.Source),
+		"status":        string(alert.Status),
+		"severity":      string(alert.Severity),
+		"vehicleNumber": alert.VehiclePlate,
+		"driverName":    alert.DriverName,
+		"intent":        "WATCHLIST",
+	}
+
+	// Company routing rules replace the built-in manager fan-out when configured.
+	if r.routeEvent(ctx, &notificationServices.RoutingEvent{
+		Type:              notificationModels.NotificationTypeGateCheckAlert,
+		Priority:          priority,
+		Title:             title,
+		Message:           message,
+		CompanyID:         alert.CompanyID,
+		ActorID:           middleware.GetCurrentUserID(ctx),
+		ActorRole:         "SATPAM",
+		RelatedEntityType: "GATE_WATCHLIST_HIT",
+		RelatedEntityID:   alert.HitID,
+		ActionURL:         "/dashboard/manager/gate-logs",
+		ActionLabel:       "Lihat Log",
+		Metadata:          metadata,
+		IdempotencyKey:    fmt.Sprintf("gate-watchlist:%s", alert.HitID),
+	}) {
+		return nil
+	}
+
+	recipients, err := r.getSatpamNotificationRecipients(ctx, alert.CompanyID)
+	if err != nil {
+		return err
+	}
+
+	for _, recipient := range recipients {
+		input := &notificationServices.CreateNotificationInput{
+			Type:               notificationModels.NotificationTypeGateCheckAlert,
+			Priority:           priority,
+			Title:              title,
+			Message:            message,
+			IdempotencyKey:     fmt.Sprintf("gate-watchlist:%s", alert.HitID),
+			RecipientID:        recipient.ID,
+			RecipientRole:      string(recipient.Role),
+			RecipientCompanyID: alert.CompanyID,
+			RelatedEntityType:  "GATE_WATCHLIST_HIT",
+			RelatedEntityID:    alert.HitID,
+			ActionURL:          "/dashboard/manager/gate-logs",
+			ActionLabel:        "Lihat Log",
+			Metadata:           metadata,
+			SenderID:           middleware.GetCurrentUserID(ctx),
+			SenderRole:         "SATPAM",
+		}
+
+		if _, err := r.NotificationService.CreateNotification(ctx, input); err != nil {
+			return fmt.Errorf("failed creating watchlist notification for recipient %s: %w", recipient.ID, err)
+		}
+	}
+	return nil
+}
+
+func gateWatchlistNotificationTitle(alert *satpam.GateWatchlistAlert) (string, notificationModels.NotificationPriority) {
+	switch {
+	case alert.Blocked:
+		return "Watchlist: Masuk Ditolak", notificationModels.NotificationPriorityCritical
+	case alert.RequiresOverride:
+		return "Watchlist: Butuh Persetujuan", notificationModels.NotificationPriorityHigh
+	default:
+		return "Watchlist: Tamu Terdeteksi", notificationModels.NotificationPriorityHigh
+	}
+}
@@ -0,0 +1,160 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.83
+
+import (
+	"agrinovagraphql/server/internal/gatecheck/models"
+	gateCheckServices "agrinovagraphql/server/internal/gatecheck/services"
+	"agrinovagraphql/server/internal/graphql/domain/satpam"
+	"agrinovagraphql/server/internal/graphql/generated"
+	"agrinovagraphql/server/internal/middleware"
+	"context"
+	"errors"
+	"time"
+)
+
+// CreateGateWatchlistEntry is the resolver for the createGateWatchlistEntry field.
+func (r *mutationResolver) CreateGateWatchlistEntry(ctx context.Context, input generated.CreateGateWatchlistEntryInput) (*models.GateWatchlistEntry, error) {
+	companyID, err := r.gateWatchlistCompanyScope(ctx, input.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &models.GateWatchlistEntry{
+		CompanyID: companyID,
+		MatchType: input.MatchType,
+		Value:     input.Value,
+		Reason:    input.Reason,
+		Severity:  input.Severity,
+		ExpiresAt: input.ExpiresAt,
+		IsActive:  true,
+		CreatedBy: middleware.GetCurrentUserID(ctx),
+	}
+	if err := r.GateWatchlistService.SaveEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// UpdateGateWatchlistEntry is the resolver for the updateGateWatchlistEntry field.
+func (r *mutationResolver) UpdateGateWatchlistEntry(ctx context.Context, input generated.UpdateGateWatchlistEntryInput) (*models.GateWatchlistEntry, error) {
+	companyID, err := r.gateWatchlistCompanyScopeForEntry(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := r.GateWatchlistService.GetEntry(ctx, companyID, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Value != nil {
+		entry.Value = *input.Value
+	}
+	if input.Reason != nil {
+		entry.Reason = *input.Reason
+	}
+	if input.Severity != nil {
+		entry.Severity = *input.Severity
+	}
+	if input.ExpiresAt != nil {
+		entry.ExpiresAt = input.ExpiresAt
+	}
+	if input.ClearExpiresAt != nil && *input.ClearExpiresAt {
+		entry.ExpiresAt = nil
+	}
+	if input.IsActive != nil {
+		entry.IsActive = *input.IsActive
+	}
+	userID := middleware.GetCurrentUserID(ctx)
+	entry.UpdatedBy = &userID
+	entry.UpdatedAt = time.Now()
+
+	if err := r.GateWatchlistService.SaveEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// DeleteGateWatchlistEntry is the resolver for the deleteGateWatchlistEntry field.
+func (r *mutationResolver) DeleteGateWatchlistEntry(ctx context.Context, id string) (bool, error) {
+	companyID, err := r.gateWatchlistCompanyScopeForEntry(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if err := r.GateWatchlistService.DeleteEntry(ctx, companyID, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ReviewGateWatchlistOverride is the resolver for the reviewGateWatchlistOverride field.
+func (r *mutationResolver) ReviewGateWatchlistOverride(ctx context.Context, input generated.ReviewGateWatchlistOverrideInput) (*satpam.GateWatchlistAlert, error) {
+	companyID, err := r.gateWatchlistCompanyScopeForHit(ctx, input.HitID)
+	if err != nil {
+		return nil, err
+	}
+
+	hit, err := r.GateWatchlistService.ReviewOverride(ctx, companyID, input.HitID, middleware.GetCurrentUserID(ctx), input.Approve, input.Notes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Satpam waiting at the gate learns the decision from the subscription.
+	alert := gateCheckServices.ToGateWatchlistAlert(hit)
+	publishSatpamWatchlistAlert(alert)
+	return alert, nil
+}
+
+// GateWatchlistEntries is the resolver for the gateWatchlistEntries field.
+func (r *queryResolver) GateWatchlistEntries(ctx context.Context, companyID *string, includeInactive *bool) ([]*models.GateWatchlistEntry, error) {
+	scopedCompanyID, err := r.gateWatchlistCompanyScope(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return r.GateWatchlistService.ListEntries(ctx, scopedCompanyID, includeInactive != nil && *includeInactive)
+}
+
+// GateWatchlistHits is the resolver for the gateWatchlistHits field.
+func (r *queryResolver) GateWatchlistHits(ctx context.Context, companyID *string, status *satpam.GateWatchlistHitStatus, limit *int32) ([]*satpam.GateWatchlistAlert, error) {
+	scopedCompanyID, err := r.gateWatchlistCompanyScope(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	maxHits := 0
+	if limit != nil {
+		maxHits = int(*limit)
+	}
+	hits, err := r.GateWatchlistService.ListHits(ctx, scopedCompanyID, status, maxHits)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := make([]*satpam.GateWatchlistAlert, 0, len(hits))
+	for _, hit := range hits {
+		alerts = append(alerts, gateCheckServices.ToGateWatchlistAlert(hit))
+	}
+	return alerts, nil
+}
+
+// GateWatchlistHit is the resolver for the gateWatchlistHit field.
+func (r *queryResolver) GateWatchlistHit(ctx context.Context, id string) (*satpam.GateWatchlistAlert, error) {
+	companyID, err := r.gateWatchlistCompanyScopeForHit(ctx, id)
+	if err != nil {
+		if errors.Is(err, gateCheckServices.ErrWatchlistHitNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	hit, err := r.GateWatchlistService.GetHit(ctx, companyID, id)
+	if err != nil {
+		if errors.Is(err, gateCheckServices.ErrWatchlistHitNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return gateCheckServices.ToGateWatchlistAlert(hit), nil
+}
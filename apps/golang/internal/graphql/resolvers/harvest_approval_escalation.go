@@ -0,0 +1,193 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	companyModels "agrinovagraphql/server/internal/company/models"
+	notificationModels "agrinovagraphql/server/internal/notifications/models"
+	notificationServices "agrinovagraphql/server/internal/notifications/services"
+	schedulerServices "agrinovagraphql/server/internal/scheduler/services"
+)
+
+const harvestApprovalEscalationBatchSize = 200
+
+type harvestApprovalEscalationRecord struct {
+	ID        string    `gorm:"column:id"`
+	EstateID  *string   `gorm:"column:estate_id"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+type harvestApprovalEscalationRecipient struct {
+	ID   string `gorm:"column:id"`
+	Role string `gorm:"column:role"`
+}
+
+// runHarvestApprovalEscalationJob notifies managers about harvest records that
+// stayed PENDING longer than the company's pendingApprovalHours setting.
+func (r *Resolver) runHarvestApprovalEscalationJob(ctx context.Context, run schedulerServices.JobContext) (string, error) {
+	if r.NotificationService == nil || r.CompanySettingsService == nil {
+		return "skipped: notification service is not configured", nil
+	}
+	if !r.db.WithContext(ctx).Migrator().HasTable(&companyModels.CompanySettings{}) {
+		return "skipped: company settings are not migrated", nil
+	}
+
+	settingsList, err := r.CompanySettingsService.ListPendingApprovalEscalations(ctx)
+	if err != nil {
+		return "", err
+	}
+	covered := make(map[string]struct{}, len(run.CompanyIDs))
+	for _, companyID := range run.CompanyIDs {
+		covered[companyID] = struct{}{}
+	}
+
+	escalated, companies := 0, 0
+	var failures []string
+	for _, settings := range settingsList {
+		if _, ok := covered[settings.CompanyID]; !ok {
+			continue
+		}
+		companies++
+		count, err := r.escalatePendingHarvestApprovals(ctx, settings)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", settings.CompanyID, err))
+			continue
+		}
+		escalated += count
+	}
+
+	message := fmt.Sprintf("%d overdue harvest record(s) escalated across %d company(ies) with escalation enabled", escalated, companies)
+	if len(failures) > 0 {
+		return message, fmt.Errorf("%d company(ies) failed: %s", len(failures), strings.Join(failures, "; "))
+	}
+	return message, nil
+}
+
+// escalatePendingHarvestApprovals notifies one company's managers about its
+// overdue records and returns how many records were escalated.
+func (r *Resolver) escalatePendingHarvestApprovals(ctx context.Context, settings *companyModels.CompanySettings) (int, error) {
+	if settings == nil || settings.PendingApprovalHours <= 0 {
+		return 0, nil
+	}
+
+	cutoff := time.Now().Add(-time.Duration(settings.PendingApprovalHours) * time.Hour)
+
+	var records []harvestApprovalEscalationRecord
+	if err := r.db.WithContext(ctx).
+		Table("harvest_records").
+		Select("id, estate_id, created_at").
+		Where("company_id = ?", settings.CompanyID).
+		Where("status = ?", "PENDING").
+		Where("approval_escalated_at IS NULL").
+		Where("created_at <= ?", cutoff).
+		Order("created_at ASC").
+		Limit(harvestApprovalEscalationBatchSize).
+		Scan(&records).Error; err != nil {
+		return 0, fmt.Errorf("failed to load overdue harvest records: %w", err)
+	}
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	recordIDs := make([]string, 0, len(records))
+	estateIDs := make([]string, 0, len(records))
+	seenEstates := make(map[string]struct{})
+	for _, record := range records {
+		recordIDs = append(recordIDs, record.ID)
+		if record.EstateID == nil || strings.TrimSpace(*record.EstateID) == "" {
+			continue
+		}
+		if _, ok := seenEstates[*record.EstateID]; ok {
+			continue
+		}
+		seenEstates[*record.EstateID] = struct{}{}
+		estateIDs = append(estateIDs, *record.EstateID)
+	}
+
+	recipients, err := r.getHarvestApprovalEscalationRecipients(ctx, settings.CompanyID, estateIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	// Records are keyed by the oldest overdue record so a retried sweep does not
+	// produce a second notification for the same batch.
+	idempotencyKey := fmt.Sprintf("harvest:approval-escalation:%s:%s", settings.CompanyID, records[0].ID)
+	message := fmt.Sprintf(
+		"%d record panen menunggu persetujuan lebih dari %d jam.",
+		len(records),
+		settings.PendingApprovalHours,
+	)
+
+	for _, recipient := range recipients {
+		input := &notificationServices.CreateNotificationInput{
+			Type:               notificationModels.NotificationTypeHarvestApprovalNeeded,
+			Priority:           notificationModels.NotificationPriorityHigh,
+			Title:              "Persetujuan Panen Terlambat",
+			Message:            message,
+			IdempotencyKey:     idempotencyKey,
+			RecipientID:        recipient.ID,
+			RecipientRole:      recipient.Role,
+			RecipientCompanyID: settings.CompanyID,
+			RelatedEntityType:  "HARVEST_RECORD",
+			RelatedEntityID:    records[0].ID,
+			ActionURL:          "/dashboard/harvest?status=PENDING",
+			ActionLabel:        "Tinjau Panen",
+			Metadata: map[string]interface{}{
+				"count":                len(records),
+				"pendingApprovalHours": settings.PendingApprovalHours,
+				"harvestRecordIds":     recordIDs,
+			},
+		}
+
+		if _, err := r.NotificationService.CreateNotification(ctx, input); err != nil {
+			return 0, fmt.Errorf("failed creating approval escalation notification for recipient %s: %w", recipient.ID, err)
+		}
+	}
+
+	if err := r.db.WithContext(ctx).
+		Table("harvest_records").
+		Where("id IN ?", recordIDs).
+		Where("approval_escalated_at IS NULL").
+		Update("approval_escalated_at", time.Now()).Error; err != nil {
+		return 0, fmt.Errorf("failed to mark harvest records as escalated: %w", err)
+	}
+
+	return len(records), nil
+}
+
+// getHarvestApprovalEscalationRecipients returns active managers of the affected
+// estates plus area managers of the company.
+func (r *Resolver) getHarvestApprovalEscalationRecipients(
+	ctx context.Context,
+	companyID string,
+	estateIDs []string,
+) ([]harvestApprovalEscalationRecipient, error) {
+	var recipients []harvestApprovalEscalationRecipient
+
+	query := r.db.WithContext(ctx).
+		Table("users AS u").
+		Select("DISTINCT u.id AS id, u.role AS role").
+		Joins("LEFT JOIN user_company_assignments AS uca ON uca.user_id = u.id AND uca.is_active = ?", true).
+		Joins("LEFT JOIN user_estate_assignments AS uea ON uea.user_id = u.id AND uea.is_active = ?", true).
+		Where("u.is_active = ?", true).
+		Where("u.deleted_at IS NULL")
+
+	if len(estateIDs) > 0 {
+		query = query.Where(
+			"(u.role = ? AND uea.estate_id IN ?) OR (u.role = ? AND uca.company_id = ?)",
+			"MANAGER", estateIDs,
+			"AREA_MANAGER", companyID,
+		)
+	} else {
+		query = query.Where("u.role IN ? AND uca.company_id = ?", []string{"MANAGER", "AREA_MANAGER"}, companyID)
+	}
+
+	if err := query.Scan(&recipients).Error; err != nil {
+		return nil, fmt.Errorf("failed to resolve approval escalation recipients: %w", err)
+	}
+
+	return recipients, nil
+}
@@ -22,6 +22,8 @@ const (
 
 type harvestBatchNotificationSummary struct {
 	MandorID    string
+	CompanyID   string
+	EstateID    string
 	HarvestIDs  []string
 	Blocks      []string
 	blockSeen   map[string]struct{}
@@ -69,6 +71,11 @@ func (r *mutationResolver) notifyMandorHarvestBatch(
 		go func() {
 			background := context.Background()
 
+			// Company routing rules replace the built-in mandor notification when configured.
+			if r.routeHarvestBatchDecision(background, summary, status, approverID, approverName, reason) {
+				return
+			}
+
 			if !isNilValue(r.FCMNotificationService) {
 				if notifier, ok := r.FCMNotificationService.(harvestBatchBroadcastFCMNotifier); ok && !isNilValue(notifier) {
 					payload := buildHarvestBatchFCMPayload(summary, status, approverName, reason)
@@ -121,8 +128,11 @@ func (r *mutationResolver) buildHarvestBatchNotificationSummaries(
 
 		summary, exists := grouped[mandorID]
 		if !exists {
+			companyID, estateID := harvestRoutingScope(record.CompanyID, record.EstateID)
 			summary = &harvestBatchNotificationSummary{
 				MandorID:   mandorID,
+				CompanyID:  companyID,
+				EstateID:   estateID,
 				HarvestIDs: make([]string, 0, 4),
 				Blocks:     make([]string, 0, 4),
 				blockSeen:  make(map[string]struct{}, 4),
@@ -201,6 +211,54 @@ func (r *mutationResolver) createMandorHarvestBatchNotification(
 	return err
 }
 
+// routeHarvestBatchDecision sends a coalesced approval or rejection through
+// the routing rules with the mandor as the event's subject.
+func (r *mutationResolver) routeHarvestBatchDecision(
+	ctx context.Context,
+	summary harvestBatchNotificationSummary,
+	status harvestBatchStatus,
+	approverID string,
+	approverName string,
+	reason string,
+) bool {
+	title, message := buildHarvestBatchNotificationContent(status, summary.Count, summary.Blocks, summary.TotalWeight, approverName, reason)
+
+	event := &notificationServices.RoutingEvent{
+		Type:              notificationModels.NotificationTypeHarvestApproved,
+		Priority:          notificationModels.NotificationPriorityMedium,
+		Title:             title,
+		Message:           message,
+		CompanyID:         summary.CompanyID,
+		EstateID:          summary.EstateID,
+		ActorID:           approverID,
+		ActorRole:         string(auth.UserRoleAsisten),
+		SubjectID:         summary.MandorID,
+		RelatedEntityType: "HARVEST_RECORD",
+		RelatedEntityID:   summary.HarvestIDs[0],
+		ActionURL:         "/dashboard/mandor/history",
+		ActionLabel:       "Lihat Detail",
+		Metadata: map[string]interface{}{
+			"harvestIds":     summary.HarvestIDs,
+			"mandorId":       summary.MandorID,
+			"batchCount":     summary.Count,
+			"blocks":         summary.Blocks,
+			"totalWeight":    summary.TotalWeight,
+			"batchCoalesced": true,
+			"approved":       status == harvestBatchStatusApproved,
+		},
+	}
+	if status == harvestBatchStatusRejected {
+		event.Type = notificationModels.NotificationTypeHarvestRejected
+		event.Priority = notificationModels.NotificationPriorityHigh
+		event.ActionURL = "/dashboard/mandor/panen"
+		event.ActionLabel = "Perbaiki Data"
+		if trimmedReason := strings.TrimSpace(reason); trimmedReason != "" {
+			event.Metadata["rejectedReason"] = trimmedReason
+		}
+	}
+	return r.routeEvent(ctx, event)
+}
+
 func (s *harvestBatchNotificationSummary) addBlock(blockName string) {
 	if s == nil {
 		return
@@ -16,6 +16,8 @@ import (
 type harvestCreatedBatchNotificationSummary struct {
 	MandorID    string
 	MandorName  string
+	CompanyID   string
+	EstateID    string
 	HarvestIDs  []string
 	Blocks      []string
 	blockSeen   map[string]struct{}
@@ -40,6 +42,12 @@ func (r *mutationResolver) notifyAsistenHarvestCreatedBatch(
 		summary := summary
 		go func() {
 			background := context.Background()
+
+			// Company routing rules replace the built-in asisten/manager fan-out when configured.
+			if r.routeHarvestCreatedBatch(background, summary) {
+				return
+			}
+
 			recipients, err := r.resolveHarvestCreatedRecipients(background, summary.MandorID)
 			if err != nil {
 				log.Printf(
@@ -119,9 +127,12 @@ func (r *mutationResolver) buildHarvestCreatedBatchNotificationSummaries(
 				}
 			}
 
+			companyID, estateID := harvestRoutingScope(record.CompanyID, record.EstateID)
 			summary = &harvestCreatedBatchNotificationSummary{
 				MandorID:   mandorID,
 				MandorName: fallbackMandorName,
+				CompanyID:  companyID,
+				EstateID:   estateID,
 				HarvestIDs: make([]string, 0, 4),
 				Blocks:     make([]string, 0, 4),
 				blockSeen:  make(map[string]struct{}, 4),
@@ -206,6 +217,39 @@ func (r *mutationResolver) createHarvestCreatedBatchNotification(
 	return err
 }
 
+// routeHarvestCreatedBatch sends a coalesced harvest-created event through the
+// routing rules.
+func (r *mutationResolver) routeHarvestCreatedBatch(
+	ctx context.Context,
+	summary harvestCreatedBatchNotificationSummary,
+) bool {
+	title, message := buildHarvestCreatedBatchNotificationContent(summary, auth.UserRoleAsisten)
+
+	return r.routeEvent(ctx, &notificationServices.RoutingEvent{
+		Type:              notificationModels.NotificationTypeHarvestApprovalNeeded,
+		Priority:          notificationModels.NotificationPriorityHigh,
+		Title:             title,
+		Message:           message,
+		CompanyID:         summary.CompanyID,
+		EstateID:          summary.EstateID,
+		ActorID:           summary.MandorID,
+		ActorRole:         string(auth.UserRoleMandor),
+		RelatedEntityType: "HARVEST_RECORD",
+		RelatedEntityID:   summary.HarvestIDs[0],
+		ActionURL:         "/approvals",
+		ActionLabel:       "Review",
+		Metadata: map[string]interface{}{
+			"harvestIds":     summary.HarvestIDs,
+			"batchCount":     summary.Count,
+			"blocks":         summary.Blocks,
+			"totalWeight":    summary.TotalWeight,
+			"batchCoalesced": true,
+			"mandorId":       summary.MandorID,
+			"mandorName":     summary.MandorName,
+		},
+	})
+}
+
 func buildHarvestCreatedBatchFCMPayload(
 	summary harvestCreatedBatchNotificationSummary,
 	role auth.UserRole,
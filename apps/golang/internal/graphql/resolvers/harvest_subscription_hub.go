@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"agrinovagraphql/server/internal/graphql/domain/mandor"
+	"agrinovagraphql/server/internal/pubsub"
 )
 
 type harvestSubscriberSet map[chan *mandor.HarvestRecord]struct{}
@@ -14,14 +15,28 @@ type harvestSubscriptionHub struct {
 	created  harvestSubscriberSet
 	approved harvestSubscriberSet
 	rejected harvestSubscriberSet
+
+	events hubBus
 }
 
 func newHarvestSubscriptionHub() *harvestSubscriptionHub {
-	return &harvestSubscriptionHub{
+	h := &harvestSubscriptionHub{
 		created:  make(harvestSubscriberSet),
 		approved: make(harvestSubscriberSet),
 		rejected: make(harvestSubscriberSet),
 	}
+	h.attach(pubsub.NewMemory())
+	return h
+}
+
+func (h *harvestSubscriptionHub) attach(bus pubsub.PubSub) {
+	h.events.attach(bus, func(bus pubsub.PubSub) []func() {
+		return []func(){
+			subscribeHubEvent(&h.events, bus, topicHarvestCreated, func(record *mandor.HarvestRecord) { h.deliver(record, h.created) }),
+			subscribeHubEvent(&h.events, bus, topicHarvestApproved, func(record *mandor.HarvestRecord) { h.deliver(record, h.approved) }),
+			subscribeHubEvent(&h.events, bus, topicHarvestRejected, func(record *mandor.HarvestRecord) { h.deliver(record, h.rejected) }),
+		}
+	})
 }
 
 var globalHarvestSubscriptionHub = newHarvestSubscriptionHub()
@@ -81,18 +96,25 @@ func (h *harvestSubscriptionHub) subscribe(ctx context.Context, subscribers *har
 }
 
 func (h *harvestSubscriptionHub) publishCreated(record *mandor.HarvestRecord) {
-	h.publish(record, h.created)
+	h.publish(topicHarvestCreated, record, h.created)
 }
 
 func (h *harvestSubscriptionHub) publishApproved(record *mandor.HarvestRecord) {
-	h.publish(record, h.approved)
+	h.publish(topicHarvestApproved, record, h.approved)
 }
 
 func (h *harvestSubscriptionHub) publishRejected(record *mandor.HarvestRecord) {
-	h.publish(record, h.rejected)
+	h.publish(topicHarvestRejected, record, h.rejected)
+}
+
+func (h *harvestSubscriptionHub) publish(topic string, record *mandor.HarvestRecord, subscribers harvestSubscriberSet) {
+	if record == nil {
+		return
+	}
+	publishHubEvent(&h.events, topic, record, func(record *mandor.HarvestRecord) { h.deliver(record, subscribers) })
 }
 
-func (h *harvestSubscriptionHub) publish(record *mandor.HarvestRecord, subscribers harvestSubscriberSet) {
+func (h *harvestSubscriptionHub) deliver(record *mandor.HarvestRecord, subscribers harvestSubscriberSet) {
 	if record == nil {
 		return
 	}
@@ -845,6 +845,9 @@ func (r *queryResolver) ActiveHarvestActivities(ctx context.Context, estateID *s
 
 // ManagerAnalytics is the resolver for the managerAnalytics field.
 func (r *queryResolver) ManagerAnalytics(ctx context.Context, period manager.AnalyticsPeriod, startDate *time.Time, endDate *time.Time, estateID *string) (*manager.ManagerAnalyticsData, error) {
+	ctx, release := r.ReadRouter.UseReplica(ctx)
+	defer release()
+
 	userID := middleware.GetCurrentUserID(ctx)
 	if userID == "" {
 		return nil, fmt.Errorf("authentication required")
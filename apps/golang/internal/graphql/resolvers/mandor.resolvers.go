@@ -17,13 +17,12 @@ import (
 
 	panenModels "agrinovagraphql/server/internal/panen/models"
 	panenResolvers "agrinovagraphql/server/internal/panen/resolvers"
+	"bytes"
 	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"log"
-	"os"
-	"path/filepath"
 	"reflect"
 	"regexp"
 	"strings"
@@ -33,6 +32,23 @@ import (
 	"gorm.io/gorm"
 )
 
+// Mandor is the resolver for the mandor field.
+func (r *harvestRecordResolver) Mandor(ctx context.Context, obj *mandor.HarvestRecord) (*auth.User, error) {
+	if obj.Mandor != nil {
+		return obj.Mandor, nil
+	}
+	return loadRequired(ctx, r.loaders(ctx).Users, "mandor", obj.MandorID)
+}
+
+// Block is the resolver for the block field.
+func (r *harvestRecordResolver) Block(ctx context.Context, obj *mandor.HarvestRecord) (*master.Block, error) {
+	if obj.Block != nil {
+		r.loaders(ctx).PrimeBlock(obj.Block)
+		return obj.Block, nil
+	}
+	return loadRequired(ctx, r.loaders(ctx).Blocks, "block", obj.BlockID)
+}
+
 // CreateMandorHarvest is the resolver for the createMandorHarvest field.
 func (r *mutationResolver) CreateMandorHarvest(ctx context.Context, input mandor.CreateMandorHarvestInput) (*mandor.MandorHarvestResult, error) {
 	// 1. Get MandorID from context (authenticated user)
@@ -215,6 +231,7 @@ func (r *mutationResolver) SyncHarvestRecords(ctx context.Context, input mandor.
 	if authUserID == "" {
 		return nil, fmt.Errorf("authentication required")
 	}
+	syncStart := time.Now()
 
 	successCount := 0
 	failureCount := 0
@@ -432,7 +449,7 @@ func (r *mutationResolver) SyncHarvestRecords(ctx context.Context, input mandor.
 				if recordInput.PhotoURL != nil {
 					photoValue := strings.TrimSpace(*recordInput.PhotoURL)
 					if photoValue != "" {
-						resolvedPhotoURL, photoErr := r.saveHarvestPhotoFromPayload(recordInput.LocalID, photoValue)
+						resolvedPhotoURL, photoErr := r.saveHarvestPhotoFromPayload(syncLookupCtx, harvestPhotoCompanyID(ctx, createInput.CompanyID), recordInput.LocalID, photoValue)
 						if photoErr != nil {
 							return fmt.Errorf("failed to process harvest photo: %w", photoErr)
 						}
@@ -483,6 +500,9 @@ func (r *mutationResolver) SyncHarvestRecords(ctx context.Context, input mandor.
 			if record != nil {
 				itemResult.ServerID = &record.ID
 			}
+			if recordInput.PhotoURL != nil && strings.TrimSpace(*recordInput.PhotoURL) != "" {
+				r.verifyHarvestPhoto(ctx, record)
+			}
 		}
 		syncResults = append(syncResults, itemResult)
 	}
@@ -501,7 +521,7 @@ func (r *mutationResolver) SyncHarvestRecords(ctx context.Context, input mandor.
 		message = fmt.Sprintf("%s, %d conflicts resolved", message, conflictsDetected)
 	}
 
-	return &mandor.MandorSyncResult{
+	result := &mandor.MandorSyncResult{
 		Success:           failureCount == 0,
 		TransactionID:     transactionID,
 		RecordsProcessed:  int32(len(input.Records)),
@@ -511,7 +531,10 @@ func (r *mutationResolver) SyncHarvestRecords(ctx context.Context, input mandor.
 		Results:           syncResults,
 		ServerTimestamp:   time.Now(),
 		Message:           message,
-	}, nil
+	}
+	recordHarvestSync(result, time.Since(syncStart))
+
+	return result, nil
 }
 
 func (r *mutationResolver) notifyAsistenHarvestCreated(ctx context.Context, record *panenModels.HarvestRecord) {
@@ -551,6 +574,8 @@ func (r *mutationResolver) notifyAsistenHarvestCreated(ctx context.Context, reco
 		return
 	}
 
+	companyID, estateID := harvestRoutingScope(record.CompanyID, record.EstateID)
+
 	go func(
 		notifier HarvestFCMNotifier,
 		notificationService *notificationServices.NotificationService,
@@ -573,6 +598,20 @@ func (r *mutationResolver) notifyAsistenHarvestCreated(ctx context.Context, reco
 			blockName,
 		)
 
+		// Company routing rules replace the built-in fan-out when configured.
+		if r.routeHarvestApprovalNeeded(
+			context.Background(),
+			harvestID,
+			mandorID,
+			companyID,
+			estateID,
+			resolvedMandorName,
+			resolvedBlockName,
+			weight,
+		) {
+			return
+		}
+
 		if !isNilValue(notifier) {
 			err := notifier.NotifyAsistenNewHarvest(
 				context.Background(),
@@ -854,12 +893,21 @@ func (r *mutationResolver) notifyMandorHarvestApproved(
 	blockName := r.resolveHarvestBlockName(ctx, record)
 	harvestDate := record.Tanggal.Format("02/01/2006")
 	bunchCount := record.JumlahJanjang
+	weight := record.BeratTbs
+	companyID, estateID := harvestRoutingScope(record.CompanyID, record.EstateID)
 
-	if !isNilValue(r.FCMNotificationService) {
-		if notifier, ok := r.FCMNotificationService.(harvestApprovalFCMNotifier); ok && !isNilValue(notifier) {
-			go func() {
+	go func() {
+		background := context.Background()
+
+		// Company routing rules replace the built-in mandor notification when configured.
+		if r.routeHarvestDecision(background, harvestID, mandorID, approverID, companyID, estateID, blockName, weight, "", true) {
+			return
+		}
+
+		if !isNilValue(r.FCMNotificationService) {
+			if notifier, ok := r.FCMNotificationService.(harvestApprovalFCMNotifier); ok && !isNilValue(notifier) {
 				err := notifier.NotifyMandorApproved(
-					context.Background(),
+					background,
 					harvestID,
 					mandorID,
 					asistenName,
@@ -875,19 +923,17 @@ func (r *mutationResolver) notifyMandorHarvestApproved(
 						err,
 					)
 				}
-			}()
+			}
 		}
-	}
 
-	if r.NotificationService != nil {
-		go func() {
+		if r.NotificationService != nil {
 			if err := r.NotificationService.NotifyHarvestApproved(
-				context.Background(),
+				background,
 				harvestID,
 				mandorID,
 				approverID,
 				blockName,
-				record.BeratTbs,
+				weight,
 			); err != nil {
 				log.Printf(
 					"Failed to create harvest-approved notification for harvest %s: %v",
@@ -895,8 +941,8 @@ func (r *mutationResolver) notifyMandorHarvestApproved(
 					err,
 				)
 			}
-		}()
-	}
+		}
+	}()
 }
 
 func (r *mutationResolver) notifyMandorHarvestRejected(
@@ -923,12 +969,21 @@ func (r *mutationResolver) notifyMandorHarvestRejected(
 	}
 	harvestDate := record.Tanggal.Format("02/01/2006")
 	bunchCount := record.JumlahJanjang
+	weight := record.BeratTbs
+	companyID, estateID := harvestRoutingScope(record.CompanyID, record.EstateID)
+
+	go func() {
+		background := context.Background()
 
-	if !isNilValue(r.FCMNotificationService) {
-		if notifier, ok := r.FCMNotificationService.(harvestRejectionFCMNotifier); ok && !isNilValue(notifier) {
-			go func() {
+		// Company routing rules replace the built-in mandor notification when configured.
+		if r.routeHarvestDecision(background, harvestID, mandorID, approverID, companyID, estateID, blockName, weight, rejectedReason, false) {
+			return
+		}
+
+		if !isNilValue(r.FCMNotificationService) {
+			if notifier, ok := r.FCMNotificationService.(harvestRejectionFCMNotifier); ok && !isNilValue(notifier) {
 				err := notifier.NotifyMandorRejected(
-					context.Background(),
+					background,
 					harvestID,
 					mandorID,
 					asistenName,
@@ -945,19 +1000,17 @@ func (r *mutationResolver) notifyMandorHarvestRejected(
 						err,
 					)
 				}
-			}()
+			}
 		}
-	}
 
-	if r.NotificationService != nil {
-		go func() {
+		if r.NotificationService != nil {
 			if err := r.NotificationService.NotifyHarvestRejected(
-				context.Background(),
+				background,
 				harvestID,
 				mandorID,
 				approverID,
 				blockName,
-				record.BeratTbs,
+				weight,
 				rejectedReason,
 			); err != nil {
 				log.Printf(
@@ -966,8 +1019,8 @@ func (r *mutationResolver) notifyMandorHarvestRejected(
 					err,
 				)
 			}
-		}()
-	}
+		}
+	}()
 }
 
 func (r *mutationResolver) resolveApproverName(ctx context.Context, approverID string) string {
@@ -1268,7 +1321,16 @@ func (r *mutationResolver) ensureManagerCanAccessHarvest(
 	return nil
 }
 
-func (r *mutationResolver) saveHarvestPhotoFromPayload(localID string, photoValue string) (string, error) {
+// harvestPhotoCompanyID picks the company a harvest photo is stored under:
+// the record's company, or the caller's when the record has none.
+func harvestPhotoCompanyID(ctx context.Context, recordCompanyID *string) string {
+	if recordCompanyID != nil && strings.TrimSpace(*recordCompanyID) != "" {
+		return strings.TrimSpace(*recordCompanyID)
+	}
+	return middleware.GetCompanyFromContext(ctx)
+}
+
+func (r *mutationResolver) saveHarvestPhotoFromPayload(ctx context.Context, companyID string, localID string, photoValue string) (string, error) {
 	trimmed := strings.TrimSpace(photoValue)
 	if trimmed == "" {
 		return "", nil
@@ -1305,24 +1367,25 @@ func (r *mutationResolver) saveHarvestPhotoFromPayload(localID string, photoValu
 		}
 	}
 
-	uploadDir := r.uploadAbsolutePath("harvest_photos")
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create upload dir: %w", err)
-	}
-
 	ext := fileExtensionFromMimeType(mimeType)
 	filePrefix := sanitizeFileComponent(localID)
 	if filePrefix == "" {
 		filePrefix = "harvest"
 	}
 
+	size := int64(len(photoBytes))
+	if err := r.reserveStorage(ctx, companyID, size); err != nil {
+		return "", err
+	}
+
 	filename := fmt.Sprintf("%s_%d%s", filePrefix, time.Now().UnixNano(), ext)
-	absolutePath := filepath.Join(uploadDir, filename)
-	if err := os.WriteFile(absolutePath, photoBytes, 0644); err != nil {
+	filePath, err := r.saveUpload(ctx, bytes.NewReader(photoBytes), size, mimeType, "harvest_photos", sanitizeUploadPathSegment(companyID), filename)
+	if err != nil {
+		r.releaseUploadQuota(ctx, companyID, size)
 		return "", fmt.Errorf("failed to save photo file: %w", err)
 	}
 
-	return uploadURLPath("harvest_photos", filename), nil
+	return filePath, nil
 }
 
 func fileExtensionFromMimeType(mimeType string) string {
@@ -1412,7 +1475,7 @@ func (r *mutationResolver) updateHarvestRecordFromSync(
 			updatedModel.PhotoURL = nil
 			needsSave = true
 		} else {
-			resolvedPhotoURL, photoErr := r.saveHarvestPhotoFromPayload(input.LocalID, photoValue)
+			resolvedPhotoURL, photoErr := r.saveHarvestPhotoFromPayload(ctx, harvestPhotoCompanyID(ctx, updatedModel.CompanyID), input.LocalID, photoValue)
 			if photoErr != nil {
 				return nil, fmt.Errorf("failed to process harvest photo: %w", photoErr)
 			}
@@ -1887,6 +1950,9 @@ func (r *queryResolver) applyMandorDivisionScope(query *gorm.DB, assignedDivisio
 
 // MandorHistory is the resolver for the mandorHistory field.
 func (r *queryResolver) MandorHistory(ctx context.Context, filter *mandor.MandorHistoryFilter) (*mandor.MandorHistoryResponse, error) {
+	ctx, release := r.ReadRouter.UseReplica(ctx)
+	defer release()
+
 	userID, ok := ctx.Value("user_id").(string)
 	if !ok || userID == "" {
 		return nil, fmt.Errorf("user not authenticated")
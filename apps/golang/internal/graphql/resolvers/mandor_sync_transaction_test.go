@@ -13,6 +13,7 @@ import (
 	graphqlAuth "agrinovagraphql/server/internal/graphql/domain/auth"
 	graphqlMandor "agrinovagraphql/server/internal/graphql/domain/mandor"
 	panenResolvers "agrinovagraphql/server/internal/panen/resolvers"
+	"agrinovagraphql/server/pkg/storage"
 )
 
 type syncTestFixture struct {
@@ -263,10 +264,12 @@ func TestSyncHarvestRecords_ConflictServerWins_NoLocalWrite(t *testing.T) {
 
 func newMandorSyncMutationResolver(t *testing.T, db *gorm.DB) *mutationResolver {
 	t.Helper()
+	uploads, err := storage.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
 	return &mutationResolver{
 		Resolver: &Resolver{
 			db:            db,
-			uploadsDir:    t.TempDir(),
+			uploads:       uploads,
 			PanenResolver: panenResolvers.NewPanenResolver(db, nil),
 		},
 	}
@@ -25,6 +25,23 @@ import (
 
 var semesterPattern = regexp.MustCompile(`^\d{4}-(S1|S2)$`)
 
+// Estate is the resolver for the estate field.
+func (r *divisionResolver) Estate(ctx context.Context, obj *master.Division) (*master.Estate, error) {
+	if obj.Estate != nil {
+		r.loaders(ctx).PrimeEstate(obj.Estate)
+		return obj.Estate, nil
+	}
+	return loadRequired(ctx, r.loaders(ctx).Estates, "estate", obj.EstateID)
+}
+
+// Company is the resolver for the company field.
+func (r *estateResolver) Company(ctx context.Context, obj *master.Estate) (*master.Company, error) {
+	if obj.Company != nil {
+		return obj.Company, nil
+	}
+	return loadRequired(ctx, r.loaders(ctx).Companies, "company", obj.CompanyID)
+}
+
 // Master data mutations
 
 // CreateCompany is the resolver for the createCompany field.
@@ -916,9 +933,18 @@ func (r *mutationResolver) DeleteVehicleTax(ctx context.Context, id string) (boo
 		return false, err
 	}
 
+	var filePaths []string
+	if err := r.db.WithContext(ctx).
+		Model(&master.VehicleTaxDocument{}).
+		Where("vehicle_tax_id = ?", id).
+		Pluck("file_path", &filePaths).Error; err != nil {
+		return false, fmt.Errorf("failed to load vehicle tax documents: %w", err)
+	}
+
 	if err := r.db.WithContext(ctx).Delete(&master.VehicleTax{}, "id = ?", id).Error; err != nil {
 		return false, fmt.Errorf("failed to delete vehicle tax: %w", err)
 	}
+	r.deleteUnreferencedVehicleTaxFiles(ctx, vehicle.CompanyID, filePaths...)
 
 	return true, nil
 }
@@ -1008,6 +1034,7 @@ func (r *mutationResolver) DeleteVehicleTaxDocument(ctx context.Context, id stri
 	if err := r.db.WithContext(ctx).Delete(&master.VehicleTaxDocument{}, "id = ?", id).Error; err != nil {
 		return false, fmt.Errorf("failed to delete vehicle tax document: %w", err)
 	}
+	r.deleteUnreferencedVehicleTaxFiles(ctx, vehicle.CompanyID, vehicleTaxDocument.FilePath)
 
 	return true, nil
 }
@@ -1708,6 +1735,54 @@ func (r *queryResolver) BlockTreatmentSemesterRequest(ctx context.Context, id st
 	return r.loadBlockTreatmentSemesterRequestWithItems(ctx, strings.TrimSpace(id), userID)
 }
 
+// MasterDataImportJob is the resolver for the masterDataImportJob field.
+func (r *queryResolver) MasterDataImportJob(ctx context.Context, id string) (*generated.MasterDataImportJob, error) {
+	userID := middleware.GetCurrentUserID(ctx)
+	if userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	job, err := r.MasterDataImportService.GetJob(ctx, userID, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.requireRBACPermission(ctx, masterDataImportPermission(job.ImportType)); err != nil {
+		return nil, err
+	}
+	return toGraphQLMasterDataImportJob(job), nil
+}
+
+// MasterDataImportJobs is the resolver for the masterDataImportJobs field.
+func (r *queryResolver) MasterDataImportJobs(ctx context.Context, companyID *string, limit *int32) ([]*generated.MasterDataImportJob, error) {
+	userID, err := r.requireRBACPermission(ctx, "block:read")
+	if err != nil {
+		return nil, err
+	}
+
+	targetCompanyID := middleware.GetCompanyFromContext(ctx)
+	if companyID != nil && strings.TrimSpace(*companyID) != "" {
+		targetCompanyID = strings.TrimSpace(*companyID)
+	}
+	if targetCompanyID == "" {
+		return nil, fmt.Errorf("companyId is required")
+	}
+
+	jobLimit := 0
+	if limit != nil {
+		jobLimit = int(*limit)
+	}
+	jobs, err := r.MasterDataImportService.ListJobs(ctx, userID, targetCompanyID, jobLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*generated.MasterDataImportJob, 0, len(jobs))
+	for _, job := range jobs {
+		result = append(result, toGraphQLMasterDataImportJob(job))
+	}
+	return result, nil
+}
+
 // CreateBlockTreatmentSemesterRequest is the resolver for the createBlockTreatmentSemesterRequest field.
 func (r *mutationResolver) CreateBlockTreatmentSemesterRequest(ctx context.Context, input generated.CreateBlockTreatmentSemesterRequestInput) (*generated.BlockTreatmentSemesterRequest, error) {
 	userID, err := r.requireRBACPermission(ctx, "block:update")
@@ -3727,23 +3802,33 @@ func convertVehicleTaxToGraphQL(vehicleTax *master.VehicleTax) *generated.Vehicl
 		return nil
 	}
 
+	now := time.Now()
+	var reminderStage *string
+	if stage := vehicleTax.ReminderStage(now); stage != "" {
+		reminderStage = &stage
+	}
+
 	return &generated.VehicleTax{
-		ID:               vehicleTax.ID,
-		VehicleID:        vehicleTax.VehicleID,
-		TaxYear:          vehicleTax.TaxYear,
-		DueDate:          vehicleTax.DueDate,
-		PkbAmount:        vehicleTax.PKBAmount,
-		SwdklljAmount:    vehicleTax.SWDKLLJAmount,
-		AdminAmount:      vehicleTax.AdminAmount,
-		PenaltyAmount:    vehicleTax.PenaltyAmount,
-		TotalAmount:      vehicleTax.TotalAmount,
-		PaymentDate:      vehicleTax.PaymentDate,
-		PaymentMethod:    vehicleTax.PaymentMethod,
-		PaymentReference: vehicleTax.PaymentReference,
-		TaxStatus:        vehicleTax.TaxStatus,
-		Notes:            vehicleTax.Notes,
-		CreatedAt:        vehicleTax.CreatedAt,
-		UpdatedAt:        vehicleTax.UpdatedAt,
+		ID:                     vehicleTax.ID,
+		VehicleID:              vehicleTax.VehicleID,
+		TaxYear:                vehicleTax.TaxYear,
+		DueDate:                vehicleTax.DueDate,
+		PkbAmount:              vehicleTax.PKBAmount,
+		SwdklljAmount:          vehicleTax.SWDKLLJAmount,
+		AdminAmount:            vehicleTax.AdminAmount,
+		PenaltyAmount:          vehicleTax.PenaltyAmount,
+		TotalAmount:            vehicleTax.TotalAmount,
+		PaymentDate:            vehicleTax.PaymentDate,
+		PaymentMethod:          vehicleTax.PaymentMethod,
+		PaymentReference:       vehicleTax.PaymentReference,
+		TaxStatus:              vehicleTax.TaxStatus,
+		Notes:                  vehicleTax.Notes,
+		DaysOverdue:            int32(vehicleTax.DaysOverdue(now)),
+		EstimatedPenaltyAmount: vehicleTax.EstimatedPenalty(now),
+		ReminderStage:          reminderStage,
+		GateEntryBlocked:       vehicleTax.BlocksGateEntry(now),
+		CreatedAt:              vehicleTax.CreatedAt,
+		UpdatedAt:              vehicleTax.UpdatedAt,
 	}
 }
 
@@ -3965,3 +4050,9 @@ func (r *Resolver) loadVehicleTaxDocumentByID(ctx context.Context, id string) (*
 	}
 	return &vehicleTaxDocument, nil
 }
+
+// DivisionResolver handles field resolvers for Division
+type divisionResolver struct{ *Resolver }
+
+// EstateResolver handles field resolvers for Estate
+type estateResolver struct{ *Resolver }
@@ -0,0 +1,289 @@
+package resolvers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"agrinovagraphql/server/internal/graphql/generated"
+	masterModels "agrinovagraphql/server/internal/master/models"
+	masterServices "agrinovagraphql/server/internal/master/services"
+	"agrinovagraphql/server/internal/middleware"
+	"agrinovagraphql/server/pkg/spreadsheet"
+
+	"github.com/gin-gonic/gin"
+)
+
+const masterDataImportMaxUploadSize = 10 * 1024 * 1024 // 10 MB
+
+// HandleMasterDataImport accepts a CSV/XLSX file of the estate → division →
+// block hierarchy or of employees and queues it as a background import job.
+// Progress is read through the masterDataImportJob query.
+func (r *Resolver) HandleMasterDataImport(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := middleware.GetCurrentUserID(ctx)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "authentication required",
+		})
+		return
+	}
+
+	importType, ok := masterServices.NormalizeMasterDataImportType(c.PostForm("type"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "type must be one of HIERARCHY, EMPLOYEE",
+		})
+		return
+	}
+
+	if _, err := r.requireRBACPermission(ctx, masterDataImportWritePermission(importType)); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	companyID := strings.TrimSpace(c.PostForm("companyId"))
+	if companyID == "" {
+		companyID = middleware.GetCompanyFromContext(ctx)
+	}
+	if companyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "companyId is required",
+		})
+		return
+	}
+	if err := r.validateCompanyScope(ctx, userID, companyID); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "file is required",
+		})
+		return
+	}
+
+	if fileHeader.Size <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "file is empty",
+		})
+		return
+	}
+
+	if fileHeader.Size > masterDataImportMaxUploadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"success": false,
+			"message": "file exceeds 10 MB",
+		})
+		return
+	}
+
+	format, err := spreadsheet.FormatFromFilename(fileHeader.Filename)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": fmt.Sprintf("failed to read file: %v", err),
+		})
+		return
+	}
+	defer file.Close()
+
+	table, err := spreadsheet.ReadTable(format, file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	job, err := r.MasterDataImportService.StartImport(ctx, userID, companyID, importType, fileHeader.Filename, table)
+	if err != nil {
+		c.JSON(masterDataErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"message": fmt.Sprintf("import queued: %d row(s)", job.TotalRows),
+		"job":     toGraphQLMasterDataImportJob(job),
+	})
+}
+
+// HandleMasterDataExport streams a company's hierarchy or employees in the
+// layout accepted by HandleMasterDataImport.
+func (r *Resolver) HandleMasterDataExport(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := middleware.GetCurrentUserID(ctx)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "authentication required",
+		})
+		return
+	}
+
+	importType, ok := masterServices.NormalizeMasterDataImportType(c.Query("type"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "type must be one of HIERARCHY, EMPLOYEE",
+		})
+		return
+	}
+
+	if _, err := r.requireRBACPermission(ctx, masterDataImportPermission(importType)); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	format := strings.ToLower(strings.TrimSpace(c.DefaultQuery("format", spreadsheet.FormatXLSX)))
+	if format != spreadsheet.FormatCSV && format != spreadsheet.FormatXLSX {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "format must be csv or xlsx",
+		})
+		return
+	}
+
+	companyID := strings.TrimSpace(c.Query("companyId"))
+	if companyID == "" {
+		companyID = middleware.GetCompanyFromContext(ctx)
+	}
+	if companyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "companyId is required",
+		})
+		return
+	}
+
+	header, rows, err := r.MasterDataImportService.Export(ctx, userID, companyID, importType)
+	if err != nil {
+		c.JSON(masterDataErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var buffer bytes.Buffer
+	if err := spreadsheet.WriteTable(format, &buffer, strings.ToLower(importType), header, rows); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": fmt.Sprintf("failed to write export: %v", err),
+		})
+		return
+	}
+
+	fileName := fmt.Sprintf("%s_%s.%s", strings.ToLower(importType), time.Now().Format("20060102"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	c.Data(http.StatusOK, spreadsheet.ContentType(format), buffer.Bytes())
+}
+
+// masterDataImportPermission is the RBAC permission needed to read imports
+// and exports of a type.
+func masterDataImportPermission(importType string) string {
+	if importType == masterModels.MasterDataImportEmployee {
+		return "employee:read"
+	}
+	return "block:read"
+}
+
+// masterDataImportWritePermission is the RBAC permission needed to import a type.
+func masterDataImportWritePermission(importType string) string {
+	if importType == masterModels.MasterDataImportEmployee {
+		return "employee:create"
+	}
+	return "block:create"
+}
+
+func masterDataErrorStatus(err error) int {
+	var masterErr *masterModels.MasterDataError
+	if !errors.As(err, &masterErr) {
+		return http.StatusInternalServerError
+	}
+	switch masterErr.Code {
+	case masterModels.ErrCodePermissionDenied:
+		return http.StatusForbidden
+	case masterModels.ErrCodeNotFound:
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+func toGraphQLMasterDataImportJob(job *masterModels.MasterDataImportJob) *generated.MasterDataImportJob {
+	if job == nil {
+		return nil
+	}
+
+	rowErrors := job.Errors()
+	errorsOut := make([]*generated.MasterDataImportRowError, 0, len(rowErrors))
+	for _, rowErr := range rowErrors {
+		item := &generated.MasterDataImportRowError{
+			Row:     int32(rowErr.Row),
+			Message: rowErr.Message,
+		}
+		if rowErr.Entity != "" {
+			entity := rowErr.Entity
+			item.Entity = &entity
+		}
+		if rowErr.Field != "" {
+			field := rowErr.Field
+			item.Field = &field
+		}
+		errorsOut = append(errorsOut, item)
+	}
+
+	return &generated.MasterDataImportJob{
+		ID:            job.ID,
+		CompanyID:     job.CompanyID,
+		ImportType:    generated.MasterDataImportType(job.ImportType),
+		Status:        generated.MasterDataImportStatus(job.Status),
+		FileName:      job.FileName,
+		TotalRows:     int32(job.TotalRows),
+		ProcessedRows: int32(job.ProcessedRows),
+		CreatedCount:  int32(job.CreatedCount),
+		UpdatedCount:  int32(job.UpdatedCount),
+		FailedRows:    int32(job.FailedRows),
+		Errors:        errorsOut,
+		Message:       job.Message,
+		CreatedBy:     job.CreatedBy,
+		StartedAt:     job.StartedAt,
+		FinishedAt:    job.FinishedAt,
+		CreatedAt:     job.CreatedAt,
+		UpdatedAt:     job.UpdatedAt,
+	}
+}
@@ -0,0 +1,126 @@
+package resolvers
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"agrinovagraphql/server/internal/graphql/domain/mandor"
+	"agrinovagraphql/server/internal/graphql/domain/satpam"
+	"agrinovagraphql/server/internal/graphql/generated"
+	syncServices "agrinovagraphql/server/internal/sync/services"
+)
+
+const satpamNotificationOutboxName = "satpam_notification"
+
+var (
+	outboxDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "agrinova",
+			Subsystem: "outbox",
+			Name:      "queue_depth",
+			Help:      "Number of outbox jobs waiting or being delivered, by status",
+		},
+		[]string{"outbox", "status"},
+	)
+
+	outboxOldestAge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "agrinova",
+			Subsystem: "outbox",
+			Name:      "oldest_job_age_seconds",
+			Help:      "Age of the oldest outbox job waiting or being delivered, by status",
+		},
+		[]string{"outbox", "status"},
+	)
+
+	outboxDeliveries = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "agrinova",
+			Subsystem: "outbox",
+			Name:      "deliveries_total",
+			Help:      "Total number of outbox delivery attempts, by result",
+		},
+		[]string{"outbox", "result"},
+	)
+)
+
+// refreshSatpamNotificationOutboxMetrics samples the queue depth and the age
+// of the oldest job for the pending and processing statuses.
+func (r *Resolver) refreshSatpamNotificationOutboxMetrics(ctx context.Context) {
+	var rows []struct {
+		Status     string
+		Jobs       int64
+		AgeSeconds float64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&satpamNotificationOutboxJob{}).
+		Select("status, COUNT(*) AS jobs, COALESCE(EXTRACT(EPOCH FROM LOCALTIMESTAMP - MIN(created_at)), 0) AS age_seconds").
+		Where("status IN ?", []string{satpamNotificationOutboxStatusPending, satpamNotificationOutboxStatusProcessing}).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return
+	}
+
+	// Statuses without rows report an empty queue rather than a stale value.
+	for _, status := range []string{satpamNotificationOutboxStatusPending, satpamNotificationOutboxStatusProcessing} {
+		outboxDepth.WithLabelValues(satpamNotificationOutboxName, status).Set(0)
+		outboxOldestAge.WithLabelValues(satpamNotificationOutboxName, status).Set(0)
+	}
+	for _, row := range rows {
+		outboxDepth.WithLabelValues(satpamNotificationOutboxName, row.Status).Set(float64(row.Jobs))
+		outboxOldestAge.WithLabelValues(satpamNotificationOutboxName, row.Status).Set(max(0, row.AgeSeconds))
+	}
+}
+
+func recordOutboxDelivery(outbox string, err error) {
+	result := "delivered"
+	if err != nil {
+		result = "failed"
+	}
+	outboxDeliveries.WithLabelValues(outbox, result).Inc()
+}
+
+func recordHarvestSync(result *mandor.MandorSyncResult, elapsed time.Duration) {
+	syncServices.RecordSync(syncServices.SyncKindHarvest, syncServices.SyncOutcome{
+		Accepted:  int(result.RecordsSuccessful),
+		Rejected:  int(result.RecordsFailed),
+		Conflicts: int(result.ConflictsDetected),
+	}, elapsed)
+}
+
+// recordGateCheckSync records a gate check sync; a nil result means the
+// whole batch failed.
+func recordGateCheckSync(input satpam.SatpamSyncInput, result *satpam.SatpamSyncResult, elapsed time.Duration) {
+	outcome := syncServices.SyncOutcome{Rejected: len(input.GuestLogs)}
+	if result != nil {
+		outcome = syncServices.SyncOutcome{
+			Accepted:  int(result.RecordsSuccessful),
+			Rejected:  int(result.RecordsFailed),
+			Conflicts: int(result.ConflictsDetected),
+		}
+	}
+	syncServices.RecordSync(syncServices.SyncKindGateCheck, outcome, elapsed)
+}
+
+func recordGateCheckPhotoSync(input generated.SatpamPhotoSyncInput, result *generated.SatpamPhotoSyncResult, elapsed time.Duration) {
+	outcome := syncServices.SyncOutcome{Rejected: len(input.Photos)}
+	if result != nil {
+		outcome = syncServices.SyncOutcome{
+			Accepted: int(result.SuccessfulUploads),
+			Rejected: int(result.FailedUploads),
+		}
+	}
+	syncServices.RecordSync(syncServices.SyncKindGateCheckPhoto, outcome, elapsed)
+}
+
+func recordEmployeeLogSync(result *generated.EmployeeLogSyncResult, elapsed time.Duration) {
+	outcome := syncServices.SyncOutcome{Rejected: 1}
+	if result != nil && result.Success {
+		outcome = syncServices.SyncOutcome{Accepted: 1}
+	}
+	syncServices.RecordSync(syncServices.SyncKindEmployeeLog, outcome, elapsed)
+}
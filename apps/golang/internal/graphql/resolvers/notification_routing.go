@@ -0,0 +1,289 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"agrinovagraphql/server/internal/graphql/domain/auth"
+	"agrinovagraphql/server/internal/graphql/generated"
+	"agrinovagraphql/server/internal/middleware"
+	notificationModels "agrinovagraphql/server/internal/notifications/models"
+	notificationServices "agrinovagraphql/server/internal/notifications/services"
+)
+
+// routeEvent sends an event through the company's routing rules. It returns
+// false when no rule matched so the caller keeps its built-in fan-out.
+func (r *Resolver) routeEvent(ctx context.Context, event *notificationServices.RoutingEvent) bool {
+	if r.NotificationRoutingService == nil || event == nil {
+		return false
+	}
+
+	result, err := r.NotificationRoutingService.Route(ctx, event)
+	if err != nil {
+		log.Printf("Failed to route %s notification for %s %s: %v", event.Type, event.RelatedEntityType, event.RelatedEntityID, err)
+	}
+	return result != nil && result.Matched
+}
+
+// routeHarvestApprovalNeeded sends the harvest-created event through the
+// company's routing rules. It returns false when no rule matched so the caller
+// keeps the built-in asisten/manager fan-out.
+func (r *Resolver) routeHarvestApprovalNeeded(
+	ctx context.Context,
+	harvestID string,
+	mandorID string,
+	companyID string,
+	estateID string,
+	mandorName string,
+	blockName string,
+	weight float64,
+) bool {
+	return r.routeEvent(ctx, &notificationServices.RoutingEvent{
+		Type:              notificationModels.NotificationTypeHarvestApprovalNeeded,
+		Priority:          notificationModels.NotificationPriorityHigh,
+		Title:             "Persetujuan Panen Diperlukan",
+		Message:           fmt.Sprintf("Data panen baru dari %s di blok %s (%.1f kg) memerlukan persetujuan", mandorName, blockName, weight),
+		CompanyID:         companyID,
+		EstateID:          estateID,
+		ActorID:           mandorID,
+		ActorRole:         string(auth.UserRoleMandor),
+		RelatedEntityType: "HARVEST_RECORD",
+		RelatedEntityID:   harvestID,
+		ActionURL:         "/approvals",
+		ActionLabel:       "Review",
+		Metadata: map[string]interface{}{
+			"harvestId":  harvestID,
+			"mandorId":   mandorID,
+			"mandorName": mandorName,
+			"block":      blockName,
+			"weight":     weight,
+		},
+	})
+}
+
+// routeHarvestDecision sends an approval or rejection through the routing
+// rules. The mandor is the event's subject, so a HIERARCHY rule listing MANDOR
+// reaches them along with their supervisors.
+func (r *Resolver) routeHarvestDecision(
+	ctx context.Context,
+	harvestID string,
+	mandorID string,
+	approverID string,
+	companyID string,
+	estateID string,
+	blockName string,
+	weight float64,
+	reason string,
+	approved bool,
+) bool {
+	event := &notificationServices.RoutingEvent{
+		Type:              notificationModels.NotificationTypeHarvestApproved,
+		Priority:          notificationModels.NotificationPriorityMedium,
+		Title:             "Data Panen Disetujui",
+		Message:           fmt.Sprintf("Data panen di blok %s (%.1f kg) telah disetujui", blockName, weight),
+		CompanyID:         companyID,
+		EstateID:          estateID,
+		ActorID:           approverID,
+		ActorRole:         string(auth.UserRoleAsisten),
+		SubjectID:         mandorID,
+		RelatedEntityType: "HARVEST_RECORD",
+		RelatedEntityID:   harvestID,
+		ActionURL:         "/dashboard/mandor/history",
+		ActionLabel:       "Lihat Detail",
+		Metadata: map[string]interface{}{
+			"harvestId": harvestID,
+			"mandorId":  mandorID,
+			"block":     blockName,
+			"weight":    weight,
+			"approved":  approved,
+		},
+	}
+	if !approved {
+		event.Type = notificationModels.NotificationTypeHarvestRejected
+		event.Priority = notificationModels.NotificationPriorityHigh
+		event.Title = "Data Panen Ditolak"
+		event.Message = fmt.Sprintf("Data panen di blok %s (%.1f kg) ditolak: %s", blockName, weight, reason)
+		event.ActionURL = "/dashboard/mandor/panen"
+		event.ActionLabel = "Perbaiki Data"
+		event.Metadata["rejectedReason"] = reason
+	}
+	return r.routeEvent(ctx, event)
+}
+
+// harvestRoutingScope returns the company and estate a harvest event is
+// routed within.
+func harvestRoutingScope(companyID *string, estateID *string) (string, string) {
+	var company, estate string
+	if companyID != nil {
+		company = strings.TrimSpace(*companyID)
+	}
+	if estateID != nil {
+		estate = strings.TrimSpace(*estateID)
+	}
+	return company, estate
+}
+
+// routingRuleCompanyScope resolves the company a routing rule request applies
+// to. Company admins are pinned to their own company; super admins may target
+// any company or the global rules (nil).
+func (r *Resolver) routingRuleCompanyScope(ctx context.Context, requested *string) (*string, error) {
+	role := middleware.GetUserRoleFromContext(ctx)
+	if role == auth.UserRoleSuperAdmin {
+		if requested == nil || strings.TrimSpace(*requested) == "" {
+			return nil, nil
+		}
+		companyID := strings.TrimSpace(*requested)
+		return &companyID, nil
+	}
+
+	companyID := middleware.GetCompanyFromContext(ctx)
+	if companyID == "" {
+		return nil, fmt.Errorf("Unauthorized: Company information missing from context")
+	}
+	if requested != nil && strings.TrimSpace(*requested) != "" && strings.TrimSpace(*requested) != companyID {
+		return nil, fmt.Errorf("access denied: routing rules of another company")
+	}
+	return &companyID, nil
+}
+
+// loadScopedRoutingRule returns a rule the current user may manage.
+func (r *Resolver) loadScopedRoutingRule(ctx context.Context, id string) (*notificationModels.NotificationRoutingRule, error) {
+	rule, err := r.NotificationRoutingService.GetRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if middleware.GetUserRoleFromContext(ctx) == auth.UserRoleSuperAdmin {
+		return rule, nil
+	}
+	companyID := middleware.GetCompanyFromContext(ctx)
+	if rule.CompanyID == nil || *rule.CompanyID != companyID {
+		return nil, notificationServices.ErrRoutingRuleNotFound
+	}
+	return rule, nil
+}
+
+// applyRoutingRuleInput copies GraphQL input onto a rule model.
+func applyRoutingRuleInput(rule *notificationModels.NotificationRoutingRule, input generated.NotificationRoutingRuleInput) error {
+	rule.Name = strings.TrimSpace(input.Name)
+	rule.EventType = input.EventType
+	rule.MinPriority = notificationModels.NotificationPriorityLow
+	if input.MinPriority != nil {
+		rule.MinPriority = *input.MinPriority
+	}
+	rule.Scope = strings.ToUpper(strings.TrimSpace(input.Scope))
+	if input.IsActive != nil {
+		rule.IsActive = *input.IsActive
+	}
+
+	roles, err := json.Marshal(normalizeRoutingValues(input.RecipientRoles))
+	if err != nil {
+		return err
+	}
+	rule.RecipientRoles = string(roles)
+
+	channels, err := json.Marshal(normalizeRoutingValues(input.Channels))
+	if err != nil {
+		return err
+	}
+	rule.Channels = string(channels)
+
+	steps := make([]notificationModels.RoutingEscalationStep, 0, len(input.EscalationSteps))
+	for _, step := range input.EscalationSteps {
+		if step == nil {
+			continue
+		}
+		converted := notificationModels.RoutingEscalationStep{
+			AfterMinutes:   int(step.AfterMinutes),
+			RecipientRoles: normalizeRoutingValues(step.RecipientRoles),
+			Scope:          strings.ToUpper(strings.TrimSpace(step.Scope)),
+			Channels:       normalizeRoutingValues(step.Channels),
+		}
+		if step.Priority != nil {
+			converted.Priority = *step.Priority
+		}
+		steps = append(steps, converted)
+	}
+	rule.EscalationSteps = ""
+	if len(steps) > 0 {
+		encoded, err := json.Marshal(steps)
+		if err != nil {
+			return err
+		}
+		rule.EscalationSteps = string(encoded)
+	}
+	return nil
+}
+
+func normalizeRoutingValues(values []string) []string {
+	normalized := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		normalized = append(normalized, value)
+	}
+	return normalized
+}
+
+func toGraphQLNotificationRoutingRule(rule *notificationModels.NotificationRoutingRule) *generated.NotificationRoutingRule {
+	if rule == nil {
+		return nil
+	}
+
+	steps := make([]*generated.NotificationEscalationStep, 0)
+	for _, step := range rule.EscalationChain() {
+		converted := &generated.NotificationEscalationStep{
+			AfterMinutes:   int32(step.AfterMinutes),
+			RecipientRoles: step.RecipientRoles,
+			Scope:          step.Scope,
+			Channels:       step.Channels,
+		}
+		if step.Priority != "" {
+			priority := step.Priority
+			converted.Priority = &priority
+		}
+		steps = append(steps, converted)
+	}
+
+	roles := rule.RoleList()
+	if roles == nil {
+		roles = []string{}
+	}
+	channels := rule.ChannelList()
+	if channels == nil {
+		channels = []string{}
+	}
+
+	return &generated.NotificationRoutingRule{
+		ID:              rule.ID,
+		CompanyID:       rule.CompanyID,
+		Name:            rule.Name,
+		EventType:       rule.EventType,
+		MinPriority:     rule.MinPriority,
+		RecipientRoles:  roles,
+		Scope:           rule.Scope,
+		Channels:        channels,
+		EscalationSteps: steps,
+		IsActive:        rule.IsActive,
+		CreatedAt:       rule.CreatedAt,
+		UpdatedAt:       rule.UpdatedAt,
+	}
+}
+
+func routingRuleError(err error) error {
+	if errors.Is(err, notificationServices.ErrRoutingRuleNotFound) {
+		return fmt.Errorf("routing rule not found")
+	}
+	return err
+}
@@ -6,6 +6,7 @@ import (
 
 	"agrinovagraphql/server/internal/graphql/domain/manager"
 	"agrinovagraphql/server/internal/graphql/generated"
+	"agrinovagraphql/server/internal/middleware"
 	"agrinovagraphql/server/internal/notifications/models"
 	"agrinovagraphql/server/internal/notifications/repositories"
 	notificationServices "agrinovagraphql/server/internal/notifications/services"
@@ -161,6 +162,29 @@ func (r *queryResolver) NotificationTemplate(ctx context.Context, id string) (*m
 	return template, nil
 }
 
+// NotificationRoutingRules returns the routing rules the caller manages
+func (r *queryResolver) NotificationRoutingRules(ctx context.Context, companyID *string) ([]*generated.NotificationRoutingRule, error) {
+	if r.NotificationRoutingService == nil {
+		return []*generated.NotificationRoutingRule{}, nil
+	}
+
+	scope, err := r.routingRuleCompanyScope(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := r.NotificationRoutingService.ListRules(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*generated.NotificationRoutingRule, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, toGraphQLNotificationRoutingRule(rule))
+	}
+	return result, nil
+}
+
 // === MUTATIONS ===
 
 // CreateNotification creates a new notification
@@ -367,6 +391,9 @@ func (r *mutationResolver) UpdateNotificationPreferences(ctx context.Context, in
 	if input.QuietHoursTimezone != nil {
 		updates.QuietHoursTimezone = input.QuietHoursTimezone
 	}
+	if input.EmailDigestMode != nil {
+		updates.EmailDigestMode = input.EmailDigestMode
+	}
 
 	// Type preferences handling - skip for now as it's a JSON string
 
@@ -402,6 +429,15 @@ func (r *mutationResolver) CreateNotificationTemplate(ctx context.Context, input
 	if input.DefaultMetadata != nil {
 		template.DefaultMetadata = *input.DefaultMetadata
 	}
+	if input.EmailSubjectTemplate != nil {
+		template.EmailSubjectTemplate = *input.EmailSubjectTemplate
+	}
+	if input.EmailTextTemplate != nil {
+		template.EmailTextTemplate = *input.EmailTextTemplate
+	}
+	if input.EmailHTMLTemplate != nil {
+		template.EmailHTMLTemplate = *input.EmailHTMLTemplate
+	}
 
 	// Save template
 	if err := r.NotificationService.CreateTemplate(ctx, template); err != nil {
@@ -437,6 +473,15 @@ func (r *mutationResolver) UpdateNotificationTemplate(ctx context.Context, id st
 	if input.DefaultMetadata != nil {
 		template.DefaultMetadata = *input.DefaultMetadata
 	}
+	if input.EmailSubjectTemplate != nil {
+		template.EmailSubjectTemplate = *input.EmailSubjectTemplate
+	}
+	if input.EmailTextTemplate != nil {
+		template.EmailTextTemplate = *input.EmailTextTemplate
+	}
+	if input.EmailHTMLTemplate != nil {
+		template.EmailHTMLTemplate = *input.EmailHTMLTemplate
+	}
 
 	// Save template
 	if err := r.NotificationService.UpdateTemplate(ctx, template); err != nil {
@@ -446,6 +491,70 @@ func (r *mutationResolver) UpdateNotificationTemplate(ctx context.Context, id st
 	return template, nil
 }
 
+// CreateNotificationRoutingRule creates a routing rule for the caller's company
+// (or a global rule for super admins)
+func (r *mutationResolver) CreateNotificationRoutingRule(ctx context.Context, input generated.NotificationRoutingRuleInput) (*generated.NotificationRoutingRule, error) {
+	if r.NotificationRoutingService == nil {
+		return nil, fmt.Errorf("notification routing is not configured")
+	}
+
+	companyID, err := r.routingRuleCompanyScope(ctx, input.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := middleware.GetCurrentUserID(ctx)
+	rule := &models.NotificationRoutingRule{
+		CompanyID: companyID,
+		IsActive:  true,
+		CreatedBy: &userID,
+	}
+	if err := applyRoutingRuleInput(rule, input); err != nil {
+		return nil, err
+	}
+	if err := r.NotificationRoutingService.SaveRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return toGraphQLNotificationRoutingRule(rule), nil
+}
+
+// UpdateNotificationRoutingRule updates a routing rule
+func (r *mutationResolver) UpdateNotificationRoutingRule(ctx context.Context, id string, input generated.NotificationRoutingRuleInput) (*generated.NotificationRoutingRule, error) {
+	if r.NotificationRoutingService == nil {
+		return nil, fmt.Errorf("notification routing is not configured")
+	}
+
+	rule, err := r.loadScopedRoutingRule(ctx, id)
+	if err != nil {
+		return nil, routingRuleError(err)
+	}
+	if err := applyRoutingRuleInput(rule, input); err != nil {
+		return nil, err
+	}
+	if err := r.NotificationRoutingService.SaveRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	return toGraphQLNotificationRoutingRule(rule), nil
+}
+
+// DeleteNotificationRoutingRule deletes a routing rule
+func (r *mutationResolver) DeleteNotificationRoutingRule(ctx context.Context, id string) (bool, error) {
+	if r.NotificationRoutingService == nil {
+		return false, fmt.Errorf("notification routing is not configured")
+	}
+
+	if _, err := r.loadScopedRoutingRule(ctx, id); err != nil {
+		return false, routingRuleError(err)
+	}
+	if err := r.NotificationRoutingService.DeleteRule(ctx, id); err != nil {
+		return false, routingRuleError(err)
+	}
+
+	return true, nil
+}
+
 // DeleteNotificationTemplate deletes a notification template (admin only)
 func (r *mutationResolver) DeleteNotificationTemplate(ctx context.Context, id string) (bool, error) {
 	// TODO: Add admin check
@@ -18,6 +18,23 @@ import (
 	"gorm.io/gorm"
 )
 
+// Block is the resolver for the block field.
+func (r *perawatanRecordResolver) Block(ctx context.Context, obj *generated.PerawatanRecord) (*masterDomain.Block, error) {
+	if obj.Block != nil && obj.Block.ID != "" {
+		r.loaders(ctx).PrimeBlock(obj.Block)
+		return obj.Block, nil
+	}
+	return loadRequired(ctx, r.loaders(ctx).Blocks, "block", obj.BlockID)
+}
+
+// Pekerja is the resolver for the pekerja field.
+func (r *perawatanRecordResolver) Pekerja(ctx context.Context, obj *generated.PerawatanRecord) (*authDomain.User, error) {
+	if obj.Pekerja != nil && obj.Pekerja.ID != "" {
+		return obj.Pekerja, nil
+	}
+	return loadRequired(ctx, r.loaders(ctx).Users, "pekerja", obj.PekerjaID)
+}
+
 type perawatanRecordModel struct {
 	ID                 string             `gorm:"primaryKey;column:id;type:uuid;default:gen_random_uuid()"`
 	BlockID            string             `gorm:"column:block_id;type:uuid"`
@@ -706,3 +723,6 @@ func (r *queryResolver) PerawatanMaterialUsageRecordsByRecord(ctx context.Contex
 
 	return mapPerawatanMaterialUsageModels(usages), nil
 }
+
+// PerawatanRecordResolver handles field resolvers for PerawatanRecord
+type perawatanRecordResolver struct{ *Resolver }
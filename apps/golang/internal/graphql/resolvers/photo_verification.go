@@ -0,0 +1,66 @@
+package resolvers
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"agrinovagraphql/server/internal/graphql/domain/asisten"
+	"agrinovagraphql/server/internal/graphql/domain/mandor"
+	"agrinovagraphql/server/internal/photoverify"
+)
+
+// recordPhotoVerifications loads the photo checks of one harvest or guest log.
+func (r *Resolver) recordPhotoVerifications(ctx context.Context, recordType photoverify.RecordType, recordID string) ([]*photoverify.Verification, error) {
+	if r.PhotoVerificationService == nil || recordID == "" {
+		return []*photoverify.Verification{}, nil
+	}
+	byRecord, err := r.PhotoVerificationService.ForRecords(ctx, recordType, []string{recordID})
+	if err != nil {
+		return nil, err
+	}
+	if verifications := byRecord[recordID]; verifications != nil {
+		return verifications, nil
+	}
+	return []*photoverify.Verification{}, nil
+}
+
+// verifyHarvestPhoto checks the EXIF of a harvest's stored photo. Failures
+// are logged; they never fail the sync that saved the photo.
+func (r *Resolver) verifyHarvestPhoto(ctx context.Context, record *mandor.HarvestRecord) {
+	if r.PhotoVerificationService == nil || record == nil || record.PhotoURL == nil || strings.TrimSpace(*record.PhotoURL) == "" {
+		return
+	}
+	subject := photoverify.Subject{
+		RecordType: photoverify.RecordHarvest,
+		RecordID:   record.ID,
+		PhotoURL:   strings.TrimSpace(*record.PhotoURL),
+	}
+	if _, err := r.PhotoVerificationService.VerifyStored(ctx, subject); err != nil {
+		log.Printf("failed to verify harvest photo %s: %v", record.ID, err)
+	}
+}
+
+// applyPhotoVerificationIssues turns flagged harvest photos into approval
+// warnings so the asisten sees them before approving.
+func (r *Resolver) applyPhotoVerificationIssues(ctx context.Context, item *asisten.ApprovalItem) {
+	verifications, err := r.recordPhotoVerifications(ctx, photoverify.RecordHarvest, item.ID)
+	if err != nil {
+		log.Printf("failed to load photo verifications for harvest %s: %v", item.ID, err)
+		return
+	}
+
+	seen := make(map[photoverify.Flag]bool)
+	for _, verification := range verifications {
+		for _, flag := range verification.Flags {
+			if seen[flag] {
+				continue
+			}
+			seen[flag] = true
+			item.ValidationIssues = append(item.ValidationIssues, flag.Description())
+		}
+	}
+	if len(seen) > 0 && item.ValidationStatus == asisten.ValidationStatusValid {
+		item.ValidationStatus = asisten.ValidationStatusWarning
+	}
+}
@@ -0,0 +1,34 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.83
+
+import (
+	"agrinovagraphql/server/internal/graphql/domain/asisten"
+	"agrinovagraphql/server/internal/graphql/domain/mandor"
+	"agrinovagraphql/server/internal/graphql/domain/satpam"
+	"agrinovagraphql/server/internal/photoverify"
+	"context"
+)
+
+// PhotoVerifications is the resolver for the photoVerifications field.
+func (r *approvalItemResolver) PhotoVerifications(ctx context.Context, obj *asisten.ApprovalItem) ([]*photoverify.Verification, error) {
+	return r.recordPhotoVerifications(ctx, photoverify.RecordHarvest, obj.ID)
+}
+
+// PhotoVerifications is the resolver for the photoVerifications field.
+func (r *harvestRecordResolver) PhotoVerifications(ctx context.Context, obj *mandor.HarvestRecord) ([]*photoverify.Verification, error) {
+	return r.recordPhotoVerifications(ctx, photoverify.RecordHarvest, obj.ID)
+}
+
+// PhotoVerifications is the resolver for the photoVerifications field.
+func (r *satpamGuestLogResolver) PhotoVerifications(ctx context.Context, obj *satpam.SatpamGuestLog) ([]*photoverify.Verification, error) {
+	return r.recordPhotoVerifications(ctx, photoverify.RecordGuestLog, obj.ID)
+}
+
+// ApprovalItemResolver handles field resolvers for ApprovalItem
+type approvalItemResolver struct{ *Resolver }
+
+// HarvestRecordResolver handles field resolvers for HarvestRecord
+type harvestRecordResolver struct{ *Resolver }
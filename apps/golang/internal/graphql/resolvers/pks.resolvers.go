@@ -5,11 +5,20 @@ package resolvers
 // Code generated by github.com/99designs/gqlgen version v0.17.78
 
 import (
+	"agrinovagraphql/server/internal/graphql/domain/mandor"
 	"agrinovagraphql/server/internal/graphql/generated"
 	"context"
 	"fmt"
 )
 
+// HarvestRecord is the resolver for the harvestRecord field.
+func (r *pKSRecordResolver) HarvestRecord(ctx context.Context, obj *generated.PKSRecord) (*mandor.HarvestRecord, error) {
+	if obj.HarvestRecord != nil {
+		return obj.HarvestRecord, nil
+	}
+	return loadRequired(ctx, r.loaders(ctx).HarvestRecords, "harvest record", obj.HarvestRecordID)
+}
+
 // CreatePKSRecord is the resolver for the createPKSRecord field.
 func (r *mutationResolver) CreatePKSRecord(ctx context.Context, input generated.CreatePKSRecordInput) (*generated.PKSRecord, error) {
 	panic(fmt.Errorf("not implemented: CreatePKSRecord - createPKSRecord"))
@@ -54,3 +63,6 @@ func (r *queryResolver) BjrCalculations(ctx context.Context) ([]*generated.BJRCa
 func (r *queryResolver) BjrCalculationByPks(ctx context.Context, pksRecordID string) (*generated.BJRCalculation, error) {
 	panic(fmt.Errorf("not implemented: BjrCalculationByPks - bjrCalculationByPKS"))
 }
+
+// PKSRecordResolver handles field resolvers for PKSRecord
+type pKSRecordResolver struct{ *Resolver }
@@ -5,8 +5,6 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strings"
 
 	"agrinovagraphql/server/internal/middleware"
@@ -78,18 +76,26 @@ func (r *Resolver) HandleProfileAvatarUpload(c *gin.Context) {
 		return
 	}
 
-	absoluteDir := r.uploadAbsolutePath("avatars", userID)
-	if err := os.MkdirAll(absoluteDir, 0755); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+	companyID := middleware.GetCompanyFromContext(ctx)
+	if !r.reserveUploadQuota(c, companyID, fileHeader.Size) {
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		r.releaseUploadQuota(ctx, companyID, fileHeader.Size)
+		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
-			"message": fmt.Sprintf("failed to create upload directory: %v", err),
+			"message": fmt.Sprintf("failed to read upload: %v", err),
 		})
 		return
 	}
+	defer file.Close()
 
 	storedFileName := uuid.NewString() + ext
-	absolutePath := filepath.Join(absoluteDir, storedFileName)
-	if err := c.SaveUploadedFile(fileHeader, absolutePath); err != nil {
+	filePath, err := r.saveUpload(ctx, file, fileHeader.Size, contentType, "avatars", userID, storedFileName)
+	if err != nil {
+		r.releaseUploadQuota(ctx, companyID, fileHeader.Size)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"message": fmt.Sprintf("failed to save file: %v", err),
@@ -97,7 +103,6 @@ func (r *Resolver) HandleProfileAvatarUpload(c *gin.Context) {
 		return
 	}
 
-	filePath := uploadURLPath("avatars", userID, storedFileName)
 	c.JSON(http.StatusOK, gin.H{
 		"success":      true,
 		"message":      "avatar uploaded successfully",
@@ -2,13 +2,14 @@ package resolvers
 
 import (
 	"context"
-	"sync"
+	"log"
 
 	"gorm.io/gorm"
 
 	authModule "agrinovagraphql/server/internal/auth"
 	authResolvers "agrinovagraphql/server/internal/auth/resolvers"
 	authServices "agrinovagraphql/server/internal/auth/services"
+	companyServices "agrinovagraphql/server/internal/company/services"
 	employeeServices "agrinovagraphql/server/internal/employee/services"
 	featureResolvers "agrinovagraphql/server/internal/features/resolvers"
 	featureServices "agrinovagraphql/server/internal/features/services"
@@ -26,12 +27,19 @@ import (
 	notificationRepositories "agrinovagraphql/server/internal/notifications/repositories"
 	notificationServices "agrinovagraphql/server/internal/notifications/services"
 	panenResolvers "agrinovagraphql/server/internal/panen/resolvers"
+	"agrinovagraphql/server/internal/photoupload"
+	"agrinovagraphql/server/internal/photoverify"
+	"agrinovagraphql/server/internal/ratelimit"
 	rbacResolvers "agrinovagraphql/server/internal/rbac/resolvers"
 	rbacServices "agrinovagraphql/server/internal/rbac/services"
+	schedulerServices "agrinovagraphql/server/internal/scheduler/services"
 	syncServices "agrinovagraphql/server/internal/sync/services"
 	websocketResolvers "agrinovagraphql/server/internal/websocket/resolvers"
 	websocketServices "agrinovagraphql/server/internal/websocket/services"
 	weighingServices "agrinovagraphql/server/internal/weighing/services"
+	"agrinovagraphql/server/pkg/database"
+	"agrinovagraphql/server/pkg/email"
+	"agrinovagraphql/server/pkg/storage"
 )
 
 // This file will not be regenerated automatically.
@@ -40,8 +48,10 @@ import (
 
 type Resolver struct {
 	db *gorm.DB
-	// uploadsDir is the filesystem root directory mapped to `/uploads`.
-	uploadsDir string
+	// uploads stores the photos and documents served under `/uploads`.
+	uploads storage.Store
+	// uploadURLs signs the short-lived `/uploads` links handed to clients.
+	uploadURLs *storage.URLSigner
 
 	// Services
 	RoleHierarchyService *authServices.RoleHierarchyService
@@ -53,6 +63,39 @@ type Resolver struct {
 	APIKeyService        *authServices.APIKeyService
 	FeatureService       *featureServices.FeatureService
 	GateCheckService     *gateCheckServices.GateCheckService
+	// AttendanceService pairs employee gate scans into attendance reports.
+	AttendanceService *gateCheckServices.AttendanceService
+	// GateWatchlistService manages the gate watchlist and supervisor overrides.
+	GateWatchlistService *gateCheckServices.GateWatchlistService
+	// GateOverstayService holds the stay limits used by the overstay detector.
+	GateOverstayService *gateCheckServices.GateOverstayService
+	// PhotoVerificationService checks harvest and gate photo EXIF against their records.
+	PhotoVerificationService *photoverify.Service
+	// CompanySettingsService backs companySettings and is shared with the auth middleware.
+	CompanySettingsService *companyServices.CompanySettingsService
+	// TenantPlanService enforces subscription plan limits and suspension.
+	TenantPlanService *companyServices.TenantPlanService
+	// CompanyUserAdminService backs COMPANY_ADMIN user management and activity logs.
+	CompanyUserAdminService *companyServices.CompanyUserAdminService
+	// UserImportService applies spreadsheet imports of users and assignments.
+	UserImportService *masterServices.UserImportService
+	// MasterDataImportService runs spreadsheet imports and exports of the
+	// estate hierarchy and employees.
+	MasterDataImportService *masterServices.MasterDataImportService
+	// Scheduler runs database-defined cron jobs on the leader instance.
+	Scheduler *schedulerServices.Scheduler
+	// VehicleTaxReminderService sends vehicle tax due-date reminders in-app and,
+	// when main.go has an FCM provider, by push.
+	VehicleTaxReminderService *notificationServices.VehicleTaxReminderService
+	// EmailNotificationService sends queued notification emails and digests.
+	// It is nil when no email provider is configured.
+	EmailNotificationService *notificationServices.EmailNotificationService
+	// NotificationRoutingService applies company routing rules and escalations.
+	// main.go replaces it with an FCM-enabled instance when push is configured.
+	NotificationRoutingService *notificationServices.NotificationRoutingService
+	// PhotoUploadService backs the resumable photo upload routes; its expired
+	// sessions are purged by the photo_upload_cleanup job.
+	PhotoUploadService *photoupload.Service
 	// UnifiedAuthService and LogoutService disabled due to undefined GraphQL types
 	// UnifiedAuthService   *authServices.UnifiedAuthService
 	// LogoutService        *authServices.LogoutService
@@ -93,8 +136,9 @@ type Resolver struct {
 	BkmSyncService   *syncServices.BkmSyncService
 	BkmReportService *syncServices.BkmReportService
 
-	satpamNotificationOutboxOnce   sync.Once
-	satpamNotificationOutboxCancel context.CancelFunc
+	// ReadRouter sends reports, analytics and history lists to read replicas
+	// (injected from main.go; nil keeps every read on the primary)
+	ReadRouter *database.ReadRouter
 }
 
 // HarvestFCMNotifier defines the FCM notification capability used by harvest flows.
@@ -121,7 +165,9 @@ func NewResolver(
 	rateLimitService *authServices.RateLimitService,
 	securityLoggingService *authServices.SecurityLoggingService,
 	jwtSecret string,
-	uploadsDir string,
+	uploads storage.Store,
+	uploadURLs *storage.URLSigner,
+	rateLimits ratelimit.Counter,
 ) *Resolver {
 	// authModuleV2 is now passed in
 
@@ -145,7 +191,7 @@ func NewResolver(
 
 	// Initialize gatecheck domain
 	gateCheckResolver := gateCheckResolvers.NewGateCheckResolver(db, authMiddleware)
-	gateCheckService := gateCheckServices.NewGateCheckService(db, jwtSecret, uploadsDir)
+	gateCheckService := gateCheckServices.NewGateCheckService(db, jwtSecret, uploads)
 
 	// Initialize employee service
 	employeeService := employeeServices.NewEmployeeService(db)
@@ -154,6 +200,16 @@ func NewResolver(
 	notificationRepo := notificationRepositories.NewNotificationRepository(db)
 	notificationService := notificationServices.NewNotificationService(notificationRepo, nil, nil)
 
+	// Email channel is enabled only when an SMTP or SendGrid provider is configured
+	var emailNotificationService *notificationServices.EmailNotificationService
+	emailSender, err := email.NewSenderFromEnv()
+	if err != nil {
+		log.Printf("Warning: email notifications disabled: %v", err)
+	} else if emailSender != nil {
+		notificationService.EnableEmailDelivery(true)
+		emailNotificationService = notificationServices.NewEmailNotificationService(notificationRepo, emailSender)
+	}
+
 	// Initialize weighing service
 	weighingService := weighingServices.NewWeighingService(db)
 
@@ -189,6 +245,7 @@ func NewResolver(
 		securityLoggingService,
 		nil,
 	)
+	forgotPasswordService.SetRateLimitCounter(rateLimits)
 	globalAuthResolver.SetForgotPasswordService(forgotPasswordService)
 
 	if authModuleV2 != nil {
@@ -198,6 +255,14 @@ func NewResolver(
 		globalAuthResolver.SetUserManagementService(authModuleV2.UserManagementService)
 	}
 
+	// Initialize company user administration (COMPANY_ADMIN)
+	var userLifecycle companyServices.UserLifecycle
+	if authModuleV2 != nil && authModuleV2.UserManagementService != nil {
+		userLifecycle = authModuleV2.UserManagementService
+	}
+	companyUserAdminService := companyServices.NewCompanyUserAdminService(db, roleHierarchyService, userLifecycle)
+	companyUserAdminService.SetEventPublisher(globalCompanyAdminSubscriptionHub)
+
 	// Initialize hierarchy service
 	hierarchyService := authServices.NewHierarchyService(db)
 
@@ -207,7 +272,8 @@ func NewResolver(
 
 	resolver := &Resolver{
 		db:                            db,
-		uploadsDir:                    normalizeUploadsRoot(uploadsDir),
+		uploads:                       uploads,
+		uploadURLs:                    uploadURLs,
 		HierarchyService:              hierarchyService,
 		RoleHierarchyService:          roleHierarchyService,
 		RBACService:                   rbacService,
@@ -218,6 +284,19 @@ func NewResolver(
 		APIKeyService:                 apiKeyService,
 		FeatureService:                featureService,
 		GateCheckService:              gateCheckService,
+		AttendanceService:             gateCheckServices.NewAttendanceService(db),
+		GateWatchlistService:          gateCheckServices.NewGateWatchlistService(db),
+		GateOverstayService:           gateCheckServices.NewGateOverstayService(db),
+		PhotoVerificationService:      photoverify.NewService(db, uploads),
+		CompanySettingsService:        companyServices.NewCompanySettingsService(db),
+		TenantPlanService:             companyServices.NewTenantPlanService(db),
+		CompanyUserAdminService:       companyUserAdminService,
+		UserImportService:             masterServices.NewUserImportService(masterRepository, db, passwordService),
+		MasterDataImportService:       masterServices.NewMasterDataImportService(masterRepository, db, employeeService),
+		Scheduler:                     schedulerServices.NewScheduler(db),
+		VehicleTaxReminderService:     notificationServices.NewVehicleTaxReminderService(db, notificationService, nil, nil),
+		EmailNotificationService:      emailNotificationService,
+		NotificationRoutingService:    notificationServices.NewNotificationRoutingService(db, notificationService, nil, hierarchyService),
 		AuthResolver:                  globalAuthResolver,
 		RBACResolver:                  rbacResolver,
 		FeatureResolver:               featureResolver,
@@ -230,7 +309,8 @@ func NewResolver(
 		BkmReportService:              bkmReportService,
 	}
 
-	resolver.startSatpamNotificationOutboxWorker()
+	notificationService.SetRoutingService(resolver.NotificationRoutingService)
+	resolver.registerScheduledJobs()
 
 	return resolver
 }
@@ -44,6 +44,10 @@ func (r *mutationResolver) RegisterGuest(ctx context.Context, input satpam.Creat
 		}, nil
 	}
 
+	if result != nil {
+		r.raiseGateWatchlistAlert(ctx, result.WatchlistAlert)
+	}
+
 	if result != nil && result.Success && result.GuestLog != nil {
 		publishSatpamVehicleEntry(result.GuestLog)
 		if err := r.persistSatpamVehicleEntryNotification(ctx, result.GuestLog); err != nil {
@@ -93,7 +97,9 @@ func (r *mutationResolver) ProcessGuestExit(ctx context.Context, input satpam.Pr
 		if err := r.persistSatpamVehicleExitNotification(ctx, result.GuestLog); err != nil {
 			log.Printf("failed to persist satpam vehicle exit notification: %v", err)
 		}
-		if result.WasOverstay {
+		if result.ResolvedOverstayLog != nil {
+			r.resolveSatpamOverstayAlert(ctx, result.ResolvedOverstayLog, result.OverstayResolvedAt)
+		} else if result.WasOverstay {
 			publishSatpamOverstayAlert(buildSatpamOverstayAlert(result.GuestLog))
 			if err := r.persistSatpamOverstayNotification(ctx, result.GuestLog); err != nil {
 				log.Printf("failed to persist satpam overstay notification: %v", err)
@@ -133,7 +139,9 @@ func (r *mutationResolver) SyncSatpamRecords(ctx context.Context, input satpam.S
 		}, nil
 	}
 
+	syncStart := time.Now()
 	result, err := r.GateCheckService.SyncSatpamRecords(ctx, input)
+	recordGateCheckSync(input, result, time.Since(syncStart))
 	if err != nil {
 		log.Printf("SyncSatpamRecords error: %v", err)
 		return &satpam.SatpamSyncResult{
@@ -147,6 +155,10 @@ func (r *mutationResolver) SyncSatpamRecords(ctx context.Context, input satpam.S
 			r.emitSatpamGuestLogEventsFromSync(ctx, input, result)
 		}
 
+		for _, alert := range result.WatchlistAlerts {
+			r.raiseGateWatchlistAlert(ctx, alert)
+		}
+
 		if result.Success {
 			r.emitSatpamSyncUpdate(ctx, input.DeviceID)
 		}
@@ -166,7 +178,9 @@ func (r *mutationResolver) SyncSatpamPhotos(ctx context.Context, input generated
 			}},
 		}, nil
 	}
+	syncStart := time.Now()
 	result, err := r.GateCheckService.SyncSatpamPhotos(ctx, input)
+	recordGateCheckPhotoSync(input, result, time.Since(syncStart))
 	if err != nil {
 		log.Printf("SyncSatpamPhotos error: %v", err)
 		return &generated.SatpamPhotoSyncResult{
@@ -194,7 +208,9 @@ func (r *mutationResolver) SyncEmployeeLog(ctx context.Context, input generated.
 			ServerTimestamp: time.Now(),
 		}, nil
 	}
+	syncStart := time.Now()
 	result, err := r.GateCheckService.SyncEmployeeLog(ctx, input)
+	recordEmployeeLogSync(result, time.Since(syncStart))
 	if err != nil {
 		log.Printf("SyncEmployeeLog error: %v", err)
 		return &generated.EmployeeLogSyncResult{
@@ -487,7 +503,14 @@ func (r *queryResolver) ValidateSatpamQR(ctx context.Context, input generated.Va
 			AllowedOperations: []satpam.GateIntent{},
 		}, nil
 	}
-	return r.GateCheckService.ValidateQR(ctx, input.QRData, satpam.GateIntent(input.ExpectedIntent), input.DeviceID)
+	result, err := r.GateCheckService.ValidateQR(ctx, input.QRData, satpam.GateIntent(input.ExpectedIntent), input.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if result != nil {
+		r.raiseGateWatchlistAlert(ctx, result.WatchlistAlert)
+	}
+	return result, nil
 }
 
 // SearchGuest is the resolver for the searchGuest field.
@@ -522,6 +545,9 @@ func (r *queryResolver) SearchGuest(ctx context.Context, query string) ([]*satpa
 
 // SatpamHistory is the resolver for the satpamHistory field.
 func (r *queryResolver) SatpamHistory(ctx context.Context, filter *satpam.SatpamHistoryFilter) (*satpam.SatpamHistoryResponse, error) {
+	ctx, release := r.ReadRouter.UseReplica(ctx)
+	defer release()
+
 	if r.GateCheckService == nil {
 		return nil, errors.New("gate check service not initialized")
 	}
@@ -938,6 +964,33 @@ func (r *mutationResolver) persistSatpamNotification(
 		return nil
 	}
 
+	entityID := getSatpamRecordEntityID(record)
+	plate, driverName := getSatpamNotificationIdentity(record)
+
+	// Company routing rules replace the built-in manager fan-out when configured.
+	if r.routeEvent(ctx, &notificationServices.RoutingEvent{
+		Type:              options.NotificationType,
+		Priority:          options.Priority,
+		Title:             options.Title,
+		Message:           options.Message,
+		CompanyID:         companyID,
+		ActorID:           strings.TrimSpace(record.CreatedBy),
+		ActorRole:         "SATPAM",
+		RelatedEntityType: "GATE_CHECK_RECORD",
+		RelatedEntityID:   entityID,
+		ActionURL:         "/dashboard/manager/gate-logs",
+		ActionLabel:       "Lihat Log",
+		Metadata: map[string]interface{}{
+			"gateCheckId":   entityID,
+			"vehicleNumber": plate,
+			"driverName":    driverName,
+			"intent":        options.Intent,
+		},
+		IdempotencyKey: options.IdempotencyKey,
+	}) {
+		return nil
+	}
+
 	recipients, err := r.getSatpamNotificationRecipients(ctx, companyID)
 	if err != nil {
 		return err
@@ -951,7 +1004,6 @@ func (r *mutationResolver) persistSatpamNotification(
 		recipientIDs = append(recipientIDs, recipient.ID)
 	}
 
-	entityID := getSatpamRecordEntityID(record)
 	existingRecipientIDs, err := r.getExistingSatpamNotificationRecipientIDs(
 		ctx,
 		options.NotificationType,
@@ -962,8 +1014,6 @@ func (r *mutationResolver) persistSatpamNotification(
 		return err
 	}
 
-	plate, driverName := getSatpamNotificationIdentity(record)
-
 	for _, recipient := range recipients {
 		if _, exists := existingRecipientIDs[recipient.ID]; exists {
 			continue
@@ -1036,8 +1086,6 @@ func (r *mutationResolver) enqueueSatpamNotificationsForSync(
 		return
 	}
 
-	r.startSatpamNotificationOutboxWorker()
-
 	jobs, err := buildSatpamSyncNotificationOutboxJobs(records, deviceID, transactionID)
 	if err != nil {
 		fmt.Printf("failed to queue synced satpam notification: %v\n", err)
@@ -1049,7 +1097,9 @@ func (r *mutationResolver) enqueueSatpamNotificationsForSync(
 
 	if err := r.db.WithContext(ctx).Create(&jobs).Error; err != nil {
 		fmt.Printf("failed to queue synced satpam notification: %v\n", err)
+		return
 	}
+	r.kickSatpamNotificationOutbox(context.WithoutCancel(ctx))
 }
 
 func buildSatpamSyncNotificationOutboxJobs(
@@ -1155,9 +1205,6 @@ func (r *mutationResolver) persistSatpamNotificationsForSync(
 	if err != nil {
 		return err
 	}
-	if len(recipients) == 0 {
-		return nil
-	}
 
 	summaries := buildSatpamSyncNotificationSummaries(records)
 	if len(summaries) == 0 {
@@ -1180,6 +1227,15 @@ func (r *mutationResolver) persistSatpamNotificationsForSync(
 		)
 		message := buildSatpamSyncSummaryMessage(summary)
 
+		// Company routing rules replace the built-in manager fan-out when configured.
+		routed, err := r.routeSatpamSyncSummary(ctx, summary, companyID, senderID, deviceID, transactionID, relatedEntityID, idempotencyKey)
+		if err != nil {
+			return err
+		}
+		if routed {
+			continue
+		}
+
 		for _, recipient := range recipients {
 			input := &notificationServices.CreateNotificationInput{
 				Type:               summary.NotificationType,
@@ -1599,6 +1655,15 @@ func (r *subscriptionResolver) SatpamOverstayAlert(ctx context.Context) (<-chan
 	return subscribeSatpamOverstayAlert(ctx)
 }
 
+// SatpamWatchlistAlert is the resolver for the satpamWatchlistAlert subscription field.
+func (r *subscriptionResolver) SatpamWatchlistAlert(ctx context.Context) (<-chan *satpam.GateWatchlistAlert, error) {
+	companyIDs, err := r.gateWatchlistSubscriberCompanies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return subscribeSatpamWatchlistAlert(ctx, companyIDs)
+}
+
 // SatpamSyncUpdate is the resolver for the satpamSyncUpdate subscription field.
 func (r *subscriptionResolver) SatpamSyncUpdate(ctx context.Context, deviceID string) (<-chan *satpam.SatpamSyncStatus, error) {
 	normalizedDeviceID := strings.TrimSpace(deviceID)
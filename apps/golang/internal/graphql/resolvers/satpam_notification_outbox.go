@@ -9,6 +9,7 @@ import (
 
 	notificationModels "agrinovagraphql/server/internal/notifications/models"
 	notificationServices "agrinovagraphql/server/internal/notifications/services"
+	schedulerServices "agrinovagraphql/server/internal/scheduler/services"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -20,9 +21,8 @@ const (
 	satpamNotificationOutboxStatusCompleted  = "COMPLETED"
 	satpamNotificationOutboxStatusFailed     = "FAILED"
 
-	satpamNotificationOutboxBatchSize    = 12
-	satpamNotificationOutboxMaxAttempts  = 5
-	satpamNotificationOutboxPollInterval = 2 * time.Second
+	satpamNotificationOutboxBatchSize   = 12
+	satpamNotificationOutboxMaxAttempts = 5
 )
 
 type satpamNotificationOutboxJob struct {
@@ -50,56 +50,53 @@ func (satpamNotificationOutboxJob) TableName() string {
 	return "satpam_notification_outbox"
 }
 
-func (r *Resolver) startSatpamNotificationOutboxWorker() {
+// kickSatpamNotificationOutbox delivers freshly queued rows right away. The
+// satpam_notification_outbox scheduler job drains whatever this misses and
+// retries failed deliveries; SKIP LOCKED claims keep the two from sending a
+// row twice.
+func (r *Resolver) kickSatpamNotificationOutbox(ctx context.Context) {
 	if r == nil || r.db == nil || r.NotificationService == nil {
 		return
 	}
 
-	r.satpamNotificationOutboxOnce.Do(func() {
-		workerCtx, cancel := context.WithCancel(context.Background())
-		r.satpamNotificationOutboxCancel = cancel
-
-		go func() {
-			defer func() {
-				if recovered := recover(); recovered != nil {
-					fmt.Printf("satpam notification outbox worker stopped: panic: %v\n", recovered)
-				}
-			}()
-
-			r.runSatpamNotificationOutboxWorker(workerCtx)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				fmt.Printf("satpam notification outbox delivery panicked: %v\n", recovered)
+			}
 		}()
-	})
-}
 
-func (r *Resolver) runSatpamNotificationOutboxWorker(ctx context.Context) {
-	ticker := time.NewTicker(satpamNotificationOutboxPollInterval)
-	defer ticker.Stop()
-
-	r.processSatpamNotificationOutboxBatches(ctx)
-
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			r.processSatpamNotificationOutboxBatches(ctx)
+		if _, err := r.processSatpamNotificationOutboxBatches(ctx); err != nil {
+			fmt.Printf("failed processing satpam notification outbox batch: %v\n", err)
 		}
+	}()
+}
+
+// runSatpamNotificationOutboxJob drains the outbox on the scheduler leader.
+func (r *Resolver) runSatpamNotificationOutboxJob(ctx context.Context, _ schedulerServices.JobContext) (string, error) {
+	if r.NotificationService == nil {
+		return "skipped: notification service is not configured", nil
 	}
+
+	processed, err := r.processSatpamNotificationOutboxBatches(ctx)
+	return fmt.Sprintf("%d outbox row(s) processed", processed), err
 }
 
-func (r *Resolver) processSatpamNotificationOutboxBatches(ctx context.Context) {
+func (r *Resolver) processSatpamNotificationOutboxBatches(ctx context.Context) (int, error) {
 	if !r.db.WithContext(ctx).Migrator().HasTable(&satpamNotificationOutboxJob{}) {
-		return
+		return 0, nil
 	}
+	defer r.refreshSatpamNotificationOutboxMetrics(ctx)
 
+	total := 0
 	for {
 		processed, err := r.processSatpamNotificationOutboxBatch(ctx, satpamNotificationOutboxBatchSize)
+		total += processed
 		if err != nil {
-			fmt.Printf("failed processing satpam notification outbox batch: %v\n", err)
-			return
+			return total, err
 		}
 		if processed < satpamNotificationOutboxBatchSize {
-			return
+			return total, nil
 		}
 	}
 }
@@ -117,6 +114,7 @@ func (r *Resolver) processSatpamNotificationOutboxBatch(ctx context.Context, lim
 		deliveryCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
 		deliveryErr := r.deliverSatpamNotificationOutboxJob(deliveryCtx, &job)
 		cancel()
+		recordOutboxDelivery(satpamNotificationOutboxName, deliveryErr)
 
 		if deliveryErr != nil {
 			if err := r.markSatpamNotificationOutboxJobFailure(ctx, &job, deliveryErr); err != nil {
@@ -252,14 +250,6 @@ func (r *Resolver) deliverSatpamNotificationOutboxJob(ctx context.Context, job *
 		return nil
 	}
 
-	recipients, err := r.getSatpamNotificationRecipients(ctx, companyID)
-	if err != nil {
-		return err
-	}
-	if len(recipients) == 0 {
-		return nil
-	}
-
 	var samplePlates []string
 	if strings.TrimSpace(job.SamplePlatesJSON) != "" {
 		if err := json.Unmarshal([]byte(job.SamplePlatesJSON), &samplePlates); err != nil {
@@ -295,6 +285,17 @@ func (r *Resolver) deliverSatpamNotificationOutboxJob(ctx context.Context, job *
 		senderID = strings.TrimSpace(*job.SenderID)
 	}
 
+	// Company routing rules replace the built-in manager fan-out when configured.
+	routed, err := r.routeSatpamSyncSummary(ctx, summary, companyID, senderID, job.DeviceID, job.TransactionID, relatedEntityID, idempotencyKey)
+	if err != nil || routed {
+		return err
+	}
+
+	recipients, err := r.getSatpamNotificationRecipients(ctx, companyID)
+	if err != nil {
+		return err
+	}
+
 	message := buildSatpamSyncSummaryMessage(summary)
 	for _, recipient := range recipients {
 		input := &notificationServices.CreateNotificationInput{
@@ -333,3 +334,48 @@ func (r *Resolver) deliverSatpamNotificationOutboxJob(ctx context.Context, job *
 
 	return nil
 }
+
+// routeSatpamSyncSummary sends one synced gate summary through the routing
+// rules. It reports whether a rule matched; a routing error is returned so the
+// outbox retries the row, and the idempotency key keeps the retry from
+// notifying recipients twice.
+func (r *Resolver) routeSatpamSyncSummary(
+	ctx context.Context,
+	summary satpamSyncNotificationSummary,
+	companyID string,
+	senderID string,
+	deviceID string,
+	transactionID string,
+	relatedEntityID string,
+	idempotencyKey string,
+) (bool, error) {
+	if r.NotificationRoutingService == nil {
+		return false, nil
+	}
+
+	result, err := r.NotificationRoutingService.Route(ctx, &notificationServices.RoutingEvent{
+		Type:              summary.NotificationType,
+		Priority:          summary.Priority,
+		Title:             summary.Title,
+		Message:           buildSatpamSyncSummaryMessage(summary),
+		CompanyID:         companyID,
+		ActorID:           senderID,
+		ActorRole:         "SATPAM",
+		RelatedEntityType: "GATE_CHECK_SYNC",
+		RelatedEntityID:   relatedEntityID,
+		ActionURL:         "/dashboard/manager/gate-logs",
+		ActionLabel:       "Lihat Log",
+		Metadata: map[string]interface{}{
+			"count":         summary.Count,
+			"intent":        summary.Intent,
+			"deviceId":      strings.TrimSpace(deviceID),
+			"transactionId": strings.TrimSpace(transactionID),
+			"samplePlates":  summary.SamplePlates,
+		},
+		IdempotencyKey: idempotencyKey,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed routing sync %s notification: %w", summary.Intent, err)
+	}
+	return result != nil && result.Matched, nil
+}
@@ -6,28 +6,61 @@ import (
 	"sync"
 
 	"agrinovagraphql/server/internal/graphql/domain/satpam"
+	"agrinovagraphql/server/internal/pubsub"
 )
 
 type satpamGuestLogSubscriberSet map[chan *satpam.SatpamGuestLog]struct{}
 type satpamOverstaySubscriberSet map[chan *satpam.VehicleInsideInfo]struct{}
 type satpamSyncSubscriberSet map[chan *satpam.SatpamSyncStatus]struct{}
 
+// satpamWatchlistSubscriberSet maps each subscriber to the companies it may
+// see; a nil set receives every company.
+type satpamWatchlistSubscriberSet map[chan *satpam.GateWatchlistAlert]map[string]struct{}
+
 type satpamSubscriptionHub struct {
 	mu sync.RWMutex
 
 	vehicleEntrySubscribers satpamGuestLogSubscriberSet
 	vehicleExitSubscribers  satpamGuestLogSubscriberSet
 	overstaySubscribers     satpamOverstaySubscriberSet
+	watchlistSubscribers    satpamWatchlistSubscriberSet
 	syncSubscribersByDevice map[string]satpamSyncSubscriberSet
+
+	events hubBus
+}
+
+// satpamSyncEvent carries a sync status together with its target device.
+type satpamSyncEvent struct {
+	DeviceID string
+	Status   *satpam.SatpamSyncStatus
 }
 
 func newSatpamSubscriptionHub() *satpamSubscriptionHub {
-	return &satpamSubscriptionHub{
+	h := &satpamSubscriptionHub{
 		vehicleEntrySubscribers: make(satpamGuestLogSubscriberSet),
 		vehicleExitSubscribers:  make(satpamGuestLogSubscriberSet),
 		overstaySubscribers:     make(satpamOverstaySubscriberSet),
+		watchlistSubscribers:    make(satpamWatchlistSubscriberSet),
 		syncSubscribersByDevice: make(map[string]satpamSyncSubscriberSet),
 	}
+	h.attach(pubsub.NewMemory())
+	return h
+}
+
+func (h *satpamSubscriptionHub) attach(bus pubsub.PubSub) {
+	h.events.attach(bus, func(bus pubsub.PubSub) []func() {
+		return []func(){
+			subscribeHubEvent(&h.events, bus, topicSatpamVehicleEntry, func(record *satpam.SatpamGuestLog) {
+				h.deliverGuestLog(record, h.vehicleEntrySubscribers)
+			}),
+			subscribeHubEvent(&h.events, bus, topicSatpamVehicleExit, func(record *satpam.SatpamGuestLog) {
+				h.deliverGuestLog(record, h.vehicleExitSubscribers)
+			}),
+			subscribeHubEvent(&h.events, bus, topicSatpamOverstay, h.deliverOverstay),
+			subscribeHubEvent(&h.events, bus, topicSatpamWatchlist, h.deliverWatchlist),
+			subscribeHubEvent(&h.events, bus, topicSatpamSyncUpdate, h.deliverSyncUpdate),
+		}
+	})
 }
 
 var globalSatpamSubscriptionHub = newSatpamSubscriptionHub()
@@ -44,6 +77,10 @@ func subscribeSatpamOverstayAlert(ctx context.Context) (<-chan *satpam.VehicleIn
 	return globalSatpamSubscriptionHub.subscribeOverstay(ctx), nil
 }
 
+func subscribeSatpamWatchlistAlert(ctx context.Context, companyIDs []string) (<-chan *satpam.GateWatchlistAlert, error) {
+	return globalSatpamSubscriptionHub.subscribeWatchlist(ctx, companyIDs), nil
+}
+
 func subscribeSatpamSyncUpdate(ctx context.Context, deviceID string) (<-chan *satpam.SatpamSyncStatus, error) {
 	return globalSatpamSubscriptionHub.subscribeSyncUpdate(ctx, deviceID), nil
 }
@@ -60,6 +97,10 @@ func publishSatpamOverstayAlert(record *satpam.VehicleInsideInfo) {
 	globalSatpamSubscriptionHub.publishOverstay(record)
 }
 
+func publishSatpamWatchlistAlert(alert *satpam.GateWatchlistAlert) {
+	globalSatpamSubscriptionHub.publishWatchlist(alert)
+}
+
 func publishSatpamSyncUpdate(deviceID string, status *satpam.SatpamSyncStatus) {
 	globalSatpamSubscriptionHub.publishSyncUpdate(deviceID, status)
 }
@@ -90,6 +131,32 @@ func (h *satpamSubscriptionHub) subscribeOverstay(ctx context.Context) <-chan *s
 	return ch
 }
 
+func (h *satpamSubscriptionHub) subscribeWatchlist(ctx context.Context, companyIDs []string) <-chan *satpam.GateWatchlistAlert {
+	ch := make(chan *satpam.GateWatchlistAlert, 16)
+
+	var companies map[string]struct{}
+	if companyIDs != nil {
+		companies = make(map[string]struct{}, len(companyIDs))
+		for _, companyID := range companyIDs {
+			companies[strings.TrimSpace(companyID)] = struct{}{}
+		}
+	}
+
+	h.mu.Lock()
+	h.watchlistSubscribers[ch] = companies
+	h.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		h.mu.Lock()
+		delete(h.watchlistSubscribers, ch)
+		h.mu.Unlock()
+		close(ch)
+	}()
+
+	return ch
+}
+
 func (h *satpamSubscriptionHub) subscribeSyncUpdate(ctx context.Context, deviceID string) <-chan *satpam.SatpamSyncStatus {
 	ch := make(chan *satpam.SatpamSyncStatus, 16)
 	normalizedDeviceID := normalizeSatpamDeviceID(deviceID)
@@ -121,17 +188,53 @@ func (h *satpamSubscriptionHub) subscribeSyncUpdate(ctx context.Context, deviceI
 }
 
 func (h *satpamSubscriptionHub) publishVehicleEntry(record *satpam.SatpamGuestLog) {
-	h.publishGuestLog(record, h.vehicleEntrySubscribers)
+	if record == nil {
+		return
+	}
+	publishHubEvent(&h.events, topicSatpamVehicleEntry, record, func(record *satpam.SatpamGuestLog) {
+		h.deliverGuestLog(record, h.vehicleEntrySubscribers)
+	})
 }
 
 func (h *satpamSubscriptionHub) publishVehicleExit(record *satpam.SatpamGuestLog) {
-	h.publishGuestLog(record, h.vehicleExitSubscribers)
+	if record == nil {
+		return
+	}
+	publishHubEvent(&h.events, topicSatpamVehicleExit, record, func(record *satpam.SatpamGuestLog) {
+		h.deliverGuestLog(record, h.vehicleExitSubscribers)
+	})
 }
 
 func (h *satpamSubscriptionHub) publishOverstay(record *satpam.VehicleInsideInfo) {
 	if record == nil {
 		return
 	}
+	publishHubEvent(&h.events, topicSatpamOverstay, record, h.deliverOverstay)
+}
+
+func (h *satpamSubscriptionHub) publishWatchlist(alert *satpam.GateWatchlistAlert) {
+	if alert == nil {
+		return
+	}
+	publishHubEvent(&h.events, topicSatpamWatchlist, alert, h.deliverWatchlist)
+}
+
+func (h *satpamSubscriptionHub) publishSyncUpdate(deviceID string, status *satpam.SatpamSyncStatus) {
+	if status == nil {
+		return
+	}
+
+	normalizedDeviceID := normalizeSatpamDeviceID(deviceID)
+	if normalizedDeviceID == "" {
+		return
+	}
+	publishHubEvent(&h.events, topicSatpamSyncUpdate, satpamSyncEvent{DeviceID: normalizedDeviceID, Status: status}, h.deliverSyncUpdate)
+}
+
+func (h *satpamSubscriptionHub) deliverOverstay(record *satpam.VehicleInsideInfo) {
+	if record == nil {
+		return
+	}
 
 	h.mu.RLock()
 	defer h.mu.RUnlock()
@@ -145,12 +248,34 @@ func (h *satpamSubscriptionHub) publishOverstay(record *satpam.VehicleInsideInfo
 	}
 }
 
-func (h *satpamSubscriptionHub) publishSyncUpdate(deviceID string, status *satpam.SatpamSyncStatus) {
-	if status == nil {
+func (h *satpamSubscriptionHub) deliverWatchlist(alert *satpam.GateWatchlistAlert) {
+	if alert == nil {
 		return
 	}
 
-	normalizedDeviceID := normalizeSatpamDeviceID(deviceID)
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch, companies := range h.watchlistSubscribers {
+		if companies != nil {
+			if _, allowed := companies[alert.CompanyID]; !allowed {
+				continue
+			}
+		}
+		select {
+		case ch <- alert:
+		default:
+			// Keep mutation path non-blocking for slow subscribers.
+		}
+	}
+}
+
+func (h *satpamSubscriptionHub) deliverSyncUpdate(event satpamSyncEvent) {
+	if event.Status == nil {
+		return
+	}
+
+	normalizedDeviceID := normalizeSatpamDeviceID(event.DeviceID)
 	if normalizedDeviceID == "" {
 		return
 	}
@@ -160,7 +285,7 @@ func (h *satpamSubscriptionHub) publishSyncUpdate(deviceID string, status *satpa
 
 	for ch := range h.syncSubscribersByDevice[normalizedDeviceID] {
 		select {
-		case ch <- status:
+		case ch <- event.Status:
 		default:
 			// Keep mutation path non-blocking for slow subscribers.
 		}
@@ -185,7 +310,7 @@ func (h *satpamSubscriptionHub) subscribeGuestLog(ctx context.Context, subscribe
 	return ch
 }
 
-func (h *satpamSubscriptionHub) publishGuestLog(record *satpam.SatpamGuestLog, subscribers satpamGuestLogSubscriberSet) {
+func (h *satpamSubscriptionHub) deliverGuestLog(record *satpam.SatpamGuestLog, subscribers satpamGuestLogSubscriberSet) {
 	if record == nil {
 		return
 	}
@@ -80,3 +80,36 @@ func TestSatpamSubscriptionHub_ContextCancellationClosesChannel(t *testing.T) {
 		t.Fatal("timed out waiting for channel to close")
 	}
 }
+
+func TestSatpamSubscriptionHub_CompanyScopedWatchlistAlert(t *testing.T) {
+	t.Parallel()
+
+	hub := newSatpamSubscriptionHub()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	chA := hub.subscribeWatchlist(ctx, []string{"company-a"})
+	chB := hub.subscribeWatchlist(ctx, []string{"company-b"})
+	chAll := hub.subscribeWatchlist(ctx, nil)
+
+	expected := &satpam.GateWatchlistAlert{HitID: "hit-1", CompanyID: "company-a"}
+	hub.publishWatchlist(expected)
+
+	for name, ch := range map[string]<-chan *satpam.GateWatchlistAlert{"company-a": chA, "all": chAll} {
+		select {
+		case got := <-ch:
+			if got == nil || got.HitID != expected.HitID {
+				t.Fatalf("unexpected %s watchlist payload: %#v", name, got)
+			}
+		case <-time.After(500 * time.Millisecond):
+			t.Fatalf("timed out waiting for %s watchlist alert", name)
+		}
+	}
+
+	select {
+	case <-chB:
+		t.Fatal("company-b subscriber should not receive company-a alert")
+	case <-time.After(150 * time.Millisecond):
+		// expected
+	}
+}
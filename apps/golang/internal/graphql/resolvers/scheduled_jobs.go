@@ -0,0 +1,362 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	sharedAuthInfra "agrinovagraphql/server/internal/auth/features/shared/infrastructure/postgres"
+	"agrinovagraphql/server/internal/graphql/generated"
+	notificationModels "agrinovagraphql/server/internal/notifications/models"
+	notificationServices "agrinovagraphql/server/internal/notifications/services"
+	schedulerModels "agrinovagraphql/server/internal/scheduler/models"
+	schedulerServices "agrinovagraphql/server/internal/scheduler/services"
+)
+
+// inactiveSessionRetention is how long revoked or expired web sessions are
+// kept for the session history before session_cleanup deletes them.
+const inactiveSessionRetention = 30 * 24 * time.Hour
+
+type scheduledJobRecipient struct {
+	ID   string `gorm:"column:id"`
+	Role string `gorm:"column:role"`
+}
+
+type weeklyHarvestSummaryStats struct {
+	TotalRecords    int64   `gorm:"column:total_records"`
+	ApprovedRecords int64   `gorm:"column:approved_records"`
+	PendingRecords  int64   `gorm:"column:pending_records"`
+	TotalWeight     float64 `gorm:"column:total_weight"`
+	TotalBunches    int64   `gorm:"column:total_bunches"`
+}
+
+// registerScheduledJobs binds the built-in job handlers to the scheduler.
+// Handlers read optional services such as ManagerNotificationService when they
+// run, so services injected from main.go after NewResolver are picked up.
+func (r *Resolver) registerScheduledJobs() {
+	if r == nil || r.Scheduler == nil {
+		return
+	}
+
+	r.Scheduler.Register(schedulerModels.JobManagerDailySummary, r.runManagerDailySummaryJob)
+	r.Scheduler.Register(schedulerModels.JobWeeklyHarvestSummary, r.runWeeklyHarvestSummaryJob)
+	r.Scheduler.Register(schedulerModels.JobVehicleTaxReminders, r.runVehicleTaxReminderJob)
+	r.Scheduler.RegisterSystem(schedulerModels.JobNotificationEmail, r.runNotificationEmailDispatchJob)
+	r.Scheduler.RegisterSystem(schedulerModels.JobNotificationEscalate, r.runNotificationEscalationJob)
+	r.Scheduler.Register(schedulerModels.JobGateOverstayDetect, r.runGateOverstayDetectionJob)
+	r.Scheduler.Register(schedulerModels.JobHarvestApprovalEscalation, r.runHarvestApprovalEscalationJob)
+	r.Scheduler.RegisterSystem(schedulerModels.JobSessionCleanup, r.runSessionCleanupJob)
+	r.Scheduler.RegisterSystem(schedulerModels.JobSatpamNotificationOutbox, r.runSatpamNotificationOutboxJob)
+	r.Scheduler.RegisterSystem(schedulerModels.JobPhotoUploadCleanup, r.runPhotoUploadCleanupJob)
+}
+
+// StartScheduler starts polling for due jobs. Call it once all optional
+// services have been injected.
+func (r *Resolver) StartScheduler(ctx context.Context) {
+	if r == nil || r.Scheduler == nil {
+		return
+	}
+	r.Scheduler.Start(ctx)
+}
+
+// runManagerDailySummaryJob pushes the daily summary to managers of companies
+// that keep daily reports enabled.
+func (r *Resolver) runManagerDailySummaryJob(ctx context.Context, run schedulerServices.JobContext) (string, error) {
+	if r.ManagerNotificationService == nil {
+		return "skipped: push notifications are not configured", nil
+	}
+
+	sent, disabled := 0, 0
+	var failures []string
+	for _, companyID := range run.CompanyIDs {
+		if r.CompanySettingsService != nil {
+			settings, err := r.CompanySettingsService.GetSettings(ctx, companyID)
+			if err == nil && !settings.DailyReportEnabled {
+				disabled++
+				continue
+			}
+		}
+
+		count, err := r.ManagerNotificationService.SendDailySummaryToCompanyManagers(ctx, companyID)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", companyID, err))
+			continue
+		}
+		sent += count
+	}
+
+	message := fmt.Sprintf("%d summary notification(s) sent for %d company(ies), %d with daily reports disabled", sent, len(run.CompanyIDs)-disabled, disabled)
+	if len(failures) > 0 {
+		return message, fmt.Errorf("%d company(ies) failed: %s", len(failures), strings.Join(failures, "; "))
+	}
+	return message, nil
+}
+
+// runWeeklyHarvestSummaryJob notifies managers, area managers and company
+// admins about the harvest of the seven days before the scheduled run.
+func (r *Resolver) runWeeklyHarvestSummaryJob(ctx context.Context, run schedulerServices.JobContext) (string, error) {
+	if r.NotificationService == nil {
+		return "skipped: notification service is not configured", nil
+	}
+
+	periodEnd := startOfDay(run.ScheduledFor, run.Location)
+	periodStart := periodEnd.AddDate(0, 0, -7)
+	periodLabel := fmt.Sprintf("%s - %s", periodStart.Format("02/01/2006"), periodEnd.AddDate(0, 0, -1).Format("02/01/2006"))
+
+	notified := 0
+	var failures []string
+	for _, companyID := range run.CompanyIDs {
+		var stats weeklyHarvestSummaryStats
+		if err := r.db.WithContext(ctx).
+			Table("harvest_records").
+			Select(`
+				COUNT(*) AS total_records,
+				COUNT(*) FILTER (WHERE status = 'APPROVED') AS approved_records,
+				COUNT(*) FILTER (WHERE status = 'PENDING') AS pending_records,
+				COALESCE(SUM(berat_tbs), 0) AS total_weight,
+				COALESCE(SUM(jumlah_janjang), 0) AS total_bunches
+			`).
+			Where("company_id = ?", companyID).
+			Where("tanggal >= ? AND tanggal < ?", periodStart, periodEnd).
+			Scan(&stats).Error; err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", companyID, err))
+			continue
+		}
+
+		recipients, err := r.scheduledJobRecipients(ctx, companyID, []string{"MANAGER", "AREA_MANAGER", "COMPANY_ADMIN"})
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", companyID, err))
+			continue
+		}
+
+		message := fmt.Sprintf(
+			"Periode %s: %d record panen, %.0f kg TBS, %d janjang. %d disetujui, %d menunggu persetujuan.",
+			periodLabel,
+			stats.TotalRecords,
+			stats.TotalWeight,
+			stats.TotalBunches,
+			stats.ApprovedRecords,
+			stats.PendingRecords,
+		)
+		idempotencyKey := fmt.Sprintf("harvest:weekly-summary:%s:%s", companyID, periodStart.Format("2006-01-02"))
+
+		for _, recipient := range recipients {
+			input := &notificationServices.CreateNotificationInput{
+				Type:               notificationModels.NotificationTypeHarvestSummaryWeekly,
+				Priority:           notificationModels.NotificationPriorityMedium,
+				Title:              "Ringkasan Panen Mingguan",
+				Message:            message,
+				IdempotencyKey:     idempotencyKey,
+				RecipientID:        recipient.ID,
+				RecipientRole:      recipient.Role,
+				RecipientCompanyID: companyID,
+				ActionURL:          "/dashboard/harvest",
+				ActionLabel:        "Lihat Panen",
+				Metadata: map[string]interface{}{
+					"periodStart":     periodStart.Format("2006-01-02"),
+					"periodEnd":       periodEnd.Format("2006-01-02"),
+					"totalRecords":    stats.TotalRecords,
+					"approvedRecords": stats.ApprovedRecords,
+					"pendingRecords":  stats.PendingRecords,
+					"totalWeightKg":   stats.TotalWeight,
+					"totalBunches":    stats.TotalBunches,
+				},
+			}
+			if _, err := r.NotificationService.CreateNotification(ctx, input); err != nil {
+				failures = append(failures, fmt.Sprintf("%s/%s: %v", companyID, recipient.ID, err))
+				continue
+			}
+			notified++
+		}
+	}
+
+	summary := fmt.Sprintf("weekly harvest summary (%s) sent to %d recipient(s) in %d company(ies)", periodLabel, notified, len(run.CompanyIDs))
+	if len(failures) > 0 {
+		return summary, fmt.Errorf("%d delivery(ies) failed: %s", len(failures), strings.Join(failures, "; "))
+	}
+	return summary, nil
+}
+
+// runVehicleTaxReminderJob runs the H-30/H-7/H-1/overdue vehicle tax
+// reminders for each company on the company's calendar day.
+func (r *Resolver) runVehicleTaxReminderJob(ctx context.Context, run schedulerServices.JobContext) (string, error) {
+	if r.VehicleTaxReminderService == nil {
+		return "skipped: vehicle tax reminders are not configured", nil
+	}
+
+	asOf := time.Now().In(run.Location)
+	total := notificationServices.VehicleTaxReminderResult{}
+	var failures []string
+	for _, companyID := range run.CompanyIDs {
+		result, err := r.VehicleTaxReminderService.RunForCompany(ctx, companyID, asOf)
+		if result != nil {
+			total.Reminded += result.Reminded
+			total.PushSent += result.PushSent
+			total.PenaltiesUpdated += result.PenaltiesUpdated
+			total.BlockedVehicles += result.BlockedVehicles
+		}
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", companyID, err))
+		}
+	}
+
+	summary := fmt.Sprintf(
+		"%d vehicle tax reminder(s) sent (%d by push), %d penalty estimate(s) updated, %d vehicle(s) blocked across %d company(ies)",
+		total.Reminded,
+		total.PushSent,
+		total.PenaltiesUpdated,
+		total.BlockedVehicles,
+		len(run.CompanyIDs),
+	)
+	if len(failures) > 0 {
+		return summary, fmt.Errorf("%d company(ies) failed: %s", len(failures), strings.Join(failures, "; "))
+	}
+	return summary, nil
+}
+
+// runNotificationEmailDispatchJob sends pending notification emails and any
+// digests that are due.
+func (r *Resolver) runNotificationEmailDispatchJob(ctx context.Context, run schedulerServices.JobContext) (string, error) {
+	if r.EmailNotificationService == nil {
+		return "skipped: email provider is not configured", nil
+	}
+
+	result, err := r.EmailNotificationService.Dispatch(ctx, time.Now())
+	if result == nil {
+		return "", err
+	}
+
+	summary := fmt.Sprintf(
+		"%d email notification(s) sent (%d digest email(s)), %d deferred, %d skipped, %d failed",
+		result.Sent,
+		result.Digests,
+		result.Deferred,
+		result.Skipped,
+		result.Failed,
+	)
+	return summary, err
+}
+
+// runNotificationEscalationJob escalates routed notifications that stayed unread.
+func (r *Resolver) runNotificationEscalationJob(ctx context.Context, run schedulerServices.JobContext) (string, error) {
+	if r.NotificationRoutingService == nil {
+		return "skipped: notification routing is not configured", nil
+	}
+
+	result, err := r.NotificationRoutingService.ProcessEscalations(ctx, time.Now())
+	if result == nil {
+		return "", err
+	}
+
+	summary := fmt.Sprintf(
+		"%d event(s) escalated with %d notification(s), %d dispatch group(s) closed",
+		result.Escalated,
+		result.Notifications,
+		result.Closed,
+	)
+	return summary, err
+}
+
+// runSessionCleanupJob revokes expired web sessions and deletes inactive ones
+// older than the retention window.
+func (r *Resolver) runSessionCleanupJob(ctx context.Context, _ schedulerServices.JobContext) (string, error) {
+	sessions := sharedAuthInfra.NewSessionRepository(r.db)
+	if err := sessions.RevokeExpiredSessions(ctx); err != nil {
+		return "", fmt.Errorf("failed to revoke expired sessions: %w", err)
+	}
+	if err := sessions.CleanupOldSessions(ctx, inactiveSessionRetention); err != nil {
+		return "expired sessions revoked", fmt.Errorf("failed to delete old inactive sessions: %w", err)
+	}
+	return fmt.Sprintf("expired sessions revoked, inactive sessions older than %s deleted", inactiveSessionRetention), nil
+}
+
+// runPhotoUploadCleanupJob removes resumable photo uploads that expired before
+// being finalized, together with their stored chunks.
+func (r *Resolver) runPhotoUploadCleanupJob(ctx context.Context, _ schedulerServices.JobContext) (string, error) {
+	if r.PhotoUploadService == nil {
+		return "skipped: photo uploads are not configured", nil
+	}
+
+	purged, err := r.PhotoUploadService.PurgeExpired(ctx)
+	return fmt.Sprintf("%d expired photo upload(s) removed", purged), err
+}
+
+// scheduledJobRecipients returns active users with one of roles assigned to a company.
+func (r *Resolver) scheduledJobRecipients(ctx context.Context, companyID string, roles []string) ([]scheduledJobRecipient, error) {
+	var recipients []scheduledJobRecipient
+	if err := r.db.WithContext(ctx).
+		Table("users AS u").
+		Select("DISTINCT u.id AS id, u.role AS role").
+		Joins("JOIN user_company_assignments AS uca ON uca.user_id = u.id AND uca.is_active = ?", true).
+		Where("uca.company_id = ?", companyID).
+		Where("u.role IN ?", roles).
+		Where("u.is_active = ?", true).
+		Where("u.deleted_at IS NULL").
+		Scan(&recipients).Error; err != nil {
+		return nil, fmt.Errorf("failed to resolve recipients: %w", err)
+	}
+	return recipients, nil
+}
+
+func startOfDay(t time.Time, location *time.Location) time.Time {
+	if location == nil {
+		location = time.Local
+	}
+	local := t.In(location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+}
+
+func (r *Resolver) toGraphQLScheduledJob(ctx context.Context, job *schedulerModels.ScheduledJob) *generated.ScheduledJob {
+	if job == nil {
+		return nil
+	}
+
+	result := &generated.ScheduledJob{
+		ID:                job.ID,
+		Name:              job.Name,
+		Description:       job.Description,
+		CronExpression:    job.CronExpression,
+		Timezone:          job.Timezone,
+		EffectiveTimezone: r.Scheduler.EffectiveTimezone(ctx, job),
+		CompanyID:         job.CompanyID,
+		IsPaused:          job.IsPaused,
+		NextRunAt:         job.NextRunAt,
+		LastRunAt:         job.LastRunAt,
+		RunRequestedAt:    job.RunRequestedAt,
+		CreatedAt:         job.CreatedAt,
+		UpdatedAt:         job.UpdatedAt,
+	}
+	if job.LastStatus != nil {
+		status := generated.ScheduledJobRunStatus(*job.LastStatus)
+		result.LastStatus = &status
+	}
+	return result
+}
+
+func toGraphQLScheduledJobRun(run *schedulerModels.ScheduledJobRun) *generated.ScheduledJobRun {
+	if run == nil {
+		return nil
+	}
+
+	result := &generated.ScheduledJobRun{
+		ID:           run.ID,
+		JobID:        run.JobID,
+		JobName:      run.JobName,
+		CompanyID:    run.CompanyID,
+		Trigger:      generated.ScheduledJobTrigger(run.TriggerType),
+		TriggeredBy:  run.TriggeredBy,
+		Status:       generated.ScheduledJobRunStatus(run.Status),
+		InstanceID:   run.InstanceID,
+		ScheduledFor: run.ScheduledFor,
+		StartedAt:    run.StartedAt,
+		FinishedAt:   run.FinishedAt,
+		Message:      run.Message,
+		Error:        run.Error,
+	}
+	if run.DurationMs != nil {
+		durationMs := int32(*run.DurationMs)
+		result.DurationMs = &durationMs
+	}
+	return result
+}
@@ -170,12 +170,21 @@ func (r *queryResolver) NotificationSummary(ctx context.Context) (*models.Notifi
 
 // Field resolvers (return nil for now - gqlgen will auto-generate needed resolvers)
 
+// ApprovalItem returns generated.ApprovalItemResolver implementation.
+func (r *Resolver) ApprovalItem() generated.ApprovalItemResolver { return &approvalItemResolver{r} }
+
 // Block returns generated.BlockResolver implementation.
 func (r *Resolver) Block() generated.BlockResolver { return &blockResolver{r} }
 
 // Company returns generated.CompanyResolver implementation.
 func (r *Resolver) Company() generated.CompanyResolver { return nil }
 
+// Division returns generated.DivisionResolver implementation.
+func (r *Resolver) Division() generated.DivisionResolver { return &divisionResolver{r} }
+
+// Estate returns generated.EstateResolver implementation.
+func (r *Resolver) Estate() generated.EstateResolver { return &estateResolver{r} }
+
 // GradingRecord returns generated.GradingRecordResolver implementation.
 func (r *Resolver) GradingRecord() generated.GradingRecordResolver { return nil }
 
@@ -185,6 +194,14 @@ func (r *Resolver) MandorPendingSyncItem() generated.MandorPendingSyncItemResolv
 // MandorPhotoSyncResult returns generated.MandorPhotoSyncResultResolver implementation.
 func (r *Resolver) MandorPhotoSyncResult() generated.MandorPhotoSyncResultResolver { return nil }
 
+// PerawatanRecord returns generated.PerawatanRecordResolver implementation.
+func (r *Resolver) PerawatanRecord() generated.PerawatanRecordResolver {
+	return &perawatanRecordResolver{r}
+}
+
+// PKSRecord returns generated.PKSRecordResolver implementation.
+func (r *Resolver) PKSRecord() generated.PKSRecordResolver { return &pKSRecordResolver{r} }
+
 // SatpamGuestLog returns generated.SatpamGuestLogResolver implementation.
 func (r *Resolver) SatpamGuestLog() generated.SatpamGuestLogResolver {
 	return &satpamGuestLogResolver{r}
@@ -215,7 +232,9 @@ func (r *Resolver) TrendDataPoint() generated.TrendDataPointResolver {
 }
 
 // UserAssignmentSummary returns generated.UserAssignmentSummaryResolver implementation.
-func (r *Resolver) UserAssignmentSummary() generated.UserAssignmentSummaryResolver { return nil }
+func (r *Resolver) UserAssignmentSummary() generated.UserAssignmentSummaryResolver {
+	return &userAssignmentSummaryResolver{r}
+}
 
 // UserFeature returns generated.UserFeatureResolver implementation.
 func (r *Resolver) UserFeature() generated.UserFeatureResolver { return nil }
@@ -244,6 +263,9 @@ func (r *Resolver) WeighingQueueItem() generated.WeighingQueueItemResolver { ret
 // WeighingResult returns generated.WeighingResultResolver implementation.
 func (r *Resolver) WeighingResult() generated.WeighingResultResolver { return nil }
 
+// HarvestRecord returns generated.HarvestRecordResolver implementation.
+func (r *Resolver) HarvestRecord() generated.HarvestRecordResolver { return &harvestRecordResolver{r} }
+
 // HarvestRecordSyncInput returns generated.HarvestRecordSyncInputResolver implementation.
 func (r *Resolver) HarvestRecordSyncInput() generated.HarvestRecordSyncInputResolver {
 	return &harvestRecordSyncInputResolver{r}
@@ -0,0 +1,112 @@
+package resolvers
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"log"
+	"sync"
+
+	"agrinovagraphql/server/internal/pubsub"
+
+	"github.com/google/uuid"
+)
+
+// Topics carrying subscription events between instances.
+const (
+	topicHarvestCreated       = "harvest.created"
+	topicHarvestApproved      = "harvest.approved"
+	topicHarvestRejected      = "harvest.rejected"
+	topicSatpamVehicleEntry   = "satpam.vehicle_entry"
+	topicSatpamVehicleExit    = "satpam.vehicle_exit"
+	topicSatpamOverstay       = "satpam.overstay"
+	topicSatpamWatchlist      = "satpam.watchlist"
+	topicSatpamSyncUpdate     = "satpam.sync_update"
+	topicCompanyUserStatus    = "company_admin.user_status"
+	topicCompanyAdminActivity = "company_admin.activity"
+	topicThemeRuntimeChanged  = "theme.runtime_changed"
+)
+
+// UseSubscriptionPubSub routes every subscription hub through bus, so
+// events published on one instance reach subscribers on all of them. Hubs
+// start on a private in-memory bus; call this once during startup.
+func UseSubscriptionPubSub(bus pubsub.PubSub) {
+	globalHarvestSubscriptionHub.attach(bus)
+	globalSatpamSubscriptionHub.attach(bus)
+	globalCompanyAdminSubscriptionHub.attach(bus)
+	globalThemeSubscriptionHub.attach(bus)
+}
+
+// hubBus is the PubSub a hub shares with the same hub on other instances.
+// Local subscribers are served directly with the published value; the bus
+// only carries the event to other instances, tagged with this hub's origin.
+type hubBus struct {
+	mu            sync.RWMutex
+	bus           pubsub.PubSub
+	origin        string
+	unsubscribers []func()
+}
+
+// hubEvent is the wire form of a hub event. Events are gob-encoded: unlike
+// JSON, gob does not run the enums' strict UnmarshalJSON, so records with
+// unset optional enums still reach remote subscribers.
+type hubEvent[T any] struct {
+	Origin string
+	Event  T
+}
+
+// attach replaces the bus; subscribe registers the hub's topics on it.
+func (b *hubBus) attach(bus pubsub.PubSub, subscribe func(pubsub.PubSub) []func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, unsubscribe := range b.unsubscribers {
+		unsubscribe()
+	}
+	if b.origin == "" {
+		b.origin = uuid.NewString()
+	}
+	b.bus = bus
+	b.unsubscribers = subscribe(bus)
+}
+
+func (b *hubBus) current() (pubsub.PubSub, string) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.bus, b.origin
+}
+
+// publishHubEvent delivers event to local subscribers and forwards it to
+// other instances. Bus errors are logged so the mutation path never fails
+// on fan-out.
+func publishHubEvent[T any](b *hubBus, topic string, event T, deliver func(T)) {
+	deliver(event)
+
+	bus, origin := b.current()
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(hubEvent[T]{Origin: origin, Event: event}); err != nil {
+		log.Printf("subscription: failed to encode %s event: %v", topic, err)
+		return
+	}
+	if err := bus.Publish(context.Background(), topic, payload.Bytes()); err != nil {
+		log.Printf("subscription: failed to publish %s event: %v", topic, err)
+	}
+}
+
+// subscribeHubEvent hands events published by other instances to deliver.
+// It must be called from the attach callback.
+func subscribeHubEvent[T any](b *hubBus, bus pubsub.PubSub, topic string, deliver func(T)) func() {
+	origin := b.origin
+	return bus.Subscribe(topic, func(payload []byte) {
+		var event hubEvent[T]
+		if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(&event); err != nil {
+			log.Printf("subscription: dropping malformed %s event: %v", topic, err)
+			return
+		}
+		if event.Origin == origin {
+			// Already delivered locally by publishHubEvent.
+			return
+		}
+		deliver(event.Event)
+	})
+}
@@ -0,0 +1,92 @@
+package resolvers
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"testing"
+	"time"
+
+	"agrinovagraphql/server/internal/graphql/domain/mandor"
+	"agrinovagraphql/server/internal/graphql/domain/satpam"
+	"agrinovagraphql/server/internal/graphql/generated"
+	"agrinovagraphql/server/internal/pubsub"
+	"agrinovagraphql/server/internal/theme"
+)
+
+func TestSubscriptionHubs_DeliverAcrossInstancesSharingABus(t *testing.T) {
+	t.Parallel()
+
+	// Two hubs on one bus stand in for two server instances.
+	bus := pubsub.NewMemory()
+	instanceA, instanceB := newHarvestSubscriptionHub(), newHarvestSubscriptionHub()
+	instanceA.attach(bus)
+	instanceB.attach(bus)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := instanceB.subscribeApproved(ctx)
+
+	// Status is left unset: remote delivery must not depend on valid enums.
+	instanceA.publishApproved(&mandor.HarvestRecord{ID: "harvest-1", JumlahJanjang: 42})
+
+	select {
+	case got := <-ch:
+		if got == nil || got.ID != "harvest-1" || got.JumlahJanjang != 42 {
+			t.Fatalf("unexpected payload: %#v", got)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("timed out waiting for approval published on the other instance")
+	}
+}
+
+func TestSatpamSubscriptionHub_SyncUpdateCarriesDeviceAcrossBus(t *testing.T) {
+	t.Parallel()
+
+	bus := pubsub.NewMemory()
+	publisher, subscriber := newSatpamSubscriptionHub(), newSatpamSubscriptionHub()
+	publisher.attach(bus)
+	subscriber.attach(bus)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	chA := subscriber.subscribeSyncUpdate(ctx, "device-a")
+	chB := subscriber.subscribeSyncUpdate(ctx, "device-b")
+
+	publisher.publishSyncUpdate(" device-a ", &satpam.SatpamSyncStatus{PendingSyncCount: 7})
+
+	select {
+	case got := <-chA:
+		if got == nil || got.PendingSyncCount != 7 {
+			t.Fatalf("unexpected sync payload: %#v", got)
+		}
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("timed out waiting for device-a sync update")
+	}
+
+	select {
+	case <-chB:
+		t.Fatal("device-b subscriber should not receive device-a update")
+	default:
+	}
+}
+
+func TestSubscriptionHubs_EventTypesAreGobEncodable(t *testing.T) {
+	t.Parallel()
+
+	events := map[string]any{
+		"harvest":        hubEvent[*mandor.HarvestRecord]{Event: &mandor.HarvestRecord{}},
+		"guest log":      hubEvent[*satpam.SatpamGuestLog]{Event: &satpam.SatpamGuestLog{}},
+		"overstay":       hubEvent[*satpam.VehicleInsideInfo]{Event: &satpam.VehicleInsideInfo{}},
+		"watchlist":      hubEvent[*satpam.GateWatchlistAlert]{Event: &satpam.GateWatchlistAlert{}},
+		"sync":           hubEvent[satpamSyncEvent]{Event: satpamSyncEvent{Status: &satpam.SatpamSyncStatus{}}},
+		"user status":    hubEvent[companyUserStatusEvent]{Event: companyUserStatusEvent{User: &generated.CompanyUser{}}},
+		"admin activity": hubEvent[adminActivityEvent]{Event: adminActivityEvent{Entry: &generated.AdminActivityLog{}}},
+		"theme":          hubEvent[theme.RuntimeChange]{},
+	}
+	for name, event := range events {
+		if err := gob.NewEncoder(&bytes.Buffer{}).Encode(event); err != nil {
+			t.Errorf("%s event is not gob-encodable: %v", name, err)
+		}
+	}
+}
@@ -2,15 +2,18 @@ package resolvers
 
 import (
 	"encoding/json"
+	"errors"
 	"sort"
 
 	authModels "agrinovagraphql/server/internal/auth/models"
+	companyServices "agrinovagraphql/server/internal/company/services"
 	"agrinovagraphql/server/internal/graphql/domain/auth"
 	"agrinovagraphql/server/internal/graphql/domain/mandor"
 	"agrinovagraphql/server/internal/graphql/domain/master"
 	"agrinovagraphql/server/internal/graphql/generated"
 	"agrinovagraphql/server/internal/master/models"
 	"agrinovagraphql/server/internal/middleware"
+	schedulerServices "agrinovagraphql/server/internal/scheduler/services"
 	"context"
 	"fmt"
 	"time"
@@ -30,12 +33,40 @@ func (r *mutationResolver) UpdateCompanyAdmin(ctx context.Context, input generat
 
 // SuspendCompany is the resolver for the suspendCompany field.
 func (r *mutationResolver) SuspendCompany(ctx context.Context, companyID string, reason string) (*generated.CompanyManagementResult, error) {
-	panic(fmt.Errorf("not implemented: SuspendCompany - suspendCompany"))
+	actorID := middleware.GetCurrentUserID(ctx)
+	if _, err := r.TenantPlanService.SuspendCompany(ctx, companyID, actorID, reason); err != nil {
+		return companyManagementError(err)
+	}
+
+	company, err := r.loadCompanyDetailAdmin(ctx, companyID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.CompanyManagementResult{
+		Success: true,
+		Message: "Company suspended successfully",
+		Company: company,
+	}, nil
 }
 
 // ActivateCompany is the resolver for the activateCompany field.
 func (r *mutationResolver) ActivateCompany(ctx context.Context, companyID string) (*generated.CompanyManagementResult, error) {
-	panic(fmt.Errorf("not implemented: ActivateCompany - activateCompany"))
+	actorID := middleware.GetCurrentUserID(ctx)
+	if _, err := r.TenantPlanService.ActivateCompany(ctx, companyID, actorID); err != nil {
+		return companyManagementError(err)
+	}
+
+	company, err := r.loadCompanyDetailAdmin(ctx, companyID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.CompanyManagementResult{
+		Success: true,
+		Message: "Company activated successfully",
+		Company: company,
+	}, nil
 }
 
 // DeleteCompanyAdmin is the resolver for the deleteCompanyAdmin field.
@@ -45,12 +76,47 @@ func (r *mutationResolver) DeleteCompanyAdmin(ctx context.Context, companyID str
 
 // ExtendCompanyTrial is the resolver for the extendCompanyTrial field.
 func (r *mutationResolver) ExtendCompanyTrial(ctx context.Context, companyID string, days int32) (*generated.CompanyManagementResult, error) {
-	panic(fmt.Errorf("not implemented: ExtendCompanyTrial - extendCompanyTrial"))
+	actorID := middleware.GetCurrentUserID(ctx)
+	plan, err := r.TenantPlanService.ExtendTrial(ctx, companyID, actorID, int(days))
+	if err != nil {
+		return companyManagementError(err)
+	}
+
+	company, err := r.loadCompanyDetailAdmin(ctx, companyID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.CompanyManagementResult{
+		Success: true,
+		Message: fmt.Sprintf("Trial extended until %s", plan.TrialEndsAt.Format("2006-01-02")),
+		Company: company,
+	}, nil
 }
 
 // UpdateCompanyPlan is the resolver for the updateCompanyPlan field.
 func (r *mutationResolver) UpdateCompanyPlan(ctx context.Context, companyID string, planType generated.PlanType, maxUsers *int32, maxEstates *int32, maxStorageGb *float64) (*generated.CompanyManagementResult, error) {
-	panic(fmt.Errorf("not implemented: UpdateCompanyPlan - updateCompanyPlan"))
+	actorID := middleware.GetCurrentUserID(ctx)
+	update := &companyServices.PlanUpdate{
+		PlanType:     string(planType),
+		MaxUsers:     int32PtrToIntPtr(maxUsers),
+		MaxEstates:   int32PtrToIntPtr(maxEstates),
+		MaxStorageGB: maxStorageGb,
+	}
+	if _, err := r.TenantPlanService.UpdatePlan(ctx, companyID, actorID, update); err != nil {
+		return companyManagementError(err)
+	}
+
+	company, err := r.loadCompanyDetailAdmin(ctx, companyID, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generated.CompanyManagementResult{
+		Success: true,
+		Message: "Company plan updated successfully",
+		Company: company,
+	}, nil
 }
 
 // UpdateSystemSettings is the resolver for the updateSystemSettings field.
@@ -88,6 +154,66 @@ func (r *mutationResolver) SetMaintenanceMode(ctx context.Context, enabled bool,
 	panic(fmt.Errorf("not implemented: SetMaintenanceMode - setMaintenanceMode"))
 }
 
+// CreateScheduledJob is the resolver for the createScheduledJob field.
+func (r *mutationResolver) CreateScheduledJob(ctx context.Context, input generated.CreateScheduledJobInput) (*generated.ScheduledJob, error) {
+	job, err := r.Scheduler.CreateJob(ctx, schedulerServices.CreateScheduledJobInput{
+		Name:           input.Name,
+		CompanyID:      input.CompanyID,
+		CronExpression: input.CronExpression,
+		Timezone:       input.Timezone,
+		Description:    input.Description,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.toGraphQLScheduledJob(ctx, job), nil
+}
+
+// UpdateScheduledJob is the resolver for the updateScheduledJob field.
+func (r *mutationResolver) UpdateScheduledJob(ctx context.Context, input generated.UpdateScheduledJobInput) (*generated.ScheduledJob, error) {
+	job, err := r.Scheduler.UpdateJob(ctx, input.ID, schedulerServices.UpdateScheduledJobInput{
+		CronExpression: input.CronExpression,
+		Timezone:       input.Timezone,
+		Description:    input.Description,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.toGraphQLScheduledJob(ctx, job), nil
+}
+
+// PauseScheduledJob is the resolver for the pauseScheduledJob field.
+func (r *mutationResolver) PauseScheduledJob(ctx context.Context, id string) (*generated.ScheduledJob, error) {
+	job, err := r.Scheduler.SetPaused(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	return r.toGraphQLScheduledJob(ctx, job), nil
+}
+
+// ResumeScheduledJob is the resolver for the resumeScheduledJob field.
+func (r *mutationResolver) ResumeScheduledJob(ctx context.Context, id string) (*generated.ScheduledJob, error) {
+	job, err := r.Scheduler.SetPaused(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+	return r.toGraphQLScheduledJob(ctx, job), nil
+}
+
+// TriggerScheduledJob is the resolver for the triggerScheduledJob field.
+func (r *mutationResolver) TriggerScheduledJob(ctx context.Context, id string) (*generated.ScheduledJob, error) {
+	userID := middleware.GetCurrentUserID(ctx)
+	if userID == "" {
+		return nil, fmt.Errorf("authentication required")
+	}
+
+	job, err := r.Scheduler.Trigger(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return r.toGraphQLScheduledJob(ctx, job), nil
+}
+
 // Query resolvers for Super Admin
 
 // SuperAdminDashboard is the resolver for the superAdminDashboard field.
@@ -137,6 +263,9 @@ func (r *queryResolver) AllCompanies(ctx context.Context, status *master.Company
 	resultCompanies := make([]*generated.CompanyDetailAdmin, len(companies))
 	for i, c := range companies {
 		resultCompanies[i] = r.convertCompanyToDetailAdmin(c)
+		if err := r.applyCompanyPlan(ctx, resultCompanies[i], false); err != nil {
+			return nil, fmt.Errorf("failed to load company plan: %w", err)
+		}
 	}
 
 	return &generated.CompanyListResponse{
@@ -153,12 +282,7 @@ func (r *queryResolver) CompanyDetail(ctx context.Context, companyID string) (*g
 		return nil, fmt.Errorf("authentication required")
 	}
 
-	company, err := r.MasterResolver.GetMasterService().GetCompanyByID(ctx, companyID, userID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch company detail: %w", err)
-	}
-
-	return r.convertCompanyToDetailAdmin(company), nil
+	return r.loadCompanyDetailAdmin(ctx, companyID, userID)
 }
 
 // Helpers for Super Admin
@@ -516,6 +640,68 @@ func (r *queryResolver) AdminDeviceStats(ctx context.Context) ([]*generated.Comp
 	return stats, nil
 }
 
+// ScheduledJobs is the resolver for the scheduledJobs field.
+func (r *queryResolver) ScheduledJobs(ctx context.Context) ([]*generated.ScheduledJob, error) {
+	jobs, err := r.Scheduler.ListJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*generated.ScheduledJob, 0, len(jobs))
+	for _, job := range jobs {
+		result = append(result, r.toGraphQLScheduledJob(ctx, job))
+	}
+	return result, nil
+}
+
+// ScheduledJob is the resolver for the scheduledJob field.
+func (r *queryResolver) ScheduledJob(ctx context.Context, id string) (*generated.ScheduledJob, error) {
+	job, err := r.Scheduler.GetJob(ctx, id)
+	if err != nil {
+		if errors.Is(err, schedulerServices.ErrScheduledJobNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.toGraphQLScheduledJob(ctx, job), nil
+}
+
+// ScheduledJobRuns is the resolver for the scheduledJobRuns field.
+func (r *queryResolver) ScheduledJobRuns(ctx context.Context, jobID *string, status *generated.ScheduledJobRunStatus, limit *int32) ([]*generated.ScheduledJobRun, error) {
+	filterJobID := ""
+	if jobID != nil {
+		filterJobID = *jobID
+	}
+	filterStatus := ""
+	if status != nil {
+		filterStatus = string(*status)
+	}
+	runLimit := 0
+	if limit != nil {
+		runLimit = int(*limit)
+	}
+
+	runs, err := r.Scheduler.ListRuns(ctx, filterJobID, filterStatus, runLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*generated.ScheduledJobRun, 0, len(runs))
+	for _, run := range runs {
+		result = append(result, toGraphQLScheduledJobRun(run))
+	}
+	return result, nil
+}
+
+// SchedulerStatus is the resolver for the schedulerStatus field.
+func (r *queryResolver) SchedulerStatus(ctx context.Context) (*generated.SchedulerStatus, error) {
+	return &generated.SchedulerStatus{
+		InstanceID:     r.Scheduler.InstanceID(),
+		IsLeader:       r.Scheduler.IsLeader(),
+		RegisteredJobs: r.Scheduler.RegisteredJobs(),
+	}, nil
+}
+
 // NewCompanyRegistration is the resolver for the newCompanyRegistration subscription field.
 func (r *subscriptionResolver) NewCompanyRegistration(ctx context.Context) (<-chan *generated.CompanyDetailAdmin, error) {
 	return nil, fmt.Errorf("not implemented")
@@ -0,0 +1,172 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	companyModels "agrinovagraphql/server/internal/company/models"
+	companyServices "agrinovagraphql/server/internal/company/services"
+	"agrinovagraphql/server/internal/graphql/generated"
+	"agrinovagraphql/server/pkg/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// reserveUploadQuota reserves storage for an upload. It writes an error
+// response and returns false when the upload would exceed the company's
+// storage quota or the company is read-only. Release the reservation with
+// releaseUploadQuota when the upload is not kept.
+func (r *Resolver) reserveUploadQuota(c *gin.Context, companyID string, sizeBytes int64) bool {
+	err := r.reserveStorage(c.Request.Context(), companyID, sizeBytes)
+	if err == nil {
+		return true
+	}
+
+	status := http.StatusInternalServerError
+	var quotaErr *companyServices.QuotaExceededError
+	switch {
+	case errors.As(err, &quotaErr):
+		status = http.StatusRequestEntityTooLarge
+	case errors.Is(err, companyServices.ErrCompanySuspended), errors.Is(err, companyServices.ErrTrialExpired):
+		status = http.StatusForbidden
+	}
+
+	c.JSON(status, gin.H{
+		"success": false,
+		"message": err.Error(),
+	})
+	return false
+}
+
+// reserveStorage adds sizeBytes to the company's tracked storage, failing
+// when it would exceed the plan.
+func (r *Resolver) reserveStorage(ctx context.Context, companyID string, sizeBytes int64) error {
+	if r.TenantPlanService == nil || strings.TrimSpace(companyID) == "" {
+		return nil
+	}
+	return r.TenantPlanService.ReserveStorage(ctx, companyID, sizeBytes)
+}
+
+// releaseUploadQuota gives back storage reserved for an upload that was not
+// kept or a stored file that was deleted.
+func (r *Resolver) releaseUploadQuota(ctx context.Context, companyID string, sizeBytes int64) {
+	if r.TenantPlanService == nil || strings.TrimSpace(companyID) == "" {
+		return
+	}
+	if err := r.TenantPlanService.RecordStorageUsage(ctx, companyID, -sizeBytes); err != nil {
+		fmt.Printf("failed releasing storage usage for company %s: %v\n", companyID, err)
+	}
+}
+
+// deleteUpload removes a stored file and gives its size back to the company's
+// storage quota.
+func (r *Resolver) deleteUpload(ctx context.Context, companyID string, urlPath string) {
+	key, ok := storage.KeyFromURLPath(urlPath)
+	if !ok || r.uploads == nil {
+		return
+	}
+	info, err := r.uploads.Stat(ctx, key)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			fmt.Printf("failed reading upload %s: %v\n", key, err)
+		}
+		return
+	}
+	if err := r.uploads.Delete(ctx, key); err != nil {
+		fmt.Printf("failed deleting upload %s: %v\n", key, err)
+		return
+	}
+	r.releaseUploadQuota(ctx, companyID, info.Size)
+}
+
+func mapCompanyPlanToSubscription(plan *companyModels.CompanyPlan) *generated.SubscriptionInfo {
+	return &generated.SubscriptionInfo{
+		PlanName:        plan.PlanName,
+		PlanType:        generated.PlanType(plan.PlanType),
+		StartDate:       plan.StartDate,
+		EndDate:         plan.EndDate,
+		IsTrial:         plan.IsTrial(),
+		TrialEndsAt:     plan.TrialEndsAt,
+		MaxUsers:        int32(plan.MaxUsers),
+		MaxEstates:      int32(plan.MaxEstates),
+		MaxStorageGb:    plan.MaxStorageGB,
+		FeaturesEnabled: plan.FeaturesEnabled(),
+	}
+}
+
+func mapCompanyUsageStats(plan *companyModels.CompanyPlan, usage *companyModels.CompanyUsage) *generated.CompanyUsageStats {
+	return &generated.CompanyUsageStats{
+		CurrentUsers:   int32(usage.CurrentUsers),
+		MaxUsers:       int32(plan.MaxUsers),
+		CurrentEstates: int32(usage.CurrentEstates),
+		MaxEstates:     int32(plan.MaxEstates),
+		StorageUsedGb:  usage.StorageUsedGB,
+		MaxStorageGb:   plan.MaxStorageGB,
+	}
+}
+
+// companyManagementError converts tenant plan errors into a failed
+// CompanyManagementResult; unexpected errors are returned as-is.
+func companyManagementError(err error) (*generated.CompanyManagementResult, error) {
+	var quotaErr *companyServices.QuotaExceededError
+	switch {
+	case errors.As(err, &quotaErr),
+		errors.Is(err, companyServices.ErrNotOnTrial),
+		errors.Is(err, companyServices.ErrInvalidPlanType),
+		errors.Is(err, companyServices.ErrSuspendReasonEmpty):
+		return &generated.CompanyManagementResult{
+			Success: false,
+			Message: err.Error(),
+			Errors:  []string{err.Error()},
+		}, nil
+	}
+	return nil, err
+}
+
+// applyCompanyPlan fills subscription info and, when includeUsage is set, the
+// usage-versus-limit report on a company detail.
+func (r *Resolver) applyCompanyPlan(ctx context.Context, detail *generated.CompanyDetailAdmin, includeUsage bool) error {
+	if detail == nil || r.TenantPlanService == nil {
+		return nil
+	}
+
+	plan, err := r.TenantPlanService.GetPlan(ctx, detail.ID)
+	if err != nil {
+		return err
+	}
+	detail.Subscription = mapCompanyPlanToSubscription(plan)
+	detail.Usage.MaxUsers = int32(plan.MaxUsers)
+	detail.Usage.MaxEstates = int32(plan.MaxEstates)
+	detail.Usage.MaxStorageGb = plan.MaxStorageGB
+
+	if !includeUsage {
+		return nil
+	}
+
+	usage, err := r.TenantPlanService.GetUsage(ctx, detail.ID)
+	if err != nil {
+		return err
+	}
+	detail.Usage = mapCompanyUsageStats(plan, usage)
+	if detail.Statistics != nil {
+		detail.Statistics.TotalEstates = int32(usage.CurrentEstates)
+		detail.Statistics.TotalUsers = int32(usage.CurrentUsers)
+	}
+	return nil
+}
+
+func (r *Resolver) loadCompanyDetailAdmin(ctx context.Context, companyID, userID string) (*generated.CompanyDetailAdmin, error) {
+	company, err := r.MasterResolver.GetMasterService().GetCompanyByID(ctx, companyID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch company detail: %w", err)
+	}
+
+	detail := (&queryResolver{r}).convertCompanyToDetailAdmin(company)
+	if err := r.applyCompanyPlan(ctx, detail, true); err != nil {
+		return nil, err
+	}
+	return detail, nil
+}
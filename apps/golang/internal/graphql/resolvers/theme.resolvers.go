@@ -0,0 +1,25 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.83
+
+import (
+	"agrinovagraphql/server/internal/graphql/domain/auth"
+	"agrinovagraphql/server/internal/graphql/generated"
+	"agrinovagraphql/server/internal/middleware"
+	"context"
+	"fmt"
+)
+
+// ThemeRuntimeChanged is the resolver for the themeRuntimeChanged field.
+func (r *subscriptionResolver) ThemeRuntimeChanged(ctx context.Context) (<-chan *generated.ThemeRuntimeChange, error) {
+	if middleware.GetUserRoleFromContext(ctx) == auth.UserRoleSuperAdmin {
+		return globalThemeSubscriptionHub.subscribe(ctx, ""), nil
+	}
+	companyID := middleware.GetCompanyFromContext(ctx)
+	if companyID == "" {
+		return nil, fmt.Errorf("company information missing from context")
+	}
+	return globalThemeSubscriptionHub.subscribe(ctx, companyID), nil
+}
@@ -0,0 +1,120 @@
+package resolvers
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"agrinovagraphql/server/internal/graphql/generated"
+	"agrinovagraphql/server/internal/pubsub"
+	"agrinovagraphql/server/internal/theme"
+)
+
+// themeRuntimeSubscriber receives changes of one company, or of every
+// company when companyID is empty (super admins).
+type themeRuntimeSubscriber struct {
+	companyID string
+}
+
+// themeSubscriptionHub fans out runtime theme changes so connected clients
+// refetch the runtime payload.
+type themeSubscriptionHub struct {
+	mu sync.RWMutex
+
+	subscribers map[chan *generated.ThemeRuntimeChange]themeRuntimeSubscriber
+	// dropCache clears this instance's runtime theme cache, which may be
+	// stale when the change was made on another instance.
+	dropCache func()
+
+	events hubBus
+}
+
+func newThemeSubscriptionHub() *themeSubscriptionHub {
+	h := &themeSubscriptionHub{
+		subscribers: make(map[chan *generated.ThemeRuntimeChange]themeRuntimeSubscriber),
+	}
+	h.attach(pubsub.NewMemory())
+	return h
+}
+
+func (h *themeSubscriptionHub) attach(bus pubsub.PubSub) {
+	h.events.attach(bus, func(bus pubsub.PubSub) []func() {
+		return []func(){subscribeHubEvent(&h.events, bus, topicThemeRuntimeChanged, h.deliver)}
+	})
+}
+
+var globalThemeSubscriptionHub = newThemeSubscriptionHub()
+
+func (h *themeSubscriptionHub) subscribe(ctx context.Context, companyID string) <-chan *generated.ThemeRuntimeChange {
+	ch := make(chan *generated.ThemeRuntimeChange, 8)
+
+	h.mu.Lock()
+	h.subscribers[ch] = themeRuntimeSubscriber{companyID: strings.TrimSpace(companyID)}
+	h.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		h.mu.Lock()
+		delete(h.subscribers, ch)
+		h.mu.Unlock()
+		close(ch)
+	}()
+
+	return ch
+}
+
+func (h *themeSubscriptionHub) publish(change theme.RuntimeChange) {
+	publishHubEvent(&h.events, topicThemeRuntimeChanged, change, h.deliver)
+}
+
+func (h *themeSubscriptionHub) deliver(change theme.RuntimeChange) {
+	h.mu.RLock()
+	dropCache := h.dropCache
+	h.mu.RUnlock()
+	if dropCache != nil {
+		dropCache()
+	}
+
+	payload := &generated.ThemeRuntimeChange{
+		Reason:    change.Reason,
+		CompanyID: change.CompanyID,
+		ChangedAt: change.ChangedAt,
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for ch, subscriber := range h.subscribers {
+		if !themeRuntimeChangeVisible(change.CompanyID, subscriber.companyID) {
+			continue
+		}
+		select {
+		case ch <- payload:
+		default:
+			// Keep mutation path non-blocking for slow subscribers.
+		}
+	}
+}
+
+// themeRuntimeChangeVisible reports whether a subscriber of companyID should
+// refetch after a change scoped to changeCompanyID.
+func themeRuntimeChangeVisible(changeCompanyID *string, companyID string) bool {
+	if changeCompanyID == nil || companyID == "" {
+		return true
+	}
+	return strings.TrimSpace(*changeCompanyID) == companyID
+}
+
+// ShareThemeRuntimeCache registers drop to clear the runtime theme cache
+// whenever a change arrives, including changes made on other instances.
+func ShareThemeRuntimeCache(drop func()) {
+	globalThemeSubscriptionHub.mu.Lock()
+	globalThemeSubscriptionHub.dropCache = drop
+	globalThemeSubscriptionHub.mu.Unlock()
+}
+
+// PublishThemeRuntimeChange forwards a theme service invalidation to
+// themeRuntimeChanged subscribers.
+func (r *Resolver) PublishThemeRuntimeChange(change theme.RuntimeChange) {
+	globalThemeSubscriptionHub.publish(change)
+}
@@ -0,0 +1,244 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"agrinovagraphql/server/internal/graphql/domain/auth"
+	"agrinovagraphql/server/internal/middleware"
+	"agrinovagraphql/server/pkg/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	uploadSignedURLTTL      = 10 * time.Minute
+	uploadRedirectTTL       = 5 * time.Minute
+	uploadSignedURLMaxPaths = 50
+	uploadPublicCache       = "public, max-age=31536000, immutable"
+)
+
+var (
+	errUploadUnauthenticated = errors.New("authentication required")
+	errUploadAccessDenied    = errors.New("access denied")
+)
+
+// uploadOwner is who may read an object: its uploader, or anyone with
+// access to one of its companies.
+type uploadOwner struct {
+	userID     string
+	companyIDs []string
+}
+
+// HandleUploadDownload serves GET /uploads/*key. Theme assets are public;
+// everything else needs a signed link from HandleUploadSignedURL or an
+// authenticated caller with access to the object's company.
+func (r *Resolver) HandleUploadDownload(c *gin.Context) {
+	key, err := storage.CleanKey(c.Param("key"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "file not found"})
+		return
+	}
+
+	if isPublicUploadKey(key) {
+		r.serveUpload(c, key, uploadPublicCache)
+		return
+	}
+
+	if signature := c.Query("signature"); signature != "" {
+		if err := r.uploadURLs.Verify(key, c.Query("expires"), signature); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"success": false, "message": "link is invalid or expired"})
+			return
+		}
+		r.serveUpload(c, key, "private, max-age=300")
+		return
+	}
+
+	if err := r.authorizeUploadKey(c.Request.Context(), key); err != nil {
+		status := http.StatusForbidden
+		if errors.Is(err, errUploadUnauthenticated) {
+			status = http.StatusUnauthorized
+		}
+		c.JSON(status, gin.H{"success": false, "message": err.Error()})
+		return
+	}
+	r.serveUpload(c, key, "private, no-store")
+}
+
+// HandleUploadSignedURL exchanges one or more `path` query values for
+// short-lived links the caller can hand to image widgets or share.
+func (r *Resolver) HandleUploadSignedURL(c *gin.Context) {
+	paths := c.QueryArray("path")
+	if len(paths) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "path is required"})
+		return
+	}
+	if len(paths) > uploadSignedURLMaxPaths {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": fmt.Sprintf("at most %d paths per request", uploadSignedURLMaxPaths),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	urls := make([]gin.H, 0, len(paths))
+	for _, requested := range paths {
+		key, ok := storage.KeyFromURLPath(requested)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": fmt.Sprintf("%q is not an upload path", requested)})
+			return
+		}
+		if err := r.authorizeUploadKey(ctx, key); err != nil {
+			status := http.StatusForbidden
+			if errors.Is(err, errUploadUnauthenticated) {
+				status = http.StatusUnauthorized
+			}
+			c.JSON(status, gin.H{"success": false, "message": err.Error(), "path": requested})
+			return
+		}
+
+		signedURL, expiresAt := r.uploadURLs.SignedURL(key, uploadSignedURLTTL)
+		urls = append(urls, gin.H{
+			"path":      storage.URLPath(key),
+			"url":       signedURL,
+			"expiresAt": expiresAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "urls": urls})
+}
+
+func (r *Resolver) serveUpload(c *gin.Context, key string, cacheControl string) {
+	ctx := c.Request.Context()
+
+	// Buckets hand out their own short-lived link so the body never passes
+	// through this instance.
+	if presigner, ok := r.uploads.(storage.Presigner); ok {
+		target, err := presigner.PresignGet(ctx, key, uploadRedirectTTL)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "failed to sign file url"})
+			return
+		}
+		c.Header("Cache-Control", "no-store")
+		c.Redirect(http.StatusFound, target)
+		return
+	}
+
+	body, info, err := r.uploads.Open(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "file not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "failed to read file"})
+		return
+	}
+	defer body.Close()
+
+	c.Header("Cache-Control", cacheControl)
+	c.Header("Content-Type", info.ContentType)
+	if seeker, ok := body.(io.ReadSeeker); ok {
+		http.ServeContent(c.Writer, c.Request, path.Base(key), info.ModTime, seeker)
+		return
+	}
+	c.DataFromReader(http.StatusOK, info.Size, info.ContentType, body, nil)
+}
+
+func isPublicUploadKey(key string) bool {
+	return strings.HasPrefix(key, "theme-assets/")
+}
+
+// authorizeUploadKey checks the caller may read key.
+func (r *Resolver) authorizeUploadKey(ctx context.Context, key string) error {
+	if isPublicUploadKey(key) {
+		return nil
+	}
+	userID := middleware.GetCurrentUserID(ctx)
+	if userID == "" {
+		return errUploadUnauthenticated
+	}
+	if middleware.GetUserRoleFromContext(ctx) == auth.UserRoleSuperAdmin {
+		return nil
+	}
+
+	owner, err := r.resolveUploadOwner(ctx, key)
+	if err != nil {
+		return err
+	}
+	if owner.userID != "" && owner.userID == userID {
+		return nil
+	}
+
+	callerCompanyID := middleware.GetCompanyFromContext(ctx)
+	for _, companyID := range owner.companyIDs {
+		if companyID == "" {
+			continue
+		}
+		if companyID == callerCompanyID || r.validateCompanyScope(ctx, userID, companyID) == nil {
+			return nil
+		}
+	}
+	return errUploadAccessDenied
+}
+
+// resolveUploadOwner reads the owner from the key layout. Photos written
+// before keys carried a company are looked up through the record that
+// references them.
+func (r *Resolver) resolveUploadOwner(ctx context.Context, key string) (uploadOwner, error) {
+	segments := strings.Split(key, "/")
+	if len(segments) < 2 {
+		return uploadOwner{}, errUploadAccessDenied
+	}
+
+	switch segments[0] {
+	case "vehicle_tax_documents":
+		if len(segments) >= 3 {
+			return uploadOwner{companyIDs: []string{segments[1]}}, nil
+		}
+	case "avatars":
+		if len(segments) >= 3 {
+			companyIDs, err := r.getAssignedCompanyIDs(ctx, segments[1])
+			if err != nil {
+				return uploadOwner{}, fmt.Errorf("failed to resolve file owner: %w", err)
+			}
+			return uploadOwner{userID: segments[1], companyIDs: companyIDs}, nil
+		}
+	case "satpam_photos":
+		if len(segments) >= 3 {
+			return uploadOwner{companyIDs: []string{segments[1]}}, nil
+		}
+		var companyIDs []string
+		err := r.db.WithContext(ctx).
+			Table("gate_check_photos AS p").
+			Joins("JOIN gate_guest_logs g ON g.id = p.related_record_id").
+			Where("p.file_path = ?", storage.URLPath(key)).
+			Distinct("g.company_id").
+			Pluck("g.company_id", &companyIDs).Error
+		if err != nil {
+			return uploadOwner{}, fmt.Errorf("failed to resolve file owner: %w", err)
+		}
+		return uploadOwner{companyIDs: companyIDs}, nil
+	case "harvest_photos":
+		if len(segments) >= 3 {
+			return uploadOwner{companyIDs: []string{segments[1]}}, nil
+		}
+		var companyIDs []string
+		err := r.db.WithContext(ctx).
+			Table("harvest_records").
+			Where("photo_url = ? AND company_id IS NOT NULL", storage.URLPath(key)).
+			Distinct("company_id").
+			Pluck("company_id", &companyIDs).Error
+		if err != nil {
+			return uploadOwner{}, fmt.Errorf("failed to resolve file owner: %w", err)
+		}
+		return uploadOwner{companyIDs: companyIDs}, nil
+	}
+	return uploadOwner{}, errUploadAccessDenied
+}
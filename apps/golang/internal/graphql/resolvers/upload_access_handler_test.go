@@ -0,0 +1,92 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"agrinovagraphql/server/pkg/storage"
+)
+
+func newUploadAccessRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	uploads, err := storage.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, storage.PutBytes(ctx, uploads, "theme-assets/web/bg.png", []byte("theme"), "image/png"))
+	require.NoError(t, storage.PutBytes(ctx, uploads, "vehicle_tax_documents/company-1/tax-1/doc.pdf", []byte("stnk"), "application/pdf"))
+
+	r := &Resolver{uploads: uploads, uploadURLs: storage.NewURLSigner("test-secret")}
+	router := gin.New()
+	// Stands in for the auth middlewares: X-Test-User/X-Test-Company become
+	// the request's identity.
+	router.Use(func(c *gin.Context) {
+		if userID := c.GetHeader("X-Test-User"); userID != "" {
+			reqCtx := context.WithValue(c.Request.Context(), "user_id", userID)
+			reqCtx = context.WithValue(reqCtx, "company_id", c.GetHeader("X-Test-Company"))
+			c.Request = c.Request.WithContext(reqCtx)
+		}
+		c.Next()
+	})
+	router.GET("/uploads/*key", r.HandleUploadDownload)
+	router.GET("/api/uploads/signed-url", r.HandleUploadSignedURL)
+	return router
+}
+
+func performUploadRequest(router *gin.Engine, target string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestHandleUploadDownload_EnforcesAccess(t *testing.T) {
+	router := newUploadAccessRouter(t)
+	documentPath := "/uploads/vehicle_tax_documents/company-1/tax-1/doc.pdf"
+	member := map[string]string{"X-Test-User": "user-1", "X-Test-Company": "company-1"}
+
+	public := performUploadRequest(router, "/uploads/theme-assets/web/bg.png", nil)
+	assert.Equal(t, http.StatusOK, public.Code)
+	assert.Equal(t, "theme", public.Body.String())
+
+	assert.Equal(t, http.StatusUnauthorized, performUploadRequest(router, documentPath, nil).Code)
+	assert.Equal(t, http.StatusNotFound, performUploadRequest(router, "/uploads/..%2F..%2Fetc/passwd", nil).Code)
+
+	owned := performUploadRequest(router, documentPath, member)
+	assert.Equal(t, http.StatusOK, owned.Code)
+	assert.Equal(t, "stnk", owned.Body.String())
+
+	signResponse := performUploadRequest(router, "/api/uploads/signed-url?path="+url.QueryEscape(documentPath), member)
+	require.Equal(t, http.StatusOK, signResponse.Code)
+	var payload struct {
+		URLs []struct {
+			Path string `json:"path"`
+			URL  string `json:"url"`
+		} `json:"urls"`
+	}
+	require.NoError(t, json.Unmarshal(signResponse.Body.Bytes(), &payload))
+	require.Len(t, payload.URLs, 1)
+	assert.Equal(t, documentPath, payload.URLs[0].Path)
+
+	signed := performUploadRequest(router, payload.URLs[0].URL, nil)
+	assert.Equal(t, http.StatusOK, signed.Code)
+	assert.Equal(t, "stnk", signed.Body.String())
+
+	tampered := performUploadRequest(router, payload.URLs[0].URL+"0", nil)
+	assert.Equal(t, http.StatusForbidden, tampered.Code)
+
+	assert.Equal(t, http.StatusUnauthorized, performUploadRequest(router, "/api/uploads/signed-url?path="+url.QueryEscape(documentPath), nil).Code)
+	assert.Equal(t, http.StatusBadRequest, performUploadRequest(router, "/api/uploads/signed-url?path=/etc/passwd", member).Code)
+}
@@ -1,49 +1,22 @@
 package resolvers
 
 import (
-	"path"
-	"path/filepath"
-	"strings"
-)
-
-const defaultUploadsDir = "./uploads"
+	"context"
+	"io"
 
-func normalizeUploadsRoot(dir string) string {
-	trimmed := strings.TrimSpace(dir)
-	if trimmed == "" {
-		return defaultUploadsDir
-	}
+	"agrinovagraphql/server/pkg/storage"
+)
 
-	cleaned := filepath.Clean(trimmed)
-	if cleaned == "." {
-		return defaultUploadsDir
+// saveUpload stores body under the key built from parts and returns the
+// served `/uploads` path recorded by callers.
+func (r *Resolver) saveUpload(ctx context.Context, body io.Reader, size int64, contentType string, parts ...string) (string, error) {
+	key := storage.JoinKey(parts...)
+	if err := r.uploads.Put(ctx, key, body, size, contentType); err != nil {
+		return "", err
 	}
-
-	return cleaned
-}
-
-func (r *Resolver) uploadsRootDir() string {
-	return normalizeUploadsRoot(r.uploadsDir)
-}
-
-func (r *Resolver) uploadAbsolutePath(parts ...string) string {
-	segments := make([]string, 0, len(parts)+1)
-	segments = append(segments, r.uploadsRootDir())
-	segments = append(segments, parts...)
-	return filepath.Join(segments...)
+	return storage.URLPath(key), nil
 }
 
 func uploadURLPath(parts ...string) string {
-	cleaned := make([]string, 0, len(parts)+1)
-	cleaned = append(cleaned, "uploads")
-
-	for _, part := range parts {
-		normalized := strings.Trim(strings.ReplaceAll(part, "\\", "/"), "/")
-		if normalized == "" {
-			continue
-		}
-		cleaned = append(cleaned, normalized)
-	}
-
-	return "/" + path.Join(cleaned...)
+	return storage.URLPath(storage.JoinKey(parts...))
 }
@@ -0,0 +1,129 @@
+package resolvers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	masterModels "agrinovagraphql/server/internal/master/models"
+	"agrinovagraphql/server/internal/middleware"
+	"agrinovagraphql/server/pkg/spreadsheet"
+
+	"github.com/gin-gonic/gin"
+)
+
+const userImportMaxUploadSize = 5 * 1024 * 1024 // 5 MB
+
+// HandleUserImport validates a CSV/XLSX file of users and assignments and,
+// when dryRun=false, applies it. Requests default to a dry run so callers
+// always see per-row errors before anything is written.
+func (r *Resolver) HandleUserImport(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := middleware.GetCurrentUserID(ctx)
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "authentication required",
+		})
+		return
+	}
+
+	dryRun := true
+	if value := strings.TrimSpace(c.PostForm("dryRun")); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "dryRun must be true or false",
+			})
+			return
+		}
+		dryRun = parsed
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "file is required",
+		})
+		return
+	}
+
+	if fileHeader.Size <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "file is empty",
+		})
+		return
+	}
+
+	if fileHeader.Size > userImportMaxUploadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"success": false,
+			"message": "file exceeds 5 MB",
+		})
+		return
+	}
+
+	format, err := spreadsheet.FormatFromFilename(fileHeader.Filename)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": fmt.Sprintf("failed to read file: %v", err),
+		})
+		return
+	}
+	defer file.Close()
+
+	table, err := spreadsheet.ReadTable(format, file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	result, err := r.UserImportService.Import(ctx, userID, middleware.GetCompanyFromContext(ctx), table, dryRun)
+	if err != nil {
+		status := http.StatusInternalServerError
+		var masterErr *masterModels.MasterDataError
+		if errors.As(err, &masterErr) {
+			status = http.StatusBadRequest
+			if masterErr.Code == masterModels.ErrCodePermissionDenied {
+				status = http.StatusForbidden
+			}
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	message := "validation passed"
+	switch {
+	case len(result.Errors) > 0:
+		message = fmt.Sprintf("validation failed with %d error(s); nothing was imported", len(result.Errors))
+	case result.Applied:
+		message = fmt.Sprintf("import successful: %d created, %d assigned", result.Created, result.Assigned)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": len(result.Errors) == 0,
+		"message": message,
+		"result":  result,
+	})
+}
@@ -1,12 +1,14 @@
 package resolvers
 
 import (
+	"context"
 	"fmt"
+	"mime"
 	"net/http"
-	"os"
 	"path/filepath"
 	"strings"
 
+	"agrinovagraphql/server/internal/graphql/domain/master"
 	"agrinovagraphql/server/internal/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -104,18 +106,26 @@ func (r *Resolver) HandleVehicleTaxDocumentUpload(c *gin.Context) {
 		return
 	}
 
-	absoluteDir := r.uploadAbsolutePath("vehicle_tax_documents", vehicle.CompanyID, vehicleTaxID)
-	if err := os.MkdirAll(absoluteDir, 0755); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+	if !r.reserveUploadQuota(c, vehicle.CompanyID, fileHeader.Size) {
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		r.releaseUploadQuota(ctx, vehicle.CompanyID, fileHeader.Size)
+		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
-			"message": fmt.Sprintf("failed to create upload directory: %v", err),
+			"message": fmt.Sprintf("failed to read upload: %v", err),
 		})
 		return
 	}
+	defer file.Close()
 
 	storedFileName := uuid.NewString() + ext
-	absolutePath := filepath.Join(absoluteDir, storedFileName)
-	if err := c.SaveUploadedFile(fileHeader, absolutePath); err != nil {
+	contentType := mime.TypeByExtension(ext)
+	filePath, err := r.saveUpload(ctx, file, fileHeader.Size, contentType, "vehicle_tax_documents", vehicle.CompanyID, vehicleTaxID, storedFileName)
+	if err != nil {
+		r.releaseUploadQuota(ctx, vehicle.CompanyID, fileHeader.Size)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
 			"message": fmt.Sprintf("failed to save file: %v", err),
@@ -123,7 +133,6 @@ func (r *Resolver) HandleVehicleTaxDocumentUpload(c *gin.Context) {
 		return
 	}
 
-	filePath := uploadURLPath("vehicle_tax_documents", vehicle.CompanyID, vehicleTaxID, storedFileName)
 	c.JSON(http.StatusOK, gin.H{
 		"success":      true,
 		"message":      "upload successful",
@@ -134,6 +143,25 @@ func (r *Resolver) HandleVehicleTaxDocumentUpload(c *gin.Context) {
 	})
 }
 
+// deleteUnreferencedVehicleTaxFiles removes uploaded documents that no
+// remaining vehicle tax document points at and releases their storage.
+func (r *Resolver) deleteUnreferencedVehicleTaxFiles(ctx context.Context, companyID string, filePaths ...string) {
+	for _, filePath := range filePaths {
+		filePath = strings.TrimSpace(filePath)
+		if filePath == "" {
+			continue
+		}
+		var references int64
+		if err := r.db.WithContext(ctx).
+			Model(&master.VehicleTaxDocument{}).
+			Where("file_path = ?", filePath).
+			Count(&references).Error; err != nil || references > 0 {
+			continue
+		}
+		r.deleteUpload(ctx, companyID, filePath)
+	}
+}
+
 func isAllowedVehicleTaxDocumentExtension(ext string) bool {
 	switch strings.ToLower(strings.TrimSpace(ext)) {
 	case ".pdf", ".jpg", ".jpeg", ".png", ".webp":
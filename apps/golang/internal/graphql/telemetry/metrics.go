@@ -0,0 +1,62 @@
+package telemetry
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	resultOK    = "ok"
+	resultError = "error"
+)
+
+var (
+	operations = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "agrinova",
+			Subsystem: "graphql",
+			Name:      "operations_total",
+			Help:      "Total number of executed GraphQL operations, by operation name, role and result",
+		},
+		[]string{"operation", "type", "role", "result"},
+	)
+
+	operationDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "agrinova",
+			Subsystem: "graphql",
+			Name:      "operation_duration_seconds",
+			Help:      "Latency of GraphQL queries and mutations, by operation name and role",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"operation", "type", "role"},
+	)
+
+	activeSubscriptions = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "agrinova",
+			Subsystem: "graphql",
+			Name:      "active_subscriptions",
+			Help:      "Number of running GraphQL subscriptions, by operation name",
+		},
+		[]string{"operation"},
+	)
+
+	websocketConnections = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "agrinova",
+			Subsystem: "graphql",
+			Name:      "websocket_connections",
+			Help:      "Number of initialized GraphQL WebSocket connections",
+		},
+	)
+)
+
+func recordOperation(operation, opType, role string, seconds float64, failed bool) {
+	result := resultOK
+	if failed {
+		result = resultError
+	}
+	operations.WithLabelValues(operation, opType, role, result).Inc()
+	operationDuration.WithLabelValues(operation, opType, role).Observe(seconds)
+}
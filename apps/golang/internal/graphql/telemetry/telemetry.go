@@ -0,0 +1,172 @@
+// Package telemetry records Prometheus metrics and OpenTelemetry spans for
+// GraphQL operations: latency and errors by operation name and role, a span
+// per operation and per resolver, and subscription and WebSocket gauges.
+package telemetry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"agrinovagraphql/server/internal/middleware"
+	"agrinovagraphql/server/pkg/logger"
+	"agrinovagraphql/server/pkg/tracing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	anonymousRole      = "ANONYMOUS"
+	anonymousOperation = "anonymous"
+	otherOperation     = "other"
+
+	// DefaultMaxOperationNames bounds the operation label, which clients
+	// choose, so unknown names cannot grow the metric series without limit.
+	DefaultMaxOperationNames = 500
+)
+
+// Tracer is a gqlgen extension that instruments every operation and
+// resolver. It should be the first extension added, so its operation span
+// is the parent of all resolver spans.
+type Tracer struct {
+	// MaxOperationNames is the number of distinct operation names kept as
+	// metric labels; later names are recorded as "other". Zero selects
+	// DefaultMaxOperationNames.
+	MaxOperationNames int
+
+	mu    sync.Mutex
+	names map[string]struct{}
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+	graphql.FieldInterceptor
+} = &Tracer{}
+
+// ExtensionName implements graphql.HandlerExtension.
+func (t *Tracer) ExtensionName() string {
+	return "Telemetry"
+}
+
+// Validate implements graphql.HandlerExtension.
+func (t *Tracer) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptOperation implements graphql.OperationInterceptor.
+func (t *Tracer) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	opCtx := graphql.GetOperationContext(ctx)
+	name := t.operationLabel(opCtx)
+	opType := operationType(opCtx)
+
+	if opType == string(ast.Subscription) {
+		activeSubscriptions.WithLabelValues(name).Inc()
+		context.AfterFunc(ctx, func() {
+			activeSubscriptions.WithLabelValues(name).Dec()
+		})
+		operations.WithLabelValues(name, opType, roleLabel(ctx), resultOK).Inc()
+		return next(ctx)
+	}
+
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "graphql."+opType+" "+name,
+		attribute.String("graphql.operation.name", name),
+		attribute.String("graphql.operation.type", opType),
+	)
+	handler := next(ctx)
+
+	return func(ctx context.Context) *graphql.Response {
+		defer span.End()
+
+		resp := handler(ctx)
+		failed := resp != nil && len(resp.Errors) > 0
+		recordOperation(name, opType, roleLabel(ctx), time.Since(start).Seconds(), failed)
+		if failed {
+			span.SetStatus(codes.Error, resp.Errors.Error())
+			logger.WithContext(ctx).Warn("GraphQL %s %s failed: %s", opType, name, resp.Errors.Error())
+		}
+		return resp
+	}
+}
+
+// InterceptField implements graphql.FieldInterceptor. Only fields backed by
+// a resolver get a span; plain struct fields would only add noise.
+func (t *Tracer) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	ctx, span := tracing.Start(ctx, fc.Object+"."+fc.Field.Name,
+		attribute.String("graphql.field.path", fc.Path().String()),
+	)
+	res, err := next(ctx)
+	tracing.End(span, err)
+	return res, err
+}
+
+// operationLabel returns the operation name to use as a metric label.
+func (t *Tracer) operationLabel(opCtx *graphql.OperationContext) string {
+	name := opCtx.OperationName
+	if name == "" && opCtx.Operation != nil {
+		name = opCtx.Operation.Name
+	}
+	if name == "" {
+		return anonymousOperation
+	}
+
+	limit := t.MaxOperationNames
+	if limit <= 0 {
+		limit = DefaultMaxOperationNames
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.names[name]; ok {
+		return name
+	}
+	if len(t.names) >= limit {
+		return otherOperation
+	}
+	if t.names == nil {
+		t.names = make(map[string]struct{})
+	}
+	t.names[name] = struct{}{}
+	return name
+}
+
+func operationType(opCtx *graphql.OperationContext) string {
+	if opCtx.Operation == nil {
+		return "unknown"
+	}
+	return string(opCtx.Operation.Operation)
+}
+
+func roleLabel(ctx context.Context) string {
+	if role := middleware.GetUserRoleFromContext(ctx); role != "" {
+		return string(role)
+	}
+	return anonymousRole
+}
+
+type websocketCountedKey struct{}
+
+// WebsocketInit counts an initialized GraphQL WebSocket connection. Use it
+// as transport.Websocket.InitFunc together with WebsocketClose.
+func WebsocketInit(ctx context.Context, _ transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+	websocketConnections.Inc()
+	return context.WithValue(ctx, websocketCountedKey{}, true), nil, nil
+}
+
+// WebsocketClose uncounts a connection counted by WebsocketInit. Use it as
+// transport.Websocket.CloseFunc.
+func WebsocketClose(ctx context.Context, _ int) {
+	if counted, _ := ctx.Value(websocketCountedKey{}).(bool); counted {
+		websocketConnections.Dec()
+	}
+}
@@ -0,0 +1,64 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestOperationLabel(t *testing.T) {
+	tracer := &Tracer{MaxOperationNames: 2}
+	label := func(name string) string {
+		return tracer.operationLabel(&graphql.OperationContext{OperationName: name})
+	}
+
+	if got := label(""); got != anonymousOperation {
+		t.Errorf("unnamed operation = %q, want %q", got, anonymousOperation)
+	}
+	if got := label("GetHarvests"); got != "GetHarvests" {
+		t.Errorf("first name = %q", got)
+	}
+	if got := label("SyncHarvests"); got != "SyncHarvests" {
+		t.Errorf("second name = %q", got)
+	}
+	if got := label("Random123"); got != otherOperation {
+		t.Errorf("name past the limit = %q, want %q", got, otherOperation)
+	}
+	if got := label("GetHarvests"); got != "GetHarvests" {
+		t.Errorf("known name past the limit = %q", got)
+	}
+}
+
+func TestOperationLabel_FromDocument(t *testing.T) {
+	opCtx := &graphql.OperationContext{
+		Operation: &ast.OperationDefinition{Name: "ManagerDashboard", Operation: ast.Query},
+	}
+	if got := (&Tracer{}).operationLabel(opCtx); got != "ManagerDashboard" {
+		t.Errorf("label = %q, want ManagerDashboard", got)
+	}
+	if got := operationType(opCtx); got != "query" {
+		t.Errorf("type = %q, want query", got)
+	}
+}
+
+func TestWebsocketGauge(t *testing.T) {
+	before := testutil.ToFloat64(websocketConnections)
+
+	ctx, _, err := WebsocketInit(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("WebsocketInit: %v", err)
+	}
+	if got := testutil.ToFloat64(websocketConnections); got != before+1 {
+		t.Errorf("after init = %v, want %v", got, before+1)
+	}
+
+	WebsocketClose(ctx, 1000)
+	// Connections closed before init were never counted.
+	WebsocketClose(context.Background(), 1000)
+	if got := testutil.ToFloat64(websocketConnections); got != before {
+		t.Errorf("after close = %v, want %v", got, before)
+	}
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Master data import types mirror the GraphQL MasterDataImportType enum.
+const (
+	MasterDataImportHierarchy = "HIERARCHY"
+	MasterDataImportEmployee  = "EMPLOYEE"
+)
+
+// Master data import job statuses mirror the GraphQL MasterDataImportStatus enum.
+const (
+	MasterDataImportPending   = "PENDING"
+	MasterDataImportRunning   = "RUNNING"
+	MasterDataImportCompleted = "COMPLETED"
+	MasterDataImportFailed    = "FAILED"
+)
+
+// MaxMasterDataImportRows caps the number of data rows accepted in one file.
+const MaxMasterDataImportRows = 5000
+
+// MasterDataImportRowError describes why one spreadsheet row was not imported.
+type MasterDataImportRowError struct {
+	Row     int    `json:"row"`
+	Entity  string `json:"entity,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// MasterDataImportJob tracks an asynchronous master data import.
+type MasterDataImportJob struct {
+	ID            string     `gorm:"column:id;type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	CompanyID     string     `gorm:"column:company_id;type:uuid" json:"companyId"`
+	ImportType    string     `gorm:"column:import_type" json:"importType"`
+	Status        string     `gorm:"column:status" json:"status"`
+	FileName      string     `gorm:"column:file_name" json:"fileName"`
+	TotalRows     int        `gorm:"column:total_rows" json:"totalRows"`
+	ProcessedRows int        `gorm:"column:processed_rows" json:"processedRows"`
+	CreatedCount  int        `gorm:"column:created_count" json:"createdCount"`
+	UpdatedCount  int        `gorm:"column:updated_count" json:"updatedCount"`
+	FailedRows    int        `gorm:"column:failed_rows" json:"failedRows"`
+	ErrorsJSON    string     `gorm:"column:errors_json" json:"-"`
+	Message       *string    `gorm:"column:message" json:"message,omitempty"`
+	CreatedBy     string     `gorm:"column:created_by;type:uuid" json:"createdBy"`
+	StartedAt     *time.Time `gorm:"column:started_at" json:"startedAt,omitempty"`
+	FinishedAt    *time.Time `gorm:"column:finished_at" json:"finishedAt,omitempty"`
+	CreatedAt     time.Time  `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName returns the table name for MasterDataImportJob
+func (MasterDataImportJob) TableName() string {
+	return "master_data_import_jobs"
+}
+
+// Errors decodes the row errors recorded on the job.
+func (j *MasterDataImportJob) Errors() []MasterDataImportRowError {
+	var rowErrors []MasterDataImportRowError
+	if j == nil || j.ErrorsJSON == "" {
+		return []MasterDataImportRowError{}
+	}
+	if err := json.Unmarshal([]byte(j.ErrorsJSON), &rowErrors); err != nil {
+		return []MasterDataImportRowError{}
+	}
+	return rowErrors
+}
+
+// SetErrors encodes row errors for storage.
+func (j *MasterDataImportJob) SetErrors(rowErrors []MasterDataImportRowError) {
+	if rowErrors == nil {
+		rowErrors = []MasterDataImportRowError{}
+	}
+	encoded, _ := json.Marshal(rowErrors)
+	j.ErrorsJSON = string(encoded)
+}
+
+// IsFinished reports whether the job has stopped running.
+func (j *MasterDataImportJob) IsFinished() bool {
+	return j.Status == MasterDataImportCompleted || j.Status == MasterDataImportFailed
+}
@@ -313,6 +313,7 @@ const (
 	ErrCodeInactiveEntity        = "INACTIVE_ENTITY"
 	ErrCodeDependencyExists      = "DEPENDENCY_EXISTS"
 	ErrCodeBusinessRuleViolation = "BUSINESS_RULE_VIOLATION"
+	ErrCodeQuotaExceeded         = "QUOTA_EXCEEDED"
 )
 
 // NewMasterDataError creates a new master data error
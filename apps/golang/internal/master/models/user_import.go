@@ -0,0 +1,50 @@
+package models
+
+// User import row actions
+const (
+	UserImportActionCreate = "CREATE"
+	UserImportActionAssign = "ASSIGN"
+)
+
+// MaxUserImportRows caps the number of data rows accepted in one file.
+const MaxUserImportRows = 1000
+
+// UserImportRowError describes a validation failure for one spreadsheet row.
+// Row 0 is used for file-level errors.
+type UserImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// UserImportRow is the resolved plan for one spreadsheet row.
+type UserImportRow struct {
+	Row               int      `json:"row"`
+	Action            string   `json:"action"`
+	UserID            string   `json:"userId,omitempty"`
+	Username          string   `json:"username"`
+	Name              string   `json:"name"`
+	Role              string   `json:"role"`
+	NIK               string   `json:"nik,omitempty"`
+	CompanyID         string   `json:"companyId"`
+	EstateIDs         []string `json:"estateIds"`
+	DivisionIDs       []string `json:"divisionIds"`
+	TemporaryPassword string   `json:"temporaryPassword,omitempty"`
+}
+
+// UserImportResult reports a dry run or an applied import.
+type UserImportResult struct {
+	DryRun    bool                 `json:"dryRun"`
+	Applied   bool                 `json:"applied"`
+	TotalRows int                  `json:"totalRows"`
+	ValidRows int                  `json:"validRows"`
+	Created   int                  `json:"created"`
+	Assigned  int                  `json:"assigned"`
+	Rows      []*UserImportRow     `json:"rows"`
+	Errors    []UserImportRowError `json:"errors"`
+}
+
+// AddError records a row validation error.
+func (r *UserImportResult) AddError(row int, field, message string) {
+	r.Errors = append(r.Errors, UserImportRowError{Row: row, Field: field, Message: message})
+}
@@ -0,0 +1,944 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	employeeServices "agrinovagraphql/server/internal/employee/services"
+	"agrinovagraphql/server/internal/graphql/domain/master"
+	"agrinovagraphql/server/internal/master/models"
+	"agrinovagraphql/server/internal/master/repositories"
+	"agrinovagraphql/server/pkg/spreadsheet"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// masterDataImportProgressEvery controls how often progress is persisted.
+	masterDataImportProgressEvery = 25
+	// maxMasterDataImportErrors caps the row errors stored on a job.
+	maxMasterDataImportErrors = 500
+
+	defaultMasterDataImportJobLimit = 20
+	maxMasterDataImportJobLimit     = 100
+)
+
+// Hierarchy spreadsheet columns. One row describes a block together with its
+// division and estate; rows without block cells only upsert the parents.
+const (
+	hierarchyColumnEstateCode     = "estate_code"
+	hierarchyColumnEstateName     = "estate_name"
+	hierarchyColumnEstateLocation = "estate_location"
+	hierarchyColumnEstateLuasHa   = "estate_luas_ha"
+	hierarchyColumnDivisionCode   = "division_code"
+	hierarchyColumnDivisionName   = "division_name"
+	hierarchyColumnBlockCode      = "block_code"
+	hierarchyColumnBlockName      = "block_name"
+	hierarchyColumnLuasHa         = "luas_ha"
+	hierarchyColumnPlantingYear   = "planting_year"
+	hierarchyColumnCropType       = "crop_type"
+	hierarchyColumnStatus         = "status"
+	hierarchyColumnISTM           = "istm"
+	hierarchyColumnLandType       = "land_type"
+	hierarchyColumnTarifBlok      = "tarif_blok"
+)
+
+// Employee spreadsheet columns.
+const (
+	employeeColumnNIK          = "nik"
+	employeeColumnName         = "name"
+	employeeColumnRole         = "role"
+	employeeColumnEstateCode   = "estate_code"
+	employeeColumnDivisionCode = "division_code"
+	employeeColumnPhotoURL     = "photo_url"
+	employeeColumnIsActive     = "is_active"
+)
+
+var hierarchyImportColumns = []string{
+	hierarchyColumnEstateCode,
+	hierarchyColumnEstateName,
+	hierarchyColumnEstateLocation,
+	hierarchyColumnEstateLuasHa,
+	hierarchyColumnDivisionCode,
+	hierarchyColumnDivisionName,
+	hierarchyColumnBlockCode,
+	hierarchyColumnBlockName,
+	hierarchyColumnLuasHa,
+	hierarchyColumnPlantingYear,
+	hierarchyColumnCropType,
+	hierarchyColumnStatus,
+	hierarchyColumnISTM,
+	hierarchyColumnLandType,
+	hierarchyColumnTarifBlok,
+}
+
+var employeeImportColumns = []string{
+	employeeColumnNIK,
+	employeeColumnName,
+	employeeColumnRole,
+	employeeColumnEstateCode,
+	employeeColumnDivisionCode,
+	employeeColumnPhotoURL,
+	employeeColumnIsActive,
+}
+
+var masterDataImportRequiredColumns = map[string][]string{
+	models.MasterDataImportHierarchy: {hierarchyColumnEstateCode},
+	models.MasterDataImportEmployee:  {employeeColumnNIK, employeeColumnName, employeeColumnRole},
+}
+
+// MasterDataImportService imports and exports the estate → division → block
+// hierarchy and employees as spreadsheets. Imports run in the background and
+// report progress through MasterDataImportJob rows; every row goes through
+// the regular master service so access checks, quotas and code generation
+// apply exactly as they do for single-record mutations.
+type MasterDataImportService struct {
+	master    *masterService
+	employees *employeeServices.EmployeeService
+	db        *gorm.DB
+}
+
+// NewMasterDataImportService creates a new master data import service
+func NewMasterDataImportService(repo repositories.MasterRepository, db *gorm.DB, employees *employeeServices.EmployeeService) *MasterDataImportService {
+	return &MasterDataImportService{
+		master:    NewMasterService(repo, db).(*masterService),
+		employees: employees,
+		db:        db,
+	}
+}
+
+// ImportColumns returns the spreadsheet columns for an import type.
+func ImportColumns(importType string) []string {
+	switch importType {
+	case models.MasterDataImportHierarchy:
+		return hierarchyImportColumns
+	case models.MasterDataImportEmployee:
+		return employeeImportColumns
+	default:
+		return nil
+	}
+}
+
+// NormalizeMasterDataImportType upper-cases an import type and reports
+// whether it is supported.
+func NormalizeMasterDataImportType(importType string) (string, bool) {
+	normalized := strings.ToUpper(strings.TrimSpace(importType))
+	return normalized, ImportColumns(normalized) != nil
+}
+
+// StartImport validates the file shape, records a PENDING job and processes
+// the rows in the background. The returned job is a snapshot; poll GetJob for
+// progress.
+func (s *MasterDataImportService) StartImport(
+	ctx context.Context,
+	requesterID string,
+	companyID string,
+	importType string,
+	fileName string,
+	table *spreadsheet.Table,
+) (*models.MasterDataImportJob, error) {
+	importType, ok := NormalizeMasterDataImportType(importType)
+	if !ok {
+		return nil, models.NewMasterDataError(models.ErrCodeInvalidInput, "type must be HIERARCHY or EMPLOYEE", "type")
+	}
+	companyID = strings.TrimSpace(companyID)
+	if companyID == "" {
+		return nil, models.NewMasterDataError(models.ErrCodeInvalidInput, "company is required", "companyId")
+	}
+	if table == nil || len(table.Rows) == 0 {
+		return nil, models.NewMasterDataError(models.ErrCodeInvalidInput, "file has no data rows", "file")
+	}
+	if len(table.Rows) > models.MaxMasterDataImportRows {
+		return nil, models.NewMasterDataError(
+			models.ErrCodeInvalidInput,
+			fmt.Sprintf("file has %d rows; at most %d rows can be imported at once", len(table.Rows), models.MaxMasterDataImportRows),
+			"file",
+		)
+	}
+	for _, column := range masterDataImportRequiredColumns[importType] {
+		if !table.HasColumn(column) {
+			return nil, models.NewMasterDataError(models.ErrCodeInvalidInput, fmt.Sprintf("missing required column %q", column), column)
+		}
+	}
+
+	if err := s.master.ValidateCompanyAccess(ctx, requesterID, companyID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job := &models.MasterDataImportJob{
+		CompanyID:  companyID,
+		ImportType: importType,
+		Status:     models.MasterDataImportPending,
+		FileName:   strings.TrimSpace(fileName),
+		TotalRows:  len(table.Rows),
+		CreatedBy:  requesterID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	job.SetErrors(nil)
+	if err := s.db.WithContext(ctx).Create(job).Error; err != nil {
+		return nil, fmt.Errorf("failed to create import job: %w", err)
+	}
+
+	snapshot := *job
+	go s.run(context.WithoutCancel(ctx), job, requesterID, table)
+
+	return &snapshot, nil
+}
+
+// GetJob returns an import job the requester can access.
+func (s *MasterDataImportService) GetJob(ctx context.Context, requesterID, jobID string) (*models.MasterDataImportJob, error) {
+	var job models.MasterDataImportJob
+	if err := s.db.WithContext(ctx).Where("id = ?", jobID).First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.NewMasterDataError(models.ErrCodeNotFound, "import job not found", "id")
+		}
+		return nil, fmt.Errorf("failed to load import job: %w", err)
+	}
+	if err := s.master.ValidateCompanyAccess(ctx, requesterID, job.CompanyID); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListJobs returns the most recent import jobs of a company.
+func (s *MasterDataImportService) ListJobs(ctx context.Context, requesterID, companyID string, limit int) ([]*models.MasterDataImportJob, error) {
+	if err := s.master.ValidateCompanyAccess(ctx, requesterID, companyID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultMasterDataImportJobLimit
+	}
+	if limit > maxMasterDataImportJobLimit {
+		limit = maxMasterDataImportJobLimit
+	}
+
+	var jobs []*models.MasterDataImportJob
+	if err := s.db.WithContext(ctx).
+		Where("company_id = ?", companyID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list import jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// Export returns the header and rows of a company's master data in the same
+// layout the importer reads, so an exported file can be edited and re-imported.
+func (s *MasterDataImportService) Export(ctx context.Context, requesterID, companyID, importType string) ([]string, [][]string, error) {
+	importType, ok := NormalizeMasterDataImportType(importType)
+	if !ok {
+		return nil, nil, models.NewMasterDataError(models.ErrCodeInvalidInput, "type must be HIERARCHY or EMPLOYEE", "type")
+	}
+	if err := s.master.ValidateCompanyAccess(ctx, requesterID, companyID); err != nil {
+		return nil, nil, err
+	}
+
+	switch importType {
+	case models.MasterDataImportEmployee:
+		rows, err := s.exportEmployees(ctx, companyID)
+		return employeeImportColumns, rows, err
+	default:
+		rows, err := s.exportHierarchy(ctx, companyID)
+		return hierarchyImportColumns, rows, err
+	}
+}
+
+type hierarchyExportRow struct {
+	EstateCode     string
+	EstateName     string
+	EstateLocation *string
+	EstateLuasHa   *float64
+	DivisionCode   *string
+	DivisionName   *string
+	BlockCode      *string
+	BlockName      *string
+	LuasHa         *float64
+	PlantingYear   *int
+	CropType       *string
+	Status         *string
+	ISTM           *string
+	LandTypeCode   *string
+	TarifCode      *string
+	Perlakuan      *string
+}
+
+func (s *MasterDataImportService) exportHierarchy(ctx context.Context, companyID string) ([][]string, error) {
+	var records []hierarchyExportRow
+	err := s.db.WithContext(ctx).Raw(`
+		SELECT
+			e.code AS estate_code,
+			e.name AS estate_name,
+			e.location AS estate_location,
+			e.area_ha AS estate_luas_ha,
+			d.code AS division_code,
+			d.name AS division_name,
+			b.block_code,
+			b.name AS block_name,
+			b.area_ha AS luas_ha,
+			b.planting_year,
+			b.crop_type,
+			b.status,
+			b.istm,
+			lt.code AS land_type_code,
+			tb.tarif_code,
+			tb.perlakuan
+		FROM estates e
+		LEFT JOIN divisions d ON d.estate_id = e.id
+		LEFT JOIN blocks b ON b.division_id = d.id
+		LEFT JOIN land_types lt ON lt.id = b.land_type_id
+		LEFT JOIN tarif_blok tb ON tb.id = b.tarif_blok_id
+		WHERE e.company_id = ?
+		ORDER BY e.code, d.code NULLS FIRST, b.block_code NULLS FIRST
+	`, companyID).Scan(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to export hierarchy: %w", err)
+	}
+
+	rows := make([][]string, 0, len(records))
+	for _, record := range records {
+		tarif := stringValue(record.TarifCode)
+		if tarif == "" {
+			tarif = stringValue(record.Perlakuan)
+		}
+		rows = append(rows, []string{
+			record.EstateCode,
+			record.EstateName,
+			stringValue(record.EstateLocation),
+			formatOptionalFloat(record.EstateLuasHa),
+			stringValue(record.DivisionCode),
+			stringValue(record.DivisionName),
+			stringValue(record.BlockCode),
+			stringValue(record.BlockName),
+			formatOptionalFloat(record.LuasHa),
+			formatOptionalInt(record.PlantingYear),
+			stringValue(record.CropType),
+			stringValue(record.Status),
+			stringValue(record.ISTM),
+			stringValue(record.LandTypeCode),
+			tarif,
+		})
+	}
+	return rows, nil
+}
+
+type employeeExportRow struct {
+	NIK          string
+	Name         string
+	Role         string
+	EstateCode   *string
+	DivisionCode *string
+	PhotoURL     *string
+	IsActive     bool
+}
+
+func (s *MasterDataImportService) exportEmployees(ctx context.Context, companyID string) ([][]string, error) {
+	var records []employeeExportRow
+	err := s.db.WithContext(ctx).Raw(`
+		SELECT
+			emp.nik,
+			emp.name,
+			emp.role,
+			e.code AS estate_code,
+			d.code AS division_code,
+			emp.photo_url,
+			emp.is_active
+		FROM employees emp
+		LEFT JOIN divisions d ON d.id = emp.division_id
+		LEFT JOIN estates e ON e.id = d.estate_id
+		WHERE emp.company_id = ?
+		ORDER BY emp.nik
+	`, companyID).Scan(&records).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to export employees: %w", err)
+	}
+
+	rows := make([][]string, 0, len(records))
+	for _, record := range records {
+		rows = append(rows, []string{
+			record.NIK,
+			record.Name,
+			record.Role,
+			stringValue(record.EstateCode),
+			stringValue(record.DivisionCode),
+			stringValue(record.PhotoURL),
+			strconv.FormatBool(record.IsActive),
+		})
+	}
+	return rows, nil
+}
+
+// masterDataRowCounts is what one row changed.
+type masterDataRowCounts struct {
+	created int
+	updated int
+}
+
+// masterDataRowImporter applies a single spreadsheet row.
+type masterDataRowImporter interface {
+	importRow(ctx context.Context, row spreadsheet.Row, counts *masterDataRowCounts) *models.MasterDataImportRowError
+}
+
+func (s *MasterDataImportService) run(ctx context.Context, job *models.MasterDataImportJob, requesterID string, table *spreadsheet.Table) {
+	var rowErrors []models.MasterDataImportRowError
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("master data import %s panicked: %v", job.ID, recovered)
+			s.finish(ctx, job, rowErrors, models.MasterDataImportFailed, fmt.Sprintf("import aborted: %v", recovered))
+		}
+	}()
+
+	startedAt := time.Now()
+	job.Status = models.MasterDataImportRunning
+	job.StartedAt = &startedAt
+	s.saveProgress(ctx, job, rowErrors)
+
+	var importer masterDataRowImporter
+	switch job.ImportType {
+	case models.MasterDataImportEmployee:
+		importer = newEmployeeRowImporter(s, job.CompanyID)
+	default:
+		importer = newHierarchyRowImporter(s, requesterID, job.CompanyID)
+	}
+
+	for _, row := range table.Rows {
+		var counts masterDataRowCounts
+		rowErr := importer.importRow(ctx, row, &counts)
+		job.CreatedCount += counts.created
+		job.UpdatedCount += counts.updated
+		job.ProcessedRows++
+		if rowErr != nil {
+			rowErr.Row = row.Number
+			job.FailedRows++
+			if len(rowErrors) < maxMasterDataImportErrors {
+				rowErrors = append(rowErrors, *rowErr)
+			}
+		}
+		if job.ProcessedRows%masterDataImportProgressEvery == 0 {
+			s.saveProgress(ctx, job, rowErrors)
+		}
+	}
+
+	message := fmt.Sprintf(
+		"%d row(s) processed: %d created, %d updated, %d failed",
+		job.ProcessedRows, job.CreatedCount, job.UpdatedCount, job.FailedRows,
+	)
+	s.finish(ctx, job, rowErrors, models.MasterDataImportCompleted, message)
+}
+
+func (s *MasterDataImportService) saveProgress(ctx context.Context, job *models.MasterDataImportJob, rowErrors []models.MasterDataImportRowError) {
+	job.SetErrors(rowErrors)
+	job.UpdatedAt = time.Now()
+	if err := s.db.WithContext(ctx).Save(job).Error; err != nil {
+		log.Printf("failed to save master data import %s progress: %v", job.ID, err)
+	}
+}
+
+func (s *MasterDataImportService) finish(
+	ctx context.Context,
+	job *models.MasterDataImportJob,
+	rowErrors []models.MasterDataImportRowError,
+	status string,
+	message string,
+) {
+	finishedAt := time.Now()
+	job.Status = status
+	job.FinishedAt = &finishedAt
+	job.Message = &message
+	s.saveProgress(ctx, job, rowErrors)
+}
+
+// hierarchyRowImporter upserts estates, divisions and blocks by code. Estates
+// and divisions are written at most once per job even when many block rows
+// repeat them.
+type hierarchyRowImporter struct {
+	svc         *MasterDataImportService
+	requesterID string
+	companyID   string
+
+	estates    map[string]string
+	divisions  map[string]string
+	landTypes  map[string]string
+	tarifBloks map[string]string
+}
+
+func newHierarchyRowImporter(svc *MasterDataImportService, requesterID, companyID string) *hierarchyRowImporter {
+	return &hierarchyRowImporter{
+		svc:         svc,
+		requesterID: requesterID,
+		companyID:   companyID,
+		estates:     make(map[string]string),
+		divisions:   make(map[string]string),
+		landTypes:   make(map[string]string),
+		tarifBloks:  make(map[string]string),
+	}
+}
+
+func (h *hierarchyRowImporter) importRow(ctx context.Context, row spreadsheet.Row, counts *masterDataRowCounts) *models.MasterDataImportRowError {
+	estateID, err := h.upsertEstate(ctx, row, counts)
+	if err != nil {
+		return masterDataRowError("ESTATE", err)
+	}
+
+	divisionCode := row.Get(hierarchyColumnDivisionCode)
+	hasBlock := row.Get(hierarchyColumnBlockCode) != "" || row.Get(hierarchyColumnBlockName) != ""
+	if divisionCode == "" {
+		if hasBlock {
+			return &models.MasterDataImportRowError{
+				Entity:  "BLOCK",
+				Field:   hierarchyColumnDivisionCode,
+				Message: "division_code is required for block rows",
+			}
+		}
+		return nil
+	}
+
+	divisionID, err := h.upsertDivision(ctx, row, estateID, divisionCode, counts)
+	if err != nil {
+		return masterDataRowError("DIVISION", err)
+	}
+	if !hasBlock {
+		return nil
+	}
+
+	if err := h.upsertBlock(ctx, row, divisionID, counts); err != nil {
+		return masterDataRowError("BLOCK", err)
+	}
+	return nil
+}
+
+func (h *hierarchyRowImporter) upsertEstate(ctx context.Context, row spreadsheet.Row, counts *masterDataRowCounts) (string, error) {
+	code := row.Get(hierarchyColumnEstateCode)
+	if code == "" {
+		return "", models.NewMasterDataError(models.ErrCodeInvalidInput, "estate_code is required", hierarchyColumnEstateCode)
+	}
+	key := strings.ToLower(code)
+	if id, ok := h.estates[key]; ok {
+		return id, nil
+	}
+
+	luasHa, err := parseImportFloat(row.Get(hierarchyColumnEstateLuasHa), hierarchyColumnEstateLuasHa)
+	if err != nil {
+		return "", err
+	}
+	name := row.Get(hierarchyColumnEstateName)
+	location := row.Get(hierarchyColumnEstateLocation)
+
+	existingID, err := h.svc.lookupID(ctx, "estates", "company_id = ? AND LOWER(code) = LOWER(?)", h.companyID, code)
+	if err != nil {
+		return "", err
+	}
+
+	if existingID != "" {
+		if name != "" || location != "" || luasHa != nil {
+			req := &models.UpdateEstateRequest{ID: existingID, LuasHa: luasHa}
+			if name != "" {
+				req.Name = &name
+			}
+			if location != "" {
+				req.Location = &location
+			}
+			if _, err := h.svc.master.UpdateEstate(ctx, req, h.requesterID); err != nil {
+				return "", err
+			}
+			counts.updated++
+		}
+		h.estates[key] = existingID
+		return existingID, nil
+	}
+
+	if name == "" {
+		return "", models.NewMasterDataError(models.ErrCodeInvalidInput, "estate_name is required for a new estate", hierarchyColumnEstateName)
+	}
+	estate, err := h.svc.master.CreateEstate(ctx, &models.CreateEstateRequest{
+		Name:      name,
+		Code:      code,
+		Location:  location,
+		LuasHa:    luasHa,
+		CompanyID: h.companyID,
+	}, h.requesterID)
+	if err != nil {
+		return "", err
+	}
+	counts.created++
+	h.estates[key] = estate.ID
+	return estate.ID, nil
+}
+
+func (h *hierarchyRowImporter) upsertDivision(
+	ctx context.Context,
+	row spreadsheet.Row,
+	estateID string,
+	code string,
+	counts *masterDataRowCounts,
+) (string, error) {
+	key := estateID + "|" + strings.ToLower(code)
+	if id, ok := h.divisions[key]; ok {
+		return id, nil
+	}
+	name := row.Get(hierarchyColumnDivisionName)
+
+	existingID, err := h.svc.lookupID(ctx, "divisions", "estate_id = ? AND LOWER(code) = LOWER(?)", estateID, code)
+	if err != nil {
+		return "", err
+	}
+
+	if existingID != "" {
+		if name != "" {
+			if _, err := h.svc.master.UpdateDivision(ctx, &models.UpdateDivisionRequest{ID: existingID, Name: &name}, h.requesterID); err != nil {
+				return "", err
+			}
+			counts.updated++
+		}
+		h.divisions[key] = existingID
+		return existingID, nil
+	}
+
+	if name == "" {
+		return "", models.NewMasterDataError(models.ErrCodeInvalidInput, "division_name is required for a new division", hierarchyColumnDivisionName)
+	}
+	division, err := h.svc.master.CreateDivision(ctx, &models.CreateDivisionRequest{
+		Name:     name,
+		Code:     code,
+		EstateID: estateID,
+	}, h.requesterID)
+	if err != nil {
+		return "", err
+	}
+	counts.created++
+	h.divisions[key] = division.ID
+	return division.ID, nil
+}
+
+func (h *hierarchyRowImporter) upsertBlock(ctx context.Context, row spreadsheet.Row, divisionID string, counts *masterDataRowCounts) error {
+	code := row.Get(hierarchyColumnBlockCode)
+	name := row.Get(hierarchyColumnBlockName)
+
+	luasHa, err := parseImportFloat(row.Get(hierarchyColumnLuasHa), hierarchyColumnLuasHa)
+	if err != nil {
+		return err
+	}
+	plantingYear, err := parseImportInt(row.Get(hierarchyColumnPlantingYear), hierarchyColumnPlantingYear)
+	if err != nil {
+		return err
+	}
+	landTypeID, err := h.resolveLandType(ctx, row.Get(hierarchyColumnLandType))
+	if err != nil {
+		return err
+	}
+	tarifBlokID, err := h.resolveTarifBlok(ctx, row.Get(hierarchyColumnTarifBlok))
+	if err != nil {
+		return err
+	}
+	cropType := row.Get(hierarchyColumnCropType)
+	status := strings.ToUpper(row.Get(hierarchyColumnStatus))
+	istm := strings.ToUpper(row.Get(hierarchyColumnISTM))
+
+	// Blocks without a code in the file are matched by name so re-importing a
+	// file that relied on generated codes updates instead of duplicating.
+	var existingID string
+	if code != "" {
+		existingID, err = h.svc.lookupID(ctx, "blocks", "division_id = ? AND LOWER(block_code) = LOWER(?)", divisionID, code)
+	} else {
+		existingID, err = h.svc.lookupID(ctx, "blocks", "division_id = ? AND LOWER(TRIM(name)) = LOWER(?)", divisionID, name)
+	}
+	if err != nil {
+		return err
+	}
+
+	if existingID != "" {
+		req := &models.UpdateBlockRequest{
+			ID:           existingID,
+			LuasHa:       luasHa,
+			PlantingYear: plantingYear,
+			LandTypeID:   landTypeID,
+			TarifBlokID:  tarifBlokID,
+		}
+		if name != "" {
+			req.Name = &name
+		}
+		if cropType != "" {
+			req.CropType = &cropType
+		}
+		if status != "" {
+			req.Status = &status
+		}
+		if istm != "" {
+			req.ISTM = &istm
+		}
+		if _, err := h.svc.master.UpdateBlock(ctx, req, h.requesterID); err != nil {
+			return err
+		}
+		counts.updated++
+		return nil
+	}
+
+	if name == "" {
+		return models.NewMasterDataError(models.ErrCodeInvalidInput, "block_name is required for a new block", hierarchyColumnBlockName)
+	}
+	if _, err := h.svc.master.CreateBlock(ctx, &models.CreateBlockRequest{
+		BlockCode:    code,
+		Name:         name,
+		LuasHa:       luasHa,
+		CropType:     cropType,
+		PlantingYear: plantingYear,
+		Status:       status,
+		ISTM:         istm,
+		LandTypeID:   landTypeID,
+		TarifBlokID:  tarifBlokID,
+		DivisionID:   divisionID,
+	}, h.requesterID); err != nil {
+		return err
+	}
+	counts.created++
+	return nil
+}
+
+// resolveLandType maps a land type code or name to its ID.
+func (h *hierarchyRowImporter) resolveLandType(ctx context.Context, value string) (*string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	key := strings.ToLower(value)
+	if id, ok := h.landTypes[key]; ok {
+		return &id, nil
+	}
+	id, err := h.svc.lookupID(ctx, "land_types", "LOWER(code) = LOWER(?) OR LOWER(name) = LOWER(?)", value, value)
+	if err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return nil, models.NewMasterDataError(models.ErrCodeNotFound, fmt.Sprintf("land type %q not found", value), hierarchyColumnLandType)
+	}
+	h.landTypes[key] = id
+	return &id, nil
+}
+
+// resolveTarifBlok maps a tarif code or perlakuan of the company to its ID.
+func (h *hierarchyRowImporter) resolveTarifBlok(ctx context.Context, value string) (*string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	key := strings.ToLower(value)
+	if id, ok := h.tarifBloks[key]; ok {
+		return &id, nil
+	}
+	id, err := h.svc.lookupID(
+		ctx,
+		"tarif_blok",
+		"company_id = ? AND (LOWER(tarif_code) = LOWER(?) OR LOWER(perlakuan) = LOWER(?))",
+		h.companyID, value, value,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return nil, models.NewMasterDataError(models.ErrCodeNotFound, fmt.Sprintf("tarif blok %q not found", value), hierarchyColumnTarifBlok)
+	}
+	h.tarifBloks[key] = id
+	return &id, nil
+}
+
+// employeeRowImporter upserts employees by NIK within the company.
+type employeeRowImporter struct {
+	svc       *MasterDataImportService
+	companyID string
+	divisions map[string]string
+}
+
+func newEmployeeRowImporter(svc *MasterDataImportService, companyID string) *employeeRowImporter {
+	return &employeeRowImporter{
+		svc:       svc,
+		companyID: companyID,
+		divisions: make(map[string]string),
+	}
+}
+
+func (e *employeeRowImporter) importRow(ctx context.Context, row spreadsheet.Row, counts *masterDataRowCounts) *models.MasterDataImportRowError {
+	nik := row.Get(employeeColumnNIK)
+	name := row.Get(employeeColumnName)
+	role := strings.ToUpper(row.Get(employeeColumnRole))
+	for field, value := range map[string]string{employeeColumnNIK: nik, employeeColumnName: name, employeeColumnRole: role} {
+		if value == "" {
+			return &models.MasterDataImportRowError{Entity: "EMPLOYEE", Field: field, Message: field + " is required"}
+		}
+	}
+
+	isActive := true
+	if value := row.Get(employeeColumnIsActive); value != "" {
+		parsed, err := parseImportBool(value)
+		if err != nil {
+			return &models.MasterDataImportRowError{Entity: "EMPLOYEE", Field: employeeColumnIsActive, Message: err.Error()}
+		}
+		isActive = parsed
+	}
+
+	divisionID, err := e.resolveDivision(ctx, row.Get(employeeColumnEstateCode), row.Get(employeeColumnDivisionCode))
+	if err != nil {
+		return masterDataRowError("EMPLOYEE", err)
+	}
+
+	var photoURL *string
+	if value := row.Get(employeeColumnPhotoURL); value != "" {
+		photoURL = &value
+	}
+
+	existingID, err := e.svc.lookupID(ctx, "employees", "company_id = ? AND nik = ?", e.companyID, nik)
+	if err != nil {
+		return masterDataRowError("EMPLOYEE", err)
+	}
+
+	if existingID != "" {
+		if _, err := e.svc.employees.UpdateEmployee(ctx, master.UpdateEmployeeInput{
+			ID:         existingID,
+			Name:       &name,
+			Role:       &role,
+			DivisionID: divisionID,
+			PhotoURL:   photoURL,
+			IsActive:   &isActive,
+		}); err != nil {
+			return masterDataRowError("EMPLOYEE", err)
+		}
+		counts.updated++
+		return nil
+	}
+
+	employee, err := e.svc.employees.CreateEmployee(ctx, master.CreateEmployeeInput{
+		Nik:        nik,
+		Name:       name,
+		Role:       role,
+		CompanyID:  e.companyID,
+		DivisionID: divisionID,
+		PhotoURL:   photoURL,
+	})
+	if err != nil {
+		return masterDataRowError("EMPLOYEE", err)
+	}
+	if !isActive {
+		if _, err := e.svc.employees.UpdateEmployee(ctx, master.UpdateEmployeeInput{ID: employee.ID, IsActive: &isActive}); err != nil {
+			return masterDataRowError("EMPLOYEE", err)
+		}
+	}
+	counts.created++
+	return nil
+}
+
+// resolveDivision finds a division of the company by code, narrowed by estate
+// code when given. Division codes are only unique within an estate, so an
+// ambiguous code without an estate is rejected.
+func (e *employeeRowImporter) resolveDivision(ctx context.Context, estateCode, divisionCode string) (*string, error) {
+	if divisionCode == "" {
+		if estateCode != "" {
+			return nil, models.NewMasterDataError(models.ErrCodeInvalidInput, "division_code is required when estate_code is set", employeeColumnDivisionCode)
+		}
+		return nil, nil
+	}
+	key := strings.ToLower(estateCode + "|" + divisionCode)
+	if id, ok := e.divisions[key]; ok {
+		return &id, nil
+	}
+
+	query := e.svc.db.WithContext(ctx).
+		Table("divisions d").
+		Joins("JOIN estates e ON e.id = d.estate_id").
+		Where("e.company_id = ? AND LOWER(d.code) = LOWER(?)", e.companyID, divisionCode)
+	if estateCode != "" {
+		query = query.Where("LOWER(e.code) = LOWER(?)", estateCode)
+	}
+	var ids []string
+	if err := query.Limit(2).Pluck("d.id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to resolve division: %w", err)
+	}
+	switch len(ids) {
+	case 0:
+		return nil, models.NewMasterDataError(models.ErrCodeNotFound, fmt.Sprintf("division %q not found", divisionCode), employeeColumnDivisionCode)
+	case 1:
+		e.divisions[key] = ids[0]
+		return &ids[0], nil
+	default:
+		return nil, models.NewMasterDataError(models.ErrCodeInvalidInput, fmt.Sprintf("division %q exists in several estates; set estate_code", divisionCode), employeeColumnEstateCode)
+	}
+}
+
+// lookupID returns the id of the first row of table matching where, or "".
+func (s *MasterDataImportService) lookupID(ctx context.Context, table string, where string, args ...interface{}) (string, error) {
+	var ids []string
+	if err := s.db.WithContext(ctx).Table(table).Where(where, args...).Limit(1).Pluck("id", &ids).Error; err != nil {
+		return "", fmt.Errorf("failed to query %s: %w", table, err)
+	}
+	if len(ids) == 0 {
+		return "", nil
+	}
+	return ids[0], nil
+}
+
+func masterDataRowError(entity string, err error) *models.MasterDataImportRowError {
+	rowErr := &models.MasterDataImportRowError{Entity: entity, Message: err.Error()}
+	var masterErr *models.MasterDataError
+	if errors.As(err, &masterErr) {
+		rowErr.Field = masterErr.Field
+		rowErr.Message = masterErr.Message
+	}
+	return rowErr
+}
+
+func parseImportFloat(value, field string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", "."), 64)
+	if err != nil {
+		return nil, models.NewMasterDataError(models.ErrCodeInvalidInput, fmt.Sprintf("%s must be a number", field), field)
+	}
+	return &parsed, nil
+}
+
+func parseImportInt(value, field string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, models.NewMasterDataError(models.ErrCodeInvalidInput, fmt.Sprintf("%s must be a whole number", field), field)
+	}
+	return &parsed, nil
+}
+
+func parseImportBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "y", "1", "ya", "aktif", "active":
+		return true, nil
+	case "false", "no", "n", "0", "tidak", "nonaktif", "inactive":
+		return false, nil
+	default:
+		return false, fmt.Errorf("%q is not a valid boolean", value)
+	}
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func formatOptionalFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
+
+func formatOptionalInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
@@ -0,0 +1,287 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"agrinovagraphql/server/internal/master/models"
+	"agrinovagraphql/server/internal/master/repositories"
+	"agrinovagraphql/server/pkg/spreadsheet"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestNormalizeMasterDataImportType(t *testing.T) {
+	importType, ok := NormalizeMasterDataImportType(" hierarchy ")
+	assert.True(t, ok)
+	assert.Equal(t, models.MasterDataImportHierarchy, importType)
+
+	_, ok = NormalizeMasterDataImportType("vehicle")
+	assert.False(t, ok)
+}
+
+func TestParseImportValues(t *testing.T) {
+	luas, err := parseImportFloat("12,5", hierarchyColumnLuasHa)
+	require.NoError(t, err)
+	require.NotNil(t, luas)
+	assert.Equal(t, 12.5, *luas)
+
+	empty, err := parseImportFloat("", hierarchyColumnLuasHa)
+	assert.NoError(t, err)
+	assert.Nil(t, empty)
+
+	_, err = parseImportInt("2019.5", hierarchyColumnPlantingYear)
+	var masterErr *models.MasterDataError
+	require.ErrorAs(t, err, &masterErr)
+	assert.Equal(t, hierarchyColumnPlantingYear, masterErr.Field)
+
+	active, err := parseImportBool("Tidak")
+	assert.NoError(t, err)
+	assert.False(t, active)
+
+	_, err = parseImportBool("maybe")
+	assert.Error(t, err)
+}
+
+func TestMasterDataRowError(t *testing.T) {
+	rowErr := masterDataRowError("BLOCK", models.NewMasterDataError(models.ErrCodeNotFound, "land type \"X\" not found", hierarchyColumnLandType))
+	assert.Equal(t, "BLOCK", rowErr.Entity)
+	assert.Equal(t, hierarchyColumnLandType, rowErr.Field)
+	assert.Equal(t, "land type \"X\" not found", rowErr.Message)
+}
+
+// openMasterImportTestDB opens a named shared-cache database so the tenant
+// plan checks that run on a second connection inside CreateEstate's
+// transaction see the same tables.
+func openMasterImportTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	const uuidDefault = `DEFAULT (lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-' || hex(randomblob(2)) || '-' || hex(randomblob(2)) || '-' || hex(randomblob(6))))`
+	for _, stmt := range []string{
+		`CREATE TABLE companies (id TEXT PRIMARY KEY, name TEXT, company_code TEXT, description TEXT, logo_url TEXT, address TEXT, phone TEXT, status TEXT DEFAULT 'ACTIVE', is_active BOOLEAN DEFAULT true, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE users (id TEXT PRIMARY KEY, role TEXT, is_active BOOLEAN DEFAULT true, deleted_at DATETIME)`,
+		`CREATE TABLE user_company_assignments (id TEXT PRIMARY KEY ` + uuidDefault + `, user_id TEXT, company_id TEXT, mandor_type TEXT, is_active BOOLEAN DEFAULT true, assigned_at DATETIME, assigned_by TEXT, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE estates (id TEXT PRIMARY KEY ` + uuidDefault + `, name TEXT, code TEXT, location TEXT, area_ha REAL, company_id TEXT, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE divisions (id TEXT PRIMARY KEY ` + uuidDefault + `, name TEXT, code TEXT, estate_id TEXT, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE land_types (id TEXT PRIMARY KEY, code TEXT, name TEXT, description TEXT, is_active BOOLEAN DEFAULT true, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE tarif_blok (id TEXT PRIMARY KEY, company_id TEXT, perlakuan TEXT, land_type_id TEXT, tarif_code TEXT, is_active BOOLEAN DEFAULT true, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE blocks (id TEXT PRIMARY KEY ` + uuidDefault + `, block_code TEXT, name TEXT, area_ha REAL, crop_type TEXT, planting_year INTEGER, status TEXT DEFAULT 'INTI', istm TEXT DEFAULT 'N', perlakuan TEXT, land_type_id TEXT, tarif_blok_id TEXT, division_id TEXT, is_active BOOLEAN DEFAULT true, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE master_data_import_jobs (id TEXT PRIMARY KEY ` + uuidDefault + `, company_id TEXT, import_type TEXT, status TEXT, file_name TEXT, total_rows INTEGER, processed_rows INTEGER, created_count INTEGER, updated_count INTEGER, failed_rows INTEGER, errors_json TEXT, message TEXT, created_by TEXT, started_at DATETIME, finished_at DATETIME, created_at DATETIME, updated_at DATETIME)`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+	return db
+}
+
+// setupHierarchyImport seeds a company with a COMPANY_ADMIN and returns an
+// import service backed by the real master repository.
+func setupHierarchyImport(t *testing.T) (svc *MasterDataImportService, db *gorm.DB, companyID, adminID string) {
+	t.Helper()
+
+	db = openMasterImportTestDB(t)
+	companyID = uuid.NewString()
+	adminID = uuid.NewString()
+	now := time.Now()
+	require.NoError(t, db.Exec(`INSERT INTO companies (id, name, created_at, updated_at) VALUES (?, ?, ?, ?)`, companyID, companyID, now, now).Error)
+	require.NoError(t, db.Exec(`INSERT INTO users (id, role) VALUES (?, 'COMPANY_ADMIN')`, adminID).Error)
+	require.NoError(t, db.Exec(`INSERT INTO user_company_assignments (user_id, company_id) VALUES (?, ?)`, adminID, companyID).Error)
+
+	return NewMasterDataImportService(repositories.NewMasterRepository(db), db, nil), db, companyID, adminID
+}
+
+// runHierarchyImport processes rows synchronously and returns the job as it
+// was persisted.
+func runHierarchyImport(t *testing.T, svc *MasterDataImportService, companyID, requesterID string, rows ...map[string]string) *models.MasterDataImportJob {
+	t.Helper()
+
+	table := &spreadsheet.Table{Header: hierarchyImportColumns}
+	for i, values := range rows {
+		table.Rows = append(table.Rows, spreadsheet.Row{Number: i + 2, Values: values})
+	}
+	job := &models.MasterDataImportJob{
+		CompanyID:  companyID,
+		ImportType: models.MasterDataImportHierarchy,
+		Status:     models.MasterDataImportPending,
+		TotalRows:  len(table.Rows),
+		CreatedBy:  requesterID,
+	}
+	job.SetErrors(nil)
+	require.NoError(t, svc.db.Create(job).Error)
+
+	svc.run(context.Background(), job, requesterID, table)
+
+	var stored models.MasterDataImportJob
+	require.NoError(t, svc.db.Where("id = ?", job.ID).First(&stored).Error)
+	return &stored
+}
+
+func countRows(t *testing.T, db *gorm.DB, table string) int64 {
+	t.Helper()
+	var count int64
+	require.NoError(t, db.Table(table).Count(&count).Error)
+	return count
+}
+
+func TestHierarchyImport_WritesParentsOncePerJob(t *testing.T) {
+	svc, db, companyID, adminID := setupHierarchyImport(t)
+
+	block := func(code, name string) map[string]string {
+		return map[string]string{
+			hierarchyColumnEstateCode:   "EST1",
+			hierarchyColumnEstateName:   "Estate Satu",
+			hierarchyColumnDivisionCode: "AFD1",
+			hierarchyColumnDivisionName: "Afdeling Satu",
+			hierarchyColumnBlockCode:    code,
+			hierarchyColumnBlockName:    name,
+		}
+	}
+	job := runHierarchyImport(t, svc, companyID, adminID,
+		block("AFD1001", "Blok A"),
+		block("", "Blok B"),
+		block("AFD1005", "Blok C"),
+	)
+
+	assert.Equal(t, models.MasterDataImportCompleted, job.Status)
+	assert.Equal(t, 3, job.ProcessedRows)
+	assert.Equal(t, 5, job.CreatedCount)
+	assert.Equal(t, 0, job.UpdatedCount)
+	assert.Equal(t, 0, job.FailedRows)
+	assert.Equal(t, int64(1), countRows(t, db, "estates"))
+	assert.Equal(t, int64(1), countRows(t, db, "divisions"))
+
+	var codes []string
+	require.NoError(t, db.Table("blocks").Order("name").Pluck("block_code", &codes).Error)
+	assert.Equal(t, []string{"AFD1001", "AFD1002", "AFD1005"}, codes)
+}
+
+func TestHierarchyImport_UpsertsByCode(t *testing.T) {
+	svc, db, companyID, adminID := setupHierarchyImport(t)
+
+	estateID, divisionID, blockAID, blockBID := uuid.NewString(), uuid.NewString(), uuid.NewString(), uuid.NewString()
+	now := time.Now()
+	require.NoError(t, db.Exec(`INSERT INTO estates (id, name, code, company_id, created_at, updated_at) VALUES (?, 'Old Estate', 'EST1', ?, ?, ?)`, estateID, companyID, now, now).Error)
+	require.NoError(t, db.Exec(`INSERT INTO divisions (id, name, code, estate_id, created_at, updated_at) VALUES (?, 'Old Division', 'AFD1', ?, ?, ?)`, divisionID, estateID, now, now).Error)
+	require.NoError(t, db.Exec(`INSERT INTO blocks (id, block_code, name, area_ha, division_id, created_at, updated_at) VALUES (?, 'AFD1001', 'Blok A', 10, ?, ?, ?)`, blockAID, divisionID, now, now).Error)
+	require.NoError(t, db.Exec(`INSERT INTO blocks (id, block_code, name, area_ha, division_id, created_at, updated_at) VALUES (?, 'AFD1002', 'Blok B', 10, ?, ?, ?)`, blockBID, divisionID, now, now).Error)
+
+	job := runHierarchyImport(t, svc, companyID, adminID,
+		map[string]string{
+			hierarchyColumnEstateCode:   "est1",
+			hierarchyColumnEstateName:   "New Estate",
+			hierarchyColumnDivisionCode: "afd1",
+			hierarchyColumnDivisionName: "New Division",
+			hierarchyColumnBlockCode:    "afd1001",
+			hierarchyColumnLuasHa:       "12,5",
+		},
+		map[string]string{
+			hierarchyColumnEstateCode:   "EST1",
+			hierarchyColumnDivisionCode: "AFD1",
+			hierarchyColumnBlockName:    "blok b",
+			hierarchyColumnLuasHa:       "7",
+		},
+	)
+
+	assert.Equal(t, 0, job.FailedRows, job.Errors())
+	assert.Equal(t, 0, job.CreatedCount)
+	assert.Equal(t, 4, job.UpdatedCount)
+	assert.Equal(t, int64(1), countRows(t, db, "estates"))
+	assert.Equal(t, int64(1), countRows(t, db, "divisions"))
+	assert.Equal(t, int64(2), countRows(t, db, "blocks"))
+
+	var estateName, divisionName string
+	require.NoError(t, db.Table("estates").Where("id = ?", estateID).Pluck("name", &estateName).Error)
+	require.NoError(t, db.Table("divisions").Where("id = ?", divisionID).Pluck("name", &divisionName).Error)
+	assert.Equal(t, "New Estate", estateName)
+	assert.Equal(t, "New Division", divisionName)
+
+	var areas []float64
+	require.NoError(t, db.Table("blocks").Order("block_code").Pluck("area_ha", &areas).Error)
+	assert.Equal(t, []float64{12.5, 7}, areas)
+}
+
+func TestHierarchyImport_ResolvesTarifWithinCompany(t *testing.T) {
+	svc, db, companyID, adminID := setupHierarchyImport(t)
+
+	landTypeID, ownTarifID := uuid.NewString(), uuid.NewString()
+	now := time.Now()
+	require.NoError(t, db.Exec(`INSERT INTO land_types (id, code, name, created_at, updated_at) VALUES (?, 'MIN', 'Mineral', ?, ?)`, landTypeID, now, now).Error)
+	require.NoError(t, db.Exec(`INSERT INTO tarif_blok (id, company_id, perlakuan, land_type_id, tarif_code, created_at, updated_at) VALUES (?, ?, 'Normal', ?, 'T1', ?, ?)`, ownTarifID, companyID, landTypeID, now, now).Error)
+	require.NoError(t, db.Exec(`INSERT INTO tarif_blok (id, company_id, perlakuan, land_type_id, tarif_code, created_at, updated_at) VALUES (?, ?, 'Khusus', ?, 'T2', ?, ?)`, uuid.NewString(), uuid.NewString(), landTypeID, now, now).Error)
+
+	block := func(code, name, landType, tarif string) map[string]string {
+		return map[string]string{
+			hierarchyColumnEstateCode:   "EST1",
+			hierarchyColumnEstateName:   "Estate Satu",
+			hierarchyColumnDivisionCode: "AFD1",
+			hierarchyColumnDivisionName: "Afdeling Satu",
+			hierarchyColumnBlockCode:    code,
+			hierarchyColumnBlockName:    name,
+			hierarchyColumnLandType:     landType,
+			hierarchyColumnTarifBlok:    tarif,
+		}
+	}
+	job := runHierarchyImport(t, svc, companyID, adminID,
+		block("AFD1001", "Blok A", "mineral", "t1"),
+		block("AFD1002", "Blok B", "MIN", "Khusus"),
+		block("AFD1003", "Blok C", "Gambut", ""),
+	)
+
+	assert.Equal(t, 3, job.ProcessedRows)
+	assert.Equal(t, 3, job.CreatedCount)
+	assert.Equal(t, 2, job.FailedRows)
+	rowErrors := job.Errors()
+	require.Len(t, rowErrors, 2)
+	assert.Equal(t, models.MasterDataImportRowError{Row: 3, Entity: "BLOCK", Field: hierarchyColumnTarifBlok, Message: `tarif blok "Khusus" not found`}, rowErrors[0])
+	assert.Equal(t, models.MasterDataImportRowError{Row: 4, Entity: "BLOCK", Field: hierarchyColumnLandType, Message: `land type "Gambut" not found`}, rowErrors[1])
+
+	var blocks []struct {
+		BlockCode   string
+		LandTypeID  *string
+		TarifBlokID *string
+		Perlakuan   *string
+	}
+	require.NoError(t, db.Table("blocks").Find(&blocks).Error)
+	require.Len(t, blocks, 1)
+	assert.Equal(t, "AFD1001", blocks[0].BlockCode)
+	assert.Equal(t, landTypeID, stringValue(blocks[0].LandTypeID))
+	assert.Equal(t, ownTarifID, stringValue(blocks[0].TarifBlokID))
+	assert.Equal(t, "Normal", stringValue(blocks[0].Perlakuan))
+}
+
+func TestHierarchyImport_CountsFailedRows(t *testing.T) {
+	svc, db, companyID, adminID := setupHierarchyImport(t)
+
+	job := runHierarchyImport(t, svc, companyID, adminID,
+		map[string]string{hierarchyColumnEstateCode: "EST1"},
+		map[string]string{hierarchyColumnEstateCode: "EST2", hierarchyColumnEstateName: "Estate Dua"},
+		map[string]string{hierarchyColumnEstateCode: "EST2", hierarchyColumnBlockName: "Blok Tanpa Divisi"},
+		map[string]string{hierarchyColumnEstateCode: "EST2", hierarchyColumnDivisionCode: "AFD1", hierarchyColumnDivisionName: "Afdeling", hierarchyColumnBlockName: "Blok A", hierarchyColumnLuasHa: "luas"},
+	)
+
+	assert.Equal(t, models.MasterDataImportCompleted, job.Status)
+	assert.Equal(t, 4, job.ProcessedRows)
+	assert.Equal(t, 3, job.FailedRows)
+	assert.Equal(t, 2, job.CreatedCount)
+	require.NotNil(t, job.Message)
+	assert.Equal(t, "4 row(s) processed: 2 created, 0 updated, 3 failed", *job.Message)
+
+	rowErrors := job.Errors()
+	require.Len(t, rowErrors, 3)
+	assert.Equal(t, models.MasterDataImportRowError{Row: 2, Entity: "ESTATE", Field: hierarchyColumnEstateName, Message: "estate_name is required for a new estate"}, rowErrors[0])
+	assert.Equal(t, models.MasterDataImportRowError{Row: 4, Entity: "BLOCK", Field: hierarchyColumnDivisionCode, Message: "division_code is required for block rows"}, rowErrors[1])
+	assert.Equal(t, models.MasterDataImportRowError{Row: 5, Entity: "BLOCK", Field: hierarchyColumnLuasHa, Message: "luas_ha must be a number"}, rowErrors[2])
+	assert.Equal(t, int64(0), countRows(t, db, "blocks"))
+}
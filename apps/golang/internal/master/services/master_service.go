@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
@@ -14,6 +15,7 @@ import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	companyServices "agrinovagraphql/server/internal/company/services"
 	"agrinovagraphql/server/internal/graphql/domain/auth"
 	"agrinovagraphql/server/internal/graphql/domain/master"
 	"agrinovagraphql/server/internal/master/models"
@@ -89,17 +91,19 @@ type MasterService interface {
 
 // masterService implements MasterService interface
 type masterService struct {
-	repo      repositories.MasterRepository
-	validator *validator.Validate
-	db        *gorm.DB
+	repo        repositories.MasterRepository
+	validator   *validator.Validate
+	db          *gorm.DB
+	tenantPlans *companyServices.TenantPlanService
 }
 
 // NewMasterService creates a new master service
 func NewMasterService(repo repositories.MasterRepository, db *gorm.DB) MasterService {
 	return &masterService{
-		repo:      repo,
-		validator: validator.New(),
-		db:        db,
+		repo:        repo,
+		validator:   validator.New(),
+		db:          db,
+		tenantPlans: companyServices.NewTenantPlanService(db),
 	}
 }
 
@@ -1160,8 +1164,33 @@ func (s *masterService) CreateEstate(ctx context.Context, req *models.CreateEsta
 		UpdatedAt: time.Now(),
 	}
 
-	if err := s.repo.CreateEstate(ctx, estate); err != nil {
-		return nil, fmt.Errorf("failed to create estate: %w", err)
+	if s.tenantPlans == nil {
+		if err := s.repo.CreateEstate(ctx, estate); err != nil {
+			return nil, fmt.Errorf("failed to create estate: %w", err)
+		}
+		return s.repo.GetEstateByID(ctx, estate.ID)
+	}
+
+	// Enforce the tenant plan estate limit in the creating transaction so
+	// concurrent creations cannot both pass it.
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.tenantPlans.CheckEstateQuotaTx(tx, req.CompanyID, 1); err != nil {
+			var quotaErr *companyServices.QuotaExceededError
+			if errors.As(err, &quotaErr) {
+				return models.NewMasterDataError(models.ErrCodeQuotaExceeded, err.Error(), "companyId")
+			}
+			if errors.Is(err, companyServices.ErrCompanySuspended) || errors.Is(err, companyServices.ErrTrialExpired) {
+				return models.NewMasterDataError(models.ErrCodeInactiveEntity, err.Error(), "companyId")
+			}
+			return err
+		}
+		if err := s.repo.WithTransaction(tx).CreateEstate(ctx, estate); err != nil {
+			return fmt.Errorf("failed to create estate: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return s.repo.GetEstateByID(ctx, estate.ID)
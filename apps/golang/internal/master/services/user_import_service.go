@@ -0,0 +1,805 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+
+	authServices "agrinovagraphql/server/internal/auth/services"
+	companyServices "agrinovagraphql/server/internal/company/services"
+	"agrinovagraphql/server/internal/graphql/domain/auth"
+	"agrinovagraphql/server/internal/master/models"
+	"agrinovagraphql/server/internal/master/repositories"
+	"agrinovagraphql/server/pkg/spreadsheet"
+
+	"gorm.io/gorm"
+)
+
+const (
+	minImportPasswordLength = 8
+	importPasswordLength    = 12
+
+	// importPasswordAlphabet omits look-alike characters (0/O, 1/l/I).
+	importPasswordAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnpqrstuvwxyz23456789"
+)
+
+// User import spreadsheet columns. Estate and division cells accept several
+// codes, names or IDs separated by commas or semicolons.
+const (
+	userImportColumnUsername   = "username"
+	userImportColumnName       = "name"
+	userImportColumnEmail      = "email"
+	userImportColumnPhone      = "phone"
+	userImportColumnRole       = "role"
+	userImportColumnNIK        = "nik"
+	userImportColumnPassword   = "password"
+	userImportColumnMandorType = "mandor_type"
+	userImportColumnCompany    = "company"
+	userImportColumnEstate     = "estate"
+	userImportColumnDivision   = "division"
+)
+
+var userImportRequiredColumns = []string{userImportColumnUsername, userImportColumnRole}
+
+var userImportRequiresEstate = map[auth.UserRole]bool{
+	auth.UserRoleManager: true,
+	auth.UserRoleAsisten: true,
+	auth.UserRoleMandor:  true,
+}
+
+var userImportRequiresDivision = map[auth.UserRole]bool{
+	auth.UserRoleAsisten: true,
+	auth.UserRoleMandor:  true,
+}
+
+// PasswordHasher hashes the passwords of imported users.
+type PasswordHasher interface {
+	HashPassword(password string) (string, error)
+}
+
+// UserImportService validates and applies spreadsheet imports of users and
+// their company, estate and division assignments. Validation always runs in
+// full; a file is only applied when every row is valid, in one transaction.
+type UserImportService struct {
+	master    *masterService
+	db        *gorm.DB
+	roles     *authServices.RoleHierarchyService
+	passwords PasswordHasher
+}
+
+// NewUserImportService creates a new user import service
+func NewUserImportService(repo repositories.MasterRepository, db *gorm.DB, passwords PasswordHasher) *UserImportService {
+	return &UserImportService{
+		master:    NewMasterService(repo, db).(*masterService),
+		db:        db,
+		roles:     authServices.NewRoleHierarchyService(),
+		passwords: passwords,
+	}
+}
+
+// userImportPlan carries the fields of a row that are not reported back.
+type userImportPlan struct {
+	row        *models.UserImportRow
+	email      *string
+	phone      *string
+	password   string
+	mandorType *string
+}
+
+type importUnit struct {
+	id       string
+	code     string
+	name     string
+	parentID string
+}
+
+type importExistingUser struct {
+	ID       string
+	Username string
+	Role     auth.UserRole
+	IsActive bool
+}
+
+// userImportState holds lookups and in-file uniqueness tracking for one run.
+type userImportState struct {
+	requesterRole    auth.UserRole
+	defaultCompanyID string
+
+	companies map[string]string
+	estates   map[string][]importUnit
+	divisions map[string][]importUnit
+
+	usernames       map[string]int
+	niks            map[string]int
+	managerEstates  map[string]int
+	areaManagers    map[string]int
+	newUsersCompany map[string]int
+}
+
+// Import validates table and, unless dryRun is set or a row is invalid,
+// creates the users and assignments it describes. Rows whose username already
+// exists only add assignments to that user.
+func (s *UserImportService) Import(
+	ctx context.Context,
+	requesterID string,
+	defaultCompanyID string,
+	table *spreadsheet.Table,
+	dryRun bool,
+) (*models.UserImportResult, error) {
+	requesterRole, err := s.master.getUserRole(ctx, requesterID)
+	if err != nil {
+		return nil, err
+	}
+	if requesterRole != auth.UserRoleSuperAdmin && requesterRole != auth.UserRoleCompanyAdmin {
+		return nil, models.NewMasterDataError(models.ErrCodePermissionDenied, "only SUPER_ADMIN and COMPANY_ADMIN can import users", "")
+	}
+
+	result := &models.UserImportResult{
+		DryRun:    dryRun,
+		TotalRows: len(table.Rows),
+		Rows:      []*models.UserImportRow{},
+		Errors:    []models.UserImportRowError{},
+	}
+
+	for _, column := range userImportRequiredColumns {
+		if !table.HasColumn(column) {
+			result.AddError(0, column, fmt.Sprintf("missing required column %q", column))
+		}
+	}
+	if len(table.Rows) == 0 {
+		result.AddError(0, "", "file has no data rows")
+	}
+	if len(table.Rows) > models.MaxUserImportRows {
+		result.AddError(0, "", fmt.Sprintf("file has %d rows; the limit is %d", len(table.Rows), models.MaxUserImportRows))
+	}
+	if len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	state := &userImportState{
+		requesterRole:    requesterRole,
+		defaultCompanyID: strings.TrimSpace(defaultCompanyID),
+		companies:        make(map[string]string),
+		estates:          make(map[string][]importUnit),
+		divisions:        make(map[string][]importUnit),
+		usernames:        make(map[string]int),
+		niks:             make(map[string]int),
+		managerEstates:   make(map[string]int),
+		areaManagers:     make(map[string]int),
+		newUsersCompany:  make(map[string]int),
+	}
+
+	plans := make([]*userImportPlan, 0, len(table.Rows))
+	for _, row := range table.Rows {
+		errorCount := len(result.Errors)
+		plan, err := s.validateRow(ctx, requesterID, state, row, result)
+		if err != nil {
+			return nil, err
+		}
+		if plan == nil || len(result.Errors) > errorCount {
+			continue
+		}
+		plans = append(plans, plan)
+		result.Rows = append(result.Rows, plan.row)
+	}
+	result.ValidRows = len(plans)
+
+	if err := s.checkUserQuotas(ctx, state, result); err != nil {
+		return nil, err
+	}
+
+	if dryRun || len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	if err := s.apply(ctx, requesterID, plans, state.newUsersCompany); err != nil {
+		if isUserQuotaError(err) {
+			result.AddError(0, userImportColumnCompany, err.Error())
+			return result, nil
+		}
+		return nil, err
+	}
+
+	result.Applied = true
+	for _, plan := range plans {
+		if plan.row.Action == models.UserImportActionCreate {
+			result.Created++
+		} else {
+			result.Assigned++
+		}
+	}
+	return result, nil
+}
+
+func (s *UserImportService) validateRow(
+	ctx context.Context,
+	requesterID string,
+	state *userImportState,
+	row spreadsheet.Row,
+	result *models.UserImportResult,
+) (*userImportPlan, error) {
+	line := row.Number
+	plan := &userImportPlan{
+		row: &models.UserImportRow{
+			Row:         line,
+			Username:    row.Get(userImportColumnUsername),
+			Name:        row.Get(userImportColumnName, "full_name"),
+			Role:        strings.ToUpper(row.Get(userImportColumnRole)),
+			NIK:         row.Get(userImportColumnNIK),
+			EstateIDs:   []string{},
+			DivisionIDs: []string{},
+		},
+		password: row.Get(userImportColumnPassword),
+	}
+	if email := row.Get(userImportColumnEmail); email != "" {
+		if _, err := mail.ParseAddress(email); err != nil {
+			result.AddError(line, userImportColumnEmail, "invalid email format")
+		}
+		plan.email = &email
+	}
+	if phone := row.Get(userImportColumnPhone); phone != "" {
+		plan.phone = &phone
+	}
+
+	username := plan.row.Username
+	if username == "" {
+		result.AddError(line, userImportColumnUsername, "username is required")
+		return nil, nil
+	}
+	usernameKey := strings.ToLower(username)
+	if previous, seen := state.usernames[usernameKey]; seen {
+		result.AddError(line, userImportColumnUsername, fmt.Sprintf("username %s duplicates row %d", username, previous))
+		return nil, nil
+	}
+	state.usernames[usernameKey] = line
+
+	existing, err := s.findUserByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	role := auth.UserRole(plan.row.Role)
+	if existing != nil {
+		plan.row.Action = models.UserImportActionAssign
+		plan.row.UserID = existing.ID
+		plan.row.Username = existing.Username
+		if role == "" {
+			role = existing.Role
+		} else if role != existing.Role {
+			result.AddError(line, userImportColumnRole, fmt.Sprintf("user %s already exists with role %s", existing.Username, existing.Role))
+			return nil, nil
+		}
+		if !existing.IsActive {
+			result.AddError(line, userImportColumnUsername, fmt.Sprintf("user %s is inactive", existing.Username))
+			return nil, nil
+		}
+		if !s.roles.CanManage(state.requesterRole, existing.Role) {
+			result.AddError(line, userImportColumnUsername, fmt.Sprintf("%s cannot manage %s users", state.requesterRole, existing.Role))
+			return nil, nil
+		}
+	} else {
+		plan.row.Action = models.UserImportActionCreate
+		if plan.row.Name == "" {
+			result.AddError(line, userImportColumnName, "name is required")
+		}
+		if plan.password != "" && len(plan.password) < minImportPasswordLength {
+			result.AddError(line, userImportColumnPassword, fmt.Sprintf("password must be at least %d characters", minImportPasswordLength))
+		}
+	}
+	plan.row.Role = string(role)
+
+	if !role.IsValid() {
+		result.AddError(line, userImportColumnRole, fmt.Sprintf("invalid role %q", plan.row.Role))
+		return nil, nil
+	}
+	if err := s.roles.ValidateRoleAssignment(state.requesterRole, role); err != nil {
+		result.AddError(line, userImportColumnRole, err.Error())
+		return nil, nil
+	}
+
+	if role == auth.UserRoleMandor && plan.row.Action == models.UserImportActionCreate {
+		mandorType := strings.ToUpper(row.Get(userImportColumnMandorType))
+		if mandorType != "PANEN" && mandorType != "PERAWATAN" {
+			result.AddError(line, userImportColumnMandorType, "MANDOR requires subtype PANEN or PERAWATAN")
+		} else {
+			plan.mandorType = &mandorType
+		}
+	}
+
+	companyID, err := s.resolveCompany(ctx, requesterID, state, row, result)
+	if err != nil || companyID == "" {
+		return nil, err
+	}
+	plan.row.CompanyID = companyID
+
+	if existing != nil {
+		inCompany, err := s.userInCompany(ctx, existing.ID, companyID)
+		if err != nil {
+			return nil, err
+		}
+		if !inCompany {
+			result.AddError(line, userImportColumnUsername, fmt.Sprintf("user %s is not assigned to this company", existing.Username))
+			return nil, nil
+		}
+	}
+
+	if err := s.resolveUnits(ctx, state, plan, row, result); err != nil {
+		return nil, err
+	}
+
+	if plan.row.Action == models.UserImportActionCreate {
+		if userImportRequiresEstate[role] && len(plan.row.EstateIDs) == 0 {
+			result.AddError(line, userImportColumnEstate, fmt.Sprintf("%s requires an estate", role))
+		}
+		if userImportRequiresDivision[role] && len(plan.row.DivisionIDs) == 0 {
+			result.AddError(line, userImportColumnDivision, fmt.Sprintf("%s requires a division", role))
+		}
+	} else if len(plan.row.EstateIDs) == 0 && len(plan.row.DivisionIDs) == 0 {
+		result.AddError(line, userImportColumnEstate, fmt.Sprintf("user %s already exists; fill estate or division for a new assignment", plan.row.Username))
+	}
+
+	if plan.row.NIK != "" {
+		nikKey := companyID + "|" + strings.ToLower(plan.row.NIK)
+		if previous, seen := state.niks[nikKey]; seen {
+			result.AddError(line, userImportColumnNIK, fmt.Sprintf("NIK %s duplicates row %d", plan.row.NIK, previous))
+		} else {
+			state.niks[nikKey] = line
+			taken, err := s.nikTaken(ctx, companyID, plan.row.NIK, plan.row.UserID)
+			if err != nil {
+				return nil, err
+			}
+			if taken {
+				result.AddError(line, userImportColumnNIK, fmt.Sprintf("NIK %s is already used by another user in this company", plan.row.NIK))
+			}
+		}
+	}
+
+	if err := s.checkUniqueAssignments(ctx, state, plan, role, result); err != nil {
+		return nil, err
+	}
+
+	if plan.row.Action == models.UserImportActionCreate {
+		state.newUsersCompany[companyID]++
+	}
+	return plan, nil
+}
+
+// checkUniqueAssignments enforces one active MANAGER per estate and one
+// active AREA_MANAGER per company, across the database and the file.
+func (s *UserImportService) checkUniqueAssignments(
+	ctx context.Context,
+	state *userImportState,
+	plan *userImportPlan,
+	role auth.UserRole,
+	result *models.UserImportResult,
+) error {
+	line := plan.row.Row
+	switch role {
+	case auth.UserRoleManager:
+		for _, estateID := range plan.row.EstateIDs {
+			if previous, seen := state.managerEstates[estateID]; seen {
+				result.AddError(line, userImportColumnEstate, fmt.Sprintf("MANAGER for this estate is already set in row %d", previous))
+				continue
+			}
+			state.managerEstates[estateID] = line
+			if err := s.master.ensureNoActiveEstateAssignmentConflictByRole(ctx, estateID, plan.row.UserID, auth.UserRoleManager); err != nil {
+				if !addMasterDataRowError(result, line, userImportColumnEstate, err) {
+					return err
+				}
+			}
+		}
+	case auth.UserRoleAreaManager:
+		if plan.row.Action != models.UserImportActionCreate {
+			return nil
+		}
+		companyID := plan.row.CompanyID
+		if previous, seen := state.areaManagers[companyID]; seen {
+			result.AddError(line, userImportColumnCompany, fmt.Sprintf("AREA_MANAGER for this company is already set in row %d", previous))
+			return nil
+		}
+		state.areaManagers[companyID] = line
+		if err := s.master.ensureNoActiveCompanyAssignmentConflictByRole(ctx, companyID, "", auth.UserRoleAreaManager); err != nil {
+			if !addMasterDataRowError(result, line, userImportColumnCompany, err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addMasterDataRowError reports business-rule errors against the row; other
+// errors are left for the caller to return.
+func addMasterDataRowError(result *models.UserImportResult, line int, field string, err error) bool {
+	var masterErr *models.MasterDataError
+	if !errors.As(err, &masterErr) {
+		return false
+	}
+	result.AddError(line, field, masterErr.Message)
+	return true
+}
+
+func (s *UserImportService) checkUserQuotas(ctx context.Context, state *userImportState, result *models.UserImportResult) error {
+	if s.master.tenantPlans == nil {
+		return nil
+	}
+	for companyID, count := range state.newUsersCompany {
+		if err := s.master.tenantPlans.CheckUserQuota(ctx, companyID, count); err != nil {
+			if isUserQuotaError(err) {
+				result.AddError(0, userImportColumnCompany, err.Error())
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// isUserQuotaError reports whether err is a plan limit or read-only company
+// error that belongs in the import result rather than failing the request.
+func isUserQuotaError(err error) bool {
+	var quotaErr *companyServices.QuotaExceededError
+	return errors.As(err, &quotaErr) ||
+		errors.Is(err, companyServices.ErrCompanySuspended) ||
+		errors.Is(err, companyServices.ErrTrialExpired)
+}
+
+func (s *UserImportService) resolveCompany(
+	ctx context.Context,
+	requesterID string,
+	state *userImportState,
+	row spreadsheet.Row,
+	result *models.UserImportResult,
+) (string, error) {
+	ref := row.Get(userImportColumnCompany, "company_code", "company_id")
+	if ref == "" {
+		if state.defaultCompanyID == "" || state.requesterRole == auth.UserRoleSuperAdmin {
+			result.AddError(row.Number, userImportColumnCompany, "company is required")
+			return "", nil
+		}
+		ref = state.defaultCompanyID
+	}
+
+	companyID, cached := state.companies[strings.ToLower(ref)]
+	if !cached {
+		var ids []string
+		if err := s.db.WithContext(ctx).
+			Table("companies").
+			Where("id::text = ? OR LOWER(company_code) = LOWER(?) OR LOWER(name) = LOWER(?)", ref, ref, ref).
+			Limit(2).
+			Pluck("id", &ids).Error; err != nil {
+			return "", fmt.Errorf("failed to resolve company %s: %w", ref, err)
+		}
+		if len(ids) == 1 {
+			companyID = ids[0]
+		}
+		state.companies[strings.ToLower(ref)] = companyID
+	}
+	if companyID == "" {
+		result.AddError(row.Number, userImportColumnCompany, fmt.Sprintf("company %s not found", ref))
+		return "", nil
+	}
+
+	if err := s.master.ValidateCompanyAccess(ctx, requesterID, companyID); err != nil {
+		if addMasterDataRowError(result, row.Number, userImportColumnCompany, err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return companyID, nil
+}
+
+// resolveUnits maps estate and division references to IDs within the row's
+// company. Divisions imply their estate.
+func (s *UserImportService) resolveUnits(
+	ctx context.Context,
+	state *userImportState,
+	plan *userImportPlan,
+	row spreadsheet.Row,
+	result *models.UserImportResult,
+) error {
+	companyID := plan.row.CompanyID
+	estates, err := s.companyEstates(ctx, state, companyID)
+	if err != nil {
+		return err
+	}
+	divisions, err := s.companyDivisions(ctx, state, companyID)
+	if err != nil {
+		return err
+	}
+
+	estateIDs := make([]string, 0)
+	for _, ref := range spreadsheet.SplitList(row.Get(userImportColumnEstate, "estate_code", "estate_id")) {
+		estate, ok := matchImportUnit(estates, ref, "")
+		if !ok {
+			result.AddError(row.Number, userImportColumnEstate, fmt.Sprintf("estate %s not found in this company", ref))
+			continue
+		}
+		estateIDs = appendUnique(estateIDs, estate.id)
+	}
+
+	divisionIDs := make([]string, 0)
+	for _, ref := range spreadsheet.SplitList(row.Get(userImportColumnDivision, "division_code", "division_id")) {
+		var (
+			division importUnit
+			ok       bool
+		)
+		// Division codes repeat across estates, so prefer the row's estates.
+		for _, estateID := range estateIDs {
+			if division, ok = matchImportUnit(divisions, ref, estateID); ok {
+				break
+			}
+		}
+		if !ok && len(estateIDs) == 0 {
+			division, ok = matchImportUnit(divisions, ref, "")
+		}
+		if !ok {
+			result.AddError(row.Number, userImportColumnDivision, fmt.Sprintf("division %s not found in the selected estate", ref))
+			continue
+		}
+		divisionIDs = appendUnique(divisionIDs, division.id)
+		estateIDs = appendUnique(estateIDs, division.parentID)
+	}
+
+	plan.row.EstateIDs = estateIDs
+	plan.row.DivisionIDs = divisionIDs
+	return nil
+}
+
+func (s *UserImportService) companyEstates(ctx context.Context, state *userImportState, companyID string) ([]importUnit, error) {
+	if estates, ok := state.estates[companyID]; ok {
+		return estates, nil
+	}
+	var rows []struct {
+		ID   string
+		Code string
+		Name string
+	}
+	if err := s.db.WithContext(ctx).
+		Table("estates").
+		Select("id, COALESCE(code, '') AS code, name").
+		Where("company_id = ?", companyID).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load estates: %w", err)
+	}
+	estates := make([]importUnit, 0, len(rows))
+	for _, row := range rows {
+		estates = append(estates, importUnit{id: row.ID, code: row.Code, name: row.Name, parentID: companyID})
+	}
+	state.estates[companyID] = estates
+	return estates, nil
+}
+
+func (s *UserImportService) companyDivisions(ctx context.Context, state *userImportState, companyID string) ([]importUnit, error) {
+	if divisions, ok := state.divisions[companyID]; ok {
+		return divisions, nil
+	}
+	var rows []struct {
+		ID       string
+		Code     string
+		Name     string
+		EstateID string
+	}
+	if err := s.db.WithContext(ctx).
+		Table("divisions d").
+		Select("d.id, COALESCE(d.code, '') AS code, d.name, d.estate_id").
+		Joins("JOIN estates e ON e.id = d.estate_id").
+		Where("e.company_id = ?", companyID).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load divisions: %w", err)
+	}
+	divisions := make([]importUnit, 0, len(rows))
+	for _, row := range rows {
+		divisions = append(divisions, importUnit{id: row.ID, code: row.Code, name: row.Name, parentID: row.EstateID})
+	}
+	state.divisions[companyID] = divisions
+	return divisions, nil
+}
+
+// matchImportUnit finds a unit by ID, code or name. A reference that matches
+// more than one unit is treated as not found.
+func matchImportUnit(units []importUnit, ref, parentID string) (importUnit, bool) {
+	var (
+		found importUnit
+		count int
+	)
+	for _, unit := range units {
+		if parentID != "" && unit.parentID != parentID {
+			continue
+		}
+		if unit.id == ref {
+			return unit, true
+		}
+		if strings.EqualFold(unit.code, ref) || strings.EqualFold(unit.name, ref) {
+			found = unit
+			count++
+		}
+	}
+	return found, count == 1
+}
+
+func (s *UserImportService) findUserByUsername(ctx context.Context, username string) (*importExistingUser, error) {
+	var users []importExistingUser
+	if err := s.db.WithContext(ctx).
+		Table("users").
+		Select("id, username, role, is_active").
+		Where("LOWER(username) = LOWER(?) AND deleted_at IS NULL", username).
+		Limit(1).
+		Scan(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to check username %s: %w", username, err)
+	}
+	if len(users) == 0 {
+		return nil, nil
+	}
+	return &users[0], nil
+}
+
+func (s *UserImportService) userInCompany(ctx context.Context, userID, companyID string) (bool, error) {
+	var count int64
+	if err := s.db.WithContext(ctx).
+		Table("user_company_assignments").
+		Where("user_id = ? AND company_id = ? AND is_active = ?", userID, companyID, true).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check company assignment: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (s *UserImportService) nikTaken(ctx context.Context, companyID, nik, excludeUserID string) (bool, error) {
+	query := s.db.WithContext(ctx).
+		Table("users u").
+		Joins("JOIN user_company_assignments uca ON uca.user_id = u.id AND uca.is_active = true").
+		Where("uca.company_id = ? AND LOWER(u.nik) = LOWER(?) AND u.deleted_at IS NULL", companyID, nik)
+	if excludeUserID != "" {
+		query = query.Where("u.id <> ?", excludeUserID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check NIK %s: %w", nik, err)
+	}
+	return count > 0, nil
+}
+
+// apply writes all rows in one transaction. Assignments are upserted against
+// the unique (user, company/estate/division) indexes so re-importing a file
+// reactivates rows instead of duplicating them. The user quotas are checked
+// again inside the transaction, with the plans locked, so a concurrent import
+// or user creation cannot push a company past its limit.
+func (s *UserImportService) apply(ctx context.Context, requesterID string, plans []*userImportPlan, newUsersCompany map[string]int) error {
+	if s.passwords == nil {
+		return errors.New("password service unavailable")
+	}
+
+	companyIDs := make([]string, 0, len(newUsersCompany))
+	for companyID := range newUsersCompany {
+		companyIDs = append(companyIDs, companyID)
+	}
+	sort.Strings(companyIDs)
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if s.master.tenantPlans != nil {
+			for _, companyID := range companyIDs {
+				if err := s.master.tenantPlans.CheckUserQuotaTx(tx, companyID, newUsersCompany[companyID]); err != nil {
+					return err
+				}
+			}
+		}
+
+		now := time.Now()
+		for _, plan := range plans {
+			row := plan.row
+			if row.Action == models.UserImportActionCreate {
+				password := plan.password
+				if password == "" {
+					generated, err := generateImportPassword()
+					if err != nil {
+						return err
+					}
+					password = generated
+					row.TemporaryPassword = generated
+				}
+				hashed, err := s.passwords.HashPassword(password)
+				if err != nil {
+					return fmt.Errorf("row %d: failed to hash password: %w", row.Row, err)
+				}
+
+				var userIDs []string
+				if err := tx.Raw(`
+					INSERT INTO users (id, username, name, email, phone, nik, password, role, is_active, password_changed_at, created_at, updated_at)
+					VALUES (gen_random_uuid(), ?, ?, ?, ?, NULLIF(?, ''), ?, ?, true, ?, ?, ?)
+					RETURNING id
+				`, row.Username, row.Name, plan.email, plan.phone, row.NIK, hashed, row.Role, now, now, now).
+					Scan(&userIDs).Error; err != nil {
+					return fmt.Errorf("row %d: failed to create user %s: %w", row.Row, row.Username, err)
+				}
+				if len(userIDs) == 0 {
+					return fmt.Errorf("row %d: failed to create user %s", row.Row, row.Username)
+				}
+				row.UserID = userIDs[0]
+			} else if row.NIK != "" {
+				if err := tx.Exec(`UPDATE users SET nik = ?, updated_at = ? WHERE id = ?`, row.NIK, now, row.UserID).Error; err != nil {
+					return fmt.Errorf("row %d: failed to update NIK: %w", row.Row, err)
+				}
+			}
+
+			if err := upsertImportCompanyAssignment(tx, row.UserID, row.CompanyID, plan.mandorType, requesterID, now); err != nil {
+				return fmt.Errorf("row %d: %w", row.Row, err)
+			}
+			for _, estateID := range row.EstateIDs {
+				if err := upsertImportAssignment(tx, "user_estate_assignments", "estate_id", row.UserID, estateID, requesterID, now); err != nil {
+					return fmt.Errorf("row %d: %w", row.Row, err)
+				}
+			}
+			for _, divisionID := range row.DivisionIDs {
+				if err := upsertImportAssignment(tx, "user_division_assignments", "division_id", row.UserID, divisionID, requesterID, now); err != nil {
+					return fmt.Errorf("row %d: %w", row.Row, err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func upsertImportCompanyAssignment(tx *gorm.DB, userID, companyID string, mandorType *string, assignedBy string, now time.Time) error {
+	if err := tx.Exec(`
+		INSERT INTO user_company_assignments (id, user_id, company_id, mandor_type, is_active, assigned_by, assigned_at, created_at, updated_at)
+		VALUES (gen_random_uuid(), ?, ?, ?, true, ?, ?, ?, ?)
+		ON CONFLICT (user_id, company_id) DO UPDATE SET
+			is_active = true,
+			mandor_type = COALESCE(EXCLUDED.mandor_type, user_company_assignments.mandor_type),
+			updated_at = EXCLUDED.updated_at
+	`, userID, companyID, mandorType, assignedBy, now, now, now).Error; err != nil {
+		return fmt.Errorf("failed to assign company: %w", err)
+	}
+	return nil
+}
+
+func upsertImportAssignment(tx *gorm.DB, table, targetColumn, userID, targetID, assignedBy string, now time.Time) error {
+	if err := tx.Exec(`
+		INSERT INTO `+table+` (id, user_id, `+targetColumn+`, is_active, assigned_by, assigned_at, created_at, updated_at)
+		VALUES (gen_random_uuid(), ?, ?, true, ?, ?, ?, ?)
+		ON CONFLICT (user_id, `+targetColumn+`) DO UPDATE SET
+			is_active = true,
+			assigned_by = EXCLUDED.assigned_by,
+			assigned_at = EXCLUDED.assigned_at,
+			updated_at = EXCLUDED.updated_at
+	`, userID, targetID, assignedBy, now, now, now).Error; err != nil {
+		return fmt.Errorf("failed to upsert %s: %w", table, err)
+	}
+	return nil
+}
+
+func generateImportPassword() (string, error) {
+	var builder strings.Builder
+	builder.Grow(importPasswordLength)
+	max := big.NewInt(int64(len(importPasswordAlphabet)))
+	for i := 0; i < importPasswordLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate temporary password: %w", err)
+		}
+		builder.WriteByte(importPasswordAlphabet[n.Int64()])
+	}
+	return builder.String(), nil
+}
+
+func appendUnique(values []string, value string) []string {
+	if containsImportID(values, value) {
+		return values
+	}
+	return append(values, value)
+}
+
+func containsImportID(values []string, value string) bool {
+	for _, existing := range values {
+		if existing == value {
+			return true
+		}
+	}
+	return false
+}
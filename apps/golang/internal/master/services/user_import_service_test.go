@@ -0,0 +1,37 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchImportUnit(t *testing.T) {
+	divisions := []importUnit{
+		{id: "div-1", code: "DIV-A", name: "Divisi A", parentID: "estate-1"},
+		{id: "div-2", code: "DIV-A", name: "Divisi A Timur", parentID: "estate-2"},
+		{id: "div-3", code: "DIV-B", name: "Divisi B", parentID: "estate-2"},
+	}
+
+	unit, ok := matchImportUnit(divisions, "div-a", "estate-2")
+	assert.True(t, ok)
+	assert.Equal(t, "div-2", unit.id)
+
+	_, ok = matchImportUnit(divisions, "DIV-A", "")
+	assert.False(t, ok, "codes shared across estates are ambiguous without an estate")
+
+	unit, ok = matchImportUnit(divisions, "Divisi B", "")
+	assert.True(t, ok)
+	assert.Equal(t, "div-3", unit.id)
+
+	unit, ok = matchImportUnit(divisions, "div-1", "")
+	assert.True(t, ok)
+	assert.Equal(t, "estate-1", unit.parentID)
+}
+
+func TestGenerateImportPassword(t *testing.T) {
+	password, err := generateImportPassword()
+	assert.NoError(t, err)
+	assert.Len(t, password, importPasswordLength)
+	assert.GreaterOrEqual(t, len(password), minImportPasswordLength)
+}
@@ -23,6 +23,7 @@ type AuthMiddleware struct {
 	tokenService         mobileDomain.TokenService
 	roleHierarchyService *services.RoleHierarchyService
 	rbacService          *rbacServices.RBACService
+	networkPolicy        NetworkPolicy
 }
 
 // NewAuthMiddleware creates a new auth middleware
@@ -34,6 +35,11 @@ func NewAuthMiddleware(tokenService mobileDomain.TokenService, roleHierarchyServ
 	}
 }
 
+// SetNetworkPolicy enables per-company IP allow-list enforcement.
+func (m *AuthMiddleware) SetNetworkPolicy(policy NetworkPolicy) {
+	m.networkPolicy = policy
+}
+
 // GraphQLAuth middleware for GraphQL endpoint authentication
 func (m *AuthMiddleware) GraphQLAuth() gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
@@ -69,6 +75,13 @@ func (m *AuthMiddleware) GraphQLAuth() gin.HandlerFunc {
 			// Debug: Log successful validation
 			fmt.Printf("✅ [AuthMiddleware] Token validated for user: %s (role: %s)\n", claims.UserID, claims.Role)
 
+			if code := checkNetworkPolicy(c.Request.Context(), m.networkPolicy, claims.CompanyID, c.ClientIP()); code != "" {
+				fmt.Printf("❌ [AuthMiddleware] IP %s not allowed for company %s (%s)\n", c.ClientIP(), claims.CompanyID, code)
+				c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), "auth_error_code", code))
+				c.Next()
+				return
+			}
+
 			// Add authenticated user context
 			ctx := context.WithValue(c.Request.Context(), "auth_token", token)
 			ctx = context.WithValue(ctx, "user_id", claims.UserID)
@@ -421,7 +434,14 @@ func (m *AuthMiddleware) RequireCompanyAccess(companyID string) gin.HandlerFunc
 }
 
 // roleRateLimitStore is a shared rate limiter for role-based limiting on mutations.
-var roleRateLimitStore = NewInMemoryRateLimitStore(DefaultRateLimitConfig())
+var roleRateLimitStore RateLimitStore = NewInMemoryRateLimitStore(DefaultRateLimitConfig())
+
+// SetRoleRateLimitStore replaces the store behind RoleBasedRateLimit, e.g.
+// with a SharedRateLimitStore so limits hold across instances. Call it once
+// during startup, before serving requests.
+func SetRoleRateLimitStore(store RateLimitStore) {
+	roleRateLimitStore = store
+}
 
 // RoleBasedRateLimit applies different rate limits based on user role.
 // Uses a sliding window counter keyed by userID:role to enforce per-minute limits.
@@ -454,7 +474,7 @@ func (m *AuthMiddleware) RoleBasedRateLimit() gin.HandlerFunc {
 		allowed := roleRateLimitStore.AllowSlidingWindow(key, rateLimit, time.Minute)
 
 		if !allowed {
-			roleRateLimitStore.metrics.RecordBlocked("user")
+			roleRateLimitStore.Metrics().RecordBlocked("user")
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":   "Rate limit exceeded",
 				"message": "Terlalu banyak permintaan, silakan coba lagi nanti",
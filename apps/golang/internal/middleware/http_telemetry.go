@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"agrinovagraphql/server/pkg/logger"
+	"agrinovagraphql/server/pkg/tracing"
+)
+
+// TraceIDHeader returns the request's trace ID so a report can be matched
+// to its trace and log lines.
+const TraceIDHeader = "X-Trace-ID"
+
+// unmatchedRoute labels requests that matched no route, keeping the route
+// label bounded.
+const unmatchedRoute = "unmatched"
+
+var (
+	httpRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "agrinova",
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Total number of HTTP requests, by route and status code",
+		},
+		[]string{"method", "route", "status"},
+	)
+
+	httpRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "agrinova",
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency, by route",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"method", "route"},
+	)
+
+	httpRequestsInFlight = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "agrinova",
+			Subsystem: "http",
+			Name:      "requests_in_flight",
+			Help:      "Number of HTTP requests being served",
+		},
+	)
+)
+
+// HTTPTelemetry starts a server span for each request, continuing any trace
+// context sent by the caller, and records request count and latency by
+// route. Server errors are logged with their trace ID.
+func HTTPTelemetry() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		method := c.Request.Method
+
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx, span := tracing.Tracer().Start(ctx, method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(method),
+				semconv.HTTPRoute(route),
+			),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		if traceID, _ := tracing.IDs(ctx); traceID != "" {
+			c.Header(TraceIDHeader, traceID)
+		}
+
+		httpRequestsInFlight.Inc()
+		defer httpRequestsInFlight.Dec()
+		c.Next()
+
+		status := c.Writer.Status()
+		httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+		// WebSocket connections last for the whole session; their latency
+		// would only skew the histogram.
+		if !c.IsWebsocket() {
+			httpRequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+		}
+
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", status))
+			logger.WithContext(ctx).Error("%s %s returned %d: %s", method, route, status, c.Errors.String())
+		}
+	}
+}
+
+// MetricsHandler serves Prometheus metrics. A non-empty token must be sent
+// as a bearer token, so the endpoint can stay on the public listener.
+func MetricsHandler(token string) gin.HandlerFunc {
+	metrics := promhttp.Handler()
+	return func(c *gin.Context) {
+		if token != "" {
+			provided := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				c.AbortWithStatus(http.StatusUnauthorized)
+				return
+			}
+		}
+		metrics.ServeHTTP(c.Writer, c.Request)
+	}
+}
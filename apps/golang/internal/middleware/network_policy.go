@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// authErrorIPNotAllowed is stored under "auth_error_code" when a request
+// carries valid credentials but originates outside the company's allow-list.
+const authErrorIPNotAllowed = "IP_NOT_ALLOWED"
+
+// authErrorNetworkPolicyUnavailable is stored under "auth_error_code" when
+// the allow-list could not be loaded or decoded.
+const authErrorNetworkPolicyUnavailable = "NETWORK_POLICY_UNAVAILABLE"
+
+// NetworkPolicy decides whether a client IP may use a company's account.
+// Implemented by the company settings service (allowedIpRanges).
+type NetworkPolicy interface {
+	IsIPAllowed(ctx context.Context, companyID, ip string) (bool, error)
+}
+
+// checkNetworkPolicy returns the auth error code for a request the company
+// allow-list rejects, or "" when it is allowed. Lookup failures are logged and
+// rejected, so a broken allow-list cannot silently open the account to every
+// network.
+func checkNetworkPolicy(ctx context.Context, policy NetworkPolicy, companyID, ip string) string {
+	if policy == nil || strings.TrimSpace(companyID) == "" {
+		return ""
+	}
+
+	allowed, err := policy.IsIPAllowed(ctx, companyID, ip)
+	if err != nil {
+		fmt.Printf("⚠️ [NetworkPolicy] allow-list lookup failed for company %s: %v\n", companyID, err)
+		return authErrorNetworkPolicyUnavailable
+	}
+	if !allowed {
+		return authErrorIPNotAllowed
+	}
+	return ""
+}
@@ -142,8 +142,14 @@ func RateLimitConfigFromEnv() RateLimitConfig {
 // RateLimitStore interface for rate limiting storage
 type RateLimitStore interface {
 	Allow(key string, tokens int) bool
+	AllowSlidingWindow(key string, maxCount int, window time.Duration) bool
 	GetRemainingTokens(key string) int
 	Reset(key string)
+	GetMetrics() map[string]interface{}
+	Metrics() *RateLimitMetrics
+	// SlidingWindowEnabled reports whether middlewares should use sliding
+	// windows instead of per-request token buckets.
+	SlidingWindowEnabled() bool
 }
 
 // SlidingWindowCounter implements sliding window rate limiting
@@ -384,6 +390,16 @@ func (s *InMemoryRateLimitStore) GetMetrics() map[string]interface{} {
 	return s.metrics.GetStats()
 }
 
+// Metrics returns the metrics tracker
+func (s *InMemoryRateLimitStore) Metrics() *RateLimitMetrics {
+	return s.metrics
+}
+
+// SlidingWindowEnabled reports whether RATE_LIMIT_ENABLE_SLIDING_WINDOW is set
+func (s *InMemoryRateLimitStore) SlidingWindowEnabled() bool {
+	return s.enableSlidingWindow
+}
+
 // cleanup removes stale limiters periodically
 func (s *InMemoryRateLimitStore) cleanup() {
 	ticker := time.NewTicker(5 * time.Minute)
@@ -597,14 +613,19 @@ func (a *GraphQLComplexityAnalyzer) calculateSelectionDepth(selection ast.Select
 
 // RateLimitMiddleware provides comprehensive rate limiting
 type RateLimitMiddleware struct {
-	store        *InMemoryRateLimitStore
+	store        RateLimitStore
 	config       RateLimitConfig
 	complexityAnalyzer *GraphQLComplexityAnalyzer
 }
 
 // NewRateLimitMiddleware creates a new rate limit middleware
 func NewRateLimitMiddleware(config RateLimitConfig) *RateLimitMiddleware {
-	store := NewInMemoryRateLimitStore(config)
+	return NewRateLimitMiddlewareWithStore(config, NewInMemoryRateLimitStore(config))
+}
+
+// NewRateLimitMiddlewareWithStore creates a rate limit middleware on store,
+// e.g. a SharedRateLimitStore so limits hold across instances
+func NewRateLimitMiddlewareWithStore(config RateLimitConfig, store RateLimitStore) *RateLimitMiddleware {
 	return &RateLimitMiddleware{
 		store:             store,
 		config:            config,
@@ -650,7 +671,7 @@ func (m *RateLimitMiddleware) IPRateLimit() gin.HandlerFunc {
 		var allowed bool
 
 		// Use sliding window if enabled, otherwise use token bucket
-		if m.store.enableSlidingWindow {
+		if m.store.SlidingWindowEnabled() {
 			allowed = m.store.AllowSlidingWindow(ipKey, m.config.IPRequestsPerMinute, time.Minute)
 		} else {
 			// Create IP-specific limiter
@@ -662,7 +683,7 @@ func (m *RateLimitMiddleware) IPRateLimit() gin.HandlerFunc {
 		}
 
 		if !allowed {
-			m.store.metrics.RecordBlocked("ip")
+			m.store.Metrics().RecordBlocked("ip")
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":       "ip_rate_limit_exceeded",
 				"message":     "Too many requests from this IP address. Please try again later.",
@@ -788,7 +809,7 @@ func (m *RateLimitMiddleware) GraphQLRateLimit() gin.HandlerFunc {
 
 		// Apply complexity-based rate limiting
 		if complexityCost > m.config.GraphQLComplexityLimit {
-			m.store.metrics.RecordBlocked("graphql")
+			m.store.Metrics().RecordBlocked("graphql")
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":          "graphql_complexity_exceeded",
 				"message":        "Query complexity exceeds allowed limit. Please simplify your query.",
@@ -829,7 +850,7 @@ func (m *RateLimitMiddleware) GraphQLRateLimit() gin.HandlerFunc {
 		graphqlKey := fmt.Sprintf("graphql:user:%s:type:%s", userIDStr, queryType)
 
 		var allowed bool
-		if m.store.enableSlidingWindow {
+		if m.store.SlidingWindowEnabled() {
 			// Use sliding window for GraphQL rate limiting
 			allowed = m.store.AllowSlidingWindow(graphqlKey, adjustedRPM, time.Minute)
 		} else {
@@ -842,7 +863,7 @@ func (m *RateLimitMiddleware) GraphQLRateLimit() gin.HandlerFunc {
 		}
 
 		if !allowed {
-			m.store.metrics.RecordBlocked("graphql")
+			m.store.Metrics().RecordBlocked("graphql")
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":           "graphql_rate_limit_exceeded",
 				"message":         "Too many GraphQL operations. Please optimize your queries or try again later.",
@@ -892,7 +913,7 @@ func (m *RateLimitMiddleware) WebSocketRateLimit() gin.HandlerFunc {
 		)
 
 		if !wsConnLimiter.Allow() {
-			m.store.metrics.RecordBlocked("websocket")
+			m.store.Metrics().RecordBlocked("websocket")
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":       "websocket_connection_rate_limit_exceeded",
 				"message":     "Too many WebSocket connection attempts. Please try again later.",
@@ -910,7 +931,7 @@ func (m *RateLimitMiddleware) WebSocketRateLimit() gin.HandlerFunc {
 		)
 
 		if !wsAuthLimiter.Allow() {
-			m.store.metrics.RecordBlocked("websocket")
+			m.store.Metrics().RecordBlocked("websocket")
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":       "websocket_auth_rate_limit_exceeded",
 				"message":     "Too many WebSocket authentication attempts. Please try again later.",
@@ -1035,7 +1056,7 @@ func (m *RateLimitMiddleware) HealthCheck() gin.H {
 		"status":                "healthy",
 		"config":                m.config,
 		"metrics":               m.store.GetMetrics(),
-		"sliding_window_enabled": m.store.enableSlidingWindow,
+		"sliding_window_enabled": m.store.SlidingWindowEnabled(),
 		"timestamp":             time.Now().Unix(),
 	}
 }
@@ -1048,13 +1069,25 @@ func (m *RateLimitMiddleware) MetricsEndpoint() gin.HandlerFunc {
 			"rate_limiting": gin.H{
 				"metrics":               metrics,
 				"config":                m.config,
-				"sliding_window_enabled": m.store.enableSlidingWindow,
-				"active_limiters": gin.H{
-					"token_bucket":  len(m.store.tokenLimiters),
-					"sliding_window": len(m.store.slidingWindows),
-				},
+				"sliding_window_enabled": m.store.SlidingWindowEnabled(),
+				"active_limiters":       m.activeLimiters(),
 			},
 			"timestamp": time.Now().Unix(),
 		})
 	})
-}
\ No newline at end of file
+}
+
+// activeLimiters counts the limiters held in process memory; shared stores
+// keep none.
+func (m *RateLimitMiddleware) activeLimiters() gin.H {
+	store, ok := m.store.(*InMemoryRateLimitStore)
+	if !ok {
+		return gin.H{"token_bucket": 0, "sliding_window": 0}
+	}
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	return gin.H{
+		"token_bucket":   len(store.tokenLimiters),
+		"sliding_window": len(store.slidingWindows),
+	}
+}
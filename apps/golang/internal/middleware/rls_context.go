@@ -407,6 +407,34 @@ func GetRLSContextFromRequest(ctx context.Context) (*RLSContext, bool) {
 	return rlsCtx, ok
 }
 
+// RLSSessionSettings returns the app.* settings app_set_user_context would
+// set for the request's RLS context, for connections that apply them
+// directly, such as read replica transactions. It returns nil when the
+// request has no RLS context.
+func RLSSessionSettings(ctx context.Context) map[string]string {
+	rlsCtx, ok := GetRLSContextFromRequest(ctx)
+	if !ok {
+		return nil
+	}
+
+	return map[string]string{
+		"app.user_id":      rlsCtx.UserID.String(),
+		"app.user_role":    rlsCtx.Role,
+		"app.company_ids":  joinUUIDs(rlsCtx.CompanyIDs),
+		"app.estate_ids":   joinUUIDs(rlsCtx.EstateIDs),
+		"app.division_ids": joinUUIDs(rlsCtx.DivisionIDs),
+	}
+}
+
+// joinUUIDs matches array_to_string(uuid[], ',') used by app_set_user_context
+func joinUUIDs(ids []uuid.UUID) string {
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = id.String()
+	}
+	return strings.Join(strs, ",")
+}
+
 // ApplicationLevelSecurityCheck performs application-level security check
 // This is used as a fallback when RLS is not available or as defense-in-depth
 func ApplicationLevelSecurityCheck(ctx context.Context, db *gorm.DB, resourceType string, resourceID uuid.UUID) (bool, error) {
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"math"
+	"time"
+
+	"agrinovagraphql/server/internal/ratelimit"
+)
+
+// sharedStoreTimeout bounds a counter round-trip on the request path.
+const sharedStoreTimeout = 2 * time.Second
+
+// SharedRateLimitStore implements RateLimitStore on a ratelimit.Counter, so
+// every instance using the same counter enforces one limit. Token buckets
+// are approximated with one-second sliding windows of GlobalRequestsPerSecond.
+// Counter failures allow the request: a database hiccup must not lock every
+// user out.
+type SharedRateLimitStore struct {
+	counter ratelimit.Counter
+	config  RateLimitConfig
+	metrics *RateLimitMetrics
+}
+
+var _ RateLimitStore = (*SharedRateLimitStore)(nil)
+
+// NewSharedRateLimitStore creates a rate limit store backed by counter
+func NewSharedRateLimitStore(config RateLimitConfig, counter ratelimit.Counter) *SharedRateLimitStore {
+	return &SharedRateLimitStore{
+		counter: counter,
+		config:  config,
+		metrics: NewRateLimitMetrics(),
+	}
+}
+
+// Allow checks if a request costing tokens is allowed for the given key
+func (s *SharedRateLimitStore) Allow(key string, tokens int) bool {
+	s.metrics.RecordRequest()
+
+	ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+	defer cancel()
+
+	w, err := s.counter.Hit(ctx, key, int64(tokens), time.Second)
+	if err != nil {
+		log.Printf("rate limit: %v", err)
+		return true
+	}
+
+	allowed := w.Estimate(time.Now()) <= float64(s.tokenLimit())
+	if !allowed {
+		s.metrics.RecordBlocked("token_bucket")
+	}
+	return allowed
+}
+
+// AllowSlidingWindow checks if a request is allowed using sliding window algorithm
+func (s *SharedRateLimitStore) AllowSlidingWindow(key string, maxCount int, window time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+	defer cancel()
+
+	allowed, _, err := ratelimit.Allow(ctx, s.counter, key, maxCount, window)
+	if err != nil {
+		log.Printf("rate limit: %v", err)
+		return true
+	}
+	return allowed
+}
+
+// GetRemainingTokens returns remaining tokens for the key
+func (s *SharedRateLimitStore) GetRemainingTokens(key string) int {
+	ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+	defer cancel()
+
+	limit := s.tokenLimit()
+	w, err := s.counter.Peek(ctx, key, time.Second)
+	if err != nil {
+		return limit
+	}
+	return max(0, limit-int(math.Ceil(w.Estimate(time.Now()))))
+}
+
+// Reset resets the rate limit for a specific key
+func (s *SharedRateLimitStore) Reset(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), sharedStoreTimeout)
+	defer cancel()
+
+	if err := s.counter.Reset(ctx, key); err != nil {
+		log.Printf("rate limit: %v", err)
+	}
+}
+
+// GetMetrics returns rate limiting metrics of this instance
+func (s *SharedRateLimitStore) GetMetrics() map[string]interface{} {
+	return s.metrics.GetStats()
+}
+
+// Metrics returns the metrics tracker
+func (s *SharedRateLimitStore) Metrics() *RateLimitMetrics {
+	return s.metrics
+}
+
+// SlidingWindowEnabled is always true: per-request token buckets cannot be
+// shared between instances.
+func (s *SharedRateLimitStore) SlidingWindowEnabled() bool {
+	return true
+}
+
+// tokenLimit is the per-second allowance, never below the burst size.
+func (s *SharedRateLimitStore) tokenLimit() int {
+	return max(int(s.config.GlobalRequestsPerSecond), s.config.GlobalBurst)
+}
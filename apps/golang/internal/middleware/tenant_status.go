@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	companyServices "agrinovagraphql/server/internal/company/services"
+	"agrinovagraphql/server/internal/graphql/domain/auth"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// TenantWriteGuard reports whether a company may still change data.
+// Implemented by the tenant plan service (suspension and trial expiry).
+type TenantWriteGuard interface {
+	CheckWritable(ctx context.Context, companyID string) error
+}
+
+// readOnlyAllowedMutations stay available to suspended or expired tenants so
+// users can still sign in, sign out and manage their own credentials.
+var readOnlyAllowedMutations = map[string]struct{}{
+	"webLogin":                {},
+	"mobileLogin":             {},
+	"refreshToken":            {},
+	"deviceRenew":             {},
+	"logout":                  {},
+	"logoutAllDevices":        {},
+	"forgotPassword":          {},
+	"resetPassword":           {},
+	"changePassword":          {},
+	"createWebQRLoginSession": {},
+	"approveWebQRLogin":       {},
+	"consumeWebQRLogin":       {},
+	"unbindDevice":            {},
+	"unregisterFCMToken":      {},
+}
+
+// TenantReadOnlyOperationMiddleware rejects mutations from companies that are
+// suspended or whose trial has expired. Queries keep working so the tenant can
+// still read and export its data. SUPER_ADMIN is never restricted.
+func TenantReadOnlyOperationMiddleware(guard TenantWriteGuard) graphql.OperationMiddleware {
+	return func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		if guard == nil || !graphql.HasOperationContext(ctx) {
+			return next(ctx)
+		}
+
+		opCtx := graphql.GetOperationContext(ctx)
+		if opCtx.Operation == nil || opCtx.Operation.Operation != ast.Mutation {
+			return next(ctx)
+		}
+
+		companyID := GetCompanyFromContext(ctx)
+		if companyID == "" || GetUserRoleFromContext(ctx) == auth.UserRoleSuperAdmin {
+			return next(ctx)
+		}
+		if onlyReadOnlyAllowedMutations(opCtx) {
+			return next(ctx)
+		}
+
+		err := guard.CheckWritable(ctx, companyID)
+		if err == nil {
+			return next(ctx)
+		}
+		if !errors.Is(err, companyServices.ErrCompanySuspended) && !errors.Is(err, companyServices.ErrTrialExpired) {
+			// Plan lookup failures must not block every write for the tenant.
+			fmt.Printf("⚠️ [TenantStatus] write check failed for company %s: %v\n", companyID, err)
+			return next(ctx)
+		}
+
+		return graphql.OneShot(&graphql.Response{
+			Errors: gqlerror.List{{
+				Message: err.Error(),
+				Extensions: map[string]interface{}{
+					"code": "COMPANY_READ_ONLY",
+				},
+			}},
+		})
+	}
+}
+
+func onlyReadOnlyAllowedMutations(opCtx *graphql.OperationContext) bool {
+	fields := graphql.CollectFields(opCtx, opCtx.Operation.SelectionSet, []string{"Mutation"})
+	if len(fields) == 0 {
+		return false
+	}
+	for _, field := range fields {
+		if _, ok := readOnlyAllowedMutations[field.Name]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -22,6 +22,7 @@ type WebAuthMiddleware struct {
 	webAuthService *webApp.Service
 	cookieService  *webInfra.CookieService
 	logger         *logger.Logger
+	networkPolicy  NetworkPolicy
 }
 
 // NewWebAuthMiddleware creates a new web authentication middleware
@@ -37,6 +38,11 @@ func NewWebAuthMiddleware(
 	}
 }
 
+// SetNetworkPolicy enables per-company IP allow-list enforcement.
+func (m *WebAuthMiddleware) SetNetworkPolicy(policy NetworkPolicy) {
+	m.networkPolicy = policy
+}
+
 // WebSessionMiddleware validates web sessions from cookies and adds user/session to context
 func (m *WebAuthMiddleware) WebSessionMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -87,6 +93,18 @@ func (m *WebAuthMiddleware) WebSessionMiddleware() gin.HandlerFunc {
 			companyID = result.Companies[0].ID
 		}
 
+		if code := checkNetworkPolicy(c.Request.Context(), m.networkPolicy, companyID, c.ClientIP()); code != "" {
+			m.logger.Info("Web session rejected by company IP allow-list",
+				"user_id", gqlUser.ID,
+				"company_id", companyID,
+				"ip", c.ClientIP(),
+				"code", code,
+			)
+			c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), "auth_error_code", code))
+			c.Next()
+			return
+		}
+
 		// Add user, session result, and JWT claims to context
 		ctx := context.WithValue(c.Request.Context(), "user", gqlUser)
 		ctx = context.WithValue(ctx, "web_login_result", result) // Use specific key for new result
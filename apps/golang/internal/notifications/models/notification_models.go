@@ -81,6 +81,14 @@ const (
 	NotificationDeliveryStatusPending   = "PENDING"
 	NotificationDeliveryStatusDelivered = "DELIVERED"
 	NotificationDeliveryStatusFailed    = "FAILED"
+	NotificationDeliveryStatusSkipped   = "SKIPPED"
+)
+
+// Email digest modes
+const (
+	EmailDigestModeImmediate = "IMMEDIATE"
+	EmailDigestModeHourly    = "HOURLY"
+	EmailDigestModeDaily     = "DAILY"
 )
 
 // Notification represents a system notification
@@ -144,6 +152,11 @@ type NotificationTemplate struct {
 	DefaultMetadata string               `gorm:"type:json" json:"defaultMetadata,omitempty"`
 	IsActive        bool                 `gorm:"not null;default:true" json:"isActive"`
 
+	// Email rendering; empty templates fall back to the title/message layout
+	EmailSubjectTemplate string `gorm:"type:varchar(255)" json:"emailSubjectTemplate,omitempty"`
+	EmailTextTemplate    string `gorm:"type:text" json:"emailTextTemplate,omitempty"`
+	EmailHTMLTemplate    string `gorm:"type:text" json:"emailHtmlTemplate,omitempty"`
+
 	CreatedAt time.Time `gorm:"index" json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
@@ -169,6 +182,10 @@ type NotificationPreferences struct {
 	QuietHoursEnd      string `gorm:"type:varchar(5)" json:"quietHoursEnd,omitempty"`   // HH:MM format
 	QuietHoursTimezone string `gorm:"type:varchar(50);default:'Asia/Jakarta'" json:"quietHoursTimezone"`
 
+	// Email batching: IMMEDIATE, HOURLY or DAILY
+	EmailDigestMode   string     `gorm:"type:varchar(20);not null;default:'IMMEDIATE'" json:"emailDigestMode"`
+	LastEmailDigestAt *time.Time `json:"lastEmailDigestAt,omitempty"`
+
 	CreatedAt time.Time `gorm:"index" json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
@@ -198,6 +215,9 @@ type NotificationDelivery struct {
 	DeliveredAt    *time.Time `json:"deliveredAt,omitempty"`
 	FailureReason  string     `gorm:"type:text" json:"failureReason,omitempty"`
 	RetryCount     int        `gorm:"not null;default:0" json:"retryCount"`
+	// NextAttemptAt holds a pending delivery back for quiet hours, a digest
+	// or a retry.
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
 
 	CreatedAt time.Time `gorm:"index" json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
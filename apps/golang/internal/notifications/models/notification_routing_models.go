@@ -0,0 +1,132 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Routing recipient scopes
+const (
+	// RoutingScopeHierarchy walks the supervisor chain of the event actor.
+	RoutingScopeHierarchy = "HIERARCHY"
+	// RoutingScopeEstate targets role holders assigned to the event's estate.
+	RoutingScopeEstate = "ESTATE"
+	// RoutingScopeCompany targets role holders assigned to the event's company.
+	RoutingScopeCompany = "COMPANY"
+)
+
+// NotificationRoutingRule maps an event type and priority to recipients and
+// delivery channels. Rules with a nil CompanyID are global defaults that apply
+// to companies without rules of their own for the same event type.
+type NotificationRoutingRule struct {
+	ID          string               `gorm:"primaryKey;type:varchar(36)" json:"id"`
+	CompanyID   *string              `gorm:"type:uuid;index" json:"companyId,omitempty"`
+	Name        string               `gorm:"type:varchar(150);not null" json:"name"`
+	EventType   NotificationType     `gorm:"type:varchar(50);not null;index" json:"eventType"`
+	MinPriority NotificationPriority `gorm:"type:varchar(20);not null;default:'LOW'" json:"minPriority"`
+
+	// Recipients and channels (JSON string arrays)
+	RecipientRoles string `gorm:"type:json;not null" json:"recipientRoles"`
+	Scope          string `gorm:"type:varchar(20);not null;default:'COMPANY'" json:"scope"`
+	Channels       string `gorm:"type:json;not null" json:"channels"`
+
+	// Escalation chain (JSON array of RoutingEscalationStep)
+	EscalationSteps string `gorm:"type:json" json:"escalationSteps,omitempty"`
+
+	IsActive  bool    `gorm:"not null;default:true" json:"isActive"`
+	CreatedBy *string `gorm:"type:varchar(36)" json:"createdBy,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// RoutingEscalationStep notifies additional recipients when every notification
+// of the previous step is still unread AfterMinutes after it was sent.
+type RoutingEscalationStep struct {
+	AfterMinutes   int                  `json:"afterMinutes"`
+	RecipientRoles []string             `json:"recipientRoles"`
+	Scope          string               `json:"scope"`
+	Channels       []string             `json:"channels"`
+	Priority       NotificationPriority `json:"priority,omitempty"`
+}
+
+// NotificationRoutingDispatch records a notification sent by a routing rule so
+// unread notifications can be escalated. Dispatches of the same event share an
+// EventKey; Step 0 is the initial fan-out.
+type NotificationRoutingDispatch struct {
+	ID             string     `gorm:"primaryKey;type:varchar(36)" json:"id"`
+	EventKey       string     `gorm:"type:varchar(36);not null;index:idx_routing_dispatch_event,priority:1" json:"eventKey"`
+	RuleID         string     `gorm:"type:varchar(36);not null;index" json:"ruleId"`
+	Step           int        `gorm:"not null;default:0;index:idx_routing_dispatch_event,priority:2" json:"step"`
+	NotificationID string     `gorm:"type:varchar(36);not null;index" json:"notificationId"`
+	RecipientID    string     `gorm:"type:varchar(36);not null" json:"recipientId"`
+	CompanyID      string     `gorm:"type:varchar(36)" json:"companyId,omitempty"`
+	EstateID       string     `gorm:"type:varchar(36)" json:"estateId,omitempty"`
+	ActorID        string     `gorm:"type:varchar(36)" json:"actorId,omitempty"`
+	SubjectID      string     `gorm:"type:varchar(36)" json:"subjectId,omitempty"`
+	ClosedAt       *time.Time `gorm:"index" json:"closedAt,omitempty"`
+
+	CreatedAt time.Time `gorm:"index" json:"createdAt"`
+}
+
+// TableName returns the table name for NotificationRoutingRule
+func (NotificationRoutingRule) TableName() string {
+	return "notification_routing_rules"
+}
+
+// TableName returns the table name for NotificationRoutingDispatch
+func (NotificationRoutingDispatch) TableName() string {
+	return "notification_routing_dispatches"
+}
+
+// RoleList returns the rule's recipient roles.
+func (r *NotificationRoutingRule) RoleList() []string {
+	return decodeStringList(r.RecipientRoles)
+}
+
+// ChannelList returns the rule's delivery channels.
+func (r *NotificationRoutingRule) ChannelList() []string {
+	return decodeStringList(r.Channels)
+}
+
+// EscalationChain returns the rule's escalation steps in order.
+func (r *NotificationRoutingRule) EscalationChain() []RoutingEscalationStep {
+	if strings.TrimSpace(r.EscalationSteps) == "" {
+		return nil
+	}
+	var steps []RoutingEscalationStep
+	if err := json.Unmarshal([]byte(r.EscalationSteps), &steps); err != nil {
+		return nil
+	}
+	return steps
+}
+
+func decodeStringList(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal([]byte(value), &list); err != nil {
+		return nil
+	}
+	return list
+}
+
+// BeforeCreate hook to generate UUID for routing rules
+func (r *NotificationRoutingRule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = generateUUID()
+	}
+	return nil
+}
+
+// BeforeCreate hook to generate UUID for routing dispatches
+func (d *NotificationRoutingDispatch) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == "" {
+		d.ID = generateUUID()
+	}
+	return nil
+}
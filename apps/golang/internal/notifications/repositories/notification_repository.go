@@ -509,6 +509,120 @@ func (r *NotificationRepository) UpdateUserPreferences(ctx context.Context, pref
 	return result.Error
 }
 
+// UpdateLastEmailDigestAt records when the user's last email digest was sent
+func (r *NotificationRepository) UpdateLastEmailDigestAt(ctx context.Context, userID string, sentAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.NotificationPreferences{}).
+		Where("user_id = ?", userID).
+		Update("last_email_digest_at", sentAt).Error
+}
+
+// Email delivery methods
+
+// IsCompanyEmailEnabled reports whether email is enabled in company_settings for
+// any company the user belongs to. Users without a company (e.g. super admins)
+// are not restricted.
+func (r *NotificationRepository) IsCompanyEmailEnabled(ctx context.Context, userID string) (bool, error) {
+	var result struct {
+		Companies int64
+		Enabled   int64
+	}
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT
+			COUNT(*) AS companies,
+			COUNT(*) FILTER (WHERE cs.email_enabled = true) AS enabled
+		FROM user_company_assignments uca
+		LEFT JOIN company_settings cs ON cs.company_id = uca.company_id
+		WHERE uca.user_id = ? AND uca.is_active = true
+	`, userID).Scan(&result).Error
+	if err != nil {
+		return false, err
+	}
+	return result.Companies == 0 || result.Enabled > 0, nil
+}
+
+// GetPendingEmailDeliveries loads pending EMAIL deliveries that are due at now
+// together with their notification and the recipient's address, oldest
+// first. Deliveries held back for quiet hours, a digest or a retry are skipped
+// until their next_attempt_at so they cannot crowd new email out of the batch.
+func (r *NotificationRepository) GetPendingEmailDeliveries(ctx context.Context, now time.Time, limit int) ([]*PendingEmailDelivery, error) {
+	if limit <= 0 {
+		limit = 500
+	}
+
+	var rows []*PendingEmailDelivery
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT
+			nd.id AS delivery_id,
+			nd.retry_count,
+			n.id AS notification_id,
+			u.id AS user_id,
+			COALESCE(u.name, '') AS user_name,
+			COALESCE(u.email, '') AS user_email
+		FROM notification_deliveries nd
+		JOIN notifications n ON n.id = nd.notification_id
+		JOIN users u ON u.id = n.recipient_id
+		WHERE nd.channel = ? AND nd.delivery_status = ?
+			AND (nd.next_attempt_at IS NULL OR nd.next_attempt_at <= ?)
+			AND (n.scheduled_for IS NULL OR n.scheduled_for <= ?)
+		ORDER BY COALESCE(nd.next_attempt_at, nd.created_at) ASC
+		LIMIT ?
+	`, models.NotificationDeliveryChannelEmail, models.NotificationDeliveryStatusPending, now, now, limit).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return rows, nil
+	}
+
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.NotificationID)
+	}
+	var notifications []*models.Notification
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Notification, len(notifications))
+	for _, notification := range notifications {
+		byID[notification.ID] = notification
+	}
+	for _, row := range rows {
+		row.Notification = byID[row.NotificationID]
+	}
+
+	return rows, nil
+}
+
+// UpdateEmailDelivery records the outcome of an email delivery attempt.
+// nextAttemptAt schedules the retry of a delivery left PENDING.
+func (r *NotificationRepository) UpdateEmailDelivery(ctx context.Context, deliveryID string, status string, retryCount int, failureReason string, nextAttemptAt *time.Time) error {
+	updates := map[string]interface{}{
+		"delivery_status": status,
+		"retry_count":     retryCount,
+		"failure_reason":  failureReason,
+		"next_attempt_at": nextAttemptAt,
+		"updated_at":      time.Now(),
+	}
+	if status == models.NotificationDeliveryStatusDelivered {
+		updates["delivered_at"] = time.Now()
+	}
+
+	return r.db.WithContext(ctx).Model(&models.NotificationDelivery{}).
+		Where("id = ?", deliveryID).
+		Updates(updates).Error
+}
+
+// DeferEmailDeliveries holds pending EMAIL deliveries back until the given time.
+func (r *NotificationRepository) DeferEmailDeliveries(ctx context.Context, deliveryIDs []string, until time.Time) error {
+	if len(deliveryIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&models.NotificationDelivery{}).
+		Where("id IN ?", deliveryIDs).
+		Updates(map[string]interface{}{"next_attempt_at": until, "updated_at": time.Now()}).Error
+}
+
 // Helper methods
 
 // applyFilters applies filtering conditions to a query
@@ -587,6 +701,7 @@ func (r *NotificationRepository) createDefaultPreferences(ctx context.Context, u
 		EnableEmailNotifications:  false,
 		MinimumPriority:           models.NotificationPriorityLow,
 		QuietHoursTimezone:        "Asia/Jakarta",
+		EmailDigestMode:           models.EmailDigestModeImmediate,
 	}
 
 	// Set default type preferences (all enabled)
@@ -624,3 +739,14 @@ type NotificationFilter struct {
 	Offset            int
 	OrderBy           string
 }
+
+// PendingEmailDelivery is a queued EMAIL delivery joined with its recipient.
+type PendingEmailDelivery struct {
+	DeliveryID     string
+	RetryCount     int
+	NotificationID string
+	UserID         string
+	UserName       string
+	UserEmail      string
+	Notification   *models.Notification `gorm:"-"`
+}
@@ -0,0 +1,504 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+
+	"agrinovagraphql/server/internal/notifications/models"
+	"agrinovagraphql/server/internal/notifications/repositories"
+	"agrinovagraphql/server/pkg/email"
+)
+
+const (
+	// emailTemplateMetadataKey stores the template a notification was created
+	// from so the email can be rendered with the template's email bodies.
+	emailTemplateMetadataKey = "templateName"
+
+	// emailMaxAttempts is how many sends are tried before a delivery is FAILED.
+	emailMaxAttempts = 3
+	// emailDispatchBatchSize caps the deliveries loaded per dispatch run.
+	emailDispatchBatchSize = 500
+	// emailDailyDigestHour is the local hour after which daily digests go out.
+	emailDailyDigestHour = 7
+	// emailRetryBackoff is multiplied by the attempt count to space out retries.
+	emailRetryBackoff = 5 * time.Minute
+)
+
+// EmailNotificationService sends queued EMAIL deliveries. Immediate and
+// CRITICAL notifications are sent one per email; recipients on HOURLY or DAILY
+// digest get a single summary email when their digest is due. Non-critical
+// email is held back during the recipient's quiet hours.
+type EmailNotificationService struct {
+	repo   *repositories.NotificationRepository
+	sender email.Sender
+}
+
+// EmailDispatchResult summarizes one dispatch run.
+type EmailDispatchResult struct {
+	Sent     int
+	Digests  int
+	Deferred int
+	Skipped  int
+	Failed   int
+}
+
+// NewEmailNotificationService creates a new email notification service
+func NewEmailNotificationService(repo *repositories.NotificationRepository, sender email.Sender) *EmailNotificationService {
+	return &EmailNotificationService{
+		repo:   repo,
+		sender: sender,
+	}
+}
+
+// Dispatch sends every pending email that is due at now.
+func (s *EmailNotificationService) Dispatch(ctx context.Context, now time.Time) (*EmailDispatchResult, error) {
+	result := &EmailDispatchResult{}
+	if s.repo == nil || s.sender == nil {
+		return result, fmt.Errorf("email notification service not initialized")
+	}
+
+	pending, err := s.repo.GetPendingEmailDeliveries(ctx, now, emailDispatchBatchSize)
+	if err != nil {
+		return result, fmt.Errorf("failed to load pending email deliveries: %w", err)
+	}
+
+	byUser := make(map[string][]*repositories.PendingEmailDelivery)
+	var userOrder []string
+	for _, delivery := range pending {
+		if _, ok := byUser[delivery.UserID]; !ok {
+			userOrder = append(userOrder, delivery.UserID)
+		}
+		byUser[delivery.UserID] = append(byUser[delivery.UserID], delivery)
+	}
+
+	templates := make(map[string]*models.NotificationTemplate)
+	for _, userID := range userOrder {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		s.dispatchForUser(ctx, now, byUser[userID], templates, result)
+	}
+
+	return result, nil
+}
+
+func (s *EmailNotificationService) dispatchForUser(
+	ctx context.Context,
+	now time.Time,
+	deliveries []*repositories.PendingEmailDelivery,
+	templates map[string]*models.NotificationTemplate,
+	result *EmailDispatchResult,
+) {
+	first := deliveries[0]
+	preferences, err := s.repo.GetUserPreferences(ctx, first.UserID)
+	if err != nil {
+		log.Printf("email dispatch: failed to load preferences for user %s: %v", first.UserID, err)
+		return
+	}
+
+	recipient := formatRecipient(first.UserName, first.UserEmail)
+
+	var digest, quiet []*repositories.PendingEmailDelivery
+	for _, delivery := range deliveries {
+		notification := delivery.Notification
+		switch {
+		case notification == nil || notification.IsExpired():
+			s.skip(ctx, delivery, "notification expired or missing", result)
+			continue
+		case recipient == "":
+			s.skip(ctx, delivery, "recipient has no valid email address", result)
+			continue
+		case !AllowsEmail(preferences, notification):
+			s.skip(ctx, delivery, "email disabled by recipient preferences", result)
+			continue
+		}
+
+		critical := notification.Priority == models.NotificationPriorityCritical
+		if !critical && InQuietHours(preferences, now) {
+			quiet = append(quiet, delivery)
+			continue
+		}
+
+		if critical || preferences.EmailDigestMode == "" || preferences.EmailDigestMode == models.EmailDigestModeImmediate {
+			message := s.renderNotification(ctx, recipient, first.UserName, notification, templates)
+			if s.send(ctx, now, message, []*repositories.PendingEmailDelivery{delivery}, result) {
+				result.Sent++
+			}
+			continue
+		}
+
+		digest = append(digest, delivery)
+	}
+
+	if len(quiet) > 0 {
+		s.deferUntil(ctx, quiet, QuietHoursEnd(preferences, now), result)
+	}
+	if len(digest) == 0 {
+		return
+	}
+	if !DigestDue(preferences, now) {
+		s.deferUntil(ctx, digest, NextDigestAt(preferences, now), result)
+		return
+	}
+
+	notifications := make([]*models.Notification, 0, len(digest))
+	for _, delivery := range digest {
+		notifications = append(notifications, delivery.Notification)
+	}
+	message := renderDigest(recipient, first.UserName, preferences.EmailDigestMode, notifications)
+	if s.send(ctx, now, message, digest, result) {
+		result.Digests++
+		result.Sent += len(digest)
+		if err := s.repo.UpdateLastEmailDigestAt(ctx, first.UserID, now); err != nil {
+			log.Printf("email dispatch: failed to record digest time for user %s: %v", first.UserID, err)
+		}
+	}
+}
+
+// send delivers one message and records the outcome on every delivery it
+// covers. Failed deliveries are retried with a growing backoff.
+func (s *EmailNotificationService) send(
+	ctx context.Context,
+	now time.Time,
+	message *email.Message,
+	deliveries []*repositories.PendingEmailDelivery,
+	result *EmailDispatchResult,
+) bool {
+	sendErr := s.sender.Send(ctx, message)
+
+	for _, delivery := range deliveries {
+		var err error
+		if sendErr == nil {
+			err = s.repo.UpdateEmailDelivery(ctx, delivery.DeliveryID, models.NotificationDeliveryStatusDelivered, delivery.RetryCount, "", nil)
+		} else {
+			attempts := delivery.RetryCount + 1
+			status := models.NotificationDeliveryStatusPending
+			retryAt := now.Add(time.Duration(attempts) * emailRetryBackoff)
+			nextAttemptAt := &retryAt
+			if attempts >= emailMaxAttempts {
+				status = models.NotificationDeliveryStatusFailed
+				nextAttemptAt = nil
+				result.Failed++
+			}
+			err = s.repo.UpdateEmailDelivery(ctx, delivery.DeliveryID, status, attempts, sendErr.Error(), nextAttemptAt)
+		}
+		if err != nil {
+			log.Printf("email dispatch: failed to update delivery %s: %v", delivery.DeliveryID, err)
+		}
+	}
+
+	if sendErr != nil {
+		log.Printf("email dispatch: send to %s failed: %v", strings.Join(message.To, ", "), sendErr)
+		return false
+	}
+	return true
+}
+
+func (s *EmailNotificationService) skip(ctx context.Context, delivery *repositories.PendingEmailDelivery, reason string, result *EmailDispatchResult) {
+	result.Skipped++
+	if err := s.repo.UpdateEmailDelivery(ctx, delivery.DeliveryID, models.NotificationDeliveryStatusSkipped, delivery.RetryCount, reason, nil); err != nil {
+		log.Printf("email dispatch: failed to skip delivery %s: %v", delivery.DeliveryID, err)
+	}
+}
+
+// deferUntil holds deliveries back so later dispatch runs do not load them
+// again before they can be sent.
+func (s *EmailNotificationService) deferUntil(ctx context.Context, deliveries []*repositories.PendingEmailDelivery, until time.Time, result *EmailDispatchResult) {
+	result.Deferred += len(deliveries)
+
+	ids := make([]string, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		ids = append(ids, delivery.DeliveryID)
+	}
+	if err := s.repo.DeferEmailDeliveries(ctx, ids, until); err != nil {
+		log.Printf("email dispatch: failed to defer %d delivery(ies): %v", len(ids), err)
+	}
+}
+
+// renderNotification renders a single notification, using the email bodies of
+// the template it was created from when present.
+func (s *EmailNotificationService) renderNotification(
+	ctx context.Context,
+	recipient string,
+	recipientName string,
+	notification *models.Notification,
+	templates map[string]*models.NotificationTemplate,
+) *email.Message {
+	metadata, _ := notification.GetMetadataMap()
+
+	var template *models.NotificationTemplate
+	if name, ok := metadata[emailTemplateMetadataKey].(string); ok && name != "" {
+		cached, seen := templates[name]
+		if !seen {
+			cached, _ = s.repo.GetTemplateByName(ctx, name)
+			templates[name] = cached
+		}
+		template = cached
+	}
+
+	return RenderNotificationEmail(recipient, recipientName, notification, template)
+}
+
+// AllowsEmail reports whether the preferences accept this notification by email.
+func AllowsEmail(preferences *models.NotificationPreferences, notification *models.Notification) bool {
+	if preferences == nil || notification == nil || !preferences.EnableEmailNotifications {
+		return false
+	}
+	return allowsTypeAndPriority(preferences, notification)
+}
+
+// allowsTypeAndPriority applies the per-type toggles and minimum priority.
+func allowsTypeAndPriority(preferences *models.NotificationPreferences, notification *models.Notification) bool {
+	if preferences.TypePreferences != "" {
+		var typePrefs map[string]bool
+		if err := json.Unmarshal([]byte(preferences.TypePreferences), &typePrefs); err == nil {
+			if enabled, ok := typePrefs[string(notification.Type)]; ok && !enabled {
+				return false
+			}
+		}
+	}
+
+	return priorityRank(notification.Priority) >= priorityRank(preferences.MinimumPriority)
+}
+
+// InQuietHours reports whether now falls inside the recipient's quiet hours.
+// Ranges that wrap past midnight (e.g. 22:00-06:00) are supported.
+func InQuietHours(preferences *models.NotificationPreferences, now time.Time) bool {
+	if preferences == nil {
+		return false
+	}
+	start, okStart := parseClock(preferences.QuietHoursStart)
+	end, okEnd := parseClock(preferences.QuietHoursEnd)
+	if !okStart || !okEnd || start == end {
+		return false
+	}
+
+	local := now.In(preferenceLocation(preferences))
+	minute := local.Hour()*60 + local.Minute()
+	if start < end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+// QuietHoursEnd returns when the quiet hours that contain now end.
+func QuietHoursEnd(preferences *models.NotificationPreferences, now time.Time) time.Time {
+	end, ok := parseClock(preferences.QuietHoursEnd)
+	if !ok {
+		return now
+	}
+	local := now.In(preferenceLocation(preferences))
+	next := time.Date(local.Year(), local.Month(), local.Day(), end/60, end%60, 0, 0, local.Location())
+	if !next.After(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// NextDigestAt returns when the next HOURLY or DAILY digest falls due after now.
+func NextDigestAt(preferences *models.NotificationPreferences, now time.Time) time.Time {
+	switch preferences.EmailDigestMode {
+	case models.EmailDigestModeHourly:
+		if preferences.LastEmailDigestAt != nil {
+			return preferences.LastEmailDigestAt.Add(time.Hour)
+		}
+		return now
+	case models.EmailDigestModeDaily:
+		local := now.In(preferenceLocation(preferences))
+		next := time.Date(local.Year(), local.Month(), local.Day(), emailDailyDigestHour, 0, 0, 0, local.Location())
+		if !next.After(local) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next
+	default:
+		return now
+	}
+}
+
+// DigestDue reports whether an HOURLY or DAILY digest should be sent at now.
+func DigestDue(preferences *models.NotificationPreferences, now time.Time) bool {
+	if preferences == nil {
+		return false
+	}
+	last := preferences.LastEmailDigestAt
+
+	switch preferences.EmailDigestMode {
+	case models.EmailDigestModeHourly:
+		return last == nil || !now.Before(last.Add(time.Hour))
+	case models.EmailDigestModeDaily:
+		location := preferenceLocation(preferences)
+		local := now.In(location)
+		if local.Hour() < emailDailyDigestHour {
+			return false
+		}
+		if last == nil {
+			return true
+		}
+		lastLocal := last.In(location)
+		ly, lm, ld := lastLocal.Date()
+		y, m, d := local.Date()
+		return ly != y || lm != m || ld != d
+	default:
+		return true
+	}
+}
+
+// RenderNotificationEmail builds the email for one notification. Template
+// email bodies may use {{title}}, {{message}}, {{recipientName}},
+// {{actionUrl}} and any notification metadata key.
+func RenderNotificationEmail(recipient, recipientName string, notification *models.Notification, template *models.NotificationTemplate) *email.Message {
+	variables, _ := notification.GetMetadataMap()
+	if variables == nil {
+		variables = make(map[string]interface{})
+	}
+	variables["title"] = notification.Title
+	variables["message"] = notification.Message
+	variables["recipientName"] = recipientName
+	variables["actionUrl"] = notification.ActionURL
+	variables["priority"] = string(notification.Priority)
+
+	subject := notification.Title
+	text := defaultEmailText(recipientName, notification)
+	htmlBody := defaultEmailHTML(recipientName, notification)
+
+	if template != nil {
+		if template.EmailSubjectTemplate != "" {
+			subject = renderEmailTemplate(template.EmailSubjectTemplate, variables, false)
+		}
+		if template.EmailTextTemplate != "" {
+			text = renderEmailTemplate(template.EmailTextTemplate, variables, false)
+		}
+		if template.EmailHTMLTemplate != "" {
+			htmlBody = renderEmailTemplate(template.EmailHTMLTemplate, variables, true)
+		}
+	}
+
+	if notification.Priority == models.NotificationPriorityCritical {
+		subject = "[PENTING] " + subject
+	}
+
+	return &email.Message{
+		To:       []string{recipient},
+		Subject:  subject,
+		TextBody: text,
+		HTMLBody: htmlBody,
+	}
+}
+
+func renderDigest(recipient, recipientName, mode string, notifications []*models.Notification) *email.Message {
+	period := "harian"
+	if mode == models.EmailDigestModeHourly {
+		period = "per jam"
+	}
+	subject := fmt.Sprintf("Ringkasan notifikasi %s (%d)", period, len(notifications))
+
+	var text, htmlBody strings.Builder
+	fmt.Fprintf(&text, "Halo %s,\n\nAnda memiliki %d notifikasi baru:\n\n", displayName(recipientName), len(notifications))
+	fmt.Fprintf(&htmlBody, "<p>Halo %s,</p><p>Anda memiliki %d notifikasi baru:</p><ul>",
+		html.EscapeString(displayName(recipientName)), len(notifications))
+
+	for _, notification := range notifications {
+		fmt.Fprintf(&text, "- %s\n  %s\n", notification.Title, notification.Message)
+		fmt.Fprintf(&htmlBody, "<li><strong>%s</strong><br>%s</li>",
+			html.EscapeString(notification.Title), html.EscapeString(notification.Message))
+	}
+	htmlBody.WriteString("</ul>")
+
+	return &email.Message{
+		To:       []string{recipient},
+		Subject:  subject,
+		TextBody: text.String(),
+		HTMLBody: htmlBody.String(),
+	}
+}
+
+func defaultEmailText(recipientName string, notification *models.Notification) string {
+	text := fmt.Sprintf("Halo %s,\n\n%s\n\n%s\n", displayName(recipientName), notification.Title, notification.Message)
+	if notification.ActionURL != "" {
+		text += "\n" + notification.ActionURL + "\n"
+	}
+	return text
+}
+
+func defaultEmailHTML(recipientName string, notification *models.Notification) string {
+	body := fmt.Sprintf("<p>Halo %s,</p><h3>%s</h3><p>%s</p>",
+		html.EscapeString(displayName(recipientName)),
+		html.EscapeString(notification.Title),
+		html.EscapeString(notification.Message))
+	if notification.ActionURL != "" {
+		label := notification.ActionLabel
+		if label == "" {
+			label = "Lihat detail"
+		}
+		body += fmt.Sprintf(`<p><a href="%s">%s</a></p>`, html.EscapeString(notification.ActionURL), html.EscapeString(label))
+	}
+	return body
+}
+
+// renderEmailTemplate replaces {{key}} placeholders, escaping values for HTML.
+func renderEmailTemplate(template string, variables map[string]interface{}, escapeHTML bool) string {
+	result := template
+	for key, value := range variables {
+		replacement := fmt.Sprintf("%v", value)
+		if escapeHTML {
+			replacement = html.EscapeString(replacement)
+		}
+		result = strings.ReplaceAll(result, "{{"+key+"}}", replacement)
+	}
+	return result
+}
+
+func formatRecipient(name, address string) string {
+	parsed, err := mail.ParseAddress(strings.TrimSpace(address))
+	if err != nil {
+		return ""
+	}
+	parsed.Name = strings.TrimSpace(name)
+	return parsed.String()
+}
+
+func displayName(name string) string {
+	if strings.TrimSpace(name) == "" {
+		return "Pengguna"
+	}
+	return name
+}
+
+func priorityRank(priority models.NotificationPriority) int {
+	switch priority {
+	case models.NotificationPriorityMedium:
+		return 1
+	case models.NotificationPriorityHigh:
+		return 2
+	case models.NotificationPriorityCritical:
+		return 3
+	default:
+		return 0
+	}
+}
+
+func parseClock(value string) (int, bool) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, false
+	}
+	return parsed.Hour()*60 + parsed.Minute(), true
+}
+
+func preferenceLocation(preferences *models.NotificationPreferences) *time.Location {
+	if preferences.QuietHoursTimezone != "" {
+		if location, err := time.LoadLocation(preferences.QuietHoursTimezone); err == nil {
+			return location
+		}
+	}
+	if location, err := time.LoadLocation("Asia/Jakarta"); err == nil {
+		return location
+	}
+	return time.UTC
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"agrinovagraphql/server/internal/notifications/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInQuietHours(t *testing.T) {
+	prefs := &models.NotificationPreferences{
+		QuietHoursStart:    "22:00",
+		QuietHoursEnd:      "06:00",
+		QuietHoursTimezone: "Asia/Jakarta",
+	}
+
+	// 23:30 WIB
+	assert.True(t, InQuietHours(prefs, time.Date(2026, 3, 2, 16, 30, 0, 0, time.UTC)))
+	// 05:59 WIB
+	assert.True(t, InQuietHours(prefs, time.Date(2026, 3, 1, 22, 59, 0, 0, time.UTC)))
+	// 06:00 WIB
+	assert.False(t, InQuietHours(prefs, time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC)))
+
+	prefs.QuietHoursStart, prefs.QuietHoursEnd = "12:00", "13:00"
+	assert.True(t, InQuietHours(prefs, time.Date(2026, 3, 2, 5, 15, 0, 0, time.UTC)))
+	assert.False(t, InQuietHours(prefs, time.Date(2026, 3, 2, 6, 0, 0, 0, time.UTC)))
+
+	prefs.QuietHoursStart = ""
+	assert.False(t, InQuietHours(prefs, time.Date(2026, 3, 2, 5, 15, 0, 0, time.UTC)))
+}
+
+func TestDigestDue(t *testing.T) {
+	now := time.Date(2026, 3, 2, 3, 0, 0, 0, time.UTC) // 10:00 WIB
+
+	hourly := &models.NotificationPreferences{EmailDigestMode: models.EmailDigestModeHourly}
+	assert.True(t, DigestDue(hourly, now))
+	last := now.Add(-30 * time.Minute)
+	hourly.LastEmailDigestAt = &last
+	assert.False(t, DigestDue(hourly, now))
+	last = now.Add(-time.Hour)
+	assert.True(t, DigestDue(hourly, now))
+
+	daily := &models.NotificationPreferences{EmailDigestMode: models.EmailDigestModeDaily, QuietHoursTimezone: "Asia/Jakarta"}
+	assert.True(t, DigestDue(daily, now))
+	assert.False(t, DigestDue(daily, time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC))) // 06:00 WIB
+	sentToday := time.Date(2026, 3, 2, 0, 30, 0, 0, time.UTC)                       // 07:30 WIB
+	daily.LastEmailDigestAt = &sentToday
+	assert.False(t, DigestDue(daily, now))
+	sentYesterday := sentToday.Add(-24 * time.Hour)
+	daily.LastEmailDigestAt = &sentYesterday
+	assert.True(t, DigestDue(daily, now))
+}
+
+func TestQuietHoursEnd(t *testing.T) {
+	prefs := &models.NotificationPreferences{
+		QuietHoursStart:    "22:00",
+		QuietHoursEnd:      "06:00",
+		QuietHoursTimezone: "Asia/Jakarta",
+	}
+
+	// 23:30 WIB ends at 06:00 WIB the next morning.
+	assert.Equal(t, time.Date(2026, 3, 2, 23, 0, 0, 0, time.UTC), QuietHoursEnd(prefs, time.Date(2026, 3, 2, 16, 30, 0, 0, time.UTC)).UTC())
+	// 05:00 WIB ends the same morning.
+	assert.Equal(t, time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC), QuietHoursEnd(prefs, time.Date(2026, 3, 1, 22, 0, 0, 0, time.UTC)).UTC())
+}
+
+func TestNextDigestAt(t *testing.T) {
+	now := time.Date(2026, 3, 2, 3, 0, 0, 0, time.UTC) // 10:00 WIB
+
+	last := now.Add(-20 * time.Minute)
+	hourly := &models.NotificationPreferences{EmailDigestMode: models.EmailDigestModeHourly, LastEmailDigestAt: &last}
+	assert.Equal(t, last.Add(time.Hour), NextDigestAt(hourly, now))
+
+	daily := &models.NotificationPreferences{EmailDigestMode: models.EmailDigestModeDaily, QuietHoursTimezone: "Asia/Jakarta"}
+	// Sent today already: tomorrow 07:00 WIB.
+	assert.Equal(t, time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), NextDigestAt(daily, now).UTC())
+	// 05:00 WIB: today 07:00 WIB.
+	assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), NextDigestAt(daily, time.Date(2026, 3, 1, 22, 0, 0, 0, time.UTC)).UTC())
+}
+
+func TestAllowsEmail(t *testing.T) {
+	prefs := &models.NotificationPreferences{
+		EnableEmailNotifications: true,
+		MinimumPriority:          models.NotificationPriorityHigh,
+		TypePreferences:          `{"HARVEST_CREATED":false,"SYSTEM_ALERT":true}`,
+	}
+
+	assert.True(t, AllowsEmail(prefs, &models.Notification{Type: models.NotificationTypeSystemAlert, Priority: models.NotificationPriorityCritical}))
+	assert.False(t, AllowsEmail(prefs, &models.Notification{Type: models.NotificationTypeSystemAlert, Priority: models.NotificationPriorityMedium}))
+	assert.False(t, AllowsEmail(prefs, &models.Notification{Type: models.NotificationTypeHarvestCreated, Priority: models.NotificationPriorityHigh}))
+
+	prefs.EnableEmailNotifications = false
+	assert.False(t, AllowsEmail(prefs, &models.Notification{Type: models.NotificationTypeSystemAlert, Priority: models.NotificationPriorityCritical}))
+}
+
+func TestRenderNotificationEmail(t *testing.T) {
+	notification := &models.Notification{
+		Title:    "Panen disetujui",
+		Message:  "Panen Blok A1 <5 ton> disetujui",
+		Priority: models.NotificationPriorityMedium,
+	}
+	_ = notification.SetMetadataMap(map[string]interface{}{"blockName": "A1 & B2"})
+
+	message := RenderNotificationEmail("budi@agrinova.test", "Budi", notification, nil)
+	assert.Equal(t, "Panen disetujui", message.Subject)
+	assert.Contains(t, message.TextBody, "Panen Blok A1 <5 ton> disetujui")
+	assert.Contains(t, message.HTMLBody, "&lt;5 ton&gt;")
+
+	template := &models.NotificationTemplate{
+		EmailSubjectTemplate: "Blok {{blockName}}: {{title}}",
+		EmailHTMLTemplate:    "<p>{{recipientName}} - {{blockName}}</p>",
+	}
+	message = RenderNotificationEmail("budi@agrinova.test", "Budi", notification, template)
+	assert.Equal(t, "Blok A1 & B2: Panen disetujui", message.Subject)
+	assert.Equal(t, "<p>Budi - A1 &amp; B2</p>", message.HTMLBody)
+	assert.Contains(t, message.TextBody, "Halo Budi")
+
+	notification.Priority = models.NotificationPriorityCritical
+	message = RenderNotificationEmail("budi@agrinova.test", "Budi", notification, nil)
+	assert.Equal(t, "[PENTING] Panen disetujui", message.Subject)
+}
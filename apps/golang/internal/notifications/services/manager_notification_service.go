@@ -276,6 +276,32 @@ func (s *ManagerNotificationService) SendDailySummaryToAllManagers(ctx context.C
 	return sentCount, nil
 }
 
+// SendDailySummaryToCompanyManagers sends daily summary to the active managers of a company
+func (s *ManagerNotificationService) SendDailySummaryToCompanyManagers(ctx context.Context, companyID string) (int, error) {
+	var managerIDs []string
+	err := s.db.WithContext(ctx).Raw(`
+		SELECT DISTINCT u.id
+		FROM users u
+		JOIN user_company_assignments uca ON uca.user_id = u.id AND uca.is_active = true
+		WHERE u.role = 'MANAGER' AND u.is_active = true AND u.deleted_at IS NULL
+			AND uca.company_id = ?
+	`, companyID).Scan(&managerIDs).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to query company managers: %w", err)
+	}
+
+	sentCount := 0
+	for _, managerID := range managerIDs {
+		if err := s.SendDailySummary(ctx, managerID); err != nil {
+			log.Printf("Failed to send summary to manager %s: %v", managerID, err)
+			continue
+		}
+		sentCount++
+	}
+
+	return sentCount, nil
+}
+
 // Helper functions
 func stringValue(s *string) string {
 	if s == nil {
@@ -0,0 +1,701 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	authServices "agrinovagraphql/server/internal/auth/services"
+	"agrinovagraphql/server/internal/notifications/models"
+	"agrinovagraphql/server/pkg/fcm"
+)
+
+const (
+	// routingHierarchyMaxDepth bounds the supervisor walk for HIERARCHY scope.
+	routingHierarchyMaxDepth = 5
+	// routingEscalationBatchSize caps the open dispatch groups checked per run.
+	routingEscalationBatchSize = 500
+)
+
+// ErrRoutingRuleNotFound is returned when a routing rule does not exist.
+var ErrRoutingRuleNotFound = errors.New("notification routing rule not found")
+
+// NotificationRoutingService fans notifications out according to
+// company-configurable routing rules and escalates them when they stay unread.
+// Recipient preferences (type toggles, minimum priority, quiet hours) are
+// applied here rather than left to the clients.
+type NotificationRoutingService struct {
+	db                  *gorm.DB
+	notificationService *NotificationService
+	fcmProvider         *fcm.FCMProvider
+	hierarchyService    *authServices.HierarchyService
+	payloadBuilder      *fcm.PayloadBuilder
+}
+
+// RoutingEvent describes something that happened and may need to be routed.
+type RoutingEvent struct {
+	Type              models.NotificationType
+	Priority          models.NotificationPriority
+	Title             string
+	Message           string
+	CompanyID         string
+	EstateID          string
+	ActorID           string
+	ActorRole         string
+	RelatedEntityType string
+	RelatedEntityID   string
+	ActionURL         string
+	ActionLabel       string
+	Metadata          map[string]interface{}
+
+	// SubjectID is the user the event is about when that is not the actor,
+	// e.g. the mandor whose harvest was approved. HIERARCHY scope then starts
+	// at the subject and includes them when they hold a listed role.
+	SubjectID string
+	// IdempotencyKey makes routing the same event again a no-op for the
+	// recipients it already reached, so retried deliveries do not duplicate
+	// notifications. Initial notifications carry it as their idempotency key.
+	IdempotencyKey string
+}
+
+// RoutingResult summarizes one routed event.
+type RoutingResult struct {
+	Matched       bool
+	Rules         int
+	Notifications int
+	PushSent      int
+	Suppressed    int
+}
+
+// EscalationResult summarizes one escalation pass.
+type EscalationResult struct {
+	Escalated     int
+	Notifications int
+	Closed        int
+}
+
+type routingRecipient struct {
+	ID   string `gorm:"column:id"`
+	Role string `gorm:"column:role"`
+}
+
+type routingDispatchGroup struct {
+	EventKey       string    `gorm:"column:event_key"`
+	RuleID         string    `gorm:"column:rule_id"`
+	Step           int       `gorm:"column:step"`
+	StartedAt      time.Time `gorm:"column:started_at"`
+	NotificationID string    `gorm:"column:notification_id"`
+	CompanyID      string    `gorm:"column:company_id"`
+	EstateID       string    `gorm:"column:estate_id"`
+	ActorID        string    `gorm:"column:actor_id"`
+	SubjectID      string    `gorm:"column:subject_id"`
+	Acknowledged   bool      `gorm:"column:acknowledged"`
+}
+
+// NewNotificationRoutingService creates a new routing service. fcmProvider and
+// hierarchyService may be nil; MOBILE channels are then skipped and HIERARCHY
+// scope resolves no recipients.
+func NewNotificationRoutingService(
+	db *gorm.DB,
+	notificationService *NotificationService,
+	fcmProvider *fcm.FCMProvider,
+	hierarchyService *authServices.HierarchyService,
+) *NotificationRoutingService {
+	return &NotificationRoutingService{
+		db:                  db,
+		notificationService: notificationService,
+		fcmProvider:         fcmProvider,
+		hierarchyService:    hierarchyService,
+		payloadBuilder:      fcm.NewPayloadBuilder(),
+	}
+}
+
+// Route delivers the event to the recipients of every matching rule. When no
+// rule matches, Matched is false and callers fall back to their built-in
+// fan-out.
+func (s *NotificationRoutingService) Route(ctx context.Context, event *RoutingEvent) (*RoutingResult, error) {
+	result := &RoutingResult{}
+	if s == nil || s.db == nil || s.notificationService == nil || event == nil {
+		return result, nil
+	}
+
+	var candidates []*models.NotificationRoutingRule
+	query := s.db.WithContext(ctx).
+		Where("is_active = ? AND event_type = ?", true, event.Type)
+	if event.CompanyID != "" {
+		query = query.Where("company_id = ? OR company_id IS NULL", event.CompanyID)
+	} else {
+		query = query.Where("company_id IS NULL")
+	}
+	if err := query.Order("created_at ASC").Find(&candidates).Error; err != nil {
+		return result, fmt.Errorf("failed to load routing rules: %w", err)
+	}
+
+	rules := SelectRoutingRules(candidates, event.CompanyID, event.Type, event.Priority)
+	if len(rules) == 0 {
+		return result, nil
+	}
+	result.Matched = true
+	result.Rules = len(rules)
+
+	eventKey := uuid.NewString()
+	if event.IdempotencyKey != "" {
+		eventKey = uuid.NewSHA1(uuid.NameSpaceOID, []byte(event.IdempotencyKey)).String()
+	}
+	notified := make(map[string]struct{})
+	for _, rule := range rules {
+		recipients, err := s.resolveRecipients(ctx, rule.Scope, rule.RoleList(), event)
+		if err != nil {
+			return result, err
+		}
+		for _, recipient := range recipients {
+			if _, seen := notified[recipient.ID]; seen || recipient.ID == event.ActorID {
+				continue
+			}
+			notified[recipient.ID] = struct{}{}
+			if err := s.deliver(ctx, rule, 0, eventKey, recipient, rule.ChannelList(), event, result); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// ProcessEscalations runs the next escalation step for every routed event
+// whose notifications are all still unread after the step's delay.
+func (s *NotificationRoutingService) ProcessEscalations(ctx context.Context, now time.Time) (*EscalationResult, error) {
+	result := &EscalationResult{}
+	if s == nil || s.db == nil || s.notificationService == nil {
+		return result, nil
+	}
+
+	var groups []routingDispatchGroup
+	if err := s.db.WithContext(ctx).Raw(`
+		SELECT
+			d.event_key,
+			d.rule_id,
+			d.step,
+			MIN(d.created_at) AS started_at,
+			MIN(d.notification_id) AS notification_id,
+			COALESCE(MAX(d.company_id), '') AS company_id,
+			COALESCE(MAX(d.estate_id), '') AS estate_id,
+			COALESCE(MAX(d.actor_id), '') AS actor_id,
+			COALESCE(MAX(d.subject_id), '') AS subject_id,
+			BOOL_OR(n.status <> ?) AS acknowledged
+		FROM notification_routing_dispatches d
+		JOIN notifications n ON n.id = d.notification_id
+		WHERE d.closed_at IS NULL
+		GROUP BY d.event_key, d.rule_id, d.step
+		ORDER BY MIN(d.created_at) ASC
+		LIMIT ?
+	`, models.NotificationStatusUnread, routingEscalationBatchSize).Scan(&groups).Error; err != nil {
+		return result, fmt.Errorf("failed to load open routing dispatches: %w", err)
+	}
+
+	rules := make(map[string]*models.NotificationRoutingRule)
+	for _, group := range groups {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		rule, ok := rules[group.RuleID]
+		if !ok {
+			rule, _ = s.GetRule(ctx, group.RuleID)
+			rules[group.RuleID] = rule
+		}
+
+		var chain []models.RoutingEscalationStep
+		if rule != nil && rule.IsActive {
+			chain = rule.EscalationChain()
+		}
+		if group.Acknowledged || group.Step >= len(chain) {
+			if err := s.closeDispatchGroup(ctx, group, now); err != nil {
+				return result, err
+			}
+			result.Closed++
+			continue
+		}
+
+		next := chain[group.Step]
+		if now.Before(group.StartedAt.Add(time.Duration(next.AfterMinutes) * time.Minute)) {
+			continue
+		}
+
+		// A step whose scope holds nobody right now must not end the chain;
+		// the following step runs in its place.
+		sent := 0
+		for step := group.Step; step < len(chain) && sent == 0; step++ {
+			var err error
+			sent, err = s.escalate(ctx, rule, group, step, chain[step])
+			if err != nil {
+				return result, err
+			}
+		}
+		if err := s.closeDispatchGroup(ctx, group, now); err != nil {
+			return result, err
+		}
+		result.Escalated++
+		result.Notifications += sent
+	}
+
+	return result, nil
+}
+
+func (s *NotificationRoutingService) escalate(
+	ctx context.Context,
+	rule *models.NotificationRoutingRule,
+	group routingDispatchGroup,
+	index int,
+	step models.RoutingEscalationStep,
+) (int, error) {
+	source, err := s.notificationService.GetNotificationByID(ctx, group.NotificationID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load escalated notification %s: %w", group.NotificationID, err)
+	}
+
+	priority := source.Priority
+	if step.Priority != "" {
+		priority = step.Priority
+	}
+	metadata, _ := source.GetMetadataMap()
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	metadata["escalationStep"] = index + 1
+	metadata["escalatedFromNotificationId"] = source.ID
+
+	event := &RoutingEvent{
+		Type:              source.Type,
+		Priority:          priority,
+		Title:             "Eskalasi: " + strings.TrimPrefix(source.Title, "Eskalasi: "),
+		Message:           source.Message,
+		CompanyID:         group.CompanyID,
+		EstateID:          group.EstateID,
+		ActorID:           group.ActorID,
+		SubjectID:         group.SubjectID,
+		RelatedEntityType: source.RelatedEntityType,
+		RelatedEntityID:   source.RelatedEntityID,
+		ActionURL:         source.ActionURL,
+		ActionLabel:       source.ActionLabel,
+		Metadata:          metadata,
+	}
+
+	recipients, err := s.resolveRecipients(ctx, step.Scope, step.RecipientRoles, event)
+	if err != nil {
+		return 0, err
+	}
+
+	result := &RoutingResult{}
+	for _, recipient := range recipients {
+		if recipient.ID == group.ActorID {
+			continue
+		}
+		if err := s.deliver(ctx, rule, index+1, group.EventKey, recipient, step.Channels, event, result); err != nil {
+			return result.Notifications, err
+		}
+	}
+	return result.Notifications, nil
+}
+
+// deliver creates the in-app notification for one recipient, queues email and
+// sends push as the channels and the recipient's preferences allow, and records
+// the dispatch for escalation.
+func (s *NotificationRoutingService) deliver(
+	ctx context.Context,
+	rule *models.NotificationRoutingRule,
+	step int,
+	eventKey string,
+	recipient routingRecipient,
+	channels []string,
+	event *RoutingEvent,
+	result *RoutingResult,
+) error {
+	if event.IdempotencyKey != "" {
+		var delivered int64
+		if err := s.db.WithContext(ctx).Model(&models.NotificationRoutingDispatch{}).
+			Where("event_key = ? AND rule_id = ? AND step = ? AND recipient_id = ?", eventKey, rule.ID, step, recipient.ID).
+			Count(&delivered).Error; err != nil {
+			return fmt.Errorf("failed to check routing dispatch: %w", err)
+		}
+		if delivered > 0 {
+			return nil
+		}
+	}
+
+	preferences, err := s.notificationService.GetUserPreferences(ctx, recipient.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load preferences for %s: %w", recipient.ID, err)
+	}
+
+	if !allowsTypeAndPriority(preferences, &models.Notification{Type: event.Type, Priority: event.Priority}) {
+		result.Suppressed++
+		return nil
+	}
+
+	metadata := make(map[string]interface{}, len(event.Metadata)+2)
+	for key, value := range event.Metadata {
+		metadata[key] = value
+	}
+	metadata["routingRuleId"] = rule.ID
+	metadata["routingEventKey"] = eventKey
+
+	idempotencyKey := fmt.Sprintf("routing:%s:%d:%s", eventKey, step, recipient.ID)
+	if step == 0 && event.IdempotencyKey != "" {
+		idempotencyKey = event.IdempotencyKey
+	}
+
+	notification, err := s.notificationService.CreateNotification(ctx, &CreateNotificationInput{
+		Type:               event.Type,
+		Priority:           event.Priority,
+		Title:              event.Title,
+		Message:            event.Message,
+		IdempotencyKey:     idempotencyKey,
+		RecipientID:        recipient.ID,
+		RecipientRole:      recipient.Role,
+		RecipientCompanyID: event.CompanyID,
+		RelatedEntityType:  event.RelatedEntityType,
+		RelatedEntityID:    event.RelatedEntityID,
+		ActionURL:          event.ActionURL,
+		ActionLabel:        event.ActionLabel,
+		Metadata:           metadata,
+		SenderID:           event.ActorID,
+		SenderRole:         event.ActorRole,
+		SkipEmail:          !containsString(channels, models.NotificationDeliveryChannelEmail),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create routed notification for %s: %w", recipient.ID, err)
+	}
+	result.Notifications++
+
+	if containsString(channels, models.NotificationDeliveryChannelMobile) {
+		if AllowsChannel(preferences, models.NotificationDeliveryChannelMobile, notification, time.Now()) {
+			if s.sendPush(ctx, recipient.ID, notification) {
+				result.PushSent++
+			}
+		} else {
+			result.Suppressed++
+		}
+	}
+
+	dispatch := &models.NotificationRoutingDispatch{
+		EventKey:       eventKey,
+		RuleID:         rule.ID,
+		Step:           step,
+		NotificationID: notification.ID,
+		RecipientID:    recipient.ID,
+		CompanyID:      event.CompanyID,
+		EstateID:       event.EstateID,
+		ActorID:        event.ActorID,
+		SubjectID:      event.SubjectID,
+	}
+	if err := s.db.WithContext(ctx).Create(dispatch).Error; err != nil {
+		return fmt.Errorf("failed to record routing dispatch: %w", err)
+	}
+	return nil
+}
+
+func (s *NotificationRoutingService) sendPush(ctx context.Context, userID string, notification *models.Notification) bool {
+	if s.fcmProvider == nil || s.hierarchyService == nil {
+		return false
+	}
+
+	tokens, err := s.hierarchyService.GetUserTokens(ctx, userID)
+	if err != nil || len(tokens) == 0 {
+		return false
+	}
+
+	payload := s.payloadBuilder.ForRoutedNotification(string(notification.Type), notification.Title, notification.Message, notification.ActionURL)
+	sendResult, err := s.fcmProvider.SendToTokens(ctx, tokens, payload)
+	if err != nil {
+		log.Printf("routing: failed to send push to %s: %v", userID, err)
+		return false
+	}
+	if len(sendResult.FailedTokens) > 0 {
+		if cleanupErr := s.hierarchyService.CleanupInvalidTokens(ctx, sendResult.FailedTokens); cleanupErr != nil {
+			log.Printf("routing: failed to cleanup invalid tokens: %v", cleanupErr)
+		}
+	}
+	return sendResult.SuccessCount > 0
+}
+
+// resolveRecipients returns active users holding one of roles within scope.
+func (s *NotificationRoutingService) resolveRecipients(ctx context.Context, scope string, roles []string, event *RoutingEvent) ([]routingRecipient, error) {
+	if len(roles) == 0 {
+		return nil, nil
+	}
+
+	if scope == models.RoutingScopeHierarchy {
+		return s.resolveHierarchyRecipients(ctx, roles, event)
+	}
+	if scope == models.RoutingScopeEstate && event.EstateID == "" {
+		scope = models.RoutingScopeCompany
+	}
+	if event.CompanyID == "" {
+		return nil, nil
+	}
+
+	query := s.db.WithContext(ctx).
+		Table("users AS u").
+		Select("DISTINCT u.id AS id, u.role AS role").
+		Where("u.is_active = ? AND u.deleted_at IS NULL", true).
+		Where("u.role IN ?", roles)
+
+	if scope == models.RoutingScopeEstate {
+		query = query.
+			Joins("JOIN user_estate_assignments AS uea ON uea.user_id = u.id AND uea.is_active = ?", true).
+			Where("uea.estate_id = ?", event.EstateID)
+	} else {
+		query = query.
+			Joins("JOIN user_company_assignments AS uca ON uca.user_id = u.id AND uca.is_active = ?", true).
+			Where("uca.company_id = ?", event.CompanyID)
+	}
+
+	var recipients []routingRecipient
+	if err := query.Scan(&recipients).Error; err != nil {
+		return nil, fmt.Errorf("failed to resolve routing recipients: %w", err)
+	}
+	return recipients, nil
+}
+
+func (s *NotificationRoutingService) resolveHierarchyRecipients(ctx context.Context, roles []string, event *RoutingEvent) ([]routingRecipient, error) {
+	origin := event.SubjectID
+	if origin == "" {
+		origin = event.ActorID
+	}
+	if s.hierarchyService == nil || origin == "" {
+		return nil, nil
+	}
+
+	var recipients []routingRecipient
+	if event.SubjectID != "" {
+		var subject routingRecipient
+		err := s.db.WithContext(ctx).
+			Table("users").
+			Select("id, role").
+			Where("id = ? AND is_active = ? AND deleted_at IS NULL", event.SubjectID, true).
+			Limit(1).
+			Scan(&subject).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to load routing subject: %w", err)
+		}
+		if subject.ID != "" && containsString(roles, subject.Role) {
+			recipients = append(recipients, subject)
+		}
+	}
+
+	current := origin
+	for depth := 0; depth < routingHierarchyMaxDepth; depth++ {
+		parent, err := s.hierarchyService.GetParent(ctx, current)
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk hierarchy: %w", err)
+		}
+		if parent == nil || parent.ID == origin {
+			break
+		}
+		if containsString(roles, string(parent.Role)) {
+			recipients = append(recipients, routingRecipient{ID: parent.ID, Role: string(parent.Role)})
+		}
+		current = parent.ID
+	}
+	return recipients, nil
+}
+
+func (s *NotificationRoutingService) closeDispatchGroup(ctx context.Context, group routingDispatchGroup, now time.Time) error {
+	err := s.db.WithContext(ctx).Model(&models.NotificationRoutingDispatch{}).
+		Where("event_key = ? AND rule_id = ? AND step = ? AND closed_at IS NULL", group.EventKey, group.RuleID, group.Step).
+		Update("closed_at", now).Error
+	if err != nil {
+		return fmt.Errorf("failed to close routing dispatch %s: %w", group.EventKey, err)
+	}
+	return nil
+}
+
+// Rule management
+
+// ListRules returns the rules of a company, or the global rules when
+// companyID is nil.
+func (s *NotificationRoutingService) ListRules(ctx context.Context, companyID *string) ([]*models.NotificationRoutingRule, error) {
+	query := s.db.WithContext(ctx).Order("event_type ASC, created_at ASC")
+	if companyID != nil {
+		query = query.Where("company_id = ?", *companyID)
+	} else {
+		query = query.Where("company_id IS NULL")
+	}
+
+	var rules []*models.NotificationRoutingRule
+	if err := query.Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list routing rules: %w", err)
+	}
+	return rules, nil
+}
+
+// GetRule returns a routing rule by ID.
+func (s *NotificationRoutingService) GetRule(ctx context.Context, id string) (*models.NotificationRoutingRule, error) {
+	var rule models.NotificationRoutingRule
+	if err := s.db.WithContext(ctx).First(&rule, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoutingRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to get routing rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// SaveRule validates and creates or updates a routing rule.
+func (s *NotificationRoutingService) SaveRule(ctx context.Context, rule *models.NotificationRoutingRule) error {
+	if err := ValidateRoutingRule(rule); err != nil {
+		return err
+	}
+	if err := s.db.WithContext(ctx).Save(rule).Error; err != nil {
+		return fmt.Errorf("failed to save routing rule: %w", err)
+	}
+	return nil
+}
+
+// DeleteRule removes a routing rule and its dispatch history.
+func (s *NotificationRoutingService) DeleteRule(ctx context.Context, id string) error {
+	result := s.db.WithContext(ctx).Delete(&models.NotificationRoutingRule{}, "id = ?", id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete routing rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRoutingRuleNotFound
+	}
+	return nil
+}
+
+// SelectRoutingRules returns the active rules that apply to an event. Company
+// rules for an event type replace the global rules for that type.
+func SelectRoutingRules(
+	rules []*models.NotificationRoutingRule,
+	companyID string,
+	eventType models.NotificationType,
+	priority models.NotificationPriority,
+) []*models.NotificationRoutingRule {
+	var companyRules, globalRules []*models.NotificationRoutingRule
+	for _, rule := range rules {
+		if rule == nil || !rule.IsActive || rule.EventType != eventType {
+			continue
+		}
+		switch {
+		case rule.CompanyID == nil:
+			globalRules = append(globalRules, rule)
+		case companyID != "" && *rule.CompanyID == companyID:
+			companyRules = append(companyRules, rule)
+		}
+	}
+
+	selected := globalRules
+	if len(companyRules) > 0 {
+		selected = companyRules
+	}
+
+	matched := make([]*models.NotificationRoutingRule, 0, len(selected))
+	for _, rule := range selected {
+		if priorityRank(priority) >= priorityRank(rule.MinPriority) {
+			matched = append(matched, rule)
+		}
+	}
+	return matched
+}
+
+// AllowsChannel reports whether the recipient accepts the notification on the
+// given channel now. Type toggles and the minimum priority apply to every
+// channel; quiet hours hold back non-critical push notifications. Email quiet
+// hours are applied by the email dispatcher when the email is sent.
+func AllowsChannel(preferences *models.NotificationPreferences, channel string, notification *models.Notification, now time.Time) bool {
+	if preferences == nil || notification == nil {
+		return false
+	}
+	if !allowsTypeAndPriority(preferences, notification) {
+		return false
+	}
+
+	switch channel {
+	case models.NotificationDeliveryChannelWeb:
+		return preferences.EnableWebNotifications
+	case models.NotificationDeliveryChannelMobile:
+		if !preferences.EnableMobileNotifications {
+			return false
+		}
+		return notification.Priority == models.NotificationPriorityCritical || !InQuietHours(preferences, now)
+	case models.NotificationDeliveryChannelEmail:
+		return preferences.EnableEmailNotifications
+	default:
+		return false
+	}
+}
+
+// ValidateRoutingRule checks a rule before it is saved.
+func ValidateRoutingRule(rule *models.NotificationRoutingRule) error {
+	if rule == nil {
+		return fmt.Errorf("routing rule is required")
+	}
+	if strings.TrimSpace(rule.Name) == "" {
+		return fmt.Errorf("routing rule name is required")
+	}
+	if strings.TrimSpace(string(rule.EventType)) == "" {
+		return fmt.Errorf("routing rule event type is required")
+	}
+	if rule.MinPriority == "" {
+		rule.MinPriority = models.NotificationPriorityLow
+	}
+	if err := validateRoutingTarget(rule.Scope, rule.RoleList(), rule.ChannelList()); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(rule.EscalationSteps) != "" {
+		var steps []models.RoutingEscalationStep
+		if err := json.Unmarshal([]byte(rule.EscalationSteps), &steps); err != nil {
+			return fmt.Errorf("invalid escalation steps: %w", err)
+		}
+		for i, step := range steps {
+			if step.AfterMinutes <= 0 {
+				return fmt.Errorf("escalation step %d: afterMinutes must be positive", i+1)
+			}
+			if err := validateRoutingTarget(step.Scope, step.RecipientRoles, step.Channels); err != nil {
+				return fmt.Errorf("escalation step %d: %w", i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateRoutingTarget(scope string, roles []string, channels []string) error {
+	switch scope {
+	case models.RoutingScopeHierarchy, models.RoutingScopeEstate, models.RoutingScopeCompany:
+	default:
+		return fmt.Errorf("scope must be one of HIERARCHY, ESTATE, COMPANY")
+	}
+	if len(roles) == 0 {
+		return fmt.Errorf("at least one recipient role is required")
+	}
+	if len(channels) == 0 {
+		return fmt.Errorf("at least one channel is required")
+	}
+	for _, channel := range channels {
+		switch channel {
+		case models.NotificationDeliveryChannelWeb, models.NotificationDeliveryChannelMobile, models.NotificationDeliveryChannelEmail:
+		default:
+			return fmt.Errorf("unsupported channel %q", channel)
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
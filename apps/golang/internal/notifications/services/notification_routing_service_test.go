@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"agrinovagraphql/server/internal/notifications/models"
+	"agrinovagraphql/server/internal/notifications/repositories"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestSelectRoutingRulesPrefersCompanyRules(t *testing.T) {
+	companyID := "company-1"
+	otherCompanyID := "company-2"
+
+	global := &models.NotificationRoutingRule{ID: "global", EventType: models.NotificationTypeHarvestApprovalNeeded, MinPriority: models.NotificationPriorityLow, IsActive: true}
+	companyHigh := &models.NotificationRoutingRule{ID: "company-high", CompanyID: &companyID, EventType: models.NotificationTypeHarvestApprovalNeeded, MinPriority: models.NotificationPriorityHigh, IsActive: true}
+	companyLow := &models.NotificationRoutingRule{ID: "company-low", CompanyID: &companyID, EventType: models.NotificationTypeHarvestApprovalNeeded, MinPriority: models.NotificationPriorityLow, IsActive: true}
+	inactive := &models.NotificationRoutingRule{ID: "inactive", CompanyID: &companyID, EventType: models.NotificationTypeHarvestApprovalNeeded, IsActive: false}
+	other := &models.NotificationRoutingRule{ID: "other", CompanyID: &otherCompanyID, EventType: models.NotificationTypeHarvestApprovalNeeded, IsActive: true}
+	rules := []*models.NotificationRoutingRule{global, companyHigh, companyLow, inactive, other}
+
+	selected := SelectRoutingRules(rules, companyID, models.NotificationTypeHarvestApprovalNeeded, models.NotificationPriorityMedium)
+	require.Len(t, selected, 1)
+	assert.Equal(t, "company-low", selected[0].ID)
+
+	selected = SelectRoutingRules(rules, companyID, models.NotificationTypeHarvestApprovalNeeded, models.NotificationPriorityCritical)
+	assert.Len(t, selected, 2)
+
+	selected = SelectRoutingRules(rules, "company-3", models.NotificationTypeHarvestApprovalNeeded, models.NotificationPriorityLow)
+	require.Len(t, selected, 1)
+	assert.Equal(t, "global", selected[0].ID)
+
+	assert.Empty(t, SelectRoutingRules(rules, companyID, models.NotificationTypeGateCheckCreated, models.NotificationPriorityCritical))
+}
+
+func TestAllowsChannelHonoursPriorityAndQuietHours(t *testing.T) {
+	prefs := &models.NotificationPreferences{
+		EnableWebNotifications:    true,
+		EnableMobileNotifications: true,
+		MinimumPriority:           models.NotificationPriorityMedium,
+		QuietHoursStart:           "22:00",
+		QuietHoursEnd:             "06:00",
+		QuietHoursTimezone:        "Asia/Jakarta",
+	}
+	night := time.Date(2026, 3, 2, 16, 0, 0, 0, time.UTC) // 23:00 WIB
+	day := time.Date(2026, 3, 2, 3, 0, 0, 0, time.UTC)    // 10:00 WIB
+
+	low := &models.Notification{Type: models.NotificationTypeHarvestApprovalNeeded, Priority: models.NotificationPriorityLow}
+	high := &models.Notification{Type: models.NotificationTypeHarvestApprovalNeeded, Priority: models.NotificationPriorityHigh}
+	critical := &models.Notification{Type: models.NotificationTypeHarvestApprovalNeeded, Priority: models.NotificationPriorityCritical}
+
+	assert.False(t, AllowsChannel(prefs, models.NotificationDeliveryChannelWeb, low, day))
+	assert.True(t, AllowsChannel(prefs, models.NotificationDeliveryChannelWeb, high, night))
+	assert.True(t, AllowsChannel(prefs, models.NotificationDeliveryChannelMobile, high, day))
+	assert.False(t, AllowsChannel(prefs, models.NotificationDeliveryChannelMobile, high, night))
+	assert.True(t, AllowsChannel(prefs, models.NotificationDeliveryChannelMobile, critical, night))
+	assert.False(t, AllowsChannel(prefs, models.NotificationDeliveryChannelEmail, high, day))
+}
+
+func TestValidateRoutingRule(t *testing.T) {
+	rule := &models.NotificationRoutingRule{
+		Name:            "Approval panen",
+		EventType:       models.NotificationTypeHarvestApprovalNeeded,
+		RecipientRoles:  `["ASISTEN"]`,
+		Scope:           models.RoutingScopeHierarchy,
+		Channels:        `["WEB","MOBILE"]`,
+		EscalationSteps: `[{"afterMinutes":240,"recipientRoles":["MANAGER"],"scope":"ESTATE","channels":["WEB","EMAIL"]}]`,
+	}
+	require.NoError(t, ValidateRoutingRule(rule))
+	assert.Equal(t, models.NotificationPriorityLow, rule.MinPriority)
+
+	steps := rule.EscalationChain()
+	require.Len(t, steps, 1)
+	assert.Equal(t, 240, steps[0].AfterMinutes)
+	assert.Equal(t, []string{"MANAGER"}, steps[0].RecipientRoles)
+
+	invalidScope := *rule
+	invalidScope.Scope = "REGION"
+	assert.Error(t, ValidateRoutingRule(&invalidScope))
+
+	invalidChannel := *rule
+	invalidChannel.Channels = `["SMS"]`
+	assert.Error(t, ValidateRoutingRule(&invalidChannel))
+
+	noRoles := *rule
+	noRoles.RecipientRoles = `[]`
+	assert.Error(t, ValidateRoutingRule(&noRoles))
+
+	badStep := *rule
+	badStep.EscalationSteps = `[{"afterMinutes":0,"recipientRoles":["MANAGER"],"scope":"COMPANY","channels":["WEB"]}]`
+	assert.Error(t, ValidateRoutingRule(&badStep))
+}
+
+func TestRouteWithIdempotencyKeyNotifiesOnce(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(
+		&models.Notification{},
+		&models.NotificationDelivery{},
+		&models.NotificationPreferences{},
+		&models.NotificationRoutingRule{},
+		&models.NotificationRoutingDispatch{},
+	))
+	require.NoError(t, db.Exec(`CREATE TABLE users (id TEXT PRIMARY KEY, role TEXT, is_active BOOLEAN, deleted_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE user_company_assignments (user_id TEXT, company_id TEXT, is_active BOOLEAN)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO users (id, role, is_active) VALUES ('manager-1', 'MANAGER', true), ('satpam-1', 'SATPAM', true)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO user_company_assignments (user_id, company_id, is_active) VALUES ('manager-1', 'company-1', true), ('satpam-1', 'company-1', true)`).Error)
+
+	companyID := "company-1"
+	rule := &models.NotificationRoutingRule{
+		CompanyID:      &companyID,
+		Name:           "Gate ke manager",
+		EventType:      models.NotificationTypeGateCheckCreated,
+		RecipientRoles: `["MANAGER"]`,
+		Scope:          models.RoutingScopeCompany,
+		Channels:       `["WEB"]`,
+		IsActive:       true,
+	}
+	notificationService := NewNotificationService(repositories.NewNotificationRepository(db), nil, nil)
+	service := NewNotificationRoutingService(db, notificationService, nil, nil)
+	require.NoError(t, service.SaveRule(context.Background(), rule))
+
+	event := &RoutingEvent{
+		Type:              models.NotificationTypeGateCheckCreated,
+		Priority:          models.NotificationPriorityMedium,
+		Title:             "Kendaraan Masuk",
+		Message:           "B 1234 CD tercatat masuk.",
+		CompanyID:         companyID,
+		ActorID:           "satpam-1",
+		RelatedEntityType: "GATE_CHECK_RECORD",
+		RelatedEntityID:   "gate-1",
+		IdempotencyKey:    "satpam:entry:gate-1",
+	}
+
+	result, err := service.Route(context.Background(), event)
+	require.NoError(t, err)
+	assert.True(t, result.Matched)
+	assert.Equal(t, 1, result.Notifications)
+
+	result, err = service.Route(context.Background(), event)
+	require.NoError(t, err)
+	assert.True(t, result.Matched)
+	assert.Zero(t, result.Notifications, "a retried event reaches nobody twice")
+
+	var notifications []models.Notification
+	require.NoError(t, db.Find(&notifications).Error)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, "manager-1", notifications[0].RecipientID)
+	assert.Equal(t, "satpam:entry:gate-1", notifications[0].IdempotencyKey)
+
+	var dispatches int64
+	require.NoError(t, db.Model(&models.NotificationRoutingDispatch{}).Count(&dispatches).Error)
+	assert.Equal(t, int64(1), dispatches)
+}
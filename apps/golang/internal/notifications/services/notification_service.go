@@ -36,6 +36,8 @@ type NotificationService struct {
 	repo             *repositories.NotificationRepository
 	wsHandler        WebSocketHandler
 	eventBroadcaster WebSocketBroadcaster
+	emailEnabled     bool
+	routing          *NotificationRoutingService
 }
 
 // NewNotificationService creates a new notification service
@@ -51,6 +53,18 @@ func NewNotificationService(
 	}
 }
 
+// EnableEmailDelivery makes CreateNotification queue EMAIL delivery rows for
+// recipients who opted in. It is only enabled when an email sender is configured.
+func (s *NotificationService) EnableEmailDelivery(enabled bool) {
+	s.emailEnabled = enabled
+}
+
+// SetRoutingService lets the Notify helpers hand events to the company routing
+// rules before falling back to their built-in recipients.
+func (s *NotificationService) SetRoutingService(routing *NotificationRoutingService) {
+	s.routing = routing
+}
+
 // CreateNotification creates a new notification and broadcasts it in real-time
 func (s *NotificationService) CreateNotification(ctx context.Context, input *CreateNotificationInput) (*models.Notification, error) {
 	recipientID := strings.TrimSpace(input.RecipientID)
@@ -103,6 +117,13 @@ func (s *NotificationService) CreateNotification(ctx context.Context, input *Cre
 
 	// Save notification and initial delivery rows atomically
 	deliveries := s.buildInitialDeliveries(notification)
+	if !input.SkipEmail && s.shouldQueueEmail(ctx, notification) {
+		deliveries = append(deliveries, &models.NotificationDelivery{
+			NotificationID: notification.ID,
+			Channel:        models.NotificationDeliveryChannelEmail,
+			DeliveryStatus: models.NotificationDeliveryStatusPending,
+		})
+	}
 	if err := s.repo.CreateNotificationWithDeliveries(ctx, notification, deliveries); err != nil {
 		return nil, fmt.Errorf("failed to create notification: %w", err)
 	}
@@ -151,6 +172,22 @@ func (s *NotificationService) markWebDeliveryDelivered(ctx context.Context, noti
 	)
 }
 
+// shouldQueueEmail reports whether the direct recipient wants this notification
+// by email. Quiet hours and digests are applied later by the email dispatcher.
+func (s *NotificationService) shouldQueueEmail(ctx context.Context, notification *models.Notification) bool {
+	if !s.emailEnabled || s.repo == nil || notification.RecipientID == "" {
+		return false
+	}
+
+	preferences, err := s.repo.GetUserPreferences(ctx, notification.RecipientID)
+	if err != nil || !AllowsEmail(preferences, notification) {
+		return false
+	}
+
+	enabled, err := s.repo.IsCompanyEmailEnabled(ctx, notification.RecipientID)
+	return err == nil && enabled
+}
+
 // CreateFromTemplate creates a notification from a template with variable substitution
 func (s *NotificationService) CreateFromTemplate(ctx context.Context, templateName string, variables map[string]interface{}, recipients *NotificationRecipients) (*models.Notification, error) {
 	// Get template
@@ -176,6 +213,10 @@ func (s *NotificationService) CreateFromTemplate(ctx context.Context, templateNa
 		}
 		metadata[k] = v
 	}
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	metadata[emailTemplateMetadataKey] = template.Name
 
 	// Create notification input
 	input := &CreateNotificationInput{
@@ -356,6 +397,15 @@ func (s *NotificationService) UpdateUserPreferences(ctx context.Context, userID
 	if updates.QuietHoursTimezone != nil {
 		preferences.QuietHoursTimezone = *updates.QuietHoursTimezone
 	}
+	if updates.EmailDigestMode != nil {
+		mode := strings.ToUpper(strings.TrimSpace(*updates.EmailDigestMode))
+		switch mode {
+		case models.EmailDigestModeImmediate, models.EmailDigestModeHourly, models.EmailDigestModeDaily:
+			preferences.EmailDigestMode = mode
+		default:
+			return nil, fmt.Errorf("invalid email digest mode: %s", *updates.EmailDigestMode)
+		}
+	}
 
 	// Save updates
 	if err := s.repo.UpdateUserPreferences(ctx, preferences); err != nil {
@@ -498,7 +548,7 @@ func (s *NotificationService) NotifyHarvestRejected(ctx context.Context, harvest
 }
 
 // NotifyGateCheck creates notifications for gate check events
-func (s *NotificationService) NotifyGateCheck(ctx context.Context, gateCheckID string, satpamID string, vehicleNumber string, driverName string, intent string) error {
+func (s *NotificationService) NotifyGateCheck(ctx context.Context, gateCheckID string, companyID string, satpamID string, vehicleNumber string, driverName string, intent string) error {
 	metadata := map[string]interface{}{
 		"gateCheckId":   gateCheckID,
 		"vehicleNumber": vehicleNumber,
@@ -515,6 +565,28 @@ func (s *NotificationService) NotifyGateCheck(ctx context.Context, gateCheckID s
 		message = fmt.Sprintf("Kendaraan %s dengan supir %s keluar dari area kebun", vehicleNumber, driverName)
 	}
 
+	// Company routing rules replace the MANAGER broadcast when configured.
+	if s.routing != nil {
+		result, err := s.routing.Route(ctx, &RoutingEvent{
+			Type:              models.NotificationTypeGateCheckCreated,
+			Priority:          models.NotificationPriorityMedium,
+			Title:             title,
+			Message:           message,
+			CompanyID:         companyID,
+			ActorID:           satpamID,
+			ActorRole:         "SATPAM",
+			RelatedEntityType: "GATE_CHECK_RECORD",
+			RelatedEntityID:   gateCheckID,
+			ActionURL:         "/dashboard/manager/gate-logs",
+			ActionLabel:       "Lihat Log",
+			Metadata:          metadata,
+			IdempotencyKey:    fmt.Sprintf("gate-check:%s:%s", strings.ToLower(intent), gateCheckID),
+		})
+		if err != nil || (result != nil && result.Matched) {
+			return err
+		}
+	}
+
 	input := &CreateNotificationInput{
 		Type:              models.NotificationTypeGateCheckCreated,
 		Priority:          models.NotificationPriorityMedium,
@@ -667,6 +739,9 @@ type CreateNotificationInput struct {
 	SenderRole         string
 	ScheduledFor       *time.Time
 	ExpiresAt          *time.Time
+	// SkipEmail suppresses the EMAIL delivery row even when the recipient
+	// opted in; routing rules use it when EMAIL is not one of their channels.
+	SkipEmail bool
 }
 
 // NotificationUpdates represents updates to apply to a notification
@@ -698,4 +773,5 @@ type PreferencesUpdates struct {
 	QuietHoursStart           *string
 	QuietHoursEnd             *string
 	QuietHoursTimezone        *string
+	EmailDigestMode           *string
 }
@@ -0,0 +1,420 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	authServices "agrinovagraphql/server/internal/auth/services"
+	master "agrinovagraphql/server/internal/graphql/domain/master"
+	"agrinovagraphql/server/internal/notifications/models"
+	"agrinovagraphql/server/pkg/fcm"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// vehicleTaxReminderWindowDays is the earliest stage (H-30).
+	vehicleTaxReminderWindowDays = 30
+	// vehicleTaxOverdueRepeatDays is how often overdue reminders are repeated.
+	vehicleTaxOverdueRepeatDays = 7
+	// vehicleTaxReminderMaxPlates caps the plates listed in one message.
+	vehicleTaxReminderMaxPlates = 10
+
+	vehicleTaxChannelInApp = "IN_APP"
+	vehicleTaxChannelPush  = "PUSH"
+)
+
+// vehicleTaxReminderStages is the delivery order within one run.
+var vehicleTaxReminderStages = []string{
+	master.VehicleTaxReminderOverdue,
+	master.VehicleTaxReminderH1,
+	master.VehicleTaxReminderH7,
+	master.VehicleTaxReminderH30,
+}
+
+// VehicleTaxReminderService sends H-30/H-7/H-1/overdue reminders for vehicle
+// taxes to company admins, in-app and through FCM. Each stage is sent once
+// per tax; overdue reminders repeat weekly until the tax is paid. Deliveries
+// are recorded in vehicle_tax_notifications.
+type VehicleTaxReminderService struct {
+	db                  *gorm.DB
+	notificationService *NotificationService
+	fcmProvider         *fcm.FCMProvider
+	hierarchyService    *authServices.HierarchyService
+	payloadBuilder      *fcm.PayloadBuilder
+}
+
+// VehicleTaxReminderResult summarizes one reminder run for a company.
+type VehicleTaxReminderResult struct {
+	Reminded         int
+	PushSent         int
+	PenaltiesUpdated int
+	BlockedVehicles  int
+}
+
+type vehicleTaxReminderRow struct {
+	master.VehicleTax `gorm:"embedded"`
+	RegistrationPlate string `gorm:"column:registration_plate"`
+}
+
+type vehicleTaxReminderHistory struct {
+	VehicleTaxID string    `gorm:"column:vehicle_tax_id"`
+	ReminderType string    `gorm:"column:reminder_type"`
+	LastSentAt   time.Time `gorm:"column:last_sent_at"`
+}
+
+type vehicleTaxReminderRecipient struct {
+	ID   string `gorm:"column:id"`
+	Role string `gorm:"column:role"`
+}
+
+// NewVehicleTaxReminderService creates a new vehicle tax reminder service.
+// fcmProvider and hierarchyService may be nil, in which case only in-app
+// notifications are sent.
+func NewVehicleTaxReminderService(
+	db *gorm.DB,
+	notificationService *NotificationService,
+	fcmProvider *fcm.FCMProvider,
+	hierarchyService *authServices.HierarchyService,
+) *VehicleTaxReminderService {
+	return &VehicleTaxReminderService{
+		db:                  db,
+		notificationService: notificationService,
+		fcmProvider:         fcmProvider,
+		hierarchyService:    hierarchyService,
+		payloadBuilder:      fcm.NewPayloadBuilder(),
+	}
+}
+
+// RunForCompany marks overdue taxes, applies the estimated PKB penalty and
+// delivers the reminders that are due on asOf. asOf should be in the
+// company's timezone.
+func (s *VehicleTaxReminderService) RunForCompany(ctx context.Context, companyID string, asOf time.Time) (*VehicleTaxReminderResult, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database connection not available")
+	}
+
+	var rows []vehicleTaxReminderRow
+	windowEnd := time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, vehicleTaxReminderWindowDays)
+	if err := s.db.WithContext(ctx).
+		Table("vehicle_taxes AS vt").
+		Select("vt.*, v.registration_plate").
+		Joins("JOIN vehicles AS v ON v.id = vt.vehicle_id").
+		Where("v.company_id = ?", companyID).
+		Where("vt.tax_status IN ?", []string{"OPEN", "OVERDUE"}).
+		Where("vt.payment_date IS NULL").
+		Where("vt.due_date <= ?", windowEnd).
+		Order("vt.due_date ASC, v.registration_plate ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load vehicle taxes: %w", err)
+	}
+
+	result := &VehicleTaxReminderResult{}
+	if len(rows) == 0 {
+		return result, nil
+	}
+
+	blocked := make(map[string]struct{})
+	for i := range rows {
+		tax := &rows[i].VehicleTax
+		if !tax.BlocksGateEntry(asOf) {
+			continue
+		}
+		blocked[tax.VehicleID] = struct{}{}
+
+		updated, err := s.applyOverduePenalty(ctx, tax, asOf)
+		if err != nil {
+			return nil, err
+		}
+		if updated {
+			result.PenaltiesUpdated++
+		}
+	}
+	result.BlockedVehicles = len(blocked)
+
+	pending, err := s.pendingReminders(ctx, rows, asOf)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) == 0 {
+		return result, nil
+	}
+
+	recipients, err := s.companyAdmins(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(recipients) == 0 {
+		log.Printf("No company admins for company %s, vehicle tax reminders deferred", companyID)
+		return result, nil
+	}
+
+	var failures []string
+	for _, stage := range vehicleTaxReminderStages {
+		stageRows := pending[stage]
+		if len(stageRows) == 0 {
+			continue
+		}
+
+		sentTo, channels, err := s.deliver(ctx, companyID, stage, stageRows, recipients, asOf)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", stage, err))
+		}
+		if len(channels) == 0 {
+			continue
+		}
+
+		if err := s.recordReminders(ctx, stage, stageRows, sentTo, channels); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", stage, err))
+			continue
+		}
+		result.Reminded += len(stageRows)
+		for _, channel := range channels {
+			if channel == vehicleTaxChannelPush {
+				result.PushSent += len(stageRows)
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return result, fmt.Errorf("vehicle tax reminders failed: %s", strings.Join(failures, "; "))
+	}
+	return result, nil
+}
+
+// applyOverduePenalty moves an overdue tax to OVERDUE and records the current
+// penalty estimate. penalty_amount and total_amount hold the figures entered
+// from the tax notice and are left alone.
+func (s *VehicleTaxReminderService) applyOverduePenalty(ctx context.Context, tax *master.VehicleTax, asOf time.Time) (bool, error) {
+	penalty := tax.EstimatedPenalty(asOf)
+	if tax.TaxStatus == "OVERDUE" && penalty == tax.PenaltyEstimate {
+		return false, nil
+	}
+
+	if err := s.db.WithContext(ctx).
+		Model(&master.VehicleTax{}).
+		Where("id = ?", tax.ID).
+		Updates(map[string]interface{}{
+			"tax_status":       "OVERDUE",
+			"penalty_estimate": penalty,
+			"updated_at":       time.Now(),
+		}).Error; err != nil {
+		return false, fmt.Errorf("failed to update overdue vehicle tax %s: %w", tax.ID, err)
+	}
+
+	estimateChanged := penalty != tax.PenaltyEstimate
+	tax.PenaltyEstimate = penalty
+	tax.TaxStatus = "OVERDUE"
+	return estimateChanged, nil
+}
+
+// pendingReminders groups taxes by the stage that still has to be sent.
+func (s *VehicleTaxReminderService) pendingReminders(ctx context.Context, rows []vehicleTaxReminderRow, asOf time.Time) (map[string][]vehicleTaxReminderRow, error) {
+	taxIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		taxIDs = append(taxIDs, row.ID)
+	}
+
+	var history []vehicleTaxReminderHistory
+	if err := s.db.WithContext(ctx).
+		Table("vehicle_tax_notifications").
+		Select("vehicle_tax_id, reminder_type, MAX(sent_at) AS last_sent_at").
+		Where("vehicle_tax_id IN ?", taxIDs).
+		Group("vehicle_tax_id, reminder_type").
+		Scan(&history).Error; err != nil {
+		return nil, fmt.Errorf("failed to load reminder history: %w", err)
+	}
+
+	lastSent := make(map[string]time.Time, len(history))
+	for _, item := range history {
+		lastSent[item.VehicleTaxID+"|"+item.ReminderType] = item.LastSentAt
+	}
+
+	repeatAfter := asOf.AddDate(0, 0, -vehicleTaxOverdueRepeatDays)
+	pending := make(map[string][]vehicleTaxReminderRow)
+	for _, row := range rows {
+		stage := row.ReminderStage(asOf)
+		if stage == "" {
+			continue
+		}
+		if sentAt, ok := lastSent[row.ID+"|"+stage]; ok {
+			if stage != master.VehicleTaxReminderOverdue || sentAt.After(repeatAfter) {
+				continue
+			}
+		}
+		pending[stage] = append(pending[stage], row)
+	}
+	return pending, nil
+}
+
+// deliver sends one stage's reminder to every recipient and returns the
+// recipients reached and the channels that delivered.
+func (s *VehicleTaxReminderService) deliver(
+	ctx context.Context,
+	companyID string,
+	stage string,
+	rows []vehicleTaxReminderRow,
+	recipients []vehicleTaxReminderRecipient,
+	asOf time.Time,
+) ([]string, []string, error) {
+	title, message, priority := vehicleTaxReminderContent(stage, rows, asOf)
+
+	taxIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		taxIDs = append(taxIDs, row.ID)
+	}
+	relatedEntityID := ""
+	if len(rows) == 1 {
+		relatedEntityID = rows[0].ID
+	}
+
+	var failures []string
+	var channels []string
+	recipientIDs := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		recipientIDs = append(recipientIDs, recipient.ID)
+	}
+
+	sentTo := make([]string, 0, len(recipients))
+	if s.notificationService != nil {
+		idempotencyKey := fmt.Sprintf("vehicle-tax:%s:%s:%s", companyID, stage, asOf.Format("2006-01-02"))
+		for _, recipient := range recipients {
+			input := &CreateNotificationInput{
+				Type:               models.NotificationTypeComplianceAlert,
+				Priority:           priority,
+				Title:              title,
+				Message:            message,
+				IdempotencyKey:     idempotencyKey,
+				RecipientID:        recipient.ID,
+				RecipientRole:      recipient.Role,
+				RecipientCompanyID: companyID,
+				RelatedEntityType:  "VEHICLE_TAX",
+				RelatedEntityID:    relatedEntityID,
+				ActionURL:          "/vehicles",
+				ActionLabel:        "Lihat Kendaraan",
+				Metadata: map[string]interface{}{
+					"reminderStage": stage,
+					"vehicleTaxIds": taxIDs,
+					"count":         len(rows),
+				},
+			}
+			if _, err := s.notificationService.CreateNotification(ctx, input); err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", recipient.ID, err))
+				continue
+			}
+			sentTo = append(sentTo, recipient.ID)
+		}
+		if len(sentTo) > 0 {
+			channels = append(channels, vehicleTaxChannelInApp)
+		}
+	}
+
+	if s.fcmProvider != nil && s.hierarchyService != nil {
+		tokens, err := s.hierarchyService.GetMultipleUserTokens(ctx, recipientIDs)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("push: %v", err))
+		} else if len(tokens) > 0 {
+			payload := s.payloadBuilder.ForVehicleTaxReminder(stage, title, message)
+			sendResult, err := s.fcmProvider.SendToTokens(ctx, tokens, payload)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("push: %v", err))
+			} else {
+				if sendResult.SuccessCount > 0 {
+					channels = append(channels, vehicleTaxChannelPush)
+					if len(sentTo) == 0 {
+						sentTo = recipientIDs
+					}
+				}
+				if len(sendResult.FailedTokens) > 0 {
+					go func() {
+						if cleanupErr := s.hierarchyService.CleanupInvalidTokens(context.Background(), sendResult.FailedTokens); cleanupErr != nil {
+							log.Printf("Failed to cleanup invalid tokens: %v", cleanupErr)
+						}
+					}()
+				}
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return sentTo, channels, fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+	return sentTo, channels, nil
+}
+
+// recordReminders writes one vehicle_tax_notifications row per tax and channel.
+func (s *VehicleTaxReminderService) recordReminders(ctx context.Context, stage string, rows []vehicleTaxReminderRow, sentTo []string, channels []string) error {
+	now := time.Now()
+	recipientList := strings.Join(sentTo, ",")
+	records := make([]master.VehicleTaxNotification, 0, len(rows)*len(channels))
+	for _, row := range rows {
+		for _, channel := range channels {
+			sentToValue := recipientList
+			records = append(records, master.VehicleTaxNotification{
+				VehicleTaxID: row.ID,
+				ReminderType: stage,
+				Channel:      channel,
+				SentTo:       &sentToValue,
+				SentAt:       now,
+			})
+		}
+	}
+
+	if err := s.db.WithContext(ctx).Create(&records).Error; err != nil {
+		return fmt.Errorf("failed to record vehicle tax reminders: %w", err)
+	}
+	return nil
+}
+
+func (s *VehicleTaxReminderService) companyAdmins(ctx context.Context, companyID string) ([]vehicleTaxReminderRecipient, error) {
+	var recipients []vehicleTaxReminderRecipient
+	err := s.db.WithContext(ctx).Raw(`
+		SELECT DISTINCT u.id, u.role
+		FROM users u
+		JOIN user_company_assignments uca ON uca.user_id = u.id AND uca.is_active = true
+		WHERE u.role = 'COMPANY_ADMIN' AND u.is_active = true AND u.deleted_at IS NULL
+			AND uca.company_id = ?
+	`, companyID).Scan(&recipients).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query company admins: %w", err)
+	}
+	return recipients, nil
+}
+
+// vehicleTaxReminderContent builds the title, message and priority for a stage.
+func vehicleTaxReminderContent(stage string, rows []vehicleTaxReminderRow, asOf time.Time) (string, string, models.NotificationPriority) {
+	plates := make([]string, 0, vehicleTaxReminderMaxPlates)
+	totalPenalty := 0.0
+	for i, row := range rows {
+		totalPenalty += row.EstimatedPenalty(asOf)
+		if i < vehicleTaxReminderMaxPlates {
+			plates = append(plates, fmt.Sprintf("%s (%s)", row.RegistrationPlate, row.DueDate.Format("02/01/2006")))
+		}
+	}
+	plateList := strings.Join(plates, ", ")
+	if extra := len(rows) - len(plates); extra > 0 {
+		plateList = fmt.Sprintf("%s dan %d lainnya", plateList, extra)
+	}
+
+	switch stage {
+	case master.VehicleTaxReminderOverdue:
+		return "Pajak Kendaraan Terlambat",
+			fmt.Sprintf("%d pajak kendaraan melewati jatuh tempo, kendaraan diblokir masuk gerbang: %s. Estimasi denda PKB Rp%.0f.", len(rows), plateList, totalPenalty),
+			models.NotificationPriorityCritical
+	case master.VehicleTaxReminderH1:
+		return "Pajak Kendaraan Jatuh Tempo Besok",
+			fmt.Sprintf("%d pajak kendaraan jatuh tempo dalam 1 hari: %s.", len(rows), plateList),
+			models.NotificationPriorityHigh
+	case master.VehicleTaxReminderH7:
+		return "Pajak Kendaraan Jatuh Tempo 7 Hari",
+			fmt.Sprintf("%d pajak kendaraan jatuh tempo dalam 7 hari: %s.", len(rows), plateList),
+			models.NotificationPriorityMedium
+	default:
+		return "Pajak Kendaraan Jatuh Tempo 30 Hari",
+			fmt.Sprintf("%d pajak kendaraan jatuh tempo dalam 30 hari: %s.", len(rows), plateList),
+			models.NotificationPriorityLow
+	}
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	master "agrinovagraphql/server/internal/graphql/domain/master"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestApplyOverduePenaltyKeepsEnteredAmounts(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE vehicle_taxes (
+		id TEXT PRIMARY KEY, vehicle_id TEXT, tax_year INTEGER, due_date DATE,
+		pkb_amount NUMERIC, swdkllj_amount NUMERIC, admin_amount NUMERIC,
+		penalty_amount NUMERIC, total_amount NUMERIC, penalty_estimate NUMERIC DEFAULT 0,
+		payment_date DATE, payment_method TEXT, payment_reference TEXT,
+		tax_status TEXT, notes TEXT, created_at DATETIME, updated_at DATETIME
+	)`).Error)
+
+	tax := &master.VehicleTax{
+		ID:            "tax-1",
+		VehicleID:     "vehicle-1",
+		TaxYear:       2026,
+		DueDate:       time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC),
+		PKBAmount:     1000000,
+		PenaltyAmount: 50000,
+		TotalAmount:   1150000,
+		TaxStatus:     "OPEN",
+	}
+	require.NoError(t, db.Create(tax).Error)
+
+	service := NewVehicleTaxReminderService(db, nil, nil, nil)
+	asOf := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+
+	updated, err := service.applyOverduePenalty(context.Background(), tax, asOf)
+	require.NoError(t, err)
+	assert.True(t, updated)
+
+	var stored master.VehicleTax
+	require.NoError(t, db.First(&stored, "id = ?", "tax-1").Error)
+	assert.Equal(t, "OVERDUE", stored.TaxStatus)
+	assert.Equal(t, tax.EstimatedPenalty(asOf), stored.PenaltyEstimate)
+	assert.Equal(t, 50000.0, stored.PenaltyAmount, "the entered penalty is not overwritten")
+	assert.Equal(t, 1150000.0, stored.TotalAmount, "the entered total is not overwritten")
+
+	updated, err = service.applyOverduePenalty(context.Background(), tax, asOf)
+	require.NoError(t, err)
+	assert.False(t, updated, "an unchanged estimate is not rewritten")
+}
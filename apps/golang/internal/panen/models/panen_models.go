@@ -105,6 +105,8 @@ const (
 	ErrHarvestAlreadyRejected = "HARVEST_ALREADY_REJECTED"
 	ErrInvalidApprover        = "INVALID_APPROVER"
 	ErrCannotModifyApproved   = "CANNOT_MODIFY_APPROVED"
+	ErrGpsRequired            = "GPS_REQUIRED"
+	ErrPhotoRequired          = "PHOTO_REQUIRED"
 )
 
 // NewHarvestError creates a new harvest error
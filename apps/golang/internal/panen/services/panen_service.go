@@ -11,6 +11,7 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 
+	companyServices "agrinovagraphql/server/internal/company/services"
 	"agrinovagraphql/server/internal/graphql/domain/asisten"
 	"agrinovagraphql/server/internal/graphql/domain/auth"
 	"agrinovagraphql/server/internal/graphql/domain/common"
@@ -20,8 +21,9 @@ import (
 )
 
 type PanenService struct {
-	db   *gorm.DB
-	repo *panenRepos.PanenRepository
+	db       *gorm.DB
+	repo     *panenRepos.PanenRepository
+	settings *companyServices.CompanySettingsService
 }
 
 type harvestSyncLookupCacheKey struct{}
@@ -80,8 +82,9 @@ func getHarvestSyncLookupCache(ctx context.Context) *harvestSyncLookupCache {
 
 func NewPanenService(db *gorm.DB) *PanenService {
 	return &PanenService{
-		db:   db,
-		repo: panenRepos.NewPanenRepository(db),
+		db:       db,
+		repo:     panenRepos.NewPanenRepository(db),
+		settings: companyServices.NewCompanySettingsService(db),
 	}
 }
 
@@ -177,6 +180,11 @@ func (s *PanenService) createHarvestRecord(
 		return nil, err
 	}
 
+	// Apply company operational settings (GPS/photo requirement, auto-approve)
+	if err := s.applyCompanyHarvestSettings(ctx, record); err != nil {
+		return nil, err
+	}
+
 	// Create record in database
 	if err := s.repo.CreateHarvestRecord(ctx, record); err != nil {
 		if mappedErr := mapHarvestWriteError(err); mappedErr != nil {
@@ -200,6 +208,36 @@ func (s *PanenService) createHarvestRecord(
 	return createdRecord, nil
 }
 
+// applyCompanyHarvestSettings enforces the company's operational settings on a
+// new harvest record. Records at or below the auto-approve threshold (kg TBS)
+// are stored as APPROVED without an approver.
+func (s *PanenService) applyCompanyHarvestSettings(ctx context.Context, record *models.HarvestRecord) error {
+	if s.settings == nil || record.CompanyID == nil {
+		return nil
+	}
+
+	settings, err := s.settings.GetSettings(ctx, *record.CompanyID)
+	if err != nil {
+		return fmt.Errorf("failed to load company settings: %w", err)
+	}
+
+	if settings.RequireGpsForHarvest && (record.Latitude == nil || record.Longitude == nil) {
+		return models.NewHarvestError(models.ErrGpsRequired, "Lokasi GPS wajib diisi untuk record panen", "latitude")
+	}
+	if settings.RequirePhotoForHarvest && (record.PhotoURL == nil || strings.TrimSpace(*record.PhotoURL) == "") {
+		return models.NewHarvestError(models.ErrPhotoRequired, "Foto wajib dilampirkan untuk record panen", "photoUrl")
+	}
+
+	if settings.AutoApproveThreshold != nil && *settings.AutoApproveThreshold > 0 &&
+		record.BeratTbs > 0 && record.BeratTbs <= *settings.AutoApproveThreshold {
+		approvedAt := time.Now()
+		record.Status = models.HarvestApproved
+		record.ApprovedAt = &approvedAt
+	}
+
+	return nil
+}
+
 // GetByLocalID retrieves a harvest record by local ID
 func (s *PanenService) GetByLocalID(ctx context.Context, localID, mandorID string) (*models.HarvestRecord, error) {
 	if localID == "" || mandorID == "" {
@@ -0,0 +1,86 @@
+package photoupload
+
+import "time"
+
+type SessionStatus string
+
+const (
+	// StatusUploading accepts further chunks.
+	StatusUploading SessionStatus = "UPLOADING"
+	// StatusCompleted has every byte and waits for Finalize.
+	StatusCompleted SessionStatus = "COMPLETED"
+	// StatusFinalized is linked to its record; the blob may be reused by
+	// later uploads of the same content.
+	StatusFinalized SessionStatus = "FINALIZED"
+)
+
+type TargetType string
+
+const (
+	TargetHarvest  TargetType = "HARVEST"
+	TargetGuestLog TargetType = "GUEST_LOG"
+)
+
+// Session tracks one resumable upload from creation to the record it was
+// linked to.
+type Session struct {
+	ID            string        `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CompanyID     string        `json:"companyId" gorm:"type:uuid;not null"`
+	UserID        string        `json:"userId" gorm:"type:uuid;not null"`
+	DeviceID      string        `json:"deviceId" gorm:"type:varchar(255);not null;default:''"`
+	FileName      string        `json:"fileName" gorm:"type:varchar(255);not null;default:''"`
+	UploadLength  int64         `json:"uploadLength" gorm:"not null"`
+	UploadOffset  int64         `json:"uploadOffset" gorm:"not null;default:0"`
+	ContentSHA256 string        `json:"contentSha256" gorm:"column:content_sha256;type:varchar(64);not null"`
+	Status        SessionStatus `json:"status" gorm:"type:varchar(20);not null;default:'UPLOADING'"`
+	FilePath      *string       `json:"filePath,omitempty" gorm:"type:varchar(512)"`
+	ThumbnailPath *string       `json:"thumbnailPath,omitempty" gorm:"type:varchar(512)"`
+	MimeType      *string       `json:"mimeType,omitempty" gorm:"type:varchar(50)"`
+	TargetType    *TargetType   `json:"targetType,omitempty" gorm:"type:varchar(20)"`
+	TargetID      *string       `json:"targetId,omitempty" gorm:"type:uuid"`
+	ExpiresAt     time.Time     `json:"expiresAt" gorm:"not null"`
+	FinalizedAt   *time.Time    `json:"finalizedAt,omitempty"`
+	CreatedAt     time.Time     `json:"createdAt"`
+	UpdatedAt     time.Time     `json:"updatedAt"`
+}
+
+func (Session) TableName() string {
+	return "photo_upload_sessions"
+}
+
+// Actor is the authenticated caller. Sessions belong to the caller's
+// company; Mandor callers may only link photos to their own harvests.
+type Actor struct {
+	UserID    string
+	CompanyID string
+	Role      string
+}
+
+type CreateInput struct {
+	Length   int64
+	SHA256   string
+	FileName string
+	DeviceID string
+}
+
+type FinalizeInput struct {
+	TargetType TargetType `json:"targetType"`
+	TargetID   string     `json:"targetId"`
+	PhotoID    string     `json:"photoId"`
+	PhotoType  string     `json:"photoType"`
+	TakenAt    *time.Time `json:"takenAt"`
+	LocalPath  string     `json:"localPath"`
+}
+
+type FinalizeResult struct {
+	SessionID     string     `json:"sessionId"`
+	FilePath      string     `json:"filePath"`
+	ThumbnailPath string     `json:"thumbnailPath"`
+	FileSize      int64      `json:"fileSize"`
+	MimeType      string     `json:"mimeType"`
+	TargetType    TargetType `json:"targetType"`
+	TargetID      string     `json:"targetId"`
+	// Deduplicated is set when the content was already stored by an
+	// earlier upload and no new object was written.
+	Deduplicated bool `json:"deduplicated"`
+}
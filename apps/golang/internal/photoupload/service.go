@@ -0,0 +1,729 @@
+package photoupload
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	companyServices "agrinovagraphql/server/internal/company/services"
+	gatecheckModels "agrinovagraphql/server/internal/gatecheck/models"
+	panenModels "agrinovagraphql/server/internal/panen/models"
+	"agrinovagraphql/server/internal/photoverify"
+	"agrinovagraphql/server/pkg/storage"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxPhotoSize matches the limit of the base64 photo sync mutations.
+	MaxPhotoSize = 5 * 1024 * 1024
+
+	sessionTTL       = 24 * time.Hour
+	partsPrefix      = "photo-uploads"
+	purgeBatchSize   = 200
+	partOffsetDigits = 12
+)
+
+var (
+	ErrForbidden         = errors.New("company assignment required")
+	ErrInvalidInput      = errors.New("invalid upload request")
+	ErrTooLarge          = fmt.Errorf("photo exceeds maximum size of %d bytes", MaxPhotoSize)
+	ErrNotFound          = errors.New("upload not found")
+	ErrExpired           = errors.New("upload expired")
+	ErrOffsetMismatch    = errors.New("upload offset does not match")
+	ErrLengthExceeded    = errors.New("chunk exceeds the declared upload length")
+	ErrIncomplete        = errors.New("upload is not complete")
+	ErrHashMismatch      = errors.New("uploaded content does not match its sha256; upload it again")
+	ErrInvalidImage      = errors.New("invalid image format: only JPEG and PNG are accepted")
+	ErrTargetNotFound    = errors.New("target record not found")
+	ErrTargetForbidden   = errors.New("your role cannot attach photos to this target")
+	ErrTargetLocked      = errors.New("approved harvest records can no longer be modified")
+	ErrAlreadyFinalized  = errors.New("upload is already linked to another record")
+	errPartsInconsistent = errors.New("stored chunks do not cover the upload")
+)
+
+var sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// targetRoles is the role allowed to attach photos to each target type.
+var targetRoles = map[TargetType]string{
+	TargetHarvest:  "MANDOR",
+	TargetGuestLog: "SATPAM",
+}
+
+// modifiableHarvestStatuses mirrors PanenRepository.CanModifyHarvestRecord:
+// approved records are frozen.
+var modifiableHarvestStatuses = []panenModels.HarvestStatus{
+	panenModels.HarvestPending,
+	panenModels.HarvestRejected,
+}
+
+var photoTypes = map[string]gatecheckModels.PhotoType{
+	string(gatecheckModels.PhotoEntry):    gatecheckModels.PhotoEntry,
+	string(gatecheckModels.PhotoExit):     gatecheckModels.PhotoExit,
+	string(gatecheckModels.PhotoVehicle):  gatecheckModels.PhotoVehicle,
+	string(gatecheckModels.PhotoFront):    gatecheckModels.PhotoFront,
+	string(gatecheckModels.PhotoBack):     gatecheckModels.PhotoBack,
+	string(gatecheckModels.PhotoDocument): gatecheckModels.PhotoDocument,
+	string(gatecheckModels.PhotoGeneral):  gatecheckModels.PhotoGeneral,
+}
+
+// Service implements resumable photo uploads. Chunks are stored as
+// separate objects under photo-uploads/<session>/ so any instance can
+// accept the next chunk; Finalize joins them, verifies the hash and links
+// the photo to its harvest or guest record.
+type Service struct {
+	db          *gorm.DB
+	uploads     storage.Store
+	verifier    *photoverify.Service
+	tenantPlans *companyServices.TenantPlanService
+	now         func() time.Time
+}
+
+func NewService(db *gorm.DB, uploads storage.Store) *Service {
+	return &Service{
+		db:          db,
+		uploads:     uploads,
+		verifier:    photoverify.NewService(db, uploads),
+		tenantPlans: companyServices.NewTenantPlanService(db),
+		now:         time.Now,
+	}
+}
+
+// Create opens an upload session. When the company already stored a photo
+// with the same content the session starts completed and the client can
+// finalize straight away without sending any bytes; otherwise the upload must
+// fit the company's storage quota.
+func (s *Service) Create(ctx context.Context, actor Actor, input CreateInput) (*Session, error) {
+	if err := requireActor(actor); err != nil {
+		return nil, err
+	}
+	if input.Length <= 0 {
+		return nil, fmt.Errorf("%w: upload length is required", ErrInvalidInput)
+	}
+	if input.Length > MaxPhotoSize {
+		return nil, ErrTooLarge
+	}
+	hash := strings.ToLower(strings.TrimSpace(input.SHA256))
+	if !sha256Pattern.MatchString(hash) {
+		return nil, fmt.Errorf("%w: sha256 must be 64 hex characters", ErrInvalidInput)
+	}
+
+	now := s.now()
+	session := &Session{
+		CompanyID:     actor.CompanyID,
+		UserID:        actor.UserID,
+		DeviceID:      truncate(strings.TrimSpace(input.DeviceID), 255),
+		FileName:      truncate(path.Base(strings.TrimSpace(input.FileName)), 255),
+		UploadLength:  input.Length,
+		ContentSHA256: hash,
+		Status:        StatusUploading,
+		ExpiresAt:     now.Add(sessionTTL),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if session.FileName == "." || session.FileName == "/" {
+		session.FileName = ""
+	}
+
+	existing, err := s.findStoredContent(ctx, actor.CompanyID, hash, input.Length)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		session.Status = StatusCompleted
+		session.UploadOffset = input.Length
+		session.FilePath = existing.FilePath
+		session.ThumbnailPath = existing.ThumbnailPath
+		session.MimeType = existing.MimeType
+	} else if err := s.tenantPlans.CheckStorageQuota(ctx, actor.CompanyID, input.Length); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Create(session).Error; err != nil {
+		return nil, fmt.Errorf("create upload session: %w", err)
+	}
+	return session, nil
+}
+
+// Get returns the caller's session. Only the uploader may resume it.
+func (s *Service) Get(ctx context.Context, actor Actor, id string) (*Session, error) {
+	if err := requireActor(actor); err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrNotFound
+	}
+
+	var session Session
+	err := s.db.WithContext(ctx).
+		Where("id = ? AND company_id = ? AND user_id = ?", id, actor.CompanyID, actor.UserID).
+		First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load upload session: %w", err)
+	}
+	if session.Status != StatusFinalized && s.now().After(session.ExpiresAt) {
+		return nil, ErrExpired
+	}
+	return &session, nil
+}
+
+// Append stores the chunk starting at offset. Whatever arrived before the
+// connection dropped is kept, so the client resumes from the offset
+// reported by the next HEAD instead of resending the chunk. A mismatched
+// offset returns the session alongside ErrOffsetMismatch.
+func (s *Service) Append(ctx context.Context, actor Actor, id string, offset int64, body io.Reader) (*Session, error) {
+	session, err := s.Get(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if offset != session.UploadOffset {
+		return session, ErrOffsetMismatch
+	}
+	if session.Status != StatusUploading {
+		return session, nil
+	}
+
+	remaining := session.UploadLength - session.UploadOffset
+	data, readErr := io.ReadAll(io.LimitReader(body, remaining+1))
+	if int64(len(data)) > remaining {
+		return session, ErrLengthExceeded
+	}
+	if len(data) == 0 {
+		if readErr != nil {
+			return session, fmt.Errorf("read chunk: %w", readErr)
+		}
+		return session, nil
+	}
+	if readErr != nil {
+		log.Printf("photoupload: session %s: keeping %d bytes of an interrupted chunk: %v", session.ID, len(data), readErr)
+	}
+
+	// A chunk that never got its offset recorded is overwritten by the retry
+	// at the same offset, so stale parts cannot survive.
+	if err := storage.PutBytes(ctx, s.uploads, partKey(session.ID, offset), data, "application/octet-stream"); err != nil {
+		return session, fmt.Errorf("store chunk: %w", err)
+	}
+
+	newOffset := offset + int64(len(data))
+	status := StatusUploading
+	if newOffset == session.UploadLength {
+		status = StatusCompleted
+	}
+	now := s.now()
+	result := s.db.WithContext(ctx).Model(&Session{}).
+		Where("id = ? AND upload_offset = ? AND status = ?", session.ID, offset, StatusUploading).
+		Updates(map[string]interface{}{"upload_offset": newOffset, "status": status, "updated_at": now})
+	if result.Error != nil {
+		return session, fmt.Errorf("record upload offset: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		current, err := s.Get(ctx, actor, id)
+		if err != nil {
+			return nil, err
+		}
+		return current, ErrOffsetMismatch
+	}
+
+	session.UploadOffset = newOffset
+	session.Status = status
+	session.UpdatedAt = now
+	return session, nil
+}
+
+// Terminate discards an unfinished upload and its chunks.
+func (s *Service) Terminate(ctx context.Context, actor Actor, id string) error {
+	session, err := s.Get(ctx, actor, id)
+	if err != nil {
+		return err
+	}
+	if session.Status == StatusFinalized {
+		return ErrAlreadyFinalized
+	}
+	return s.discard(ctx, session.ID)
+}
+
+// Finalize joins the chunks, verifies the declared sha256, stores the photo
+// with a thumbnail and links it to the target record. Storing a new photo
+// reserves its size on the company's storage quota; the reservation is
+// released when the photo is not kept. Repeating the call for the same target
+// returns the earlier result.
+func (s *Service) Finalize(ctx context.Context, actor Actor, id string, input FinalizeInput) (*FinalizeResult, error) {
+	session, err := s.Get(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	targetType := TargetType(strings.ToUpper(strings.TrimSpace(string(input.TargetType))))
+	targetRef := strings.TrimSpace(input.TargetID)
+	if (targetType != TargetHarvest && targetType != TargetGuestLog) || targetRef == "" {
+		return nil, fmt.Errorf("%w: targetType must be HARVEST or GUEST_LOG with a targetId", ErrInvalidInput)
+	}
+	photoType := gatecheckModels.PhotoGeneral
+	if value := strings.ToUpper(strings.TrimSpace(input.PhotoType)); value != "" {
+		known, ok := photoTypes[value]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown photoType %q", ErrInvalidInput, input.PhotoType)
+		}
+		photoType = known
+	}
+
+	targetID, err := s.resolveTarget(ctx, actor, targetType, targetRef)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Status == StatusFinalized {
+		if session.TargetType == nil || *session.TargetType != targetType || session.TargetID == nil || *session.TargetID != targetID {
+			return nil, ErrAlreadyFinalized
+		}
+		return finalizeResult(session, false), nil
+	}
+	if session.Status != StatusCompleted {
+		return nil, ErrIncomplete
+	}
+	if targetType == TargetHarvest {
+		if err := ensureHarvestModifiable(s.db.WithContext(ctx), targetID); err != nil {
+			return nil, err
+		}
+	}
+
+	deduplicated := session.FilePath != nil
+	var reserved int64
+	if !deduplicated {
+		if err := s.tenantPlans.ReserveStorage(ctx, session.CompanyID, session.UploadLength); err != nil {
+			return nil, err
+		}
+		reserved = session.UploadLength
+		if deduplicated, err = s.storePhoto(ctx, session, targetType); err != nil {
+			s.releaseStorage(ctx, session.CompanyID, reserved)
+			return nil, err
+		}
+		if deduplicated {
+			s.releaseStorage(ctx, session.CompanyID, reserved)
+			reserved = 0
+		}
+	}
+
+	now := s.now()
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.linkTarget(tx, actor, session, targetType, targetID, photoType, input, now); err != nil {
+			return err
+		}
+		return tx.Model(&Session{}).Where("id = ?", session.ID).Updates(map[string]interface{}{
+			"status":         StatusFinalized,
+			"file_path":      session.FilePath,
+			"thumbnail_path": session.ThumbnailPath,
+			"mime_type":      session.MimeType,
+			"target_type":    targetType,
+			"target_id":      targetID,
+			"finalized_at":   now,
+			"updated_at":     now,
+		}).Error
+	})
+	if err != nil {
+		s.releaseStorage(ctx, session.CompanyID, reserved)
+		return nil, fmt.Errorf("link photo: %w", err)
+	}
+
+	s.deleteParts(ctx, session.ID)
+
+	subject := photoverify.Subject{RecordType: photoverify.RecordType(targetType), RecordID: targetID, PhotoURL: *session.FilePath}
+	if _, err := s.verifier.VerifyStored(ctx, subject); err != nil {
+		log.Printf("photoupload: session %s: failed to verify photo: %v", session.ID, err)
+	}
+
+	session.Status = StatusFinalized
+	session.TargetType = &targetType
+	session.TargetID = &targetID
+	session.FinalizedAt = &now
+	return finalizeResult(session, deduplicated), nil
+}
+
+// PurgeExpired removes unfinished sessions past their expiry together with
+// their chunks and returns how many were removed.
+func (s *Service) PurgeExpired(ctx context.Context) (int, error) {
+	var ids []string
+	err := s.db.WithContext(ctx).Model(&Session{}).
+		Where("status <> ? AND expires_at < ?", StatusFinalized, s.now()).
+		Order("expires_at").
+		Limit(purgeBatchSize).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return 0, fmt.Errorf("load expired upload sessions: %w", err)
+	}
+
+	purged := 0
+	for _, id := range ids {
+		if err := s.discard(ctx, id); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// releaseStorage gives back storage reserved for a photo that was not kept.
+func (s *Service) releaseStorage(ctx context.Context, companyID string, sizeBytes int64) {
+	if sizeBytes <= 0 {
+		return
+	}
+	if err := s.tenantPlans.RecordStorageUsage(ctx, companyID, -sizeBytes); err != nil {
+		log.Printf("photoupload: failed to release storage for company %s: %v", companyID, err)
+	}
+}
+
+func (s *Service) discard(ctx context.Context, id string) error {
+	s.deleteParts(ctx, id)
+	if err := s.db.WithContext(ctx).Where("id = ? AND status <> ?", id, StatusFinalized).Delete(&Session{}).Error; err != nil {
+		return fmt.Errorf("delete upload session: %w", err)
+	}
+	return nil
+}
+
+// storePhoto verifies the joined chunks and writes the photo and its
+// thumbnail. When another upload stored the same content in the meantime
+// that object is reused instead and reused is true.
+func (s *Service) storePhoto(ctx context.Context, session *Session, targetType TargetType) (reused bool, err error) {
+	data, err := assembleParts(ctx, s.uploads, session.ID, session.UploadLength)
+	if err != nil {
+		return false, err
+	}
+
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != session.ContentSHA256 {
+		// Start over: the chunks cannot be trusted.
+		s.deleteParts(ctx, session.ID)
+		if err := s.db.WithContext(ctx).Model(&Session{}).Where("id = ?", session.ID).
+			Updates(map[string]interface{}{"upload_offset": 0, "status": StatusUploading, "updated_at": s.now()}).Error; err != nil {
+			return false, fmt.Errorf("reset upload session: %w", err)
+		}
+		return false, ErrHashMismatch
+	}
+
+	mimeType, ext, ok := detectPhotoType(data)
+	if !ok {
+		return false, ErrInvalidImage
+	}
+
+	existing, err := s.findStoredContent(ctx, session.CompanyID, session.ContentSHA256, session.UploadLength)
+	if err != nil {
+		return false, err
+	}
+	if existing != nil {
+		session.FilePath = existing.FilePath
+		session.ThumbnailPath = existing.ThumbnailPath
+		session.MimeType = existing.MimeType
+		return true, nil
+	}
+
+	thumbnail, err := makeThumbnail(data)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	dir := photoDirectory(targetType)
+	photoKey := storage.JoinKey(dir, session.CompanyID, session.ContentSHA256+ext)
+	thumbnailKey := storage.JoinKey(dir, session.CompanyID, "thumbs", session.ContentSHA256+".jpg")
+	if err := storage.PutBytes(ctx, s.uploads, photoKey, data, mimeType); err != nil {
+		return false, fmt.Errorf("store photo: %w", err)
+	}
+	if err := storage.PutBytes(ctx, s.uploads, thumbnailKey, thumbnail, "image/jpeg"); err != nil {
+		return false, fmt.Errorf("store thumbnail: %w", err)
+	}
+
+	filePath := storage.URLPath(photoKey)
+	thumbnailPath := storage.URLPath(thumbnailKey)
+	session.FilePath = &filePath
+	session.ThumbnailPath = &thumbnailPath
+	session.MimeType = &mimeType
+	return false, nil
+}
+
+// findStoredContent returns a finalized session of the company holding the
+// same content whose object still exists.
+func (s *Service) findStoredContent(ctx context.Context, companyID, hash string, length int64) (*Session, error) {
+	var candidates []Session
+	err := s.db.WithContext(ctx).
+		Where("company_id = ? AND content_sha256 = ? AND upload_length = ? AND status = ? AND file_path IS NOT NULL",
+			companyID, hash, length, StatusFinalized).
+		Order("finalized_at DESC").
+		Limit(3).
+		Find(&candidates).Error
+	if err != nil {
+		return nil, fmt.Errorf("look up stored photo: %w", err)
+	}
+
+	for i := range candidates {
+		key, ok := storage.KeyFromURLPath(*candidates[i].FilePath)
+		if !ok {
+			continue
+		}
+		if _, err := s.uploads.Stat(ctx, key); err == nil {
+			return &candidates[i], nil
+		} else if !errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("check stored photo: %w", err)
+		}
+	}
+	return nil, nil
+}
+
+// resolveTarget maps a server ID or the device's local ID to the record's
+// ID, scoped to the caller's company. Mandor callers only reach their own
+// harvests.
+func (s *Service) resolveTarget(ctx context.Context, actor Actor, targetType TargetType, ref string) (string, error) {
+	if !strings.EqualFold(actor.Role, targetRoles[targetType]) {
+		return "", ErrTargetForbidden
+	}
+
+	table := "gate_guest_logs"
+	if targetType == TargetHarvest {
+		table = "harvest_records"
+	}
+
+	query := s.db.WithContext(ctx).Table(table).Where("company_id = ?", actor.CompanyID)
+	if _, err := uuid.Parse(ref); err == nil {
+		query = query.Where("id = ? OR local_id = ?", ref, ref)
+	} else {
+		query = query.Where("local_id = ?", ref)
+	}
+	if targetType == TargetHarvest {
+		query = query.Where("mandor_id = ?", actor.UserID)
+	}
+
+	var ids []string
+	if err := query.Limit(1).Pluck("id", &ids).Error; err != nil {
+		return "", fmt.Errorf("look up target record: %w", err)
+	}
+	if len(ids) == 0 {
+		return "", ErrTargetNotFound
+	}
+	return ids[0], nil
+}
+
+func (s *Service) linkTarget(tx *gorm.DB, actor Actor, session *Session, targetType TargetType, targetID string, photoType gatecheckModels.PhotoType, input FinalizeInput, now time.Time) error {
+	if targetType == TargetHarvest {
+		// The status condition keeps an approval that lands after Finalize's
+		// check from being overwritten.
+		result := tx.Table("harvest_records").
+			Where("id = ? AND status IN ?", targetID, modifiableHarvestStatuses).
+			Updates(map[string]interface{}{"photo_url": *session.FilePath, "updated_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTargetLocked
+		}
+		return nil
+	}
+
+	// Finalize retries after a lost response must not add the photo twice.
+	var linked int64
+	if err := tx.Model(&gatecheckModels.GateCheckPhoto{}).
+		Where("related_record_id = ? AND file_hash = ?", targetID, session.ContentSHA256).
+		Count(&linked).Error; err != nil {
+		return err
+	}
+	if linked > 0 {
+		return nil
+	}
+
+	photoID := truncate(strings.TrimSpace(input.PhotoID), 128)
+	if photoID == "" {
+		photoID = session.ID
+	}
+	takenAt := now
+	if input.TakenAt != nil && !input.TakenAt.IsZero() {
+		takenAt = *input.TakenAt
+	}
+	filePath := *session.FilePath
+	mimeType := ""
+	if session.MimeType != nil {
+		mimeType = *session.MimeType
+	}
+
+	return tx.Create(&gatecheckModels.GateCheckPhoto{
+		PhotoID:           photoID,
+		RelatedRecordType: gatecheckModels.RecordTypeGuestLog,
+		RelatedRecordID:   targetID,
+		FilePath:          filePath,
+		FileName:          path.Base(filePath),
+		FileSize:          session.UploadLength,
+		FileExtension:     path.Ext(filePath),
+		MimeType:          mimeType,
+		PhotoType:         photoType,
+		PhotoQuality:      gatecheckModels.PhotoQualityMedium,
+		TakenAt:           takenAt,
+		CreatedUserID:     actor.UserID,
+		DeviceID:          session.DeviceID,
+		LocalPath:         strings.TrimSpace(input.LocalPath),
+		CloudPath:         &filePath,
+		ThumbnailPath:     session.ThumbnailPath,
+		FileHash:          session.ContentSHA256,
+		SyncStatus:        "SYNCED",
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}).Error
+}
+
+// ensureHarvestModifiable refuses harvest records that are no longer
+// PENDING or REJECTED.
+func ensureHarvestModifiable(db *gorm.DB, harvestID string) error {
+	var statuses []string
+	if err := db.Table("harvest_records").Where("id = ?", harvestID).Limit(1).Pluck("status", &statuses).Error; err != nil {
+		return fmt.Errorf("look up target record: %w", err)
+	}
+	if len(statuses) == 0 {
+		return ErrTargetNotFound
+	}
+	for _, status := range modifiableHarvestStatuses {
+		if strings.EqualFold(statuses[0], string(status)) {
+			return nil
+		}
+	}
+	return ErrTargetLocked
+}
+
+func (s *Service) deleteParts(ctx context.Context, sessionID string) {
+	prefix := storage.JoinKey(partsPrefix, sessionID) + "/"
+	var keys []string
+	if err := s.uploads.List(ctx, prefix, func(info storage.ObjectInfo) error {
+		keys = append(keys, info.Key)
+		return nil
+	}); err != nil {
+		log.Printf("photoupload: session %s: failed to list chunks: %v", sessionID, err)
+		return
+	}
+	for _, key := range keys {
+		if err := s.uploads.Delete(ctx, key); err != nil {
+			log.Printf("photoupload: session %s: failed to delete chunk %s: %v", sessionID, key, err)
+		}
+	}
+}
+
+// assembleParts joins the chunks of a session in offset order. The parts
+// must cover exactly [0, length).
+func assembleParts(ctx context.Context, store storage.Store, sessionID string, length int64) ([]byte, error) {
+	prefix := storage.JoinKey(partsPrefix, sessionID) + "/"
+	type part struct {
+		key    string
+		offset int64
+	}
+	var parts []part
+	if err := store.List(ctx, prefix, func(info storage.ObjectInfo) error {
+		offset, err := strconv.ParseInt(strings.TrimPrefix(info.Key, prefix), 10, 64)
+		if err != nil {
+			return nil
+		}
+		parts = append(parts, part{key: info.Key, offset: offset})
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("list chunks: %w", err)
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].offset < parts[j].offset })
+
+	var out bytes.Buffer
+	out.Grow(int(length))
+	for _, p := range parts {
+		if p.offset >= length {
+			break
+		}
+		if p.offset != int64(out.Len()) {
+			return nil, errPartsInconsistent
+		}
+		body, _, err := store.Open(ctx, p.key)
+		if err != nil {
+			return nil, fmt.Errorf("open chunk: %w", err)
+		}
+		_, err = io.Copy(&out, io.LimitReader(body, length-p.offset))
+		body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read chunk: %w", err)
+		}
+	}
+	if int64(out.Len()) != length {
+		return nil, errPartsInconsistent
+	}
+	return out.Bytes(), nil
+}
+
+func partKey(sessionID string, offset int64) string {
+	return storage.JoinKey(partsPrefix, sessionID, fmt.Sprintf("%0*d", partOffsetDigits, offset))
+}
+
+// photoDirectory keeps the layout of the base64 sync mutations so the
+// upload access rules apply unchanged.
+func photoDirectory(targetType TargetType) string {
+	if targetType == TargetHarvest {
+		return "harvest_photos"
+	}
+	return "satpam_photos"
+}
+
+func detectPhotoType(data []byte) (mimeType string, ext string, ok bool) {
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return "image/jpeg", ".jpg", true
+	case "image/png":
+		return "image/png", ".png", true
+	}
+	return "", "", false
+}
+
+func finalizeResult(session *Session, deduplicated bool) *FinalizeResult {
+	result := &FinalizeResult{
+		SessionID:    session.ID,
+		FileSize:     session.UploadLength,
+		Deduplicated: deduplicated,
+	}
+	if session.FilePath != nil {
+		result.FilePath = *session.FilePath
+	}
+	if session.ThumbnailPath != nil {
+		result.ThumbnailPath = *session.ThumbnailPath
+	}
+	if session.MimeType != nil {
+		result.MimeType = *session.MimeType
+	}
+	if session.TargetType != nil {
+		result.TargetType = *session.TargetType
+	}
+	if session.TargetID != nil {
+		result.TargetID = *session.TargetID
+	}
+	return result
+}
+
+func requireActor(actor Actor) error {
+	if strings.TrimSpace(actor.UserID) == "" || strings.TrimSpace(actor.CompanyID) == "" {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// truncate cuts value to at most limit bytes without splitting a rune.
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	for limit > 0 && !utf8.RuneStart(value[limit]) {
+		limit--
+	}
+	return value[:limit]
+}
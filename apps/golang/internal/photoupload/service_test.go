@@ -0,0 +1,212 @@
+package photoupload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"time"
+
+	gatecheckModels "agrinovagraphql/server/internal/gatecheck/models"
+	"agrinovagraphql/server/pkg/storage"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestAssembleParts_JoinsChunksInOffsetOrder(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	put := func(offset int64, data string) {
+		t.Helper()
+		if err := storage.PutBytes(ctx, store, partKey("s1", offset), []byte(data), "application/octet-stream"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Offset 10 sorts before 4 as a plain string; the zero padding keeps
+	// the order right either way.
+	put(10, "klm")
+	put(0, "abcd")
+	put(4, "efghij")
+
+	data, err := assembleParts(ctx, store, "s1", 13)
+	if err != nil {
+		t.Fatalf("assembleParts: %v", err)
+	}
+	if string(data) != "abcdefghijklm" {
+		t.Fatalf("assembled %q", data)
+	}
+
+	if _, err := assembleParts(ctx, store, "s1", 20); !errors.Is(err, errPartsInconsistent) {
+		t.Fatalf("expected errPartsInconsistent for a short upload, got %v", err)
+	}
+
+	put(20, "zzz")
+	if _, err := assembleParts(ctx, store, "s1", 23); !errors.Is(err, errPartsInconsistent) {
+		t.Fatalf("expected errPartsInconsistent for a gap, got %v", err)
+	}
+}
+
+func TestThumbnailSize(t *testing.T) {
+	tests := []struct {
+		width, height         int
+		wantWidth, wantHeight int
+	}{
+		{4000, 3000, 320, 240},
+		{3000, 4000, 240, 320},
+		{200, 100, 200, 100},
+		{10000, 10, 320, 1},
+		{0, 10, 0, 0},
+	}
+	for _, tt := range tests {
+		gotWidth, gotHeight := thumbnailSize(tt.width, tt.height, thumbnailMaxEdge)
+		if gotWidth != tt.wantWidth || gotHeight != tt.wantHeight {
+			t.Errorf("thumbnailSize(%d, %d) = %dx%d, want %dx%d", tt.width, tt.height, gotWidth, gotHeight, tt.wantWidth, tt.wantHeight)
+		}
+	}
+}
+
+func TestMakeThumbnail_DownscalesPhotos(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 1280, 960))
+	for y := 0; y < 960; y++ {
+		for x := 0; x < 1280; x++ {
+			src.Set(x, y, color.RGBA{R: 200, G: 120, B: 40, A: 255})
+		}
+	}
+
+	for name, encode := range map[string]func(*bytes.Buffer) error{
+		"jpeg": func(b *bytes.Buffer) error { return jpeg.Encode(b, src, nil) },
+		"png":  func(b *bytes.Buffer) error { return png.Encode(b, src) },
+	} {
+		var photo bytes.Buffer
+		if err := encode(&photo); err != nil {
+			t.Fatal(err)
+		}
+		if mimeType, _, ok := detectPhotoType(photo.Bytes()); !ok || mimeType != "image/"+name {
+			t.Fatalf("%s: detectPhotoType = %q, %v", name, mimeType, ok)
+		}
+
+		thumbnail, err := makeThumbnail(photo.Bytes())
+		if err != nil {
+			t.Fatalf("%s: makeThumbnail: %v", name, err)
+		}
+		decoded, err := jpeg.Decode(bytes.NewReader(thumbnail))
+		if err != nil {
+			t.Fatalf("%s: thumbnail is not a JPEG: %v", name, err)
+		}
+		if size := decoded.Bounds().Size(); size.X != 320 || size.Y != 240 {
+			t.Fatalf("%s: thumbnail is %v, want 320x240", name, size)
+		}
+		r, g, b, _ := decoded.At(160, 120).RGBA()
+		if r>>8 < 190 || g>>8 < 110 || g>>8 > 130 || b>>8 > 55 {
+			t.Fatalf("%s: thumbnail colour drifted: %d %d %d", name, r>>8, g>>8, b>>8)
+		}
+	}
+
+	if _, err := makeThumbnail([]byte("GIF89a not a photo")); err == nil {
+		t.Fatal("expected an error for a non-photo")
+	}
+	if _, _, ok := detectPhotoType([]byte("GIF89a not a photo")); ok {
+		t.Fatal("detectPhotoType accepted a GIF")
+	}
+}
+
+func TestTruncate_KeepsRunesWhole(t *testing.T) {
+	if got := truncate("foto-é.jpg", 6); got != "foto-" {
+		t.Fatalf("truncate = %q", got)
+	}
+	if got := truncate("short", 10); got != "short" {
+		t.Fatalf("truncate = %q", got)
+	}
+}
+
+func setupTargetTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	for _, stmt := range []string{
+		`CREATE TABLE harvest_records (id TEXT PRIMARY KEY, local_id TEXT, company_id TEXT, mandor_id TEXT, status TEXT, photo_url TEXT, updated_at DATETIME)`,
+		`CREATE TABLE gate_guest_logs (id TEXT PRIMARY KEY, local_id TEXT, company_id TEXT)`,
+		`INSERT INTO harvest_records (id, local_id, company_id, mandor_id, status) VALUES ('harvest-pending', 'local-pending', 'company-1', 'mandor-1', 'PENDING')`,
+		`INSERT INTO harvest_records (id, local_id, company_id, mandor_id, status) VALUES ('harvest-approved', 'local-approved', 'company-1', 'mandor-1', 'APPROVED')`,
+		`INSERT INTO gate_guest_logs (id, local_id, company_id) VALUES ('guest-1', 'local-guest', 'company-1')`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func TestResolveTarget_LimitsTargetsToTheirRole(t *testing.T) {
+	s := &Service{db: setupTargetTestDB(t)}
+	ctx := context.Background()
+	mandor := Actor{UserID: "mandor-1", CompanyID: "company-1", Role: "MANDOR"}
+	satpam := Actor{UserID: "satpam-1", CompanyID: "company-1", Role: "SATPAM"}
+
+	if id, err := s.resolveTarget(ctx, mandor, TargetHarvest, "local-pending"); err != nil || id != "harvest-pending" {
+		t.Fatalf("expected the owning mandor to resolve the harvest, got %q, %v", id, err)
+	}
+	otherMandor := Actor{UserID: "mandor-2", CompanyID: "company-1", Role: "MANDOR"}
+	if _, err := s.resolveTarget(ctx, otherMandor, TargetHarvest, "local-pending"); !errors.Is(err, ErrTargetNotFound) {
+		t.Fatalf("expected ErrTargetNotFound for another mandor's harvest, got %v", err)
+	}
+	if _, err := s.resolveTarget(ctx, satpam, TargetHarvest, "local-pending"); !errors.Is(err, ErrTargetForbidden) {
+		t.Fatalf("expected ErrTargetForbidden for a satpam on a harvest, got %v", err)
+	}
+	if id, err := s.resolveTarget(ctx, satpam, TargetGuestLog, "local-guest"); err != nil || id != "guest-1" {
+		t.Fatalf("expected the satpam to resolve the guest log, got %q, %v", id, err)
+	}
+	if _, err := s.resolveTarget(ctx, mandor, TargetGuestLog, "local-guest"); !errors.Is(err, ErrTargetForbidden) {
+		t.Fatalf("expected ErrTargetForbidden for a mandor on a guest log, got %v", err)
+	}
+}
+
+func TestLinkTarget_RefusesApprovedHarvest(t *testing.T) {
+	db := setupTargetTestDB(t)
+	s := &Service{db: db}
+	mandor := Actor{UserID: "mandor-1", CompanyID: "company-1", Role: "MANDOR"}
+	filePath := "/uploads/harvest/photo.jpg"
+	session := &Session{ID: "session-1", FilePath: &filePath}
+	now := time.Now()
+
+	if err := ensureHarvestModifiable(db, "harvest-approved"); !errors.Is(err, ErrTargetLocked) {
+		t.Fatalf("expected ErrTargetLocked before storing, got %v", err)
+	}
+	err := s.linkTarget(db, mandor, session, TargetHarvest, "harvest-approved", gatecheckModels.PhotoGeneral, FinalizeInput{}, now)
+	if !errors.Is(err, ErrTargetLocked) {
+		t.Fatalf("expected ErrTargetLocked when linking, got %v", err)
+	}
+	var photoURL *string
+	if err := db.Table("harvest_records").Where("id = ?", "harvest-approved").Select("photo_url").Row().Scan(&photoURL); err != nil {
+		t.Fatal(err)
+	}
+	if photoURL != nil {
+		t.Fatalf("approved record photo_url changed to %q", *photoURL)
+	}
+
+	if err := ensureHarvestModifiable(db, "harvest-pending"); err != nil {
+		t.Fatalf("expected a pending harvest to be modifiable, got %v", err)
+	}
+	if err := s.linkTarget(db, mandor, session, TargetHarvest, "harvest-pending", gatecheckModels.PhotoGeneral, FinalizeInput{}, now); err != nil {
+		t.Fatalf("linkTarget on a pending harvest: %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package photoupload
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+)
+
+const (
+	thumbnailMaxEdge = 320
+	thumbnailQuality = 70
+)
+
+// makeThumbnail decodes a JPEG or PNG photo and returns a JPEG preview
+// whose longest edge is at most thumbnailMaxEdge pixels.
+func makeThumbnail(data []byte) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode photo: %w", err)
+	}
+
+	bounds := src.Bounds()
+	width, height := thumbnailSize(bounds.Dx(), bounds.Dy(), thumbnailMaxEdge)
+	if width == 0 || height == 0 {
+		return nil, fmt.Errorf("photo has no pixels")
+	}
+
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, downscaleBox(src, width, height), &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, fmt.Errorf("encode thumbnail: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// thumbnailSize fits width x height within maxEdge keeping the aspect
+// ratio. Images already small enough keep their size.
+func thumbnailSize(width, height, maxEdge int) (int, int) {
+	if width <= 0 || height <= 0 {
+		return 0, 0
+	}
+	if width <= maxEdge && height <= maxEdge {
+		return width, height
+	}
+	if width >= height {
+		return maxEdge, max(1, height*maxEdge/width)
+	}
+	return max(1, width*maxEdge/height), maxEdge
+}
+
+// downscaleBox averages every source pixel that falls into each target
+// pixel, which avoids the aliasing of nearest-neighbour sampling on large
+// camera photos.
+func downscaleBox(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*srcH/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*srcH/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*srcW/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*srcW/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r += uint64(pr)
+					g += uint64(pg)
+					b += uint64(pb)
+					a += uint64(pa)
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
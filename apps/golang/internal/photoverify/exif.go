@@ -0,0 +1,313 @@
+package photoverify
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"strings"
+	"time"
+)
+
+// Metadata is what ExtractMetadata reads from a photo's EXIF block.
+type Metadata struct {
+	CapturedAt *time.Time
+	Latitude   *float64
+	Longitude  *float64
+	Make       string
+	Model      string
+	Software   string
+}
+
+// HasEXIF reports whether any EXIF field was found.
+func (m Metadata) HasEXIF() bool {
+	return m.CapturedAt != nil || m.Latitude != nil || m.Make != "" || m.Model != "" || m.Software != ""
+}
+
+var (
+	errNoEXIF      = errors.New("photo has no EXIF metadata")
+	errInvalidEXIF = errors.New("photo has malformed EXIF metadata")
+)
+
+const (
+	tagMake               = 0x010F
+	tagModel              = 0x0110
+	tagSoftware           = 0x0131
+	tagDateTime           = 0x0132
+	tagExifIFD            = 0x8769
+	tagGPSIFD             = 0x8825
+	tagDateTimeOriginal   = 0x9003
+	tagOffsetTimeOriginal = 0x9011
+	tagGPSLatitudeRef     = 0x0001
+	tagGPSLatitude        = 0x0002
+	tagGPSLongitudeRef    = 0x0003
+	tagGPSLongitude       = 0x0004
+	tagGPSTimeStamp       = 0x0007
+	tagGPSDateStamp       = 0x001D
+
+	exifTimeLayout = "2006:01:02 15:04:05"
+)
+
+// ExtractMetadata reads capture time, GPS position and camera from the EXIF
+// block of a JPEG (APP1) or PNG (eXIf chunk). Capture times without an
+// offset are read in loc; a GPS timestamp, which is always UTC, wins over
+// an unzoned camera clock.
+func ExtractMetadata(data []byte, loc *time.Location) (Metadata, error) {
+	var tiff []byte
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8}):
+		tiff = jpegEXIF(data)
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		tiff = pngEXIF(data)
+	}
+	if tiff == nil {
+		return Metadata{}, errNoEXIF
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	reader, err := newTIFFReader(tiff)
+	if err != nil {
+		return Metadata{}, err
+	}
+	ifd0, err := reader.readIFD(reader.firstIFD)
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	meta := Metadata{
+		Make:     reader.ascii(ifd0[tagMake]),
+		Model:    reader.ascii(ifd0[tagModel]),
+		Software: reader.ascii(ifd0[tagSoftware]),
+	}
+
+	capturedRaw := reader.ascii(ifd0[tagDateTime])
+	offsetRaw := ""
+	if entry, ok := ifd0[tagExifIFD]; ok {
+		if exifIFD, err := reader.readIFD(reader.long(entry)); err == nil {
+			if original := reader.ascii(exifIFD[tagDateTimeOriginal]); original != "" {
+				capturedRaw = original
+			}
+			offsetRaw = reader.ascii(exifIFD[tagOffsetTimeOriginal])
+		}
+	}
+
+	var gpsTime *time.Time
+	if entry, ok := ifd0[tagGPSIFD]; ok {
+		if gps, err := reader.readIFD(reader.long(entry)); err == nil {
+			meta.Latitude = reader.coordinate(gps[tagGPSLatitude], reader.ascii(gps[tagGPSLatitudeRef]), "S")
+			meta.Longitude = reader.coordinate(gps[tagGPSLongitude], reader.ascii(gps[tagGPSLongitudeRef]), "W")
+			gpsTime = reader.gpsTimestamp(gps[tagGPSDateStamp], gps[tagGPSTimeStamp])
+		}
+	}
+
+	meta.CapturedAt = parseCaptureTime(capturedRaw, offsetRaw, gpsTime, loc)
+	return meta, nil
+}
+
+func parseCaptureTime(raw, offset string, gpsTime *time.Time, loc *time.Location) *time.Time {
+	raw = strings.TrimSpace(raw)
+	if raw != "" && offset != "" {
+		if parsed, err := time.Parse(exifTimeLayout+"-07:00", raw+strings.TrimSpace(offset)); err == nil {
+			return &parsed
+		}
+	}
+	if gpsTime != nil {
+		return gpsTime
+	}
+	if raw == "" {
+		return nil
+	}
+	parsed, err := time.ParseInLocation(exifTimeLayout, raw, loc)
+	if err != nil || parsed.Year() < 1990 {
+		return nil
+	}
+	return &parsed
+}
+
+// jpegEXIF returns the TIFF payload of the first Exif APP1 segment.
+func jpegEXIF(data []byte) []byte {
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return nil
+		}
+		marker := data[pos+1]
+		if marker == 0xD8 || marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			pos += 2
+			continue
+		}
+		if marker == 0xDA || marker == 0xD9 {
+			return nil
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return nil
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:]
+		}
+		pos += 2 + length
+	}
+	return nil
+}
+
+// pngEXIF returns the payload of the eXIf chunk.
+func pngEXIF(data []byte) []byte {
+	pos := 8
+	for pos+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		chunkType := string(data[pos+4 : pos+8])
+		if length < 0 || pos+12+length > len(data) {
+			return nil
+		}
+		switch chunkType {
+		case "eXIf":
+			return data[pos+8 : pos+8+length]
+		case "IDAT", "IEND":
+			// eXIf must precede the image data.
+			return nil
+		}
+		pos += 12 + length
+	}
+	return nil
+}
+
+type ifdEntry struct {
+	kind  uint16
+	count uint32
+	value []byte
+}
+
+type tiffReader struct {
+	data     []byte
+	order    binary.ByteOrder
+	firstIFD uint32
+}
+
+var typeSizes = map[uint16]uint32{1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 7: 1, 9: 4, 10: 8}
+
+func newTIFFReader(data []byte) (*tiffReader, error) {
+	if len(data) < 8 {
+		return nil, errInvalidEXIF
+	}
+	var order binary.ByteOrder
+	switch string(data[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, errInvalidEXIF
+	}
+	if order.Uint16(data[2:]) != 42 {
+		return nil, errInvalidEXIF
+	}
+	return &tiffReader{data: data, order: order, firstIFD: order.Uint32(data[4:])}, nil
+}
+
+func (r *tiffReader) readIFD(offset uint32) (map[uint16]ifdEntry, error) {
+	start := int(offset)
+	if start < 8 || start+2 > len(r.data) {
+		return nil, errInvalidEXIF
+	}
+	count := int(r.order.Uint16(r.data[start:]))
+	if start+2+count*12 > len(r.data) {
+		return nil, errInvalidEXIF
+	}
+
+	entries := make(map[uint16]ifdEntry, count)
+	for i := 0; i < count; i++ {
+		raw := r.data[start+2+i*12 : start+14+i*12]
+		kind := r.order.Uint16(raw[2:])
+		size, known := typeSizes[kind]
+		if !known {
+			continue
+		}
+		valueCount := r.order.Uint32(raw[4:])
+		total := uint64(size) * uint64(valueCount)
+		var value []byte
+		if total <= 4 {
+			value = raw[8 : 8+total]
+		} else {
+			valueOffset := uint64(r.order.Uint32(raw[8:]))
+			if valueOffset+total > uint64(len(r.data)) {
+				continue
+			}
+			value = r.data[valueOffset : valueOffset+total]
+		}
+		entries[r.order.Uint16(raw)] = ifdEntry{kind: kind, count: valueCount, value: value}
+	}
+	return entries, nil
+}
+
+func (r *tiffReader) ascii(entry ifdEntry) string {
+	if entry.kind != 2 {
+		return ""
+	}
+	value := string(entry.value)
+	if idx := strings.IndexByte(value, 0); idx >= 0 {
+		value = value[:idx]
+	}
+	return strings.TrimSpace(value)
+}
+
+func (r *tiffReader) long(entry ifdEntry) uint32 {
+	switch {
+	case entry.kind == 4 && len(entry.value) >= 4:
+		return r.order.Uint32(entry.value)
+	case entry.kind == 3 && len(entry.value) >= 2:
+		return uint32(r.order.Uint16(entry.value))
+	}
+	return 0
+}
+
+func (r *tiffReader) rationals(entry ifdEntry) []float64 {
+	if entry.kind != 5 {
+		return nil
+	}
+	values := make([]float64, 0, entry.count)
+	for i := 0; i+8 <= len(entry.value); i += 8 {
+		numerator := r.order.Uint32(entry.value[i:])
+		denominator := r.order.Uint32(entry.value[i+4:])
+		if denominator == 0 {
+			return nil
+		}
+		values = append(values, float64(numerator)/float64(denominator))
+	}
+	return values
+}
+
+// coordinate converts degrees, minutes and seconds to signed decimal
+// degrees; negativeRef is "S" or "W".
+func (r *tiffReader) coordinate(entry ifdEntry, ref string, negativeRef string) *float64 {
+	parts := r.rationals(entry)
+	if len(parts) != 3 {
+		return nil
+	}
+	value := parts[0] + parts[1]/60 + parts[2]/3600
+	if strings.EqualFold(ref, negativeRef) {
+		value = -value
+	}
+	if math.IsNaN(value) || math.Abs(value) > 180 {
+		return nil
+	}
+	return &value
+}
+
+func (r *tiffReader) gpsTimestamp(dateEntry, timeEntry ifdEntry) *time.Time {
+	date := r.ascii(dateEntry)
+	clock := r.rationals(timeEntry)
+	if date == "" || len(clock) != 3 {
+		return nil
+	}
+	day, err := time.Parse("2006:01:02", date)
+	if err != nil {
+		return nil
+	}
+	seconds := clock[0]*3600 + clock[1]*60 + clock[2]
+	parsed := day.Add(time.Duration(seconds * float64(time.Second)))
+	return &parsed
+}
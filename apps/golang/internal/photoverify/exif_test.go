@@ -0,0 +1,218 @@
+package photoverify
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"testing"
+	"time"
+)
+
+type testTag struct {
+	tag   uint16
+	kind  uint16
+	count uint32
+	data  []byte
+}
+
+func asciiTag(tag uint16, value string) testTag {
+	return testTag{tag: tag, kind: 2, count: uint32(len(value) + 1), data: append([]byte(value), 0)}
+}
+
+func longTag(tag uint16, value uint32) testTag {
+	data := make([]byte, 4)
+	binary.LittleEndian.PutUint32(data, value)
+	return testTag{tag: tag, kind: 4, count: 1, data: data}
+}
+
+func rationalTag(tag uint16, values ...[2]uint32) testTag {
+	data := make([]byte, 0, 8*len(values))
+	for _, v := range values {
+		data = binary.LittleEndian.AppendUint32(data, v[0])
+		data = binary.LittleEndian.AppendUint32(data, v[1])
+	}
+	return testTag{tag: tag, kind: 5, count: uint32(len(values)), data: data}
+}
+
+// buildTIFF lays out IFD0 followed by the Exif and GPS IFDs, patching the
+// pointers in IFD0 to where they land.
+func buildTIFF(ifd0, exifIFD, gpsIFD []testTag) []byte {
+	ifdSize := func(tags []testTag) int {
+		size := 2 + 12*len(tags) + 4
+		for _, t := range tags {
+			if len(t.data) > 4 {
+				size += len(t.data)
+			}
+		}
+		return size
+	}
+	if len(exifIFD) > 0 {
+		ifd0 = append(ifd0, longTag(tagExifIFD, 0))
+	}
+	if len(gpsIFD) > 0 {
+		ifd0 = append(ifd0, longTag(tagGPSIFD, 0))
+	}
+	exifOffset := 8 + ifdSize(ifd0)
+	gpsOffset := exifOffset + ifdSize(exifIFD)
+	for i := range ifd0 {
+		switch ifd0[i].tag {
+		case tagExifIFD:
+			ifd0[i] = longTag(tagExifIFD, uint32(exifOffset))
+		case tagGPSIFD:
+			ifd0[i] = longTag(tagGPSIFD, uint32(gpsOffset))
+		}
+	}
+
+	out := []byte("II*\x00")
+	out = binary.LittleEndian.AppendUint32(out, 8)
+	writeIFD := func(tags []testTag) {
+		start := len(out)
+		extra := start + 2 + 12*len(tags) + 4
+		var payload []byte
+		out = binary.LittleEndian.AppendUint16(out, uint16(len(tags)))
+		for _, t := range tags {
+			out = binary.LittleEndian.AppendUint16(out, t.tag)
+			out = binary.LittleEndian.AppendUint16(out, t.kind)
+			out = binary.LittleEndian.AppendUint32(out, t.count)
+			if len(t.data) <= 4 {
+				value := make([]byte, 4)
+				copy(value, t.data)
+				out = append(out, value...)
+				continue
+			}
+			out = binary.LittleEndian.AppendUint32(out, uint32(extra+len(payload)))
+			payload = append(payload, t.data...)
+		}
+		out = binary.LittleEndian.AppendUint32(out, 0)
+		out = append(out, payload...)
+	}
+	writeIFD(ifd0)
+	if len(exifIFD) > 0 {
+		writeIFD(exifIFD)
+	}
+	if len(gpsIFD) > 0 {
+		writeIFD(gpsIFD)
+	}
+	return out
+}
+
+func jpegWithEXIF(t *testing.T, tiff []byte) []byte {
+	t.Helper()
+	var img bytes.Buffer
+	if err := jpeg.Encode(&img, image.NewGray(image.Rect(0, 0, 8, 8)), nil); err != nil {
+		t.Fatal(err)
+	}
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	raw := img.Bytes()
+	return append(append(append([]byte{}, raw[:2]...), segment...), raw[2:]...)
+}
+
+func pngWithEXIF(t *testing.T, tiff []byte) []byte {
+	t.Helper()
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewGray(image.Rect(0, 0, 8, 8))); err != nil {
+		t.Fatal(err)
+	}
+	raw := img.Bytes()
+	// Signature (8) plus the IHDR chunk (25) come first.
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(tiff)))
+	chunk = append(chunk, "eXIf"...)
+	chunk = append(chunk, tiff...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+	return append(append(append([]byte{}, raw[:33]...), chunk...), raw[33:]...)
+}
+
+func sampleTIFF() []byte {
+	return buildTIFF(
+		[]testTag{asciiTag(tagMake, "samsung"), asciiTag(tagModel, "SM-A155F")},
+		[]testTag{asciiTag(tagDateTimeOriginal, "2026:10:18 09:15:30"), asciiTag(tagOffsetTimeOriginal, "+07:00")},
+		[]testTag{
+			asciiTag(tagGPSLatitudeRef, "S"),
+			rationalTag(tagGPSLatitude, [2]uint32{0, 1}, [2]uint32{30, 1}, [2]uint32{36, 1}),
+			asciiTag(tagGPSLongitudeRef, "E"),
+			rationalTag(tagGPSLongitude, [2]uint32{101, 1}, [2]uint32{26, 1}, [2]uint32{2400, 100}),
+		},
+	)
+}
+
+func TestExtractMetadata_ReadsJPEGAndPNG(t *testing.T) {
+	want := time.Date(2026, 10, 18, 2, 15, 30, 0, time.UTC)
+	for name, data := range map[string][]byte{
+		"jpeg": jpegWithEXIF(t, sampleTIFF()),
+		"png":  pngWithEXIF(t, sampleTIFF()),
+	} {
+		meta, err := ExtractMetadata(data, time.UTC)
+		if err != nil {
+			t.Fatalf("%s: ExtractMetadata: %v", name, err)
+		}
+		if meta.Make != "samsung" || meta.Model != "SM-A155F" {
+			t.Errorf("%s: camera = %q %q", name, meta.Make, meta.Model)
+		}
+		if meta.CapturedAt == nil || !meta.CapturedAt.Equal(want) {
+			t.Errorf("%s: captured at %v, want %v", name, meta.CapturedAt, want)
+		}
+		if meta.Latitude == nil || math.Abs(*meta.Latitude-(-0.51)) > 1e-9 {
+			t.Errorf("%s: latitude %v", name, meta.Latitude)
+		}
+		if meta.Longitude == nil || math.Abs(*meta.Longitude-101.44) > 1e-9 {
+			t.Errorf("%s: longitude %v", name, meta.Longitude)
+		}
+	}
+}
+
+func TestExtractMetadata_CaptureTimeZones(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*60*60)
+
+	unzoned := buildTIFF(nil, []testTag{asciiTag(tagDateTimeOriginal, "2026:10:18 09:15:30")}, nil)
+	meta, err := ExtractMetadata(jpegWithEXIF(t, unzoned), wib)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 10, 18, 9, 15, 30, 0, wib); meta.CapturedAt == nil || !meta.CapturedAt.Equal(want) {
+		t.Errorf("unzoned capture read as %v, want %v", meta.CapturedAt, want)
+	}
+
+	// The GPS clock is UTC and wins over an unzoned camera clock.
+	withGPS := buildTIFF(nil,
+		[]testTag{asciiTag(tagDateTimeOriginal, "2026:10:18 09:15:30")},
+		[]testTag{
+			asciiTag(tagGPSDateStamp, "2026:10:18"),
+			rationalTag(tagGPSTimeStamp, [2]uint32{1, 1}, [2]uint32{15, 1}, [2]uint32{0, 1}),
+		},
+	)
+	meta, err = ExtractMetadata(jpegWithEXIF(t, withGPS), wib)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 10, 18, 1, 15, 0, 0, time.UTC); meta.CapturedAt == nil || !meta.CapturedAt.Equal(want) {
+		t.Errorf("GPS capture read as %v, want %v", meta.CapturedAt, want)
+	}
+	if meta.Latitude != nil {
+		t.Errorf("latitude should be missing, got %v", *meta.Latitude)
+	}
+}
+
+func TestExtractMetadata_WithoutEXIF(t *testing.T) {
+	var img bytes.Buffer
+	if err := jpeg.Encode(&img, image.NewGray(image.Rect(0, 0, 4, 4)), nil); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := ExtractMetadata(img.Bytes(), time.UTC)
+	if err == nil || meta.HasEXIF() {
+		t.Fatalf("expected no EXIF, got %+v, %v", meta, err)
+	}
+
+	// Truncated or hostile EXIF must not panic.
+	broken := jpegWithEXIF(t, []byte("II*\x00\xff\xff\xff\x7f"))
+	if _, err := ExtractMetadata(broken, time.UTC); err == nil {
+		t.Fatal("expected an error for a bad IFD offset")
+	}
+}
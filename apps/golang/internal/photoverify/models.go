@@ -0,0 +1,155 @@
+package photoverify
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type RecordType string
+
+const (
+	RecordHarvest  RecordType = "HARVEST"
+	RecordGuestLog RecordType = "GUEST_LOG"
+)
+
+// Status enum.
+type Status string
+
+const (
+	StatusVerified Status = "VERIFIED"
+	StatusFlagged  Status = "FLAGGED"
+)
+
+func (e Status) IsValid() bool {
+	switch e {
+	case StatusVerified, StatusFlagged:
+		return true
+	}
+	return false
+}
+
+func (e Status) String() string {
+	return string(e)
+}
+
+func (e *Status) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+	*e = Status(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PhotoVerificationStatus", str)
+	}
+	return nil
+}
+
+func (e Status) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// Flag enum: one reason an approver should look at the photo.
+type Flag string
+
+const (
+	FlagNoMetadata          Flag = "NO_METADATA"
+	FlagCaptureTimeMissing  Flag = "CAPTURE_TIME_MISSING"
+	FlagCaptureTimeMismatch Flag = "CAPTURE_TIME_MISMATCH"
+	FlagLocationMissing     Flag = "LOCATION_MISSING"
+	FlagLocationMismatch    Flag = "LOCATION_MISMATCH"
+	FlagPhotoReused         Flag = "PHOTO_REUSED"
+)
+
+var AllFlags = []Flag{
+	FlagNoMetadata,
+	FlagCaptureTimeMissing,
+	FlagCaptureTimeMismatch,
+	FlagLocationMissing,
+	FlagLocationMismatch,
+	FlagPhotoReused,
+}
+
+func (e Flag) IsValid() bool {
+	for _, flag := range AllFlags {
+		if e == flag {
+			return true
+		}
+	}
+	return false
+}
+
+func (e Flag) String() string {
+	return string(e)
+}
+
+// Description is the sentence shown to approvers next to the record.
+func (e Flag) Description() string {
+	switch e {
+	case FlagNoMetadata:
+		return "Foto tidak memiliki metadata EXIF"
+	case FlagCaptureTimeMissing:
+		return "Waktu pengambilan foto tidak tersedia"
+	case FlagCaptureTimeMismatch:
+		return "Waktu pengambilan foto tidak sesuai dengan waktu record"
+	case FlagLocationMissing:
+		return "Lokasi GPS foto tidak tersedia"
+	case FlagLocationMismatch:
+		return "Lokasi GPS foto jauh dari lokasi record"
+	case FlagPhotoReused:
+		return "Foto yang sama sudah dipakai di record lain"
+	}
+	return string(e)
+}
+
+func (e *Flag) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+	*e = Flag(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PhotoVerificationFlag", str)
+	}
+	return nil
+}
+
+func (e Flag) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// Verification is the EXIF check of one photo attached to a harvest or
+// guest record.
+type Verification struct {
+	ID                  string     `json:"id" gorm:"primaryKey;type:uuid;default:gen_random_uuid()"`
+	CompanyID           string     `json:"companyId" gorm:"type:uuid;not null"`
+	RecordType          RecordType `json:"recordType" gorm:"type:varchar(20);not null"`
+	RecordID            string     `json:"recordId" gorm:"type:uuid;not null"`
+	PhotoURL            string     `json:"photoUrl" gorm:"column:photo_url;type:varchar(512);not null"`
+	SHA256              string     `json:"sha256" gorm:"column:sha256;type:varchar(64);not null"`
+	Status              Status     `json:"status" gorm:"type:varchar(20);not null"`
+	Flags               []Flag     `json:"flags" gorm:"type:jsonb;serializer:json;not null"`
+	CapturedAt          *time.Time `json:"capturedAt,omitempty"`
+	CaptureDriftMinutes *float64   `json:"captureDriftMinutes,omitempty"`
+	Latitude            *float64   `json:"latitude,omitempty"`
+	Longitude           *float64   `json:"longitude,omitempty"`
+	DistanceMeters      *float64   `json:"distanceMeters,omitempty"`
+	DeviceMake          *string    `json:"deviceMake,omitempty" gorm:"type:varchar(100)"`
+	DeviceModel         *string    `json:"deviceModel,omitempty" gorm:"type:varchar(100)"`
+	Software            *string    `json:"software,omitempty" gorm:"type:varchar(100)"`
+	DuplicateRecordType *string    `json:"duplicateRecordType,omitempty" gorm:"type:varchar(20)"`
+	DuplicateRecordID   *string    `json:"duplicateRecordId,omitempty" gorm:"type:uuid"`
+	VerifiedAt          time.Time  `json:"verifiedAt" gorm:"not null"`
+}
+
+func (Verification) TableName() string {
+	return "photo_verifications"
+}
+
+// Subject identifies the photo to check and the record it belongs to.
+type Subject struct {
+	RecordType RecordType
+	RecordID   string
+	PhotoURL   string
+}
@@ -0,0 +1,334 @@
+package photoverify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"agrinovagraphql/server/pkg/storage"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// Harvest photos must be taken on the harvest date, with some grace for
+	// work that runs past midnight.
+	harvestCaptureGrace = 2 * time.Hour
+	// Gate photos are taken while the vehicle is at the gate.
+	gateCaptureTolerance = 30 * time.Minute
+	// Capture times ahead of the server clock point to a tampered device clock.
+	futureCaptureTolerance = 10 * time.Minute
+
+	harvestLocationToleranceMeters = 2000
+	gateLocationToleranceMeters    = 500
+
+	maxVerifiedPhotoSize = 10 * 1024 * 1024
+	earthRadiusMeters    = 6371000
+)
+
+var ErrRecordNotFound = errors.New("photo record not found")
+
+// reference is what a photo is compared against: when and where its record
+// was captured on the device. Blocks and gates have no surveyed position,
+// so the record's own GPS fix stands in for the block or gate location.
+type reference struct {
+	companyID string
+	times     []time.Time
+	dayOnly   bool
+	latitude  *float64
+	longitude *float64
+}
+
+// duplicate is an earlier record that used the same photo.
+type duplicate struct {
+	RecordType RecordType
+	RecordID   string
+}
+
+// Service extracts EXIF metadata from harvest and gate photos and records
+// the verification flags approvers see next to the record.
+type Service struct {
+	db      *gorm.DB
+	uploads storage.Store
+	loc     *time.Location
+	now     func() time.Time
+}
+
+func NewService(db *gorm.DB, uploads storage.Store) *Service {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		loc = time.FixedZone("WIB", 7*60*60)
+	}
+	return &Service{db: db, uploads: uploads, loc: loc, now: time.Now}
+}
+
+// VerifyStored verifies a photo already saved under an `/uploads` path.
+// Photos kept elsewhere (external URLs) are skipped and return nil.
+func (s *Service) VerifyStored(ctx context.Context, subject Subject) (*Verification, error) {
+	key, ok := storage.KeyFromURLPath(subject.PhotoURL)
+	if !ok {
+		return nil, nil
+	}
+	body, _, err := s.uploads.Open(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("open photo: %w", err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, maxVerifiedPhotoSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read photo: %w", err)
+	}
+	if len(data) > maxVerifiedPhotoSize {
+		return nil, fmt.Errorf("photo exceeds %d bytes", maxVerifiedPhotoSize)
+	}
+	return s.Verify(ctx, subject, data)
+}
+
+// Verify checks data against its record and stores the result, replacing
+// an earlier check of the same photo on the same record.
+func (s *Service) Verify(ctx context.Context, subject Subject, data []byte) (*Verification, error) {
+	ref, err := s.loadReference(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	meta, _ := ExtractMetadata(data, s.loc)
+
+	var dup *duplicate
+	var earlier []duplicate
+	err = s.db.WithContext(ctx).Model(&Verification{}).
+		Select("record_type, record_id").
+		Where("company_id = ? AND sha256 = ? AND NOT (record_type = ? AND record_id = ?)", ref.companyID, hash, subject.RecordType, subject.RecordID).
+		Order("verified_at").
+		Limit(1).
+		Scan(&earlier).Error
+	if err != nil {
+		return nil, fmt.Errorf("look up reused photos: %w", err)
+	}
+	if len(earlier) > 0 {
+		dup = &earlier[0]
+	}
+
+	verification := evaluate(subject, ref, meta, dup, s.now(), s.loc)
+	verification.CompanyID = ref.companyID
+	verification.SHA256 = hash
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// A harvest carries one photo; checks of a replaced photo are stale.
+		if subject.RecordType == RecordHarvest {
+			if err := tx.Where("record_type = ? AND record_id = ? AND sha256 <> ?", subject.RecordType, subject.RecordID, hash).
+				Delete(&Verification{}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "record_type"}, {Name: "record_id"}, {Name: "sha256"}},
+			UpdateAll: true,
+		}).Create(verification).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("save photo verification: %w", err)
+	}
+	return verification, nil
+}
+
+// ForRecords returns the verifications of the given records keyed by
+// record ID, oldest first.
+func (s *Service) ForRecords(ctx context.Context, recordType RecordType, recordIDs []string) (map[string][]*Verification, error) {
+	result := make(map[string][]*Verification, len(recordIDs))
+	if len(recordIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []*Verification
+	if err := s.db.WithContext(ctx).
+		Where("record_type = ? AND record_id IN ?", recordType, recordIDs).
+		Order("verified_at").
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("load photo verifications: %w", err)
+	}
+	for _, row := range rows {
+		result[row.RecordID] = append(result[row.RecordID], row)
+	}
+	return result, nil
+}
+
+func (s *Service) loadReference(ctx context.Context, subject Subject) (reference, error) {
+	var row struct {
+		CompanyID *string
+		Tanggal   *time.Time
+		EntryTime *time.Time
+		ExitTime  *time.Time
+		CreatedAt *time.Time
+		Latitude  *float64
+		Longitude *float64
+	}
+
+	var query *gorm.DB
+	switch subject.RecordType {
+	case RecordHarvest:
+		query = s.db.WithContext(ctx).Table("harvest_records AS h").
+			Select("COALESCE(h.company_id, e.company_id) AS company_id, h.tanggal, h.latitude, h.longitude").
+			Joins("LEFT JOIN blocks b ON b.id = h.block_id").
+			Joins("LEFT JOIN divisions d ON d.id = b.division_id").
+			Joins("LEFT JOIN estates e ON e.id = d.estate_id").
+			Where("h.id = ?", subject.RecordID)
+	case RecordGuestLog:
+		query = s.db.WithContext(ctx).Table("gate_guest_logs").
+			Select("company_id, entry_time, exit_time, created_at, latitude, longitude").
+			Where("id = ?", subject.RecordID)
+	default:
+		return reference{}, fmt.Errorf("unknown record type %q", subject.RecordType)
+	}
+
+	result := query.Limit(1).Scan(&row)
+	if result.Error != nil {
+		return reference{}, fmt.Errorf("load photo record: %w", result.Error)
+	}
+	if result.RowsAffected == 0 || row.CompanyID == nil || *row.CompanyID == "" {
+		return reference{}, ErrRecordNotFound
+	}
+
+	ref := reference{companyID: *row.CompanyID, latitude: row.Latitude, longitude: row.Longitude}
+	if subject.RecordType == RecordHarvest {
+		ref.dayOnly = true
+		if row.Tanggal != nil {
+			ref.times = append(ref.times, *row.Tanggal)
+		}
+		return ref, nil
+	}
+	for _, at := range []*time.Time{row.EntryTime, row.ExitTime} {
+		if at != nil {
+			ref.times = append(ref.times, *at)
+		}
+	}
+	if len(ref.times) == 0 && row.CreatedAt != nil {
+		ref.times = append(ref.times, *row.CreatedAt)
+	}
+	return ref, nil
+}
+
+// evaluate compares a photo's metadata with its record.
+func evaluate(subject Subject, ref reference, meta Metadata, dup *duplicate, now time.Time, loc *time.Location) *Verification {
+	verification := &Verification{
+		RecordType:  subject.RecordType,
+		RecordID:    subject.RecordID,
+		PhotoURL:    subject.PhotoURL,
+		CapturedAt:  meta.CapturedAt,
+		Latitude:    meta.Latitude,
+		Longitude:   meta.Longitude,
+		DeviceMake:  optionalString(meta.Make, 100),
+		DeviceModel: optionalString(meta.Model, 100),
+		Software:    optionalString(meta.Software, 100),
+		VerifiedAt:  now,
+	}
+	flags := []Flag{}
+
+	if !meta.HasEXIF() {
+		flags = append(flags, FlagNoMetadata)
+	} else {
+		switch {
+		case meta.CapturedAt == nil:
+			flags = append(flags, FlagCaptureTimeMissing)
+		case len(ref.times) > 0:
+			drift := captureDrift(*meta.CapturedAt, ref, loc)
+			minutes := math.Round(drift.Minutes()*10) / 10
+			verification.CaptureDriftMinutes = &minutes
+			tolerance := gateCaptureTolerance
+			if ref.dayOnly {
+				tolerance = 0
+			}
+			if drift > tolerance || drift < -tolerance || meta.CapturedAt.After(now.Add(futureCaptureTolerance)) {
+				flags = append(flags, FlagCaptureTimeMismatch)
+			}
+		case meta.CapturedAt.After(now.Add(futureCaptureTolerance)):
+			flags = append(flags, FlagCaptureTimeMismatch)
+		}
+
+		switch {
+		case meta.Latitude == nil || meta.Longitude == nil:
+			flags = append(flags, FlagLocationMissing)
+		case ref.latitude != nil && ref.longitude != nil:
+			distance := math.Round(haversineMeters(*meta.Latitude, *meta.Longitude, *ref.latitude, *ref.longitude))
+			verification.DistanceMeters = &distance
+			limit := float64(gateLocationToleranceMeters)
+			if subject.RecordType == RecordHarvest {
+				limit = harvestLocationToleranceMeters
+			}
+			if distance > limit {
+				flags = append(flags, FlagLocationMismatch)
+			}
+		}
+	}
+
+	if dup != nil {
+		flags = append(flags, FlagPhotoReused)
+		recordType := string(dup.RecordType)
+		recordID := dup.RecordID
+		verification.DuplicateRecordType = &recordType
+		verification.DuplicateRecordID = &recordID
+	}
+
+	verification.Flags = flags
+	verification.Status = StatusVerified
+	if len(flags) > 0 {
+		verification.Status = StatusFlagged
+	}
+	return verification
+}
+
+// captureDrift is how far captured lies outside the record's time. Harvest
+// records carry a date, so anywhere on that day (plus grace) counts as zero;
+// guest logs compare with the closest of entry and exit. Negative values
+// mean the photo predates the record.
+func captureDrift(captured time.Time, ref reference, loc *time.Location) time.Duration {
+	if ref.dayOnly {
+		day := ref.times[0].In(loc)
+		start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc).Add(-harvestCaptureGrace)
+		end := start.AddDate(0, 0, 1).Add(2 * harvestCaptureGrace)
+		switch {
+		case captured.Before(start):
+			return captured.Sub(start)
+		case captured.After(end):
+			return captured.Sub(end)
+		}
+		return 0
+	}
+
+	best := captured.Sub(ref.times[0])
+	for _, at := range ref.times[1:] {
+		if drift := captured.Sub(at); math.Abs(float64(drift)) < math.Abs(float64(best)) {
+			best = drift
+		}
+	}
+	return best
+}
+
+func haversineMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+func optionalString(value string, limit int) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if len(value) > limit {
+		value = strings.ToValidUTF8(value[:limit], "")
+	}
+	return &value
+}
@@ -0,0 +1,116 @@
+package photoverify
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func floatPtr(v float64) *float64    { return &v }
+func timePtr(v time.Time) *time.Time { return &v }
+
+func TestEvaluate_Flags(t *testing.T) {
+	wib := time.FixedZone("WIB", 7*60*60)
+	now := time.Date(2026, 10, 18, 15, 0, 0, 0, wib)
+	harvestDay := time.Date(2026, 10, 18, 0, 0, 0, 0, wib)
+	entry := time.Date(2026, 10, 18, 8, 0, 0, 0, wib)
+	exit := time.Date(2026, 10, 18, 11, 0, 0, 0, wib)
+
+	harvest := Subject{RecordType: RecordHarvest, RecordID: "h1", PhotoURL: "/uploads/harvest_photos/c1/a.jpg"}
+	guest := Subject{RecordType: RecordGuestLog, RecordID: "g1", PhotoURL: "/uploads/satpam_photos/c1/b.jpg"}
+	harvestRef := reference{companyID: "c1", times: []time.Time{harvestDay}, dayOnly: true, latitude: floatPtr(0.5), longitude: floatPtr(101.4)}
+	gateRef := reference{companyID: "c1", times: []time.Time{entry, exit}, latitude: floatPtr(0.5), longitude: floatPtr(101.4)}
+
+	tests := []struct {
+		name      string
+		subject   Subject
+		ref       reference
+		meta      Metadata
+		dup       *duplicate
+		wantFlags []Flag
+		wantDrift *float64
+	}{
+		{
+			name:      "harvest photo on the day and in the block",
+			subject:   harvest,
+			ref:       harvestRef,
+			meta:      Metadata{Make: "x", CapturedAt: timePtr(harvestDay.Add(10 * time.Hour)), Latitude: floatPtr(0.505), Longitude: floatPtr(101.4)},
+			wantFlags: []Flag{},
+			wantDrift: floatPtr(0),
+		},
+		{
+			name:      "harvest photo from the previous week, far away",
+			subject:   harvest,
+			ref:       harvestRef,
+			meta:      Metadata{Make: "x", CapturedAt: timePtr(harvestDay.AddDate(0, 0, -7)), Latitude: floatPtr(0.6), Longitude: floatPtr(101.4)},
+			wantFlags: []Flag{FlagCaptureTimeMismatch, FlagLocationMismatch},
+			wantDrift: floatPtr(-(6*24 + 22) * 60),
+		},
+		{
+			name:      "gate photo at exit is matched against the exit time",
+			subject:   guest,
+			ref:       gateRef,
+			meta:      Metadata{Model: "y", CapturedAt: timePtr(exit.Add(-10 * time.Minute)), Latitude: floatPtr(0.501), Longitude: floatPtr(101.4)},
+			wantFlags: []Flag{},
+			wantDrift: floatPtr(-10),
+		},
+		{
+			name:      "gate photo between entry and exit without GPS",
+			subject:   guest,
+			ref:       gateRef,
+			meta:      Metadata{Model: "y", CapturedAt: timePtr(entry.Add(90 * time.Minute))},
+			wantFlags: []Flag{FlagCaptureTimeMismatch, FlagLocationMissing},
+			wantDrift: floatPtr(90),
+		},
+		{
+			name:      "capture time in the future",
+			subject:   guest,
+			ref:       reference{companyID: "c1"},
+			meta:      Metadata{Model: "y", CapturedAt: timePtr(now.Add(time.Hour)), Latitude: floatPtr(0.5), Longitude: floatPtr(101.4)},
+			wantFlags: []Flag{FlagCaptureTimeMismatch},
+		},
+		{
+			name:      "stripped photo used on another record",
+			subject:   harvest,
+			ref:       harvestRef,
+			dup:       &duplicate{RecordType: RecordHarvest, RecordID: "h0"},
+			wantFlags: []Flag{FlagNoMetadata, FlagPhotoReused},
+		},
+		{
+			name:      "EXIF without capture time",
+			subject:   harvest,
+			ref:       harvestRef,
+			meta:      Metadata{Make: "x", Latitude: floatPtr(0.5), Longitude: floatPtr(101.4)},
+			wantFlags: []Flag{FlagCaptureTimeMissing},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := evaluate(tt.subject, tt.ref, tt.meta, tt.dup, now, wib)
+			if !reflect.DeepEqual(got.Flags, tt.wantFlags) {
+				t.Fatalf("flags = %v, want %v", got.Flags, tt.wantFlags)
+			}
+			wantStatus := StatusVerified
+			if len(tt.wantFlags) > 0 {
+				wantStatus = StatusFlagged
+			}
+			if got.Status != wantStatus {
+				t.Errorf("status = %s, want %s", got.Status, wantStatus)
+			}
+			if tt.wantDrift != nil && (got.CaptureDriftMinutes == nil || *got.CaptureDriftMinutes != *tt.wantDrift) {
+				t.Errorf("drift = %v, want %v", got.CaptureDriftMinutes, *tt.wantDrift)
+			}
+			if tt.dup != nil && (got.DuplicateRecordID == nil || *got.DuplicateRecordID != tt.dup.RecordID) {
+				t.Errorf("duplicate record = %v", got.DuplicateRecordID)
+			}
+		})
+	}
+}
+
+func TestHaversineMeters(t *testing.T) {
+	// One hundredth of a degree of latitude is about 1.11 km.
+	if got := haversineMeters(0.5, 101.4, 0.51, 101.4); got < 1100 || got > 1125 {
+		t.Fatalf("haversineMeters = %.1f", got)
+	}
+}
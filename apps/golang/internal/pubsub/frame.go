@@ -0,0 +1,139 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// maxNotifyPayload is PostgreSQL's NOTIFY payload limit (8000 bytes)
+	// less headroom for the frame header.
+	maxNotifyPayload = 7800
+	// chunkSize is the raw bytes per frame; base64 grows it by 4/3.
+	chunkSize = 5400
+	// maxChunks bounds a single event at roughly 1.3 MB so one publisher
+	// cannot fill the server's notification queue.
+	maxChunks = 256
+	// partialTTL is how long an incomplete chunked event is kept.
+	partialTTL = 30 * time.Second
+)
+
+// frame is one NOTIFY payload. Events that fit travel in a single frame;
+// larger ones are split into Total frames sharing an ID and published in
+// one transaction, so they arrive contiguously and in order.
+type frame struct {
+	Origin string `json:"o"`
+	Topic  string `json:"t"`
+	ID     string `json:"i,omitempty"`
+	Seq    int    `json:"s,omitempty"`
+	Total  int    `json:"n,omitempty"`
+	Data   []byte `json:"d"`
+}
+
+// encodeFrames splits payload into NOTIFY-sized frames.
+func encodeFrames(origin, topic string, payload []byte) ([]string, error) {
+	single, err := json.Marshal(frame{Origin: origin, Topic: topic, Data: payload})
+	if err != nil {
+		return nil, err
+	}
+	if len(single) <= maxNotifyPayload {
+		return []string{string(single)}, nil
+	}
+
+	total := (len(payload) + chunkSize - 1) / chunkSize
+	if total > maxChunks {
+		return nil, fmt.Errorf("%w: %d bytes on topic %s", ErrPayloadTooLarge, len(payload), topic)
+	}
+
+	id := uuid.NewString()
+	frames := make([]string, 0, total)
+	for seq := 0; seq < total; seq++ {
+		end := min((seq+1)*chunkSize, len(payload))
+		encoded, err := json.Marshal(frame{
+			Origin: origin,
+			Topic:  topic,
+			ID:     id,
+			Seq:    seq,
+			Total:  total,
+			Data:   payload[seq*chunkSize : end],
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(encoded) > maxNotifyPayload {
+			return nil, fmt.Errorf("%w: topic name %q is too long", ErrPayloadTooLarge, topic)
+		}
+		frames = append(frames, string(encoded))
+	}
+	return frames, nil
+}
+
+type partialEvent struct {
+	topic    string
+	chunks   [][]byte
+	received int
+	started  time.Time
+}
+
+// assembler rebuilds chunked events. It is only used from the listener
+// goroutine and is not safe for concurrent use.
+type assembler struct {
+	partial map[string]*partialEvent
+	now     func() time.Time
+}
+
+func newAssembler() *assembler {
+	return &assembler{partial: make(map[string]*partialEvent), now: time.Now}
+}
+
+// add consumes one frame and returns the complete event once all of its
+// chunks have arrived.
+func (a *assembler) add(f frame) (topic string, payload []byte, complete bool) {
+	if f.Total <= 1 {
+		return f.Topic, f.Data, true
+	}
+	if f.Seq < 0 || f.Seq >= f.Total || f.Total > maxChunks {
+		return "", nil, false
+	}
+
+	now := a.now()
+	a.expire(now)
+
+	event := a.partial[f.ID]
+	if event == nil {
+		event = &partialEvent{topic: f.Topic, chunks: make([][]byte, f.Total), started: now}
+		a.partial[f.ID] = event
+	}
+	if len(event.chunks) != f.Total || event.chunks[f.Seq] != nil {
+		return "", nil, false
+	}
+	event.chunks[f.Seq] = f.Data
+	event.received++
+	if event.received < f.Total {
+		return "", nil, false
+	}
+
+	delete(a.partial, f.ID)
+	size := 0
+	for _, chunk := range event.chunks {
+		size += len(chunk)
+	}
+	payload = make([]byte, 0, size)
+	for _, chunk := range event.chunks {
+		payload = append(payload, chunk...)
+	}
+	return event.topic, payload, true
+}
+
+// expire drops events whose remaining chunks were lost, e.g. when the
+// listener reconnected mid-event.
+func (a *assembler) expire(now time.Time) {
+	for id, event := range a.partial {
+		if now.Sub(event.started) > partialTTL {
+			delete(a.partial, id)
+		}
+	}
+}
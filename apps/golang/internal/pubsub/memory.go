@@ -0,0 +1,79 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var errClosed = errors.New("pubsub: closed")
+
+// Memory is an in-process PubSub. Publish calls the topic's handlers
+// synchronously, so delivery is complete when Publish returns.
+type Memory struct {
+	mu       sync.RWMutex
+	handlers map[string]map[uint64]Handler
+	nextID   uint64
+	closed   bool
+}
+
+var _ PubSub = (*Memory)(nil)
+
+// NewMemory creates an in-process PubSub.
+func NewMemory() *Memory {
+	return &Memory{handlers: make(map[string]map[uint64]Handler)}
+}
+
+// Publish implements PubSub.
+func (m *Memory) Publish(_ context.Context, topic string, payload []byte) error {
+	m.mu.RLock()
+	if m.closed {
+		m.mu.RUnlock()
+		return errClosed
+	}
+	handlers := make([]Handler, 0, len(m.handlers[topic]))
+	for _, handler := range m.handlers[topic] {
+		handlers = append(handlers, handler)
+	}
+	m.mu.RUnlock()
+
+	// Handlers run outside the lock so they may subscribe or unsubscribe.
+	for _, handler := range handlers {
+		handler(payload)
+	}
+	return nil
+}
+
+// Subscribe implements PubSub.
+func (m *Memory) Subscribe(topic string, handler Handler) func() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	id := m.nextID
+	if m.handlers[topic] == nil {
+		m.handlers[topic] = make(map[uint64]Handler)
+	}
+	m.handlers[topic][id] = handler
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			delete(m.handlers[topic], id)
+			if len(m.handlers[topic]) == 0 {
+				delete(m.handlers, topic)
+			}
+		})
+	}
+}
+
+// Close implements PubSub.
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	m.handlers = make(map[string]map[uint64]Handler)
+	return nil
+}
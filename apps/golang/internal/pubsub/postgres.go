@@ -0,0 +1,202 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultChannel is the NOTIFY channel shared by all topics.
+	DefaultChannel = "agrinova_events"
+
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+	// keepaliveInterval bounds how long the listener waits before pinging,
+	// so a silently dropped connection is noticed and replaced.
+	keepaliveInterval = 30 * time.Second
+)
+
+// PostgresConfig configures the LISTEN/NOTIFY driver.
+type PostgresConfig struct {
+	// DSN opens the dedicated listener connection.
+	DSN string
+	// Channel is the NOTIFY channel; defaults to DefaultChannel.
+	Channel string
+}
+
+// Postgres relays events between instances with LISTEN/NOTIFY. Local
+// subscribers are served directly, so they keep receiving local events
+// while the listener reconnects; remote events published during an outage
+// are lost, as NOTIFY is not durable.
+type Postgres struct {
+	db      *gorm.DB
+	dsn     string
+	channel string
+	origin  string
+	local   *Memory
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	closeOnce sync.Once
+}
+
+var _ PubSub = (*Postgres)(nil)
+
+// NewPostgres starts the listener and returns a driver that publishes
+// through db.
+func NewPostgres(db *gorm.DB, cfg PostgresConfig) (*Postgres, error) {
+	if db == nil {
+		return nil, errors.New("pubsub: postgres driver requires a database")
+	}
+	if strings.TrimSpace(cfg.DSN) == "" {
+		return nil, errors.New("pubsub: postgres driver requires a DSN")
+	}
+	channel := strings.TrimSpace(cfg.Channel)
+	if channel == "" {
+		channel = DefaultChannel
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Postgres{
+		db:      db,
+		dsn:     cfg.DSN,
+		channel: channel,
+		origin:  uuid.NewString(),
+		local:   NewMemory(),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go p.run(ctx)
+	return p, nil
+}
+
+// Publish implements PubSub.
+func (p *Postgres) Publish(ctx context.Context, topic string, payload []byte) error {
+	frames, err := encodeFrames(p.origin, topic, payload)
+	if err != nil {
+		return err
+	}
+	if err := p.local.Publish(ctx, topic, payload); err != nil {
+		return err
+	}
+
+	notify := func(tx *gorm.DB) error {
+		for _, f := range frames {
+			if err := tx.Exec("SELECT pg_notify(?, ?)", p.channel, f).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	db := p.db.WithContext(ctx)
+	if len(frames) == 1 {
+		err = notify(db)
+	} else {
+		// Notifications of one transaction are delivered together, in order.
+		err = db.Transaction(notify)
+	}
+	if err != nil {
+		return fmt.Errorf("pubsub: notify %s: %w", topic, err)
+	}
+	return nil
+}
+
+// Subscribe implements PubSub.
+func (p *Postgres) Subscribe(topic string, handler Handler) func() {
+	return p.local.Subscribe(topic, handler)
+}
+
+// Close stops the listener.
+func (p *Postgres) Close() error {
+	p.closeOnce.Do(func() {
+		p.cancel()
+		<-p.done
+		p.local.Close()
+	})
+	return nil
+}
+
+func (p *Postgres) run(ctx context.Context) {
+	defer close(p.done)
+
+	delay := minReconnectDelay
+	for {
+		connected, err := p.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			delay = minReconnectDelay
+		}
+		log.Printf("pubsub: listener on %q lost (%v), reconnecting in %s", p.channel, err, delay)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+// listen holds one listener connection until it fails. connected reports
+// whether LISTEN succeeded, which resets the reconnect backoff.
+func (p *Postgres) listen(ctx context.Context) (connected bool, err error) {
+	conn, err := pgx.Connect(ctx, p.dsn)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{p.channel}.Sanitize()); err != nil {
+		return false, err
+	}
+	log.Printf("pubsub: listening on %q", p.channel)
+
+	frames := newAssembler()
+	for {
+		waitCtx, cancel := context.WithTimeout(ctx, keepaliveInterval)
+		notification, err := conn.WaitForNotification(waitCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+				if err := conn.Ping(ctx); err != nil {
+					return true, err
+				}
+				continue
+			}
+			return true, err
+		}
+		p.receive(frames, notification.Payload)
+	}
+}
+
+func (p *Postgres) receive(frames *assembler, raw string) {
+	var f frame
+	if err := json.Unmarshal([]byte(raw), &f); err != nil {
+		log.Printf("pubsub: dropping malformed notification on %q: %v", p.channel, err)
+		return
+	}
+	if f.Origin == p.origin {
+		// Already delivered locally by Publish.
+		return
+	}
+
+	topic, payload, complete := frames.add(f)
+	if !complete {
+		return
+	}
+	_ = p.local.Publish(context.Background(), topic, payload)
+}
@@ -0,0 +1,56 @@
+// Package pubsub fans out real-time events across server instances so a
+// subscription opened on one instance sees mutations made on another.
+//
+// Two drivers are available: Memory, for a single instance and tests, and
+// Postgres, which relays events over LISTEN/NOTIFY and needs no extra
+// infrastructure.
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Drivers accepted by New.
+const (
+	DriverMemory   = "memory"
+	DriverPostgres = "postgres"
+)
+
+// ErrPayloadTooLarge is returned when a payload exceeds what a driver can
+// deliver, even after chunking.
+var ErrPayloadTooLarge = errors.New("pubsub: payload too large")
+
+// Handler receives a payload published to a subscribed topic. Handlers run
+// on the publishing goroutine (local events) or the listener goroutine
+// (remote events) and must not block.
+type Handler func(payload []byte)
+
+// PubSub publishes opaque payloads to named topics.
+type PubSub interface {
+	// Publish delivers payload to every subscriber of topic on every
+	// instance, including the local one.
+	Publish(ctx context.Context, topic string, payload []byte) error
+	// Subscribe registers handler for topic on this instance. The returned
+	// function removes the subscription.
+	Subscribe(topic string, handler Handler) (unsubscribe func())
+	// Close releases driver resources. Publishing after Close is an error.
+	Close() error
+}
+
+// New creates the PubSub selected by driver. db and cfg are only used by
+// the postgres driver; an empty driver selects memory.
+func New(driver string, db *gorm.DB, cfg PostgresConfig) (PubSub, error) {
+	switch strings.ToLower(strings.TrimSpace(driver)) {
+	case "", DriverMemory:
+		return NewMemory(), nil
+	case DriverPostgres:
+		return NewPostgres(db, cfg)
+	default:
+		return nil, fmt.Errorf("pubsub: unknown driver %q", driver)
+	}
+}
@@ -0,0 +1,175 @@
+package pubsub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMemory_PublishAndUnsubscribe(t *testing.T) {
+	bus := NewMemory()
+
+	var got []string
+	unsubscribe := bus.Subscribe("harvest.approved", func(payload []byte) {
+		got = append(got, string(payload))
+	})
+	bus.Subscribe("harvest.rejected", func([]byte) {
+		t.Error("handler of another topic was called")
+	})
+
+	if err := bus.Publish(context.Background(), "harvest.approved", []byte("one")); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	unsubscribe()
+	unsubscribe()
+	if err := bus.Publish(context.Background(), "harvest.approved", []byte("two")); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	if len(got) != 1 || got[0] != "one" {
+		t.Errorf("delivered = %v, want [one]", got)
+	}
+
+	bus.Close()
+	if err := bus.Publish(context.Background(), "harvest.approved", nil); err == nil {
+		t.Error("Publish after Close should fail")
+	}
+}
+
+func TestNew_Drivers(t *testing.T) {
+	if bus, err := New("", nil, PostgresConfig{}); err != nil || bus == nil {
+		t.Errorf("New(\"\") = %v, %v", bus, err)
+	}
+	if _, err := New(DriverPostgres, nil, PostgresConfig{}); err == nil {
+		t.Error("postgres driver without a database should fail")
+	}
+	if _, err := New("redis", nil, PostgresConfig{}); err == nil {
+		t.Error("unknown driver should fail")
+	}
+}
+
+func decodeFrames(t *testing.T, encoded []string) []frame {
+	t.Helper()
+	frames := make([]frame, len(encoded))
+	for i, raw := range encoded {
+		if len(raw) > maxNotifyPayload {
+			t.Fatalf("frame %d is %d bytes, over the NOTIFY limit", i, len(raw))
+		}
+		if err := json.Unmarshal([]byte(raw), &frames[i]); err != nil {
+			t.Fatalf("frame %d: %v", i, err)
+		}
+	}
+	return frames
+}
+
+func TestFrames_SmallPayloadIsSingleFrame(t *testing.T) {
+	encoded, err := encodeFrames("origin", "satpam.overstay", []byte(`{"id":"1"}`))
+	if err != nil {
+		t.Fatalf("encodeFrames: %v", err)
+	}
+	frames := decodeFrames(t, encoded)
+	if len(frames) != 1 {
+		t.Fatalf("frames = %d, want 1", len(frames))
+	}
+
+	topic, payload, complete := newAssembler().add(frames[0])
+	if !complete || topic != "satpam.overstay" || string(payload) != `{"id":"1"}` {
+		t.Errorf("add = %q, %q, %v", topic, payload, complete)
+	}
+}
+
+func TestFrames_ChunkedPayloadRoundTrip(t *testing.T) {
+	// Multi-byte runes must survive being split across chunk boundaries.
+	payload := []byte(strings.Repeat("panen ✓ ", 4000))
+	encoded, err := encodeFrames("origin", "harvest.approved", payload)
+	if err != nil {
+		t.Fatalf("encodeFrames: %v", err)
+	}
+	frames := decodeFrames(t, encoded)
+	if len(frames) < 2 {
+		t.Fatalf("frames = %d, want the payload to be chunked", len(frames))
+	}
+
+	other, err := encodeFrames("origin", "harvest.created", bytes.Repeat([]byte("x"), chunkSize*2))
+	if err != nil {
+		t.Fatalf("encodeFrames: %v", err)
+	}
+	otherFrames := decodeFrames(t, other)
+
+	// Interleave two events; each completes only on its last chunk.
+	a := newAssembler()
+	if _, _, complete := a.add(otherFrames[0]); complete {
+		t.Fatal("first chunk of the other event completed it")
+	}
+	for i, f := range frames {
+		topic, got, complete := a.add(f)
+		if i < len(frames)-1 {
+			if complete {
+				t.Fatalf("event completed after chunk %d of %d", i+1, len(frames))
+			}
+			continue
+		}
+		if !complete || topic != "harvest.approved" || !bytes.Equal(got, payload) {
+			t.Fatalf("reassembled %q (%d bytes), complete=%v", topic, len(got), complete)
+		}
+	}
+	if topic, _, complete := a.add(otherFrames[1]); !complete || topic != "harvest.created" {
+		t.Errorf("other event = %q, complete=%v", topic, complete)
+	}
+	if len(a.partial) != 0 {
+		t.Errorf("partial events left: %d", len(a.partial))
+	}
+}
+
+func TestFrames_TooLarge(t *testing.T) {
+	_, err := encodeFrames("origin", "topic", make([]byte, chunkSize*maxChunks+1))
+	if !errors.Is(err, ErrPayloadTooLarge) {
+		t.Errorf("error = %v, want ErrPayloadTooLarge", err)
+	}
+}
+
+func TestAssembler_ExpiresIncompleteEvents(t *testing.T) {
+	encoded, err := encodeFrames("origin", "topic", make([]byte, chunkSize*2))
+	if err != nil {
+		t.Fatalf("encodeFrames: %v", err)
+	}
+	frames := decodeFrames(t, encoded)
+
+	now := time.Now()
+	a := newAssembler()
+	a.now = func() time.Time { return now }
+	a.add(frames[0])
+
+	// The rest of the event was lost, e.g. during a reconnect.
+	now = now.Add(partialTTL + time.Second)
+	a.expire(now)
+	if len(a.partial) != 0 {
+		t.Fatalf("expired event still buffered")
+	}
+	if _, _, complete := a.add(frames[1]); complete {
+		t.Error("event completed without its first chunk")
+	}
+}
+
+func TestPostgres_ReceiveSkipsOwnNotifications(t *testing.T) {
+	p := &Postgres{channel: DefaultChannel, origin: "self", local: NewMemory()}
+
+	var got []string
+	p.Subscribe("harvest.approved", func(payload []byte) { got = append(got, string(payload)) })
+
+	own, _ := encodeFrames("self", "harvest.approved", []byte("local"))
+	remote, _ := encodeFrames("other", "harvest.approved", []byte("remote"))
+
+	a := newAssembler()
+	p.receive(a, own[0])
+	p.receive(a, remote[0])
+	p.receive(a, "not json")
+
+	if len(got) != 1 || got[0] != "remote" {
+		t.Errorf("delivered = %v, want [remote]", got)
+	}
+}
@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory keeps counters in process memory. Limits reset on restart and are
+// enforced per instance.
+type Memory struct {
+	mu      sync.Mutex
+	windows map[string]*memoryWindow
+	now     func() time.Time
+
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+type memoryWindow struct {
+	start    time.Time
+	length   time.Duration
+	count    int64
+	previous int64
+}
+
+var _ Counter = (*Memory)(nil)
+
+// NewMemory creates an in-memory counter and starts its garbage collector.
+func NewMemory(gcInterval time.Duration) *Memory {
+	if gcInterval <= 0 {
+		gcInterval = DefaultGCInterval
+	}
+	m := &Memory{
+		windows: make(map[string]*memoryWindow),
+		now:     time.Now,
+		stop:    make(chan struct{}),
+	}
+	go m.gcLoop(gcInterval)
+	return m
+}
+
+// Hit implements Counter.
+func (m *Memory) Hit(_ context.Context, key string, n int64, window time.Duration) (Window, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w := m.roll(key, window)
+	w.count += n
+	return w.snapshot(), nil
+}
+
+// Peek implements Counter.
+func (m *Memory) Peek(_ context.Context, key string, window time.Duration) (Window, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.roll(key, window).snapshot(), nil
+}
+
+// Reset implements Counter.
+func (m *Memory) Reset(_ context.Context, key string) error {
+	m.mu.Lock()
+	delete(m.windows, key)
+	m.mu.Unlock()
+	return nil
+}
+
+// Close stops the garbage collector.
+func (m *Memory) Close() error {
+	m.closeOnce.Do(func() { close(m.stop) })
+	return nil
+}
+
+// roll returns key's window, advanced to the current one. Must be called
+// with mu held.
+func (m *Memory) roll(key string, window time.Duration) *memoryWindow {
+	start := windowStart(m.now(), window)
+	w, ok := m.windows[key]
+	if !ok || w.length != window {
+		w = &memoryWindow{start: start, length: window}
+		m.windows[key] = w
+		return w
+	}
+
+	switch {
+	case w.start.Equal(start):
+	case w.start.Add(window).Equal(start):
+		w.previous, w.count, w.start = w.count, 0, start
+	default:
+		w.previous, w.count, w.start = 0, 0, start
+	}
+	return w
+}
+
+func (w *memoryWindow) snapshot() Window {
+	return Window{Start: w.start, Length: w.length, Count: w.count, Previous: w.previous}
+}
+
+func (m *Memory) gcLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.gc()
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// gc drops keys whose windows no longer affect an estimate.
+func (m *Memory) gc() {
+	now := m.now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, w := range m.windows {
+		if !now.Before(w.start.Add(2 * w.length)) {
+			delete(m.windows, key)
+		}
+	}
+}
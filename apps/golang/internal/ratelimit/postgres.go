@@ -0,0 +1,143 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// hitSQL increments the current window and reads the previous one in a
+// single statement, so concurrent hits from any instance never lose counts.
+const hitSQL = `
+WITH hit AS (
+	INSERT INTO rate_limit_counters (key, window_start, hits, expires_at)
+	VALUES (@key, @start, @n, @expires)
+	ON CONFLICT (key, window_start)
+	DO UPDATE SET hits = rate_limit_counters.hits + EXCLUDED.hits
+	RETURNING hits
+)
+SELECT hit.hits AS count,
+	COALESCE((
+		SELECT hits FROM rate_limit_counters
+		WHERE key = @key AND window_start = @previous
+	), 0) AS previous
+FROM hit`
+
+// Postgres keeps counters in the UNLOGGED rate_limit_counters table, shared
+// by every instance. Counters are lost if Postgres crashes, which only
+// resets the limits.
+type Postgres struct {
+	db  *gorm.DB
+	now func() time.Time
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+var _ Counter = (*Postgres)(nil)
+
+// NewPostgres creates a counter backed by db and starts its garbage
+// collector.
+func NewPostgres(db *gorm.DB, gcInterval time.Duration) (*Postgres, error) {
+	if db == nil {
+		return nil, errors.New("ratelimit: postgres driver requires a database")
+	}
+	if gcInterval <= 0 {
+		gcInterval = DefaultGCInterval
+	}
+	p := &Postgres{
+		db:   db,
+		now:  time.Now,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go p.gcLoop(gcInterval)
+	return p, nil
+}
+
+// Hit implements Counter.
+func (p *Postgres) Hit(ctx context.Context, key string, n int64, window time.Duration) (Window, error) {
+	start := windowStart(p.now(), window)
+	var row struct {
+		Count    int64
+		Previous int64
+	}
+	err := p.db.WithContext(ctx).Raw(hitSQL, map[string]interface{}{
+		"key":      key,
+		"start":    start,
+		"n":        n,
+		"expires":  start.Add(2 * window),
+		"previous": start.Add(-window),
+	}).Scan(&row).Error
+	if err != nil {
+		return Window{}, fmt.Errorf("ratelimit: hit %s: %w", key, err)
+	}
+	return Window{Start: start, Length: window, Count: row.Count, Previous: row.Previous}, nil
+}
+
+// Peek implements Counter.
+func (p *Postgres) Peek(ctx context.Context, key string, window time.Duration) (Window, error) {
+	start := windowStart(p.now(), window)
+	var rows []struct {
+		WindowStart time.Time
+		Hits        int64
+	}
+	err := p.db.WithContext(ctx).
+		Raw(`SELECT window_start, hits FROM rate_limit_counters WHERE key = ? AND window_start IN (?, ?)`,
+			key, start, start.Add(-window)).
+		Scan(&rows).Error
+	if err != nil {
+		return Window{}, fmt.Errorf("ratelimit: peek %s: %w", key, err)
+	}
+
+	w := Window{Start: start, Length: window}
+	for _, row := range rows {
+		if row.WindowStart.Equal(start) {
+			w.Count = row.Hits
+		} else {
+			w.Previous = row.Hits
+		}
+	}
+	return w, nil
+}
+
+// Reset implements Counter.
+func (p *Postgres) Reset(ctx context.Context, key string) error {
+	if err := p.db.WithContext(ctx).Exec(`DELETE FROM rate_limit_counters WHERE key = ?`, key).Error; err != nil {
+		return fmt.Errorf("ratelimit: reset %s: %w", key, err)
+	}
+	return nil
+}
+
+// Close stops the garbage collector.
+func (p *Postgres) Close() error {
+	p.closeOnce.Do(func() {
+		close(p.stop)
+		<-p.done
+	})
+	return nil
+}
+
+func (p *Postgres) gcLoop(interval time.Duration) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.db.Exec(`DELETE FROM rate_limit_counters WHERE expires_at < NOW()`).Error; err != nil {
+				log.Printf("ratelimit: failed to remove expired counters: %v", err)
+			}
+		case <-p.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Package ratelimit counts hits per key in fixed time windows so limits can
+// be shared by every server instance.
+//
+// Two drivers are available: Memory, for a single instance and tests, and
+// Postgres, which keeps counters in an UNLOGGED table and increments them
+// atomically. Callers combine the current and previous window into a
+// sliding-window estimate with Window.Estimate.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Drivers accepted by New.
+const (
+	DriverMemory   = "memory"
+	DriverPostgres = "postgres"
+)
+
+// DefaultGCInterval is how often expired counters are removed.
+const DefaultGCInterval = time.Minute
+
+// Window is the state of a key after a Hit or Peek.
+type Window struct {
+	// Start is the beginning of the current window.
+	Start time.Time
+	// Length is the window duration.
+	Length time.Duration
+	// Count is the number of hits in the current window, including the
+	// hit that produced this Window.
+	Count int64
+	// Previous is the number of hits in the window before Start.
+	Previous int64
+}
+
+// End returns when the current window closes.
+func (w Window) End() time.Time {
+	return w.Start.Add(w.Length)
+}
+
+// Estimate approximates the hits in the sliding window ending at now by
+// weighting the previous window by how much of it still overlaps.
+func (w Window) Estimate(now time.Time) float64 {
+	if w.Length <= 0 {
+		return float64(w.Count)
+	}
+	overlap := 1 - float64(now.Sub(w.Start))/float64(w.Length)
+	overlap = max(0, min(1, overlap))
+	return float64(w.Previous)*overlap + float64(w.Count)
+}
+
+// Counter stores hit counts. Implementations must be safe for concurrent
+// use, and Hit must be atomic across every instance sharing the counter.
+type Counter interface {
+	// Hit adds n hits to key's current window and returns its state.
+	Hit(ctx context.Context, key string, n int64, window time.Duration) (Window, error)
+	// Peek returns key's state without adding a hit.
+	Peek(ctx context.Context, key string, window time.Duration) (Window, error)
+	// Reset forgets every window of key.
+	Reset(ctx context.Context, key string) error
+	// Close stops garbage collection.
+	Close() error
+}
+
+// Allow records one hit for key and reports whether the sliding-window
+// estimate, including this hit, stays within limit. Rejected hits are
+// counted too, so a client that keeps retrying stays blocked.
+func Allow(ctx context.Context, c Counter, key string, limit int, window time.Duration) (bool, Window, error) {
+	w, err := c.Hit(ctx, key, 1, window)
+	if err != nil {
+		return false, Window{}, err
+	}
+	return w.Estimate(time.Now()) <= float64(limit), w, nil
+}
+
+// New creates the Counter selected by driver. db is only used by the
+// postgres driver; an empty driver selects memory. A non-positive
+// gcInterval uses DefaultGCInterval.
+func New(driver string, db *gorm.DB, gcInterval time.Duration) (Counter, error) {
+	switch strings.ToLower(strings.TrimSpace(driver)) {
+	case "", DriverMemory:
+		return NewMemory(gcInterval), nil
+	case DriverPostgres:
+		return NewPostgres(db, gcInterval)
+	default:
+		return nil, fmt.Errorf("ratelimit: unknown driver %q", driver)
+	}
+}
+
+// windowStart aligns t to the window grid shared by all instances.
+func windowStart(t time.Time, window time.Duration) time.Time {
+	return t.Truncate(window).UTC()
+}
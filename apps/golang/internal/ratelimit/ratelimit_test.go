@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWindow_Estimate(t *testing.T) {
+	start := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	w := Window{Start: start, Length: time.Minute, Count: 2, Previous: 10}
+
+	cases := []struct {
+		now  time.Time
+		want float64
+	}{
+		{start, 12},
+		{start.Add(15 * time.Second), 9.5},
+		{start.Add(45 * time.Second), 4.5},
+		{start.Add(2 * time.Minute), 2},
+	}
+	for _, tc := range cases {
+		if got := w.Estimate(tc.now); got != tc.want {
+			t.Errorf("Estimate(%s) = %v, want %v", tc.now.Sub(start), got, tc.want)
+		}
+	}
+}
+
+func TestMemory_HitRollsWindows(t *testing.T) {
+	m := NewMemory(time.Hour)
+	defer m.Close()
+
+	now := time.Date(2026, 1, 1, 8, 0, 10, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := m.Hit(ctx, "ip:1", 1, time.Minute); err != nil {
+			t.Fatalf("Hit: %v", err)
+		}
+	}
+	if w, _ := m.Hit(ctx, "ip:2", 5, time.Minute); w.Count != 5 {
+		t.Errorf("other key count = %d, want 5", w.Count)
+	}
+
+	now = now.Add(time.Minute)
+	w, _ := m.Hit(ctx, "ip:1", 1, time.Minute)
+	if w.Count != 1 || w.Previous != 3 {
+		t.Errorf("next window = %+v, want count 1 previous 3", w)
+	}
+
+	// A gap of more than one window forgets the old counts.
+	now = now.Add(5 * time.Minute)
+	if w, _ := m.Peek(ctx, "ip:1", time.Minute); w.Count != 0 || w.Previous != 0 {
+		t.Errorf("after gap = %+v, want empty", w)
+	}
+}
+
+func TestMemory_ResetAndGC(t *testing.T) {
+	m := NewMemory(time.Hour)
+	defer m.Close()
+
+	now := time.Date(2026, 1, 1, 8, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	m.Hit(ctx, "a", 1, time.Minute)
+	m.Hit(ctx, "b", 1, time.Minute)
+	m.Reset(ctx, "a")
+	if w, _ := m.Peek(ctx, "a", time.Minute); w.Count != 0 {
+		t.Errorf("count after Reset = %d", w.Count)
+	}
+
+	now = now.Add(2 * time.Minute)
+	m.gc()
+	if len(m.windows) != 0 {
+		t.Errorf("windows after gc = %d, want 0", len(m.windows))
+	}
+}
+
+func TestAllow(t *testing.T) {
+	m := NewMemory(time.Hour)
+	defer m.Close()
+
+	allowed := 0
+	for i := 0; i < 8; i++ {
+		ok, _, err := Allow(context.Background(), m, "forgot:1", 5, time.Hour)
+		if err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+		if ok {
+			allowed++
+		}
+	}
+	if allowed != 5 {
+		t.Errorf("allowed = %d, want 5", allowed)
+	}
+}
+
+func TestNew_Drivers(t *testing.T) {
+	c, err := New("", nil, 0)
+	if err != nil {
+		t.Fatalf("New(\"\"): %v", err)
+	}
+	c.Close()
+	if _, err := New(DriverPostgres, nil, 0); err == nil {
+		t.Error("postgres driver without a database should fail")
+	}
+	if _, err := New("redis", nil, 0); err == nil {
+		t.Error("unknown driver should fail")
+	}
+}
@@ -0,0 +1,246 @@
+package routes
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	companyServices "agrinovagraphql/server/internal/company/services"
+	appmiddleware "agrinovagraphql/server/internal/middleware"
+	"agrinovagraphql/server/internal/photoupload"
+	log "agrinovagraphql/server/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The upload endpoints follow the tus 1.0.0 core protocol with the
+// creation, termination and expiration extensions, plus a finalize call
+// that links the photo to its record.
+const (
+	tusResumableVersion = "1.0.0"
+	tusOffsetMediaType  = "application/offset+octet-stream"
+)
+
+type photoUploadHandler struct {
+	service *photoupload.Service
+}
+
+func SetupPhotoUploadRoutes(r *gin.RouterGroup, service *photoupload.Service) {
+	handler := &photoUploadHandler{service: service}
+
+	r.Use(tusResumableMiddleware())
+	r.POST("", handler.create)
+	r.HEAD("/:id", handler.head)
+	r.PATCH("/:id", handler.patch)
+	r.DELETE("/:id", handler.terminate)
+	r.POST("/:id/finalize", handler.finalize)
+}
+
+func tusResumableMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Tus-Resumable", tusResumableVersion)
+		if version := c.GetHeader("Tus-Resumable"); version != "" && version != tusResumableVersion {
+			c.Header("Tus-Version", tusResumableVersion)
+			c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{"message": "unsupported Tus-Resumable version"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// create opens an upload. Upload-Metadata must carry the photo's sha256
+// (hex); filename and deviceId are optional.
+func (h *photoUploadHandler) create(c *gin.Context) {
+	actor, ok := requirePhotoUploadActor(c)
+	if !ok {
+		return
+	}
+
+	length, err := strconv.ParseInt(c.GetHeader("Upload-Length"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Upload-Length header is required"})
+		return
+	}
+	metadata, err := parseUploadMetadata(c.GetHeader("Upload-Metadata"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	session, err := h.service.Create(c.Request.Context(), actor, photoupload.CreateInput{
+		Length:   length,
+		SHA256:   metadata["sha256"],
+		FileName: metadata["filename"],
+		DeviceID: metadata["deviceId"],
+	})
+	if err != nil {
+		writePhotoUploadError(c, err)
+		return
+	}
+
+	c.Header("Location", strings.TrimSuffix(c.Request.URL.Path, "/")+"/"+session.ID)
+	writeUploadHeaders(c, session)
+	c.JSON(http.StatusCreated, session)
+}
+
+func (h *photoUploadHandler) head(c *gin.Context) {
+	actor, ok := requirePhotoUploadActor(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.service.Get(c.Request.Context(), actor, c.Param("id"))
+	if err != nil {
+		c.Status(photoUploadErrorStatus(err))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	writeUploadHeaders(c, session)
+	c.Status(http.StatusOK)
+}
+
+func (h *photoUploadHandler) patch(c *gin.Context) {
+	actor, ok := requirePhotoUploadActor(c)
+	if !ok {
+		return
+	}
+
+	if c.ContentType() != tusOffsetMediaType {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"message": "Content-Type must be " + tusOffsetMediaType})
+		return
+	}
+	offset, err := strconv.ParseInt(c.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Upload-Offset header is required"})
+		return
+	}
+
+	session, err := h.service.Append(c.Request.Context(), actor, c.Param("id"), offset, c.Request.Body)
+	if session != nil {
+		writeUploadHeaders(c, session)
+	}
+	if err != nil {
+		writePhotoUploadError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *photoUploadHandler) terminate(c *gin.Context) {
+	actor, ok := requirePhotoUploadActor(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Terminate(c.Request.Context(), actor, c.Param("id")); err != nil {
+		writePhotoUploadError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *photoUploadHandler) finalize(c *gin.Context) {
+	actor, ok := requirePhotoUploadActor(c)
+	if !ok {
+		return
+	}
+
+	var input photoupload.FinalizeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid request payload"})
+		return
+	}
+
+	result, err := h.service.Finalize(c.Request.Context(), actor, c.Param("id"), input)
+	if err != nil {
+		writePhotoUploadError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func requirePhotoUploadActor(c *gin.Context) (photoupload.Actor, bool) {
+	ctx := c.Request.Context()
+	userID := strings.TrimSpace(appmiddleware.GetCurrentUserID(ctx))
+	if userID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "authentication required"})
+		return photoupload.Actor{}, false
+	}
+	companyID := strings.TrimSpace(appmiddleware.GetCompanyFromContext(ctx))
+	if companyID == "" {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"message": "company assignment required"})
+		return photoupload.Actor{}, false
+	}
+	return photoupload.Actor{
+		UserID:    userID,
+		CompanyID: companyID,
+		Role:      strings.ToUpper(strings.TrimSpace(string(appmiddleware.GetUserRoleFromContext(ctx)))),
+	}, true
+}
+
+func writeUploadHeaders(c *gin.Context, session *photoupload.Session) {
+	c.Header("Upload-Offset", strconv.FormatInt(session.UploadOffset, 10))
+	c.Header("Upload-Length", strconv.FormatInt(session.UploadLength, 10))
+	if session.Status != photoupload.StatusFinalized {
+		c.Header("Upload-Expires", session.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+func writePhotoUploadError(c *gin.Context, err error) {
+	status := photoUploadErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		log.Error("Photo upload failed: %v", err)
+		c.JSON(status, gin.H{"message": "photo upload failed"})
+		return
+	}
+	c.JSON(status, gin.H{"message": err.Error()})
+}
+
+func photoUploadErrorStatus(err error) int {
+	var quotaErr *companyServices.QuotaExceededError
+	switch {
+	case errors.Is(err, photoupload.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, photoupload.ErrForbidden),
+		errors.Is(err, photoupload.ErrTargetForbidden),
+		errors.Is(err, companyServices.ErrCompanySuspended),
+		errors.Is(err, companyServices.ErrTrialExpired):
+		return http.StatusForbidden
+	case errors.Is(err, photoupload.ErrNotFound), errors.Is(err, photoupload.ErrTargetNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, photoupload.ErrExpired):
+		return http.StatusGone
+	case errors.Is(err, photoupload.ErrOffsetMismatch), errors.Is(err, photoupload.ErrIncomplete), errors.Is(err, photoupload.ErrAlreadyFinalized),
+		errors.Is(err, photoupload.ErrTargetLocked):
+		return http.StatusConflict
+	case errors.Is(err, photoupload.ErrTooLarge), errors.Is(err, photoupload.ErrLengthExceeded), errors.As(err, &quotaErr):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, photoupload.ErrInvalidImage):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, photoupload.ErrHashMismatch):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+// parseUploadMetadata decodes the tus Upload-Metadata header: comma
+// separated pairs of a key and an optional base64 value.
+func parseUploadMetadata(header string) (map[string]string, error) {
+	metadata := map[string]string{}
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, encoded, _ := strings.Cut(pair, " ")
+		value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, errors.New("Upload-Metadata values must be base64 encoded")
+		}
+		metadata[key] = string(value)
+	}
+	return metadata, nil
+}
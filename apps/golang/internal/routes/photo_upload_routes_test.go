@@ -0,0 +1,83 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	companyServices "agrinovagraphql/server/internal/company/services"
+	"agrinovagraphql/server/internal/photoupload"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseUploadMetadata(t *testing.T) {
+	metadata, err := parseUploadMetadata("sha256 YWJj, filename Zm90by5qcGc=,empty")
+	if err != nil {
+		t.Fatalf("parseUploadMetadata: %v", err)
+	}
+	if metadata["sha256"] != "abc" || metadata["filename"] != "foto.jpg" {
+		t.Fatalf("unexpected metadata %v", metadata)
+	}
+	if value, ok := metadata["empty"]; !ok || value != "" {
+		t.Fatalf("key without value should decode to an empty string, got %q, %v", value, ok)
+	}
+
+	if _, err := parseUploadMetadata("sha256 not-base64!"); err == nil {
+		t.Fatal("expected an error for a value that is not base64")
+	}
+}
+
+func TestPhotoUploadErrorStatus(t *testing.T) {
+	tests := map[error]int{
+		photoupload.ErrOffsetMismatch:                             http.StatusConflict,
+		photoupload.ErrExpired:                                    http.StatusGone,
+		photoupload.ErrTooLarge:                                   http.StatusRequestEntityTooLarge,
+		photoupload.ErrHashMismatch:                               http.StatusUnprocessableEntity,
+		&companyServices.QuotaExceededError{Resource: "Storage"}:  http.StatusRequestEntityTooLarge,
+		companyServices.ErrCompanySuspended:                       http.StatusForbidden,
+		photoupload.ErrTargetForbidden:                            http.StatusForbidden,
+		fmt.Errorf("link photo: %w", photoupload.ErrTargetLocked): http.StatusConflict,
+		fmt.Errorf("%w: bad", photoupload.ErrInvalidInput):        http.StatusBadRequest,
+		fmt.Errorf("database down"):                               http.StatusInternalServerError,
+	}
+	for err, want := range tests {
+		if got := photoUploadErrorStatus(err); got != want {
+			t.Errorf("photoUploadErrorStatus(%v) = %d, want %d", err, got, want)
+		}
+	}
+}
+
+func TestPhotoUploadRoutes_RequireIdentityAndVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID := c.GetHeader("X-Test-User"); userID != "" {
+			c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), "user_id", userID))
+		}
+		c.Next()
+	})
+	SetupPhotoUploadRoutes(router.Group("/api/uploads/photos"), photoupload.NewService(nil, nil))
+
+	request := func(headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/uploads/photos", nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	if got := request(nil); got.Code != http.StatusUnauthorized || got.Header().Get("Tus-Resumable") != tusResumableVersion {
+		t.Fatalf("anonymous create: status %d, Tus-Resumable %q", got.Code, got.Header().Get("Tus-Resumable"))
+	}
+	if got := request(map[string]string{"X-Test-User": "user-1"}); got.Code != http.StatusForbidden {
+		t.Fatalf("create without company: status %d", got.Code)
+	}
+	if got := request(map[string]string{"Tus-Resumable": "0.2.2"}); got.Code != http.StatusPreconditionFailed {
+		t.Fatalf("old protocol version: status %d", got.Code)
+	}
+}
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"image"
 	"image/gif"
@@ -10,8 +11,6 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
-	"os"
-	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
@@ -22,10 +21,10 @@ import (
 	appmiddleware "agrinovagraphql/server/internal/middleware"
 	"agrinovagraphql/server/internal/theme"
 	log "agrinovagraphql/server/pkg/logger"
+	"agrinovagraphql/server/pkg/storage"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 )
 
 const (
@@ -76,18 +75,19 @@ var svgInterTagWhitespacePattern = regexp.MustCompile(`>\s+<`)
 var svgRepeatedWhitespacePattern = regexp.MustCompile(`\s{2,}`)
 
 type themeCampaignHandler struct {
-	service    *theme.Service
-	uploadsDir string
+	service *theme.Service
+	uploads storage.Store
 }
 
-func SetupThemeCampaignRoutes(r *gin.RouterGroup, db *gorm.DB, uploadsDir string) {
+func SetupThemeCampaignRoutes(r *gin.RouterGroup, service *theme.Service, uploads storage.Store) {
 	handler := &themeCampaignHandler{
-		service:    theme.NewService(db),
-		uploadsDir: normalizeUploadsRoot(uploadsDir),
+		service: service,
+		uploads: uploads,
 	}
 
 	r.POST("/assets/upload", handler.uploadAsset)
 	r.GET("/dashboard", handler.getDashboard)
+	r.GET("/preview", handler.previewRuntimeTheme)
 	r.GET("/themes", handler.getThemes)
 	r.POST("/themes", handler.createTheme)
 	r.PUT("/themes/:id", handler.updateTheme)
@@ -98,20 +98,22 @@ func SetupThemeCampaignRoutes(r *gin.RouterGroup, db *gorm.DB, uploadsDir string
 	r.POST("/campaigns/:id/toggle-enabled", handler.toggleCampaignEnabled)
 	r.POST("/campaigns/:id/duplicate", handler.duplicateCampaign)
 	r.POST("/settings/default-theme", handler.setDefaultTheme)
+	r.GET("/settings/company", handler.getCompanySettings)
+	r.POST("/settings/company-default-theme", handler.setCompanyDefaultTheme)
 	r.POST("/settings/kill-switch", handler.setKillSwitch)
 }
 
-func SetupPublicThemeCampaignRoutes(r *gin.RouterGroup, db *gorm.DB) {
+func SetupPublicThemeCampaignRoutes(r *gin.RouterGroup, service *theme.Service) {
 	handler := &themeCampaignHandler{
-		service:    theme.NewService(db),
-		uploadsDir: "",
+		service: service,
 	}
 
 	r.GET("/theme-runtime", handler.resolveRuntimeTheme)
 }
 
 func (h *themeCampaignHandler) uploadAsset(c *gin.Context) {
-	if _, ok := requireSuperAdmin(c); !ok {
+	_, scope, ok := requireThemeManager(c)
+	if !ok {
 		return
 	}
 
@@ -186,28 +188,20 @@ func (h *themeCampaignHandler) uploadAsset(c *gin.Context) {
 		}
 	}
 
-	dirSegments := []string{h.uploadsDir, themeAssetsDirName, platform, assetKey}
-	urlSegments := []string{themeAssetsDirName, platform, assetKey}
-	if assetKey == "appUiAsset" && slotKey != "" {
-		dirSegments = append(dirSegments, slotKey)
-		urlSegments = append(urlSegments, slotKey)
-	}
-
-	absoluteDir := filepath.Join(dirSegments...)
-	if err := os.MkdirAll(absoluteDir, 0755); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("failed to create upload directory: %v", err)})
-		return
-	}
-
-	storedFileName := uuid.NewString() + ext
-	absolutePath := filepath.Join(absoluteDir, storedFileName)
-	if err := os.WriteFile(absolutePath, content, 0644); err != nil {
+	ctx := c.Request.Context()
+	assetDir := themeAssetDirectory(scope, platform, assetKey, slotKey)
+	storedKey := storage.JoinKey(assetDir, uuid.NewString()+ext)
+	if err := storage.PutBytes(ctx, h.uploads, storedKey, content, contentType); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("failed to save file: %v", err)})
 		return
 	}
-	_ = pruneThemeAssetDirectory(absoluteDir, storedFileName)
+	if referenced, err := h.service.ReferencedUploadPaths(ctx); err != nil {
+		log.Warn("Skipping theme asset pruning for %s: %v", assetDir, err)
+	} else {
+		_ = pruneThemeAssetDirectory(ctx, h.uploads, assetDir, storedKey, referenced)
+	}
 
-	filePath := uploadURLPath(append(urlSegments, storedFileName)...)
+	filePath := storage.URLPath(storedKey)
 	c.JSON(http.StatusOK, gin.H{
 		"success":      true,
 		"path":         filePath,
@@ -553,32 +547,6 @@ func normalizeThemeAssetPlatform(raw string) string {
 	}
 }
 
-func normalizeUploadsRoot(dir string) string {
-	trimmed := strings.TrimSpace(dir)
-	if trimmed == "" {
-		return filepath.Clean("./uploads")
-	}
-
-	cleaned := filepath.Clean(trimmed)
-	if cleaned == "." {
-		return filepath.Clean("./uploads")
-	}
-	return cleaned
-}
-
-func uploadURLPath(parts ...string) string {
-	cleaned := make([]string, 0, len(parts)+1)
-	cleaned = append(cleaned, "uploads")
-	for _, part := range parts {
-		normalized := strings.Trim(strings.ReplaceAll(part, "\\", "/"), "/")
-		if normalized == "" {
-			continue
-		}
-		cleaned = append(cleaned, normalized)
-	}
-	return "/" + path.Join(cleaned...)
-}
-
 func normalizeMimeType(contentType string) string {
 	normalized := strings.ToLower(strings.TrimSpace(contentType))
 	if idx := strings.Index(normalized, ";"); idx >= 0 {
@@ -587,44 +555,45 @@ func normalizeMimeType(contentType string) string {
 	return normalized
 }
 
-type retainedFile struct {
-	name    string
-	path    string
-	modTime time.Time
-}
-
-func pruneThemeAssetDirectory(directory string, keepFileName string) error {
-	entries, err := os.ReadDir(directory)
-	if err != nil {
-		return err
+// themeAssetDirectory returns the storage directory of an upload. Company
+// admins upload under companies/<companyID> so their pruning never reaches
+// another company's or the global assets.
+func themeAssetDirectory(scope theme.ManageScope, platform, assetKey, slotKey string) string {
+	keySegments := []string{themeAssetsDirName}
+	if !scope.IsGlobal() {
+		keySegments = append(keySegments, "companies", strings.TrimSpace(scope.CompanyID))
+	}
+	keySegments = append(keySegments, platform, assetKey)
+	if assetKey == "appUiAsset" && slotKey != "" {
+		keySegments = append(keySegments, slotKey)
 	}
+	return storage.JoinKey(keySegments...)
+}
 
+// pruneThemeAssetDirectory drops assets older than the retention age and
+// keeps at most themeAssetRetentionMaxFilesPerPath directly under directory.
+// Assets whose path is in referenced are still used by a theme or campaign
+// and are never deleted.
+func pruneThemeAssetDirectory(ctx context.Context, uploads storage.Store, directory string, keepKey string, referenced map[string]struct{}) error {
+	prefix := directory + "/"
 	now := time.Now()
-	candidates := make([]retainedFile, 0, len(entries))
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		name := strings.TrimSpace(entry.Name())
-		if name == "" || name == keepFileName {
-			continue
+	candidates := []storage.ObjectInfo{}
+	err := uploads.List(ctx, prefix, func(info storage.ObjectInfo) error {
+		if info.Key == keepKey || strings.Contains(strings.TrimPrefix(info.Key, prefix), "/") {
+			return nil
 		}
-		info, err := entry.Info()
-		if err != nil || !info.Mode().IsRegular() {
-			continue
+		if _, ok := referenced[storage.URLPath(info.Key)]; ok {
+			return nil
 		}
-
-		absolutePath := filepath.Join(directory, name)
-		if now.Sub(info.ModTime()) > themeAssetRetentionMaxAge {
-			_ = os.Remove(absolutePath)
-			continue
+		if now.Sub(info.ModTime) > themeAssetRetentionMaxAge {
+			_ = uploads.Delete(ctx, info.Key)
+			return nil
 		}
-
-		candidates = append(candidates, retainedFile{
-			name:    name,
-			path:    absolutePath,
-			modTime: info.ModTime(),
-		})
+		candidates = append(candidates, info)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	if len(candidates) <= themeAssetRetentionMaxFilesPerPath {
@@ -632,21 +601,26 @@ func pruneThemeAssetDirectory(directory string, keepFileName string) error {
 	}
 
 	sort.Slice(candidates, func(i, j int) bool {
-		return candidates[i].modTime.Before(candidates[j].modTime)
+		return candidates[i].ModTime.Before(candidates[j].ModTime)
 	})
 
 	excess := len(candidates) - themeAssetRetentionMaxFilesPerPath
 	for i := 0; i < excess; i++ {
-		_ = os.Remove(candidates[i].path)
+		_ = uploads.Delete(ctx, candidates[i].Key)
 	}
 
 	return nil
 }
 
 func (h *themeCampaignHandler) getDashboard(c *gin.Context) {
-	if _, ok := requireSuperAdmin(c); !ok {
+	_, scope, ok := requireThemeManager(c)
+	if !ok {
 		return
 	}
+	if scope.IsGlobal() {
+		// Super admins may look at one company's branding.
+		scope.CompanyID = strings.TrimSpace(c.Query("companyId"))
+	}
 
 	page := parseInt(c.Query("page"), 1)
 	pageSize := parseInt(c.Query("pageSize"), 5)
@@ -658,6 +632,7 @@ func (h *themeCampaignHandler) getDashboard(c *gin.Context) {
 		SortDirection: c.Query("sortDirection"),
 		Page:          page,
 		PageSize:      pageSize,
+		Scope:         scope,
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -669,12 +644,46 @@ func (h *themeCampaignHandler) getDashboard(c *gin.Context) {
 	c.JSON(http.StatusOK, payload)
 }
 
+// previewRuntimeTheme shows what a device would receive at a given time,
+// why the winning campaign won and the upcoming campaign transitions.
+func (h *themeCampaignHandler) previewRuntimeTheme(c *gin.Context) {
+	_, scope, ok := requireThemeManager(c)
+	if !ok {
+		return
+	}
+
+	input := theme.RuntimePreviewInput{
+		Platform:    c.Query("platform"),
+		Mode:        c.Query("mode"),
+		CompanyID:   c.Query("companyId"),
+		Role:        c.Query("role"),
+		HorizonDays: parseInt(c.Query("horizonDays"), 0),
+	}
+	if raw := strings.TrimSpace(c.Query("at")); raw != "" {
+		at, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "at must be an RFC3339 timestamp"})
+			return
+		}
+		input.At = &at
+	}
+
+	result, err := h.service.PreviewRuntimeTheme(c.Request.Context(), scope, input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 func (h *themeCampaignHandler) getThemes(c *gin.Context) {
-	if _, ok := requireSuperAdmin(c); !ok {
+	_, scope, ok := requireThemeManager(c)
+	if !ok {
 		return
 	}
 
-	result, err := h.service.GetThemes(c.Request.Context())
+	result, err := h.service.GetThemes(c.Request.Context(), scope)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
@@ -684,7 +693,7 @@ func (h *themeCampaignHandler) getThemes(c *gin.Context) {
 }
 
 func (h *themeCampaignHandler) createTheme(c *gin.Context) {
-	actor, ok := requireSuperAdmin(c)
+	actor, scope, ok := requireThemeManager(c)
 	if !ok {
 		return
 	}
@@ -695,7 +704,7 @@ func (h *themeCampaignHandler) createTheme(c *gin.Context) {
 		return
 	}
 
-	result, err := h.service.CreateTheme(c.Request.Context(), actor, input)
+	result, err := h.service.CreateTheme(c.Request.Context(), actor, scope, input)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
@@ -705,7 +714,7 @@ func (h *themeCampaignHandler) createTheme(c *gin.Context) {
 }
 
 func (h *themeCampaignHandler) updateTheme(c *gin.Context) {
-	actor, ok := requireSuperAdmin(c)
+	actor, scope, ok := requireThemeManager(c)
 	if !ok {
 		return
 	}
@@ -716,7 +725,7 @@ func (h *themeCampaignHandler) updateTheme(c *gin.Context) {
 		return
 	}
 
-	result, err := h.service.UpdateTheme(c.Request.Context(), actor, strings.TrimSpace(c.Param("id")), input)
+	result, err := h.service.UpdateTheme(c.Request.Context(), actor, scope, strings.TrimSpace(c.Param("id")), input)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
@@ -726,12 +735,12 @@ func (h *themeCampaignHandler) updateTheme(c *gin.Context) {
 }
 
 func (h *themeCampaignHandler) toggleThemeActive(c *gin.Context) {
-	actor, ok := requireSuperAdmin(c)
+	actor, scope, ok := requireThemeManager(c)
 	if !ok {
 		return
 	}
 
-	result, err := h.service.ToggleThemeActive(c.Request.Context(), actor, strings.TrimSpace(c.Param("id")))
+	result, err := h.service.ToggleThemeActive(c.Request.Context(), actor, scope, strings.TrimSpace(c.Param("id")))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
@@ -741,7 +750,7 @@ func (h *themeCampaignHandler) toggleThemeActive(c *gin.Context) {
 }
 
 func (h *themeCampaignHandler) createCampaign(c *gin.Context) {
-	actor, ok := requireSuperAdmin(c)
+	actor, scope, ok := requireThemeManager(c)
 	if !ok {
 		return
 	}
@@ -752,7 +761,7 @@ func (h *themeCampaignHandler) createCampaign(c *gin.Context) {
 		return
 	}
 
-	result, err := h.service.CreateCampaign(c.Request.Context(), actor, input)
+	result, err := h.service.CreateCampaign(c.Request.Context(), actor, scope, input)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
@@ -762,7 +771,7 @@ func (h *themeCampaignHandler) createCampaign(c *gin.Context) {
 }
 
 func (h *themeCampaignHandler) updateCampaign(c *gin.Context) {
-	actor, ok := requireSuperAdmin(c)
+	actor, scope, ok := requireThemeManager(c)
 	if !ok {
 		return
 	}
@@ -773,7 +782,7 @@ func (h *themeCampaignHandler) updateCampaign(c *gin.Context) {
 		return
 	}
 
-	result, err := h.service.UpdateCampaign(c.Request.Context(), actor, strings.TrimSpace(c.Param("id")), input)
+	result, err := h.service.UpdateCampaign(c.Request.Context(), actor, scope, strings.TrimSpace(c.Param("id")), input)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
@@ -783,12 +792,12 @@ func (h *themeCampaignHandler) updateCampaign(c *gin.Context) {
 }
 
 func (h *themeCampaignHandler) deleteCampaign(c *gin.Context) {
-	actor, ok := requireSuperAdmin(c)
+	actor, scope, ok := requireThemeManager(c)
 	if !ok {
 		return
 	}
 
-	if err := h.service.DeleteCampaign(c.Request.Context(), actor, strings.TrimSpace(c.Param("id"))); err != nil {
+	if err := h.service.DeleteCampaign(c.Request.Context(), actor, scope, strings.TrimSpace(c.Param("id"))); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
 	}
@@ -797,12 +806,12 @@ func (h *themeCampaignHandler) deleteCampaign(c *gin.Context) {
 }
 
 func (h *themeCampaignHandler) toggleCampaignEnabled(c *gin.Context) {
-	actor, ok := requireSuperAdmin(c)
+	actor, scope, ok := requireThemeManager(c)
 	if !ok {
 		return
 	}
 
-	result, err := h.service.ToggleCampaignEnabled(c.Request.Context(), actor, strings.TrimSpace(c.Param("id")))
+	result, err := h.service.ToggleCampaignEnabled(c.Request.Context(), actor, scope, strings.TrimSpace(c.Param("id")))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
@@ -812,12 +821,12 @@ func (h *themeCampaignHandler) toggleCampaignEnabled(c *gin.Context) {
 }
 
 func (h *themeCampaignHandler) duplicateCampaign(c *gin.Context) {
-	actor, ok := requireSuperAdmin(c)
+	actor, scope, ok := requireThemeManager(c)
 	if !ok {
 		return
 	}
 
-	result, err := h.service.DuplicateCampaign(c.Request.Context(), actor, strings.TrimSpace(c.Param("id")))
+	result, err := h.service.DuplicateCampaign(c.Request.Context(), actor, scope, strings.TrimSpace(c.Param("id")))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
 		return
@@ -872,25 +881,84 @@ func (h *themeCampaignHandler) setDefaultTheme(c *gin.Context) {
 	c.JSON(http.StatusOK, settings)
 }
 
+func (h *themeCampaignHandler) getCompanySettings(c *gin.Context) {
+	_, scope, ok := requireThemeManager(c)
+	if !ok {
+		return
+	}
+
+	companyID := scope.CompanyID
+	if scope.IsGlobal() {
+		companyID = strings.TrimSpace(c.Query("companyId"))
+	}
+
+	settings, err := h.service.GetCompanySettings(c.Request.Context(), companyID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *themeCampaignHandler) setCompanyDefaultTheme(c *gin.Context) {
+	actor, scope, ok := requireThemeManager(c)
+	if !ok {
+		return
+	}
+
+	var payload struct {
+		CompanyID string `json:"company_id"`
+		ThemeID   string `json:"theme_id"`
+	}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "invalid request payload"})
+		return
+	}
+
+	settings, err := h.service.SetCompanyDefaultTheme(c.Request.Context(), actor, scope, payload.CompanyID, payload.ThemeID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
 func (h *themeCampaignHandler) resolveRuntimeTheme(c *gin.Context) {
 	platform := strings.TrimSpace(c.Query("platform"))
 	mode := strings.TrimSpace(c.Query("mode"))
-
-	result, err := h.service.ResolveRuntimeTheme(c.Request.Context(), theme.RuntimeThemeContext{
-		Platform: platform,
-		Mode:     mode,
+	companyID := strings.TrimSpace(c.Query("companyId"))
+	role := strings.TrimSpace(c.Query("role"))
+
+	result, etag, err := h.service.ResolveRuntimeThemeCached(c.Request.Context(), theme.RuntimeThemeContext{
+		Platform:  platform,
+		Mode:      mode,
+		CompanyID: companyID,
+		Role:      role,
 	})
 	if err != nil {
 		log.Warn(
-			"Theme runtime resolve failed platform=%s mode=%s error=%v",
+			"Theme runtime resolve failed platform=%s mode=%s company_id=%s role=%s error=%v",
 			platform,
 			mode,
+			companyID,
+			role,
 			err,
 		)
 		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
 		return
 	}
 
+	// Clients revalidate on every launch; no-cache keeps intermediaries from
+	// serving a payload without checking the ETag.
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache")
+	if theme.ETagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
 	campaignID := ""
 	campaignName := ""
 	if result.Campaign != nil {
@@ -926,6 +994,32 @@ func (h *themeCampaignHandler) resolveRuntimeTheme(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// requireThemeManager allows super admins with the global scope and company
+// admins scoped to the company in their session.
+func requireThemeManager(c *gin.Context) (string, theme.ManageScope, bool) {
+	userID := strings.TrimSpace(appmiddleware.GetCurrentUserID(c.Request.Context()))
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "authentication required"})
+		return "", theme.ManageScope{}, false
+	}
+
+	role := strings.ToUpper(strings.TrimSpace(string(appmiddleware.GetUserRoleFromContext(c.Request.Context()))))
+	switch role {
+	case "SUPER_ADMIN":
+		return userID, theme.ManageScope{}, true
+	case "COMPANY_ADMIN":
+		companyID := strings.TrimSpace(appmiddleware.GetCompanyFromContext(c.Request.Context()))
+		if companyID == "" {
+			c.JSON(http.StatusForbidden, gin.H{"message": "company assignment required"})
+			return "", theme.ManageScope{}, false
+		}
+		return userID, theme.ManageScope{CompanyID: companyID}, true
+	}
+
+	c.JSON(http.StatusForbidden, gin.H{"message": "SUPER_ADMIN or COMPANY_ADMIN role required"})
+	return "", theme.ManageScope{}, false
+}
+
 func requireSuperAdmin(c *gin.Context) (string, bool) {
 	userID := strings.TrimSpace(appmiddleware.GetCurrentUserID(c.Request.Context()))
 	if userID == "" {
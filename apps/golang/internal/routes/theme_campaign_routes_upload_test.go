@@ -2,11 +2,19 @@ package routes
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"image"
 	"image/color"
 	"image/jpeg"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"agrinovagraphql/server/internal/theme"
+	"agrinovagraphql/server/pkg/storage"
 )
 
 func TestDetectThemeAssetContentType(t *testing.T) {
@@ -324,3 +332,76 @@ func TestTransformThemeAssetForUploadUnsupportedServerOptimization(t *testing.T)
 		t.Fatalf("unexpected error message: %v", err)
 	}
 }
+
+func TestThemeAssetDirectory(t *testing.T) {
+	t.Parallel()
+
+	if got := themeAssetDirectory(theme.ManageScope{}, "web", "backgroundImage", ""); got != "theme-assets/web/backgroundImage" {
+		t.Fatalf("global directory = %q", got)
+	}
+	if got := themeAssetDirectory(theme.ManageScope{CompanyID: "company-1"}, "mobile", "appUiAsset", "navbar"); got != "theme-assets/companies/company-1/mobile/appUiAsset/navbar" {
+		t.Fatalf("company directory = %q", got)
+	}
+}
+
+func TestPruneThemeAssetDirectoryKeepsReferencedAssets(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	uploads, err := storage.NewLocalStore(root)
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	ctx := context.Background()
+	directory := "theme-assets/companies/company-1/web/backgroundImage"
+	put := func(key string, age time.Duration) {
+		t.Helper()
+		if err := storage.PutBytes(ctx, uploads, key, []byte("asset"), "image/png"); err != nil {
+			t.Fatalf("PutBytes(%s): %v", key, err)
+		}
+		modTime := time.Now().Add(-age)
+		if err := os.Chtimes(filepath.Join(root, filepath.FromSlash(key)), modTime, modTime); err != nil {
+			t.Fatalf("Chtimes(%s): %v", key, err)
+		}
+	}
+
+	expiredReferenced := directory + "/referenced.png"
+	expiredUnused := directory + "/unused.png"
+	otherCompany := "theme-assets/companies/company-2/web/backgroundImage/old.png"
+	put(expiredReferenced, 2*themeAssetRetentionMaxAge)
+	put(expiredUnused, 2*themeAssetRetentionMaxAge)
+	put(otherCompany, 2*themeAssetRetentionMaxAge)
+	// recent-00 is referenced and recent-01 is the new upload, so only the
+	// remaining files count toward the limit.
+	total := themeAssetRetentionMaxFilesPerPath + 3
+	for i := 0; i < total; i++ {
+		put(fmt.Sprintf("%s/recent-%02d.png", directory, i), time.Duration(total-i)*time.Minute)
+	}
+	referenced := map[string]struct{}{
+		storage.URLPath(expiredReferenced):            {},
+		storage.URLPath(directory + "/recent-00.png"): {},
+	}
+
+	if err := pruneThemeAssetDirectory(ctx, uploads, directory, directory+"/recent-01.png", referenced); err != nil {
+		t.Fatalf("pruneThemeAssetDirectory: %v", err)
+	}
+
+	exists := func(key string) bool {
+		_, err := os.Stat(filepath.Join(root, filepath.FromSlash(key)))
+		return err == nil
+	}
+	for _, key := range []string{expiredReferenced, otherCompany, directory + "/recent-00.png", directory + "/recent-01.png"} {
+		if !exists(key) {
+			t.Fatalf("expected %s to be kept", key)
+		}
+	}
+	if exists(expiredUnused) {
+		t.Fatalf("expected expired unreferenced asset to be pruned")
+	}
+	if exists(directory + "/recent-02.png") {
+		t.Fatalf("expected the oldest unreferenced asset over the limit to be pruned")
+	}
+	if !exists(directory + "/recent-03.png") {
+		t.Fatalf("expected assets within the limit to be kept")
+	}
+}
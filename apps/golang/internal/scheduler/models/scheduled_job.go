@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+// Built-in job names. Definitions are seeded by migrations; handlers are
+// registered with the scheduler at startup.
+const (
+	JobManagerDailySummary       = "manager_daily_summary"
+	JobWeeklyHarvestSummary      = "weekly_harvest_summary"
+	JobVehicleTaxReminders       = "vehicle_tax_reminders"
+	JobNotificationEmail         = "notification_email_dispatch"
+	JobNotificationEscalate      = "notification_escalations"
+	JobGateOverstayDetect        = "gate_overstay_detection"
+	JobHarvestApprovalEscalation = "harvest_approval_escalation"
+	JobSessionCleanup            = "session_cleanup"
+	JobSatpamNotificationOutbox  = "satpam_notification_outbox"
+	JobPhotoUploadCleanup        = "photo_upload_cleanup"
+)
+
+// Run statuses mirror the GraphQL ScheduledJobRunStatus enum.
+const (
+	RunStatusRunning   = "RUNNING"
+	RunStatusSucceeded = "SUCCEEDED"
+	RunStatusFailed    = "FAILED"
+)
+
+// Run triggers mirror the GraphQL ScheduledJobTrigger enum.
+const (
+	TriggerSchedule = "SCHEDULE"
+	TriggerManual   = "MANUAL"
+)
+
+// DefaultTimezone is used when neither the job nor its company has one.
+const DefaultTimezone = "Asia/Jakarta"
+
+// CompanyTimezone is reported as the effective timezone of a global job
+// without its own timezone; it runs in each company's configured timezone.
+const CompanyTimezone = "COMPANY"
+
+// ScheduledJob is a cron definition for a registered job handler. Rows with a
+// CompanyID override the global row of the same name for that company; the
+// global row then skips that company.
+type ScheduledJob struct {
+	ID             string     `gorm:"column:id;type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Name           string     `gorm:"column:name" json:"name"`
+	Description    *string    `gorm:"column:description" json:"description,omitempty"`
+	CronExpression string     `gorm:"column:cron_expression" json:"cronExpression"`
+	Timezone       *string    `gorm:"column:timezone" json:"timezone,omitempty"`
+	CompanyID      *string    `gorm:"column:company_id;type:uuid" json:"companyId,omitempty"`
+	IsPaused       bool       `gorm:"column:is_paused" json:"isPaused"`
+	NextRunAt      *time.Time `gorm:"column:next_run_at" json:"nextRunAt,omitempty"`
+	LastRunAt      *time.Time `gorm:"column:last_run_at" json:"lastRunAt,omitempty"`
+	LastStatus     *string    `gorm:"column:last_status" json:"lastStatus,omitempty"`
+	RunRequestedAt *time.Time `gorm:"column:run_requested_at" json:"runRequestedAt,omitempty"`
+	RunRequestedBy *string    `gorm:"column:run_requested_by;type:uuid" json:"runRequestedBy,omitempty"`
+	CreatedAt      time.Time  `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName returns the table name for ScheduledJob
+func (ScheduledJob) TableName() string {
+	return "scheduled_jobs"
+}
+
+// ScheduledJobRun records one execution of a scheduled job.
+type ScheduledJobRun struct {
+	ID           string     `gorm:"column:id;type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	JobID        string     `gorm:"column:job_id;type:uuid" json:"jobId"`
+	JobName      string     `gorm:"column:job_name" json:"jobName"`
+	CompanyID    *string    `gorm:"column:company_id;type:uuid" json:"companyId,omitempty"`
+	TriggerType  string     `gorm:"column:trigger_type" json:"trigger"`
+	TriggeredBy  *string    `gorm:"column:triggered_by;type:uuid" json:"triggeredBy,omitempty"`
+	Status       string     `gorm:"column:status" json:"status"`
+	InstanceID   string     `gorm:"column:instance_id" json:"instanceId"`
+	ScheduledFor *time.Time `gorm:"column:scheduled_for" json:"scheduledFor,omitempty"`
+	StartedAt    time.Time  `gorm:"column:started_at" json:"startedAt"`
+	FinishedAt   *time.Time `gorm:"column:finished_at" json:"finishedAt,omitempty"`
+	DurationMs   *int64     `gorm:"column:duration_ms" json:"durationMs,omitempty"`
+	Message      *string    `gorm:"column:message" json:"message,omitempty"`
+	Error        *string    `gorm:"column:error" json:"error,omitempty"`
+}
+
+// TableName returns the table name for ScheduledJobRun
+func (ScheduledJobRun) TableName() string {
+	return "scheduled_job_runs"
+}
@@ -0,0 +1,886 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	// Embedded zone data so company timezones resolve on minimal images.
+	_ "time/tzdata"
+
+	"agrinovagraphql/server/internal/scheduler/models"
+
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultSchedulerPollInterval = 30 * time.Second
+
+	// schedulerLeaderLockKey is the Postgres advisory lock held by the single
+	// instance allowed to run jobs.
+	schedulerLeaderLockKey int64 = 0x41475249_4e4f5641 // "AGRINOVA"
+
+	// scheduledJobRunTimeout bounds a single handler invocation.
+	scheduledJobRunTimeout = 30 * time.Minute
+
+	defaultScheduledJobRunLimit = 50
+	maxScheduledJobRunLimit     = 500
+)
+
+var (
+	ErrScheduledJobNotFound  = errors.New("scheduled job not found")
+	ErrUnknownScheduledJob   = errors.New("no handler is registered for this job name")
+	ErrDuplicateScheduledJob = errors.New("a scheduled job with this name already exists for the company")
+	ErrInvalidCronExpression = errors.New("invalid cron expression")
+	ErrInvalidTimezone       = errors.New("invalid timezone")
+)
+
+// JobContext is passed to a handler for one run.
+type JobContext struct {
+	Job *models.ScheduledJob
+	// CompanyIDs lists the companies this run covers: the job's own company,
+	// or every active company without an override for a global job.
+	CompanyIDs []string
+	// Location is the timezone the cron expression was evaluated in.
+	Location     *time.Location
+	ScheduledFor time.Time
+	Trigger      string
+}
+
+// JobFunc runs a scheduled job and returns a short summary for the run history.
+type JobFunc func(ctx context.Context, run JobContext) (string, error)
+
+// CreateScheduledJobInput defines a new global or company-scoped schedule.
+type CreateScheduledJobInput struct {
+	Name           string
+	CompanyID      *string
+	CronExpression string
+	Timezone       *string
+	Description    *string
+}
+
+// UpdateScheduledJobInput changes the schedule of an existing job.
+type UpdateScheduledJobInput struct {
+	CronExpression *string
+	Timezone       *string
+	Description    *string
+}
+
+// Scheduler runs database-defined cron jobs. Every instance polls, but only
+// the one holding the Postgres advisory lock executes jobs, so a deployment
+// with several replicas still runs each job once. Manual triggers are queued
+// on the job row and picked up by the leader on its next poll.
+type Scheduler struct {
+	db           *gorm.DB
+	instanceID   string
+	pollInterval time.Duration
+	lockKey      int64
+
+	handlersMu sync.RWMutex
+	handlers   map[string]JobFunc
+	// systemJobs names handlers that run once per occurrence for the whole
+	// deployment instead of per company.
+	systemJobs map[string]bool
+
+	leaderMu   sync.Mutex
+	leaderConn *sql.Conn
+	leader     atomic.Bool
+
+	// now, tryLock and unlock are replaced in tests; SQLite has no advisory
+	// locks.
+	now     func() time.Time
+	tryLock func(ctx context.Context, conn *sql.Conn, key int64) (bool, error)
+	unlock  func(ctx context.Context, conn *sql.Conn, key int64) error
+
+	startOnce sync.Once
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// NewScheduler creates a new scheduler
+func NewScheduler(db *gorm.DB) *Scheduler {
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "unknown"
+	}
+
+	return &Scheduler{
+		db:           db,
+		instanceID:   fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		pollInterval: defaultSchedulerPollInterval,
+		lockKey:      schedulerLeaderLockKey,
+		handlers:     make(map[string]JobFunc),
+		systemJobs:   make(map[string]bool),
+		now:          time.Now,
+		tryLock:      tryAdvisoryLock,
+		unlock:       advisoryUnlock,
+	}
+}
+
+// Register binds a handler to a job name. Registering after Start is allowed.
+func (s *Scheduler) Register(name string, fn JobFunc) {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+	s.handlers[name] = fn
+	delete(s.systemJobs, name)
+}
+
+// RegisterSystem binds a handler that works on the whole deployment, such as
+// a queue drain or a cleanup. It runs once per occurrence with no CompanyIDs,
+// in the job's timezone or DefaultTimezone.
+func (s *Scheduler) RegisterSystem(name string, fn JobFunc) {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+	s.handlers[name] = fn
+	s.systemJobs[name] = true
+}
+
+func (s *Scheduler) isSystemJob(name string) bool {
+	s.handlersMu.RLock()
+	defer s.handlersMu.RUnlock()
+	return s.systemJobs[name]
+}
+
+// RegisteredJobs returns the sorted names of registered handlers.
+func (s *Scheduler) RegisteredJobs() []string {
+	s.handlersMu.RLock()
+	defer s.handlersMu.RUnlock()
+
+	names := make([]string, 0, len(s.handlers))
+	for name := range s.handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// InstanceID identifies this process in run history.
+func (s *Scheduler) InstanceID() string {
+	return s.instanceID
+}
+
+// IsLeader reports whether this instance currently holds the scheduler lock.
+func (s *Scheduler) IsLeader() bool {
+	return s.leader.Load()
+}
+
+// Start begins polling in the background until ctx is cancelled or Stop is called.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.startOnce.Do(func() {
+		loopCtx, cancel := context.WithCancel(ctx)
+		s.cancel = cancel
+		s.done = make(chan struct{})
+
+		go func() {
+			defer close(s.done)
+			defer s.releaseLeadership()
+
+			ticker := time.NewTicker(s.pollInterval)
+			defer ticker.Stop()
+
+			s.tick(loopCtx)
+			for {
+				select {
+				case <-loopCtx.Done():
+					return
+				case <-ticker.C:
+					s.tick(loopCtx)
+				}
+			}
+		}()
+
+		log.Printf("Scheduler started (instance: %s, poll interval: %s)", s.instanceID, s.pollInterval)
+	})
+}
+
+// Stop halts polling and releases leadership.
+func (s *Scheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("scheduler tick panicked: %v", recovered)
+		}
+	}()
+
+	if !s.acquireLeadership(ctx) {
+		return
+	}
+	s.runDueJobs(ctx)
+}
+
+// acquireLeadership keeps a dedicated connection holding a session-level
+// advisory lock. The lock is released by Postgres if the connection dies, so
+// another instance takes over on its next poll.
+func (s *Scheduler) acquireLeadership(ctx context.Context) bool {
+	s.leaderMu.Lock()
+	defer s.leaderMu.Unlock()
+
+	if s.leaderConn != nil {
+		if err := s.leaderConn.PingContext(ctx); err == nil {
+			return true
+		}
+		log.Printf("Scheduler leadership lost on instance %s", s.instanceID)
+		s.leaderConn.Close()
+		s.leaderConn = nil
+		s.leader.Store(false)
+	}
+
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		log.Printf("scheduler: failed to access database pool: %v", err)
+		return false
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		log.Printf("scheduler: failed to open leader connection: %v", err)
+		return false
+	}
+
+	locked, err := s.tryLock(ctx, conn, s.lockKey)
+	if err != nil || !locked {
+		if err != nil {
+			log.Printf("scheduler: failed to try leader lock: %v", err)
+		}
+		conn.Close()
+		return false
+	}
+
+	s.leaderConn = conn
+	s.leader.Store(true)
+	log.Printf("Scheduler leadership acquired by instance %s", s.instanceID)
+	return true
+}
+
+func (s *Scheduler) releaseLeadership() {
+	s.leaderMu.Lock()
+	defer s.leaderMu.Unlock()
+
+	if s.leaderConn == nil {
+		return
+	}
+	// sql.Conn.Close returns the connection to the pool, so the session lock
+	// must be released explicitly.
+	if err := s.unlock(context.Background(), s.leaderConn, s.lockKey); err != nil {
+		log.Printf("scheduler: failed to release leader lock: %v", err)
+	}
+	s.leaderConn.Close()
+	s.leaderConn = nil
+	s.leader.Store(false)
+}
+
+func tryAdvisoryLock(ctx context.Context, conn *sql.Conn, key int64) (bool, error) {
+	var locked bool
+	err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&locked)
+	return locked, err
+}
+
+func advisoryUnlock(ctx context.Context, conn *sql.Conn, key int64) error {
+	_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", key)
+	return err
+}
+
+func (s *Scheduler) runDueJobs(ctx context.Context) {
+	now := s.now()
+
+	var jobs []*models.ScheduledJob
+	if err := s.db.WithContext(ctx).
+		Where("run_requested_at IS NOT NULL OR (is_paused = ? AND (next_run_at IS NULL OR next_run_at <= ?))", false, now).
+		Order("next_run_at ASC NULLS FIRST").
+		Find(&jobs).Error; err != nil {
+		log.Printf("scheduler: failed to load due jobs: %v", err)
+		return
+	}
+
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			return
+		}
+
+		groups, err := s.jobGroups(ctx, job)
+		if err != nil {
+			log.Printf("scheduler: failed to resolve companies for job %s: %v", job.Name, err)
+			continue
+		}
+		switch {
+		case job.RunRequestedAt != nil:
+			for i := range groups {
+				groups[i].scheduledFor = *job.RunRequestedAt
+			}
+			s.execute(ctx, job, groups, groups, models.TriggerManual, job.RunRequestedBy)
+		case job.NextRunAt == nil:
+			// Newly seeded or resumed jobs are scheduled, not run immediately.
+			if err := s.scheduleNext(ctx, job, groups, now); err != nil {
+				log.Printf("scheduler: failed to schedule job %s: %v", job.Name, err)
+			}
+		default:
+			due := dueGroups(job, groups, *job.NextRunAt, now)
+			if len(due) == 0 {
+				// The companies whose slot this was changed timezone or
+				// went away since the job was scheduled.
+				if err := s.scheduleNext(ctx, job, groups, now); err != nil {
+					log.Printf("scheduler: failed to reschedule job %s: %v", job.Name, err)
+				}
+				continue
+			}
+			s.execute(ctx, job, groups, due, models.TriggerSchedule, nil)
+		}
+	}
+}
+
+func (s *Scheduler) scheduleNext(ctx context.Context, job *models.ScheduledJob, groups []companyGroup, after time.Time) error {
+	next, err := nextGroupRun(job.CronExpression, groups, after)
+	if err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).
+		Model(&models.ScheduledJob{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]interface{}{"next_run_at": next, "updated_at": s.now()}).Error
+}
+
+// execute records a run, advances the schedule and invokes the handler once
+// per due timezone group. The next run is computed from now rather than from
+// the missed slot, so an instance that was down for days runs each overdue
+// job once instead of replaying every missed occurrence.
+func (s *Scheduler) execute(
+	ctx context.Context,
+	job *models.ScheduledJob,
+	groups []companyGroup,
+	due []companyGroup,
+	trigger string,
+	triggeredBy *string,
+) {
+	scheduledFor := due[0].scheduledFor
+	startedAt := s.now()
+	run := &models.ScheduledJobRun{
+		JobID:        job.ID,
+		JobName:      job.Name,
+		CompanyID:    job.CompanyID,
+		TriggerType:  trigger,
+		TriggeredBy:  triggeredBy,
+		Status:       models.RunStatusRunning,
+		InstanceID:   s.instanceID,
+		ScheduledFor: &scheduledFor,
+		StartedAt:    startedAt,
+	}
+	if err := s.db.WithContext(ctx).Create(run).Error; err != nil {
+		log.Printf("scheduler: failed to record run of %s: %v", job.Name, err)
+		return
+	}
+
+	jobUpdates := map[string]interface{}{
+		"last_run_at": startedAt,
+		"last_status": models.RunStatusRunning,
+		"updated_at":  startedAt,
+	}
+	if trigger == models.TriggerManual {
+		jobUpdates["run_requested_at"] = nil
+		jobUpdates["run_requested_by"] = nil
+	}
+	if !job.IsPaused {
+		if next, err := nextGroupRun(job.CronExpression, groups, startedAt); err == nil {
+			jobUpdates["next_run_at"] = next
+		} else {
+			log.Printf("scheduler: job %s has an invalid schedule: %v", job.Name, err)
+			jobUpdates["next_run_at"] = nil
+		}
+	}
+	if err := s.db.WithContext(ctx).Model(&models.ScheduledJob{}).Where("id = ?", job.ID).Updates(jobUpdates).Error; err != nil {
+		log.Printf("scheduler: failed to advance job %s: %v", job.Name, err)
+	}
+
+	message, runErr := s.invokeGroups(ctx, job, due, trigger)
+
+	finishedAt := s.now()
+	durationMs := finishedAt.Sub(startedAt).Milliseconds()
+	run.FinishedAt = &finishedAt
+	run.DurationMs = &durationMs
+	run.Status = models.RunStatusSucceeded
+	if message != "" {
+		run.Message = &message
+	}
+	if runErr != nil {
+		errMessage := runErr.Error()
+		run.Status = models.RunStatusFailed
+		run.Error = &errMessage
+		log.Printf("Scheduled job %s failed after %dms: %v", job.Name, durationMs, runErr)
+	} else {
+		log.Printf("Scheduled job %s finished in %dms: %s", job.Name, durationMs, message)
+	}
+
+	// Use a fresh context so shutdown mid-run still records the outcome.
+	saveCtx := context.WithoutCancel(ctx)
+	if err := s.db.WithContext(saveCtx).Save(run).Error; err != nil {
+		log.Printf("scheduler: failed to finish run of %s: %v", job.Name, err)
+	}
+	if err := s.db.WithContext(saveCtx).
+		Model(&models.ScheduledJob{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]interface{}{"last_status": run.Status, "updated_at": finishedAt}).Error; err != nil {
+		log.Printf("scheduler: failed to update status of %s: %v", job.Name, err)
+	}
+}
+
+// invokeGroups runs the handler for each group and combines the summaries.
+// A failing group does not stop the others.
+func (s *Scheduler) invokeGroups(ctx context.Context, job *models.ScheduledJob, groups []companyGroup, trigger string) (string, error) {
+	s.handlersMu.RLock()
+	handler, ok := s.handlers[job.Name]
+	s.handlersMu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownScheduledJob, job.Name)
+	}
+
+	if len(groups) == 1 {
+		return s.invoke(ctx, handler, JobContext{
+			Job:          job,
+			CompanyIDs:   groups[0].companyIDs,
+			Location:     groups[0].location,
+			ScheduledFor: groups[0].scheduledFor,
+			Trigger:      trigger,
+		})
+	}
+
+	messages := make([]string, 0, len(groups))
+	var errs []error
+	for _, group := range groups {
+		message, err := s.invoke(ctx, handler, JobContext{
+			Job:          job,
+			CompanyIDs:   group.companyIDs,
+			Location:     group.location,
+			ScheduledFor: group.scheduledFor,
+			Trigger:      trigger,
+		})
+		if message != "" {
+			messages = append(messages, fmt.Sprintf("%s: %s", group.location, message))
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", group.location, err))
+		}
+	}
+	return strings.Join(messages, "; "), errors.Join(errs...)
+}
+
+func (s *Scheduler) invoke(ctx context.Context, handler JobFunc, run JobContext) (message string, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("job panicked: %v", recovered)
+		}
+	}()
+
+	runCtx, cancel := context.WithTimeout(ctx, scheduledJobRunTimeout)
+	defer cancel()
+	return handler(runCtx, run)
+}
+
+// companyGroup is the set of companies one handler invocation covers, all
+// sharing the timezone the cron expression is evaluated in.
+type companyGroup struct {
+	location     *time.Location
+	companyIDs   []string
+	scheduledFor time.Time
+}
+
+type scheduledCompany struct {
+	ID       string  `gorm:"column:id"`
+	Timezone *string `gorm:"column:timezone"`
+}
+
+// jobGroups returns the companies a run covers, grouped by timezone. A
+// system job, a company-scoped job, or a global job with its own timezone
+// forms a single group. A global job without one follows each company's configured
+// timezone, so a 06:00 job fires at 06:00 WIB for Sumatra estates and at
+// 06:00 WIT for Papua estates. Global jobs skip companies that have their own
+// definition of the same job.
+func (s *Scheduler) jobGroups(ctx context.Context, job *models.ScheduledJob) ([]companyGroup, error) {
+	if s.isSystemJob(job.Name) {
+		return []companyGroup{{location: s.jobLocation(ctx, job)}}, nil
+	}
+	if job.CompanyID != nil && *job.CompanyID != "" {
+		return []companyGroup{{location: s.jobLocation(ctx, job), companyIDs: []string{*job.CompanyID}}}, nil
+	}
+
+	var companies []scheduledCompany
+	if err := s.db.WithContext(ctx).
+		Table("companies").
+		Select("companies.id, company_settings.timezone").
+		Joins("LEFT JOIN company_settings ON company_settings.company_id = companies.id").
+		Where("companies.is_active = ?", true).
+		Where("companies.id NOT IN (?)", s.db.Table("scheduled_jobs").Select("company_id").Where("name = ? AND company_id IS NOT NULL", job.Name)).
+		Order("companies.name ASC").
+		Scan(&companies).Error; err != nil {
+		return nil, fmt.Errorf("failed to list companies for job %s: %w", job.Name, err)
+	}
+
+	if hasTimezone(job) || len(companies) == 0 {
+		companyIDs := make([]string, 0, len(companies))
+		for _, company := range companies {
+			companyIDs = append(companyIDs, company.ID)
+		}
+		return []companyGroup{{location: s.jobLocation(ctx, job), companyIDs: companyIDs}}, nil
+	}
+
+	var groups []companyGroup
+	groupIndex := make(map[string]int)
+	for _, company := range companies {
+		location := defaultLocation()
+		if company.Timezone != nil {
+			if companyLocation, err := LoadTimezone(*company.Timezone); err == nil {
+				location = companyLocation
+			}
+		}
+		index, ok := groupIndex[location.String()]
+		if !ok {
+			index = len(groups)
+			groupIndex[location.String()] = index
+			groups = append(groups, companyGroup{location: location})
+		}
+		groups[index].companyIDs = append(groups[index].companyIDs, company.ID)
+	}
+	return groups, nil
+}
+
+// dueGroups returns the groups with an occurrence between the job's
+// next_run_at and now. next_run_at is the earliest occurrence across groups,
+// so no group has one that falls before it.
+func dueGroups(job *models.ScheduledJob, groups []companyGroup, nextRunAt time.Time, now time.Time) []companyGroup {
+	var due []companyGroup
+	for _, group := range groups {
+		occurrence, err := NextRun(job.CronExpression, group.location, nextRunAt.Add(-time.Second))
+		if err != nil || occurrence.After(now) {
+			continue
+		}
+		group.scheduledFor = occurrence
+		due = append(due, group)
+	}
+	return due
+}
+
+// nextGroupRun returns the earliest occurrence after the given time across
+// all groups.
+func nextGroupRun(expression string, groups []companyGroup, after time.Time) (time.Time, error) {
+	if len(groups) == 0 {
+		groups = []companyGroup{{location: defaultLocation()}}
+	}
+	var earliest time.Time
+	for _, group := range groups {
+		next, err := NextRun(expression, group.location, after)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if earliest.IsZero() || next.Before(earliest) {
+			earliest = next
+		}
+	}
+	return earliest.UTC(), nil
+}
+
+// jobLocation resolves the job's timezone, falling back to the company's
+// configured timezone and then to DefaultTimezone.
+func (s *Scheduler) jobLocation(ctx context.Context, job *models.ScheduledJob) *time.Location {
+	location, err := LoadTimezone(s.EffectiveTimezone(ctx, job))
+	if err != nil {
+		return defaultLocation()
+	}
+	return location
+}
+
+func defaultLocation() *time.Location {
+	location, err := LoadTimezone(models.DefaultTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+func hasTimezone(job *models.ScheduledJob) bool {
+	return job.Timezone != nil && strings.TrimSpace(*job.Timezone) != ""
+}
+
+// EffectiveTimezone returns the timezone name a job's cron expression is
+// evaluated in, or models.CompanyTimezone for a global job that follows each
+// company's timezone.
+func (s *Scheduler) EffectiveTimezone(ctx context.Context, job *models.ScheduledJob) string {
+	if hasTimezone(job) {
+		return strings.TrimSpace(*job.Timezone)
+	}
+	if job.CompanyID == nil || *job.CompanyID == "" {
+		if s.isSystemJob(job.Name) {
+			return models.DefaultTimezone
+		}
+		return models.CompanyTimezone
+	}
+
+	var timezones []string
+	if err := s.db.WithContext(ctx).
+		Table("company_settings").
+		Where("company_id = ?", *job.CompanyID).
+		Limit(1).
+		Pluck("timezone", &timezones).Error; err == nil && len(timezones) > 0 && strings.TrimSpace(timezones[0]) != "" {
+		return strings.TrimSpace(timezones[0])
+	}
+	return models.DefaultTimezone
+}
+
+// ListJobs returns all job definitions, global rows first.
+func (s *Scheduler) ListJobs(ctx context.Context) ([]*models.ScheduledJob, error) {
+	var jobs []*models.ScheduledJob
+	if err := s.db.WithContext(ctx).Order("name ASC, company_id ASC NULLS FIRST").Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list scheduled jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// GetJob returns a job definition by ID.
+func (s *Scheduler) GetJob(ctx context.Context, id string) (*models.ScheduledJob, error) {
+	var job models.ScheduledJob
+	if err := s.db.WithContext(ctx).Where("id = ?", strings.TrimSpace(id)).First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrScheduledJobNotFound
+		}
+		return nil, fmt.Errorf("failed to load scheduled job: %w", err)
+	}
+	return &job, nil
+}
+
+// ListRuns returns recent runs, optionally filtered by job and status.
+func (s *Scheduler) ListRuns(ctx context.Context, jobID string, status string, limit int) ([]*models.ScheduledJobRun, error) {
+	if limit <= 0 {
+		limit = defaultScheduledJobRunLimit
+	}
+	if limit > maxScheduledJobRunLimit {
+		limit = maxScheduledJobRunLimit
+	}
+
+	query := s.db.WithContext(ctx).Order("started_at DESC").Limit(limit)
+	if jobID = strings.TrimSpace(jobID); jobID != "" {
+		query = query.Where("job_id = ?", jobID)
+	}
+	if status = strings.TrimSpace(status); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var runs []*models.ScheduledJobRun
+	if err := query.Find(&runs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list scheduled job runs: %w", err)
+	}
+	return runs, nil
+}
+
+// CreateJob adds a schedule for a registered handler. A company-scoped job
+// overrides the global schedule of the same name for that company.
+func (s *Scheduler) CreateJob(ctx context.Context, input CreateScheduledJobInput) (*models.ScheduledJob, error) {
+	name := strings.TrimSpace(input.Name)
+	s.handlersMu.RLock()
+	_, registered := s.handlers[name]
+	s.handlersMu.RUnlock()
+	if !registered {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownScheduledJob, name)
+	}
+
+	job := &models.ScheduledJob{
+		Name:           name,
+		Description:    trimmedOrNil(input.Description),
+		CronExpression: strings.TrimSpace(input.CronExpression),
+		Timezone:       trimmedOrNil(input.Timezone),
+		CompanyID:      trimmedOrNil(input.CompanyID),
+	}
+
+	duplicateQuery := s.db.WithContext(ctx).Model(&models.ScheduledJob{}).Where("name = ?", name)
+	if job.CompanyID != nil {
+		duplicateQuery = duplicateQuery.Where("company_id = ?", *job.CompanyID)
+	} else {
+		duplicateQuery = duplicateQuery.Where("company_id IS NULL")
+	}
+	var existing int64
+	if err := duplicateQuery.Count(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to check scheduled job: %w", err)
+	}
+	if existing > 0 {
+		return nil, ErrDuplicateScheduledJob
+	}
+
+	next, err := s.nextRunFor(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	job.NextRunAt = &next
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	if err := s.db.WithContext(ctx).Create(job).Error; err != nil {
+		return nil, fmt.Errorf("failed to create scheduled job: %w", err)
+	}
+	return job, nil
+}
+
+// UpdateJob changes a job's cron expression, timezone or description and
+// reschedules it.
+func (s *Scheduler) UpdateJob(ctx context.Context, id string, input UpdateScheduledJobInput) (*models.ScheduledJob, error) {
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.CronExpression != nil {
+		job.CronExpression = strings.TrimSpace(*input.CronExpression)
+	}
+	if input.Timezone != nil {
+		job.Timezone = trimmedOrNil(input.Timezone)
+	}
+	if input.Description != nil {
+		job.Description = trimmedOrNil(input.Description)
+	}
+
+	next, err := s.nextRunFor(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	job.NextRunAt = &next
+	job.UpdatedAt = s.now()
+
+	if err := s.db.WithContext(ctx).Save(job).Error; err != nil {
+		return nil, fmt.Errorf("failed to update scheduled job: %w", err)
+	}
+	return job, nil
+}
+
+// SetPaused pauses or resumes a job. Resuming schedules the next occurrence
+// from now; missed occurrences are not replayed.
+func (s *Scheduler) SetPaused(ctx context.Context, id string, paused bool) (*models.ScheduledJob, error) {
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	job.IsPaused = paused
+	job.NextRunAt = nil
+	if !paused {
+		next, err := s.nextRunFor(ctx, job)
+		if err != nil {
+			return nil, err
+		}
+		job.NextRunAt = &next
+	}
+	job.UpdatedAt = s.now()
+
+	if err := s.db.WithContext(ctx).Save(job).Error; err != nil {
+		return nil, fmt.Errorf("failed to update scheduled job: %w", err)
+	}
+	return job, nil
+}
+
+// Trigger queues a manual run, which the leader executes on its next poll
+// even when the job is paused.
+func (s *Scheduler) Trigger(ctx context.Context, id string, requestedBy string) (*models.ScheduledJob, error) {
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	job.RunRequestedAt = &now
+	job.RunRequestedBy = trimmedOrNil(&requestedBy)
+	job.UpdatedAt = now
+
+	if err := s.db.WithContext(ctx).
+		Model(&models.ScheduledJob{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]interface{}{
+			"run_requested_at": job.RunRequestedAt,
+			"run_requested_by": job.RunRequestedBy,
+			"updated_at":       now,
+		}).Error; err != nil {
+		return nil, fmt.Errorf("failed to trigger scheduled job: %w", err)
+	}
+	return job, nil
+}
+
+func (s *Scheduler) nextRunFor(ctx context.Context, job *models.ScheduledJob) (time.Time, error) {
+	if job.Timezone != nil {
+		if _, err := LoadTimezone(*job.Timezone); err != nil {
+			return time.Time{}, err
+		}
+	}
+	groups, err := s.jobGroups(ctx, job)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return nextGroupRun(job.CronExpression, groups, s.now())
+}
+
+// ParseSchedule parses a standard five-field cron expression or a descriptor
+// such as @daily. Timezones are configured on the job, so CRON_TZ/TZ
+// prefixes are rejected.
+func ParseSchedule(expression string) (cron.Schedule, error) {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return nil, fmt.Errorf("%w: expression is empty", ErrInvalidCronExpression)
+	}
+	if strings.HasPrefix(expression, "TZ=") || strings.HasPrefix(expression, "CRON_TZ=") {
+		return nil, fmt.Errorf("%w: set the timezone on the job instead of in the expression", ErrInvalidCronExpression)
+	}
+	schedule, err := cron.ParseStandard(expression)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCronExpression, err)
+	}
+	return schedule, nil
+}
+
+// NextRun returns the first occurrence of expression after the given time,
+// evaluated in location.
+func NextRun(expression string, location *time.Location, after time.Time) (time.Time, error) {
+	schedule, err := ParseSchedule(expression)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if location == nil {
+		location = time.UTC
+	}
+	next := schedule.Next(after.In(location))
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("%w: expression never fires", ErrInvalidCronExpression)
+	}
+	return next, nil
+}
+
+// LoadTimezone loads an IANA timezone such as Asia/Makassar.
+func LoadTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: timezone is empty", ErrInvalidTimezone)
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTimezone, name)
+	}
+	return location, nil
+}
+
+func trimmedOrNil(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
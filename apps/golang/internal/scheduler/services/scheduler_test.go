@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"agrinovagraphql/server/internal/scheduler/models"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// setupSchedulerTestDB opens a named shared-cache database so the leader
+// connection and the pool see the same tables.
+func setupSchedulerTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	for _, stmt := range []string{
+		`CREATE TABLE companies (id TEXT PRIMARY KEY, name TEXT, is_active BOOLEAN DEFAULT true)`,
+		`CREATE TABLE company_settings (company_id TEXT PRIMARY KEY, timezone TEXT)`,
+		`CREATE TABLE scheduled_jobs (
+			id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+			name TEXT, description TEXT, cron_expression TEXT, timezone TEXT, company_id TEXT,
+			is_paused BOOLEAN DEFAULT false, next_run_at DATETIME, last_run_at DATETIME, last_status TEXT,
+			run_requested_at DATETIME, run_requested_by TEXT, created_at DATETIME, updated_at DATETIME
+		)`,
+		`CREATE TABLE scheduled_job_runs (
+			id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+			job_id TEXT, job_name TEXT, company_id TEXT, trigger_type TEXT, triggered_by TEXT,
+			status TEXT, instance_id TEXT, scheduled_for DATETIME, started_at DATETIME,
+			finished_at DATETIME, duration_ms INTEGER, message TEXT, error TEXT
+		)`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+	return db
+}
+
+// fakeAdvisoryLock stands in for pg_try_advisory_lock: one connection holds
+// it until it unlocks.
+type fakeAdvisoryLock struct {
+	mu     sync.Mutex
+	holder *sql.Conn
+}
+
+func (l *fakeAdvisoryLock) install(s *Scheduler) {
+	s.tryLock = func(_ context.Context, conn *sql.Conn, _ int64) (bool, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.holder != nil && l.holder != conn {
+			return false, nil
+		}
+		l.holder = conn
+		return true, nil
+	}
+	s.unlock = func(_ context.Context, conn *sql.Conn, _ int64) error {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if l.holder == conn {
+			l.holder = nil
+		}
+		return nil
+	}
+}
+
+type testClock struct{ now time.Time }
+
+func newTestScheduler(db *gorm.DB, clock *testClock) *Scheduler {
+	s := NewScheduler(db)
+	s.now = func() time.Time { return clock.now }
+	return s
+}
+
+func seedSchedulerCompany(t *testing.T, db *gorm.DB, id, name, timezone string) {
+	t.Helper()
+	require.NoError(t, db.Exec(`INSERT INTO companies (id, name) VALUES (?, ?)`, id, name).Error)
+	if timezone != "" {
+		require.NoError(t, db.Exec(`INSERT INTO company_settings VALUES (?, ?)`, id, timezone).Error)
+	}
+}
+
+func reloadJob(t *testing.T, s *Scheduler, id string) *models.ScheduledJob {
+	t.Helper()
+	job, err := s.GetJob(context.Background(), id)
+	require.NoError(t, err)
+	return job
+}
+
+func TestNextRunUsesLocation(t *testing.T) {
+	makassar, err := LoadTimezone("Asia/Makassar")
+	require.NoError(t, err)
+
+	after := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC) // 08:00 WITA
+
+	next, err := NextRun("0 6 * * *", makassar, after)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 2, 22, 0, 0, 0, time.UTC), next.UTC())
+
+	next, err = NextRun("0 6 * * *", time.UTC, after)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 2, 6, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func TestParseScheduleRejectsInvalidExpressions(t *testing.T) {
+	for _, expression := range []string{"", "not a cron", "0 6 * *", "CRON_TZ=UTC 0 6 * * *", "TZ=UTC 0 6 * * *"} {
+		_, err := ParseSchedule(expression)
+		assert.True(t, errors.Is(err, ErrInvalidCronExpression), "expression %q", expression)
+	}
+
+	_, err := ParseSchedule(" 0 7 * * 1 ")
+	assert.NoError(t, err)
+}
+
+func TestLoadTimezone(t *testing.T) {
+	loc, err := LoadTimezone("Asia/Jayapura")
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Jayapura", loc.String())
+
+	for _, name := range []string{"", "Mars/Olympus"} {
+		_, err := LoadTimezone(name)
+		assert.True(t, errors.Is(err, ErrInvalidTimezone), "timezone %q", name)
+	}
+}
+
+func TestSchedulerLeaderLock(t *testing.T) {
+	db := setupSchedulerTestDB(t)
+	clock := &testClock{now: time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC)}
+	lock := &fakeAdvisoryLock{}
+	first := newTestScheduler(db, clock)
+	second := newTestScheduler(db, clock)
+	lock.install(first)
+	lock.install(second)
+
+	var runs []string
+	for name, s := range map[string]*Scheduler{"first": first, "second": second} {
+		name := name
+		s.RegisterSystem("cleanup", func(context.Context, JobContext) (string, error) {
+			runs = append(runs, name)
+			return "", nil
+		})
+	}
+	job, err := first.CreateJob(context.Background(), CreateScheduledJobInput{Name: "cleanup", CronExpression: "* * * * *"})
+	require.NoError(t, err)
+
+	first.tick(context.Background())
+	assert.True(t, first.IsLeader())
+	assert.Empty(t, runs, "the first occurrence is not due yet")
+
+	clock.now = clock.now.Add(90 * time.Second)
+	second.tick(context.Background())
+	assert.False(t, second.IsLeader())
+	assert.Empty(t, runs, "only the lock holder runs jobs")
+
+	first.tick(context.Background())
+	assert.True(t, first.IsLeader(), "the leader keeps its lock across polls")
+	assert.Equal(t, []string{"first"}, runs)
+
+	first.releaseLeadership()
+	assert.False(t, first.IsLeader())
+
+	clock.now = clock.now.Add(time.Minute)
+	second.tick(context.Background())
+	assert.True(t, second.IsLeader(), "another instance takes over once the lock is released")
+	assert.Equal(t, []string{"first", "second"}, runs)
+	second.releaseLeadership()
+
+	var recorded []string
+	require.NoError(t, db.Model(&models.ScheduledJobRun{}).Where("job_id = ?", job.ID).Order("started_at ASC").Pluck("instance_id", &recorded).Error)
+	assert.Len(t, recorded, 2)
+}
+
+func TestSchedulerDispatchesGlobalJobPerCompanyTimezone(t *testing.T) {
+	db := setupSchedulerTestDB(t)
+	ctx := context.Background()
+	// 03:00 WIB / 05:00 WIT on 2 March.
+	clock := &testClock{now: time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)}
+	s := newTestScheduler(db, clock)
+
+	seedSchedulerCompany(t, db, "company-aceh", "Aceh Estate", "Asia/Jakarta")
+	seedSchedulerCompany(t, db, "company-kalbar", "Kalbar Estate", "")
+	seedSchedulerCompany(t, db, "company-papua", "Papua Estate", "Asia/Jayapura")
+	seedSchedulerCompany(t, db, "company-override", "Override Estate", "Asia/Jayapura")
+	seedSchedulerCompany(t, db, "company-closed", "Closed Estate", "Asia/Jayapura")
+	require.NoError(t, db.Exec(`UPDATE companies SET is_active = false WHERE id = 'company-closed'`).Error)
+
+	var runs []JobContext
+	s.Register("daily_summary", func(_ context.Context, run JobContext) (string, error) {
+		runs = append(runs, run)
+		return "sent", nil
+	})
+
+	job, err := s.CreateJob(ctx, CreateScheduledJobInput{Name: "daily_summary", CronExpression: "0 6 * * *"})
+	require.NoError(t, err)
+	_, err = s.CreateJob(ctx, CreateScheduledJobInput{Name: "daily_summary", CronExpression: "0 9 * * *", CompanyID: stringPtr("company-override")})
+	require.NoError(t, err)
+	assert.Equal(t, models.CompanyTimezone, s.EffectiveTimezone(ctx, job))
+
+	// 06:00 WIT comes first.
+	papuaSlot := time.Date(2026, 3, 1, 21, 0, 0, 0, time.UTC)
+	require.NotNil(t, job.NextRunAt)
+	assert.Equal(t, papuaSlot, job.NextRunAt.UTC())
+
+	clock.now = papuaSlot.Add(30 * time.Second)
+	s.runDueJobs(ctx)
+	require.Len(t, runs, 1)
+	assert.Equal(t, "Asia/Jayapura", runs[0].Location.String())
+	assert.Equal(t, []string{"company-papua"}, runs[0].CompanyIDs, "companies with an override or inactive are skipped")
+	assert.Equal(t, papuaSlot, runs[0].ScheduledFor.UTC())
+
+	// 06:00 WIB follows two hours later for the western estates.
+	jakartaSlot := time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, jakartaSlot, reloadJob(t, s, job.ID).NextRunAt.UTC())
+
+	clock.now = jakartaSlot.Add(30 * time.Second)
+	s.runDueJobs(ctx)
+	require.Len(t, runs, 2)
+	assert.Equal(t, "Asia/Jakarta", runs[1].Location.String())
+	assert.Equal(t, []string{"company-aceh", "company-kalbar"}, runs[1].CompanyIDs, "companies without settings use the default timezone")
+	assert.Equal(t, papuaSlot.AddDate(0, 0, 1), reloadJob(t, s, job.ID).NextRunAt.UTC())
+}
+
+func TestSchedulerRecordsRunHistory(t *testing.T) {
+	db := setupSchedulerTestDB(t)
+	ctx := context.Background()
+	clock := &testClock{now: time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)}
+	s := newTestScheduler(db, clock)
+
+	seedSchedulerCompany(t, db, "company-aceh", "Aceh Estate", "Asia/Jakarta")
+	seedSchedulerCompany(t, db, "company-papua", "Papua Estate", "Asia/Jayapura")
+
+	s.Register("weekly_summary", func(_ context.Context, run JobContext) (string, error) {
+		if run.Location.String() == "Asia/Jayapura" {
+			return "", errors.New("push provider unavailable")
+		}
+		return "1 summary sent", nil
+	})
+	s.RegisterSystem("broken", func(context.Context, JobContext) (string, error) {
+		panic("nil service")
+	})
+
+	summary, err := s.CreateJob(ctx, CreateScheduledJobInput{Name: "weekly_summary", CronExpression: "0 7 * * 1"})
+	require.NoError(t, err)
+	broken, err := s.CreateJob(ctx, CreateScheduledJobInput{Name: "broken", CronExpression: "* * * * *"})
+	require.NoError(t, err)
+
+	_, err = s.Trigger(ctx, summary.ID, "user-1")
+	require.NoError(t, err)
+	clock.now = clock.now.Add(90 * time.Second)
+	s.runDueJobs(ctx)
+
+	runs, err := s.ListRuns(ctx, summary.ID, "", 0)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	manual := runs[0]
+	assert.Equal(t, models.TriggerManual, manual.TriggerType)
+	require.NotNil(t, manual.TriggeredBy)
+	assert.Equal(t, "user-1", *manual.TriggeredBy)
+	assert.Equal(t, models.RunStatusFailed, manual.Status)
+	require.NotNil(t, manual.Message)
+	assert.Equal(t, "Asia/Jakarta: 1 summary sent", *manual.Message, "a failing group does not hide the others")
+	require.NotNil(t, manual.Error)
+	assert.Equal(t, "Asia/Jayapura: push provider unavailable", *manual.Error)
+	assert.NotNil(t, manual.FinishedAt)
+
+	job := reloadJob(t, s, summary.ID)
+	assert.Nil(t, job.RunRequestedAt, "the manual request is consumed")
+	require.NotNil(t, job.LastStatus)
+	assert.Equal(t, models.RunStatusFailed, *job.LastStatus)
+
+	failed, err := s.ListRuns(ctx, broken.ID, models.RunStatusFailed, 0)
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, models.TriggerSchedule, failed[0].TriggerType)
+	assert.Equal(t, "job panicked: nil service", *failed[0].Error)
+
+	succeeded, err := s.ListRuns(ctx, "", models.RunStatusSucceeded, 0)
+	require.NoError(t, err)
+	assert.Empty(t, succeeded)
+}
+
+func stringPtr(value string) *string { return &value }
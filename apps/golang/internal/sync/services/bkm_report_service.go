@@ -8,6 +8,7 @@ import (
 	"agrinovagraphql/server/internal/graphql/domain/auth"
 	"agrinovagraphql/server/internal/graphql/domain/bkm"
 	"agrinovagraphql/server/internal/middleware"
+	"agrinovagraphql/server/pkg/tracing"
 
 	"gorm.io/gorm"
 )
@@ -30,7 +31,10 @@ func NewBkmReportService(db *gorm.DB) *BkmReportService {
 
 // GetPotongBuahReport queries ais_bkmmaster + ais_bkmdetail for pekerjaan=41001
 // and returns a hierarchical report aggregated by estate → divisi → blok.
-func (s *BkmReportService) GetPotongBuahReport(ctx context.Context, filter *bkm.BkmPotongBuahFilter) (*bkm.BkmPotongBuahSummary, error) {
+func (s *BkmReportService) GetPotongBuahReport(ctx context.Context, filter *bkm.BkmPotongBuahFilter) (_ *bkm.BkmPotongBuahSummary, err error) {
+	ctx, span := tracing.Start(ctx, "BkmReportService.GetPotongBuahReport")
+	defer func() { tracing.End(span, err) }()
+
 	rows, err := s.queryRows(ctx, filter)
 	if err != nil {
 		return nil, fmt.Errorf("bkm report query failed: %w", err)
@@ -434,7 +438,10 @@ func (s *BkmReportService) aggregateRows(rows []*bkm.RawPotongBuahRow) *bkm.BkmP
 
 // GetPotongBuahFlat queries ais_bkmmaster + ais_bkmdetail for pekerjaan=41001
 // and returns a flat list with pagination and summary.
-func (s *BkmReportService) GetPotongBuahFlat(ctx context.Context, filter *bkm.BkmPotongBuahFilter, page int32, limit int32) (*bkm.BkmPotongBuahFlatResponse, error) {
+func (s *BkmReportService) GetPotongBuahFlat(ctx context.Context, filter *bkm.BkmPotongBuahFilter, page int32, limit int32) (_ *bkm.BkmPotongBuahFlatResponse, err error) {
+	ctx, span := tracing.Start(ctx, "BkmReportService.GetPotongBuahFlat")
+	defer func() { tracing.End(span, err) }()
+
 	conditions, args, err := s.buildReportConditions(ctx, filter)
 	if err != nil {
 		return nil, err
@@ -656,7 +663,10 @@ func (s *BkmReportService) GetPotongBuahFlat(ctx context.Context, filter *bkm.Bk
 }
 
 // GetPotongBuahAnalytics returns aggregated analytics data for dashboard usage.
-func (s *BkmReportService) GetPotongBuahAnalytics(ctx context.Context, filter *bkm.BkmPotongBuahFilter, topN int32) (*bkm.BkmPotongBuahAnalytics, error) {
+func (s *BkmReportService) GetPotongBuahAnalytics(ctx context.Context, filter *bkm.BkmPotongBuahFilter, topN int32) (_ *bkm.BkmPotongBuahAnalytics, err error) {
+	ctx, span := tracing.Start(ctx, "BkmReportService.GetPotongBuahAnalytics")
+	defer func() { tracing.End(span, err) }()
+
 	conditions, args, err := s.buildReportConditions(ctx, filter)
 	if err != nil {
 		return nil, err
@@ -7,9 +7,12 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
 
 	"agrinovagraphql/server/internal/graphql/domain/bkm"
+	"agrinovagraphql/server/pkg/tracing"
 )
 
 // BkmSyncService handles bulk upsert of BKM master and detail records.
@@ -23,7 +26,12 @@ func NewBkmSyncService(db *gorm.DB) *BkmSyncService {
 }
 
 // UpsertMasters performs a bulk upsert of BKM master records.
-func (s *BkmSyncService) UpsertMasters(ctx context.Context, inputs []*bkm.BkmMasterUpsertInput) (*bkm.UpsertBkmResult, error) {
+func (s *BkmSyncService) UpsertMasters(ctx context.Context, inputs []*bkm.BkmMasterUpsertInput) (res *bkm.UpsertBkmResult, err error) {
+	ctx, span := tracing.Start(ctx, "BkmSyncService.UpsertMasters", attribute.Int("sync.received", len(inputs)))
+	defer func(start time.Time) {
+		endBkmUpsert(span, SyncKindBkmMaster, len(inputs), res, err, time.Since(start))
+	}(time.Now())
+
 	if len(inputs) == 0 {
 		return &bkm.UpsertBkmResult{Received: 0, Upserted: 0}, nil
 	}
@@ -49,7 +57,7 @@ func (s *BkmSyncService) UpsertMasters(ctx context.Context, inputs []*bkm.BkmMas
 	received := int32(len(inputs))
 	var upserted int32
 
-	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		for _, inp := range inputs {
 			// Compute source_updated_at: prefer updateAtTs, fallback createAtTs
 			var sourceUpdatedAt *time.Time
@@ -124,7 +132,12 @@ func (s *BkmSyncService) UpsertMasters(ctx context.Context, inputs []*bkm.BkmMas
 }
 
 // UpsertDetails performs a bulk upsert of BKM detail records.
-func (s *BkmSyncService) UpsertDetails(ctx context.Context, inputs []*bkm.BkmDetailUpsertInput) (*bkm.UpsertBkmResult, error) {
+func (s *BkmSyncService) UpsertDetails(ctx context.Context, inputs []*bkm.BkmDetailUpsertInput) (res *bkm.UpsertBkmResult, err error) {
+	ctx, span := tracing.Start(ctx, "BkmSyncService.UpsertDetails", attribute.Int("sync.received", len(inputs)))
+	defer func(start time.Time) {
+		endBkmUpsert(span, SyncKindBkmDetail, len(inputs), res, err, time.Since(start))
+	}(time.Now())
+
 	if len(inputs) == 0 {
 		return &bkm.UpsertBkmResult{Received: 0, Upserted: 0}, nil
 	}
@@ -142,7 +155,7 @@ func (s *BkmSyncService) UpsertDetails(ctx context.Context, inputs []*bkm.BkmDet
 	received := int32(len(inputs))
 	var upserted int32
 
-	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		for _, inp := range inputs {
 			// Convert int32 pointers to int pointers
 			var baris, pekerjaan *int
@@ -205,6 +218,18 @@ func (s *BkmSyncService) UpsertDetails(ctx context.Context, inputs []*bkm.BkmDet
 // SQL builders
 // ============================================================================
 
+// endBkmUpsert records the sync metrics of an upsert call and ends its span.
+// The upsert runs in one transaction, so a failure rejects the whole batch.
+func endBkmUpsert(span trace.Span, kind string, received int, res *bkm.UpsertBkmResult, err error, elapsed time.Duration) {
+	outcome := SyncOutcome{Rejected: received}
+	if err == nil && res != nil {
+		upserted := int(res.Upserted)
+		outcome = SyncOutcome{Accepted: upserted, Skipped: max(0, received-upserted)}
+	}
+	RecordSync(kind, outcome, elapsed)
+	tracing.End(span, err)
+}
+
 func buildMasterUpsertSQL() string {
 	cols := []string{
 		"masterid", "periode", "remise", "iddata", "nomor", "tanggal", "jenisbkm", "isborongan",
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Sync kinds recorded by RecordSync.
+const (
+	SyncKindHarvest        = "harvest"
+	SyncKindGateCheck      = "gate_check"
+	SyncKindGateCheckPhoto = "gate_check_photo"
+	SyncKindEmployeeLog    = "employee_log"
+	SyncKindBkmMaster      = "bkm_master"
+	SyncKindBkmDetail      = "bkm_detail"
+)
+
+// SyncOutcome counts the records of one sync batch.
+type SyncOutcome struct {
+	Accepted  int // stored or updated
+	Rejected  int // refused by validation, access checks or errors
+	Skipped   int // received but already up to date
+	Conflicts int // resolved against a newer server copy
+}
+
+var (
+	syncRecords = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "agrinova",
+			Subsystem: "sync",
+			Name:      "records_total",
+			Help:      "Total number of records received from offline and external sync, by result",
+		},
+		[]string{"kind", "result"},
+	)
+
+	syncConflicts = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "agrinova",
+			Subsystem: "sync",
+			Name:      "conflicts_total",
+			Help:      "Total number of synced records that conflicted with a newer server copy",
+		},
+		[]string{"kind"},
+	)
+
+	syncBatchDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "agrinova",
+			Subsystem: "sync",
+			Name:      "batch_duration_seconds",
+			Help:      "Time taken to process one sync batch",
+			Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+		},
+		[]string{"kind"},
+	)
+)
+
+// RecordSync records the outcome of one sync batch of kind.
+func RecordSync(kind string, outcome SyncOutcome, elapsed time.Duration) {
+	syncRecords.WithLabelValues(kind, "accepted").Add(float64(outcome.Accepted))
+	syncRecords.WithLabelValues(kind, "rejected").Add(float64(outcome.Rejected))
+	syncRecords.WithLabelValues(kind, "skipped").Add(float64(outcome.Skipped))
+	syncConflicts.WithLabelValues(kind).Add(float64(outcome.Conflicts))
+	syncBatchDuration.WithLabelValues(kind).Observe(elapsed.Seconds())
+}
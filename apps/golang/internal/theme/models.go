@@ -97,6 +97,7 @@ type Theme struct {
 	Code              string    `gorm:"column:code;type:varchar(120);uniqueIndex;not null" json:"code"`
 	Name              string    `gorm:"column:name;type:varchar(160);not null" json:"name"`
 	Type              string    `gorm:"column:type;type:varchar(20);not null" json:"type"`
+	CompanyID         *string   `gorm:"column:company_id;type:uuid;index" json:"company_id,omitempty"`
 	TokenJSON         JSONMap   `gorm:"column:token_json;type:jsonb;not null" json:"token_json"`
 	AssetManifestJSON JSONMap   `gorm:"column:asset_manifest_json;type:jsonb;not null" json:"asset_manifest_json"`
 	IsActive          bool      `gorm:"column:is_active;not null" json:"is_active"`
@@ -109,21 +110,24 @@ func (Theme) TableName() string {
 }
 
 type ThemeCampaign struct {
-	ID               string     `gorm:"column:id;type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
-	ThemeID          string     `gorm:"column:theme_id;type:uuid;not null;index" json:"theme_id"`
-	CampaignGroupKey string     `gorm:"column:campaign_group_key;type:varchar(180);not null;index" json:"campaign_group_key"`
-	CampaignName     string     `gorm:"column:campaign_name;type:varchar(180);not null;index" json:"campaign_name"`
-	Description      string     `gorm:"column:description;type:text" json:"description"`
-	Enabled          bool       `gorm:"column:enabled;not null" json:"enabled"`
-	StartAt          *time.Time `gorm:"column:start_at;type:timestamptz" json:"start_at,omitempty"`
-	EndAt            *time.Time `gorm:"column:end_at;type:timestamptz" json:"end_at,omitempty"`
-	Priority         int        `gorm:"column:priority;not null" json:"priority"`
-	LightModeEnabled bool       `gorm:"column:light_mode_enabled;not null" json:"light_mode_enabled"`
-	DarkModeEnabled  bool       `gorm:"column:dark_mode_enabled;not null" json:"dark_mode_enabled"`
-	AssetsJSON       JSONMap    `gorm:"column:assets_json;type:jsonb;not null" json:"assets"`
-	UpdatedBy        string     `gorm:"column:updated_by;type:varchar(180);not null" json:"updated_by"`
-	UpdatedAt        time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
-	CreatedAt        time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	ID               string      `gorm:"column:id;type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ThemeID          string      `gorm:"column:theme_id;type:uuid;not null;index" json:"theme_id"`
+	CampaignGroupKey string      `gorm:"column:campaign_group_key;type:varchar(180);not null;index" json:"campaign_group_key"`
+	CampaignName     string      `gorm:"column:campaign_name;type:varchar(180);not null;index" json:"campaign_name"`
+	CompanyID        *string     `gorm:"column:company_id;type:uuid;index" json:"company_id,omitempty"`
+	TargetRoles      StringArray `gorm:"column:target_roles;type:jsonb;not null" json:"target_roles"`
+	TargetPlatforms  StringArray `gorm:"column:target_platforms;type:jsonb;not null" json:"target_platforms"`
+	Description      string      `gorm:"column:description;type:text" json:"description"`
+	Enabled          bool        `gorm:"column:enabled;not null" json:"enabled"`
+	StartAt          *time.Time  `gorm:"column:start_at;type:timestamptz" json:"start_at,omitempty"`
+	EndAt            *time.Time  `gorm:"column:end_at;type:timestamptz" json:"end_at,omitempty"`
+	Priority         int         `gorm:"column:priority;not null" json:"priority"`
+	LightModeEnabled bool        `gorm:"column:light_mode_enabled;not null" json:"light_mode_enabled"`
+	DarkModeEnabled  bool        `gorm:"column:dark_mode_enabled;not null" json:"dark_mode_enabled"`
+	AssetsJSON       JSONMap     `gorm:"column:assets_json;type:jsonb;not null" json:"assets"`
+	UpdatedBy        string      `gorm:"column:updated_by;type:varchar(180);not null" json:"updated_by"`
+	UpdatedAt        time.Time   `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	CreatedAt        time.Time   `gorm:"column:created_at;autoCreateTime" json:"created_at"`
 }
 
 func (ThemeCampaign) TableName() string {
@@ -141,9 +145,23 @@ func (ThemeSettings) TableName() string {
 	return "theme_settings"
 }
 
+// ThemeCompanySettings holds the branding of one company. A nil
+// DefaultThemeID falls back to the global default theme.
+type ThemeCompanySettings struct {
+	CompanyID      string    `gorm:"column:company_id;type:uuid;primaryKey" json:"company_id"`
+	DefaultThemeID *string   `gorm:"column:default_theme_id;type:uuid" json:"default_theme_id"`
+	UpdatedBy      string    `gorm:"column:updated_by;type:varchar(180);not null" json:"updated_by"`
+	UpdatedAt      time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (ThemeCompanySettings) TableName() string {
+	return "theme_company_settings"
+}
+
 type ThemeAuditLog struct {
 	ID          string    `gorm:"column:id;type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
 	ActorUserID string    `gorm:"column:actor_user_id;type:varchar(180);not null" json:"actor_user_id"`
+	CompanyID   *string   `gorm:"column:company_id;type:uuid;index" json:"company_id,omitempty"`
 	Action      string    `gorm:"column:action;type:varchar(80);not null" json:"action"`
 	EntityType  string    `gorm:"column:entity_type;type:varchar(80);not null" json:"entity_type"`
 	EntityID    string    `gorm:"column:entity_id;type:varchar(180);not null" json:"entity_id"`
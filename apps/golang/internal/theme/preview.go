@@ -0,0 +1,295 @@
+package theme
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPreviewHorizonDays = 30
+	maxPreviewHorizonDays     = 366
+)
+
+// Reasons a campaign did or did not win a runtime preview.
+const (
+	PreviewReasonSelected       = "SELECTED"
+	PreviewReasonModeNotAllowed = "SELECTED_MODE_NOT_ALLOWED"
+	PreviewReasonKillSwitch     = "KILL_SWITCH"
+	PreviewReasonDisabled       = "DISABLED"
+	PreviewReasonOutsideWindow  = "OUTSIDE_WINDOW"
+	PreviewReasonTargetMismatch = "TARGET_MISMATCH"
+	PreviewReasonLessSpecific   = "LESS_SPECIFIC"
+	PreviewReasonLowerPriority  = "LOWER_PRIORITY"
+	PreviewReasonOlderUpdate    = "OLDER_UPDATE"
+)
+
+const (
+	CampaignTransitionStart = "START"
+	CampaignTransitionEnd   = "END"
+)
+
+type RuntimePreviewInput struct {
+	Platform  string
+	Mode      string
+	CompanyID string
+	Role      string
+	// At defaults to now.
+	At *time.Time
+	// HorizonDays bounds the transition timeline; zero uses 30 days.
+	HorizonDays int
+}
+
+// CampaignEvaluation explains how one campaign fared for a preview.
+type CampaignEvaluation struct {
+	Campaign    CampaignDTO `json:"campaign"`
+	InWindow    bool        `json:"in_window"`
+	TargetMatch bool        `json:"target_match"`
+	Specificity int         `json:"specificity"`
+	ModeAllowed bool        `json:"mode_allowed"`
+	Selected    bool        `json:"selected"`
+	Reason      string      `json:"reason"`
+}
+
+// CampaignTransition is a campaign start or end and the campaign served
+// right after it.
+type CampaignTransition struct {
+	At           time.Time `json:"at"`
+	Kind         string    `json:"kind"`
+	CampaignID   string    `json:"campaign_id"`
+	CampaignName string    `json:"campaign_name"`
+	// WinnerCampaignID is nil when the base theme is served.
+	WinnerCampaignID   *string `json:"winner_campaign_id,omitempty"`
+	WinnerCampaignName string  `json:"winner_campaign_name,omitempty"`
+	WinnerModeAllowed  bool    `json:"winner_mode_allowed"`
+	// LiveCampaignIDs lists every matching campaign live after the
+	// transition, so overlapping campaigns are visible.
+	LiveCampaignIDs []string `json:"live_campaign_ids"`
+}
+
+type RuntimePreviewPayload struct {
+	At         time.Time            `json:"at"`
+	HorizonEnd time.Time            `json:"horizon_end"`
+	Platform   string               `json:"platform"`
+	Mode       string               `json:"mode"`
+	CompanyID  string               `json:"company_id,omitempty"`
+	Role       string               `json:"role,omitempty"`
+	Runtime    *RuntimeThemePayload `json:"runtime"`
+	Winner     *CampaignEvaluation  `json:"winner,omitempty"`
+	Candidates []CampaignEvaluation `json:"candidates"`
+	Timeline   []CampaignTransition `json:"timeline"`
+}
+
+// PreviewRuntimeTheme resolves the runtime payload a device would receive at
+// input.At, explains the campaign decision and lists upcoming transitions.
+// The timeline ignores the kill switch so schedules can be checked while it
+// is on.
+func (s *Service) PreviewRuntimeTheme(ctx context.Context, scope ManageScope, input RuntimePreviewInput) (*RuntimePreviewPayload, error) {
+	owner, err := scope.resolveOwner(&input.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	companyID := ""
+	if owner != nil {
+		companyID = *owner
+	}
+
+	horizonDays := input.HorizonDays
+	if horizonDays == 0 {
+		horizonDays = defaultPreviewHorizonDays
+	}
+	if horizonDays < 0 || horizonDays > maxPreviewHorizonDays {
+		return nil, fmt.Errorf("horizon_days must be between 1 and %d", maxPreviewHorizonDays)
+	}
+
+	at := time.Now()
+	if input.At != nil {
+		at = *input.At
+	}
+	runtimeCtx := RuntimeThemeContext{
+		Platform:  normalizeRuntimePlatform(input.Platform),
+		Mode:      normalizeRuntimeMode(input.Mode),
+		CompanyID: companyID,
+		Role:      strings.ToUpper(strings.TrimSpace(input.Role)),
+	}
+
+	runtime, err := s.resolveRuntimeThemeAt(ctx, runtimeCtx, at)
+	if err != nil {
+		return nil, err
+	}
+
+	var campaigns []ThemeCampaign
+	query := s.db.WithContext(ctx)
+	if companyID != "" {
+		query = query.Where("(company_id IS NULL OR company_id = ?)", companyID)
+	} else {
+		query = query.Where("company_id IS NULL")
+	}
+	if err := query.Order("priority DESC, updated_at DESC").Find(&campaigns).Error; err != nil {
+		return nil, fmt.Errorf("load preview campaigns: %w", err)
+	}
+
+	horizonEnd := at.AddDate(0, 0, horizonDays)
+	payload := &RuntimePreviewPayload{
+		At:         at,
+		HorizonEnd: horizonEnd,
+		Platform:   runtimeCtx.Platform,
+		Mode:       runtimeCtx.Mode,
+		CompanyID:  companyID,
+		Role:       runtimeCtx.Role,
+		Runtime:    runtime,
+		Candidates: evaluateRuntimeCampaigns(campaigns, runtimeCtx, at, runtime.KillSwitchEnabled),
+		Timeline:   buildCampaignTimeline(campaigns, runtimeCtx, at, horizonEnd),
+	}
+	for i := range payload.Candidates {
+		if payload.Candidates[i].Selected {
+			winner := payload.Candidates[i]
+			payload.Winner = &winner
+			break
+		}
+	}
+	return payload, nil
+}
+
+// campaignInRuntimeWindow mirrors the window filter of the runtime query:
+// a missing start or end leaves that side open.
+func campaignInRuntimeWindow(campaign ThemeCampaign, at time.Time) bool {
+	if campaign.StartAt != nil && campaign.StartAt.After(at) {
+		return false
+	}
+	if campaign.EndAt != nil && campaign.EndAt.Before(at) {
+		return false
+	}
+	return true
+}
+
+// liveRuntimeCampaigns returns the enabled campaigns inside their window.
+func liveRuntimeCampaigns(campaigns []ThemeCampaign, at time.Time) []ThemeCampaign {
+	live := make([]ThemeCampaign, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		if campaign.Enabled && campaignInRuntimeWindow(campaign, at) {
+			live = append(live, campaign)
+		}
+	}
+	return live
+}
+
+// evaluateRuntimeCampaigns explains each campaign against the same selection
+// the runtime resolver uses.
+func evaluateRuntimeCampaigns(campaigns []ThemeCampaign, runtimeCtx RuntimeThemeContext, at time.Time, killSwitch bool) []CampaignEvaluation {
+	companyID := strings.TrimSpace(runtimeCtx.CompanyID)
+	role := strings.ToUpper(strings.TrimSpace(runtimeCtx.Role))
+	platform := normalizeRuntimePlatform(runtimeCtx.Platform)
+	mode := normalizeRuntimeMode(runtimeCtx.Mode)
+
+	var winner *ThemeCampaign
+	winnerScore := -1
+	if !killSwitch {
+		if selected := selectRuntimeCampaign(liveRuntimeCampaigns(campaigns, at), runtimeCtx); selected != nil {
+			winner = selected
+			winnerScore = campaignTargetSpecificity(*selected, companyID, role, platform)
+		}
+	}
+
+	evaluations := make([]CampaignEvaluation, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		score := campaignTargetSpecificity(campaign, companyID, role, platform)
+		evaluation := CampaignEvaluation{
+			Campaign:    toCampaignDTO(campaign, at),
+			InWindow:    campaignInRuntimeWindow(campaign, at),
+			TargetMatch: score >= 0,
+			ModeAllowed: isCampaignModeAllowed(campaign, mode),
+		}
+		if score > 0 {
+			evaluation.Specificity = score
+		}
+
+		switch {
+		case !campaign.Enabled:
+			evaluation.Reason = PreviewReasonDisabled
+		case !evaluation.InWindow:
+			evaluation.Reason = PreviewReasonOutsideWindow
+		case !evaluation.TargetMatch:
+			evaluation.Reason = PreviewReasonTargetMismatch
+		case killSwitch:
+			evaluation.Reason = PreviewReasonKillSwitch
+		case winner != nil && campaign.ID == winner.ID:
+			evaluation.Selected = true
+			evaluation.Reason = PreviewReasonSelected
+			if !evaluation.ModeAllowed {
+				evaluation.Reason = PreviewReasonModeNotAllowed
+			}
+		case score < winnerScore:
+			evaluation.Reason = PreviewReasonLessSpecific
+		case winner != nil && campaign.Priority < winner.Priority:
+			evaluation.Reason = PreviewReasonLowerPriority
+		default:
+			evaluation.Reason = PreviewReasonOlderUpdate
+		}
+		evaluations = append(evaluations, evaluation)
+	}
+	return evaluations
+}
+
+// buildCampaignTimeline lists the starts and ends of matching enabled
+// campaigns between from and until, with the winner after each one. A
+// campaign is still live at its end time, so an end takes effect just after.
+func buildCampaignTimeline(campaigns []ThemeCampaign, runtimeCtx RuntimeThemeContext, from time.Time, until time.Time) []CampaignTransition {
+	companyID := strings.TrimSpace(runtimeCtx.CompanyID)
+	role := strings.ToUpper(strings.TrimSpace(runtimeCtx.Role))
+	platform := normalizeRuntimePlatform(runtimeCtx.Platform)
+	mode := normalizeRuntimeMode(runtimeCtx.Mode)
+
+	matching := make([]ThemeCampaign, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		if campaign.Enabled && campaignTargetSpecificity(campaign, companyID, role, platform) >= 0 {
+			matching = append(matching, campaign)
+		}
+	}
+
+	type timelineEvent struct {
+		transition CampaignTransition
+		effective  time.Time
+	}
+	events := []timelineEvent{}
+	for _, campaign := range matching {
+		if campaign.StartAt != nil && campaign.StartAt.After(from) && !campaign.StartAt.After(until) {
+			events = append(events, timelineEvent{
+				transition: CampaignTransition{At: *campaign.StartAt, Kind: CampaignTransitionStart, CampaignID: campaign.ID, CampaignName: campaign.CampaignName},
+				effective:  *campaign.StartAt,
+			})
+		}
+		if campaign.EndAt != nil && !campaign.EndAt.Before(from) && campaign.EndAt.Before(until) {
+			events = append(events, timelineEvent{
+				transition: CampaignTransition{At: *campaign.EndAt, Kind: CampaignTransitionEnd, CampaignID: campaign.ID, CampaignName: campaign.CampaignName},
+				effective:  campaign.EndAt.Add(time.Nanosecond),
+			})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].effective.Equal(events[j].effective) {
+			return events[i].effective.Before(events[j].effective)
+		}
+		return events[i].transition.CampaignName < events[j].transition.CampaignName
+	})
+
+	timeline := make([]CampaignTransition, 0, len(events))
+	for _, event := range events {
+		transition := event.transition
+		live := liveRuntimeCampaigns(matching, event.effective)
+		transition.LiveCampaignIDs = make([]string, 0, len(live))
+		for _, campaign := range live {
+			transition.LiveCampaignIDs = append(transition.LiveCampaignIDs, campaign.ID)
+		}
+		if winner := selectRuntimeCampaign(live, runtimeCtx); winner != nil {
+			winnerID := winner.ID
+			transition.WinnerCampaignID = &winnerID
+			transition.WinnerCampaignName = winner.CampaignName
+			transition.WinnerModeAllowed = isCampaignModeAllowed(*winner, mode)
+		}
+		timeline = append(timeline, transition)
+	}
+	return timeline
+}
@@ -0,0 +1,88 @@
+package theme
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvaluateRuntimeCampaigns_ExplainsDecision(t *testing.T) {
+	companyA := "company-a"
+	at := time.Date(2026, 3, 20, 8, 0, 0, 0, time.UTC)
+	started := at.Add(-24 * time.Hour)
+	ends := at.Add(24 * time.Hour)
+	future := at.Add(72 * time.Hour)
+
+	campaigns := []ThemeCampaign{
+		{ID: "ramadan", Enabled: true, StartAt: &started, EndAt: &ends, Priority: 50, LightModeEnabled: true},
+		{ID: "harvest", Enabled: true, CompanyID: &companyA, StartAt: &started, EndAt: &ends, Priority: 10},
+		{ID: "low", Enabled: true, CompanyID: &companyA, StartAt: &started, Priority: 1, UpdatedAt: at},
+		{ID: "later", Enabled: true, StartAt: &future},
+		{ID: "off", Enabled: false},
+		{ID: "satpam", Enabled: true, TargetRoles: StringArray{"SATPAM"}},
+	}
+
+	evaluations := evaluateRuntimeCampaigns(campaigns, RuntimeThemeContext{Platform: "mobile", Mode: "dark", CompanyID: companyA, Role: "MANAGER"}, at, false)
+	want := map[string]string{
+		"ramadan": PreviewReasonLessSpecific,
+		"harvest": PreviewReasonModeNotAllowed,
+		"low":     PreviewReasonLowerPriority,
+		"later":   PreviewReasonOutsideWindow,
+		"off":     PreviewReasonDisabled,
+		"satpam":  PreviewReasonTargetMismatch,
+	}
+	for _, evaluation := range evaluations {
+		if evaluation.Reason != want[evaluation.Campaign.ID] {
+			t.Errorf("%s: reason = %q, want %q", evaluation.Campaign.ID, evaluation.Reason, want[evaluation.Campaign.ID])
+		}
+		if evaluation.Selected != (evaluation.Campaign.ID == "harvest") {
+			t.Errorf("%s: selected = %t", evaluation.Campaign.ID, evaluation.Selected)
+		}
+	}
+
+	for _, evaluation := range evaluateRuntimeCampaigns(campaigns, RuntimeThemeContext{CompanyID: companyA}, at, true) {
+		if evaluation.Selected {
+			t.Errorf("%s selected with kill switch on", evaluation.Campaign.ID)
+		}
+	}
+}
+
+func TestBuildCampaignTimeline_ShowsOverlaps(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	ramadanStart := from.Add(24 * time.Hour)
+	ramadanEnd := from.Add(20 * 24 * time.Hour)
+	harvestStart := from.Add(10 * 24 * time.Hour)
+	harvestEnd := from.Add(15 * 24 * time.Hour)
+	beyond := from.Add(90 * 24 * time.Hour)
+
+	campaigns := []ThemeCampaign{
+		{ID: "ramadan", CampaignName: "Ramadan", Enabled: true, StartAt: &ramadanStart, EndAt: &ramadanEnd, Priority: 10},
+		{ID: "harvest", CampaignName: "Harvest Week", Enabled: true, StartAt: &harvestStart, EndAt: &harvestEnd, Priority: 20},
+		{ID: "next-year", CampaignName: "Next Year", Enabled: true, StartAt: &beyond},
+		{ID: "disabled", CampaignName: "Disabled", Enabled: false, StartAt: &harvestStart},
+	}
+
+	timeline := buildCampaignTimeline(campaigns, RuntimeThemeContext{Platform: "web"}, from, from.AddDate(0, 0, 30))
+	type step struct {
+		kind, campaign, winner string
+		live                   int
+	}
+	want := []step{
+		{CampaignTransitionStart, "ramadan", "ramadan", 1},
+		{CampaignTransitionStart, "harvest", "harvest", 2},
+		{CampaignTransitionEnd, "harvest", "ramadan", 1},
+		{CampaignTransitionEnd, "ramadan", "", 0},
+	}
+	if len(timeline) != len(want) {
+		t.Fatalf("buildCampaignTimeline() returned %d transitions, want %d: %+v", len(timeline), len(want), timeline)
+	}
+	for i, transition := range timeline {
+		winner := ""
+		if transition.WinnerCampaignID != nil {
+			winner = *transition.WinnerCampaignID
+		}
+		got := step{transition.Kind, transition.CampaignID, winner, len(transition.LiveCampaignIDs)}
+		if got != want[i] {
+			t.Errorf("transition %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
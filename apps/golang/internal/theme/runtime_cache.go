@@ -0,0 +1,331 @@
+package theme
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RuntimeChangeTheme          = "THEME_UPDATED"
+	RuntimeChangeCampaign       = "CAMPAIGN_UPDATED"
+	RuntimeChangeSettings       = "SETTINGS_UPDATED"
+	RuntimeChangeCampaignWindow = "CAMPAIGN_WINDOW"
+)
+
+// campaignBoundaryCheckInterval is how often WatchCampaignBoundaries checks
+// whether a campaign started or ended.
+const campaignBoundaryCheckInterval = 30 * time.Second
+
+// runtimeCacheMaxEntries caps the cached payloads; the least recently used
+// entry is evicted first.
+const runtimeCacheMaxEntries = 1024
+
+// RuntimeChange tells clients that the runtime theme may have changed and
+// should be fetched again.
+type RuntimeChange struct {
+	Reason string
+	// CompanyID limits the change to one company; nil affects every company.
+	CompanyID *string
+	ChangedAt time.Time
+}
+
+type runtimeCacheEntry struct {
+	key     string
+	payload *RuntimeThemePayload
+	etag    string
+}
+
+// runtimeCache keeps resolved runtime payloads until a mutation or the next
+// campaign start or end.
+type runtimeCache struct {
+	mu sync.Mutex
+
+	entries map[string]*list.Element
+	// recency orders entries from most to least recently used.
+	recency    *list.List
+	maxEntries int
+	// generation changes on every reset so a payload resolved before an
+	// invalidation is not stored after it.
+	generation uint64
+	// validUntil is the next campaign boundary; the zero value means the
+	// boundary has not been computed since the last invalidation.
+	validUntil time.Time
+	// hasBoundary is false when no enabled campaign starts or ends later.
+	hasBoundary bool
+
+	listeners []func(RuntimeChange)
+}
+
+func newRuntimeCache() *runtimeCache {
+	return &runtimeCache{
+		entries:    make(map[string]*list.Element),
+		recency:    list.New(),
+		maxEntries: runtimeCacheMaxEntries,
+	}
+}
+
+// get returns the cached entry for key and the current generation.
+func (c *runtimeCache) get(key string) (runtimeCacheEntry, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return runtimeCacheEntry{}, c.generation, false
+	}
+	c.recency.MoveToFront(element)
+	return element.Value.(runtimeCacheEntry), c.generation, true
+}
+
+// put stores entry unless the cache was reset after generation was read,
+// evicting the least recently used entries beyond maxEntries.
+func (c *runtimeCache) put(entry runtimeCacheEntry, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+	if element, ok := c.entries[entry.key]; ok {
+		element.Value = entry
+		c.recency.MoveToFront(element)
+		return
+	}
+	c.entries[entry.key] = c.recency.PushFront(entry)
+	for c.recency.Len() > c.maxEntries {
+		oldest := c.recency.Back()
+		c.recency.Remove(oldest)
+		delete(c.entries, oldest.Value.(runtimeCacheEntry).key)
+	}
+}
+
+// OnRuntimeChange registers a listener called after every invalidation.
+// Listeners must not block.
+func (s *Service) OnRuntimeChange(listener func(RuntimeChange)) {
+	if listener == nil {
+		return
+	}
+	s.cache.mu.Lock()
+	s.cache.listeners = append(s.cache.listeners, listener)
+	s.cache.mu.Unlock()
+}
+
+// ResolveRuntimeThemeCached returns the runtime payload with its ETag,
+// resolving it only when no cached payload is valid for the context. Unknown
+// roles and companies resolve like an empty role or company, so they share
+// that entry instead of growing the cache.
+func (s *Service) ResolveRuntimeThemeCached(ctx context.Context, runtimeCtx RuntimeThemeContext) (*RuntimeThemePayload, string, error) {
+	if err := s.expireRuntimeCacheAt(ctx, time.Now()); err != nil {
+		return nil, "", err
+	}
+
+	runtimeCtx.CompanyID = strings.TrimSpace(runtimeCtx.CompanyID)
+	runtimeCtx.Role = strings.ToUpper(strings.TrimSpace(runtimeCtx.Role))
+	if !containsTarget(campaignTargetRoleKeys, runtimeCtx.Role) {
+		runtimeCtx.Role = ""
+	}
+
+	entry, generation, ok := s.cache.get(runtimeCacheKey(runtimeCtx))
+	if ok {
+		return entry.payload, entry.etag, nil
+	}
+
+	if runtimeCtx.CompanyID != "" {
+		exists, err := s.runtimeCompanyExists(ctx, runtimeCtx.CompanyID)
+		if err != nil {
+			return nil, "", err
+		}
+		if !exists {
+			runtimeCtx.CompanyID = ""
+			if entry, generation, ok = s.cache.get(runtimeCacheKey(runtimeCtx)); ok {
+				return entry.payload, entry.etag, nil
+			}
+		}
+	}
+
+	payload, err := s.ResolveRuntimeTheme(ctx, runtimeCtx)
+	if err != nil {
+		return nil, "", err
+	}
+	etag, err := runtimePayloadETag(payload)
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.cache.put(runtimeCacheEntry{key: runtimeCacheKey(runtimeCtx), payload: payload, etag: etag}, generation)
+	return payload, etag, nil
+}
+
+// runtimeCompanyExists reports whether companyID names a stored company.
+func (s *Service) runtimeCompanyExists(ctx context.Context, companyID string) (bool, error) {
+	if _, err := uuid.Parse(companyID); err != nil {
+		return false, nil
+	}
+
+	var count int64
+	if err := s.db.WithContext(ctx).Table("companies").Where("id = ?", companyID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("load runtime company: %w", err)
+	}
+	return count > 0, nil
+}
+
+// WatchCampaignBoundaries invalidates the runtime cache and notifies
+// listeners when a campaign starts or ends, until ctx is done.
+func (s *Service) WatchCampaignBoundaries(ctx context.Context) {
+	ticker := time.NewTicker(campaignBoundaryCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.expireRuntimeCacheAt(ctx, now); err != nil {
+				fmt.Printf("theme campaign boundary check failed: %v\n", err)
+			}
+		}
+	}
+}
+
+// DropRuntimeCache drops every cached payload without notifying listeners.
+// It is used when another instance reported the change.
+func (s *Service) DropRuntimeCache() {
+	s.cache.mu.Lock()
+	s.cache.resetLocked()
+	s.cache.mu.Unlock()
+}
+
+// invalidateRuntime drops every cached payload and notifies listeners.
+func (s *Service) invalidateRuntime(reason string, companyID *string) {
+	s.cache.mu.Lock()
+	listeners := s.cache.resetLocked()
+	s.cache.mu.Unlock()
+
+	notifyRuntimeChange(listeners, RuntimeChange{Reason: reason, CompanyID: companyID, ChangedAt: time.Now()})
+}
+
+// expireRuntimeCacheAt invalidates the cache when a campaign boundary has
+// passed and computes the next boundary when it is unknown.
+func (s *Service) expireRuntimeCacheAt(ctx context.Context, now time.Time) error {
+	s.cache.mu.Lock()
+	var expiredListeners []func(RuntimeChange)
+	expired := s.cache.hasBoundary && now.After(s.cache.validUntil)
+	if expired {
+		expiredListeners = s.cache.resetLocked()
+	}
+	validUntil := s.cache.validUntil
+	s.cache.mu.Unlock()
+
+	if expired {
+		notifyRuntimeChange(expiredListeners, RuntimeChange{Reason: RuntimeChangeCampaignWindow, ChangedAt: now})
+	}
+	if !validUntil.IsZero() {
+		return nil
+	}
+
+	var campaigns []ThemeCampaign
+	if err := s.db.WithContext(ctx).
+		Select("start_at", "end_at").
+		Where("enabled = ?", true).
+		Where("(start_at > ? OR end_at >= ?)", now, now).
+		Find(&campaigns).Error; err != nil {
+		return fmt.Errorf("load campaign boundaries: %w", err)
+	}
+
+	next := nextCampaignBoundary(campaigns, now)
+	s.cache.mu.Lock()
+	if s.cache.validUntil.IsZero() {
+		if next != nil {
+			s.cache.validUntil, s.cache.hasBoundary = *next, true
+		} else {
+			// No boundary ahead; keep a far value so the lookup is not repeated.
+			s.cache.validUntil, s.cache.hasBoundary = now.Add(100*365*24*time.Hour), false
+		}
+	}
+	s.cache.mu.Unlock()
+	return nil
+}
+
+// resetLocked clears the cache and returns the listeners to notify. The
+// caller must hold mu.
+func (c *runtimeCache) resetLocked() []func(RuntimeChange) {
+	c.entries = make(map[string]*list.Element)
+	c.recency.Init()
+	c.generation++
+	c.validUntil = time.Time{}
+	c.hasBoundary = false
+	return append([]func(RuntimeChange){}, c.listeners...)
+}
+
+func notifyRuntimeChange(listeners []func(RuntimeChange), change RuntimeChange) {
+	for _, listener := range listeners {
+		listener(change)
+	}
+}
+
+// runtimeChangeCompany returns the company affected by moving a record from
+// one owner to another; a move between owners affects every company.
+func runtimeChangeCompany(before *string, after *string) *string {
+	if !sameCompany(before, after) {
+		return nil
+	}
+	return after
+}
+
+// nextCampaignBoundary returns the earliest start or end after now.
+func nextCampaignBoundary(campaigns []ThemeCampaign, now time.Time) *time.Time {
+	var next *time.Time
+	consider := func(value *time.Time) {
+		if value == nil || !value.After(now) {
+			return
+		}
+		if next == nil || value.Before(*next) {
+			candidate := *value
+			next = &candidate
+		}
+	}
+	for _, campaign := range campaigns {
+		consider(campaign.StartAt)
+		consider(campaign.EndAt)
+	}
+	return next
+}
+
+func runtimeCacheKey(runtimeCtx RuntimeThemeContext) string {
+	return strings.Join([]string{
+		normalizeRuntimePlatform(runtimeCtx.Platform),
+		normalizeRuntimeMode(runtimeCtx.Mode),
+		strings.TrimSpace(runtimeCtx.CompanyID),
+		strings.ToUpper(strings.TrimSpace(runtimeCtx.Role)),
+	}, "|")
+}
+
+// runtimePayloadETag hashes the payload into a strong ETag.
+func runtimePayloadETag(payload *RuntimeThemePayload) (string, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("encode runtime theme: %w", err)
+	}
+	sum := sha256.Sum256(encoded)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// ETagMatches reports whether an If-None-Match header matches etag.
+func ETagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
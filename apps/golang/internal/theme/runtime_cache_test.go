@@ -0,0 +1,126 @@
+package theme
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextCampaignBoundary(t *testing.T) {
+	now := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	soon := now.Add(2 * time.Hour)
+	later := now.Add(48 * time.Hour)
+
+	campaigns := []ThemeCampaign{
+		{ID: "running", StartAt: &past, EndAt: &later},
+		{ID: "upcoming", StartAt: &soon},
+		{ID: "open", StartAt: &past},
+	}
+
+	next := nextCampaignBoundary(campaigns, now)
+	if next == nil || !next.Equal(soon) {
+		t.Fatalf("nextCampaignBoundary() = %v, want %v", next, soon)
+	}
+
+	if next := nextCampaignBoundary([]ThemeCampaign{{ID: "open", StartAt: &past}}, now); next != nil {
+		t.Errorf("nextCampaignBoundary() without future boundary = %v, want nil", next)
+	}
+}
+
+func TestRuntimeCacheKey(t *testing.T) {
+	a := runtimeCacheKey(RuntimeThemeContext{Platform: " WEB ", Mode: "dark", CompanyID: "company-a", Role: "manager"})
+	b := runtimeCacheKey(RuntimeThemeContext{Platform: "web", Mode: "DARK", CompanyID: "company-a ", Role: "MANAGER"})
+	if a != b {
+		t.Errorf("runtimeCacheKey() = %q and %q, want equal keys", a, b)
+	}
+
+	other := runtimeCacheKey(RuntimeThemeContext{Platform: "web", Mode: "dark", CompanyID: "company-b", Role: "MANAGER"})
+	if a == other {
+		t.Errorf("runtimeCacheKey() for different companies = %q, want distinct keys", a)
+	}
+}
+
+func TestRuntimeCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newRuntimeCache()
+	cache.maxEntries = 2
+
+	_, generation, _ := cache.get("a")
+	cache.put(runtimeCacheEntry{key: "a", etag: `"a"`}, generation)
+	cache.put(runtimeCacheEntry{key: "b", etag: `"b"`}, generation)
+	if _, _, ok := cache.get("a"); !ok {
+		t.Fatalf("get(a) missed before the cache was full")
+	}
+	cache.put(runtimeCacheEntry{key: "c", etag: `"c"`}, generation)
+
+	if _, _, ok := cache.get("b"); ok {
+		t.Errorf("get(b) hit, want the least recently used entry evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, _, ok := cache.get(key); !ok {
+			t.Errorf("get(%s) missed, want entry kept", key)
+		}
+	}
+	if cache.recency.Len() != 2 || len(cache.entries) != 2 {
+		t.Errorf("cache holds %d/%d entries, want 2", cache.recency.Len(), len(cache.entries))
+	}
+}
+
+func TestRuntimeCacheDropsResolveStartedBeforeReset(t *testing.T) {
+	cache := newRuntimeCache()
+
+	_, generation, _ := cache.get("web|dark||")
+	cache.mu.Lock()
+	cache.resetLocked()
+	cache.mu.Unlock()
+	cache.put(runtimeCacheEntry{key: "web|dark||", etag: `"stale"`}, generation)
+
+	if _, _, ok := cache.get("web|dark||"); ok {
+		t.Errorf("get() hit a payload resolved before the invalidation")
+	}
+
+	_, generation, _ = cache.get("web|dark||")
+	cache.put(runtimeCacheEntry{key: "web|dark||", etag: `"fresh"`}, generation)
+	if entry, _, ok := cache.get("web|dark||"); !ok || entry.etag != `"fresh"` {
+		t.Errorf("get() = %q, %t; want the fresh payload", entry.etag, ok)
+	}
+}
+
+func TestRuntimePayloadETag(t *testing.T) {
+	payload := &RuntimeThemePayload{Source: "DEFAULT_THEME", AppliedMode: "light"}
+
+	first, err := runtimePayloadETag(payload)
+	if err != nil {
+		t.Fatalf("runtimePayloadETag() error: %v", err)
+	}
+	second, _ := runtimePayloadETag(&RuntimeThemePayload{Source: "DEFAULT_THEME", AppliedMode: "light"})
+	if first != second {
+		t.Errorf("runtimePayloadETag() = %q and %q for equal payloads", first, second)
+	}
+
+	payload.AppliedMode = "dark"
+	changed, _ := runtimePayloadETag(payload)
+	if changed == first {
+		t.Errorf("runtimePayloadETag() unchanged after payload change")
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	etag := `"abc123"`
+	cases := []struct {
+		header string
+		want   bool
+	}{
+		{`"abc123"`, true},
+		{`W/"abc123"`, true},
+		{`"other", "abc123"`, true},
+		{`*`, true},
+		{`"other"`, false},
+		{``, false},
+	}
+
+	for _, tc := range cases {
+		if got := ETagMatches(tc.header, etag); got != tc.want {
+			t.Errorf("ETagMatches(%q) = %t, want %t", tc.header, got, tc.want)
+		}
+	}
+}
@@ -81,6 +81,7 @@ type DashboardQuery struct {
 	SortDirection string
 	Page          int
 	PageSize      int
+	Scope         ManageScope
 }
 
 type CampaignInput struct {
@@ -95,6 +96,11 @@ type CampaignInput struct {
 	LightModeEnabled bool       `json:"light_mode_enabled"`
 	DarkModeEnabled  bool       `json:"dark_mode_enabled"`
 	Assets           JSONMap    `json:"assets"`
+	// CompanyID scopes the campaign to one company; nil targets every company.
+	CompanyID *string `json:"company_id"`
+	// TargetRoles and TargetPlatforms narrow the campaign; empty matches all.
+	TargetRoles     []string `json:"target_roles"`
+	TargetPlatforms []string `json:"target_platforms"`
 }
 
 type ThemeInput struct {
@@ -104,6 +110,7 @@ type ThemeInput struct {
 	IsActive          bool    `json:"is_active"`
 	TokenJSON         JSONMap `json:"token_json"`
 	AssetManifestJSON JSONMap `json:"asset_manifest_json"`
+	CompanyID         *string `json:"company_id"`
 }
 
 type CampaignDTO struct {
@@ -119,19 +126,23 @@ type CampaignDTO struct {
 	LightModeEnabled bool       `json:"light_mode_enabled"`
 	DarkModeEnabled  bool       `json:"dark_mode_enabled"`
 	Assets           JSONMap    `json:"assets"`
+	CompanyID        *string    `json:"company_id,omitempty"`
+	TargetRoles      []string   `json:"target_roles"`
+	TargetPlatforms  []string   `json:"target_platforms"`
 	UpdatedBy        string     `json:"updated_by"`
 	UpdatedAt        time.Time  `json:"updated_at"`
 	Status           string     `json:"status"`
 }
 
 type ThemeDTO struct {
-	ID       string  `json:"id"`
-	Code     string  `json:"code"`
-	Name     string  `json:"name"`
-	Type     string  `json:"type"`
-	IsActive bool    `json:"is_active"`
-	Tokens   JSONMap `json:"token_json"`
-	Assets   JSONMap `json:"asset_manifest_json"`
+	ID        string  `json:"id"`
+	Code      string  `json:"code"`
+	Name      string  `json:"name"`
+	Type      string  `json:"type"`
+	IsActive  bool    `json:"is_active"`
+	CompanyID *string `json:"company_id,omitempty"`
+	Tokens    JSONMap `json:"token_json"`
+	Assets    JSONMap `json:"asset_manifest_json"`
 }
 
 type AuditLogDTO struct {
@@ -145,16 +156,18 @@ type AuditLogDTO struct {
 }
 
 type DashboardPayload struct {
-	Campaigns      []CampaignDTO `json:"campaigns"`
-	Themes         []ThemeDTO    `json:"themes"`
-	Settings       ThemeSettings `json:"settings"`
-	AuditLogs      []AuditLogDTO `json:"audit_logs"`
-	TotalFiltered  int           `json:"total_filtered"`
-	TotalPages     int           `json:"total_pages"`
-	Page           int           `json:"page"`
-	PageSize       int           `json:"page_size"`
-	ActiveCampaign *CampaignDTO  `json:"active_campaign,omitempty"`
-	Stats          struct {
+	Campaigns []CampaignDTO `json:"campaigns"`
+	Themes    []ThemeDTO    `json:"themes"`
+	Settings  ThemeSettings `json:"settings"`
+	// CompanySettings is set when the dashboard is scoped to a company.
+	CompanySettings *ThemeCompanySettings `json:"company_settings,omitempty"`
+	AuditLogs       []AuditLogDTO         `json:"audit_logs"`
+	TotalFiltered   int                   `json:"total_filtered"`
+	TotalPages      int                   `json:"total_pages"`
+	Page            int                   `json:"page"`
+	PageSize        int                   `json:"page_size"`
+	ActiveCampaign  *CampaignDTO          `json:"active_campaign,omitempty"`
+	Stats           struct {
 		Total             int  `json:"total"`
 		Active            int  `json:"active"`
 		Scheduled         int  `json:"scheduled"`
@@ -167,12 +180,15 @@ type DashboardPayload struct {
 }
 
 type RuntimeThemeContext struct {
-	Platform string
-	Mode     string
+	Platform  string
+	Mode      string
+	CompanyID string
+	Role      string
 }
 
 type RuntimeThemePayload struct {
 	Source            string       `json:"source"`
+	Scope             string       `json:"scope"`
 	KillSwitchEnabled bool         `json:"kill_switch_enabled"`
 	AppliedMode       string       `json:"applied_mode"`
 	ModeAllowed       bool         `json:"mode_allowed"`
@@ -184,7 +200,8 @@ type RuntimeThemePayload struct {
 }
 
 type Service struct {
-	db *gorm.DB
+	db    *gorm.DB
+	cache *runtimeCache
 }
 
 type userDisplayRow struct {
@@ -194,7 +211,7 @@ type userDisplayRow struct {
 }
 
 func NewService(db *gorm.DB) *Service {
-	return &Service{db: db}
+	return &Service{db: db, cache: newRuntimeCache()}
 }
 
 func (s *Service) GetDashboard(ctx context.Context, query DashboardQuery) (*DashboardPayload, error) {
@@ -211,7 +228,11 @@ func (s *Service) GetDashboard(ctx context.Context, query DashboardQuery) (*Dash
 	}
 
 	var campaigns []ThemeCampaign
-	if err := s.db.WithContext(ctx).Order("updated_at DESC").Find(&campaigns).Error; err != nil {
+	campaignQuery := s.db.WithContext(ctx).Order("updated_at DESC")
+	if !query.Scope.IsGlobal() {
+		campaignQuery = campaignQuery.Where("company_id = ?", query.Scope.CompanyID)
+	}
+	if err := campaignQuery.Find(&campaigns).Error; err != nil {
 		return nil, fmt.Errorf("load campaigns: %w", err)
 	}
 
@@ -249,7 +270,7 @@ func (s *Service) GetDashboard(ctx context.Context, query DashboardQuery) (*Dash
 	}
 
 	var themes []Theme
-	if err := s.db.WithContext(ctx).Order("name ASC").Find(&themes).Error; err != nil {
+	if err := scopeThemeQuery(s.db.WithContext(ctx), query.Scope).Order("name ASC").Find(&themes).Error; err != nil {
 		return nil, fmt.Errorf("load themes: %w", err)
 	}
 
@@ -259,7 +280,11 @@ func (s *Service) GetDashboard(ctx context.Context, query DashboardQuery) (*Dash
 	}
 
 	var auditLogs []ThemeAuditLog
-	if err := s.db.WithContext(ctx).Order("created_at DESC").Limit(25).Find(&auditLogs).Error; err != nil {
+	auditQuery := s.db.WithContext(ctx).Order("created_at DESC").Limit(25)
+	if !query.Scope.IsGlobal() {
+		auditQuery = auditQuery.Where("company_id = ?", query.Scope.CompanyID)
+	}
+	if err := auditQuery.Find(&auditLogs).Error; err != nil {
 		return nil, fmt.Errorf("load audit logs: %w", err)
 	}
 
@@ -279,6 +304,12 @@ func (s *Service) GetDashboard(ctx context.Context, query DashboardQuery) (*Dash
 		PageSize:      pageSize,
 		Settings:      settings,
 	}
+	if !query.Scope.IsGlobal() {
+		payload.CompanySettings, err = s.GetCompanySettings(ctx, query.Scope.CompanyID)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	for _, theme := range themes {
 		payload.Themes = append(payload.Themes, toThemeDTO(theme))
@@ -400,9 +431,9 @@ func sanitizeUserLookupIDs(ids []string) []string {
 	return result
 }
 
-func (s *Service) GetThemes(ctx context.Context) ([]ThemeDTO, error) {
+func (s *Service) GetThemes(ctx context.Context, scope ManageScope) ([]ThemeDTO, error) {
 	var themes []Theme
-	if err := s.db.WithContext(ctx).
+	if err := scopeThemeQuery(s.db.WithContext(ctx), scope).
 		Order("CASE WHEN type = 'base' THEN 0 ELSE 1 END, name ASC").
 		Find(&themes).Error; err != nil {
 		return nil, fmt.Errorf("load themes: %w", err)
@@ -415,15 +446,20 @@ func (s *Service) GetThemes(ctx context.Context) ([]ThemeDTO, error) {
 	return result, nil
 }
 
-func (s *Service) CreateTheme(ctx context.Context, actor string, input ThemeInput) (*ThemeDTO, error) {
+func (s *Service) CreateTheme(ctx context.Context, actor string, scope ManageScope, input ThemeInput) (*ThemeDTO, error) {
 	if err := validateThemeInput(input); err != nil {
 		return nil, err
 	}
+	owner, err := scope.resolveOwner(input.CompanyID)
+	if err != nil {
+		return nil, err
+	}
 
 	createdTheme := Theme{
 		Code:              strings.TrimSpace(input.Code),
 		Name:              strings.TrimSpace(input.Name),
 		Type:              strings.ToLower(strings.TrimSpace(input.Type)),
+		CompanyID:         owner,
 		TokenJSON:         normalizeThemeTokens(input.TokenJSON),
 		AssetManifestJSON: normalizeThemeAssetManifest(input.AssetManifestJSON),
 		IsActive:          input.IsActive,
@@ -445,7 +481,7 @@ func (s *Service) CreateTheme(ctx context.Context, actor string, input ThemeInpu
 		return nil, fmt.Errorf("create theme: %w", err)
 	}
 
-	if err := s.appendAuditTx(tx, actor, "CREATE_THEME", "themes", createdTheme.ID, JSONMap{}, JSONMap{
+	if err := s.appendAuditTx(tx, actor, createdTheme.CompanyID, "CREATE_THEME", "themes", createdTheme.ID, JSONMap{}, JSONMap{
 		"code":      createdTheme.Code,
 		"name":      createdTheme.Name,
 		"type":      createdTheme.Type,
@@ -458,18 +494,23 @@ func (s *Service) CreateTheme(ctx context.Context, actor string, input ThemeInpu
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
+	s.invalidateRuntime(RuntimeChangeTheme, createdTheme.CompanyID)
 
 	dto := toThemeDTO(createdTheme)
 	return &dto, nil
 }
 
-func (s *Service) UpdateTheme(ctx context.Context, actor string, themeID string, input ThemeInput) (*ThemeDTO, error) {
+func (s *Service) UpdateTheme(ctx context.Context, actor string, scope ManageScope, themeID string, input ThemeInput) (*ThemeDTO, error) {
 	if strings.TrimSpace(themeID) == "" {
 		return nil, fmt.Errorf("theme id is required")
 	}
 	if err := validateThemeInput(input); err != nil {
 		return nil, err
 	}
+	owner, err := scope.resolveOwner(input.CompanyID)
+	if err != nil {
+		return nil, err
+	}
 
 	tx := s.db.WithContext(ctx).Begin()
 	if tx.Error != nil {
@@ -487,15 +528,22 @@ func (s *Service) UpdateTheme(ctx context.Context, actor string, themeID string,
 		tx.Rollback()
 		return nil, fmt.Errorf("theme not found: %w", err)
 	}
-
-	settings, err := s.getSettingsTx(tx)
-	if err != nil {
+	if !scope.canManage(existingTheme.CompanyID) {
 		tx.Rollback()
-		return nil, err
+		return nil, fmt.Errorf("theme not found")
 	}
-	if !input.IsActive && settings.DefaultThemeID == existingTheme.ID {
-		tx.Rollback()
-		return nil, fmt.Errorf("default theme cannot be deactivated")
+
+	if !input.IsActive {
+		if err := s.ensureThemeNotDefaultTx(tx, existingTheme.ID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if !sameCompany(owner, existingTheme.CompanyID) {
+		if err := s.ensureThemeNotDefaultTx(tx, existingTheme.ID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
 	}
 
 	before := JSONMap{
@@ -508,6 +556,8 @@ func (s *Service) UpdateTheme(ctx context.Context, actor string, themeID string,
 	existingTheme.Code = strings.TrimSpace(input.Code)
 	existingTheme.Name = strings.TrimSpace(input.Name)
 	existingTheme.Type = strings.ToLower(strings.TrimSpace(input.Type))
+	previousOwner := existingTheme.CompanyID
+	existingTheme.CompanyID = owner
 	existingTheme.TokenJSON = normalizeThemeTokens(input.TokenJSON)
 	existingTheme.AssetManifestJSON = normalizeThemeAssetManifest(input.AssetManifestJSON)
 	existingTheme.IsActive = input.IsActive
@@ -518,7 +568,7 @@ func (s *Service) UpdateTheme(ctx context.Context, actor string, themeID string,
 		return nil, fmt.Errorf("update theme: %w", err)
 	}
 
-	if err := s.appendAuditTx(tx, actor, "UPDATE_THEME", "themes", existingTheme.ID, before, JSONMap{
+	if err := s.appendAuditTx(tx, actor, existingTheme.CompanyID, "UPDATE_THEME", "themes", existingTheme.ID, before, JSONMap{
 		"code":      existingTheme.Code,
 		"name":      existingTheme.Name,
 		"type":      existingTheme.Type,
@@ -531,12 +581,13 @@ func (s *Service) UpdateTheme(ctx context.Context, actor string, themeID string,
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
+	s.invalidateRuntime(RuntimeChangeTheme, runtimeChangeCompany(previousOwner, existingTheme.CompanyID))
 
 	dto := toThemeDTO(existingTheme)
 	return &dto, nil
 }
 
-func (s *Service) ToggleThemeActive(ctx context.Context, actor string, themeID string) (*ThemeDTO, error) {
+func (s *Service) ToggleThemeActive(ctx context.Context, actor string, scope ManageScope, themeID string) (*ThemeDTO, error) {
 	if strings.TrimSpace(themeID) == "" {
 		return nil, fmt.Errorf("theme id is required")
 	}
@@ -558,16 +609,17 @@ func (s *Service) ToggleThemeActive(ctx context.Context, actor string, themeID s
 		return nil, fmt.Errorf("theme not found: %w", err)
 	}
 
-	settings, err := s.getSettingsTx(tx)
-	if err != nil {
+	if !scope.canManage(existingTheme.CompanyID) {
 		tx.Rollback()
-		return nil, err
+		return nil, fmt.Errorf("theme not found")
 	}
 
 	nextActive := !existingTheme.IsActive
-	if !nextActive && settings.DefaultThemeID == existingTheme.ID {
-		tx.Rollback()
-		return nil, fmt.Errorf("default theme cannot be deactivated")
+	if !nextActive {
+		if err := s.ensureThemeNotDefaultTx(tx, existingTheme.ID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
 	}
 
 	beforeActive := existingTheme.IsActive
@@ -585,6 +637,7 @@ func (s *Service) ToggleThemeActive(ctx context.Context, actor string, themeID s
 	if err := s.appendAuditTx(
 		tx,
 		actor,
+		existingTheme.CompanyID,
 		action,
 		"themes",
 		existingTheme.ID,
@@ -598,6 +651,7 @@ func (s *Service) ToggleThemeActive(ctx context.Context, actor string, themeID s
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
+	s.invalidateRuntime(RuntimeChangeTheme, existingTheme.CompanyID)
 
 	dto := toThemeDTO(existingTheme)
 	return &dto, nil
@@ -629,6 +683,10 @@ func (s *Service) SetDefaultTheme(ctx context.Context, actor string, themeID str
 		tx.Rollback()
 		return nil, fmt.Errorf("default theme must be active")
 	}
+	if selectedTheme.CompanyID != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("global default theme cannot belong to a company")
+	}
 
 	settings, err := s.getSettingsTx(tx)
 	if err != nil {
@@ -644,7 +702,7 @@ func (s *Service) SetDefaultTheme(ctx context.Context, actor string, themeID str
 		return nil, fmt.Errorf("set default theme: %w", err)
 	}
 
-	if err := s.appendAuditTx(tx, actor, "SET_DEFAULT_THEME", "theme_settings.default_theme_id", "1", JSONMap{
+	if err := s.appendAuditTx(tx, actor, nil, "SET_DEFAULT_THEME", "theme_settings.default_theme_id", "1", JSONMap{
 		"default_theme_id": before,
 	}, JSONMap{
 		"default_theme_id": settings.DefaultThemeID,
@@ -656,20 +714,25 @@ func (s *Service) SetDefaultTheme(ctx context.Context, actor string, themeID str
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
+	s.invalidateRuntime(RuntimeChangeSettings, nil)
 
 	return &settings, nil
 }
 
-func (s *Service) CreateCampaign(ctx context.Context, actor string, input CampaignInput) (*CampaignDTO, error) {
+func (s *Service) CreateCampaign(ctx context.Context, actor string, scope ManageScope, input CampaignInput) (*CampaignDTO, error) {
 	if err := validateCampaignInput(input); err != nil {
 		return nil, err
 	}
-	if _, err := s.findThemeByID(ctx, strings.TrimSpace(input.ThemeID)); err != nil {
-		return nil, fmt.Errorf("invalid theme_id: %w", err)
+	owner, targetRoles, targetPlatforms, err := s.resolveCampaignTargets(ctx, scope, input)
+	if err != nil {
+		return nil, err
 	}
 
 	campaign := ThemeCampaign{
 		ThemeID:          strings.TrimSpace(input.ThemeID),
+		CompanyID:        owner,
+		TargetRoles:      targetRoles,
+		TargetPlatforms:  targetPlatforms,
 		CampaignGroupKey: strings.TrimSpace(input.CampaignGroupKey),
 		CampaignName:     strings.TrimSpace(input.CampaignName),
 		Description:      strings.TrimSpace(input.Description),
@@ -699,7 +762,7 @@ func (s *Service) CreateCampaign(ctx context.Context, actor string, input Campai
 		return nil, fmt.Errorf("create campaign: %w", err)
 	}
 
-	if err := s.appendAuditTx(tx, actor, "CREATE_CAMPAIGN", "theme_campaigns", campaign.ID, JSONMap{}, JSONMap{
+	if err := s.appendAuditTx(tx, actor, campaign.CompanyID, "CREATE_CAMPAIGN", "theme_campaigns", campaign.ID, JSONMap{}, JSONMap{
 		"campaign_name":      campaign.CampaignName,
 		"campaign_group_key": campaign.CampaignGroupKey,
 		"enabled":            campaign.Enabled,
@@ -712,20 +775,22 @@ func (s *Service) CreateCampaign(ctx context.Context, actor string, input Campai
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
+	s.invalidateRuntime(RuntimeChangeCampaign, campaign.CompanyID)
 
 	dto := toCampaignDTO(campaign, time.Now())
 	return &dto, nil
 }
 
-func (s *Service) UpdateCampaign(ctx context.Context, actor string, campaignID string, input CampaignInput) (*CampaignDTO, error) {
+func (s *Service) UpdateCampaign(ctx context.Context, actor string, scope ManageScope, campaignID string, input CampaignInput) (*CampaignDTO, error) {
 	if campaignID == "" {
 		return nil, fmt.Errorf("campaign id is required")
 	}
 	if err := validateCampaignInput(input); err != nil {
 		return nil, err
 	}
-	if _, err := s.findThemeByID(ctx, strings.TrimSpace(input.ThemeID)); err != nil {
-		return nil, fmt.Errorf("invalid theme_id: %w", err)
+	owner, targetRoles, targetPlatforms, err := s.resolveCampaignTargets(ctx, scope, input)
+	if err != nil {
+		return nil, err
 	}
 
 	tx := s.db.WithContext(ctx).Begin()
@@ -744,6 +809,10 @@ func (s *Service) UpdateCampaign(ctx context.Context, actor string, campaignID s
 		tx.Rollback()
 		return nil, fmt.Errorf("campaign not found: %w", err)
 	}
+	if !scope.canManage(campaign.CompanyID) {
+		tx.Rollback()
+		return nil, fmt.Errorf("campaign not found")
+	}
 	before := JSONMap{
 		"campaign_name":      campaign.CampaignName,
 		"campaign_group_key": campaign.CampaignGroupKey,
@@ -751,7 +820,11 @@ func (s *Service) UpdateCampaign(ctx context.Context, actor string, campaignID s
 		"priority":           campaign.Priority,
 	}
 
+	previousOwner := campaign.CompanyID
 	campaign.ThemeID = strings.TrimSpace(input.ThemeID)
+	campaign.CompanyID = owner
+	campaign.TargetRoles = targetRoles
+	campaign.TargetPlatforms = targetPlatforms
 	campaign.CampaignGroupKey = strings.TrimSpace(input.CampaignGroupKey)
 	campaign.CampaignName = strings.TrimSpace(input.CampaignName)
 	campaign.Description = strings.TrimSpace(input.Description)
@@ -770,7 +843,7 @@ func (s *Service) UpdateCampaign(ctx context.Context, actor string, campaignID s
 		return nil, fmt.Errorf("update campaign: %w", err)
 	}
 
-	if err := s.appendAuditTx(tx, actor, "UPDATE_CAMPAIGN", "theme_campaigns", campaign.ID, before, JSONMap{
+	if err := s.appendAuditTx(tx, actor, campaign.CompanyID, "UPDATE_CAMPAIGN", "theme_campaigns", campaign.ID, before, JSONMap{
 		"campaign_name":      campaign.CampaignName,
 		"campaign_group_key": campaign.CampaignGroupKey,
 		"enabled":            campaign.Enabled,
@@ -783,12 +856,13 @@ func (s *Service) UpdateCampaign(ctx context.Context, actor string, campaignID s
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
+	s.invalidateRuntime(RuntimeChangeCampaign, runtimeChangeCompany(previousOwner, campaign.CompanyID))
 
 	dto := toCampaignDTO(campaign, time.Now())
 	return &dto, nil
 }
 
-func (s *Service) ToggleCampaignEnabled(ctx context.Context, actor string, campaignID string) (*CampaignDTO, error) {
+func (s *Service) ToggleCampaignEnabled(ctx context.Context, actor string, scope ManageScope, campaignID string) (*CampaignDTO, error) {
 	tx := s.db.WithContext(ctx).Begin()
 	if tx.Error != nil {
 		return nil, tx.Error
@@ -799,6 +873,10 @@ func (s *Service) ToggleCampaignEnabled(ctx context.Context, actor string, campa
 		tx.Rollback()
 		return nil, fmt.Errorf("campaign not found: %w", err)
 	}
+	if !scope.canManage(campaign.CompanyID) {
+		tx.Rollback()
+		return nil, fmt.Errorf("campaign not found")
+	}
 
 	beforeEnabled := campaign.Enabled
 	campaign.Enabled = !campaign.Enabled
@@ -814,7 +892,7 @@ func (s *Service) ToggleCampaignEnabled(ctx context.Context, actor string, campa
 	if campaign.Enabled {
 		action = "ENABLE_CAMPAIGN"
 	}
-	if err := s.appendAuditTx(tx, actor, action, "theme_campaigns", campaign.ID, JSONMap{"enabled": beforeEnabled}, JSONMap{"enabled": campaign.Enabled}); err != nil {
+	if err := s.appendAuditTx(tx, actor, campaign.CompanyID, action, "theme_campaigns", campaign.ID, JSONMap{"enabled": beforeEnabled}, JSONMap{"enabled": campaign.Enabled}); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -822,12 +900,13 @@ func (s *Service) ToggleCampaignEnabled(ctx context.Context, actor string, campa
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
+	s.invalidateRuntime(RuntimeChangeCampaign, campaign.CompanyID)
 
 	dto := toCampaignDTO(campaign, time.Now())
 	return &dto, nil
 }
 
-func (s *Service) DuplicateCampaign(ctx context.Context, actor string, campaignID string) (*CampaignDTO, error) {
+func (s *Service) DuplicateCampaign(ctx context.Context, actor string, scope ManageScope, campaignID string) (*CampaignDTO, error) {
 	tx := s.db.WithContext(ctx).Begin()
 	if tx.Error != nil {
 		return nil, tx.Error
@@ -838,6 +917,10 @@ func (s *Service) DuplicateCampaign(ctx context.Context, actor string, campaignI
 		tx.Rollback()
 		return nil, fmt.Errorf("campaign not found: %w", err)
 	}
+	if !scope.canManage(source.CompanyID) {
+		tx.Rollback()
+		return nil, fmt.Errorf("campaign not found")
+	}
 
 	duplicated := source
 	duplicated.ID = ""
@@ -853,7 +936,7 @@ func (s *Service) DuplicateCampaign(ctx context.Context, actor string, campaignI
 		return nil, fmt.Errorf("duplicate campaign: %w", err)
 	}
 
-	if err := s.appendAuditTx(tx, actor, "DUPLICATE_CAMPAIGN", "theme_campaigns", duplicated.ID, JSONMap{"source_id": source.ID}, JSONMap{
+	if err := s.appendAuditTx(tx, actor, duplicated.CompanyID, "DUPLICATE_CAMPAIGN", "theme_campaigns", duplicated.ID, JSONMap{"source_id": source.ID}, JSONMap{
 		"campaign_group_key": duplicated.CampaignGroupKey,
 		"enabled":            duplicated.Enabled,
 	}); err != nil {
@@ -864,12 +947,13 @@ func (s *Service) DuplicateCampaign(ctx context.Context, actor string, campaignI
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
+	s.invalidateRuntime(RuntimeChangeCampaign, duplicated.CompanyID)
 
 	dto := toCampaignDTO(duplicated, time.Now())
 	return &dto, nil
 }
 
-func (s *Service) DeleteCampaign(ctx context.Context, actor string, campaignID string) error {
+func (s *Service) DeleteCampaign(ctx context.Context, actor string, scope ManageScope, campaignID string) error {
 	tx := s.db.WithContext(ctx).Begin()
 	if tx.Error != nil {
 		return tx.Error
@@ -880,13 +964,17 @@ func (s *Service) DeleteCampaign(ctx context.Context, actor string, campaignID s
 		tx.Rollback()
 		return fmt.Errorf("campaign not found: %w", err)
 	}
+	if !scope.canManage(campaign.CompanyID) {
+		tx.Rollback()
+		return fmt.Errorf("campaign not found")
+	}
 
 	if err := tx.Delete(&ThemeCampaign{}, "id = ?", campaignID).Error; err != nil {
 		tx.Rollback()
 		return fmt.Errorf("delete campaign: %w", err)
 	}
 
-	if err := s.appendAuditTx(tx, actor, "DELETE_CAMPAIGN", "theme_campaigns", campaign.ID, JSONMap{
+	if err := s.appendAuditTx(tx, actor, campaign.CompanyID, "DELETE_CAMPAIGN", "theme_campaigns", campaign.ID, JSONMap{
 		"campaign_name":      campaign.CampaignName,
 		"campaign_group_key": campaign.CampaignGroupKey,
 		"enabled":            campaign.Enabled,
@@ -899,6 +987,7 @@ func (s *Service) DeleteCampaign(ctx context.Context, actor string, campaignID s
 	if err := tx.Commit().Error; err != nil {
 		return err
 	}
+	s.invalidateRuntime(RuntimeChangeCampaign, campaign.CompanyID)
 	return nil
 }
 
@@ -926,7 +1015,7 @@ func (s *Service) SetGlobalKillSwitch(ctx context.Context, actor string, enabled
 	if enabled {
 		action = "ENABLE_GLOBAL_KILL_SWITCH"
 	}
-	if err := s.appendAuditTx(tx, actor, action, "theme_settings.global_kill_switch", "1", JSONMap{"value": before}, JSONMap{"value": enabled}); err != nil {
+	if err := s.appendAuditTx(tx, actor, nil, action, "theme_settings.global_kill_switch", "1", JSONMap{"value": before}, JSONMap{"value": enabled}); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -934,16 +1023,28 @@ func (s *Service) SetGlobalKillSwitch(ctx context.Context, actor string, enabled
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
+	s.invalidateRuntime(RuntimeChangeSettings, nil)
 	return &settings, nil
 }
 
+// ResolveRuntimeTheme returns the theme a client should render. The company
+// default theme replaces the global default as base, and the most specific
+// live campaign for the company, role and platform is applied on top. The
+// global kill switch falls every company back to the global default theme.
 func (s *Service) ResolveRuntimeTheme(ctx context.Context, runtimeCtx RuntimeThemeContext) (*RuntimeThemePayload, error) {
+	return s.resolveRuntimeThemeAt(ctx, runtimeCtx, time.Now())
+}
+
+// resolveRuntimeThemeAt resolves the runtime theme as it will be served at
+// the given time.
+func (s *Service) resolveRuntimeThemeAt(ctx context.Context, runtimeCtx RuntimeThemeContext, now time.Time) (*RuntimeThemePayload, error) {
 	settings, err := s.getSettings(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	theme, err := s.findThemeByID(ctx, settings.DefaultThemeID)
+	companyID := strings.TrimSpace(runtimeCtx.CompanyID)
+	theme, scope, err := s.resolveBaseTheme(ctx, settings, companyID)
 	if err != nil {
 		return nil, err
 	}
@@ -956,21 +1057,14 @@ func (s *Service) ResolveRuntimeTheme(ctx context.Context, runtimeCtx RuntimeThe
 
 	payload := &RuntimeThemePayload{
 		Source:            "BASE_THEME",
+		Scope:             scope,
 		KillSwitchEnabled: settings.GlobalKillSwitch,
 		AppliedMode:       mode,
 		ModeAllowed:       true,
-		Theme: ThemeDTO{
-			ID:       theme.ID,
-			Code:     theme.Code,
-			Name:     theme.Name,
-			Type:     theme.Type,
-			IsActive: theme.IsActive,
-			Tokens:   cloneJSONMap(baseThemeTokens),
-			Assets:   cloneJSONMap(baseThemeAssets),
-		},
-		Token:  cloneJSONMap(baseTokens),
-		Assets: cloneJSONMap(baseAssets),
-		AppUI:  cloneJSONMap(baseAppUI),
+		Theme:             toRuntimeThemeDTO(*theme, baseThemeTokens, baseThemeAssets),
+		Token:             cloneJSONMap(baseTokens),
+		Assets:            cloneJSONMap(baseAssets),
+		AppUI:             cloneJSONMap(baseAppUI),
 	}
 
 	if settings.GlobalKillSwitch {
@@ -979,20 +1073,27 @@ func (s *Service) ResolveRuntimeTheme(ctx context.Context, runtimeCtx RuntimeThe
 	}
 
 	platform := normalizeRuntimePlatform(runtimeCtx.Platform)
-	now := time.Now()
 
-	var candidate ThemeCampaign
-	err = s.db.WithContext(ctx).
+	var candidates []ThemeCampaign
+	query := s.db.WithContext(ctx).
 		Where("enabled = ?", true).
 		Where("(start_at IS NULL OR start_at <= ?)", now).
-		Where("(end_at IS NULL OR end_at >= ?)", now).
-		Order("priority DESC, updated_at DESC").
-		Limit(1).
-		Find(&candidate).Error
-	if err != nil {
+		Where("(end_at IS NULL OR end_at >= ?)", now)
+	if companyID != "" {
+		query = query.Where("(company_id IS NULL OR company_id = ?)", companyID)
+	} else {
+		query = query.Where("company_id IS NULL")
+	}
+	if err := query.Order("priority DESC, updated_at DESC").Find(&candidates).Error; err != nil {
 		return nil, fmt.Errorf("load runtime campaign candidates: %w", err)
 	}
-	if candidate.ID == "" {
+
+	candidate := selectRuntimeCampaign(candidates, RuntimeThemeContext{
+		Platform:  platform,
+		CompanyID: companyID,
+		Role:      runtimeCtx.Role,
+	})
+	if candidate == nil {
 		return payload, nil
 	}
 
@@ -1001,8 +1102,8 @@ func (s *Service) ResolveRuntimeTheme(ctx context.Context, runtimeCtx RuntimeThe
 		return payload, nil
 	}
 
-	campaignDTO := toCampaignDTO(candidate, now)
-	if !isCampaignModeAllowed(candidate, mode) {
+	campaignDTO := toCampaignDTO(*candidate, now)
+	if !isCampaignModeAllowed(*candidate, mode) {
 		payload.Source = "MODE_FALLBACK_BASE"
 		payload.ModeAllowed = false
 		payload.Campaign = &campaignDTO
@@ -1017,16 +1118,11 @@ func (s *Service) ResolveRuntimeTheme(ctx context.Context, runtimeCtx RuntimeThe
 	assets := mergeThemeAssetsWithCampaign(selectedThemeAssets, platformAssets)
 
 	payload.Source = "ACTIVE_CAMPAIGN"
-	payload.ModeAllowed = true
-	payload.Theme = ThemeDTO{
-		ID:       resolvedTheme.ID,
-		Code:     resolvedTheme.Code,
-		Name:     resolvedTheme.Name,
-		Type:     resolvedTheme.Type,
-		IsActive: resolvedTheme.IsActive,
-		Tokens:   cloneJSONMap(resolvedThemeTokens),
-		Assets:   cloneJSONMap(resolvedThemeAssets),
+	if candidate.CompanyID != nil {
+		payload.Scope = RuntimeScopeCompany
 	}
+	payload.ModeAllowed = true
+	payload.Theme = toRuntimeThemeDTO(*resolvedTheme, resolvedThemeTokens, resolvedThemeAssets)
 	payload.Campaign = &campaignDTO
 	payload.Token = cloneJSONMap(selectedThemeTokens)
 	payload.Assets = assets
@@ -1043,6 +1139,103 @@ func (s *Service) findThemeByID(ctx context.Context, themeID string) (*Theme, er
 	return &theme, nil
 }
 
+// ReferencedUploadPaths returns the upload paths ("/uploads/...") that any
+// theme or campaign still points at, so asset retention never deletes them.
+func (s *Service) ReferencedUploadPaths(ctx context.Context) (map[string]struct{}, error) {
+	var manifests []JSONMap
+	if err := s.db.WithContext(ctx).Model(&Theme{}).Pluck("asset_manifest_json", &manifests).Error; err != nil {
+		return nil, fmt.Errorf("failed to load theme assets: %w", err)
+	}
+	var campaignAssets []JSONMap
+	if err := s.db.WithContext(ctx).Model(&ThemeCampaign{}).Pluck("assets_json", &campaignAssets).Error; err != nil {
+		return nil, fmt.Errorf("failed to load campaign assets: %w", err)
+	}
+
+	paths := make(map[string]struct{})
+	for _, manifest := range append(manifests, campaignAssets...) {
+		collectUploadPaths(manifest, paths)
+	}
+	return paths, nil
+}
+
+// collectUploadPaths adds every upload path found in a decoded JSON value.
+func collectUploadPaths(value interface{}, paths map[string]struct{}) {
+	switch typed := value.(type) {
+	case JSONMap:
+		for _, nested := range typed {
+			collectUploadPaths(nested, paths)
+		}
+	case map[string]interface{}:
+		for _, nested := range typed {
+			collectUploadPaths(nested, paths)
+		}
+	case []interface{}:
+		for _, nested := range typed {
+			collectUploadPaths(nested, paths)
+		}
+	case string:
+		if normalized := normalizeThemeAssetReference(typed); strings.HasPrefix(normalized, "/uploads/") {
+			paths[normalized] = struct{}{}
+		}
+	}
+}
+
+// resolveCampaignTargets validates the owner, theme and targeting of a
+// campaign input for the managing scope.
+func (s *Service) resolveCampaignTargets(ctx context.Context, scope ManageScope, input CampaignInput) (*string, StringArray, StringArray, error) {
+	owner, err := scope.resolveOwner(input.CompanyID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	selectedTheme, err := s.findThemeByID(ctx, strings.TrimSpace(input.ThemeID))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid theme_id: %w", err)
+	}
+	if !canUseTheme(selectedTheme.CompanyID, owner) {
+		return nil, nil, nil, fmt.Errorf("invalid theme_id: theme belongs to another company")
+	}
+
+	targetRoles, err := normalizeCampaignTargets(input.TargetRoles, campaignTargetRoleKeys, "target_roles", true)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	targetPlatforms, err := normalizeCampaignTargets(input.TargetPlatforms, campaignTargetPlatformKeys, "target_platforms", false)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return owner, targetRoles, targetPlatforms, nil
+}
+
+// ensureThemeNotDefaultTx rejects changes that would break the global or a
+// company default theme.
+func (s *Service) ensureThemeNotDefaultTx(tx *gorm.DB, themeID string) error {
+	settings, err := s.getSettingsTx(tx)
+	if err != nil {
+		return err
+	}
+	if settings.DefaultThemeID == themeID {
+		return fmt.Errorf("default theme cannot be deactivated")
+	}
+
+	isCompanyDefault, err := isCompanyDefaultThemeTx(tx, themeID)
+	if err != nil {
+		return err
+	}
+	if isCompanyDefault {
+		return fmt.Errorf("company default theme cannot be deactivated or reassigned")
+	}
+	return nil
+}
+
+// scopeThemeQuery limits company scopes to global themes and their own.
+func scopeThemeQuery(query *gorm.DB, scope ManageScope) *gorm.DB {
+	if scope.IsGlobal() {
+		return query
+	}
+	return query.Where("(company_id IS NULL OR company_id = ?)", scope.CompanyID)
+}
+
 func (s *Service) getSettings(ctx context.Context) (ThemeSettings, error) {
 	return s.getSettingsTx(s.db.WithContext(ctx))
 }
@@ -1055,9 +1248,10 @@ func (s *Service) getSettingsTx(tx *gorm.DB) (ThemeSettings, error) {
 	return settings, nil
 }
 
-func (s *Service) appendAuditTx(tx *gorm.DB, actor string, action string, entityType string, entityID string, before JSONMap, after JSONMap) error {
+func (s *Service) appendAuditTx(tx *gorm.DB, actor string, companyID *string, action string, entityType string, entityID string, before JSONMap, after JSONMap) error {
 	log := ThemeAuditLog{
 		ActorUserID: strings.TrimSpace(actor),
+		CompanyID:   companyID,
 		Action:      action,
 		EntityType:  entityType,
 		EntityID:    entityID,
@@ -1137,14 +1331,35 @@ func toThemeDTO(theme Theme) ThemeDTO {
 	normalizedTokens := normalizeThemeTokens(theme.TokenJSON)
 	normalizedAssets := normalizeThemeAssetManifest(theme.AssetManifestJSON)
 	return ThemeDTO{
-		ID:       theme.ID,
-		Code:     theme.Code,
-		Name:     theme.Name,
-		Type:     theme.Type,
-		IsActive: theme.IsActive,
-		Tokens:   cloneJSONMap(normalizedTokens),
-		Assets:   cloneJSONMap(normalizedAssets),
+		ID:        theme.ID,
+		Code:      theme.Code,
+		Name:      theme.Name,
+		Type:      theme.Type,
+		IsActive:  theme.IsActive,
+		CompanyID: theme.CompanyID,
+		Tokens:    cloneJSONMap(normalizedTokens),
+		Assets:    cloneJSONMap(normalizedAssets),
+	}
+}
+
+func toRuntimeThemeDTO(theme Theme, tokens JSONMap, assets JSONMap) ThemeDTO {
+	return ThemeDTO{
+		ID:        theme.ID,
+		Code:      theme.Code,
+		Name:      theme.Name,
+		Type:      theme.Type,
+		IsActive:  theme.IsActive,
+		CompanyID: theme.CompanyID,
+		Tokens:    cloneJSONMap(tokens),
+		Assets:    cloneJSONMap(assets),
+	}
+}
+
+func sameCompany(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
 	}
+	return strings.TrimSpace(*a) == strings.TrimSpace(*b)
 }
 
 func toCampaignDTO(campaign ThemeCampaign, now time.Time) CampaignDTO {
@@ -1161,6 +1376,9 @@ func toCampaignDTO(campaign ThemeCampaign, now time.Time) CampaignDTO {
 		LightModeEnabled: campaign.LightModeEnabled,
 		DarkModeEnabled:  campaign.DarkModeEnabled,
 		Assets:           normalizeCampaignAssets(campaign.AssetsJSON),
+		CompanyID:        campaign.CompanyID,
+		TargetRoles:      append([]string{}, campaign.TargetRoles...),
+		TargetPlatforms:  append([]string{}, campaign.TargetPlatforms...),
 		UpdatedBy:        campaign.UpdatedBy,
 		UpdatedAt:        campaign.UpdatedAt,
 		Status:           string(resolveCampaignStatus(campaign, now)),
@@ -405,3 +405,34 @@ func containsValue(values []string, target string) bool {
 	}
 	return false
 }
+
+func TestCollectUploadPaths_WalksNestedCampaignAssets(t *testing.T) {
+	paths := map[string]struct{}{}
+	collectUploadPaths(JSONMap{
+		"backgroundImage": "/uploads/theme-assets/web/backgroundImage/a.png",
+		"web": map[string]interface{}{
+			"illustration": "uploads/theme-assets/companies/c1/web/illustration/b.png",
+			"app_ui": map[string]interface{}{
+				"navbar": "/uploads/theme-assets/web/appUiAsset/navbar/c.svg",
+			},
+		},
+		"gallery":  []interface{}{"/uploads/theme-assets/mobile/illustration/d.png"},
+		"external": "https://example.com/e.png",
+		"iconPack": "outline-enterprise",
+	}, paths)
+
+	want := []string{
+		"/uploads/theme-assets/web/backgroundImage/a.png",
+		"/uploads/theme-assets/companies/c1/web/illustration/b.png",
+		"/uploads/theme-assets/web/appUiAsset/navbar/c.svg",
+		"/uploads/theme-assets/mobile/illustration/d.png",
+	}
+	if len(paths) != len(want) {
+		t.Fatalf("expected %d paths, got %v", len(want), paths)
+	}
+	for _, path := range want {
+		if _, ok := paths[path]; !ok {
+			t.Fatalf("expected %s to be collected, got %v", path, paths)
+		}
+	}
+}
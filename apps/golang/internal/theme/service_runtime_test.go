@@ -402,7 +402,7 @@ func newRuntimeThemeTestService(t *testing.T) (*Service, *gorm.DB) {
 		return nil, nil
 	}
 
-	if err := db.AutoMigrate(&Theme{}, &ThemeCampaign{}, &ThemeSettings{}); err != nil {
+	if err := db.AutoMigrate(&Theme{}, &ThemeCampaign{}, &ThemeSettings{}, &ThemeCompanySettings{}); err != nil {
 		t.Fatalf("db.AutoMigrate(theme runtime models) error: %v", err)
 	}
 
@@ -0,0 +1,313 @@
+package theme
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	RuntimeScopeGlobal  = "GLOBAL"
+	RuntimeScopeCompany = "COMPANY"
+)
+
+var campaignTargetRoleKeys = []string{
+	"SUPER_ADMIN",
+	"COMPANY_ADMIN",
+	"AREA_MANAGER",
+	"MANAGER",
+	"ASISTEN",
+	"MANDOR",
+	"SATPAM",
+	"TIMBANGAN",
+	"GRADING",
+}
+
+var campaignTargetPlatformKeys = []string{
+	"web",
+	"mobile",
+}
+
+// ManageScope limits theme management to the records of one company. The
+// zero value is the global scope used by super admins.
+type ManageScope struct {
+	CompanyID string
+}
+
+// IsGlobal reports whether the scope may manage every company and the
+// global records.
+func (s ManageScope) IsGlobal() bool {
+	return strings.TrimSpace(s.CompanyID) == ""
+}
+
+// canManage reports whether the scope may change a record owned by owner.
+func (s ManageScope) canManage(owner *string) bool {
+	if s.IsGlobal() {
+		return true
+	}
+	return owner != nil && strings.TrimSpace(*owner) == strings.TrimSpace(s.CompanyID)
+}
+
+// canUseTheme reports whether records of owner may reference a theme owned
+// by themeOwner. Global themes are usable by everyone.
+func canUseTheme(themeOwner *string, owner *string) bool {
+	if themeOwner == nil {
+		return true
+	}
+	return owner != nil && strings.TrimSpace(*themeOwner) == strings.TrimSpace(*owner)
+}
+
+// resolveOwner returns the company a new or updated record belongs to.
+// Company scopes always own their records; the global scope may assign any
+// company or leave the record global.
+func (s ManageScope) resolveOwner(requested *string) (*string, error) {
+	requestedID := ""
+	if requested != nil {
+		requestedID = strings.TrimSpace(*requested)
+	}
+
+	if s.IsGlobal() {
+		if requestedID == "" {
+			return nil, nil
+		}
+		return &requestedID, nil
+	}
+
+	companyID := strings.TrimSpace(s.CompanyID)
+	if requestedID != "" && requestedID != companyID {
+		return nil, fmt.Errorf("company_id is outside your company scope")
+	}
+	return &companyID, nil
+}
+
+// normalizeCampaignTargets trims, deduplicates and validates target values.
+func normalizeCampaignTargets(values []string, allowed []string, field string, upper bool) (StringArray, error) {
+	allowedSet := make(map[string]struct{}, len(allowed))
+	for _, value := range allowed {
+		allowedSet[value] = struct{}{}
+	}
+
+	seen := map[string]struct{}{}
+	result := StringArray{}
+	for _, value := range values {
+		normalized := strings.TrimSpace(value)
+		if upper {
+			normalized = strings.ToUpper(normalized)
+		} else {
+			normalized = strings.ToLower(normalized)
+		}
+		if normalized == "" {
+			continue
+		}
+		if _, ok := allowedSet[normalized]; !ok {
+			return nil, fmt.Errorf("%s contains unsupported value %q", field, value)
+		}
+		if _, ok := seen[normalized]; ok {
+			continue
+		}
+		seen[normalized] = struct{}{}
+		result = append(result, normalized)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// campaignTargetSpecificity scores how narrowly a campaign targets the
+// runtime context, or returns -1 when it does not apply. A company match
+// outweighs role and platform targeting together.
+func campaignTargetSpecificity(campaign ThemeCampaign, companyID string, role string, platform string) int {
+	score := 0
+	if campaign.CompanyID != nil && strings.TrimSpace(*campaign.CompanyID) != "" {
+		if strings.TrimSpace(*campaign.CompanyID) != companyID {
+			return -1
+		}
+		score += 4
+	}
+	if len(campaign.TargetRoles) > 0 {
+		if !containsTarget(campaign.TargetRoles, role) {
+			return -1
+		}
+		score += 2
+	}
+	if len(campaign.TargetPlatforms) > 0 {
+		if !containsTarget(campaign.TargetPlatforms, platform) {
+			return -1
+		}
+		score++
+	}
+	return score
+}
+
+// selectRuntimeCampaign picks the most specific live campaign for the
+// runtime context. Priority and then recency break ties.
+func selectRuntimeCampaign(campaigns []ThemeCampaign, runtimeCtx RuntimeThemeContext) *ThemeCampaign {
+	companyID := strings.TrimSpace(runtimeCtx.CompanyID)
+	role := strings.ToUpper(strings.TrimSpace(runtimeCtx.Role))
+	platform := normalizeRuntimePlatform(runtimeCtx.Platform)
+
+	var selected *ThemeCampaign
+	selectedScore := -1
+	for i := range campaigns {
+		score := campaignTargetSpecificity(campaigns[i], companyID, role, platform)
+		if score < 0 {
+			continue
+		}
+		if selected == nil || score > selectedScore ||
+			(score == selectedScore && (campaigns[i].Priority > selected.Priority ||
+				(campaigns[i].Priority == selected.Priority && campaigns[i].UpdatedAt.After(selected.UpdatedAt)))) {
+			selected = &campaigns[i]
+			selectedScore = score
+		}
+	}
+	return selected
+}
+
+func containsTarget(values StringArray, target string) bool {
+	if target == "" {
+		return false
+	}
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCompanySettings returns the branding settings of a company. Companies
+// without a row get empty settings.
+func (s *Service) GetCompanySettings(ctx context.Context, companyID string) (*ThemeCompanySettings, error) {
+	companyID = strings.TrimSpace(companyID)
+	if companyID == "" {
+		return nil, fmt.Errorf("company id is required")
+	}
+
+	var settings ThemeCompanySettings
+	err := s.db.WithContext(ctx).First(&settings, "company_id = ?", companyID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &ThemeCompanySettings{CompanyID: companyID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load company theme settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// SetCompanyDefaultTheme sets the base theme of a company. An empty themeID
+// falls the company back to the global default theme.
+func (s *Service) SetCompanyDefaultTheme(ctx context.Context, actor string, scope ManageScope, companyID string, themeID string) (*ThemeCompanySettings, error) {
+	owner, err := scope.resolveOwner(&companyID)
+	if err != nil {
+		return nil, err
+	}
+	if owner == nil {
+		return nil, fmt.Errorf("company id is required")
+	}
+	themeID = strings.TrimSpace(themeID)
+
+	tx := s.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	var nextThemeID *string
+	if themeID != "" {
+		var selectedTheme Theme
+		if err := tx.First(&selectedTheme, "id = ?", themeID).Error; err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("theme not found: %w", err)
+		}
+		if !selectedTheme.IsActive {
+			tx.Rollback()
+			return nil, fmt.Errorf("default theme must be active")
+		}
+		if !canUseTheme(selectedTheme.CompanyID, owner) {
+			tx.Rollback()
+			return nil, fmt.Errorf("theme belongs to another company")
+		}
+		nextThemeID = &selectedTheme.ID
+	}
+
+	settings := ThemeCompanySettings{CompanyID: *owner}
+	if err := tx.First(&settings, "company_id = ?", *owner).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		tx.Rollback()
+		return nil, fmt.Errorf("load company theme settings: %w", err)
+	}
+	before := ""
+	if settings.DefaultThemeID != nil {
+		before = *settings.DefaultThemeID
+	}
+
+	settings.DefaultThemeID = nextThemeID
+	settings.UpdatedBy = actor
+	settings.UpdatedAt = time.Now()
+	if err := tx.Save(&settings).Error; err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("set company default theme: %w", err)
+	}
+
+	after := ""
+	if nextThemeID != nil {
+		after = *nextThemeID
+	}
+	if err := s.appendAuditTx(tx, actor, owner, "SET_COMPANY_DEFAULT_THEME", "theme_company_settings.default_theme_id", *owner, JSONMap{
+		"default_theme_id": before,
+	}, JSONMap{
+		"default_theme_id": after,
+	}); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	s.invalidateRuntime(RuntimeChangeSettings, owner)
+	return &settings, nil
+}
+
+// resolveBaseTheme returns the base theme for a runtime request and whether
+// it is the company's own branding. Inactive or missing company defaults fall
+// back to the global default.
+func (s *Service) resolveBaseTheme(ctx context.Context, settings ThemeSettings, companyID string) (*Theme, string, error) {
+	if companyID != "" && !settings.GlobalKillSwitch {
+		var companySettings ThemeCompanySettings
+		err := s.db.WithContext(ctx).First(&companySettings, "company_id = ?", companyID).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", fmt.Errorf("load company theme settings: %w", err)
+		}
+		if err == nil && companySettings.DefaultThemeID != nil {
+			companyTheme, themeErr := s.findThemeByID(ctx, *companySettings.DefaultThemeID)
+			if themeErr == nil && companyTheme.IsActive {
+				return companyTheme, RuntimeScopeCompany, nil
+			}
+		}
+	}
+
+	theme, err := s.findThemeByID(ctx, settings.DefaultThemeID)
+	if err != nil {
+		return nil, "", err
+	}
+	return theme, RuntimeScopeGlobal, nil
+}
+
+// isCompanyDefaultThemeTx reports whether any company uses themeID as its
+// default theme.
+func isCompanyDefaultThemeTx(tx *gorm.DB, themeID string) (bool, error) {
+	var count int64
+	if err := tx.Model(&ThemeCompanySettings{}).Where("default_theme_id = ?", themeID).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("check company default themes: %w", err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,99 @@
+package theme
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSelectRuntimeCampaign_PrefersMostSpecificMatch(t *testing.T) {
+	companyA := "00000000-0000-0000-0000-00000000000a"
+	companyB := "00000000-0000-0000-0000-00000000000b"
+	now := time.Now()
+
+	campaigns := []ThemeCampaign{
+		{ID: "global-high", Priority: 900, UpdatedAt: now},
+		{ID: "company-a", CompanyID: &companyA, Priority: 10, UpdatedAt: now},
+		{ID: "company-a-manager", CompanyID: &companyA, TargetRoles: StringArray{"MANAGER"}, Priority: 5, UpdatedAt: now},
+		{ID: "company-b", CompanyID: &companyB, Priority: 999, UpdatedAt: now},
+		{ID: "global-mobile", TargetPlatforms: StringArray{"mobile"}, Priority: 1, UpdatedAt: now},
+	}
+
+	cases := []struct {
+		name string
+		ctx  RuntimeThemeContext
+		want string
+	}{
+		{"company role match", RuntimeThemeContext{CompanyID: companyA, Role: "manager", Platform: "web"}, "company-a-manager"},
+		{"company match", RuntimeThemeContext{CompanyID: companyA, Role: "MANDOR", Platform: "web"}, "company-a"},
+		{"no company prefers platform match", RuntimeThemeContext{Platform: "mobile"}, "global-mobile"},
+		{"no company falls back to priority", RuntimeThemeContext{Platform: "web"}, "global-high"},
+	}
+
+	for _, tc := range cases {
+		selected := selectRuntimeCampaign(campaigns, tc.ctx)
+		if selected == nil {
+			t.Fatalf("%s: selectRuntimeCampaign() = nil, want %q", tc.name, tc.want)
+		}
+		if selected.ID != tc.want {
+			t.Errorf("%s: selectRuntimeCampaign() = %q, want %q", tc.name, selected.ID, tc.want)
+		}
+	}
+}
+
+func TestSelectRuntimeCampaign_BreaksTiesByPriorityThenRecency(t *testing.T) {
+	now := time.Now()
+	campaigns := []ThemeCampaign{
+		{ID: "older", Priority: 10, UpdatedAt: now.Add(-time.Hour)},
+		{ID: "newer", Priority: 10, UpdatedAt: now},
+		{ID: "low", Priority: 1, UpdatedAt: now.Add(time.Hour)},
+	}
+
+	selected := selectRuntimeCampaign(campaigns, RuntimeThemeContext{Platform: "web"})
+	if selected == nil || selected.ID != "newer" {
+		t.Fatalf("selectRuntimeCampaign() = %v, want newer", selected)
+	}
+
+	if selected := selectRuntimeCampaign([]ThemeCampaign{{ID: "roles", TargetRoles: StringArray{"SATPAM"}}}, RuntimeThemeContext{}); selected != nil {
+		t.Errorf("selectRuntimeCampaign() without role = %q, want nil", selected.ID)
+	}
+}
+
+func TestNormalizeCampaignTargets(t *testing.T) {
+	roles, err := normalizeCampaignTargets([]string{" manager", "SATPAM", "Manager", ""}, campaignTargetRoleKeys, "target_roles", true)
+	if err != nil {
+		t.Fatalf("normalizeCampaignTargets(roles) error: %v", err)
+	}
+	if len(roles) != 2 || roles[0] != "MANAGER" || roles[1] != "SATPAM" {
+		t.Errorf("normalizeCampaignTargets(roles) = %v, want [MANAGER SATPAM]", roles)
+	}
+
+	if _, err := normalizeCampaignTargets([]string{"tablet"}, campaignTargetPlatformKeys, "target_platforms", false); err == nil {
+		t.Errorf("normalizeCampaignTargets(tablet) error = nil, want error")
+	}
+}
+
+func TestManageScopeResolveOwner(t *testing.T) {
+	companyA := "company-a"
+	companyB := "company-b"
+
+	owner, err := ManageScope{}.resolveOwner(nil)
+	if err != nil || owner != nil {
+		t.Errorf("global resolveOwner(nil) = %v, %v; want nil, nil", owner, err)
+	}
+
+	owner, err = ManageScope{CompanyID: companyA}.resolveOwner(nil)
+	if err != nil || owner == nil || *owner != companyA {
+		t.Errorf("company resolveOwner(nil) = %v, %v; want %q", owner, err, companyA)
+	}
+
+	if _, err := (ManageScope{CompanyID: companyA}).resolveOwner(&companyB); err == nil {
+		t.Errorf("company resolveOwner(other company) error = nil, want error")
+	}
+
+	if (ManageScope{CompanyID: companyA}).canManage(nil) {
+		t.Errorf("company scope canManage(global) = true, want false")
+	}
+	if !canUseTheme(nil, &companyA) || canUseTheme(&companyB, &companyA) || canUseTheme(&companyA, nil) {
+		t.Errorf("canUseTheme() returned unexpected result")
+	}
+}
@@ -72,27 +72,11 @@ func (cm *ConnectionManager) AddClient(userID, username, role, companyID string,
 
 	// Add to main clients map
 	cm.clients[clientID] = client
-
-	// Add to user index
-	if cm.clientsByUser[userID] == nil {
-		cm.clientsByUser[userID] = make(map[string]*models.Client)
-	}
-	cm.clientsByUser[userID][clientID] = client
-
-	// Add to role index
-	if cm.clientsByRole[role] == nil {
-		cm.clientsByRole[role] = make(map[string]*models.Client)
-	}
-	cm.clientsByRole[role][clientID] = client
-
-	// Add to company index
-	if cm.clientsByCompany[companyID] == nil {
-		cm.clientsByCompany[companyID] = make(map[string]*models.Client)
-	}
-	cm.clientsByCompany[companyID][clientID] = client
+	cm.indexClient(client)
 
 	// Subscribe to appropriate channels based on role
 	cm.subscribeToRoleChannels(client)
+	connectedClients.WithLabelValues(role).Inc()
 
 	log.Printf("Client connected: %s (User: %s, Role: %s, Company: %s)", clientID, username, role, companyID)
 
@@ -116,37 +100,83 @@ func (cm *ConnectionManager) RemoveClient(clientID string) {
 		}
 	}
 
-	// Remove from user index
+	cm.unindexClient(client)
+
+	// Close send channel
+	close(client.SendChannel)
+
+	// Remove from main clients map
+	delete(cm.clients, clientID)
+	connectedClients.WithLabelValues(client.Role).Dec()
+
+	log.Printf("Client disconnected: %s (User: %s)", clientID, client.Username)
+}
+
+// AuthenticateClient records the user an anonymous client authenticated as
+// and moves the client to the indexes, channels and connection gauge of its
+// new role
+func (cm *ConnectionManager) AuthenticateClient(clientID, userID, username, role, companyID string) {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+
+	client, exists := cm.clients[clientID]
+	if !exists {
+		return
+	}
+
+	previousRole := client.Role
+	cm.unindexClient(client)
+	client.UserID = userID
+	client.Username = username
+	client.Role = role
+	client.CompanyID = companyID
+	cm.indexClient(client)
+
+	cm.subscribeToRoleChannels(client)
+	connectedClients.WithLabelValues(previousRole).Dec()
+	connectedClients.WithLabelValues(role).Inc()
+}
+
+// indexClient adds a client to the user, role and company indexes
+func (cm *ConnectionManager) indexClient(client *models.Client) {
+	if cm.clientsByUser[client.UserID] == nil {
+		cm.clientsByUser[client.UserID] = make(map[string]*models.Client)
+	}
+	cm.clientsByUser[client.UserID][client.ID] = client
+
+	if cm.clientsByRole[client.Role] == nil {
+		cm.clientsByRole[client.Role] = make(map[string]*models.Client)
+	}
+	cm.clientsByRole[client.Role][client.ID] = client
+
+	if cm.clientsByCompany[client.CompanyID] == nil {
+		cm.clientsByCompany[client.CompanyID] = make(map[string]*models.Client)
+	}
+	cm.clientsByCompany[client.CompanyID][client.ID] = client
+}
+
+// unindexClient removes a client from the user, role and company indexes
+func (cm *ConnectionManager) unindexClient(client *models.Client) {
 	if cm.clientsByUser[client.UserID] != nil {
-		delete(cm.clientsByUser[client.UserID], clientID)
+		delete(cm.clientsByUser[client.UserID], client.ID)
 		if len(cm.clientsByUser[client.UserID]) == 0 {
 			delete(cm.clientsByUser, client.UserID)
 		}
 	}
 
-	// Remove from role index
 	if cm.clientsByRole[client.Role] != nil {
-		delete(cm.clientsByRole[client.Role], clientID)
+		delete(cm.clientsByRole[client.Role], client.ID)
 		if len(cm.clientsByRole[client.Role]) == 0 {
 			delete(cm.clientsByRole, client.Role)
 		}
 	}
 
-	// Remove from company index
 	if cm.clientsByCompany[client.CompanyID] != nil {
-		delete(cm.clientsByCompany[client.CompanyID], clientID)
+		delete(cm.clientsByCompany[client.CompanyID], client.ID)
 		if len(cm.clientsByCompany[client.CompanyID]) == 0 {
 			delete(cm.clientsByCompany, client.CompanyID)
 		}
 	}
-
-	// Close send channel
-	close(client.SendChannel)
-
-	// Remove from main clients map
-	delete(cm.clients, clientID)
-
-	log.Printf("Client disconnected: %s (User: %s)", clientID, client.Username)
 }
 
 // GetClient returns a client by ID
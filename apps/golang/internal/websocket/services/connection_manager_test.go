@@ -0,0 +1,38 @@
+package services
+
+import (
+	"testing"
+
+	"agrinovagraphql/server/internal/websocket/models"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticateClient_MovesClientToItsRole(t *testing.T) {
+	cm := NewConnectionManager()
+	anonymousBefore := testutil.ToFloat64(connectedClients.WithLabelValues("anonymous"))
+	mandorBefore := testutil.ToFloat64(connectedClients.WithLabelValues("MANDOR"))
+
+	client := cm.AddClient("", "anonymous", "anonymous", "", nil)
+	assert.Equal(t, anonymousBefore+1, testutil.ToFloat64(connectedClients.WithLabelValues("anonymous")))
+
+	cm.AuthenticateClient(client.ID, "user-1", "mandor1", "MANDOR", "company-1")
+
+	assert.Equal(t, anonymousBefore, testutil.ToFloat64(connectedClients.WithLabelValues("anonymous")))
+	assert.Equal(t, mandorBefore+1, testutil.ToFloat64(connectedClients.WithLabelValues("MANDOR")))
+	assert.Empty(t, cm.GetClientsByRole("anonymous"))
+	require.Len(t, cm.GetClientsByRole("MANDOR"), 1)
+	assert.Len(t, cm.GetClientsByUser("user-1"), 1)
+	assert.Len(t, cm.GetClientsByCompany("company-1"), 1)
+	assert.Empty(t, cm.GetClientsByCompany(""))
+	assert.True(t, client.Channels[models.ChannelHarvest], "the client joins its role channels")
+
+	cm.RemoveClient(client.ID)
+
+	assert.Equal(t, anonymousBefore, testutil.ToFloat64(connectedClients.WithLabelValues("anonymous")))
+	assert.Equal(t, mandorBefore, testutil.ToFloat64(connectedClients.WithLabelValues("MANDOR")))
+	assert.Empty(t, cm.GetClientsByRole("MANDOR"))
+	assert.Empty(t, cm.GetClientsByUser("user-1"))
+}
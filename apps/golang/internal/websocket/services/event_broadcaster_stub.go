@@ -1,58 +1,112 @@
 package services
 
 import (
+	"context"
+	"encoding/json"
 	"log"
 	"time"
 
 	"gorm.io/gorm"
 
 	"agrinovagraphql/server/internal/graphql/domain/mandor"
+	"agrinovagraphql/server/internal/pubsub"
 	"agrinovagraphql/server/internal/websocket/models"
 )
 
+// topicWebSocketBroadcast carries broadcasts to the WebSocket clients of
+// every instance.
+const topicWebSocketBroadcast = "websocket.broadcast"
+
 // EventBroadcaster handles database event broadcasting for real-time updates
 type EventBroadcaster struct {
 	db        *gorm.DB
 	wsHandler *WebSocketHandler
+	bus       pubsub.PubSub
 }
 
-// NewEventBroadcaster creates a new event broadcaster
+// NewEventBroadcaster creates a new event broadcaster. Broadcasts are
+// published on bus and delivered by every instance to its own clients.
 func NewEventBroadcaster(
 	db *gorm.DB,
 	wsHandler *WebSocketHandler,
+	bus pubsub.PubSub,
 ) *EventBroadcaster {
-	return &EventBroadcaster{
+	if bus == nil {
+		bus = pubsub.NewMemory()
+	}
+	eb := &EventBroadcaster{
 		db:        db,
 		wsHandler: wsHandler,
+		bus:       bus,
+	}
+	bus.Subscribe(topicWebSocketBroadcast, eb.deliver)
+	return eb
+}
+
+// deliver hands a broadcast received from the bus to local clients
+func (eb *EventBroadcaster) deliver(payload []byte) {
+	var broadcast models.EventBroadcast
+	if err := json.Unmarshal(payload, &broadcast); err != nil {
+		log.Printf("Dropping malformed broadcast: %v", err)
+		return
+	}
+	eb.wsHandler.Broadcast(&broadcast)
+}
+
+// publish sends a broadcast to every instance
+func (eb *EventBroadcaster) publish(broadcast *models.EventBroadcast) {
+	payload, err := json.Marshal(broadcast)
+	if err != nil {
+		log.Printf("Failed to encode broadcast %s: %v", broadcast.Event, err)
+		return
+	}
+	if err := eb.bus.Publish(context.Background(), topicWebSocketBroadcast, payload); err != nil {
+		log.Printf("Failed to publish broadcast %s: %v", broadcast.Event, err)
 	}
 }
 
+func (eb *EventBroadcaster) broadcastToChannel(channel models.ChannelType, event string, data interface{}) {
+	eb.publish(&models.EventBroadcast{Event: event, Data: data, Channels: []models.ChannelType{channel}})
+}
+
+func (eb *EventBroadcaster) broadcastToRole(role string, event string, data interface{}) {
+	eb.publish(&models.EventBroadcast{Event: event, Data: data, Roles: []string{role}})
+}
+
+func (eb *EventBroadcaster) broadcastToUser(userID string, event string, data interface{}) {
+	eb.publish(&models.EventBroadcast{Event: event, Data: data, UserIDs: []string{userID}})
+}
+
+func (eb *EventBroadcaster) broadcastToCompany(companyID string, event string, data interface{}) {
+	eb.publish(&models.EventBroadcast{Event: event, Data: data, CompanyIDs: []string{companyID}})
+}
+
 // Harvest Event Broadcasting
 
 // OnHarvestRecordCreated broadcasts when a new harvest record is created
 func (eb *EventBroadcaster) OnHarvestRecordCreated(record *mandor.HarvestRecord) {
 	log.Printf("Broadcasting harvest record created event: %s", record.ID)
-	eb.wsHandler.BroadcastToChannel(models.ChannelHarvest, "harvestRecordCreated", record)
-	eb.wsHandler.BroadcastToRole("ASISTEN", "harvestRecordCreated", record)
-	eb.wsHandler.BroadcastToRole("MANAGER", "harvestRecordCreated", record)
-	eb.wsHandler.BroadcastToRole("AREA_MANAGER", "harvestRecordCreated", record)
+	eb.broadcastToChannel(models.ChannelHarvest, "harvestRecordCreated", record)
+	eb.broadcastToRole("ASISTEN", "harvestRecordCreated", record)
+	eb.broadcastToRole("MANAGER", "harvestRecordCreated", record)
+	eb.broadcastToRole("AREA_MANAGER", "harvestRecordCreated", record)
 }
 
 // OnHarvestRecordApproved broadcasts when a harvest record is approved
 func (eb *EventBroadcaster) OnHarvestRecordApproved(record *mandor.HarvestRecord) {
 	log.Printf("Broadcasting harvest record approved event: %s", record.ID)
-	eb.wsHandler.BroadcastToChannel(models.ChannelHarvest, "harvestRecordApproved", record)
+	eb.broadcastToChannel(models.ChannelHarvest, "harvestRecordApproved", record)
 	if record.Mandor != nil {
-		eb.wsHandler.BroadcastToUser(record.Mandor.ID, "harvestRecordApproved", record)
+		eb.broadcastToUser(record.Mandor.ID, "harvestRecordApproved", record)
 	}
-	eb.wsHandler.BroadcastToRole("MANAGER", "harvestRecordApproved", record)
-	eb.wsHandler.BroadcastToRole("AREA_MANAGER", "harvestRecordApproved", record)
+	eb.broadcastToRole("MANAGER", "harvestRecordApproved", record)
+	eb.broadcastToRole("AREA_MANAGER", "harvestRecordApproved", record)
 }
 
 // OnHarvestRecordRejected broadcasts when a harvest record is rejected
 func (eb *EventBroadcaster) OnHarvestRecordRejected(record *mandor.HarvestRecord) {
 	log.Printf("Broadcasting harvest record rejected event: %s", record.ID)
-	eb.wsHandler.BroadcastToChannel(models.ChannelHarvest, "harvestRecordRejected", record)
+	eb.broadcastToChannel(models.ChannelHarvest, "harvestRecordRejected", record)
 }
 
 // Gate Check Event Broadcasting (stub - GateCheckRecord type not in current schema)
@@ -60,13 +114,13 @@ func (eb *EventBroadcaster) OnHarvestRecordRejected(record *mandor.HarvestRecord
 // OnGateCheckCreated broadcasts when a new gate check record is created
 func (eb *EventBroadcaster) OnGateCheckCreated(record interface{}) {
 	log.Printf("Broadcasting gate check created event")
-	eb.wsHandler.BroadcastToChannel(models.ChannelGateCheck, "gateCheckCreated", record)
+	eb.broadcastToChannel(models.ChannelGateCheck, "gateCheckCreated", record)
 }
 
 // OnGateCheckCompleted broadcasts when a gate check is completed
 func (eb *EventBroadcaster) OnGateCheckCompleted(record interface{}) {
 	log.Printf("Broadcasting gate check completed event")
-	eb.wsHandler.BroadcastToChannel(models.ChannelGateCheck, "gateCheckCompleted", record)
+	eb.broadcastToChannel(models.ChannelGateCheck, "gateCheckCompleted", record)
 }
 
 // OnSystemAlert broadcasts system alerts to appropriate roles
@@ -81,15 +135,15 @@ func (eb *EventBroadcaster) OnSystemAlert(alertType, message string, severity st
 		"timestamp": time.Now(),
 	}
 
-	eb.wsHandler.BroadcastToChannel(models.ChannelSystem, "systemAlert", alertData)
+	eb.broadcastToChannel(models.ChannelSystem, "systemAlert", alertData)
 
 	switch severity {
 	case "critical":
-		eb.wsHandler.BroadcastToRole("SUPER_ADMIN", "critical_alert", alertData)
+		eb.broadcastToRole("SUPER_ADMIN", "critical_alert", alertData)
 	case "high":
-		eb.wsHandler.BroadcastToRole("COMPANY_ADMIN", "high_severity_alert", alertData)
+		eb.broadcastToRole("COMPANY_ADMIN", "high_severity_alert", alertData)
 	case "medium":
-		eb.wsHandler.BroadcastToRole("MANAGER", "management_alert", alertData)
+		eb.broadcastToRole("MANAGER", "management_alert", alertData)
 	}
 }
 
@@ -104,9 +158,9 @@ func (eb *EventBroadcaster) OnUserStatusChange(userID, status string, metadata m
 		"timestamp": time.Now(),
 	}
 
-	eb.wsHandler.BroadcastToUser(userID, "userStatusChange", statusData)
-	eb.wsHandler.BroadcastToRole("COMPANY_ADMIN", "userStatusChange", statusData)
-	eb.wsHandler.BroadcastToRole("SUPER_ADMIN", "userStatusChange", statusData)
+	eb.broadcastToUser(userID, "userStatusChange", statusData)
+	eb.broadcastToRole("COMPANY_ADMIN", "userStatusChange", statusData)
+	eb.broadcastToRole("SUPER_ADMIN", "userStatusChange", statusData)
 }
 
 // OnCompanyUpdate broadcasts company-wide updates
@@ -121,13 +175,13 @@ func (eb *EventBroadcaster) OnCompanyUpdate(companyID, updateType, message strin
 		"timestamp": time.Now(),
 	}
 
-	eb.wsHandler.BroadcastToCompany(companyID, "companyUpdate", updateData)
+	eb.broadcastToCompany(companyID, "companyUpdate", updateData)
 }
 
 // OnPKSDataReceived broadcasts when PKS data is received
 func (eb *EventBroadcaster) OnPKSDataReceived(pksData interface{}) {
 	log.Printf("Broadcasting PKS data received event")
-	eb.wsHandler.BroadcastToChannel(models.ChannelPKS, "pksDataReceived", pksData)
-	eb.wsHandler.BroadcastToRole("MANAGER", "pksDataReceived", pksData)
-	eb.wsHandler.BroadcastToRole("AREA_MANAGER", "pksDataReceived", pksData)
+	eb.broadcastToChannel(models.ChannelPKS, "pksDataReceived", pksData)
+	eb.broadcastToRole("MANAGER", "pksDataReceived", pksData)
+	eb.broadcastToRole("AREA_MANAGER", "pksDataReceived", pksData)
 }
@@ -0,0 +1,16 @@
+package services
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var connectedClients = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "agrinova",
+		Subsystem: "websocket",
+		Name:      "connections",
+		Help:      "Number of connected real-time event WebSocket clients, by role",
+	},
+	[]string{"role"},
+)
@@ -22,6 +22,7 @@ type WebSocketHandler struct {
 	tokenService      mobileDomain.TokenService
 	userService       *authServices.UserService
 	upgrader          websocket.Upgrader
+	rateLimiter       *WebSocketRateLimiter
 }
 
 // NewWebSocketHandler creates a new WebSocket handler
@@ -45,11 +46,25 @@ func NewWebSocketHandler(
 	}
 }
 
+// SetRateLimiter limits new connections per IP and messages per connection.
+// Without one the handler accepts everything.
+func (h *WebSocketHandler) SetRateLimiter(limiter *WebSocketRateLimiter) {
+	h.rateLimiter = limiter
+}
+
 // HandleWebSocket handles WebSocket upgrade and connection
 func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
+	if h.rateLimiter != nil && !h.rateLimiter.AllowConnection(c.ClientIP(), c.Request.UserAgent()) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"message": "too many WebSocket connections, try again later"})
+		return
+	}
+
 	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		log.Printf("Failed to upgrade WebSocket connection: %v", err)
+		if h.rateLimiter != nil {
+			h.rateLimiter.RemoveConnection("")
+		}
 		return
 	}
 
@@ -94,18 +109,15 @@ func (h *WebSocketHandler) handleAuthentication(client *models.Client, message *
 		return false
 	}
 
-	// Update client information
-	client.UserID = user.ID
-	client.Username = user.Username
-	client.Role = string(user.Role)
-	client.Username = user.Username
-	client.Role = string(user.Role)
-
 	// Get company assignment
+	companyID := client.CompanyID
 	assignments, err := h.userService.GetUserCompanyAssignments(user.ID)
 	if err == nil && len(assignments) > 0 {
-		client.CompanyID = assignments[0].CompanyID
+		companyID = assignments[0].CompanyID
 	}
+
+	// Update client information
+	h.connectionManager.AuthenticateClient(client.ID, user.ID, user.Username, string(user.Role), companyID)
 	client.SetStatus(models.ConnectionConnected)
 
 	// Add platform information if provided
@@ -129,6 +141,9 @@ func (h *WebSocketHandler) handleClientMessages(client *models.Client) {
 			log.Printf("Panic in handleClientMessages for client %s: %v", client.ID, r)
 		}
 	}()
+	if h.rateLimiter != nil {
+		defer h.rateLimiter.RemoveConnection(client.ID)
+	}
 
 	for {
 		client.Connection.SetReadDeadline(time.Now().Add(60 * time.Second))
@@ -140,6 +155,11 @@ func (h *WebSocketHandler) handleClientMessages(client *models.Client) {
 			break
 		}
 
+		if h.rateLimiter != nil && !h.rateLimiter.AllowMessage(client.ID, int64(len(messageBytes))) {
+			h.sendError(client, "Rate limit exceeded")
+			continue
+		}
+
 		var message models.WebSocketMessage
 		if err := json.Unmarshal(messageBytes, &message); err != nil {
 			log.Printf("Failed to unmarshal message from client %s: %v", client.ID, err)
@@ -350,6 +370,11 @@ func (h *WebSocketHandler) GetAllConnections() []models.ConnectionInfo {
 	return h.connectionManager.GetAllConnections()
 }
 
+// Broadcast delivers a prepared broadcast to this instance's clients
+func (h *WebSocketHandler) Broadcast(broadcast *models.EventBroadcast) {
+	h.connectionManager.Broadcast(broadcast)
+}
+
 // BroadcastToChannel broadcasts a message to a specific channel
 func (h *WebSocketHandler) BroadcastToChannel(channel models.ChannelType, event string, data interface{}) {
 	h.connectionManager.BroadcastToChannel(channel, event, data)
@@ -1,10 +1,14 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"sync"
 	"time"
 
+	"agrinovagraphql/server/internal/ratelimit"
+
 	"github.com/gin-gonic/gin"
 	"golang.org/x/time/rate"
 )
@@ -31,6 +35,10 @@ type WebSocketRateLimiter struct {
 	globalConnLimiter *rate.Limiter
 	globalMsgLimiter  *rate.Limiter
 
+	// Shared per-IP connection and auth limits; nil keeps them in memory.
+	// Message and subscription limits stay per connection.
+	counter ratelimit.Counter
+
 	// Configuration
 	config WebSocketRateLimitConfig
 
@@ -153,25 +161,40 @@ func NewWebSocketRateLimiter(config WebSocketRateLimitConfig) *WebSocketRateLimi
 	return wrl
 }
 
+// NewSharedWebSocketRateLimiter creates a WebSocket rate limiter whose
+// per-IP connection and auth limits are kept in counter, so they hold
+// across instances and restarts
+func NewSharedWebSocketRateLimiter(config WebSocketRateLimitConfig, counter ratelimit.Counter) *WebSocketRateLimiter {
+	wrl := NewWebSocketRateLimiter(config)
+	wrl.counter = counter
+	return wrl
+}
+
+// allowShared counts one hit against a per-minute limit in the shared
+// counter. Counter failures allow the attempt and are logged.
+func (wrl *WebSocketRateLimiter) allowShared(key string, perMinute int) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	allowed, _, err := ratelimit.Allow(ctx, wrl.counter, key, perMinute, time.Minute)
+	if err != nil {
+		log.Printf("websocket rate limit unavailable: %v", err)
+		return true
+	}
+	return allowed
+}
+
 // AllowConnection checks if a new WebSocket connection is allowed
 func (wrl *WebSocketRateLimiter) AllowConnection(clientIP, userAgent string) bool {
+	ipKey := fmt.Sprintf("conn:%s", clientIP)
+	if wrl.counter != nil {
+		return wrl.allowSharedConnection(ipKey)
+	}
+
 	wrl.mutex.Lock()
 	defer wrl.mutex.Unlock()
 
-	// Update statistics
-	wrl.stats.TotalConnections++
-	wrl.stats.ActiveConnections++
-	wrl.stats.LastUpdated = time.Now()
-
-	// Check global connection limit
-	if !wrl.globalConnLimiter.Allow() {
-		wrl.stats.RejectedConnections++
-		return false
-	}
-
-	// Check active connection limit
-	if wrl.stats.ActiveConnections > wrl.config.GlobalMaxConnections {
-		wrl.stats.RejectedConnections++
+	if !wrl.admitConnectionLocked() {
 		return false
 	}
 
@@ -179,7 +202,6 @@ func (wrl *WebSocketRateLimiter) AllowConnection(clientIP, userAgent string) boo
 	wrl.connMutex.Lock()
 	defer wrl.connMutex.Unlock()
 
-	ipKey := fmt.Sprintf("conn:%s", clientIP)
 	limiter, exists := wrl.connLimiters[ipKey]
 
 	if !exists {
@@ -196,6 +218,56 @@ func (wrl *WebSocketRateLimiter) AllowConnection(clientIP, userAgent string) boo
 		return false
 	}
 
+	wrl.stats.ActiveConnections++
+	return true
+}
+
+// allowSharedConnection checks the per-IP limit in the shared counter. The
+// counter call may take up to two seconds, so it runs without wrl.mutex; an
+// active slot is reserved first and released again when the IP is over its
+// limit, which keeps concurrent connections within GlobalMaxConnections.
+func (wrl *WebSocketRateLimiter) allowSharedConnection(ipKey string) bool {
+	wrl.mutex.Lock()
+	if !wrl.admitConnectionLocked() {
+		wrl.mutex.Unlock()
+		return false
+	}
+	wrl.stats.ActiveConnections++
+	wrl.mutex.Unlock()
+
+	if wrl.allowShared("ws:"+ipKey, wrl.config.MaxConnectionsPerMinuteIP) {
+		return true
+	}
+
+	wrl.mutex.Lock()
+	defer wrl.mutex.Unlock()
+	wrl.stats.ActiveConnections--
+	if wrl.stats.ActiveConnections < 0 {
+		wrl.stats.ActiveConnections = 0
+	}
+	wrl.stats.RejectedConnections++
+	return false
+}
+
+// admitConnectionLocked counts a connection attempt and applies the global
+// limits. The caller must hold wrl.mutex.
+func (wrl *WebSocketRateLimiter) admitConnectionLocked() bool {
+	// Update statistics
+	wrl.stats.TotalConnections++
+	wrl.stats.LastUpdated = time.Now()
+
+	// Check global connection limit
+	if !wrl.globalConnLimiter.Allow() {
+		wrl.stats.RejectedConnections++
+		return false
+	}
+
+	// Check active connection limit; only accepted connections are counted
+	// so RemoveConnection balances them
+	if wrl.stats.ActiveConnections >= wrl.config.GlobalMaxConnections {
+		wrl.stats.RejectedConnections++
+		return false
+	}
 	return true
 }
 
@@ -244,6 +316,24 @@ func (wrl *WebSocketRateLimiter) AllowMessage(clientID string, messageSize int64
 
 // AllowAuth checks if a WebSocket authentication attempt is allowed
 func (wrl *WebSocketRateLimiter) AllowAuth(clientIP string) bool {
+	ipKey := fmt.Sprintf("auth:%s", clientIP)
+	if wrl.counter != nil {
+		// The shared counter is consulted without wrl.mutex held; see
+		// allowSharedConnection.
+		wrl.mutex.Lock()
+		wrl.stats.TotalAuthAttempts++
+		wrl.stats.LastUpdated = time.Now()
+		wrl.mutex.Unlock()
+
+		if wrl.allowShared("ws:"+ipKey, wrl.config.AuthAttemptsPerMinuteIP) {
+			return true
+		}
+		wrl.mutex.Lock()
+		wrl.stats.RejectedAuthAttempts++
+		wrl.mutex.Unlock()
+		return false
+	}
+
 	wrl.mutex.Lock()
 	defer wrl.mutex.Unlock()
 
@@ -255,7 +345,6 @@ func (wrl *WebSocketRateLimiter) AllowAuth(clientIP string) bool {
 	wrl.authMutex.Lock()
 	defer wrl.authMutex.Unlock()
 
-	ipKey := fmt.Sprintf("auth:%s", clientIP)
 	limiter, exists := wrl.authLimiters[ipKey]
 
 	if !exists {
@@ -383,6 +472,16 @@ func (wrl *WebSocketRateLimiter) ResetClient(clientID string) {
 
 // ResetIP resets rate limiting for a specific IP
 func (wrl *WebSocketRateLimiter) ResetIP(clientIP string) {
+	if wrl.counter != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		for _, key := range []string{"ws:conn:" + clientIP, "ws:auth:" + clientIP} {
+			if err := wrl.counter.Reset(ctx, key); err != nil {
+				log.Printf("websocket rate limit reset failed: %v", err)
+			}
+		}
+	}
+
 	wrl.connMutex.Lock()
 	delete(wrl.connLimiters, fmt.Sprintf("conn:%s", clientIP))
 	wrl.connMutex.Unlock()
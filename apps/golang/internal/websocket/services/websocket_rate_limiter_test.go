@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"agrinovagraphql/server/internal/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingCounter holds every Hit until release is closed and then reports
+// count hits in the window.
+type blockingCounter struct {
+	entered chan string
+	release chan struct{}
+	count   int64
+}
+
+func newBlockingCounter(count int64) *blockingCounter {
+	return &blockingCounter{
+		entered: make(chan string, 4),
+		release: make(chan struct{}),
+		count:   count,
+	}
+}
+
+func (c *blockingCounter) Hit(ctx context.Context, key string, n int64, window time.Duration) (ratelimit.Window, error) {
+	c.entered <- key
+	select {
+	case <-c.release:
+	case <-ctx.Done():
+		return ratelimit.Window{}, ctx.Err()
+	}
+	return ratelimit.Window{Start: time.Now(), Length: window, Count: c.count}, nil
+}
+
+func (c *blockingCounter) Peek(ctx context.Context, key string, window time.Duration) (ratelimit.Window, error) {
+	return ratelimit.Window{Start: time.Now(), Length: window}, nil
+}
+
+func (c *blockingCounter) Reset(ctx context.Context, key string) error { return nil }
+
+func (c *blockingCounter) Close() error { return nil }
+
+func activeConnections(wrl *WebSocketRateLimiter) int {
+	wrl.mutex.RLock()
+	defer wrl.mutex.RUnlock()
+	return wrl.stats.ActiveConnections
+}
+
+// waitFor fails the test when fn does not return within a second.
+func waitFor(t *testing.T, what string, fn func()) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("%s blocked while the shared counter was busy", what)
+	}
+}
+
+func TestAllowConnection_DoesNotHoldLockDuringSharedCounter(t *testing.T) {
+	counter := newBlockingCounter(1)
+	wrl := NewSharedWebSocketRateLimiter(DefaultWebSocketRateLimitConfig(), counter)
+
+	allowed := make(chan bool, 1)
+	go func() { allowed <- wrl.AllowConnection("10.0.0.1", "test") }()
+	assert.Equal(t, "ws:conn:10.0.0.1", <-counter.entered)
+
+	// Other clients keep flowing while one connection waits on the counter.
+	waitFor(t, "AllowMessage", func() { assert.True(t, wrl.AllowMessage("client-1", 10)) })
+	waitFor(t, "GetStats", func() { wrl.GetStats() })
+	assert.Equal(t, 1, activeConnections(wrl), "the pending connection reserves its slot")
+
+	close(counter.release)
+	assert.True(t, <-allowed)
+	assert.Equal(t, 1, activeConnections(wrl))
+}
+
+func TestAllowConnection_ReleasesSlotWhenSharedLimitRejects(t *testing.T) {
+	config := DefaultWebSocketRateLimitConfig()
+	counter := newBlockingCounter(int64(config.MaxConnectionsPerMinuteIP) + 1)
+	close(counter.release)
+	wrl := NewSharedWebSocketRateLimiter(config, counter)
+
+	assert.False(t, wrl.AllowConnection("10.0.0.1", "test"))
+	assert.Equal(t, 0, activeConnections(wrl))
+	stats := wrl.GetStats()
+	assert.Equal(t, int64(1), stats.TotalConnections)
+	assert.Equal(t, int64(1), stats.RejectedConnections)
+}
+
+func TestAllowAuth_DoesNotHoldLockDuringSharedCounter(t *testing.T) {
+	counter := newBlockingCounter(1)
+	wrl := NewSharedWebSocketRateLimiter(DefaultWebSocketRateLimitConfig(), counter)
+
+	allowed := make(chan bool, 1)
+	go func() { allowed <- wrl.AllowAuth("10.0.0.1") }()
+	require.Equal(t, "ws:auth:10.0.0.1", <-counter.entered)
+
+	waitFor(t, "AllowSubscription", func() { assert.True(t, wrl.AllowSubscription("client-1", 0)) })
+
+	close(counter.release)
+	assert.True(t, <-allowed)
+	assert.Equal(t, int64(1), wrl.GetStats().TotalAuthAttempts)
+}
@@ -17,15 +17,18 @@ const productionBackendEnvPath = `D:\agrinova\backend\config\.env`
 
 // Config holds all application configuration
 type Config struct {
-	Database   DatabaseConfig  `mapstructure:"database"`
-	Server     ServerConfig    `mapstructure:"server"`
-	CORS       CORSConfig      `mapstructure:"cors"`
-	Auth       AuthConfig      `mapstructure:"auth"`
-	Security   SecurityConfig  `mapstructure:"security"`
-	Logging    LoggingConfig   `mapstructure:"logging"`
-	WebSocket  WebSocketConfig `mapstructure:"websocket"`
-	GraphQL    GraphQLConfig   `mapstructure:"graphql"`
-	UploadsDir string          `mapstructure:"uploads_dir"`
+	Database      DatabaseConfig      `mapstructure:"database"`
+	Server        ServerConfig        `mapstructure:"server"`
+	CORS          CORSConfig          `mapstructure:"cors"`
+	Auth          AuthConfig          `mapstructure:"auth"`
+	Security      SecurityConfig      `mapstructure:"security"`
+	Logging       LoggingConfig       `mapstructure:"logging"`
+	WebSocket     WebSocketConfig     `mapstructure:"websocket"`
+	GraphQL       GraphQLConfig       `mapstructure:"graphql"`
+	PubSub        PubSubConfig        `mapstructure:"pubsub"`
+	SharedState   SharedStateConfig   `mapstructure:"shared_state"`
+	Observability ObservabilityConfig `mapstructure:"observability"`
+	UploadsDir    string              `mapstructure:"uploads_dir"`
 }
 
 // DatabaseConfig holds database configuration
@@ -37,6 +40,24 @@ type DatabaseConfig struct {
 	Password string `mapstructure:"password"`
 	Name     string `mapstructure:"name"`
 	SSLMode  string `mapstructure:"ssl_mode"`
+
+	// Read replicas for reports, analytics and history lists
+	Replicas                   []ReplicaConfig `mapstructure:"replicas"`
+	ReplicaMaxLag              time.Duration   `mapstructure:"replica_max_lag"`               // reads go to primary beyond this lag
+	ReplicaHealthCheckInterval time.Duration   `mapstructure:"replica_health_check_interval"` // how often health and lag are checked
+}
+
+// ReplicaConfig holds a read replica; empty credentials inherit the primary's
+type ReplicaConfig struct {
+	ID       string `mapstructure:"id"`
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	User     string `mapstructure:"user"`
+	Password string `mapstructure:"password"`
+	Name     string `mapstructure:"name"`
+	SSLMode  string `mapstructure:"ssl_mode"`
+	Weight   int    `mapstructure:"weight"`
+	Priority int    `mapstructure:"priority"`
 }
 
 // DSN returns the PostgreSQL connection string
@@ -120,7 +141,37 @@ type GraphQLConfig struct {
 	MaxQueryDepth        int           `mapstructure:"max_query_depth"`
 	MaxQueryFields       int           `mapstructure:"max_query_fields"`
 	MaxQueryCost         int           `mapstructure:"max_query_cost"`
+	RoleMaxQueryCost     string        `mapstructure:"role_max_query_cost"` // e.g. "MANDOR=5000,SUPER_ADMIN=50000"
 	SlowQueryThreshold   time.Duration `mapstructure:"slow_query_threshold"`
+
+	// Automatic persisted queries and the allow-list for mobile builds
+	PersistedQueriesEnabled bool          `mapstructure:"persisted_queries_enabled"`
+	PersistedQueryTTL       time.Duration `mapstructure:"persisted_query_ttl"`
+	AllowlistMode           string        `mapstructure:"allowlist_mode"`     // off, mobile or all
+	AllowlistManifest       string        `mapstructure:"allowlist_manifest"` // path to the persisted query manifest
+}
+
+// PubSubConfig selects how real-time events reach other server instances
+type PubSubConfig struct {
+	Driver  string `mapstructure:"driver"`  // memory (single instance) or postgres
+	Channel string `mapstructure:"channel"` // NOTIFY channel used by the postgres driver
+}
+
+// SharedStateConfig selects where caches and rate-limit counters live
+type SharedStateConfig struct {
+	Driver     string        `mapstructure:"driver"`      // memory (single instance) or postgres
+	GCInterval time.Duration `mapstructure:"gc_interval"` // how often expired rows are removed
+}
+
+// ObservabilityConfig holds Prometheus metrics and OpenTelemetry tracing settings
+type ObservabilityConfig struct {
+	MetricsEnabled     bool    `mapstructure:"metrics_enabled"`
+	MetricsPath        string  `mapstructure:"metrics_path"`
+	MetricsToken       string  `mapstructure:"metrics_token"` // bearer token required to scrape; empty allows any caller
+	TracingEnabled     bool    `mapstructure:"tracing_enabled"`
+	TracingEndpoint    string  `mapstructure:"tracing_endpoint"`     // OTLP/HTTP collector URL; empty records spans for log correlation only
+	TracingSampleRatio float64 `mapstructure:"tracing_sample_ratio"` // fraction of new traces recorded
+	ServiceName        string  `mapstructure:"service_name"`
 }
 
 // Load loads configuration using Viper from environment variables and config files
@@ -160,8 +211,31 @@ func Load() (*Config, error) {
 	viper.BindEnv("database.password", "AGRINOVA_DATABASE_PASSWORD")
 	viper.BindEnv("database.name", "AGRINOVA_DATABASE_NAME")
 	viper.BindEnv("database.ssl_mode", "AGRINOVA_DATABASE_SSL_MODE")
+	viper.BindEnv("database.replica_max_lag", "AGRINOVA_DATABASE_REPLICA_MAX_LAG")
 	viper.BindEnv("uploads_dir", "AGRINOVA_UPLOADS_DIR")
 
+	// Bind GraphQL hardening environment variables
+	viper.BindEnv("graphql.playground_enabled", "AGRINOVA_GRAPHQL_PLAYGROUND_ENABLED")
+	viper.BindEnv("graphql.introspection_enabled", "AGRINOVA_GRAPHQL_INTROSPECTION_ENABLED")
+	viper.BindEnv("graphql.role_max_query_cost", "AGRINOVA_GRAPHQL_ROLE_MAX_QUERY_COST")
+	viper.BindEnv("graphql.allowlist_mode", "AGRINOVA_GRAPHQL_ALLOWLIST_MODE")
+	viper.BindEnv("graphql.allowlist_manifest", "AGRINOVA_GRAPHQL_ALLOWLIST_MANIFEST")
+
+	// Bind pub/sub environment variables
+	viper.BindEnv("pubsub.driver", "AGRINOVA_PUBSUB_DRIVER")
+	viper.BindEnv("pubsub.channel", "AGRINOVA_PUBSUB_CHANNEL")
+
+	// Bind shared state environment variables
+	viper.BindEnv("shared_state.driver", "AGRINOVA_SHARED_STATE_DRIVER")
+	viper.BindEnv("shared_state.gc_interval", "AGRINOVA_SHARED_STATE_GC_INTERVAL")
+
+	// Bind observability environment variables
+	viper.BindEnv("observability.metrics_enabled", "AGRINOVA_OBSERVABILITY_METRICS_ENABLED")
+	viper.BindEnv("observability.metrics_token", "AGRINOVA_OBSERVABILITY_METRICS_TOKEN")
+	viper.BindEnv("observability.tracing_enabled", "AGRINOVA_OBSERVABILITY_TRACING_ENABLED")
+	viper.BindEnv("observability.tracing_endpoint", "AGRINOVA_OBSERVABILITY_TRACING_ENDPOINT")
+	viper.BindEnv("observability.tracing_sample_ratio", "AGRINOVA_OBSERVABILITY_TRACING_SAMPLE_RATIO")
+
 	// Read in config file if available
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
@@ -238,6 +312,8 @@ func setDefaults() {
 	viper.SetDefault("database.user", "postgres")
 	viper.SetDefault("database.name", "agrinova_go")
 	viper.SetDefault("database.ssl_mode", "disable")
+	viper.SetDefault("database.replica_max_lag", 30*time.Second)
+	viper.SetDefault("database.replica_health_check_interval", 10*time.Second)
 
 	// Server defaults
 	viper.SetDefault("server.port", 8080)
@@ -292,8 +368,32 @@ func setDefaults() {
 	viper.SetDefault("graphql.introspection_enabled", true)
 	viper.SetDefault("graphql.max_query_depth", 10)
 	viper.SetDefault("graphql.max_query_fields", 100)
-	viper.SetDefault("graphql.max_query_cost", 1000)
+	// Every selected field costs 1 and lists multiply their selection by the
+	// page size (10 when unbounded), so a 100-row page of ~40 fields is ~4000.
+	viper.SetDefault("graphql.max_query_cost", 10000)
+	viper.SetDefault("graphql.role_max_query_cost", "SUPER_ADMIN=50000,COMPANY_ADMIN=25000,AREA_MANAGER=25000,MANAGER=20000,ASISTEN=15000")
 	viper.SetDefault("graphql.slow_query_threshold", 5*time.Second)
+	viper.SetDefault("graphql.persisted_queries_enabled", true)
+	viper.SetDefault("graphql.persisted_query_ttl", 24*time.Hour)
+	viper.SetDefault("graphql.allowlist_mode", "off")
+	viper.SetDefault("graphql.allowlist_manifest", "")
+
+	// Pub/sub defaults; run multiple instances with driver "postgres"
+	viper.SetDefault("pubsub.driver", "memory")
+	viper.SetDefault("pubsub.channel", "agrinova_events")
+
+	// Shared state defaults; run multiple instances with driver "postgres"
+	viper.SetDefault("shared_state.driver", "memory")
+	viper.SetDefault("shared_state.gc_interval", time.Minute)
+
+	// Observability defaults; tracing is opt-in
+	viper.SetDefault("observability.metrics_enabled", true)
+	viper.SetDefault("observability.metrics_path", "/metrics")
+	viper.SetDefault("observability.metrics_token", "")
+	viper.SetDefault("observability.tracing_enabled", false)
+	viper.SetDefault("observability.tracing_endpoint", "")
+	viper.SetDefault("observability.tracing_sample_ratio", 0.1)
+	viper.SetDefault("observability.service_name", "agrinova-graphql")
 
 	// Storage defaults
 	viper.SetDefault("uploads_dir", "./uploads")
@@ -317,6 +417,14 @@ func validateConfig(config *Config) error {
 	if config.Database.Name == "" {
 		return fmt.Errorf("database name is required")
 	}
+	for i, replica := range config.Database.Replicas {
+		if replica.Host == "" {
+			return fmt.Errorf("database replica %d host is required", i+1)
+		}
+		if replica.Port < 0 || replica.Port > 65535 {
+			return fmt.Errorf("database replica %d port must be between 1 and 65535", i+1)
+		}
+	}
 
 	// Validate server configuration
 	if config.Server.Port <= 0 || config.Server.Port > 65535 {
@@ -341,6 +449,39 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("CSRF secret must be at least %d characters", minSecretLength)
 	}
 
+	// Validate GraphQL persisted query allow-list
+	switch config.GraphQL.AllowlistMode {
+	case "", "off":
+	case "mobile", "all":
+		if strings.TrimSpace(config.GraphQL.AllowlistManifest) == "" {
+			return fmt.Errorf("graphql allowlist_mode %q requires allowlist_manifest", config.GraphQL.AllowlistMode)
+		}
+	default:
+		return fmt.Errorf("graphql allowlist_mode must be off, mobile or all")
+	}
+
+	// Validate pub/sub driver
+	switch config.PubSub.Driver {
+	case "", "memory", "postgres":
+	default:
+		return fmt.Errorf("pubsub driver must be memory or postgres")
+	}
+
+	// Validate shared state driver
+	switch config.SharedState.Driver {
+	case "", "memory", "postgres":
+	default:
+		return fmt.Errorf("shared_state driver must be memory or postgres")
+	}
+
+	// Validate observability settings
+	if config.Observability.MetricsEnabled && !strings.HasPrefix(config.Observability.MetricsPath, "/") {
+		return fmt.Errorf("observability metrics_path must start with /")
+	}
+	if ratio := config.Observability.TracingSampleRatio; ratio < 0 || ratio > 1 {
+		return fmt.Errorf("observability tracing_sample_ratio must be between 0 and 1")
+	}
+
 	// Note: Using Argon2id with hardcoded secure defaults
 	// No bcrypt cost validation needed
 
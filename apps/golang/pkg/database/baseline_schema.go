@@ -0,0 +1,846 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+
+	authmodels "agrinovagraphql/server/internal/auth/models"
+	gatecheckmodels "agrinovagraphql/server/internal/gatecheck/models"
+	"agrinovagraphql/server/internal/graphql/domain/auth"
+	"agrinovagraphql/server/internal/graphql/domain/mandor"
+	"agrinovagraphql/server/internal/graphql/domain/master"
+	notificationmodels "agrinovagraphql/server/internal/notifications/models"
+)
+
+// migrateBaselineSchema creates the legacy tables, GORM models, indexes and
+// RBAC data that predate the numbered migrations. It is idempotent.
+func migrateBaselineSchema(db *gorm.DB) error {
+	// Import generated models for migration
+	// Use GORM AutoMigrate for proper schema generation
+	if err := db.AutoMigrate(
+		// Core business models (hierarchical order)
+		&master.Company{},
+		&auth.User{},
+		&master.Estate{},
+		&master.Division{},
+		&master.Block{},
+		&master.Vehicle{},
+		&master.VehicleTax{},
+		&master.VehicleTaxDocument{},
+		&master.VehicleTaxNotification{},
+		&mandor.HarvestRecord{},
+
+		// Multi-assignment models (created via SQL below)
+		// &master.UserEstateAssignment{},
+		// &master.UserDivisionAssignment{},
+		// &master.UserCompanyAssignment{},
+
+		// Authentication models
+		&authmodels.UserSession{},
+		&authmodels.DeviceBinding{},
+		&authmodels.JWTToken{},
+		&authmodels.PasswordReset{},
+		&authmodels.SecurityEvent{},
+		&authmodels.LoginAttempt{},
+		&authmodels.APIKey{},
+		&authmodels.APIKeyLog{},
+
+		// Gate check models
+		&gatecheckmodels.GateCheckRecord{},
+		&gatecheckmodels.GuestLog{},
+		&gatecheckmodels.QRToken{},
+		&gatecheckmodels.GateCheckPhoto{},
+
+		// Notification models
+		&notificationmodels.Notification{},
+		&notificationmodels.NotificationTemplate{},
+		&notificationmodels.NotificationPreferences{},
+		&notificationmodels.NotificationDelivery{},
+	); err != nil {
+		return fmt.Errorf("failed to auto-migrate tables: %w", err)
+	}
+
+	// Ensure id_card_number column exists in gate_guest_logs (fix for sync error)
+	if err := db.Exec(`
+		DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='gate_guest_logs' AND column_name='id_card_number') THEN
+				ALTER TABLE gate_guest_logs ADD COLUMN id_card_number VARCHAR(50);
+			END IF;
+		END $$;
+	`).Error; err != nil {
+		log.Printf("Note: Adding id_card_number column to gate_guest_logs may have issues: %v", err)
+	}
+
+	// Ensure cargo columns exist in gate_guest_logs (fix for sync error)
+	if err := db.Exec(`
+		DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='gate_guest_logs' AND column_name='cargo_volume') THEN
+				ALTER TABLE gate_guest_logs ADD COLUMN cargo_volume DOUBLE PRECISION;
+			END IF;
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='gate_guest_logs' AND column_name='cargo_owner') THEN
+				ALTER TABLE gate_guest_logs ADD COLUMN cargo_owner VARCHAR(255);
+			END IF;
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='gate_guest_logs' AND column_name='estimated_weight') THEN
+				ALTER TABLE gate_guest_logs ADD COLUMN estimated_weight DOUBLE PRECISION;
+			END IF;
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='gate_guest_logs' AND column_name='delivery_order_number') THEN
+				ALTER TABLE gate_guest_logs ADD COLUMN delivery_order_number VARCHAR(255);
+			END IF;
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='gate_guest_logs' AND column_name='second_cargo') THEN
+				ALTER TABLE gate_guest_logs ADD COLUMN second_cargo VARCHAR(255);
+			END IF;
+		END $$;
+	`).Error; err != nil {
+		log.Printf("Note: Adding cargo columns to gate_guest_logs may have issues: %v", err)
+	}
+
+	// Add password column to users table since auth.User doesn't have it
+	// This is needed because UserWithPassword and authentication require the password column
+	if err := db.Exec(`
+		DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns 
+				WHERE table_name='users' AND column_name='password') THEN
+				ALTER TABLE users ADD COLUMN password TEXT;
+			END IF;
+		END $$;
+	`).Error; err != nil {
+		log.Printf("Note: Adding password column may have issues: %v", err)
+	}
+
+	// Create legacy table structure for backward compatibility
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS companies (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			nama VARCHAR(255) NOT NULL,
+			alamat TEXT,
+			telepon VARCHAR(20),
+			logo_url TEXT,
+			status VARCHAR(20) DEFAULT 'ACTIVE',
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			deleted_at TIMESTAMP WITH TIME ZONE
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create companies table: %w", err)
+	}
+
+	if err := db.Exec(`ALTER TABLE companies ADD COLUMN IF NOT EXISTS logo_url TEXT`).Error; err != nil {
+		log.Printf("Note: Adding logo_url column may have issues: %v", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS users (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			username TEXT,
+			name TEXT,
+			email TEXT,
+			phone TEXT,
+			password TEXT NOT NULL,
+			role TEXT,
+			manager_id UUID,
+			is_active BOOLEAN DEFAULT true,
+			created_at TIMESTAMP WITH TIME ZONE,
+			updated_at TIMESTAMP WITH TIME ZONE,
+			deleted_at TIMESTAMP WITH TIME ZONE
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create users table: %w", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS estates (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			nama TEXT,
+			lokasi TEXT,
+			luas_ha DECIMAL,
+			company_id UUID NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE,
+			updated_at TIMESTAMP WITH TIME ZONE,
+			deleted_at TIMESTAMP WITH TIME ZONE
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create estates table: %w", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS divisions (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			nama TEXT,
+			kode TEXT,
+			estate_id UUID NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE,
+			updated_at TIMESTAMP WITH TIME ZONE,
+			deleted_at TIMESTAMP WITH TIME ZONE
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create divisions table: %w", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS land_types (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			code VARCHAR(50) NOT NULL UNIQUE,
+			name VARCHAR(100) NOT NULL,
+			description TEXT,
+			is_active BOOLEAN DEFAULT true,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create land_types table: %w", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS tarif_blok (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			company_id UUID NOT NULL,
+			perlakuan VARCHAR(100) NOT NULL,
+			keterangan TEXT,
+			land_type_id UUID,
+			tarif_code VARCHAR(20),
+			scheme_type VARCHAR(30),
+			bjr_min_kg NUMERIC(10,2),
+			bjr_max_kg NUMERIC(10,2),
+			target_lebih_kg NUMERIC(14,2),
+			sort_order INTEGER,
+			basis NUMERIC(14,2),
+			tarif_upah NUMERIC(14,2),
+			premi NUMERIC(14,2),
+			tarif_premi1 NUMERIC(14,2),
+			tarif_premi2 NUMERIC(14,2),
+			tarif_libur NUMERIC(14,2),
+			tarif_lebaran NUMERIC(14,2),
+			is_active BOOLEAN DEFAULT true,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			CONSTRAINT uq_tarif_blok_company_perlakuan UNIQUE (company_id, perlakuan)
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create tarif_blok table: %w", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS blocks (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			nama TEXT,
+			kode TEXT,
+			division_id UUID NOT NULL,
+			luas_ha DECIMAL,
+			status VARCHAR(10) DEFAULT 'INTI',
+			istm CHAR(1) DEFAULT 'N',
+			perlakuan VARCHAR(100),
+			land_type_id UUID,
+			tarif_blok_id UUID,
+			created_at TIMESTAMP WITH TIME ZONE,
+			updated_at TIMESTAMP WITH TIME ZONE,
+			deleted_at TIMESTAMP WITH TIME ZONE
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create blocks table: %w", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS harvest_records (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			local_id TEXT UNIQUE,
+			device_id TEXT,
+			tanggal TIMESTAMP WITH TIME ZONE,
+			mandor_id UUID NOT NULL,
+			company_id UUID,
+			estate_id UUID,
+			division_id UUID,
+			block_id UUID NOT NULL,
+			karyawan_id UUID,
+			nik TEXT,
+			employee_division_id UUID,
+			employee_division_name TEXT,
+			karyawan TEXT,
+			berat_tbs DECIMAL,
+			jumlah_janjang INTEGER,
+			status TEXT,
+			approved_by UUID,
+			approved_at TIMESTAMP WITH TIME ZONE,
+			rejected_reason TEXT,
+			notes TEXT,
+			latitude DOUBLE PRECISION,
+			longitude DOUBLE PRECISION,
+			photo_url TEXT,
+			created_at TIMESTAMP WITH TIME ZONE,
+			updated_at TIMESTAMP WITH TIME ZONE
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create harvest_records table: %w", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS gate_check_records (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			tanggal TIMESTAMP WITH TIME ZONE,
+			satpam_id UUID NOT NULL,
+			nopol_kendaraan TEXT,
+			nama_sopir TEXT,
+			nama_pks TEXT,
+			berat_masuk DECIMAL,
+			berat_keluar DECIMAL,
+			berat_bersih DECIMAL,
+			intent TEXT,
+			status TEXT,
+			approved_by UUID,
+			approved_at TIMESTAMP WITH TIME ZONE,
+			rejected_reason TEXT,
+			created_at TIMESTAMP WITH TIME ZONE,
+			updated_at TIMESTAMP WITH TIME ZONE
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create gate_check_records table: %w", err)
+	}
+
+	// Assignment models
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS user_estate_assignments (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id UUID NOT NULL,
+			estate_id UUID NOT NULL,
+			is_active BOOLEAN DEFAULT true,
+			assigned_by UUID NOT NULL,
+			assigned_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE,
+			updated_at TIMESTAMP WITH TIME ZONE
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create user_estate_assignments table: %w", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS user_division_assignments (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id UUID NOT NULL,
+			division_id UUID NOT NULL,
+			is_active BOOLEAN DEFAULT true,
+			assigned_by UUID NOT NULL,
+			assigned_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE,
+			updated_at TIMESTAMP WITH TIME ZONE
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create user_division_assignments table: %w", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS user_company_assignments (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id UUID NOT NULL,
+			company_id UUID NOT NULL,
+			is_active BOOLEAN DEFAULT true,
+			assigned_by UUID NOT NULL,
+			assigned_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE,
+			updated_at TIMESTAMP WITH TIME ZONE
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create user_company_assignments table: %w", err)
+	}
+
+	// Authentication and security models
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS user_sessions (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id UUID NOT NULL,
+			device_id TEXT,
+			session_token TEXT UNIQUE NOT NULL,
+			refresh_token TEXT,
+			platform VARCHAR(20) NOT NULL,
+			device_info JSON,
+			ip_address TEXT,
+			user_agent TEXT,
+			last_activity TIMESTAMP WITH TIME ZONE NOT NULL,
+			expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			is_active BOOLEAN DEFAULT true,
+			login_method VARCHAR(20) NOT NULL,
+			security_flags JSON,
+			created_at TIMESTAMP WITH TIME ZONE,
+			updated_at TIMESTAMP WITH TIME ZONE,
+			deleted_at TIMESTAMP WITH TIME ZONE
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create user_sessions table: %w", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS device_bindings (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id UUID NOT NULL,
+			device_id TEXT NOT NULL,
+			device_fingerprint TEXT NOT NULL,
+			platform TEXT NOT NULL,
+			trust_level TEXT DEFAULT 'UNTRUSTED',
+			is_trusted BOOLEAN DEFAULT false,
+			is_authorized BOOLEAN DEFAULT false,
+			last_seen_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE,
+			updated_at TIMESTAMP WITH TIME ZONE
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create device_bindings table: %w", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS jwt_tokens (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id UUID NOT NULL,
+			device_id TEXT NOT NULL,
+			token_type VARCHAR(20) NOT NULL,
+			token_hash TEXT UNIQUE NOT NULL,
+			refresh_hash TEXT UNIQUE,
+			offline_hash TEXT UNIQUE,
+			expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			refresh_expires_at TIMESTAMP WITH TIME ZONE,
+			offline_expires_at TIMESTAMP WITH TIME ZONE,
+			is_revoked BOOLEAN DEFAULT false,
+			revoked_at TIMESTAMP WITH TIME ZONE,
+			last_used_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE,
+			updated_at TIMESTAMP WITH TIME ZONE,
+			deleted_at TIMESTAMP WITH TIME ZONE
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create jwt_tokens table: %w", err)
+	}
+
+	// Add migration for existing user_sessions table to ensure all columns exist
+	if err := db.Exec(`
+		DO $$
+		BEGIN
+			-- Add session_token column if not exists
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='user_sessions' AND column_name='session_token') THEN
+				ALTER TABLE user_sessions ADD COLUMN session_token TEXT UNIQUE;
+			END IF;
+			
+			-- Add refresh_token column if not exists
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='user_sessions' AND column_name='refresh_token') THEN
+				ALTER TABLE user_sessions ADD COLUMN refresh_token TEXT;
+			END IF;
+			
+			-- Add platform column if not exists
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='user_sessions' AND column_name='platform') THEN
+				ALTER TABLE user_sessions ADD COLUMN platform VARCHAR(20);
+			END IF;
+			
+			-- Add device_info column if not exists
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='user_sessions' AND column_name='device_info') THEN
+				ALTER TABLE user_sessions ADD COLUMN device_info JSON;
+			END IF;
+			
+			-- Add last_activity column if not exists
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='user_sessions' AND column_name='last_activity') THEN
+				ALTER TABLE user_sessions ADD COLUMN last_activity TIMESTAMP WITH TIME ZONE;
+			END IF;
+			
+			-- Add login_method column if not exists
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='user_sessions' AND column_name='login_method') THEN
+				ALTER TABLE user_sessions ADD COLUMN login_method VARCHAR(20);
+			END IF;
+			
+			-- Add security_flags column if not exists
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='user_sessions' AND column_name='security_flags') THEN
+				ALTER TABLE user_sessions ADD COLUMN security_flags JSON;
+			END IF;
+			
+			-- Add deleted_at column if not exists
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='user_sessions' AND column_name='deleted_at') THEN
+				ALTER TABLE user_sessions ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+			END IF;
+		END $$;
+	`).Error; err != nil {
+		log.Printf("Note: User sessions table migration may have issues: %v", err)
+	}
+
+	// Add migration for existing jwt_tokens table to ensure all columns exist
+	if err := db.Exec(`
+		DO $$
+		BEGIN
+			-- Add refresh_hash column if not exists
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='jwt_tokens' AND column_name='refresh_hash') THEN
+				ALTER TABLE jwt_tokens ADD COLUMN refresh_hash TEXT UNIQUE;
+			END IF;
+			
+			-- Add offline_hash column if not exists
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='jwt_tokens' AND column_name='offline_hash') THEN
+				ALTER TABLE jwt_tokens ADD COLUMN offline_hash TEXT UNIQUE;
+			END IF;
+			
+			-- Add refresh_expires_at column if not exists
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='jwt_tokens' AND column_name='refresh_expires_at') THEN
+				ALTER TABLE jwt_tokens ADD COLUMN refresh_expires_at TIMESTAMP WITH TIME ZONE;
+			END IF;
+			
+			-- Add offline_expires_at column if not exists
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='jwt_tokens' AND column_name='offline_expires_at') THEN
+				ALTER TABLE jwt_tokens ADD COLUMN offline_expires_at TIMESTAMP WITH TIME ZONE;
+			END IF;
+			
+			-- Add revoked_at column if not exists
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='jwt_tokens' AND column_name='revoked_at') THEN
+				ALTER TABLE jwt_tokens ADD COLUMN revoked_at TIMESTAMP WITH TIME ZONE;
+			END IF;
+			
+			-- Add last_used_at column if not exists
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='jwt_tokens' AND column_name='last_used_at') THEN
+				ALTER TABLE jwt_tokens ADD COLUMN last_used_at TIMESTAMP WITH TIME ZONE;
+			END IF;
+			
+			-- Add deleted_at column if not exists
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='jwt_tokens' AND column_name='deleted_at') THEN
+				ALTER TABLE jwt_tokens ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+			END IF;
+		END $$;
+	`).Error; err != nil {
+		log.Printf("Note: JWT tokens table migration may have issues: %v", err)
+	}
+
+	// Add migration for existing security_events table to ensure all columns exist
+	if err := db.Exec(`
+		DO $$
+		BEGIN
+			-- Add details column if not exists
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='security_events' AND column_name='details') THEN
+				ALTER TABLE security_events ADD COLUMN details JSON;
+			END IF;
+			
+			-- Add deleted_at column if not exists
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='security_events' AND column_name='deleted_at') THEN
+				ALTER TABLE security_events ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+			END IF;
+		END $$;
+	`).Error; err != nil {
+		log.Printf("Note: Security events table migration may have issues: %v", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS security_events (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id UUID NOT NULL,
+			event_type VARCHAR(50) NOT NULL,
+			details JSON,
+			ip_address TEXT,
+			user_agent TEXT,
+			device_id VARCHAR(255),
+			severity VARCHAR(10) NOT NULL,
+			is_resolved BOOLEAN DEFAULT false,
+			resolved_by UUID,
+			resolved_at TIMESTAMP WITH TIME ZONE,
+			created_at TIMESTAMP WITH TIME ZONE,
+			updated_at TIMESTAMP WITH TIME ZONE,
+			deleted_at TIMESTAMP WITH TIME ZONE
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create security_events table: %w", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS login_attempts (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			username TEXT NOT NULL,
+			ip_address TEXT,
+			user_agent TEXT,
+			is_successful BOOLEAN DEFAULT false,
+			failure_reason TEXT,
+			attempted_at TIMESTAMP WITH TIME ZONE
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create login_attempts table: %w", err)
+	}
+
+	// Create RLS audit log table for security monitoring
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS harvest_rls_audit_log (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id UUID,
+			user_role VARCHAR(50),
+			action VARCHAR(20),
+			record_id UUID,
+			attempted_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			violation_type VARCHAR(100),
+			details JSONB,
+			ip_address INET,
+			user_agent TEXT
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create harvest_rls_audit_log table: %w", err)
+	}
+
+	// Create RLS context functions for PostgreSQL
+	if err := db.Exec(`
+		-- Function to set current user context (application level)
+		CREATE OR REPLACE FUNCTION app_set_user_context(
+			p_user_id UUID,
+			p_role VARCHAR(50),
+			p_company_ids UUID[],
+			p_estate_ids UUID[],
+			p_division_ids UUID[]
+		) RETURNS VOID AS $$
+		BEGIN
+			PERFORM set_config('app.user_id', p_user_id::TEXT, false);
+			PERFORM set_config('app.user_role', p_role, false);
+			PERFORM set_config('app.company_ids', array_to_string(p_company_ids, ','), false);
+			PERFORM set_config('app.estate_ids', array_to_string(p_estate_ids, ','), false);
+			PERFORM set_config('app.division_ids', array_to_string(p_division_ids, ','), false);
+		END;
+		$$ LANGUAGE plpgsql SECURITY DEFINER;
+
+		-- Function to clear user context
+		CREATE OR REPLACE FUNCTION app_clear_user_context() RETURNS VOID AS $$
+		BEGIN
+			PERFORM set_config('app.user_id', '', false);
+			PERFORM set_config('app.user_role', '', false);
+			PERFORM set_config('app.company_ids', '', false);
+			PERFORM set_config('app.estate_ids', '', false);
+			PERFORM set_config('app.division_ids', '', false);
+		END;
+		$$ LANGUAGE plpgsql SECURITY DEFINER;
+
+		-- Function to get current user ID from context
+		CREATE OR REPLACE FUNCTION app_get_user_id() RETURNS UUID AS $$
+		BEGIN
+			RETURN NULLIF(current_setting('app.user_id', true), '')::UUID;
+		EXCEPTION
+			WHEN OTHERS THEN RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql STABLE SECURITY DEFINER;
+
+		-- Function to get current user role from context
+		CREATE OR REPLACE FUNCTION app_get_user_role() RETURNS VARCHAR(50) AS $$
+		BEGIN
+			RETURN NULLIF(current_setting('app.user_role', true), '');
+		EXCEPTION
+			WHEN OTHERS THEN RETURN NULL;
+		END;
+		$$ LANGUAGE plpgsql STABLE SECURITY DEFINER;
+	`).Error; err != nil {
+		log.Printf("Note: RLS context functions may already exist: %v", err)
+	}
+
+	// Add employees table if not exists
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS employees (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			nik VARCHAR(50) NOT NULL,
+			name VARCHAR(100) NOT NULL,
+			role VARCHAR(50) NOT NULL,
+			company_id UUID NOT NULL,
+			division_id UUID,
+			photo_url TEXT,
+			is_active BOOLEAN DEFAULT true,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("failed to create employees table: %w", err)
+	}
+
+	// Add is_active column to blocks table if not exists
+	if err := db.Exec(`
+		DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='blocks' AND column_name='is_active') THEN
+				ALTER TABLE blocks ADD COLUMN is_active BOOLEAN DEFAULT true;
+			END IF;
+		END $$;
+	`).Error; err != nil {
+		log.Printf("Note: Adding is_active column to blocks may have issues: %v", err)
+	}
+
+	// Add division_id column to employees table if not exists
+	if err := db.Exec(`
+		DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='employees' AND column_name='division_id') THEN
+				ALTER TABLE employees ADD COLUMN division_id UUID;
+			END IF;
+		END $$;
+	`).Error; err != nil {
+		log.Printf("Note: Adding division_id column to employees may have issues: %v", err)
+	}
+
+	// Add id_card_number column to gate_guest_logs table if not exists
+	if err := db.Exec(`
+		DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name='gate_guest_logs' AND column_name='id_card_number') THEN
+				ALTER TABLE gate_guest_logs ADD COLUMN id_card_number VARCHAR(50);
+			END IF;
+		END $$;
+	`).Error; err != nil {
+		log.Printf("Note: Adding id_card_number column to gate_guest_logs may have issues: %v", err)
+	}
+
+	// Create index for employees division_id
+	if err := db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_employees_division_id ON employees(division_id);
+		CREATE INDEX IF NOT EXISTS idx_employees_company_id ON employees(company_id);
+		CREATE INDEX IF NOT EXISTS idx_employees_is_active ON employees(is_active);
+		CREATE INDEX IF NOT EXISTS idx_blocks_is_active ON blocks(is_active);
+	`).Error; err != nil {
+		log.Printf("Note: Creating indexes may have issues: %v", err)
+	}
+
+	// Add foreign key constraints after all tables are created.
+	type foreignKeyDef struct {
+		table      string
+		name       string
+		sql        string
+		label      string
+		requireRow bool
+	}
+
+	foreignKeys := []foreignKeyDef{
+		{table: "estates", name: "fk_companies_estates", sql: "ALTER TABLE estates ADD CONSTRAINT fk_companies_estates FOREIGN KEY (company_id) REFERENCES companies(id)", label: "estates", requireRow: true},
+		{table: "tarif_blok", name: "fk_tarif_blok_company", sql: "ALTER TABLE tarif_blok ADD CONSTRAINT fk_tarif_blok_company FOREIGN KEY (company_id) REFERENCES companies(id)", label: "tarif_blok company", requireRow: true},
+		{table: "tarif_blok", name: "fk_tarif_blok_land_type", sql: "ALTER TABLE tarif_blok ADD CONSTRAINT fk_tarif_blok_land_type FOREIGN KEY (land_type_id) REFERENCES land_types(id)", label: "tarif_blok land_type", requireRow: true},
+		{table: "divisions", name: "fk_estates_divisions", sql: "ALTER TABLE divisions ADD CONSTRAINT fk_estates_divisions FOREIGN KEY (estate_id) REFERENCES estates(id)", label: "divisions", requireRow: true},
+		{table: "user_estate_assignments", name: "fk_user_estate_assignments_user", sql: "ALTER TABLE user_estate_assignments ADD CONSTRAINT fk_user_estate_assignments_user FOREIGN KEY (user_id) REFERENCES users(id)", label: "user_estate_assignments user", requireRow: true},
+		{table: "user_estate_assignments", name: "fk_user_estate_assignments_estate", sql: "ALTER TABLE user_estate_assignments ADD CONSTRAINT fk_user_estate_assignments_estate FOREIGN KEY (estate_id) REFERENCES estates(id)", label: "user_estate_assignments estate", requireRow: true},
+		{table: "blocks", name: "fk_divisions_blocks", sql: "ALTER TABLE blocks ADD CONSTRAINT fk_divisions_blocks FOREIGN KEY (division_id) REFERENCES divisions(id)", label: "blocks", requireRow: true},
+		{table: "blocks", name: "fk_blocks_land_type", sql: "ALTER TABLE blocks ADD CONSTRAINT fk_blocks_land_type FOREIGN KEY (land_type_id) REFERENCES land_types(id)", label: "blocks land_type", requireRow: true},
+		{table: "user_division_assignments", name: "fk_user_division_assignments_user", sql: "ALTER TABLE user_division_assignments ADD CONSTRAINT fk_user_division_assignments_user FOREIGN KEY (user_id) REFERENCES users(id)", label: "user_division_assignments user", requireRow: true},
+		{table: "user_division_assignments", name: "fk_user_division_assignments_division", sql: "ALTER TABLE user_division_assignments ADD CONSTRAINT fk_user_division_assignments_division FOREIGN KEY (division_id) REFERENCES divisions(id)", label: "user_division_assignments division", requireRow: true},
+		{table: "harvest_records", name: "fk_harvest_records_block", sql: "ALTER TABLE harvest_records ADD CONSTRAINT fk_harvest_records_block FOREIGN KEY (block_id) REFERENCES blocks(id)", label: "harvest_records block", requireRow: true},
+		{table: "harvest_records", name: "fk_harvest_records_mandor", sql: "ALTER TABLE harvest_records ADD CONSTRAINT fk_harvest_records_mandor FOREIGN KEY (mandor_id) REFERENCES users(id)", label: "harvest_records mandor", requireRow: true},
+		{table: "gate_check_records", name: "fk_gate_check_records_satpam", sql: "ALTER TABLE gate_check_records ADD CONSTRAINT fk_gate_check_records_satpam FOREIGN KEY (satpam_id) REFERENCES users(id)", label: "gate_check_records satpam", requireRow: true},
+		{table: "user_company_assignments", name: "fk_user_company_assignments_user", sql: "ALTER TABLE user_company_assignments ADD CONSTRAINT fk_user_company_assignments_user FOREIGN KEY (user_id) REFERENCES users(id)", label: "user_company_assignments user", requireRow: true},
+		{table: "user_company_assignments", name: "fk_user_company_assignments_company", sql: "ALTER TABLE user_company_assignments ADD CONSTRAINT fk_user_company_assignments_company FOREIGN KEY (company_id) REFERENCES companies(id)", label: "user_company_assignments company", requireRow: true},
+		{table: "user_sessions", name: "fk_user_sessions_user", sql: "ALTER TABLE user_sessions ADD CONSTRAINT fk_user_sessions_user FOREIGN KEY (user_id) REFERENCES users(id)", label: "user_sessions", requireRow: true},
+		{table: "device_bindings", name: "fk_device_bindings_user", sql: "ALTER TABLE device_bindings ADD CONSTRAINT fk_device_bindings_user FOREIGN KEY (user_id) REFERENCES users(id)", label: "device_bindings", requireRow: true},
+		{table: "jwt_tokens", name: "fk_jwt_tokens_user", sql: "ALTER TABLE jwt_tokens ADD CONSTRAINT fk_jwt_tokens_user FOREIGN KEY (user_id) REFERENCES users(id)", label: "jwt_tokens", requireRow: true},
+		{table: "security_events", name: "fk_security_events_user", sql: "ALTER TABLE security_events ADD CONSTRAINT fk_security_events_user FOREIGN KEY (user_id) REFERENCES users(id)", label: "security_events", requireRow: true},
+	}
+
+	for _, fk := range foreignKeys {
+		ensureForeignKeyConstraint(db, fk.table, fk.name, fk.sql, fk.label)
+	}
+
+	// Create indexes
+	log.Println("Creating database indexes...")
+
+	// Company indexes
+	ensureIndexIfColumnExists(db, "companies", "deleted_at", "CREATE INDEX IF NOT EXISTS idx_companies_deleted_at ON companies(deleted_at)")
+
+	// User indexes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_users_username ON users(username)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_users_email ON users(email)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_users_active ON users(is_active)")
+	ensureIndexIfColumnExists(db, "users", "deleted_at", "CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at)")
+
+	// Estate indexes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_estates_company_id ON estates(company_id)")
+	ensureIndexIfColumnExists(db, "estates", "deleted_at", "CREATE INDEX IF NOT EXISTS idx_estates_deleted_at ON estates(deleted_at)")
+
+	// Division indexes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_divisions_estate_id ON divisions(estate_id)")
+	ensureIndexIfColumnExists(db, "divisions", "deleted_at", "CREATE INDEX IF NOT EXISTS idx_divisions_deleted_at ON divisions(deleted_at)")
+
+	// Block indexes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_blocks_division_id ON blocks(division_id)")
+	ensureIndexIfColumnExists(db, "blocks", "deleted_at", "CREATE INDEX IF NOT EXISTS idx_blocks_deleted_at ON blocks(deleted_at)")
+
+	// Harvest record indexes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_harvest_records_mandor_id ON harvest_records(mandor_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_harvest_records_block_id ON harvest_records(block_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_harvest_records_status ON harvest_records(status)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_harvest_records_tanggal ON harvest_records(tanggal)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_harvest_records_local_id ON harvest_records(local_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_harvest_records_device_id ON harvest_records(device_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_harvest_records_company_id ON harvest_records(company_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_harvest_records_estate_id ON harvest_records(estate_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_harvest_records_division_id ON harvest_records(division_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_harvest_records_karyawan_id ON harvest_records(karyawan_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_harvest_records_nik ON harvest_records(nik)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_harvest_records_employee_division_id ON harvest_records(employee_division_id)")
+
+	// Gate check record indexes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_gate_check_records_satpam_id ON gate_check_records(satpam_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_gate_check_records_status ON gate_check_records(status)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_gate_check_records_intent ON gate_check_records(intent)")
+
+	// Assignment indexes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_user_estate_assignments_user_id ON user_estate_assignments(user_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_user_estate_assignments_estate_id ON user_estate_assignments(estate_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_user_division_assignments_user_id ON user_division_assignments(user_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_user_division_assignments_division_id ON user_division_assignments(division_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_user_company_assignments_user_id ON user_company_assignments(user_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_user_company_assignments_company_id ON user_company_assignments(company_id)")
+
+	// Session indexes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_user_sessions_user_device ON user_sessions(user_id, device_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_user_sessions_active ON user_sessions(is_active)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_user_sessions_expires_at ON user_sessions(expires_at)")
+
+	// Device binding indexes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_device_bindings_user_id ON device_bindings(user_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_device_bindings_device_id ON device_bindings(device_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_device_bindings_authorized ON device_bindings(is_authorized)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_device_bindings_trusted ON device_bindings(is_trusted)")
+
+	// JWT Token indexes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_jwt_tokens_user_device ON jwt_tokens(user_id, device_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_jwt_tokens_hash ON jwt_tokens(token_hash)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_jwt_tokens_revoked ON jwt_tokens(is_revoked)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_jwt_tokens_expires_at ON jwt_tokens(expires_at)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_jwt_tokens_refresh_hash ON jwt_tokens(refresh_hash)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_jwt_tokens_offline_hash ON jwt_tokens(offline_hash)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_jwt_tokens_refresh_expires_at ON jwt_tokens(refresh_expires_at)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_jwt_tokens_offline_expires_at ON jwt_tokens(offline_expires_at)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_jwt_tokens_deleted_at ON jwt_tokens(deleted_at)")
+
+	// Security event indexes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_security_events_user_id ON security_events(user_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_security_events_type ON security_events(event_type)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_security_events_severity ON security_events(severity)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_security_events_resolved ON security_events(is_resolved)")
+
+	// Login attempt indexes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_login_attempts_username ON login_attempts(username)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_login_attempts_ip ON login_attempts(ip_address)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_login_attempts_successful ON login_attempts(is_successful)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_login_attempts_attempted_at ON login_attempts(attempted_at)")
+
+	// RLS audit log indexes
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_harvest_rls_audit_user ON harvest_rls_audit_log(user_id)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_harvest_rls_audit_date ON harvest_rls_audit_log(attempted_at)")
+	db.Exec("CREATE INDEX IF NOT EXISTS idx_harvest_rls_audit_violation ON harvest_rls_audit_log(violation_type)")
+
+	// Create RBAC schema and seed data
+	if err := CreateRBACSchema(db); err != nil {
+		return fmt.Errorf("failed to create RBAC schema: %w", err)
+	}
+
+	if err := SeedRBACData(db); err != nil {
+		return fmt.Errorf("failed to seed RBAC data: %w", err)
+	}
+
+	if err := MigrateStaticPermissionsToRBAC(db); err != nil {
+		return fmt.Errorf("failed to migrate static permissions: %w", err)
+	}
+
+	// Run role-specific migrations
+	if err := Migration0005_AddNewRoles(db); err != nil {
+		return fmt.Errorf("failed to add new roles: %w", err)
+	}
+
+	// Run Satpam POS tables migration
+	if err := MigrateSatpamTables(db); err != nil {
+		return fmt.Errorf("failed to migrate satpam tables: %w", err)
+	}
+
+	// Remove guest* columns from gate_guest_logs table
+	if err := RemoveGuestColumnsFromGateGuestLogs(db); err != nil {
+		return fmt.Errorf("failed to remove guest columns: %w", err)
+	}
+
+	return nil
+}
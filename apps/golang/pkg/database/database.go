@@ -39,10 +39,15 @@ type DatabaseService struct {
 	relationshipService *RelationshipService
 }
 
+// DSN returns the PostgreSQL connection string used by Connect
+func (config *DatabaseConfig) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=Asia/Jakarta",
+		config.Host, config.User, config.Password, config.DBName, config.Port, config.SSLMode)
+}
+
 // Connect initializes the database connection with proper configuration
 func Connect(config *DatabaseConfig) (*DatabaseService, error) {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=Asia/Jakarta",
-		config.Host, config.User, config.Password, config.DBName, config.Port, config.SSLMode)
+	dsn := config.DSN()
 
 	var err error
 	db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
@@ -129,21 +134,24 @@ func GetRelationshipService() *RelationshipService {
 
 // Initialize runs all database setup including migrations and relationship validation
 func (ds *DatabaseService) Initialize(ctx context.Context) error {
-	log.Println("Initializing database with GORM migrations...")
+	log.Println("Initializing database with versioned migrations...")
 
-	// Run GORM migrations
-	if err := AutoMigrateWithGORM(ds.db); err != nil {
-		return fmt.Errorf("failed to run GORM migrations: %w", err)
-	}
-
-	// Run legacy migrations for backward compatibility
 	if err := AutoMigrate(ds.db); err != nil {
 		if isProductionRuntime() {
-			return fmt.Errorf("legacy migrations failed in production runtime: %w", err)
+			return fmt.Errorf("migrations failed in production runtime: %w", err)
 		}
-		log.Printf("Warning: Legacy migrations failed (non-production runtime): %v", err)
+		log.Printf("Warning: Migrations failed (non-production runtime): %v", err)
 	}
 
+	ds.RunMaintenance(ctx)
+
+	log.Println("Database initialization completed successfully")
+	return nil
+}
+
+// RunMaintenance validates relationships and refreshes composite indexes and
+// query optimizations. Failures are logged, not returned.
+func (ds *DatabaseService) RunMaintenance(ctx context.Context) {
 	// Validate and fix relationships
 	if err := ds.relationshipService.ValidateAndFixRelationships(ctx); err != nil {
 		log.Printf("Warning: Relationship validation failed: %v", err)
@@ -158,9 +166,6 @@ func (ds *DatabaseService) Initialize(ctx context.Context) error {
 	if err := ds.relationshipService.OptimizeRelationshipQueries(ctx); err != nil {
 		log.Printf("Warning: Query optimization failed: %v", err)
 	}
-
-	log.Println("Database initialization completed successfully")
-	return nil
 }
 
 // GetStatistics returns database relationship statistics
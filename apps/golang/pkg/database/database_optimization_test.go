@@ -1,15 +1,12 @@
 package database
 
 import (
-	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"gorm.io/gorm"
-
-	"agrinovagraphql/server/internal/graphql/generated"
 )
 
 // TestReadReplicaManager tests the read replica manager functionality
@@ -55,8 +52,6 @@ func TestReadReplicaManager(t *testing.T) {
 
 // TestDatabaseContext tests database context functionality
 func TestDatabaseContext(t *testing.T) {
-	ctx := context.Background()
-
 	// Test read context
 	readCtx := DatabaseContext{
 		Type:      OperationTypeRead,
@@ -165,15 +160,6 @@ func TestEnhancedDatabaseService(t *testing.T) {
 	// This test requires a mock database connection
 	// In a real scenario, you would use a test database
 
-	config := &DatabaseConfig{
-		Host:     "localhost",
-		Port:     "5432",
-		User:     "test",
-		Password: "test",
-		DBName:   "test_db",
-		SSLMode:  "disable",
-	}
-
 	// Create a mock GORM database for testing
 	// Note: This will fail without an actual database, but tests the logic
 	// In production, you would set up a test database
@@ -0,0 +1,342 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"agrinovagraphql/server/pkg/database/migrations"
+	"agrinovagraphql/server/pkg/database/migrator"
+)
+
+// baselineSources are the files behind the baseline entries, hashed to
+// detect edits after they were applied.
+//
+//go:embed gorm_migrations.go baseline_schema.go rbac_migration.go migration_0005_add_new_roles.go migration_0007_satpam_tables.go
+var baselineSources embed.FS
+
+// NewMigrationRunner returns a runner over every versioned migration.
+func NewMigrationRunner(db *gorm.DB) *migrator.Runner {
+	return migrator.New(db, versionedMigrations())
+}
+
+// versionedMigrations lists migrations in apply order. New migrations go
+// before the legacy cutover block at the end.
+func versionedMigrations() []migrator.Migration {
+	return []migrator.Migration{
+		// Schema that predates the numbered migrations.
+		{Version: "000000", Name: "gorm_models", Up: migrationFunc(AutoMigrateWithGORM), Checksum: baselineSource("gorm_migrations.go")},
+		// Vehicle schema must be finalized before strict GORM model migration,
+		// otherwise non-null columns in the new model can fail on legacy rows.
+		numberedMigration("000030_create_vehicle_master_table", migrations.Migration000030CreateVehicleMasterTable),
+		numberedMigration("000031_finalize_vehicle_schema_and_tax_tables", migrations.Migration000031FinalizeVehicleSchemaAndTaxTables),
+		{Version: "000001", Name: "baseline_schema", Up: migrationFunc(migrateBaselineSchema), Checksum: baselineSource("baseline_schema.go", "rbac_migration.go", "migration_0005_add_new_roles.go", "migration_0007_satpam_tables.go")},
+
+		// Add cargo columns to gate_guest_logs table
+		numberedMigration("000013_add_cargo_columns", migrations.Migration000013AddCargoColumns),
+		// Add load_type column to gate_guest_logs table
+		numberedMigration("000014_add_load_type_column", migrations.Migration000014AddLoadTypeColumn),
+		// Add second_cargo column to gate_guest_logs table
+		numberedMigration("000015_add_second_cargo_column", migrations.Migration000015AddSecondCargoColumn),
+		// Remove photo_path column from gate_guest_logs table
+		numberedMigration("000016_remove_guest_log_photo_path", migrations.Migration000016RemoveGuestLogPhotoPath),
+		// Migration 000017 (refactor user schema) has been rolled back.
+		// Name/phone columns remain in the users table. See migration 000022.
+		// Enforce unique division assignments per user and cleanup historical duplicates.
+		numberedMigration("000018_enforce_unique_user_division_assignments", migrations.Migration000018EnforceUniqueUserDivisionAssignments),
+		// Enforce unique estate/company assignments per user and cleanup historical duplicates.
+		numberedMigration("000019_enforce_unique_estate_company_assignments", migrations.Migration000019EnforceUniqueEstateCompanyAssignments),
+		// Add users.avatar_url for profile avatar persistence.
+		numberedMigration("000020_add_user_avatar_column", migrations.Migration000020AddUserAvatarColumn),
+		// Backfill legacy OFFLINE jwt_tokens rows for consistent offline validation.
+		numberedMigration("000021_backfill_offline_jwt_tokens", migrations.Migration000021BackfillOfflineJWTTokens),
+		// Rollback migration 000017: restore name/phone to users table.
+		numberedMigration("000022_rollback_user_schema", migrations.Migration000022RollbackUserSchema),
+		// Add block status/istm/perlakuan and tarif_blok master relation.
+		numberedMigration("000023_add_block_tarif_metadata", migrations.Migration000023AddBlockTarifMetadata),
+		// Enforce tarif_blok uniqueness per company (company_id + perlakuan).
+		numberedMigration("000028_enforce_tarif_blok_per_company_unique", migrations.Migration000028EnforceTarifBlokPerCompanyUnique),
+		// Remove FK relation from gate_check_records to blocks (keep block_id as plain UUID).
+		numberedMigration("000029_remove_gatecheck_block_reference", migrations.Migration000029RemoveGateCheckBlockReference),
+		// Add nik column to harvest_records and backfill from karyawan.
+		numberedMigration("000032_add_harvest_records_nik_column", migrations.Migration000032AddHarvestRecordsNikColumn),
+		// Add harvest quality breakdown columns for sync payload compatibility.
+		numberedMigration("000033_add_harvest_quality_columns", migrations.Migration000033AddHarvestQualityColumns),
+		// Align harvest_records schema for legacy databases and remove deprecated asisten_id.
+		numberedMigration("000034_align_harvest_records_schema", migrations.Migration000034AlignHarvestRecordsSchema),
+		// Drop deprecated harvest_records.karyawan after nik/karyawan_id cutover.
+		numberedMigration("000035_drop_harvest_karyawan_column", migrations.Migration000035DropHarvestKaryawanColumn),
+		// Add harvest_records.device_id for sync source tracking.
+		numberedMigration("000036_add_harvest_device_id_column", migrations.Migration000036AddHarvestDeviceIDColumn),
+		// Add employee-origin division snapshot columns to harvest_records.
+		numberedMigration("000037_add_harvest_employee_division_columns", migrations.Migration000037AddHarvestEmployeeDivisionColumns),
+		// Enforce business uniqueness: company + day + block + worker must be unique.
+		numberedMigration("000038_enforce_unique_harvest_worker_scope", migrations.Migration000038EnforceUniqueHarvestWorkerScope),
+		// Create BKM sync tables (Oracle → PostgreSQL).
+		numberedMigration("000039_create_bkm_sync_tables", migrations.Migration000039CreateBkmSyncTables),
+		// Hard cutover: revoke all existing JWT-format OFFLINE tokens so users get
+		// new opaque tokens on next login (one-time migration).
+		numberedMigration("000040_migrate_offline_token_to_opaque", migrations.Migration000040MigrateOfflineTokenToOpaque),
+		// Add per-user unique constraints on local_id for idempotent sync pushes.
+		numberedMigration("000041_add_sync_idempotency_constraints", migrations.Migration000041AddSyncIdempotencyConstraints),
+		// Add partial index on jwt_tokens.offline_hash for fast deviceRenew lookups.
+		numberedMigration("000042_add_offline_token_index", migrations.Migration000042AddOfflineTokenIndex),
+		// Create manager division production budget table with unique division+period constraint.
+		numberedMigration("000043_create_manager_division_production_budgets", migrations.Migration000043CreateManagerDivisionProductionBudgets),
+		// Add targeted indexes for BKM report query hot paths.
+		numberedMigration("000044_optimize_bkm_report_indexes", migrations.Migration000044OptimizeBkmReportIndexes),
+		// Add notification idempotency and delivery tracking indexes.
+		numberedMigration("000045_optimize_notification_idempotency_and_delivery", migrations.Migration000045OptimizeNotificationIdempotencyAndDelivery),
+		// Create bridge mapping table for BKM source identifiers to companies.
+		numberedMigration("000046_create_bkm_company_bridge", migrations.Migration000046CreateBkmCompanyBridge),
+		// Add tarif_blok.keterangan and seed supplier BJR tarif templates.
+		numberedMigration("000047_seed_tarif_blok_bjr_template", migrations.Migration000047SeedTarifBlokBJRTemplate),
+		// Add land_types master and enforce land_type_id FK on blocks/tarif_blok.
+		numberedMigration("000048_add_land_types_and_tarif_block_alignment", migrations.Migration000048AddLandTypesAndTarifBlockAlignment),
+		// Collapse land_types into two grouped types as business taxonomy.
+		numberedMigration("000049_collapse_land_types_to_two_groups", migrations.Migration000049CollapseLandTypesToTwoGroups),
+		// Create normalized tariff schema tables (header/detail/override) and backfill from tarif_blok.
+		numberedMigration("000050_create_tariff_scheme_tables", migrations.Migration000050CreateTariffSchemeTables),
+		// Remove physical tarif_blok table and keep compatibility through view + triggers.
+		numberedMigration("000051_drop_tarif_blok_table_create_view", migrations.Migration000051DropTarifBlokTableCreateView),
+		// Track tariff changes per block with audit logs and reporting views.
+		numberedMigration("000052_create_block_tariff_change_logs", migrations.Migration000052CreateBlockTariffChangeLogs),
+		// Create manager block production budget table with unique block+period constraint.
+		numberedMigration("000053_create_manager_block_production_budgets", migrations.Migration000053CreateManagerBlockProductionBudgets),
+		// Add workflow status and override controls for division budget control tower.
+		numberedMigration("000054_add_manager_division_budget_workflow", migrations.Migration000054AddManagerDivisionBudgetWorkflow),
+		// Add workflow status controls for manager block budget proposals and approvals.
+		numberedMigration("000055_add_manager_block_budget_workflow", migrations.Migration000055AddManagerBlockBudgetWorkflow),
+		// Add targeted covering partial index for mandor approval status pull sync.
+		numberedMigration("000056_optimize_mandor_server_updates_index", migrations.Migration000056OptimizeMandorServerUpdatesIndex),
+		// Create employee access sync table for satpam syncEmployeeLog.
+		numberedMigration("000057_create_gate_employee_logs", migrations.Migration000057CreateGateEmployeeLogs),
+		// Add composite lookup index for satpam guest log sync by company + local_id.
+		numberedMigration("000058_optimize_satpam_guest_log_sync_index", migrations.Migration000058OptimizeSatpamGuestLogSyncIndex),
+		// Create durable outbox storage for satpam sync summary notifications.
+		numberedMigration("000059_create_satpam_notification_outbox", migrations.Migration000059CreateSatpamNotificationOutbox),
+		// Add a partial composite index for area manager harvest rollups by estate + date.
+		numberedMigration("000060_optimize_area_manager_harvest_rollups", migrations.Migration000060OptimizeAreaManagerHarvestRollups),
+		// Seed dummy production budget records so Manager Dashboard target widget shows data.
+		numberedMigration("000061_seed_dummy_budget_data", migrations.Migration000061SeedDummyBudgetData),
+		// Add explicit mandor subtype for dashboard and transaction branching.
+		numberedMigration("000062_add_mandor_type_to_user_company_assignments", migrations.Migration000062AddMandorTypeToUserCompanyAssignments),
+		// Create maintenance transaction storage for MANDOR_PERAWATAN.
+		numberedMigration("000063_create_perawatan_records", migrations.Migration000063CreatePerawatanRecords),
+		// Create fertilizer and herbicide usage tables for maintenance records.
+		numberedMigration("000064_create_perawatan_usage_tables", migrations.Migration000064CreatePerawatanUsageTables),
+		// Create block treatment semester workflow + tariff management decision audit tables.
+		numberedMigration("000065_create_block_treatment_request_workflow", migrations.Migration000065CreateBlockTreatmentRequestWorkflow),
+		// Create runtime theme campaign tables and seed baseline theme campaign data.
+		numberedMigration("000066_create_theme_campaign_tables", migrations.Migration000066CreateThemeCampaignTables),
+		// Refactor theme campaign architecture: shared campaign rules + platform-specific visual assets.
+		numberedMigration("000067_refactor_theme_campaign_shared_rules", migrations.Migration000067RefactorThemeCampaignSharedRules),
+		// Remove audience targeting columns so theme campaigns are global-only at runtime.
+		numberedMigration("000068_remove_theme_campaign_targeting", migrations.Migration000068RemoveThemeCampaignTargeting),
+		// Backfill seeded Ramadan Core campaign visual assets with local dummy URLs for web/mobile.
+		numberedMigration("000069_backfill_ramadan_core_campaign_assets", migrations.Migration000069BackfillRamadanCoreCampaignAssets),
+		// Backfill seeded Harvest Week campaign visual assets with local dummy URLs for web/mobile.
+		numberedMigration("000070_backfill_harvest_week_campaign_assets", migrations.Migration000070BackfillHarvestWeekCampaignAssets),
+		// Ensure Harvest Week campaign keeps complete visual assets without overriding valid values.
+		numberedMigration("000071_ensure_harvest_week_campaign_visual_assets_complete", migrations.Migration000071EnsureHarvestWeekCampaignVisualAssetsComplete),
+		// Optimize runtime theme campaign lookup for active endpoint traffic.
+		numberedMigration("000072_optimize_theme_runtime_campaign_lookup", migrations.Migration000072OptimizeThemeRuntimeCampaignLookup),
+		// Rename theme asset keys to canonical names (backgroundImage + illustration).
+		numberedMigration("000073_rename_theme_asset_keys", migrations.Migration000073RenameThemeAssetKeys),
+		// Remove legacy theme asset URL prefixes and standardize to /uploads/theme-assets/*.
+		numberedMigration("000074_remove_theme_dummy_asset_paths", migrations.Migration000074RemoveThemeDummyAssetPaths),
+		// Add forgot-password schema (users.email_verified + password_resets table + email unique partial index).
+		numberedMigration("000075_add_forgot_password_support", migrations.Migration000075AddForgotPasswordSupport),
+		// Add per-company settings plus lockout/password-age/escalation columns used to enforce them.
+		numberedMigration("000076_create_company_settings", migrations.Migration000076CreateCompanySettings),
+		// Add tenant subscription plans (limits, trial expiry, storage usage, suspension).
+		numberedMigration("000077_create_company_plans", migrations.Migration000077CreateCompanyPlans),
+		// Add the company admin activity audit trail.
+		numberedMigration("000078_create_admin_activity_logs", migrations.Migration000078CreateAdminActivityLogs),
+		// Store the employee number (NIK) of users created by bulk import.
+		numberedMigration("000079_add_user_nik_column", migrations.Migration000079AddUserNIKColumn),
+		// Track asynchronous master data spreadsheet imports.
+		numberedMigration("000080_create_master_data_import_jobs", migrations.Migration000080CreateMasterDataImportJobs),
+		// Cron job definitions and run history for the leader-elected scheduler.
+		numberedMigration("000081_create_scheduled_jobs", migrations.Migration000081CreateScheduledJobs),
+		// Email channel for notifications: digest preferences, email templates and dispatch job.
+		numberedMigration("000082_add_notification_email_channel", migrations.Migration000082AddNotificationEmailChannel),
+		// Company-configurable notification routing rules with escalation chains.
+		numberedMigration("000083_create_notification_routing_rules", migrations.Migration000083CreateNotificationRoutingRules),
+		// Attendance report indexes for employee gate scans and harvest cross-checks.
+		numberedMigration("000084_add_gate_employee_attendance_indexes", migrations.Migration000084AddGateEmployeeAttendanceIndexes),
+		// Company-scoped guest and vehicle watchlist checked at the gate.
+		numberedMigration("000085_create_gate_watchlist", migrations.Migration000085CreateGateWatchlist),
+		// Per-company maximum stay rules and the automatic overstay detector.
+		numberedMigration("000086_add_gate_overstay_detection", migrations.Migration000086AddGateOverstayDetection),
+		// Company-scoped themes and campaigns with role and platform targeting.
+		numberedMigration("000087_add_theme_company_targeting", migrations.Migration000087AddThemeCompanyTargeting),
+		// Enforce harvest row-level security so Mandor can only access own records.
+		{
+			Version:  "000088",
+			Name:     "enforce_harvest_rls_policies",
+			Up:       migrationFunc(migrations.Migration000088EnforceHarvestRLSPolicies),
+			Down:     migrationFunc(migrations.Migration000088EnforceHarvestRLSPoliciesDown),
+			Checksum: migrationSource("000088_enforce_harvest_rls_policies"),
+		},
+		// Resumable chunked photo uploads with hash dedup and thumbnails.
+		{
+			Version:  "000089",
+			Name:     "create_photo_upload_sessions",
+			Up:       migrationFunc(migrations.Migration000089CreatePhotoUploadSessions),
+			Down:     migrationFunc(migrations.Migration000089CreatePhotoUploadSessionsDown),
+			Checksum: migrationSource("000089_create_photo_upload_sessions"),
+		},
+		// EXIF verification of harvest and gate photos for approvers.
+		{
+			Version:  "000090",
+			Name:     "create_photo_verifications",
+			Up:       migrationFunc(migrations.Migration000090CreatePhotoVerifications),
+			Down:     migrationFunc(migrations.Migration000090CreatePhotoVerificationsDown),
+			Checksum: migrationSource("000090_create_photo_verifications"),
+		},
+		// UNLOGGED cache and rate-limit tables shared by all instances.
+		{
+			Version:  "000091",
+			Name:     "create_shared_state_tables",
+			Up:       migrationFunc(migrations.Migration000091CreateSharedStateTables),
+			Down:     migrationFunc(migrations.Migration000091CreateSharedStateTablesDown),
+			Checksum: migrationSource("000091_create_shared_state_tables"),
+		},
+		// Harvest approval escalation runs as a scheduler job.
+		{
+			Version:  "000092",
+			Name:     "add_harvest_approval_escalation_job",
+			Up:       migrationFunc(migrations.Migration000092AddHarvestApprovalEscalationJob),
+			Down:     migrationFunc(migrations.Migration000092AddHarvestApprovalEscalationJobDown),
+			Checksum: migrationSource("000092_add_harvest_approval_escalation_job"),
+		},
+		// Global jobs follow company timezones; session cleanup and the satpam
+		// notification outbox run as scheduler jobs.
+		{
+			Version:  "000093",
+			Name:     "use_company_timezones_for_scheduled_jobs",
+			Up:       migrationFunc(migrations.Migration000093UseCompanyTimezonesForScheduledJobs),
+			Down:     migrationFunc(migrations.Migration000093UseCompanyTimezonesForScheduledJobsDown),
+			Checksum: migrationSource("000093_use_company_timezones_for_scheduled_jobs"),
+		},
+		// Vehicle tax reminders record their penalty estimate separately.
+		{
+			Version:  "000094",
+			Name:     "add_vehicle_tax_penalty_estimate",
+			Up:       migrationFunc(migrations.Migration000094AddVehicleTaxPenaltyEstimate),
+			Down:     migrationFunc(migrations.Migration000094AddVehicleTaxPenaltyEstimateDown),
+			Checksum: migrationSource("000094_add_vehicle_tax_penalty_estimate"),
+		},
+		// Deferred email deliveries wait for next_attempt_at.
+		{
+			Version:  "000095",
+			Name:     "add_notification_delivery_next_attempt",
+			Up:       migrationFunc(migrations.Migration000095AddNotificationDeliveryNextAttempt),
+			Down:     migrationFunc(migrations.Migration000095AddNotificationDeliveryNextAttemptDown),
+			Checksum: migrationSource("000095_add_notification_delivery_next_attempt"),
+		},
+		// Routed approval events remember their subject for escalation.
+		{
+			Version:  "000096",
+			Name:     "add_notification_routing_dispatch_subject",
+			Up:       migrationFunc(migrations.Migration000096AddNotificationRoutingDispatchSubject),
+			Down:     migrationFunc(migrations.Migration000096AddNotificationRoutingDispatchSubjectDown),
+			Checksum: migrationSource("000096_add_notification_routing_dispatch_subject"),
+		},
+		// Expired photo uploads are purged by a scheduler job.
+		{
+			Version:  "000097",
+			Name:     "seed_photo_upload_cleanup_job",
+			Up:       migrationFunc(migrations.Migration000097SeedPhotoUploadCleanupJob),
+			Down:     migrationFunc(migrations.Migration000097SeedPhotoUploadCleanupJobDown),
+			Checksum: migrationSource("000097_seed_photo_upload_cleanup_job"),
+		},
+
+		// Legacy cutover runs after the numbered migrations. Only sync when
+		// legacy master columns still exist.
+		{Version: "000024", Name: "cutover_legacy_schema", Up: migrationFunc(migrateLegacyCutoverSync), Checksum: migrationSource("000024_cutover_legacy_schema")},
+		// Finalize legacy schema removal. Includes parity validation internally.
+		numberedMigration("000026_finalize_drop_legacy_schema", migrations.Migration000026FinalizeDropLegacySchema),
+		// Finalize legacy users schema removal.
+		numberedMigration("000027_finalize_drop_legacy_user_schema", migrations.Migration000027FinalizeDropLegacyUserSchema),
+
+		// Reversible migrations that are not part of the default chain. They
+		// enable row-level security and the features/RBAC schema, so they
+		// only run with an explicit manual apply. 000017 is superseded by
+		// 000022 and not registered.
+		structMigration(&migrations.Migration000005CreateFeaturesSchema{}),
+		structMigration(&migrations.Migration000006SeedSystemFeatures{}),
+		structMigration(&migrations.Migration000007ImplementHarvestRLS{}),
+		structMigration(&migrations.Migration000008OptimizeHarvestIndexes{}),
+		structMigration(&migrations.Migration000009ImplementGateCheckRLS{}),
+		structMigration(&migrations.Migration000010ImplementCompanyUserRLS{}),
+		structMigration(&migrations.Migration000011CreateRBACTables{}),
+		structMigration(&migrations.Migration000012CreateSecurityAuditLogs{}),
+	}
+}
+
+// reversibleMigration is implemented by the struct based migrations.
+type reversibleMigration interface {
+	Version() string
+	Name() string
+	Up(ctx context.Context, db *gorm.DB) error
+	Down(ctx context.Context, db *gorm.DB) error
+}
+
+func migrationFunc(fn func(db *gorm.DB) error) func(ctx context.Context, db *gorm.DB) error {
+	return func(ctx context.Context, db *gorm.DB) error {
+		return fn(db.WithContext(ctx))
+	}
+}
+
+// numberedMigration registers a function migration by its file stem, e.g.
+// "000087_add_theme_company_targeting".
+func numberedMigration(stem string, fn func(db *gorm.DB) error) migrator.Migration {
+	version, name := stem, ""
+	if len(stem) > 7 && stem[6] == '_' {
+		version, name = stem[:6], stem[7:]
+	}
+	return migrator.Migration{
+		Version:  version,
+		Name:     name,
+		Up:       migrationFunc(fn),
+		Checksum: migrationSource(stem),
+	}
+}
+
+func structMigration(m reversibleMigration) migrator.Migration {
+	return migrator.Migration{
+		Version:  m.Version(),
+		Name:     m.Name(),
+		Manual:   true,
+		Up:       m.Up,
+		Down:     m.Down,
+		Checksum: migrationSource(m.Version() + "_" + m.Name()),
+	}
+}
+
+func migrationSource(stem string) func() (string, error) {
+	return func() (string, error) {
+		return migrations.SourceChecksum(stem)
+	}
+}
+
+func baselineSource(names ...string) func() (string, error) {
+	return func() (string, error) {
+		hash := sha256.New()
+		for _, name := range names {
+			content, err := baselineSources.ReadFile(name)
+			if err != nil {
+				return "", fmt.Errorf("read baseline source %s: %w", name, err)
+			}
+			hash.Write([]byte(name))
+			hash.Write(content)
+		}
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	}
+}
@@ -0,0 +1,97 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000076CreateCompanySettings creates per-company settings storage and
+// the user/harvest columns needed to enforce them (login lockout, password
+// expiry, pending approval escalation).
+func Migration000076CreateCompanySettings(db *gorm.DB) error {
+	log.Println("Running migration: 000076_create_company_settings")
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS company_settings (
+			company_id UUID PRIMARY KEY REFERENCES companies(id) ON DELETE CASCADE,
+			timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Jakarta',
+			date_format VARCHAR(32) NOT NULL DEFAULT 'DD/MM/YYYY',
+			currency VARCHAR(8) NOT NULL DEFAULT 'IDR',
+			language VARCHAR(8) NOT NULL DEFAULT 'id',
+			email_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+			push_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			sms_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+			daily_report_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+			production_below_target DOUBLE PRECISION NOT NULL DEFAULT 80,
+			quality_below_threshold DOUBLE PRECISION NOT NULL DEFAULT 90,
+			pending_approval_hours INTEGER NOT NULL DEFAULT 0,
+			session_timeout_minutes INTEGER NOT NULL DEFAULT 0,
+			max_failed_logins INTEGER NOT NULL DEFAULT 0,
+			password_expiry_days INTEGER NOT NULL DEFAULT 0,
+			two_factor_required BOOLEAN NOT NULL DEFAULT FALSE,
+			allowed_ip_ranges_json TEXT NOT NULL DEFAULT '[]',
+			default_shift_start VARCHAR(5) NOT NULL DEFAULT '06:00',
+			default_shift_end VARCHAR(5) NOT NULL DEFAULT '14:00',
+			auto_approve_threshold DOUBLE PRECISION,
+			require_gps_for_harvest BOOLEAN NOT NULL DEFAULT FALSE,
+			require_photo_for_harvest BOOLEAN NOT NULL DEFAULT FALSE,
+			updated_by UUID,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000076 failed to create company_settings table: %w", err)
+	}
+
+	if err := tx.Exec(`
+		ALTER TABLE users
+		ADD COLUMN IF NOT EXISTS failed_login_attempts INTEGER NOT NULL DEFAULT 0,
+		ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ NULL,
+		ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMPTZ NULL;
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000076 failed to add users lockout columns: %w", err)
+	}
+
+	// Existing users start their password age at migration time instead of
+	// being expired immediately when a company enables passwordExpiryDays.
+	if err := tx.Exec(`
+		UPDATE users SET password_changed_at = NOW()
+		WHERE password_changed_at IS NULL;
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000076 failed to backfill users.password_changed_at: %w", err)
+	}
+
+	if err := tx.Exec(`
+		ALTER TABLE harvest_records
+		ADD COLUMN IF NOT EXISTS approval_escalated_at TIMESTAMPTZ NULL;
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000076 failed to add harvest_records.approval_escalated_at: %w", err)
+	}
+
+	if err := tx.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_harvest_records_pending_escalation
+		ON harvest_records(company_id, created_at)
+		WHERE status = 'PENDING' AND approval_escalated_at IS NULL;
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000076 failed to create pending escalation index: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("migration 000076 commit failed: %w", err)
+	}
+
+	log.Println("Migration 000076 completed successfully")
+	return nil
+}
@@ -0,0 +1,63 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000077CreateCompanyPlans stores the tenant subscription plan, trial
+// expiry, quota limits, storage usage counter and suspension metadata per company.
+func Migration000077CreateCompanyPlans(db *gorm.DB) error {
+	log.Println("Running migration: 000077_create_company_plans")
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS company_plans (
+			company_id UUID PRIMARY KEY REFERENCES companies(id) ON DELETE CASCADE,
+			plan_type VARCHAR(20) NOT NULL DEFAULT 'ENTERPRISE',
+			plan_name VARCHAR(100) NOT NULL DEFAULT 'Enterprise',
+			start_date TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			end_date TIMESTAMPTZ NULL,
+			trial_ends_at TIMESTAMPTZ NULL,
+			max_users INTEGER NOT NULL DEFAULT 0,
+			max_estates INTEGER NOT NULL DEFAULT 0,
+			max_storage_gb DOUBLE PRECISION NOT NULL DEFAULT 0,
+			features_enabled_json TEXT NOT NULL DEFAULT '[]',
+			storage_used_bytes BIGINT NOT NULL DEFAULT 0,
+			suspended_at TIMESTAMPTZ NULL,
+			suspended_reason TEXT NULL,
+			suspended_by UUID NULL,
+			updated_by UUID NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			CONSTRAINT chk_company_plans_plan_type CHECK (
+				plan_type IN ('TRIAL', 'BASIC', 'STANDARD', 'PREMIUM', 'ENTERPRISE', 'CUSTOM')
+			)
+		);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000077 failed to create company_plans table: %w", err)
+	}
+
+	if err := tx.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_company_plans_trial_ends_at
+		ON company_plans(trial_ends_at)
+		WHERE trial_ends_at IS NOT NULL;
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000077 failed to create trial expiry index: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("migration 000077 commit failed: %w", err)
+	}
+
+	log.Println("Migration 000077 completed successfully")
+	return nil
+}
@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000078CreateAdminActivityLogs stores the company-scoped audit trail
+// written by company admin user management and settings changes.
+func Migration000078CreateAdminActivityLogs(db *gorm.DB) error {
+	log.Println("Running migration: 000078_create_admin_activity_logs")
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS admin_activity_logs (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+			activity_type VARCHAR(40) NOT NULL,
+			actor_id UUID NOT NULL,
+			actor_name VARCHAR(255) NOT NULL DEFAULT '',
+			description TEXT NOT NULL,
+			entity_type VARCHAR(50) NULL,
+			entity_id VARCHAR(100) NULL,
+			ip_address VARCHAR(64) NULL,
+			metadata_json TEXT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000078 failed to create admin_activity_logs table: %w", err)
+	}
+
+	indexes := []string{
+		`CREATE INDEX IF NOT EXISTS idx_admin_activity_logs_company_created
+		 ON admin_activity_logs(company_id, created_at DESC);`,
+		`CREATE INDEX IF NOT EXISTS idx_admin_activity_logs_company_type
+		 ON admin_activity_logs(company_id, activity_type, created_at DESC);`,
+		`CREATE INDEX IF NOT EXISTS idx_admin_activity_logs_entity
+		 ON admin_activity_logs(entity_type, entity_id);`,
+	}
+	for _, statement := range indexes {
+		if err := tx.Exec(statement).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration 000078 failed to create admin_activity_logs index: %w", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("migration 000078 commit failed: %w", err)
+	}
+
+	log.Println("Migration 000078 completed successfully")
+	return nil
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000079AddUserNIKColumn adds the employee number (NIK) captured by
+// the bulk user import.
+func Migration000079AddUserNIKColumn(db *gorm.DB) error {
+	log.Println("Running migration: 000079_add_user_nik_column")
+
+	if err := db.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS nik VARCHAR(50) NULL;`).Error; err != nil {
+		return fmt.Errorf("migration 000079 failed to add users.nik: %w", err)
+	}
+
+	if err := db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_users_nik
+		ON users (LOWER(nik))
+		WHERE nik IS NOT NULL;
+	`).Error; err != nil {
+		return fmt.Errorf("migration 000079 failed to create idx_users_nik: %w", err)
+	}
+
+	log.Println("Migration 000079 completed successfully")
+	return nil
+}
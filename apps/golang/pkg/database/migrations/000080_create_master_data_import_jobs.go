@@ -0,0 +1,59 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000080CreateMasterDataImportJobs stores the status of asynchronous
+// estate/division/block and employee spreadsheet imports.
+func Migration000080CreateMasterDataImportJobs(db *gorm.DB) error {
+	log.Println("Running migration: 000080_create_master_data_import_jobs")
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS master_data_import_jobs (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+			import_type VARCHAR(20) NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+			file_name VARCHAR(255) NOT NULL DEFAULT '',
+			total_rows INTEGER NOT NULL DEFAULT 0,
+			processed_rows INTEGER NOT NULL DEFAULT 0,
+			created_count INTEGER NOT NULL DEFAULT 0,
+			updated_count INTEGER NOT NULL DEFAULT 0,
+			failed_rows INTEGER NOT NULL DEFAULT 0,
+			errors_json TEXT NOT NULL DEFAULT '[]',
+			message TEXT NULL,
+			created_by UUID NOT NULL,
+			started_at TIMESTAMPTZ NULL,
+			finished_at TIMESTAMPTZ NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000080 failed to create master_data_import_jobs table: %w", err)
+	}
+
+	if err := tx.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_master_data_import_jobs_company_created
+		ON master_data_import_jobs(company_id, created_at DESC);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000080 failed to create master_data_import_jobs index: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("migration 000080 commit failed: %w", err)
+	}
+
+	log.Println("Migration 000080 completed successfully")
+	return nil
+}
@@ -0,0 +1,113 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000081CreateScheduledJobs creates the cron job definitions and run
+// history used by the leader-elected scheduler, and seeds the built-in jobs.
+func Migration000081CreateScheduledJobs(db *gorm.DB) error {
+	log.Println("Running migration: 000081_create_scheduled_jobs")
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS scheduled_jobs (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			name VARCHAR(100) NOT NULL,
+			description TEXT NULL,
+			cron_expression VARCHAR(100) NOT NULL,
+			timezone VARCHAR(64) NULL,
+			company_id UUID NULL REFERENCES companies(id) ON DELETE CASCADE,
+			is_paused BOOLEAN NOT NULL DEFAULT FALSE,
+			next_run_at TIMESTAMPTZ NULL,
+			last_run_at TIMESTAMPTZ NULL,
+			last_status VARCHAR(20) NULL,
+			run_requested_at TIMESTAMPTZ NULL,
+			run_requested_by UUID NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000081 failed to create scheduled_jobs table: %w", err)
+	}
+
+	// One definition per job name globally and per company override.
+	if err := tx.Exec(`
+		CREATE UNIQUE INDEX IF NOT EXISTS uq_scheduled_jobs_name_company
+		ON scheduled_jobs(name, COALESCE(company_id, '00000000-0000-0000-0000-000000000000'::uuid));
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000081 failed to create scheduled_jobs unique index: %w", err)
+	}
+
+	if err := tx.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_scheduled_jobs_due
+		ON scheduled_jobs(next_run_at)
+		WHERE is_paused = FALSE;
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000081 failed to create scheduled_jobs due index: %w", err)
+	}
+
+	if err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS scheduled_job_runs (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			job_id UUID NOT NULL REFERENCES scheduled_jobs(id) ON DELETE CASCADE,
+			job_name VARCHAR(100) NOT NULL,
+			company_id UUID NULL,
+			trigger_type VARCHAR(20) NOT NULL,
+			triggered_by UUID NULL,
+			status VARCHAR(20) NOT NULL,
+			instance_id VARCHAR(255) NOT NULL DEFAULT '',
+			scheduled_for TIMESTAMPTZ NULL,
+			started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			finished_at TIMESTAMPTZ NULL,
+			duration_ms BIGINT NULL,
+			message TEXT NULL,
+			error TEXT NULL
+		);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000081 failed to create scheduled_job_runs table: %w", err)
+	}
+
+	if err := tx.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_scheduled_job_runs_job_started
+		ON scheduled_job_runs(job_id, started_at DESC);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000081 failed to create scheduled_job_runs index: %w", err)
+	}
+
+	if err := tx.Exec(`
+		INSERT INTO scheduled_jobs (name, description, cron_expression, timezone)
+		SELECT seed.name, seed.description, seed.cron_expression, 'Asia/Jakarta'
+		FROM (VALUES
+			('manager_daily_summary', 'Push the daily production summary to managers', '0 6 * * *'),
+			('weekly_harvest_summary', 'Notify managers and company admins about last week''s harvest', '0 7 * * 1'),
+			('vehicle_tax_reminders', 'Remind company admins about vehicle taxes that are due or overdue', '0 8 * * *')
+		) AS seed(name, description, cron_expression)
+		WHERE NOT EXISTS (
+			SELECT 1 FROM scheduled_jobs sj
+			WHERE sj.name = seed.name AND sj.company_id IS NULL
+		);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000081 failed to seed scheduled jobs: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("migration 000081 commit failed: %w", err)
+	}
+
+	log.Println("Migration 000081 completed successfully")
+	return nil
+}
@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000082AddNotificationEmailChannel adds email digest preferences,
+// email template bodies and the email dispatch job.
+func Migration000082AddNotificationEmailChannel(db *gorm.DB) error {
+	log.Println("Running migration: 000082_add_notification_email_channel")
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Exec(`
+		ALTER TABLE notification_preferences
+			ADD COLUMN IF NOT EXISTS email_digest_mode VARCHAR(20) NOT NULL DEFAULT 'IMMEDIATE',
+			ADD COLUMN IF NOT EXISTS last_email_digest_at TIMESTAMPTZ NULL;
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000082 failed to alter notification_preferences: %w", err)
+	}
+
+	if err := tx.Exec(`
+		ALTER TABLE notification_templates
+			ADD COLUMN IF NOT EXISTS email_subject_template VARCHAR(255) NULL,
+			ADD COLUMN IF NOT EXISTS email_text_template TEXT NULL,
+			ADD COLUMN IF NOT EXISTS email_html_template TEXT NULL;
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000082 failed to alter notification_templates: %w", err)
+	}
+
+	if err := tx.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_notification_deliveries_channel_status
+		ON notification_deliveries (channel, delivery_status, updated_at);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000082 failed to create idx_notification_deliveries_channel_status: %w", err)
+	}
+
+	if err := tx.Exec(`
+		INSERT INTO scheduled_jobs (name, description, cron_expression, timezone)
+		SELECT 'notification_email_dispatch', 'Send pending notification emails and hourly/daily digests', '* * * * *', 'Asia/Jakarta'
+		WHERE NOT EXISTS (
+			SELECT 1 FROM scheduled_jobs sj
+			WHERE sj.name = 'notification_email_dispatch' AND sj.company_id IS NULL
+		);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000082 failed to seed notification_email_dispatch job: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("migration 000082 failed to commit: %w", err)
+	}
+
+	log.Println("Migration 000082 completed successfully")
+	return nil
+}
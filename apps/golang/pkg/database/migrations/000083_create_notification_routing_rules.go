@@ -0,0 +1,105 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000083CreateNotificationRoutingRules creates company-configurable
+// notification routing rules, the dispatch log used for escalation, and seeds
+// the escalation job.
+func Migration000083CreateNotificationRoutingRules(db *gorm.DB) error {
+	log.Println("Running migration: 000083_create_notification_routing_rules")
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS notification_routing_rules (
+			id VARCHAR(36) PRIMARY KEY,
+			company_id UUID NULL REFERENCES companies(id) ON DELETE CASCADE,
+			name VARCHAR(150) NOT NULL,
+			event_type VARCHAR(50) NOT NULL,
+			min_priority VARCHAR(20) NOT NULL DEFAULT 'LOW',
+			recipient_roles JSON NOT NULL,
+			scope VARCHAR(20) NOT NULL DEFAULT 'COMPANY',
+			channels JSON NOT NULL,
+			escalation_steps JSON NULL,
+			is_active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_by VARCHAR(36) NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000083 failed to create notification_routing_rules table: %w", err)
+	}
+
+	if err := tx.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_notification_routing_rules_lookup
+		ON notification_routing_rules (event_type, company_id)
+		WHERE is_active = TRUE;
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000083 failed to create notification_routing_rules index: %w", err)
+	}
+
+	if err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS notification_routing_dispatches (
+			id VARCHAR(36) PRIMARY KEY,
+			event_key VARCHAR(36) NOT NULL,
+			rule_id VARCHAR(36) NOT NULL REFERENCES notification_routing_rules(id) ON DELETE CASCADE,
+			step INTEGER NOT NULL DEFAULT 0,
+			notification_id VARCHAR(36) NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+			recipient_id VARCHAR(36) NOT NULL,
+			company_id VARCHAR(36) NULL,
+			estate_id VARCHAR(36) NULL,
+			actor_id VARCHAR(36) NULL,
+			closed_at TIMESTAMPTZ NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000083 failed to create notification_routing_dispatches table: %w", err)
+	}
+
+	if err := tx.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_routing_dispatch_event
+		ON notification_routing_dispatches (event_key, step);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000083 failed to create idx_routing_dispatch_event: %w", err)
+	}
+
+	if err := tx.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_routing_dispatch_open
+		ON notification_routing_dispatches (created_at)
+		WHERE closed_at IS NULL;
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000083 failed to create idx_routing_dispatch_open: %w", err)
+	}
+
+	if err := tx.Exec(`
+		INSERT INTO scheduled_jobs (name, description, cron_expression, timezone)
+		SELECT 'notification_escalations', 'Escalate routed notifications that stay unread', '*/5 * * * *', 'Asia/Jakarta'
+		WHERE NOT EXISTS (
+			SELECT 1 FROM scheduled_jobs sj
+			WHERE sj.name = 'notification_escalations' AND sj.company_id IS NULL
+		);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000083 failed to seed notification_escalations job: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("migration 000083 failed to commit: %w", err)
+	}
+
+	log.Println("Migration 000083 completed successfully")
+	return nil
+}
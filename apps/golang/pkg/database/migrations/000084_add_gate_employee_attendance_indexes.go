@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000084AddGateEmployeeAttendanceIndexes adds the lookup indexes used
+// by the attendance reports to pair employee gate scans per NIK and day.
+func Migration000084AddGateEmployeeAttendanceIndexes(db *gorm.DB) error {
+	log.Println("Running migration: 000084_add_gate_employee_attendance_indexes")
+
+	indexes := []string{
+		`CREATE INDEX IF NOT EXISTS idx_gate_employee_logs_company_nik_scanned
+			ON gate_employee_logs(company_id, nik, scanned_at)
+			WHERE deleted_at IS NULL`,
+		`CREATE INDEX IF NOT EXISTS idx_harvest_records_company_tanggal_nik
+			ON harvest_records(company_id, tanggal, nik)
+			WHERE deleted_at IS NULL`,
+	}
+
+	for _, stmt := range indexes {
+		if err := db.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("migration 000084 failed to create attendance index: %w", err)
+		}
+	}
+
+	log.Println("Migration 000084 completed successfully")
+	return nil
+}
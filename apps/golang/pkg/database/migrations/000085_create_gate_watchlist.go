@@ -0,0 +1,91 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000085CreateGateWatchlist creates the company-scoped guest and
+// vehicle watchlist and the log of gate hits against it.
+func Migration000085CreateGateWatchlist(db *gorm.DB) error {
+	log.Println("Running migration: 000085_create_gate_watchlist")
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS gate_watchlist_entries (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			company_id UUID NOT NULL,
+			match_type VARCHAR(20) NOT NULL,
+			value VARCHAR(150) NOT NULL,
+			normalized_value VARCHAR(150) NOT NULL,
+			reason TEXT NOT NULL,
+			severity VARCHAR(20) NOT NULL,
+			expires_at TIMESTAMPTZ NULL,
+			is_active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_by UUID NOT NULL,
+			updated_by UUID NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			CONSTRAINT chk_gate_watchlist_match_type CHECK (match_type IN ('PLATE', 'ID_CARD', 'NAME')),
+			CONSTRAINT chk_gate_watchlist_severity CHECK (severity IN ('WARN', 'REQUIRE_OVERRIDE', 'BLOCK'))
+		);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000085 failed to create gate_watchlist_entries: %w", err)
+	}
+
+	if err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS gate_watchlist_hits (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			company_id UUID NOT NULL,
+			entry_id UUID NOT NULL,
+			source VARCHAR(20) NOT NULL,
+			status VARCHAR(20) NOT NULL,
+			severity VARCHAR(20) NOT NULL,
+			match_type VARCHAR(20) NOT NULL,
+			matched_value VARCHAR(150) NOT NULL,
+			reason TEXT NOT NULL,
+			vehicle_plate VARCHAR(20),
+			driver_name VARCHAR(100),
+			id_card_number VARCHAR(50),
+			guest_log_id UUID NULL,
+			device_id VARCHAR(255),
+			scanned_by UUID NOT NULL,
+			reviewed_by UUID NULL,
+			reviewed_at TIMESTAMPTZ NULL,
+			review_notes TEXT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000085 failed to create gate_watchlist_hits: %w", err)
+	}
+
+	indexes := []string{
+		"CREATE INDEX IF NOT EXISTS idx_gate_watchlist_entries_lookup ON gate_watchlist_entries(company_id, match_type, normalized_value) WHERE is_active = TRUE",
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_gate_watchlist_entries_unique ON gate_watchlist_entries(company_id, match_type, normalized_value)",
+		"CREATE INDEX IF NOT EXISTS idx_gate_watchlist_hits_company_status ON gate_watchlist_hits(company_id, status, created_at DESC)",
+		"CREATE INDEX IF NOT EXISTS idx_gate_watchlist_hits_guest_log ON gate_watchlist_hits(guest_log_id) WHERE guest_log_id IS NOT NULL",
+		"CREATE INDEX IF NOT EXISTS idx_gate_watchlist_hits_entry ON gate_watchlist_hits(entry_id)",
+	}
+	for _, stmt := range indexes {
+		if err := tx.Exec(stmt).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration 000085 failed to create gate watchlist index: %w", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("migration 000085 failed to commit: %w", err)
+	}
+
+	log.Println("Migration 000085 completed successfully")
+	return nil
+}
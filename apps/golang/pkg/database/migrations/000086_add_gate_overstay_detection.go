@@ -0,0 +1,94 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000086AddGateOverstayDetection adds per-company maximum stay rules,
+// overstay tracking columns on gate_guest_logs and the detector job.
+func Migration000086AddGateOverstayDetection(db *gorm.DB) error {
+	log.Println("Running migration: 000086_add_gate_overstay_detection")
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS gate_overstay_rules (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+			vehicle_type VARCHAR(20) NULL,
+			destination VARCHAR(255) NULL,
+			max_stay_minutes INTEGER NOT NULL,
+			is_active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_by UUID NOT NULL,
+			updated_by UUID NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			CONSTRAINT chk_gate_overstay_rules_minutes CHECK (max_stay_minutes BETWEEN 1 AND 10080)
+		);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000086 failed to create gate_overstay_rules: %w", err)
+	}
+
+	// One rule per vehicle type and destination; destinations match case-insensitively.
+	if err := tx.Exec(`
+		CREATE UNIQUE INDEX IF NOT EXISTS uq_gate_overstay_rules_scope
+		ON gate_overstay_rules(company_id, COALESCE(vehicle_type, ''), COALESCE(LOWER(destination), ''));
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000086 failed to create gate_overstay_rules unique index: %w", err)
+	}
+
+	if err := tx.Exec(`
+		ALTER TABLE gate_guest_logs
+			ADD COLUMN IF NOT EXISTS overstay_detected_at TIMESTAMPTZ NULL,
+			ADD COLUMN IF NOT EXISTS overstay_limit_minutes INTEGER NULL,
+			ADD COLUMN IF NOT EXISTS overstay_resolved_at TIMESTAMPTZ NULL;
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000086 failed to add overstay columns: %w", err)
+	}
+
+	if err := tx.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_gate_guest_logs_open_entries
+		ON gate_guest_logs(company_id, entry_time)
+		WHERE exit_time IS NULL AND entry_time IS NOT NULL AND deleted_at IS NULL;
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000086 failed to create open entries index: %w", err)
+	}
+
+	if err := tx.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_gate_guest_logs_unresolved_overstay
+		ON gate_guest_logs(company_id)
+		WHERE overstay_detected_at IS NOT NULL AND overstay_resolved_at IS NULL;
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000086 failed to create unresolved overstay index: %w", err)
+	}
+
+	if err := tx.Exec(`
+		INSERT INTO scheduled_jobs (name, description, cron_expression, timezone)
+		SELECT 'gate_overstay_detection', 'Mark guest vehicles that stayed past the company limit and alert managers', '*/5 * * * *', 'Asia/Jakarta'
+		WHERE NOT EXISTS (
+			SELECT 1 FROM scheduled_jobs sj
+			WHERE sj.name = 'gate_overstay_detection' AND sj.company_id IS NULL
+		);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000086 failed to seed overstay detection job: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("migration 000086 commit failed: %w", err)
+	}
+
+	log.Println("Migration 000086 completed successfully")
+	return nil
+}
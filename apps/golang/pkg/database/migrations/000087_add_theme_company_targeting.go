@@ -0,0 +1,71 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000087AddThemeCompanyTargeting scopes themes and campaigns to
+// companies, adds optional role and platform targeting to campaigns and a
+// per-company default theme.
+func Migration000087AddThemeCompanyTargeting(db *gorm.DB) error {
+	log.Println("Running migration: 000087_add_theme_company_targeting")
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Exec(`
+		ALTER TABLE themes
+			ADD COLUMN IF NOT EXISTS company_id UUID NULL REFERENCES companies(id) ON DELETE CASCADE;
+		CREATE INDEX IF NOT EXISTS idx_themes_company_id ON themes(company_id);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000087 failed to scope themes: %w", err)
+	}
+
+	if err := tx.Exec(`
+		ALTER TABLE theme_campaigns
+			ADD COLUMN IF NOT EXISTS company_id UUID NULL REFERENCES companies(id) ON DELETE CASCADE,
+			ADD COLUMN IF NOT EXISTS target_roles JSONB NOT NULL DEFAULT '[]'::jsonb,
+			ADD COLUMN IF NOT EXISTS target_platforms JSONB NOT NULL DEFAULT '[]'::jsonb;
+		CREATE INDEX IF NOT EXISTS idx_theme_campaigns_company_runtime
+			ON theme_campaigns(company_id, priority DESC, updated_at DESC)
+			WHERE enabled = true;
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000087 failed to add campaign targeting: %w", err)
+	}
+
+	if err := tx.Exec(`
+		ALTER TABLE theme_audit_logs
+			ADD COLUMN IF NOT EXISTS company_id UUID NULL;
+		CREATE INDEX IF NOT EXISTS idx_theme_audit_logs_company_created
+			ON theme_audit_logs(company_id, created_at DESC);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000087 failed to scope theme audit logs: %w", err)
+	}
+
+	if err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS theme_company_settings (
+			company_id UUID PRIMARY KEY REFERENCES companies(id) ON DELETE CASCADE,
+			default_theme_id UUID NULL REFERENCES themes(id) ON DELETE SET NULL,
+			updated_by VARCHAR(180) NOT NULL DEFAULT '',
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000087 failed to create theme_company_settings: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("migration 000087 failed to commit: %w", err)
+	}
+
+	log.Println("Migration 000087 completed successfully")
+	return nil
+}
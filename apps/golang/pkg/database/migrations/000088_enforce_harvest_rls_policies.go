@@ -0,0 +1,94 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000088EnforceHarvestRLSPolicies enforces harvest row-level security
+// so a Mandor can only access their own records. It replaces the policy block
+// that used to run inline on every startup.
+func Migration000088EnforceHarvestRLSPolicies(db *gorm.DB) error {
+	log.Println("Running migration: 000088_enforce_harvest_rls_policies")
+
+	if err := db.Exec(`
+		ALTER TABLE harvest_records ENABLE ROW LEVEL SECURITY;
+
+		DROP POLICY IF EXISTS harvest_select_policy ON harvest_records;
+		CREATE POLICY harvest_select_policy ON harvest_records
+			FOR SELECT
+			USING (
+				app_get_user_id() IS NOT NULL
+				AND (
+					app_get_user_role() <> 'MANDOR'
+					OR mandor_id = app_get_user_id()
+				)
+			);
+
+		DROP POLICY IF EXISTS harvest_insert_policy ON harvest_records;
+		CREATE POLICY harvest_insert_policy ON harvest_records
+			FOR INSERT
+			WITH CHECK (
+				app_get_user_id() IS NOT NULL
+				AND (
+					app_get_user_role() <> 'MANDOR'
+					OR mandor_id = app_get_user_id()
+				)
+			);
+
+		DROP POLICY IF EXISTS harvest_update_policy ON harvest_records;
+		CREATE POLICY harvest_update_policy ON harvest_records
+			FOR UPDATE
+			USING (
+				app_get_user_id() IS NOT NULL
+				AND (
+					app_get_user_role() <> 'MANDOR'
+					OR mandor_id = app_get_user_id()
+				)
+			)
+			WITH CHECK (
+				app_get_user_id() IS NOT NULL
+				AND (
+					app_get_user_role() <> 'MANDOR'
+					OR mandor_id = app_get_user_id()
+				)
+			);
+
+		DROP POLICY IF EXISTS harvest_delete_policy ON harvest_records;
+		CREATE POLICY harvest_delete_policy ON harvest_records
+			FOR DELETE
+			USING (
+				app_get_user_id() IS NOT NULL
+				AND (
+					app_get_user_role() <> 'MANDOR'
+					OR mandor_id = app_get_user_id()
+				)
+			);
+	`).Error; err != nil {
+		return fmt.Errorf("migration 000088 failed to configure harvest row-level security policies: %w", err)
+	}
+
+	log.Println("Migration 000088 completed successfully")
+	return nil
+}
+
+// Migration000088EnforceHarvestRLSPoliciesDown drops the harvest policies and
+// disables row-level security on harvest_records.
+func Migration000088EnforceHarvestRLSPoliciesDown(db *gorm.DB) error {
+	log.Println("Rolling back migration: 000088_enforce_harvest_rls_policies")
+
+	if err := db.Exec(`
+		DROP POLICY IF EXISTS harvest_select_policy ON harvest_records;
+		DROP POLICY IF EXISTS harvest_insert_policy ON harvest_records;
+		DROP POLICY IF EXISTS harvest_update_policy ON harvest_records;
+		DROP POLICY IF EXISTS harvest_delete_policy ON harvest_records;
+		ALTER TABLE harvest_records DISABLE ROW LEVEL SECURITY;
+	`).Error; err != nil {
+		return fmt.Errorf("migration 000088 failed to drop harvest row-level security policies: %w", err)
+	}
+
+	log.Println("Migration 000088 rolled back successfully")
+	return nil
+}
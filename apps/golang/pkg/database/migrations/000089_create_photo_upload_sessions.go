@@ -0,0 +1,78 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000089CreatePhotoUploadSessions adds the resumable photo upload
+// sessions and a thumbnail path for gate check photos.
+func Migration000089CreatePhotoUploadSessions(db *gorm.DB) error {
+	log.Println("Running migration: 000089_create_photo_upload_sessions")
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS photo_upload_sessions (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+			user_id UUID NOT NULL,
+			device_id VARCHAR(255) NOT NULL DEFAULT '',
+			file_name VARCHAR(255) NOT NULL DEFAULT '',
+			upload_length BIGINT NOT NULL,
+			upload_offset BIGINT NOT NULL DEFAULT 0,
+			content_sha256 VARCHAR(64) NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'UPLOADING',
+			file_path VARCHAR(512) NULL,
+			thumbnail_path VARCHAR(512) NULL,
+			mime_type VARCHAR(50) NULL,
+			target_type VARCHAR(20) NULL,
+			target_id UUID NULL,
+			expires_at TIMESTAMPTZ NOT NULL,
+			finalized_at TIMESTAMPTZ NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		CREATE INDEX IF NOT EXISTS idx_photo_upload_sessions_company_hash
+			ON photo_upload_sessions(company_id, content_sha256)
+			WHERE status = 'FINALIZED';
+		CREATE INDEX IF NOT EXISTS idx_photo_upload_sessions_expires
+			ON photo_upload_sessions(expires_at)
+			WHERE status <> 'FINALIZED';
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000089 failed to create photo_upload_sessions: %w", err)
+	}
+
+	if err := tx.Exec(`
+		ALTER TABLE gate_check_photos
+			ADD COLUMN IF NOT EXISTS thumbnail_path VARCHAR(512) NULL;
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000089 failed to add gate check photo thumbnails: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("migration 000089 failed to commit: %w", err)
+	}
+
+	log.Println("Migration 000089 completed successfully")
+	return nil
+}
+
+// Migration000089CreatePhotoUploadSessionsDown drops the upload sessions and
+// the thumbnail column. Stored objects are left in place.
+func Migration000089CreatePhotoUploadSessionsDown(db *gorm.DB) error {
+	if err := db.Exec(`
+		ALTER TABLE gate_check_photos DROP COLUMN IF EXISTS thumbnail_path;
+		DROP TABLE IF EXISTS photo_upload_sessions;
+	`).Error; err != nil {
+		return fmt.Errorf("migration 000089 rollback failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000090CreatePhotoVerifications stores the EXIF verification of
+// harvest and gate photos with the flags shown to approvers.
+func Migration000090CreatePhotoVerifications(db *gorm.DB) error {
+	log.Println("Running migration: 000090_create_photo_verifications")
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS photo_verifications (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			company_id UUID NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+			record_type VARCHAR(20) NOT NULL,
+			record_id UUID NOT NULL,
+			photo_url VARCHAR(512) NOT NULL,
+			sha256 VARCHAR(64) NOT NULL,
+			status VARCHAR(20) NOT NULL,
+			flags JSONB NOT NULL DEFAULT '[]'::jsonb,
+			captured_at TIMESTAMPTZ NULL,
+			capture_drift_minutes DOUBLE PRECISION NULL,
+			latitude DOUBLE PRECISION NULL,
+			longitude DOUBLE PRECISION NULL,
+			distance_meters DOUBLE PRECISION NULL,
+			device_make VARCHAR(100) NULL,
+			device_model VARCHAR(100) NULL,
+			software VARCHAR(100) NULL,
+			duplicate_record_type VARCHAR(20) NULL,
+			duplicate_record_id UUID NULL,
+			verified_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_photo_verifications_record_photo
+			ON photo_verifications(record_type, record_id, sha256);
+		CREATE INDEX IF NOT EXISTS idx_photo_verifications_company_hash
+			ON photo_verifications(company_id, sha256);
+	`).Error; err != nil {
+		return fmt.Errorf("migration 000090 failed to create photo_verifications: %w", err)
+	}
+
+	log.Println("Migration 000090 completed successfully")
+	return nil
+}
+
+// Migration000090CreatePhotoVerificationsDown drops the verification table.
+func Migration000090CreatePhotoVerificationsDown(db *gorm.DB) error {
+	if err := db.Exec(`DROP TABLE IF EXISTS photo_verifications;`).Error; err != nil {
+		return fmt.Errorf("migration 000090 rollback failed: %w", err)
+	}
+	return nil
+}
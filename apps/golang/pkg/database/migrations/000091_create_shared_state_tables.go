@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000091CreateSharedStateTables creates the cache and rate-limit
+// counter tables shared by all instances. They are UNLOGGED: the data is
+// disposable, so skipping the WAL keeps hot-path writes cheap.
+func Migration000091CreateSharedStateTables(db *gorm.DB) error {
+	log.Println("Running migration: 000091_create_shared_state_tables")
+
+	if err := db.Exec(`
+		CREATE UNLOGGED TABLE IF NOT EXISTS cache_entries (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL,
+			expires_at TIMESTAMPTZ NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_cache_entries_expires_at
+			ON cache_entries(expires_at) WHERE expires_at IS NOT NULL;
+
+		CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_counters (
+			key TEXT NOT NULL,
+			window_start TIMESTAMPTZ NOT NULL,
+			hits BIGINT NOT NULL DEFAULT 0,
+			expires_at TIMESTAMPTZ NOT NULL,
+			PRIMARY KEY (key, window_start)
+		);
+		CREATE INDEX IF NOT EXISTS idx_rate_limit_counters_expires_at
+			ON rate_limit_counters(expires_at);
+	`).Error; err != nil {
+		return fmt.Errorf("migration 000091 failed to create shared state tables: %w", err)
+	}
+
+	log.Println("Migration 000091 completed successfully")
+	return nil
+}
+
+// Migration000091CreateSharedStateTablesDown drops the shared state tables.
+func Migration000091CreateSharedStateTablesDown(db *gorm.DB) error {
+	if err := db.Exec(`
+		DROP TABLE IF EXISTS rate_limit_counters;
+		DROP TABLE IF EXISTS cache_entries;
+	`).Error; err != nil {
+		return fmt.Errorf("migration 000091 rollback failed: %w", err)
+	}
+	return nil
+}
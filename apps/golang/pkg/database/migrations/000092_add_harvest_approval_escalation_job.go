@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000092AddHarvestApprovalEscalationJob seeds the scheduler job that
+// replaces the per-instance harvest approval escalation ticker.
+func Migration000092AddHarvestApprovalEscalationJob(db *gorm.DB) error {
+	log.Println("Running migration: 000092_add_harvest_approval_escalation_job")
+
+	if err := db.Exec(`
+		INSERT INTO scheduled_jobs (name, description, cron_expression)
+		SELECT 'harvest_approval_escalation', 'Notify managers about harvest records pending approval past the company limit', '*/5 * * * *'
+		WHERE NOT EXISTS (
+			SELECT 1 FROM scheduled_jobs sj
+			WHERE sj.name = 'harvest_approval_escalation' AND sj.company_id IS NULL
+		);
+	`).Error; err != nil {
+		return fmt.Errorf("migration 000092 failed to seed harvest approval escalation job: %w", err)
+	}
+
+	log.Println("Migration 000092 completed successfully")
+	return nil
+}
+
+// Migration000092AddHarvestApprovalEscalationJobDown removes the job; its run
+// history is removed by the foreign key cascade.
+func Migration000092AddHarvestApprovalEscalationJobDown(db *gorm.DB) error {
+	if err := db.Exec(`DELETE FROM scheduled_jobs WHERE name = 'harvest_approval_escalation'`).Error; err != nil {
+		return fmt.Errorf("migration 000092 rollback failed: %w", err)
+	}
+	return nil
+}
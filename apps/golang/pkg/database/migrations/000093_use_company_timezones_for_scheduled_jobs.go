@@ -0,0 +1,79 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// seededGlobalJobs lists the global jobs earlier migrations pinned to
+// Asia/Jakarta.
+const seededGlobalJobs = `(
+	'manager_daily_summary',
+	'weekly_harvest_summary',
+	'vehicle_tax_reminders',
+	'notification_email_dispatch',
+	'notification_escalations',
+	'gate_overstay_detection'
+)`
+
+// Migration000093UseCompanyTimezonesForScheduledJobs clears the Asia/Jakarta
+// timezone seeded on the global jobs so they run in each company's configured
+// timezone, and seeds the session cleanup and satpam notification outbox jobs
+// that replace per-instance tickers.
+func Migration000093UseCompanyTimezonesForScheduledJobs(db *gorm.DB) error {
+	log.Println("Running migration: 000093_use_company_timezones_for_scheduled_jobs")
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	// next_run_at is reset so the scheduler recomputes it per company.
+	if err := tx.Exec(`
+		UPDATE scheduled_jobs
+		SET timezone = NULL, next_run_at = NULL, updated_at = NOW()
+		WHERE company_id IS NULL AND timezone = 'Asia/Jakarta' AND name IN ` + seededGlobalJobs + `;
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000093 failed to clear seeded job timezones: %w", err)
+	}
+
+	if err := tx.Exec(`
+		INSERT INTO scheduled_jobs (name, description, cron_expression)
+		SELECT seed.name, seed.description, seed.cron_expression
+		FROM (VALUES
+			('session_cleanup', 'Revoke expired web sessions and delete old inactive ones', '*/30 * * * *'),
+			('satpam_notification_outbox', 'Deliver queued satpam sync notifications and retry failed ones', '* * * * *')
+		) AS seed(name, description, cron_expression)
+		WHERE NOT EXISTS (
+			SELECT 1 FROM scheduled_jobs sj
+			WHERE sj.name = seed.name AND sj.company_id IS NULL
+		);
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000093 failed to seed scheduled jobs: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("migration 000093 commit failed: %w", err)
+	}
+
+	log.Println("Migration 000093 completed successfully")
+	return nil
+}
+
+// Migration000093UseCompanyTimezonesForScheduledJobsDown pins the seeded jobs
+// back to Asia/Jakarta and removes the new jobs.
+func Migration000093UseCompanyTimezonesForScheduledJobsDown(db *gorm.DB) error {
+	if err := db.Exec(`
+		UPDATE scheduled_jobs
+		SET timezone = 'Asia/Jakarta', next_run_at = NULL, updated_at = NOW()
+		WHERE company_id IS NULL AND timezone IS NULL AND name IN ` + seededGlobalJobs + `;
+		DELETE FROM scheduled_jobs WHERE name IN ('session_cleanup', 'satpam_notification_outbox');
+	`).Error; err != nil {
+		return fmt.Errorf("migration 000093 rollback failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000094AddVehicleTaxPenaltyEstimate stores the reminder job's late
+// penalty estimate apart from the penalty and total entered by admins.
+func Migration000094AddVehicleTaxPenaltyEstimate(db *gorm.DB) error {
+	log.Println("Running migration: 000094_add_vehicle_tax_penalty_estimate")
+
+	if err := db.Exec(`
+		ALTER TABLE vehicle_taxes
+			ADD COLUMN IF NOT EXISTS penalty_estimate NUMERIC(18,2) NOT NULL DEFAULT 0;
+	`).Error; err != nil {
+		return fmt.Errorf("migration 000094 failed to add penalty_estimate: %w", err)
+	}
+
+	log.Println("Migration 000094 completed successfully")
+	return nil
+}
+
+// Migration000094AddVehicleTaxPenaltyEstimateDown drops the estimate column.
+func Migration000094AddVehicleTaxPenaltyEstimateDown(db *gorm.DB) error {
+	if err := db.Exec(`ALTER TABLE vehicle_taxes DROP COLUMN IF EXISTS penalty_estimate;`).Error; err != nil {
+		return fmt.Errorf("migration 000094 rollback failed: %w", err)
+	}
+	return nil
+}
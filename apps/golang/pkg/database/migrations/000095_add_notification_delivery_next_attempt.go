@@ -0,0 +1,56 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000095AddNotificationDeliveryNextAttempt lets the email dispatcher
+// hold deliveries back for quiet hours, digests and retries without loading
+// them again on every run.
+func Migration000095AddNotificationDeliveryNextAttempt(db *gorm.DB) error {
+	log.Println("Running migration: 000095_add_notification_delivery_next_attempt")
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Exec(`
+		ALTER TABLE notification_deliveries
+			ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ NULL;
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000095 failed to add next_attempt_at: %w", err)
+	}
+
+	if err := tx.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_notification_deliveries_pending_email
+		ON notification_deliveries (COALESCE(next_attempt_at, created_at))
+		WHERE channel = 'EMAIL' AND delivery_status = 'PENDING';
+	`).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("migration 000095 failed to create idx_notification_deliveries_pending_email: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("migration 000095 commit failed: %w", err)
+	}
+
+	log.Println("Migration 000095 completed successfully")
+	return nil
+}
+
+// Migration000095AddNotificationDeliveryNextAttemptDown drops the column and
+// its index.
+func Migration000095AddNotificationDeliveryNextAttemptDown(db *gorm.DB) error {
+	if err := db.Exec(`
+		DROP INDEX IF EXISTS idx_notification_deliveries_pending_email;
+		ALTER TABLE notification_deliveries DROP COLUMN IF EXISTS next_attempt_at;
+	`).Error; err != nil {
+		return fmt.Errorf("migration 000095 rollback failed: %w", err)
+	}
+	return nil
+}
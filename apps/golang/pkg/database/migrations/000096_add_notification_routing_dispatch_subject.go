@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000096AddNotificationRoutingDispatchSubject records the user a
+// routed event is about, so escalations of approval and rejection events walk
+// the same hierarchy as the initial fan-out.
+func Migration000096AddNotificationRoutingDispatchSubject(db *gorm.DB) error {
+	log.Println("Running migration: 000096_add_notification_routing_dispatch_subject")
+
+	if err := db.Exec(`
+		ALTER TABLE notification_routing_dispatches
+			ADD COLUMN IF NOT EXISTS subject_id VARCHAR(36) NULL;
+	`).Error; err != nil {
+		return fmt.Errorf("migration 000096 failed to add subject_id: %w", err)
+	}
+
+	log.Println("Migration 000096 completed successfully")
+	return nil
+}
+
+// Migration000096AddNotificationRoutingDispatchSubjectDown drops the column.
+func Migration000096AddNotificationRoutingDispatchSubjectDown(db *gorm.DB) error {
+	if err := db.Exec(`
+		ALTER TABLE notification_routing_dispatches DROP COLUMN IF EXISTS subject_id;
+	`).Error; err != nil {
+		return fmt.Errorf("migration 000096 rollback failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// Migration000097SeedPhotoUploadCleanupJob seeds the photo upload cleanup job
+// that replaces the hourly per-instance ticker.
+func Migration000097SeedPhotoUploadCleanupJob(db *gorm.DB) error {
+	log.Println("Running migration: 000097_seed_photo_upload_cleanup_job")
+
+	if err := db.Exec(`
+		INSERT INTO scheduled_jobs (name, description, cron_expression)
+		SELECT 'photo_upload_cleanup', 'Remove expired resumable photo uploads and their chunks', '0 * * * *'
+		WHERE NOT EXISTS (
+			SELECT 1 FROM scheduled_jobs
+			WHERE name = 'photo_upload_cleanup' AND company_id IS NULL
+		);
+	`).Error; err != nil {
+		return fmt.Errorf("migration 000097 failed to seed photo_upload_cleanup: %w", err)
+	}
+
+	log.Println("Migration 000097 completed successfully")
+	return nil
+}
+
+// Migration000097SeedPhotoUploadCleanupJobDown removes the seeded job.
+func Migration000097SeedPhotoUploadCleanupJobDown(db *gorm.DB) error {
+	if err := db.Exec(`DELETE FROM scheduled_jobs WHERE name = 'photo_upload_cleanup';`).Error; err != nil {
+		return fmt.Errorf("migration 000097 rollback failed: %w", err)
+	}
+	return nil
+}
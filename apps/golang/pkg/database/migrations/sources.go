@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+)
+
+// sourceFiles embeds the migration sources so the runner can detect edits to
+// migrations that were already applied.
+//
+//go:embed *.go *.sql
+var sourceFiles embed.FS
+
+// SourceChecksum returns the sha256 of every source file whose name starts
+// with prefix, e.g. "000087_add_theme_company_targeting".
+func SourceChecksum(prefix string) (string, error) {
+	matches, err := fs.Glob(sourceFiles, prefix+".*")
+	if err != nil {
+		return "", fmt.Errorf("match migration sources for %s: %w", prefix, err)
+	}
+	if len(matches) == 0 {
+		return "", fmt.Errorf("no migration source found for %s", prefix)
+	}
+
+	hash := sha256.New()
+	for _, name := range matches {
+		content, err := sourceFiles.ReadFile(name)
+		if err != nil {
+			return "", fmt.Errorf("read migration source %s: %w", name, err)
+		}
+		hash.Write([]byte(name))
+		hash.Write(content)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,592 @@
+// Package migrator applies versioned schema migrations and records them in
+// the schema_migrations history table.
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migrationLockKey is the Postgres advisory lock held while migrations run so
+// concurrent instances do not race.
+const migrationLockKey int64 = 0x41475249_4d494752 // "AGRIMIGR"
+
+var ErrIrreversible = errors.New("migration is irreversible")
+
+// Migration is one versioned schema change.
+type Migration struct {
+	Version string
+	Name    string
+	// Manual migrations are never pending; they only run when requested.
+	Manual bool
+	Up     func(ctx context.Context, db *gorm.DB) error
+	// Down is nil for irreversible migrations.
+	Down func(ctx context.Context, db *gorm.DB) error
+	// Checksum hashes the migration source to detect edits after apply.
+	Checksum func() (string, error)
+}
+
+// AppliedMigration is a row of schema_migrations.
+type AppliedMigration struct {
+	Version     string    `gorm:"column:version;primaryKey"`
+	Name        string    `gorm:"column:name"`
+	Checksum    string    `gorm:"column:checksum"`
+	AppliedSeq  int64     `gorm:"column:applied_seq;->"`
+	AppliedAt   time.Time `gorm:"column:applied_at"`
+	ExecutionMS int64     `gorm:"column:execution_ms"`
+}
+
+func (AppliedMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Status describes a registered or recorded migration.
+type Status struct {
+	Version    string     `json:"version"`
+	Name       string     `json:"name"`
+	Manual     bool       `json:"manual"`
+	Reversible bool       `json:"reversible"`
+	Applied    bool       `json:"applied"`
+	AppliedAt  *time.Time `json:"applied_at,omitempty"`
+	Checksum   string     `json:"checksum"`
+	// AppliedChecksum is the checksum recorded when the migration ran.
+	AppliedChecksum string `json:"applied_checksum,omitempty"`
+	// Drifted is set when an applied migration's source changed since.
+	Drifted bool `json:"drifted"`
+	// Unknown is set for recorded versions missing from the registry.
+	Unknown bool `json:"unknown"`
+}
+
+// Runner applies and rolls back the migrations of a registry. Migrations run
+// in registry order, which is not always version order.
+type Runner struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+func New(db *gorm.DB, migrations []Migration) *Runner {
+	return &Runner{db: db, migrations: migrations}
+}
+
+// Status lists every registered migration followed by recorded versions the
+// registry no longer knows.
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	history, err := r.history(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return buildStatus(r.migrations, history)
+}
+
+// Pending returns the non-manual migrations that have not been applied.
+func (r *Runner) Pending(ctx context.Context) ([]Status, error) {
+	statuses, err := r.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pending := []Status{}
+	for _, status := range statuses {
+		if !status.Applied && !status.Manual && !status.Unknown {
+			pending = append(pending, status)
+		}
+	}
+	return pending, nil
+}
+
+// Verify returns applied migrations whose source drifted and recorded
+// versions missing from the registry.
+func (r *Runner) Verify(ctx context.Context) ([]Status, error) {
+	statuses, err := r.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	problems := []Status{}
+	for _, status := range statuses {
+		if status.Drifted || status.Unknown {
+			problems = append(problems, status)
+		}
+	}
+	return problems, nil
+}
+
+// Up applies up to limit pending migrations, or all of them when limit is
+// zero. Manual migrations are included only when includeManual is set.
+func (r *Runner) Up(ctx context.Context, limit int, includeManual bool) ([]string, error) {
+	applied := []string{}
+	err := r.withLock(ctx, func() error {
+		if err := r.ensureHistoryTable(ctx); err != nil {
+			return err
+		}
+		history, err := r.history(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range planUp(r.migrations, history, limit, includeManual) {
+			if err := r.apply(ctx, migration); err != nil {
+				return err
+			}
+			applied = append(applied, migration.Version)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down rolls back up to limit applied migrations, most recent first. Nothing
+// is rolled back when one of them is irreversible.
+func (r *Runner) Down(ctx context.Context, limit int) ([]string, error) {
+	if limit <= 0 {
+		limit = 1
+	}
+
+	rolledBack := []string{}
+	err := r.withLock(ctx, func() error {
+		if err := r.ensureHistoryTable(ctx); err != nil {
+			return err
+		}
+		history, err := r.history(ctx)
+		if err != nil {
+			return err
+		}
+
+		plan, err := planDown(r.migrations, history, limit)
+		if err != nil {
+			return err
+		}
+		for _, migration := range plan {
+			if err := r.revert(ctx, migration); err != nil {
+				return err
+			}
+			rolledBack = append(rolledBack, migration.Version)
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Redo rolls back the most recently applied migration and applies it again.
+func (r *Runner) Redo(ctx context.Context) (string, error) {
+	version := ""
+	err := r.withLock(ctx, func() error {
+		if err := r.ensureHistoryTable(ctx); err != nil {
+			return err
+		}
+		history, err := r.history(ctx)
+		if err != nil {
+			return err
+		}
+
+		plan, err := planDown(r.migrations, history, 1)
+		if err != nil {
+			return err
+		}
+		if len(plan) == 0 {
+			return fmt.Errorf("no applied migration to redo")
+		}
+		if err := r.revert(ctx, plan[0]); err != nil {
+			return err
+		}
+		if err := r.apply(ctx, plan[0]); err != nil {
+			return err
+		}
+		version = plan[0].Version
+		return nil
+	})
+	return version, err
+}
+
+// Baseline records pending migrations as applied without running them, in
+// registry order up to and including through, or all of them when through is
+// empty. It is meant for databases whose schema already matches those
+// migrations, e.g. one restored from a dump or created before the runner.
+func (r *Runner) Baseline(ctx context.Context, through string, includeManual bool) ([]string, error) {
+	marked := []string{}
+	err := r.withLock(ctx, func() error {
+		if err := r.ensureHistoryTable(ctx); err != nil {
+			return err
+		}
+		history, err := r.history(ctx)
+		if err != nil {
+			return err
+		}
+
+		plan, err := planBaseline(r.migrations, history, through, includeManual)
+		if err != nil {
+			return err
+		}
+		for _, migration := range plan {
+			checksum, err := migrationChecksum(migration)
+			if err != nil {
+				return err
+			}
+			if err := r.record(r.db.WithContext(ctx), migration, checksum, 0); err != nil {
+				return err
+			}
+			log.Printf("Marked migration %s_%s as applied", migration.Version, migration.Name)
+			marked = append(marked, migration.Version)
+		}
+		return nil
+	})
+	return marked, err
+}
+
+// apply runs the migration and records it in one transaction, so a failed
+// migration leaves neither partial schema changes nor a history row.
+func (r *Runner) apply(ctx context.Context, migration Migration) error {
+	checksum, err := migrationChecksum(migration)
+	if err != nil {
+		return err
+	}
+
+	startedAt := time.Now()
+	var executionMS int64
+	err = r.inTransaction(ctx, func(tx *gorm.DB) error {
+		if err := migration.Up(ctx, tx); err != nil {
+			return fmt.Errorf("failed migration %s %s: %w", migration.Version, migration.Name, err)
+		}
+		executionMS = time.Since(startedAt).Milliseconds()
+		return r.record(tx, migration, checksum, executionMS)
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("Applied migration %s_%s in %dms", migration.Version, migration.Name, executionMS)
+	return nil
+}
+
+// revert rolls the migration back and removes its record in one transaction.
+func (r *Runner) revert(ctx context.Context, migration Migration) error {
+	err := r.inTransaction(ctx, func(tx *gorm.DB) error {
+		if err := migration.Down(ctx, tx); err != nil {
+			return fmt.Errorf("failed rollback %s %s: %w", migration.Version, migration.Name, err)
+		}
+		if err := tx.Delete(&AppliedMigration{}, "version = ?", migration.Version).Error; err != nil {
+			return fmt.Errorf("remove migration record %s: %w", migration.Version, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("Rolled back migration %s_%s", migration.Version, migration.Name)
+	return nil
+}
+
+func (r *Runner) record(db *gorm.DB, migration Migration, checksum string, executionMS int64) error {
+	record := AppliedMigration{
+		Version:     migration.Version,
+		Name:        migration.Name,
+		Checksum:    checksum,
+		AppliedAt:   time.Now(),
+		ExecutionMS: executionMS,
+	}
+	if err := db.Create(&record).Error; err != nil {
+		return fmt.Errorf("record migration %s: %w", migration.Version, err)
+	}
+	return nil
+}
+
+// inTransaction runs fn on a session bound to one transaction. Migrations
+// that open their own transaction with Begin get a savepoint instead, so their
+// Commit and Rollback stay inside the outer transaction.
+func (r *Runner) inTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		sqlTx, ok := tx.Statement.ConnPool.(*sql.Tx)
+		if !ok {
+			return fn(tx)
+		}
+		session := tx.Session(&gorm.Session{Context: ctx})
+		session.Statement.ConnPool = &savepointTx{Tx: sqlTx}
+		return fn(session)
+	})
+}
+
+// savepointTx is a transaction handed to migrations. Begin on it creates a
+// savepoint whose Commit releases it and whose Rollback rolls back to it.
+// Every statement also runs under its own savepoint: several migrations log
+// and tolerate a failed statement, which would otherwise abort the whole
+// transaction in Postgres.
+type savepointTx struct {
+	*sql.Tx
+	name  string
+	depth int
+}
+
+// statementSavepoint guards a single statement of a migration.
+const statementSavepoint = "migration_statement"
+
+func (t *savepointTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	// Releasing the statement savepoint would also release a savepoint the
+	// statement creates, e.g. one of a nested gorm Transaction.
+	if isSavepointStatement(query) {
+		return t.Tx.ExecContext(ctx, query, args...)
+	}
+	if _, err := t.Tx.ExecContext(ctx, "SAVEPOINT "+statementSavepoint); err != nil {
+		return nil, err
+	}
+	result, err := t.Tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		t.rollbackStatement(ctx)
+		return nil, err
+	}
+	if _, err := t.Tx.ExecContext(ctx, "RELEASE SAVEPOINT "+statementSavepoint); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// QueryContext keeps the statement savepoint until the transaction ends; it
+// cannot be released while the rows are still being read.
+func (t *savepointTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if _, err := t.Tx.ExecContext(ctx, "SAVEPOINT "+statementSavepoint); err != nil {
+		return nil, err
+	}
+	rows, err := t.Tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		t.rollbackStatement(ctx)
+		return nil, err
+	}
+	return rows, nil
+}
+
+func isSavepointStatement(query string) bool {
+	query = strings.ToUpper(strings.TrimSpace(query))
+	return strings.HasPrefix(query, "SAVEPOINT ") ||
+		strings.HasPrefix(query, "RELEASE ") ||
+		strings.HasPrefix(query, "ROLLBACK TO ")
+}
+
+func (t *savepointTx) rollbackStatement(ctx context.Context) {
+	if _, err := t.Tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+statementSavepoint); err != nil {
+		log.Printf("migrator: failed to roll back statement savepoint: %v", err)
+	}
+}
+
+func (t *savepointTx) BeginTx(ctx context.Context, _ *sql.TxOptions) (gorm.ConnPool, error) {
+	name := fmt.Sprintf("migration_%d", t.depth+1)
+	if _, err := t.Tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return nil, fmt.Errorf("create savepoint %s: %w", name, err)
+	}
+	return &savepointTx{Tx: t.Tx, name: name, depth: t.depth + 1}, nil
+}
+
+func (t *savepointTx) Commit() error {
+	if t.name == "" {
+		return errors.New("migration transaction is committed by the runner")
+	}
+	_, err := t.Tx.Exec("RELEASE SAVEPOINT " + t.name)
+	return err
+}
+
+func (t *savepointTx) Rollback() error {
+	if t.name == "" {
+		return errors.New("migration transaction is rolled back by the runner")
+	}
+	_, err := t.Tx.Exec("ROLLBACK TO SAVEPOINT " + t.name)
+	return err
+}
+
+func (r *Runner) ensureHistoryTable(ctx context.Context) error {
+	if err := r.db.WithContext(ctx).Exec(`
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version VARCHAR(32) PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			checksum VARCHAR(64) NOT NULL,
+			applied_seq BIGSERIAL NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			execution_ms BIGINT NOT NULL DEFAULT 0
+		)
+	`).Error; err != nil {
+		return fmt.Errorf("create schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+// history returns the applied migrations in apply order. A missing history
+// table means nothing has been applied yet.
+func (r *Runner) history(ctx context.Context) ([]AppliedMigration, error) {
+	db := r.db.WithContext(ctx)
+	if !db.Migrator().HasTable(&AppliedMigration{}) {
+		return nil, nil
+	}
+
+	var history []AppliedMigration
+	if err := db.Order("applied_seq ASC").Find(&history).Error; err != nil {
+		return nil, fmt.Errorf("load schema_migrations: %w", err)
+	}
+	return history, nil
+}
+
+// withLock runs fn while holding the migration advisory lock on a dedicated
+// connection, waiting for any other instance to finish first.
+func (r *Runner) withLock(ctx context.Context, fn func() error) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return fmt.Errorf("access database pool: %w", err)
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("open migration lock connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	// sql.Conn.Close returns the connection to the pool, so the session lock
+	// must be released explicitly.
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey); err != nil {
+			log.Printf("migrator: failed to release migration lock: %v", err)
+		}
+	}()
+
+	return fn()
+}
+
+func migrationChecksum(migration Migration) (string, error) {
+	if migration.Checksum == nil {
+		return "", nil
+	}
+	checksum, err := migration.Checksum()
+	if err != nil {
+		return "", fmt.Errorf("checksum migration %s: %w", migration.Version, err)
+	}
+	return checksum, nil
+}
+
+func buildStatus(migrations []Migration, history []AppliedMigration) ([]Status, error) {
+	applied := make(map[string]AppliedMigration, len(history))
+	for _, record := range history {
+		applied[record.Version] = record
+	}
+
+	statuses := make([]Status, 0, len(migrations)+len(history))
+	known := make(map[string]struct{}, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = struct{}{}
+		checksum, err := migrationChecksum(migration)
+		if err != nil {
+			return nil, err
+		}
+
+		status := Status{
+			Version:    migration.Version,
+			Name:       migration.Name,
+			Manual:     migration.Manual,
+			Reversible: migration.Down != nil,
+			Checksum:   checksum,
+		}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			status.AppliedChecksum = record.Checksum
+			status.Drifted = record.Checksum != checksum
+		}
+		statuses = append(statuses, status)
+	}
+
+	for _, record := range history {
+		if _, ok := known[record.Version]; ok {
+			continue
+		}
+		appliedAt := record.AppliedAt
+		statuses = append(statuses, Status{
+			Version:         record.Version,
+			Name:            record.Name,
+			Applied:         true,
+			AppliedAt:       &appliedAt,
+			AppliedChecksum: record.Checksum,
+			Unknown:         true,
+		})
+	}
+	return statuses, nil
+}
+
+// planUp returns the migrations to apply in registry order.
+func planUp(migrations []Migration, history []AppliedMigration, limit int, includeManual bool) []Migration {
+	applied := make(map[string]struct{}, len(history))
+	for _, record := range history {
+		applied[record.Version] = struct{}{}
+	}
+
+	plan := []Migration{}
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		if migration.Manual && !includeManual {
+			continue
+		}
+		plan = append(plan, migration)
+		if limit > 0 && len(plan) == limit {
+			break
+		}
+	}
+	return plan
+}
+
+// planBaseline returns the pending migrations to mark as applied, in registry
+// order up to and including through. It fails when through is not registered.
+func planBaseline(migrations []Migration, history []AppliedMigration, through string, includeManual bool) ([]Migration, error) {
+	if through != "" {
+		found := false
+		for _, migration := range migrations {
+			if migration.Version == through {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("migration %s is not registered", through)
+		}
+	}
+
+	applied := make(map[string]struct{}, len(history))
+	for _, record := range history {
+		applied[record.Version] = struct{}{}
+	}
+
+	plan := []Migration{}
+	for _, migration := range migrations {
+		_, done := applied[migration.Version]
+		if !done && (!migration.Manual || includeManual) {
+			plan = append(plan, migration)
+		}
+		if migration.Version == through {
+			break
+		}
+	}
+	return plan, nil
+}
+
+// planDown returns up to limit applied migrations, most recent first. It
+// fails when one of them is irreversible or no longer registered.
+func planDown(migrations []Migration, history []AppliedMigration, limit int) ([]Migration, error) {
+	byVersion := make(map[string]Migration, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	plan := []Migration{}
+	for i := len(history) - 1; i >= 0 && len(plan) < limit; i-- {
+		migration, ok := byVersion[history[i].Version]
+		if !ok {
+			return nil, fmt.Errorf("applied migration %s is not registered", history[i].Version)
+		}
+		if migration.Down == nil {
+			return nil, fmt.Errorf("%w: %s_%s", ErrIrreversible, migration.Version, migration.Name)
+		}
+		plan = append(plan, migration)
+	}
+	return plan, nil
+}
@@ -0,0 +1,174 @@
+package migrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func noop(context.Context, *gorm.DB) error { return nil }
+
+func checksum(value string) func() (string, error) {
+	return func() (string, error) { return value, nil }
+}
+
+func testMigrations() []Migration {
+	return []Migration{
+		{Version: "000001", Name: "baseline", Up: noop, Checksum: checksum("a")},
+		{Version: "000002", Name: "add_index", Up: noop, Down: noop, Checksum: checksum("b")},
+		{Version: "000003", Name: "enable_rls", Manual: true, Up: noop, Down: noop, Checksum: checksum("c")},
+		{Version: "000004", Name: "add_column", Up: noop, Down: noop, Checksum: checksum("d")},
+	}
+}
+
+func versions(migrations []Migration) []string {
+	result := make([]string, 0, len(migrations))
+	for _, migration := range migrations {
+		result = append(result, migration.Version)
+	}
+	return result
+}
+
+func TestPlanUp(t *testing.T) {
+	history := []AppliedMigration{{Version: "000001"}}
+
+	assert.Equal(t, []string{"000002", "000004"}, versions(planUp(testMigrations(), history, 0, false)))
+	assert.Equal(t, []string{"000002"}, versions(planUp(testMigrations(), history, 1, false)))
+	assert.Equal(t, []string{"000002", "000003", "000004"}, versions(planUp(testMigrations(), history, 0, true)))
+}
+
+func TestPlanDown(t *testing.T) {
+	history := []AppliedMigration{{Version: "000001"}, {Version: "000002"}, {Version: "000004"}}
+
+	plan, err := planDown(testMigrations(), history, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"000004", "000002"}, versions(plan))
+
+	_, err = planDown(testMigrations(), history, 3)
+	assert.True(t, errors.Is(err, ErrIrreversible))
+
+	_, err = planDown(testMigrations(), append(history, AppliedMigration{Version: "000099"}), 1)
+	assert.Error(t, err)
+}
+
+func TestBuildStatus_FlagsDriftAndUnknown(t *testing.T) {
+	appliedAt := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+	history := []AppliedMigration{
+		{Version: "000001", Checksum: "a", AppliedAt: appliedAt},
+		{Version: "000002", Checksum: "edited", AppliedAt: appliedAt},
+		{Version: "000099", Name: "removed", Checksum: "z", AppliedAt: appliedAt},
+	}
+
+	statuses, err := buildStatus(testMigrations(), history)
+	require.NoError(t, err)
+	require.Len(t, statuses, 5)
+
+	byVersion := map[string]Status{}
+	for _, status := range statuses {
+		byVersion[status.Version] = status
+	}
+	assert.True(t, byVersion["000001"].Applied)
+	assert.False(t, byVersion["000001"].Drifted)
+	assert.False(t, byVersion["000001"].Reversible)
+	assert.True(t, byVersion["000002"].Drifted)
+	assert.False(t, byVersion["000004"].Applied)
+	assert.True(t, byVersion["000003"].Manual)
+	assert.True(t, byVersion["000099"].Unknown)
+}
+
+func TestPlanBaseline(t *testing.T) {
+	history := []AppliedMigration{{Version: "000001"}}
+
+	plan, err := planBaseline(testMigrations(), history, "000002", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"000002"}, versions(plan))
+
+	plan, err = planBaseline(testMigrations(), history, "", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"000002", "000004"}, versions(plan))
+
+	plan, err = planBaseline(testMigrations(), history, "", true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"000002", "000003", "000004"}, versions(plan))
+
+	_, err = planBaseline(testMigrations(), history, "000099", false)
+	assert.Error(t, err)
+}
+
+func newApplyTestRunner(t *testing.T) *Runner {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	require.NoError(t, db.Exec(`
+		CREATE TABLE schema_migrations (
+			version TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			applied_seq INTEGER,
+			applied_at DATETIME NOT NULL,
+			execution_ms INTEGER NOT NULL DEFAULT 0
+		)
+	`).Error)
+	return New(db, nil)
+}
+
+func recorded(t *testing.T, runner *Runner, version string) bool {
+	t.Helper()
+	var count int64
+	require.NoError(t, runner.db.Model(&AppliedMigration{}).Where("version = ?", version).Count(&count).Error)
+	return count > 0
+}
+
+func TestApply_FailedMigrationLeavesNoSchemaOrHistory(t *testing.T) {
+	runner := newApplyTestRunner(t)
+	migration := Migration{
+		Version:  "000010",
+		Name:     "create_widgets",
+		Checksum: checksum("w"),
+		Up: func(ctx context.Context, db *gorm.DB) error {
+			tx := db.Begin()
+			if err := tx.Exec("CREATE TABLE widgets (id INTEGER PRIMARY KEY)").Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+			if err := tx.Commit().Error; err != nil {
+				return err
+			}
+			return errors.New("backfill failed")
+		},
+	}
+
+	require.Error(t, runner.apply(context.Background(), migration))
+	assert.False(t, runner.db.Migrator().HasTable("widgets"))
+	assert.False(t, recorded(t, runner, "000010"))
+}
+
+func TestApply_RecordsMigrationAndToleratesFailedStatements(t *testing.T) {
+	runner := newApplyTestRunner(t)
+	migration := Migration{
+		Version:  "000011",
+		Name:     "create_gadgets",
+		Checksum: checksum("g"),
+		Up: func(ctx context.Context, db *gorm.DB) error {
+			if err := db.Exec("DROP TABLE missing_table").Error; err == nil {
+				return errors.New("expected the drop to fail")
+			}
+			return db.Exec("CREATE TABLE gadgets (id INTEGER PRIMARY KEY)").Error
+		},
+	}
+
+	require.NoError(t, runner.apply(context.Background(), migration))
+	assert.True(t, runner.db.Migrator().HasTable("gadgets"))
+	assert.True(t, recorded(t, runner, "000011"))
+}
@@ -30,6 +30,16 @@ type ReplicaConfig struct {
 	Priority        int           `mapstructure:"priority"` // Primary/secondary priority
 }
 
+// replicationLagSQL returns how many seconds a standby is behind. A standby
+// that has replayed everything it received is not lagging, even when the
+// primary has been idle since the last replayed transaction.
+const replicationLagSQL = `
+	SELECT CASE
+		WHEN NOT pg_is_in_recovery() THEN 0
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END`
+
 // ReplicaStatus represents the health status of a replica
 type ReplicaStatus int
 
@@ -49,6 +59,7 @@ type ReadReplica struct {
 	Error     string
 	Weight    int
 	Priority  int
+	Lag       time.Duration // Replication lag measured by the last health check
 }
 
 // ReadReplicaManager manages multiple read replicas with health checking and load balancing
@@ -58,6 +69,8 @@ type ReadReplicaManager struct {
 	mutex        sync.RWMutex
 	healthCheck  *HealthChecker
 	config       *ReadReplicaManagerConfig
+	stop         chan struct{}
+	stopOnce     sync.Once
 }
 
 // ReadReplicaManagerConfig holds configuration for the replica manager
@@ -68,6 +81,7 @@ type ReadReplicaManagerConfig struct {
 	FailoverThreshold   int           `mapstructure:"failover_threshold"`
 	EnableSlowQueryLog  bool          `mapstructure:"enable_slow_query_log"`
 	SlowQueryThreshold  time.Duration `mapstructure:"slow_query_threshold"`
+	MaxReplicationLag   time.Duration `mapstructure:"max_replication_lag"` // Replicas lagging more are skipped (0 = no limit)
 }
 
 // DatabaseOperationType represents the type of database operation
@@ -108,6 +122,7 @@ func NewReadReplicaManager(config *ReadReplicaManagerConfig) *ReadReplicaManager
 	rrm := &ReadReplicaManager{
 		replicas: make([]*ReadReplica, 0),
 		config:   config,
+		stop:     make(chan struct{}),
 	}
 
 	// Initialize health checker
@@ -134,6 +149,7 @@ func DefaultReadReplicaManagerConfig() *ReadReplicaManagerConfig {
 		FailoverThreshold:   3,
 		EnableSlowQueryLog:  true,
 		SlowQueryThreshold:  1 * time.Second,
+		MaxReplicationLag:   30 * time.Second,
 	}
 }
 
@@ -215,10 +231,10 @@ func (rrm *ReadReplicaManager) GetReplica() (*gorm.DB, error) {
 		return nil, fmt.Errorf("no read replicas available")
 	}
 
-	// Filter healthy replicas
+	// Filter healthy replicas that are not lagging behind the primary
 	healthyReplicas := make([]*ReadReplica, 0)
 	for _, replica := range rrm.replicas {
-		if replica.Status == ReplicaStatusHealthy {
+		if replica.Status == ReplicaStatusHealthy && !rrm.isLagging(replica) {
 			healthyReplicas = append(healthyReplicas, replica)
 		}
 	}
@@ -240,6 +256,11 @@ func (rrm *ReadReplicaManager) GetReplica() (*gorm.DB, error) {
 	return rrm.selectReplicaByWeight(healthyReplicas)
 }
 
+// isLagging reports whether replica is further behind than MaxReplicationLag
+func (rrm *ReadReplicaManager) isLagging(replica *ReadReplica) bool {
+	return rrm.config.MaxReplicationLag > 0 && replica.Lag > rrm.config.MaxReplicationLag
+}
+
 // selectReplicaByWeight selects a replica based on weights
 func (rrm *ReadReplicaManager) selectReplicaByWeight(replicas []*ReadReplica) (*gorm.DB, error) {
 	// Calculate total weight
@@ -289,20 +310,29 @@ func (rrm *ReadReplicaManager) GetAllReplicas() map[string]*ReadReplica {
 			Error:     replica.Error,
 			Weight:    replica.Weight,
 			Priority:  replica.Priority,
+			Lag:       replica.Lag,
 		}
 		result[replica.ID] = replicaCopy
 	}
 	return result
 }
 
-// StartHealthChecking starts the background health checking process
+// StartHealthChecking checks the replicas once, so lag is known before the
+// first read, then keeps checking in the background until Close
 func (rrm *ReadReplicaManager) StartHealthChecking() {
+	rrm.performHealthCheck()
+
 	go func() {
 		ticker := time.NewTicker(rrm.config.HealthCheckInterval)
 		defer ticker.Stop()
 
-		for range ticker.C {
-			rrm.performHealthCheck()
+		for {
+			select {
+			case <-ticker.C:
+				rrm.performHealthCheck()
+			case <-rrm.stop:
+				return
+			}
 		}
 	}()
 	log.Printf("Started read replica health checking with interval %v", rrm.config.HealthCheckInterval)
@@ -316,8 +346,9 @@ func (rrm *ReadReplicaManager) performHealthCheck() {
 	for _, replica := range rrm.replicas {
 		ctx, cancel := context.WithTimeout(context.Background(), rrm.config.HealthCheckTimeout)
 
-		// Perform health check
-		err := replica.DB.WithContext(ctx).Exec("SELECT 1").Error
+		// Perform health check; the lag query doubles as the ping
+		var lagSeconds float64
+		err := replica.DB.WithContext(ctx).Raw(replicationLagSQL).Scan(&lagSeconds).Error
 		cancel()
 
 		replica.LastCheck = time.Now()
@@ -335,6 +366,16 @@ func (rrm *ReadReplicaManager) performHealthCheck() {
 				replica.Status = ReplicaStatusHealthy
 				replica.Error = ""
 			}
+
+			wasLagging := rrm.isLagging(replica)
+			replica.Lag = time.Duration(lagSeconds * float64(time.Second))
+			if lagging := rrm.isLagging(replica); lagging != wasLagging {
+				if lagging {
+					log.Printf("Replica %s is lagging %v behind primary, routing reads to primary", replica.ID, replica.Lag)
+				} else {
+					log.Printf("Replica %s caught up (lag %v)", replica.ID, replica.Lag)
+				}
+			}
 		}
 	}
 }
@@ -353,8 +394,10 @@ func (rrm *ReadReplicaManager) GetHealthyReplicaCount() int {
 	return count
 }
 
-// Close all replica connections
+// Close stops health checking and closes all replica connections
 func (rrm *ReadReplicaManager) Close() error {
+	rrm.stopOnce.Do(func() { close(rrm.stop) })
+
 	rrm.mutex.Lock()
 	defer rrm.mutex.Unlock()
 
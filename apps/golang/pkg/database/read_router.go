@@ -0,0 +1,139 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// SessionSettingsFunc returns the session settings a read on a replica
+// needs for ctx, typically the app.* variables the RLS policies read.
+type SessionSettingsFunc func(ctx context.Context) map[string]string
+
+type replicaSessionKey struct{}
+
+// ReadRouter sends heavy reads to read replicas. Reads are routed only for
+// contexts returned by UseReplica, so writes and read-your-own-write paths
+// keep using the primary without changes.
+type ReadRouter struct {
+	replicas *ReadReplicaManager
+	settings SessionSettingsFunc
+}
+
+// NewReadRouter hooks the router into primary's query callbacks. Queries
+// made with primary.WithContext(ctx), for a ctx from UseReplica, run on the
+// replica chosen for that context.
+func NewReadRouter(primary *gorm.DB, replicas *ReadReplicaManager, settings SessionSettingsFunc) (*ReadRouter, error) {
+	r := &ReadRouter{replicas: replicas, settings: settings}
+
+	if err := primary.Callback().Query().Before("gorm:query").Register("read_router:query", r.route); err != nil {
+		return nil, fmt.Errorf("failed to register read router query callback: %w", err)
+	}
+	if err := primary.Callback().Row().Before("gorm:row").Register("read_router:row", r.route); err != nil {
+		return nil, fmt.Errorf("failed to register read router row callback: %w", err)
+	}
+	return r, nil
+}
+
+// UseReplica returns a context whose reads run on a healthy replica that is
+// within the configured lag, inside a read-only transaction with the
+// session settings applied locally. Without such a replica, or on a nil
+// router, ctx is returned unchanged and reads stay on the primary.
+//
+// release must be called once the reads are done. The context must not be
+// used by concurrent queries, as they share one replica connection. Raw
+// statements that do not start with SELECT or WITH stay on the primary, so
+// an INSERT ... RETURNING read through Scan still writes there; a
+// data-modifying WITH is refused by the read-only replica transaction.
+func (r *ReadRouter) UseReplica(ctx context.Context) (_ context.Context, release func()) {
+	noop := func() {}
+	if r == nil || r.replicas == nil {
+		return ctx, noop
+	}
+	if _, ok := ctx.Value(replicaSessionKey{}).(*sql.Tx); ok {
+		return ctx, noop
+	}
+
+	replica, err := r.replicas.GetReplica()
+	if err != nil {
+		return ctx, noop
+	}
+	sqlDB, err := replica.DB()
+	if err != nil {
+		return ctx, noop
+	}
+
+	tx, err := sqlDB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		log.Printf("Read replica unavailable, falling back to primary: %v", err)
+		return ctx, noop
+	}
+	if err := r.applySettings(ctx, tx); err != nil {
+		_ = tx.Rollback()
+		log.Printf("Failed to apply session settings on read replica, falling back to primary: %v", err)
+		return ctx, noop
+	}
+
+	// The transaction only reads, so ending it with a rollback is enough.
+	return context.WithValue(ctx, replicaSessionKey{}, tx), func() { _ = tx.Rollback() }
+}
+
+// applySettings sets the session settings for the transaction only, so
+// they never leak to the next user of the pooled connection.
+func (r *ReadRouter) applySettings(ctx context.Context, tx *sql.Tx) error {
+	if r.settings == nil {
+		return nil
+	}
+	settings := r.settings(ctx)
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := tx.ExecContext(ctx, "SELECT set_config($1, $2, true)", name, settings[name]); err != nil {
+			return fmt.Errorf("set %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// route points a query at the replica transaction of its context. Queries
+// already inside a primary transaction, and raw statements that write,
+// stay on the primary.
+func (r *ReadRouter) route(db *gorm.DB) {
+	if db.Statement.Context == nil {
+		return
+	}
+	tx, ok := db.Statement.Context.Value(replicaSessionKey{}).(*sql.Tx)
+	if !ok {
+		return
+	}
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+		return
+	}
+	if !isReadStatement(db.Statement.SQL.String()) {
+		return
+	}
+	db.Statement.ConnPool = tx
+}
+
+// isReadStatement reports whether sql, the statement of a Raw call, only
+// reads. Builder queries have no SQL yet when route runs and always read.
+func isReadStatement(sql string) bool {
+	sql = strings.TrimLeft(sql, " \t\r\n(")
+	if sql == "" {
+		return true
+	}
+	keyword := sql
+	if i := strings.IndexAny(sql, " \t\r\n("); i >= 0 {
+		keyword = sql[:i]
+	}
+	return strings.EqualFold(keyword, "SELECT") || strings.EqualFold(keyword, "WITH")
+}
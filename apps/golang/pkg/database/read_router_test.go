@@ -0,0 +1,179 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/go-sqlite"
+	gormsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type routedItem struct {
+	ID     uint
+	Source string
+}
+
+// setConfigCalls records the set_config calls made on any sqlite connection,
+// standing in for the Postgres function the router uses.
+var (
+	setConfigOnce  sync.Once
+	setConfigMu    sync.Mutex
+	setConfigCalls []string
+)
+
+func registerSetConfig(t *testing.T) {
+	t.Helper()
+	setConfigOnce.Do(func() {
+		sqlite.MustRegisterScalarFunction("set_config", 3, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			setConfigMu.Lock()
+			defer setConfigMu.Unlock()
+			setConfigCalls = append(setConfigCalls, fmt.Sprintf("%v=%v", args[0], args[1]))
+			return args[1], nil
+		})
+	})
+	setConfigMu.Lock()
+	setConfigCalls = nil
+	setConfigMu.Unlock()
+}
+
+func recordedSetConfig() []string {
+	setConfigMu.Lock()
+	defer setConfigMu.Unlock()
+	return append([]string(nil), setConfigCalls...)
+}
+
+// openRoutedDB opens an in-memory database holding one routed_items row
+// whose source is name.
+func openRoutedDB(t *testing.T, name string) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s_%s?mode=memory&cache=shared", t.Name(), name)
+	db, err := gorm.Open(gormsqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	require.NoError(t, db.Exec(`CREATE TABLE routed_items (id INTEGER PRIMARY KEY AUTOINCREMENT, source TEXT)`).Error)
+	require.NoError(t, db.Create(&routedItem{Source: name}).Error)
+	return db
+}
+
+// setupReadRouter returns a primary hooked to a router over one replica.
+func setupReadRouter(t *testing.T, settings SessionSettingsFunc) (*gorm.DB, *ReadRouter, *ReadReplica) {
+	t.Helper()
+	registerSetConfig(t)
+	primary := openRoutedDB(t, "primary")
+
+	replica := &ReadReplica{ID: "replica-1", DB: openRoutedDB(t, "replica"), Status: ReplicaStatusHealthy}
+	manager := NewReadReplicaManager(DefaultReadReplicaManagerConfig())
+	manager.replicas = append(manager.replicas, replica)
+
+	router, err := NewReadRouter(primary, manager, settings)
+	require.NoError(t, err)
+	return primary, router, replica
+}
+
+func findSource(t *testing.T, db *gorm.DB) string {
+	t.Helper()
+	var item routedItem
+	require.NoError(t, db.First(&item).Error)
+	return item.Source
+}
+
+func scanSource(t *testing.T, db *gorm.DB) string {
+	t.Helper()
+	var source string
+	require.NoError(t, db.Raw(`SELECT source FROM routed_items LIMIT 1`).Scan(&source).Error)
+	return source
+}
+
+func TestUseReplica_FallsBackToPrimary(t *testing.T) {
+	t.Run("nil router", func(t *testing.T) {
+		var router *ReadRouter
+		ctx := context.Background()
+		replicaCtx, release := router.UseReplica(ctx)
+		defer release()
+		assert.Equal(t, ctx, replicaCtx)
+	})
+
+	cases := map[string]func(replica *ReadReplica){
+		"unhealthy replica": func(replica *ReadReplica) { replica.Status = ReplicaStatusUnhealthy },
+		"lagging replica":   func(replica *ReadReplica) { replica.Lag = time.Hour },
+	}
+	for name, degrade := range cases {
+		t.Run(name, func(t *testing.T) {
+			primary, router, replica := setupReadRouter(t, nil)
+			degrade(replica)
+
+			ctx := context.Background()
+			replicaCtx, release := router.UseReplica(ctx)
+			defer release()
+
+			assert.Equal(t, ctx, replicaCtx)
+			assert.Equal(t, "primary", findSource(t, primary.WithContext(replicaCtx)))
+		})
+	}
+}
+
+func TestReadRouter_RoutesReadsToReplica(t *testing.T) {
+	primary, router, _ := setupReadRouter(t, func(ctx context.Context) map[string]string {
+		return map[string]string{"app.user_id": "u-1", "app.company_id": "c-1"}
+	})
+
+	ctx, release := router.UseReplica(context.Background())
+	defer release()
+
+	assert.Equal(t, "replica", findSource(t, primary.WithContext(ctx)))
+	assert.Equal(t, "replica", scanSource(t, primary.WithContext(ctx)))
+	assert.Equal(t, "primary", findSource(t, primary), "contexts without a replica stay on the primary")
+	assert.Equal(t, []string{"app.company_id=c-1", "app.user_id=u-1"}, recordedSetConfig())
+}
+
+func TestReadRouter_KeepsPrimaryTransactionOnPrimary(t *testing.T) {
+	primary, router, _ := setupReadRouter(t, nil)
+
+	ctx, release := router.UseReplica(context.Background())
+	defer release()
+
+	err := primary.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		assert.Equal(t, "primary", findSource(t, tx))
+		assert.Equal(t, "primary", scanSource(t, tx))
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestReadRouter_KeepsRawWritesOnPrimary(t *testing.T) {
+	primary, router, _ := setupReadRouter(t, nil)
+
+	ctx, release := router.UseReplica(context.Background())
+	defer release()
+
+	var id uint
+	err := primary.WithContext(ctx).
+		Raw(`INSERT INTO routed_items (source) VALUES ('written') RETURNING id`).
+		Scan(&id).Error
+	require.NoError(t, err)
+
+	var count int64
+	require.NoError(t, primary.Model(&routedItem{}).Where("source = ?", "written").Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestIsReadStatement(t *testing.T) {
+	assert.True(t, isReadStatement(""))
+	assert.True(t, isReadStatement("  select 1"))
+	assert.True(t, isReadStatement("(SELECT 1) UNION (SELECT 2)"))
+	assert.True(t, isReadStatement("WITH t AS (SELECT 1) SELECT * FROM t"))
+	assert.False(t, isReadStatement("INSERT INTO t VALUES (1) RETURNING id"))
+	assert.False(t, isReadStatement("UPDATE t SET a = 1 RETURNING a"))
+	assert.False(t, isReadStatement("selected"))
+}
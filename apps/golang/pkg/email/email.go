@@ -0,0 +1,99 @@
+// Package email delivers multipart (text + HTML) email through SMTP or SendGrid.
+package email
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Provider names accepted by EMAIL_PROVIDER.
+const (
+	ProviderSMTP     = "smtp"
+	ProviderSendGrid = "sendgrid"
+)
+
+// Message is a single email. At least one of TextBody and HTMLBody is required.
+type Message struct {
+	To       []string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
+
+// Sender delivers a message through a provider.
+type Sender interface {
+	Send(ctx context.Context, message *Message) error
+}
+
+// Validate checks that the message can be delivered.
+func (m *Message) Validate() error {
+	if m == nil {
+		return fmt.Errorf("email message is nil")
+	}
+	if len(m.To) == 0 {
+		return fmt.Errorf("email destination is empty")
+	}
+	for _, to := range m.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return fmt.Errorf("invalid email destination %q: %w", to, err)
+		}
+	}
+	if strings.TrimSpace(m.Subject) == "" {
+		return fmt.Errorf("email subject is empty")
+	}
+	if strings.TrimSpace(m.TextBody) == "" && strings.TrimSpace(m.HTMLBody) == "" {
+		return fmt.Errorf("email body is empty")
+	}
+	return nil
+}
+
+// NewSenderFromEnv builds the sender selected by EMAIL_PROVIDER. Without
+// EMAIL_PROVIDER, SendGrid is used when SENDGRID_API_KEY is set and SMTP when
+// SMTP_HOST is set. It returns nil, nil when email is not configured.
+//
+// SMTP reads SMTP_HOST, SMTP_PORT (587), SMTP_USERNAME, SMTP_PASSWORD and
+// SMTP_TLS (starttls, tls or none). Both providers send from EMAIL_FROM.
+func NewSenderFromEnv() (Sender, error) {
+	provider := strings.ToLower(strings.TrimSpace(os.Getenv("EMAIL_PROVIDER")))
+	if provider == "" {
+		switch {
+		case strings.TrimSpace(os.Getenv("SENDGRID_API_KEY")) != "":
+			provider = ProviderSendGrid
+		case strings.TrimSpace(os.Getenv("SMTP_HOST")) != "":
+			provider = ProviderSMTP
+		default:
+			return nil, nil
+		}
+	}
+
+	from := os.Getenv("EMAIL_FROM")
+	switch provider {
+	case ProviderSMTP:
+		port := 587
+		if rawPort := strings.TrimSpace(os.Getenv("SMTP_PORT")); rawPort != "" {
+			parsed, err := strconv.Atoi(rawPort)
+			if err != nil {
+				return nil, fmt.Errorf("invalid SMTP_PORT %q: %w", rawPort, err)
+			}
+			port = parsed
+		}
+		return NewSMTPSender(SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+			TLSMode:  os.Getenv("SMTP_TLS"),
+			Timeout:  15 * time.Second,
+		})
+	case ProviderSendGrid:
+		return NewSendGridSender(os.Getenv("SENDGRID_API_KEY"), from)
+	default:
+		return nil, fmt.Errorf("unsupported EMAIL_PROVIDER %q", provider)
+	}
+}
@@ -0,0 +1,103 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// buildMIME renders the message as RFC 5322 bytes. Text and HTML bodies are
+// sent as multipart/alternative so clients pick the richest part they support.
+func buildMIME(from string, message *Message, now time.Time) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	writeHeader := func(key, value string) {
+		buffer.WriteString(key)
+		buffer.WriteString(": ")
+		buffer.WriteString(value)
+		buffer.WriteString("\r\n")
+	}
+
+	writeHeader("From", from)
+	writeHeader("To", strings.Join(message.To, ", "))
+	writeHeader("Subject", mime.QEncoding.Encode("utf-8", message.Subject))
+	writeHeader("Date", now.Format(time.RFC1123Z))
+	writeHeader("Message-ID", messageID(from))
+	writeHeader("MIME-Version", "1.0")
+
+	hasText := strings.TrimSpace(message.TextBody) != ""
+	hasHTML := strings.TrimSpace(message.HTMLBody) != ""
+
+	if hasText != hasHTML {
+		contentType, body := "text/plain; charset=UTF-8", message.TextBody
+		if hasHTML {
+			contentType, body = "text/html; charset=UTF-8", message.HTMLBody
+		}
+		writeHeader("Content-Type", contentType)
+		writeHeader("Content-Transfer-Encoding", "quoted-printable")
+		buffer.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buffer, body); err != nil {
+			return nil, err
+		}
+		return buffer.Bytes(), nil
+	}
+
+	var parts bytes.Buffer
+	writer := multipart.NewWriter(&parts)
+	writeHeader("Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", writer.Boundary()))
+	buffer.WriteString("\r\n")
+
+	for _, part := range []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=UTF-8", message.TextBody},
+		{"text/html; charset=UTF-8", message.HTMLBody},
+	} {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", part.contentType)
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		partWriter, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, fmt.Errorf("create mime part: %w", err)
+		}
+		if err := writeQuotedPrintable(partWriter, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("close mime writer: %w", err)
+	}
+
+	buffer.Write(parts.Bytes())
+	return buffer.Bytes(), nil
+}
+
+func writeQuotedPrintable(w interface{ Write([]byte) (int, error) }, body string) error {
+	encoder := quotedprintable.NewWriter(w)
+	if _, err := encoder.Write([]byte(body)); err != nil {
+		return fmt.Errorf("encode email body: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("encode email body: %w", err)
+	}
+	return nil
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = strings.Trim(from[at+1:], "> ")
+	}
+
+	random := make([]byte, 12)
+	_, _ = rand.Read(random)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(random), domain)
+}
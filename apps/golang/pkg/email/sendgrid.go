@@ -0,0 +1,104 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// SendGridSender sends email through the SendGrid v3 API.
+type SendGridSender struct {
+	apiKey     string
+	from       *mail.Address
+	httpClient *http.Client
+	endpoint   string
+}
+
+// NewSendGridSender creates a SendGrid-backed sender.
+func NewSendGridSender(apiKey, from string) (*SendGridSender, error) {
+	apiKey = strings.TrimSpace(apiKey)
+	if apiKey == "" {
+		return nil, fmt.Errorf("sendgrid api key is empty")
+	}
+
+	address, err := mail.ParseAddress(strings.TrimSpace(from))
+	if err != nil {
+		return nil, fmt.Errorf("invalid sendgrid from address %q: %w", from, err)
+	}
+
+	return &SendGridSender{
+		apiKey:     apiKey,
+		from:       address,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		endpoint:   "https://api.sendgrid.com/v3/mail/send",
+	}, nil
+}
+
+// Send delivers the message through SendGrid.
+func (s *SendGridSender) Send(ctx context.Context, message *Message) error {
+	if err := message.Validate(); err != nil {
+		return err
+	}
+
+	recipients := make([]map[string]string, 0, len(message.To))
+	for _, to := range message.To {
+		address, _ := mail.ParseAddress(to)
+		recipient := map[string]string{"email": address.Address}
+		if address.Name != "" {
+			recipient["name"] = address.Name
+		}
+		recipients = append(recipients, recipient)
+	}
+
+	from := map[string]string{"email": s.from.Address}
+	if s.from.Name != "" {
+		from["name"] = s.from.Name
+	}
+
+	// SendGrid requires text/plain to precede text/html.
+	content := make([]map[string]string, 0, 2)
+	if strings.TrimSpace(message.TextBody) != "" {
+		content = append(content, map[string]string{"type": "text/plain", "value": message.TextBody})
+	}
+	if strings.TrimSpace(message.HTMLBody) != "" {
+		content = append(content, map[string]string{"type": "text/html", "value": message.HTMLBody})
+	}
+
+	payload := map[string]interface{}{
+		"personalizations": []map[string]interface{}{
+			{"to": recipients},
+		},
+		"from":    from,
+		"subject": message.Subject,
+		"content": content,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal sendgrid payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build sendgrid request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sendgrid request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sendgrid returned non-success status: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTP transport security modes.
+const (
+	SMTPTLSStartTLS = "starttls"
+	SMTPTLSImplicit = "tls"
+	SMTPTLSNone     = "none"
+)
+
+// SMTPConfig configures an SMTP relay. TLSMode defaults to STARTTLS; use
+// "none" for a local sink such as MailHog or Mailpit.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	TLSMode  string
+	Timeout  time.Duration
+}
+
+// SMTPSender sends email through an SMTP relay.
+type SMTPSender struct {
+	config   SMTPConfig
+	envelope string
+}
+
+// NewSMTPSender validates the configuration and creates an SMTP sender.
+func NewSMTPSender(config SMTPConfig) (*SMTPSender, error) {
+	config.Host = strings.TrimSpace(config.Host)
+	config.From = strings.TrimSpace(config.From)
+	config.TLSMode = strings.ToLower(strings.TrimSpace(config.TLSMode))
+	if config.TLSMode == "" {
+		config.TLSMode = SMTPTLSStartTLS
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 15 * time.Second
+	}
+
+	if config.Host == "" {
+		return nil, fmt.Errorf("smtp host is empty")
+	}
+	if config.Port <= 0 || config.Port > 65535 {
+		return nil, fmt.Errorf("smtp port %d is invalid", config.Port)
+	}
+	if config.TLSMode != SMTPTLSStartTLS && config.TLSMode != SMTPTLSImplicit && config.TLSMode != SMTPTLSNone {
+		return nil, fmt.Errorf("smtp tls mode must be one of starttls, tls, none")
+	}
+
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp from address %q: %w", config.From, err)
+	}
+
+	return &SMTPSender{config: config, envelope: from.Address}, nil
+}
+
+// Send delivers the message over a new SMTP connection.
+func (s *SMTPSender) Send(ctx context.Context, message *Message) error {
+	if err := message.Validate(); err != nil {
+		return err
+	}
+
+	body, err := buildMIME(s.config.From, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("smtp connect failed: %w", err)
+	}
+
+	deadline := time.Now().Add(s.config.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, s.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake failed: %w", err)
+	}
+	defer client.Close()
+
+	if s.config.TLSMode == SMTPTLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(&tls.Config{ServerName: s.config.Host}); err != nil {
+			return fmt.Errorf("smtp starttls failed: %w", err)
+		}
+	}
+
+	if s.config.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return fmt.Errorf("smtp server does not support AUTH")
+		}
+		auth := smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(s.envelope); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	for _, to := range message.To {
+		address, _ := mail.ParseAddress(to)
+		if err := client.Rcpt(address.Address); err != nil {
+			return fmt.Errorf("smtp RCPT TO %s failed: %w", address.Address, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := writer.Write(body); err != nil {
+		writer.Close()
+		return fmt.Errorf("smtp write failed: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("smtp message rejected: %w", err)
+	}
+
+	return client.Quit()
+}
+
+func (s *SMTPSender) dial(ctx context.Context) (net.Conn, error) {
+	address := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	dialer := &net.Dialer{Timeout: s.config.Timeout}
+
+	if s.config.TLSMode == SMTPTLSImplicit {
+		tlsDialer := &tls.Dialer{
+			NetDialer: dialer,
+			Config:    &tls.Config{ServerName: s.config.Host},
+		}
+		return tlsDialer.DialContext(ctx, "tcp", address)
+	}
+	return dialer.DialContext(ctx, "tcp", address)
+}
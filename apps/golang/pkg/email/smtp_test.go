@@ -0,0 +1,178 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpSink is a minimal SMTP server that accepts every message.
+type smtpSink struct {
+	listener   net.Listener
+	recipients chan []string
+	messages   chan string
+}
+
+func startSMTPSink(t *testing.T) *smtpSink {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	sink := &smtpSink{
+		listener:   listener,
+		recipients: make(chan []string, 1),
+		messages:   make(chan string, 1),
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		sink.serve(conn)
+	}()
+
+	return sink
+}
+
+func (s *smtpSink) serve(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+
+	var recipients []string
+	reply("220 sink ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.TrimSpace(line))
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250-sink")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(command, "MAIL FROM"):
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO"):
+			recipients = append(recipients, strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>"))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			s.recipients <- recipients
+			s.messages <- data.String()
+			reply("250 OK queued")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func (s *smtpSink) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func TestSMTPSenderDeliversMultipartMessage(t *testing.T) {
+	sink := startSMTPSink(t)
+
+	sender, err := NewSMTPSender(SMTPConfig{
+		Host:    "127.0.0.1",
+		Port:    sink.port(),
+		From:    "Agrinova <noreply@agrinova.test>",
+		TLSMode: SMTPTLSNone,
+		Timeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+
+	err = sender.Send(context.Background(), &Message{
+		To:       []string{"Budi <budi@agrinova.test>"},
+		Subject:  "Pengingat pajak kendaraan",
+		TextBody: "Pajak BK 1234 AB jatuh tempo besok.",
+		HTMLBody: "<p>Pajak <strong>BK 1234 AB</strong> jatuh tempo besok.</p>",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"budi@agrinova.test"}, <-sink.recipients)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(<-sink.messages))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Pengingat pajak kendaraan", subject)
+	assert.Equal(t, "Agrinova <noreply@agrinova.test>", parsed.Header.Get("From"))
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	bodies := map[string]string{}
+	parts := multipart.NewReader(parsed.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(quotedprintable.NewReader(part))
+		require.NoError(t, err)
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		bodies[partType] = string(content)
+	}
+	assert.Equal(t, "Pajak BK 1234 AB jatuh tempo besok.", bodies["text/plain"])
+	assert.Contains(t, bodies["text/html"], "<strong>BK 1234 AB</strong>")
+}
+
+func TestSMTPSenderRequiresStartTLSByDefault(t *testing.T) {
+	sink := startSMTPSink(t)
+
+	sender, err := NewSMTPSender(SMTPConfig{
+		Host: "127.0.0.1",
+		Port: sink.port(),
+		From: "noreply@agrinova.test",
+	})
+	require.NoError(t, err)
+
+	err = sender.Send(context.Background(), &Message{
+		To:       []string{"budi@agrinova.test"},
+		Subject:  "Test",
+		TextBody: "Test",
+	})
+	assert.ErrorContains(t, err, "STARTTLS")
+}
+
+func TestMessageValidate(t *testing.T) {
+	assert.Error(t, (&Message{Subject: "s", TextBody: "b"}).Validate())
+	assert.Error(t, (&Message{To: []string{"not-an-email"}, Subject: "s", TextBody: "b"}).Validate())
+	assert.Error(t, (&Message{To: []string{"a@b.test"}, TextBody: "b"}).Validate())
+	assert.Error(t, (&Message{To: []string{"a@b.test"}, Subject: "s"}).Validate())
+	assert.NoError(t, (&Message{To: []string{"a@b.test"}, Subject: "s", HTMLBody: "<p>b</p>"}).Validate())
+}
@@ -324,3 +324,25 @@ func (b *PayloadBuilder) ForManagerTargetAchieved(estateName string, achievement
 		ClickAction: "/manager",
 	}
 }
+
+// ForVehicleTaxReminder creates payload for a vehicle tax due-date reminder
+func (b *PayloadBuilder) ForVehicleTaxReminder(stage string, title string, body string) FCMPayload {
+	return FCMPayload{
+		Type:        "VEHICLE_TAX_REMINDER",
+		Action:      stage,
+		Title:       title,
+		Body:        body,
+		ClickAction: "/vehicles",
+	}
+}
+
+// ForRoutedNotification creates payload for a notification sent by a routing rule
+func (b *PayloadBuilder) ForRoutedNotification(notificationType string, title string, body string, clickAction string) FCMPayload {
+	return FCMPayload{
+		Type:        notificationType,
+		Action:      "ROUTED",
+		Title:       title,
+		Body:        body,
+		ClickAction: clickAction,
+	}
+}
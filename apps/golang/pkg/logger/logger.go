@@ -1,12 +1,16 @@
 package logger
 
 import (
+	"context"
 	"log"
 	"os"
+
+	"agrinovagraphql/server/pkg/tracing"
 )
 
 type Logger struct {
 	*log.Logger
+	fields string
 }
 
 func New() *Logger {
@@ -15,17 +19,30 @@ func New() *Logger {
 	}
 }
 
+// WithContext returns a logger that tags each line with the trace and span
+// IDs of the span in ctx, so log lines can be matched to traces.
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	traceID, spanID := tracing.IDs(ctx)
+	if traceID == "" {
+		return l
+	}
+	return &Logger{
+		Logger: l.Logger,
+		fields: "trace_id=" + traceID + " span_id=" + spanID + " ",
+	}
+}
+
 func (l *Logger) Info(msg string, args ...interface{}) {
-	l.Printf("[INFO] "+msg, args...)
+	l.Printf("[INFO] "+l.fields+msg, args...)
 }
 
 func (l *Logger) Error(msg string, args ...interface{}) {
-	l.Printf("[ERROR] "+msg, args...)
+	l.Printf("[ERROR] "+l.fields+msg, args...)
 }
 
 func (l *Logger) Debug(msg string, args ...interface{}) {
 	if os.Getenv("GO_ENV") == "development" {
-		l.Printf("[DEBUG] "+msg, args...)
+		l.Printf("[DEBUG] "+l.fields+msg, args...)
 	}
 }
 
@@ -33,15 +50,15 @@ func (l *Logger) Warn(msg string, data ...interface{}) {
 	// Handle both format string args and map data
 	if len(data) == 1 {
 		if dataMap, ok := data[0].(map[string]interface{}); ok {
-			l.Printf("[WARN] "+msg+" %+v", dataMap)
+			l.Printf("[WARN] "+l.fields+msg+" %+v", dataMap)
 			return
 		}
 	}
-	l.Printf("[WARN] "+msg, data...)
+	l.Printf("[WARN] "+l.fields+msg, data...)
 }
 
 func (l *Logger) Fatal(msg string, args ...interface{}) {
-	l.Printf("[FATAL] "+msg, args...)
+	l.Printf("[FATAL] "+l.fields+msg, args...)
 	os.Exit(1)
 }
 
@@ -49,6 +66,10 @@ func (l *Logger) Fatal(msg string, args ...interface{}) {
 var defaultLogger = New()
 
 // Package-level functions for convenience
+func WithContext(ctx context.Context) *Logger {
+	return defaultLogger.WithContext(ctx)
+}
+
 func Info(msg string, args ...interface{}) {
 	defaultLogger.Info(msg, args...)
 }
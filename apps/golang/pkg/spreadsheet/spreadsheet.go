@@ -0,0 +1,226 @@
+// Package spreadsheet reads and writes the CSV and XLSX files used for bulk
+// imports and exports.
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Supported file formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// ErrUnsupportedFormat is returned for files that are neither CSV nor XLSX.
+var ErrUnsupportedFormat = errors.New("unsupported file type. allowed: csv, xlsx")
+
+// Row is one non-blank data row keyed by normalized header name.
+type Row struct {
+	// Number is the 1-based line number in the file; the header is line 1.
+	Number int
+	Values map[string]string
+}
+
+// Get returns the trimmed value of the first column that is present.
+func (r Row) Get(columns ...string) string {
+	for _, column := range columns {
+		if value, ok := r.Values[NormalizeHeader(column)]; ok {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// Table is a parsed sheet: a header plus its data rows.
+type Table struct {
+	Header []string
+	Rows   []Row
+}
+
+// HasColumn reports whether the header contains column.
+func (t *Table) HasColumn(column string) bool {
+	column = NormalizeHeader(column)
+	for _, header := range t.Header {
+		if header == column {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatFromFilename returns the format implied by the file extension.
+func FormatFromFilename(filename string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// NormalizeHeader lower-cases a header and joins words with underscores so
+// "Division Code" and "division_code" match.
+func NormalizeHeader(header string) string {
+	header = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, "\ufeff")))
+	return strings.Join(strings.FieldsFunc(header, func(r rune) bool {
+		return r == ' ' || r == '_' || r == '-' || r == '.'
+	}), "_")
+}
+
+// ReadTable parses the first sheet of an XLSX file, or a CSV file, into a
+// Table. Blank rows are skipped but keep their line numbers.
+func ReadTable(format string, r io.Reader) (*Table, error) {
+	var records [][]string
+	switch format {
+	case FormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		parsed, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv: %w", err)
+		}
+		records = parsed
+	case FormatXLSX:
+		file, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open xlsx: %w", err)
+		}
+		defer file.Close()
+
+		sheets := file.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errors.New("xlsx file has no sheets")
+		}
+		parsed, err := file.GetRows(sheets[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read xlsx sheet %s: %w", sheets[0], err)
+		}
+		records = parsed
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("file is empty")
+	}
+
+	table := &Table{Header: make([]string, len(records[0]))}
+	for i, header := range records[0] {
+		table.Header[i] = NormalizeHeader(header)
+	}
+
+	for i, record := range records[1:] {
+		if isBlankRecord(record) {
+			continue
+		}
+		row := Row{Number: i + 2, Values: make(map[string]string, len(table.Header))}
+		for col, header := range table.Header {
+			if header == "" || col >= len(record) {
+				continue
+			}
+			row.Values[header] = record[col]
+		}
+		table.Rows = append(table.Rows, row)
+	}
+
+	return table, nil
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ContentType returns the MIME type for a format.
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// WriteTable writes a header and rows as CSV or as a single-sheet XLSX file.
+func WriteTable(format string, w io.Writer, sheetName string, header []string, rows [][]string) error {
+	switch format {
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(header); err != nil {
+			return fmt.Errorf("failed to write csv header: %w", err)
+		}
+		if err := writer.WriteAll(rows); err != nil {
+			return fmt.Errorf("failed to write csv rows: %w", err)
+		}
+		return nil
+	case FormatXLSX:
+		file := excelize.NewFile()
+		defer file.Close()
+
+		if sheetName == "" {
+			sheetName = "Sheet1"
+		}
+		if err := file.SetSheetName(file.GetSheetName(0), sheetName); err != nil {
+			return fmt.Errorf("failed to name xlsx sheet: %w", err)
+		}
+
+		stream, err := file.NewStreamWriter(sheetName)
+		if err != nil {
+			return fmt.Errorf("failed to open xlsx sheet: %w", err)
+		}
+		if err := stream.SetRow("A1", toCells(header)); err != nil {
+			return fmt.Errorf("failed to write xlsx header: %w", err)
+		}
+		for i, row := range rows {
+			cell, err := excelize.CoordinatesToCellName(1, i+2)
+			if err != nil {
+				return err
+			}
+			if err := stream.SetRow(cell, toCells(row)); err != nil {
+				return fmt.Errorf("failed to write xlsx row %d: %w", i+2, err)
+			}
+		}
+		if err := stream.Flush(); err != nil {
+			return fmt.Errorf("failed to write xlsx sheet: %w", err)
+		}
+		if _, err := file.WriteTo(w); err != nil {
+			return fmt.Errorf("failed to write xlsx: %w", err)
+		}
+		return nil
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+func toCells(values []string) []interface{} {
+	cells := make([]interface{}, len(values))
+	for i, value := range values {
+		cells[i] = value
+	}
+	return cells
+}
+
+// SplitList splits a multi-value cell on commas or semicolons.
+func SplitList(value string) []string {
+	parts := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' })
+	result := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+	return result
+}
@@ -0,0 +1,70 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestReadTableCSV(t *testing.T) {
+	input := "\ufeffUsername,Full Name,Division Code\nmandor01, Budi ,DIV-A;DIV-B\n,,\nmandor02,Sari\n"
+
+	table, err := ReadTable(FormatCSV, strings.NewReader(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"username", "full_name", "division_code"}, table.Header)
+	require.Len(t, table.Rows, 2)
+	assert.Equal(t, 2, table.Rows[0].Number)
+	assert.Equal(t, "Budi", table.Rows[0].Get("full name"))
+	assert.Equal(t, []string{"DIV-A", "DIV-B"}, SplitList(table.Rows[0].Get("division", "division_code")))
+	assert.Equal(t, 4, table.Rows[1].Number, "blank rows keep their line numbers")
+	assert.Equal(t, "", table.Rows[1].Get("division_code"))
+}
+
+func TestReadTableXLSX(t *testing.T) {
+	file := excelize.NewFile()
+	sheet := file.GetSheetName(0)
+	require.NoError(t, file.SetSheetRow(sheet, "A1", &[]interface{}{"username", "role"}))
+	require.NoError(t, file.SetSheetRow(sheet, "A2", &[]interface{}{"asisten01", "ASISTEN"}))
+
+	var buf bytes.Buffer
+	require.NoError(t, file.Write(&buf))
+
+	table, err := ReadTable(FormatXLSX, &buf)
+	require.NoError(t, err)
+	require.Len(t, table.Rows, 1)
+	assert.True(t, table.HasColumn("Role"))
+	assert.Equal(t, "ASISTEN", table.Rows[0].Get("role"))
+}
+
+func TestFormatFromFilename(t *testing.T) {
+	format, err := FormatFromFilename("users.XLSX")
+	require.NoError(t, err)
+	assert.Equal(t, FormatXLSX, format)
+
+	_, err = FormatFromFilename("users.xls")
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestWriteTableRoundTrip(t *testing.T) {
+	header := []string{"estate_code", "block_name"}
+	rows := [][]string{{"EST-1", "Blok A, Timur"}, {"EST-2", ""}}
+
+	for _, format := range []string{FormatCSV, FormatXLSX} {
+		var buf bytes.Buffer
+		require.NoError(t, WriteTable(format, &buf, "hierarchy", header, rows), format)
+
+		table, err := ReadTable(format, &buf)
+		require.NoError(t, err, format)
+		assert.Equal(t, header, table.Header, format)
+		require.Len(t, table.Rows, 2, format)
+		assert.Equal(t, "Blok A, Timur", table.Rows[0].Get("block_name"), format)
+		assert.Equal(t, "EST-2", table.Rows[1].Get("estate_code"), format)
+	}
+
+	assert.ErrorIs(t, WriteTable("xls", &bytes.Buffer{}, "", header, rows), ErrUnsupportedFormat)
+}
@@ -0,0 +1,196 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore keeps objects as files under a root directory. It only works
+// for a single server instance.
+type LocalStore struct {
+	root string
+}
+
+// NewLocalStore creates the root directory when it does not exist.
+func NewLocalStore(root string) (*LocalStore, error) {
+	trimmed := strings.TrimSpace(root)
+	if trimmed == "" {
+		trimmed = "./uploads"
+	}
+	absolute, err := filepath.Abs(filepath.Clean(trimmed))
+	if err != nil {
+		return nil, fmt.Errorf("resolve local storage root: %w", err)
+	}
+	if err := os.MkdirAll(absolute, 0755); err != nil {
+		return nil, fmt.Errorf("create local storage root: %w", err)
+	}
+	return &LocalStore{root: absolute}, nil
+}
+
+// Root returns the absolute root directory.
+func (s *LocalStore) Root() string {
+	return s.root
+}
+
+func (s *LocalStore) filePath(key string) (string, string, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return "", "", err
+	}
+	return cleaned, filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
+
+// Put writes through a temporary file so readers never see a partial object.
+func (s *LocalStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	_, target, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("create object directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("create temporary object: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write object: %w", err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write object: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write object: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("store object: %w", err)
+	}
+	return nil
+}
+
+// Open returns an *os.File, so callers can seek for range requests.
+func (s *LocalStore) Open(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	cleaned, target, err := s.filePath(key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	file, err := os.Open(target)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ObjectInfo{}, ErrNotFound
+		}
+		return nil, ObjectInfo{}, err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, ObjectInfo{}, err
+	}
+	if stat.IsDir() {
+		file.Close()
+		return nil, ObjectInfo{}, ErrNotFound
+	}
+	return file, localObjectInfo(cleaned, stat), nil
+}
+
+func (s *LocalStore) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	cleaned, target, err := s.filePath(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	stat, err := os.Stat(target)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ObjectInfo{}, ErrNotFound
+		}
+		return ObjectInfo{}, err
+	}
+	if stat.IsDir() {
+		return ObjectInfo{}, ErrNotFound
+	}
+	return localObjectInfo(cleaned, stat), nil
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	_, target, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List walks the directory holding prefix in lexical order. Temporary files
+// from in-flight writes are skipped.
+func (s *LocalStore) List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	normalizedPrefix := strings.TrimLeft(strings.ReplaceAll(prefix, "\\", "/"), "/")
+	walkRoot := s.root
+	if dir := path.Dir(normalizedPrefix); dir != "." && dir != "/" {
+		cleanedDir, err := CleanKey(dir)
+		if err != nil {
+			return err
+		}
+		walkRoot = filepath.Join(s.root, filepath.FromSlash(cleanedDir))
+	}
+
+	err := filepath.WalkDir(walkRoot, func(current string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			if errors.Is(walkErr, fs.ErrNotExist) {
+				return nil
+			}
+			return walkErr
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if entry.IsDir() || !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".upload-") {
+			return nil
+		}
+
+		relative, err := filepath.Rel(s.root, current)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(relative)
+		if !strings.HasPrefix(key, normalizedPrefix) {
+			return nil
+		}
+		stat, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		return fn(localObjectInfo(key, stat))
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func localObjectInfo(key string, stat fs.FileInfo) ObjectInfo {
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return ObjectInfo{
+		Key:         key,
+		Size:        stat.Size(),
+		ContentType: contentType,
+		ModTime:     stat.ModTime(),
+	}
+}
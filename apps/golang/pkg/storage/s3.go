@@ -0,0 +1,433 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm        = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload  = "UNSIGNED-PAYLOAD"
+	s3EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	s3MaxPresignTTL    = 7 * 24 * time.Hour
+)
+
+// S3Config configures an S3-compatible bucket.
+type S3Config struct {
+	// Endpoint defaults to https://s3.<region>.amazonaws.com.
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle addresses the bucket as <endpoint>/<bucket> instead of
+	// <bucket>.<endpoint>. MinIO and most self-hosted servers need it.
+	PathStyle  bool
+	HTTPClient *http.Client
+}
+
+// S3Store talks to an S3-compatible API with SigV4-signed requests.
+type S3Store struct {
+	endpoint *url.URL
+	region   string
+	bucket   string
+	key      string
+	secret   string
+	style    bool
+	client   *http.Client
+	now      func() time.Time
+}
+
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	bucket := strings.TrimSpace(cfg.Bucket)
+	if bucket == "" {
+		return nil, fmt.Errorf("s3 bucket is required")
+	}
+	if strings.TrimSpace(cfg.AccessKeyID) == "" || strings.TrimSpace(cfg.SecretAccessKey) == "" {
+		return nil, fmt.Errorf("s3 access key id and secret access key are required")
+	}
+
+	region := strings.TrimSpace(cfg.Region)
+	if region == "" {
+		region = "us-east-1"
+	}
+	rawEndpoint := strings.TrimRight(strings.TrimSpace(cfg.Endpoint), "/")
+	if rawEndpoint == "" {
+		rawEndpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	endpoint, err := url.Parse(rawEndpoint)
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 60 * time.Second}
+	}
+	return &S3Store{
+		endpoint: endpoint,
+		region:   region,
+		bucket:   bucket,
+		key:      strings.TrimSpace(cfg.AccessKeyID),
+		secret:   strings.TrimSpace(cfg.SecretAccessKey),
+		style:    cfg.PathStyle,
+		client:   client,
+		now:      time.Now,
+	}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return err
+	}
+	if size < 0 {
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return fmt.Errorf("read object body: %w", err)
+		}
+		body, size = bytes.NewReader(data), int64(len(data))
+	}
+
+	req, err := s.newRequest(ctx, http.MethodPut, cleaned, nil, body)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := s.do(req, s3UnsignedPayload)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3Store) Open(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	req, err := s.newRequest(ctx, http.MethodGet, cleaned, nil, nil)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	resp, err := s.do(req, s3EmptyPayloadHash)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	return resp.Body, s3ObjectInfo(cleaned, resp), nil
+}
+
+func (s *S3Store) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	req, err := s.newRequest(ctx, http.MethodHead, cleaned, nil, nil)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	resp, err := s.do(req, s3EmptyPayloadHash)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	resp.Body.Close()
+	return s3ObjectInfo(cleaned, resp), nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return err
+	}
+	req, err := s.newRequest(ctx, http.MethodDelete, cleaned, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req, s3EmptyPayloadHash)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+type s3ListResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List pages through ListObjectsV2.
+func (s *S3Store) List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {strings.TrimLeft(prefix, "/")}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+		req, err := s.newRequest(ctx, http.MethodGet, "", query, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := s.do(req, s3EmptyPayloadHash)
+		if err != nil {
+			return err
+		}
+		var result s3ListResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("decode s3 object list: %w", err)
+		}
+
+		for _, object := range result.Contents {
+			if err := fn(ObjectInfo{Key: object.Key, Size: object.Size, ModTime: object.LastModified}); err != nil {
+				return err
+			}
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+// PresignGet returns a query-signed GET URL valid for ttl.
+func (s *S3Store) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	if ttl <= 0 || ttl > s3MaxPresignTTL {
+		return "", fmt.Errorf("s3 presign ttl must be between 1s and %s", s3MaxPresignTTL)
+	}
+
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	target := s.objectURL(cleaned)
+	query := url.Values{
+		"X-Amz-Algorithm":     {s3Algorithm},
+		"X-Amz-Credential":    {s.key + "/" + s.credentialScope(now)},
+		"X-Amz-Date":          {amzDate},
+		"X-Amz-Expires":       {strconv.FormatInt(int64(ttl/time.Second), 10)},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	headers := http.Header{}
+	canonicalQuery := s3CanonicalQuery(query)
+	signature := s.signature(now, http.MethodGet, target.RawPath, canonicalQuery, target.Host, headers, s3UnsignedPayload)
+	return target.Scheme + "://" + target.Host + target.RawPath + "?" + canonicalQuery + "&X-Amz-Signature=" + signature, nil
+}
+
+func (s *S3Store) objectURL(key string) *url.URL {
+	host := s.endpoint.Host
+	objectPath := "/" + key
+	if s.style {
+		objectPath = "/" + s.bucket
+		if key != "" {
+			objectPath += "/" + key
+		}
+	} else {
+		host = s.bucket + "." + host
+	}
+	return &url.URL{
+		Scheme:  s.endpoint.Scheme,
+		Host:    host,
+		Path:    objectPath,
+		RawPath: s3EncodePath(objectPath),
+	}
+}
+
+func (s *S3Store) newRequest(ctx context.Context, method string, key string, query url.Values, body io.Reader) (*http.Request, error) {
+	target := s.objectURL(key)
+	if query != nil {
+		target.RawQuery = s3CanonicalQuery(query)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("build s3 request: %w", err)
+	}
+	return req, nil
+}
+
+// do signs and sends req. Non-2xx responses become errors; 404 becomes
+// ErrNotFound.
+func (s *S3Store) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	now := s.now().UTC()
+	req.Header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signed := http.Header{
+		"X-Amz-Content-Sha256": {payloadHash},
+		"X-Amz-Date":           {req.Header.Get("X-Amz-Date")},
+	}
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		signed.Set("Content-Type", contentType)
+	}
+	signature := s.signature(now, req.Method, req.URL.EscapedPath(), req.URL.RawQuery, req.URL.Host, signed, payloadHash)
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.key, s.credentialScope(now), s3SignedHeaders(signed), signature,
+	))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 %s %s: %w", req.Method, req.URL.Path, err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+
+	var apiErr struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	payload, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if xml.Unmarshal(payload, &apiErr) == nil && apiErr.Code != "" {
+		return nil, fmt.Errorf("s3 %s %s: %s: %s (%d)", req.Method, req.URL.Path, apiErr.Code, apiErr.Message, resp.StatusCode)
+	}
+	return nil, fmt.Errorf("s3 %s %s: unexpected status %d", req.Method, req.URL.Path, resp.StatusCode)
+}
+
+func (s *S3Store) credentialScope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.region + "/s3/aws4_request"
+}
+
+// signature computes the SigV4 signature over host plus the given headers.
+func (s *S3Store) signature(now time.Time, method, escapedPath, canonicalQuery, host string, headers http.Header, payloadHash string) string {
+	signed := headers.Clone()
+	signed.Set("Host", host)
+
+	names := strings.Split(s3SignedHeaders(signed), ";")
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name)
+		canonicalHeaders.WriteByte(':')
+		canonicalHeaders.WriteString(strings.TrimSpace(signed.Get(name)))
+		canonicalHeaders.WriteByte('\n')
+	}
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		escapedPath,
+		canonicalQuery,
+		canonicalHeaders.String(),
+		strings.Join(names, ";"),
+		payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		now.Format("20060102T150405Z"),
+		s.credentialScope(now),
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	signingKey := s3HMAC([]byte("AWS4"+s.secret), now.Format("20060102"))
+	signingKey = s3HMAC(signingKey, s.region)
+	signingKey = s3HMAC(signingKey, "s3")
+	signingKey = s3HMAC(signingKey, "aws4_request")
+	return hex.EncodeToString(s3HMAC(signingKey, stringToSign))
+}
+
+func s3SignedHeaders(headers http.Header) string {
+	names := make([]string, 0, len(headers)+1)
+	seen := map[string]struct{}{}
+	for name := range headers {
+		lower := strings.ToLower(name)
+		if _, ok := seen[lower]; !ok {
+			seen[lower] = struct{}{}
+			names = append(names, lower)
+		}
+	}
+	if _, ok := seen["host"]; !ok {
+		names = append(names, "host")
+	}
+	sort.Strings(names)
+	return strings.Join(names, ";")
+}
+
+func s3HMAC(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, s3Escape(key, true)+"="+s3Escape(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func s3EncodePath(objectPath string) string {
+	return s3Escape(objectPath, false)
+}
+
+// s3Escape percent-encodes everything except RFC 3986 unreserved characters,
+// and "/" unless encodeSlash is set.
+func s3Escape(value string, encodeSlash bool) string {
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			builder.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			builder.WriteByte(c)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", c)
+		}
+	}
+	return builder.String()
+}
+
+func s3ObjectInfo(key string, resp *http.Response) ObjectInfo {
+	info := ObjectInfo{
+		Key:         key,
+		Size:        resp.ContentLength,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+	if modified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.ModTime = modified
+	}
+	return info
+}
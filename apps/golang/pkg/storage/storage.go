@@ -0,0 +1,186 @@
+// Package storage keeps uploaded photos and documents in an object store:
+// the local uploads directory or an S3-compatible bucket.
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Provider names accepted by STORAGE_PROVIDER.
+const (
+	ProviderLocal = "local"
+	ProviderS3    = "s3"
+)
+
+// URLPrefix is the HTTP path objects are served under. Object keys are the
+// part after it, e.g. "avatars/<user>/<file>.jpg".
+const URLPrefix = "/uploads/"
+
+var (
+	ErrNotFound   = errors.New("storage: object not found")
+	ErrInvalidKey = errors.New("storage: invalid object key")
+)
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Store reads and writes objects by key.
+type Store interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, ObjectInfo, error)
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	// Delete succeeds when the object does not exist.
+	Delete(ctx context.Context, key string) error
+	// List calls fn for every object whose key starts with prefix.
+	List(ctx context.Context, prefix string, fn func(ObjectInfo) error) error
+}
+
+// Presigner is implemented by stores that can hand out short-lived direct
+// download URLs, so the server can redirect instead of proxying the body.
+type Presigner interface {
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// CleanKey normalizes an object key and rejects keys that would escape the
+// store root.
+func CleanKey(key string) (string, error) {
+	normalized := strings.Trim(strings.ReplaceAll(strings.TrimSpace(key), "\\", "/"), "/")
+	if normalized == "" {
+		return "", ErrInvalidKey
+	}
+	for _, segment := range strings.Split(normalized, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	return normalized, nil
+}
+
+// JoinKey joins key segments with "/", skipping empty ones.
+func JoinKey(parts ...string) string {
+	cleaned := make([]string, 0, len(parts))
+	for _, part := range parts {
+		normalized := strings.Trim(strings.ReplaceAll(part, "\\", "/"), "/")
+		if normalized != "" {
+			cleaned = append(cleaned, normalized)
+		}
+	}
+	return path.Join(cleaned...)
+}
+
+// URLPath returns the served path of a key, e.g. "/uploads/avatars/u/a.jpg".
+func URLPath(key string) string {
+	return URLPrefix + strings.TrimPrefix(key, "/")
+}
+
+// KeyFromURLPath extracts the object key from a served path. It accepts the
+// legacy form without a leading slash and reports false for anything that is
+// not an upload path.
+func KeyFromURLPath(urlPath string) (string, bool) {
+	trimmed := strings.TrimSpace(urlPath)
+	if idx := strings.IndexAny(trimmed, "?#"); idx >= 0 {
+		trimmed = trimmed[:idx]
+	}
+	trimmed = "/" + strings.TrimPrefix(trimmed, "/")
+	if !strings.HasPrefix(trimmed, URLPrefix) {
+		return "", false
+	}
+	key, err := CleanKey(strings.TrimPrefix(trimmed, URLPrefix))
+	if err != nil {
+		return "", false
+	}
+	return key, true
+}
+
+// PutBytes stores data under key.
+func PutBytes(ctx context.Context, store Store, key string, data []byte, contentType string) error {
+	return store.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType)
+}
+
+// Copy copies every object under prefix from src to dst. Objects already in
+// dst with the same size are skipped. fn, when set, is called once per object
+// with the outcome and may stop the copy by returning an error.
+func Copy(ctx context.Context, src Store, dst Store, prefix string, fn func(info ObjectInfo, copied bool, err error) error) error {
+	return src.List(ctx, prefix, func(info ObjectInfo) error {
+		copied, err := copyObject(ctx, src, dst, info)
+		if fn != nil {
+			return fn(info, copied, err)
+		}
+		return err
+	})
+}
+
+func copyObject(ctx context.Context, src Store, dst Store, info ObjectInfo) (bool, error) {
+	if existing, err := dst.Stat(ctx, info.Key); err == nil && existing.Size == info.Size {
+		return false, nil
+	} else if err != nil && !errors.Is(err, ErrNotFound) {
+		return false, err
+	}
+
+	body, opened, err := src.Open(ctx, info.Key)
+	if err != nil {
+		return false, err
+	}
+	defer body.Close()
+
+	if err := dst.Put(ctx, info.Key, body, opened.Size, opened.ContentType); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// NewStoreFromEnv builds the store selected by STORAGE_PROVIDER. Without
+// STORAGE_PROVIDER, S3 is used when S3_BUCKET is set and the local uploads
+// directory otherwise.
+//
+// S3 reads S3_ENDPOINT, S3_REGION (us-east-1), S3_BUCKET, S3_ACCESS_KEY_ID,
+// S3_SECRET_ACCESS_KEY and S3_FORCE_PATH_STYLE (true when S3_ENDPOINT is set,
+// which is what MinIO-style servers expect).
+func NewStoreFromEnv(uploadsDir string) (Store, error) {
+	provider := strings.ToLower(strings.TrimSpace(os.Getenv("STORAGE_PROVIDER")))
+	if provider == "" {
+		provider = ProviderLocal
+		if strings.TrimSpace(os.Getenv("S3_BUCKET")) != "" {
+			provider = ProviderS3
+		}
+	}
+
+	switch provider {
+	case ProviderLocal:
+		return NewLocalStore(uploadsDir)
+	case ProviderS3:
+		endpoint := strings.TrimSpace(os.Getenv("S3_ENDPOINT"))
+		pathStyle := endpoint != ""
+		if raw := strings.TrimSpace(os.Getenv("S3_FORCE_PATH_STYLE")); raw != "" {
+			parsed, err := strconv.ParseBool(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid S3_FORCE_PATH_STYLE %q: %w", raw, err)
+			}
+			pathStyle = parsed
+		}
+		return NewS3Store(S3Config{
+			Endpoint:        endpoint,
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			PathStyle:       pathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported STORAGE_PROVIDER %q", provider)
+	}
+}
@@ -0,0 +1,108 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+)
+
+// recordSpans installs a tracer provider that keeps finished spans in memory
+// for the duration of the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return recorder
+}
+
+func TestStartEnd(t *testing.T) {
+	recorder := recordSpans(t)
+
+	ctx, span := Start(context.Background(), "Service.Method")
+	traceID, spanID := IDs(ctx)
+	if traceID == "" || spanID == "" {
+		t.Fatalf("IDs = %q, %q; want both set", traceID, spanID)
+	}
+	End(span, errors.New("boom"))
+
+	ended := recorder.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("ended spans = %d, want 1", len(ended))
+	}
+	if got := ended[0].Status().Code; got != codes.Error {
+		t.Errorf("status = %v, want Error", got)
+	}
+}
+
+func TestIDs_NoSpan(t *testing.T) {
+	if traceID, spanID := IDs(context.Background()); traceID != "" || spanID != "" {
+		t.Errorf("IDs = %q, %q; want empty", traceID, spanID)
+	}
+}
+
+func TestSetup_Disabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Config{})
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown: %v", err)
+	}
+}
+
+func TestGormPlugin(t *testing.T) {
+	recorder := recordSpans(t)
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Use(GormPlugin{}); err != nil {
+		t.Fatalf("Use: %v", err)
+	}
+	if err := db.Exec("CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)").Error; err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	ctx, parent := Start(context.Background(), "Query.items")
+	var names []string
+	if err := db.WithContext(ctx).Table("items").Pluck("name", &names).Error; err != nil {
+		t.Fatalf("pluck: %v", err)
+	}
+	var missing struct{ ID int }
+	db.WithContext(ctx).Table("items").First(&missing)
+	parent.End()
+
+	var queries []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == "sql.query" {
+			queries = append(queries, span)
+		}
+	}
+	if len(queries) != 2 {
+		t.Fatalf("sql.query spans = %d, want 2", len(queries))
+	}
+	for _, span := range queries {
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %s is not a child of the resolver span", span.Name())
+		}
+		// A missing row is an expected outcome, not a failed statement.
+		if span.Status().Code == codes.Error {
+			t.Errorf("span status = Error: %s", span.Status().Description)
+		}
+	}
+}
@@ -25,7 +25,6 @@ class CompanyAdminQueries {
           totalEmployees
           todayProduction
           monthlyProduction
-          systemUptime
         }
         userOverview {
           total
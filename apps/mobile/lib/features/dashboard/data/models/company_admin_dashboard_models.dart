@@ -67,7 +67,6 @@ class CompanyAdminStats {
   final int totalEmployees;
   final double todayProduction;
   final double monthlyProduction;
-  final double systemUptime;
 
   const CompanyAdminStats({
     required this.totalUsers,
@@ -79,7 +78,6 @@ class CompanyAdminStats {
     required this.totalEmployees,
     required this.todayProduction,
     required this.monthlyProduction,
-    required this.systemUptime,
   });
 
   factory CompanyAdminStats.fromJson(Map<String, dynamic> json) {
@@ -93,7 +91,6 @@ class CompanyAdminStats {
       totalEmployees: _asInt(json['totalEmployees']),
       todayProduction: _asDouble(json['todayProduction']),
       monthlyProduction: _asDouble(json['monthlyProduction']),
-      systemUptime: _asDouble(json['systemUptime']),
     );
   }
 }
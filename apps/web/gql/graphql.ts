@@ -1404,8 +1404,6 @@ export type CompanyAdminStats = {
   activeUsers: Scalars['Int']['output'];
   /** Monthly production */
   monthlyProduction: Scalars['Float']['output'];
-  /** System uptime */
-  systemUptime: Scalars['Float']['output'];
   /** Today's production */
   todayProduction: Scalars['Float']['output'];
   /** Total blocks */
@@ -9512,6 +9510,8 @@ export type UserManagementResult = {
   message: Scalars['String']['output'];
   /** Success */
   success: Scalars['Boolean']['output'];
+  /** One-time temporary password, set only when adminResetUserPassword generated one. It is not stored or shown again. */
+  temporaryPassword?: Maybe<Scalars['String']['output']>;
   /** User */
   user?: Maybe<CompanyUser>;
 };